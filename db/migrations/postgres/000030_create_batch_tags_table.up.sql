@@ -0,0 +1,12 @@
+BEGIN;
+CREATE TABLE batch_tags (
+  seq        SERIAL          PRIMARY KEY,
+  batch_id   UUID            NOT NULL,
+  tag        VARCHAR(64)     NOT NULL,
+  tag_idx    INTEGER         NOT NULL
+);
+
+CREATE UNIQUE INDEX batch_tags_batch ON batch_tags(batch_id,tag);
+CREATE INDEX batch_tags_tag ON batch_tags(tag);
+
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX operations_deleted;
+ALTER TABLE operations DROP COLUMN deleted;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE operations ADD COLUMN deleted BIGINT;
+CREATE INDEX operations_deleted ON operations(deleted);
+COMMIT;
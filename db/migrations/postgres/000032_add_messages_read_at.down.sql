@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX messages_read_at;
+ALTER TABLE messages DROP COLUMN read_at;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN read_at BIGINT;
+CREATE INDEX messages_read_at ON messages(read_at);
+COMMIT;
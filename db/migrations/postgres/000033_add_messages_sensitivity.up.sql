@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN sensitivity VARCHAR(64) DEFAULT '';
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+CREATE TABLE token_balances (
+  seq            SERIAL          PRIMARY KEY,
+  pool_id        UUID            NOT NULL,
+  address        VARCHAR(1024)   NOT NULL,
+  amount         VARCHAR(65),
+  updated        BIGINT
+);
+
+CREATE UNIQUE INDEX token_balances_pool ON token_balances(pool_id,address);
+
+COMMIT;
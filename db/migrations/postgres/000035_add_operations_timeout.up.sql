@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE operations ADD COLUMN timeout VARCHAR(64);
+COMMIT;
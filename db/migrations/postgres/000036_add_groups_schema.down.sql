@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE groups DROP COLUMN schema_name;
+ALTER TABLE groups DROP COLUMN schema_version;
+COMMIT;
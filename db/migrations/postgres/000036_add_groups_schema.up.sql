@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE groups ADD COLUMN schema_name VARCHAR(64) DEFAULT '';
+ALTER TABLE groups ADD COLUMN schema_version VARCHAR(64) DEFAULT '';
+COMMIT;
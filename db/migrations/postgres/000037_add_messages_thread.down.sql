@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX messages_thread;
+ALTER TABLE messages DROP COLUMN thread_id;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE groups DROP COLUMN description;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE groups ADD COLUMN description VARCHAR(4096) DEFAULT '';
+COMMIT;
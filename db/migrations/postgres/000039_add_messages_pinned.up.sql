@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN pinned BOOLEAN DEFAULT true;
+COMMIT;
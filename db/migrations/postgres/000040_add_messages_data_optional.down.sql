@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE messages_data DROP COLUMN optional;
+COMMIT;
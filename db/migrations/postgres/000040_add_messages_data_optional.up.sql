@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE messages_data ADD COLUMN optional BOOLEAN DEFAULT false;
+COMMIT;
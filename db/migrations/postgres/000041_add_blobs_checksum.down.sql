@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE blobs DROP COLUMN unverifiable;
+ALTER TABLE blobs DROP COLUMN checksum;
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+ALTER TABLE blobs ADD COLUMN checksum BIGINT DEFAULT 0;
+ALTER TABLE blobs ADD COLUMN unverifiable BOOLEAN DEFAULT false;
+UPDATE blobs SET unverifiable = true;
+COMMIT;
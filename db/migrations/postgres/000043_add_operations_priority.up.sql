@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE operations ADD COLUMN priority INTEGER DEFAULT 0;
+COMMIT;
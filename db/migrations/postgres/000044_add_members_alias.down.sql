@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE members DROP COLUMN alias;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE members ADD COLUMN alias VARCHAR(128) DEFAULT '';
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+DROP INDEX data_previous;
+ALTER TABLE data DROP COLUMN previous_id;
+ALTER TABLE data DROP COLUMN version;
+COMMIT;
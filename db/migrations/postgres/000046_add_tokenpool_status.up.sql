@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE tokenpool ADD COLUMN status VARCHAR(64) DEFAULT 'active';
+COMMIT;
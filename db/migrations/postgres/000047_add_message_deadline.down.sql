@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE messages DROP COLUMN deadline;
+ALTER TABLE messages DROP COLUMN error;
+COMMIT;
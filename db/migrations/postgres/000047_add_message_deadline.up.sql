@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN deadline BIGINT;
+ALTER TABLE messages ADD COLUMN error TEXT DEFAULT '';
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE groups ADD COLUMN nonce BIGINT DEFAULT 0;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE batches DROP COLUMN creator_node;
+COMMIT;
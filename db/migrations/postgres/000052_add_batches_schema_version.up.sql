@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE batches ADD COLUMN schema_version INTEGER DEFAULT 0;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+DROP TABLE IF EXISTS operation_history;
+COMMIT;
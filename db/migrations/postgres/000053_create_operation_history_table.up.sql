@@ -0,0 +1,12 @@
+BEGIN;
+CREATE TABLE operation_history (
+  seq         SERIAL          PRIMARY KEY,
+  op_id       UUID            NOT NULL,
+  opstatus    VARCHAR(64)     NOT NULL,
+  error       VARCHAR         NOT NULL,
+  timestamp   BIGINT          NOT NULL
+);
+
+CREATE INDEX operation_history_op ON operation_history(op_id);
+
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE nodes ADD COLUMN public_key TEXT DEFAULT '';
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX data_created_from;
+ALTER TABLE data DROP COLUMN created_from;
+COMMIT;
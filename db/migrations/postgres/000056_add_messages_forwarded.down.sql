@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE messages DROP COLUMN forwarded_from;
+ALTER TABLE messages DROP COLUMN forwarded;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN forwarded BOOLEAN DEFAULT false;
+ALTER TABLE messages ADD COLUMN forwarded_from UUID;
+COMMIT;
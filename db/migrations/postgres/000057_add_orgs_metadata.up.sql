@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE orgs ADD COLUMN metadata BYTEA;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN content_type VARCHAR(256) DEFAULT '';
+COMMIT;
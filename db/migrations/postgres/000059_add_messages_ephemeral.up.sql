@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN ephemeral BOOLEAN DEFAULT false;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE nodes ADD COLUMN heartbeat_interval VARCHAR(64);
+COMMIT;
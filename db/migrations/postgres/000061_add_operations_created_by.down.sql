@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX operations_created_by;
+ALTER TABLE operations DROP COLUMN created_by;
+COMMIT;
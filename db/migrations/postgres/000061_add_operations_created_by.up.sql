@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE operations ADD COLUMN created_by VARCHAR(1024) DEFAULT '';
+CREATE INDEX operations_created_by ON operations(created_by);
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE batches ADD COLUMN payload_compressed BOOLEAN DEFAULT false;
+COMMIT;
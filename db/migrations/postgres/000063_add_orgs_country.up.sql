@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE orgs ADD COLUMN country VARCHAR(2) DEFAULT '';
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE batches DROP COLUMN confirmed_at;
+COMMIT;
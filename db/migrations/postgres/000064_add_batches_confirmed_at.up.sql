@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE batches ADD COLUMN confirmed_at BIGINT;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE batches DROP COLUMN pin_count;
+COMMIT;
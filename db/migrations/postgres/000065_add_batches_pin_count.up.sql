@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE batches ADD COLUMN pin_count INTEGER DEFAULT 0;
+COMMIT;
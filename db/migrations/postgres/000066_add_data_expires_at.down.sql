@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX data_expires_at;
+ALTER TABLE data DROP COLUMN expires_at;
+COMMIT;
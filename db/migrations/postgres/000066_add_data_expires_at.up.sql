@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE data ADD COLUMN expires_at BIGINT;
+CREATE INDEX data_expires_at ON data(expires_at);
+COMMIT;
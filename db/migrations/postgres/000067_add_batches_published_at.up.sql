@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE batches ADD COLUMN published_at BIGINT;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE groups ADD COLUMN acl TEXT;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE blobs DROP COLUMN uploaded_by;
+ALTER TABLE data DROP COLUMN blob_uploaded_by;
+COMMIT;
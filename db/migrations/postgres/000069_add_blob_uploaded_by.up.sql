@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE blobs ADD COLUMN uploaded_by VARCHAR(1024) DEFAULT '';
+ALTER TABLE data ADD COLUMN blob_uploaded_by VARCHAR(1024) DEFAULT '';
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE subscriptions DROP COLUMN max_delivery_attempts;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE subscriptions ADD COLUMN max_delivery_attempts INTEGER DEFAULT 0;
+COMMIT;
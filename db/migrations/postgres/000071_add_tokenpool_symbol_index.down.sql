@@ -0,0 +1,3 @@
+BEGIN;
+DROP INDEX tokenpool_symbol;
+COMMIT;
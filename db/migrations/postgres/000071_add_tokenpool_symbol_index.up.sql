@@ -0,0 +1,3 @@
+BEGIN;
+CREATE UNIQUE INDEX tokenpool_symbol ON tokenpool(namespace,symbol) WHERE symbol <> '';
+COMMIT;
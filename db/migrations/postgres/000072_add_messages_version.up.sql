@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN version INTEGER DEFAULT 0;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE transactions DROP COLUMN gas_limit;
+ALTER TABLE transactions DROP COLUMN gas_price;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE transactions ADD COLUMN gas_limit VARCHAR(65);
+ALTER TABLE transactions ADD COLUMN gas_price VARCHAR(65);
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE messages DROP COLUMN created_by;
+COMMIT;
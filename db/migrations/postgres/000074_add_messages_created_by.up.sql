@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN created_by VARCHAR(1024) DEFAULT '';
+COMMIT;
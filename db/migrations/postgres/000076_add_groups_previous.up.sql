@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE groups ADD COLUMN previous CHAR(64);
+CREATE UNIQUE INDEX groups_previous ON groups(previous);
+COMMIT;
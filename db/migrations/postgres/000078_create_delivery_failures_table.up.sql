@@ -0,0 +1,13 @@
+BEGIN;
+CREATE TABLE delivery_failures (
+  seq             SERIAL          PRIMARY KEY,
+  namespace       VARCHAR(64)     NOT NULL,
+  subscription_id UUID            NOT NULL,
+  event_id        UUID            NOT NULL,
+  attempts        INTEGER         DEFAULT 0,
+  updated         BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX delivery_failures_subscription ON delivery_failures(subscription_id,event_id);
+
+COMMIT;
@@ -0,0 +1,2 @@
+DROP INDEX operations_deleted;
+ALTER TABLE operations DROP COLUMN deleted;
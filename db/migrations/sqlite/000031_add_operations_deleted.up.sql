@@ -0,0 +1,2 @@
+ALTER TABLE operations ADD COLUMN deleted BIGINT;
+CREATE INDEX operations_deleted ON operations(deleted);
@@ -0,0 +1,2 @@
+DROP INDEX messages_read_at;
+ALTER TABLE messages DROP COLUMN read_at;
@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN read_at BIGINT;
+CREATE INDEX messages_read_at ON messages(read_at);
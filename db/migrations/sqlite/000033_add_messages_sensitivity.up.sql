@@ -0,0 +1 @@
+ALTER TABLE messages ADD COLUMN sensitivity VARCHAR(64) DEFAULT '';
@@ -0,0 +1,9 @@
+CREATE TABLE token_balances (
+  seq            INTEGER         PRIMARY KEY AUTOINCREMENT,
+  pool_id        UUID            NOT NULL,
+  address        VARCHAR(1024)   NOT NULL,
+  amount         VARCHAR(65),
+  updated        BIGINT
+);
+
+CREATE UNIQUE INDEX token_balances_pool ON token_balances(pool_id,address);
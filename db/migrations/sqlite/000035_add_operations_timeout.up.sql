@@ -0,0 +1 @@
+ALTER TABLE operations ADD COLUMN timeout VARCHAR(64);
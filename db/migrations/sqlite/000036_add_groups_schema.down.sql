@@ -0,0 +1,2 @@
+ALTER TABLE groups DROP COLUMN schema_name;
+ALTER TABLE groups DROP COLUMN schema_version;
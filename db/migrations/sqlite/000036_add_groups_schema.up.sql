@@ -0,0 +1,2 @@
+ALTER TABLE groups ADD COLUMN schema_name VARCHAR(64) DEFAULT '';
+ALTER TABLE groups ADD COLUMN schema_version VARCHAR(64) DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN thread_id UUID;
+CREATE INDEX messages_thread ON messages(thread_id);
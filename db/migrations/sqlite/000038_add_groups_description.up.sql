@@ -0,0 +1 @@
+ALTER TABLE groups ADD COLUMN description VARCHAR(4096) DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE messages_data DROP COLUMN optional;
@@ -0,0 +1 @@
+ALTER TABLE messages_data ADD COLUMN optional BOOLEAN DEFAULT false;
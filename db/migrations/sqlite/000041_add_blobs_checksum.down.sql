@@ -0,0 +1,2 @@
+ALTER TABLE blobs DROP COLUMN unverifiable;
+ALTER TABLE blobs DROP COLUMN checksum;
@@ -0,0 +1 @@
+ALTER TABLE operations ADD COLUMN priority INTEGER DEFAULT 0;
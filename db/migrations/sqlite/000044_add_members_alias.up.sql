@@ -0,0 +1 @@
+ALTER TABLE members ADD COLUMN alias VARCHAR(128) DEFAULT '';
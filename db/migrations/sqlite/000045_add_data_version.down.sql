@@ -0,0 +1,3 @@
+DROP INDEX data_previous;
+ALTER TABLE data DROP COLUMN previous_id;
+ALTER TABLE data DROP COLUMN version;
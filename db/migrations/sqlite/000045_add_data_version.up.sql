@@ -0,0 +1,3 @@
+ALTER TABLE data ADD COLUMN version INTEGER DEFAULT 1;
+ALTER TABLE data ADD COLUMN previous_id UUID;
+CREATE INDEX data_previous ON data(previous_id);
@@ -0,0 +1 @@
+ALTER TABLE tokenpool ADD COLUMN status VARCHAR(64) DEFAULT 'active';
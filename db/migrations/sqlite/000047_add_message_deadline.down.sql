@@ -0,0 +1,2 @@
+ALTER TABLE messages DROP COLUMN deadline;
+ALTER TABLE messages DROP COLUMN error;
@@ -0,0 +1,12 @@
+CREATE TABLE dead_letter_events (
+  seq             INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id              UUID            NOT NULL,
+  namespace       VARCHAR(64)     NOT NULL,
+  subscription_id UUID            NOT NULL,
+  event_id        UUID            NOT NULL,
+  attempts        INTEGER         DEFAULT 0,
+  created         BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX dead_letter_events_id ON dead_letter_events(id);
+CREATE INDEX dead_letter_events_subscription ON dead_letter_events(subscription_id,event_id);
@@ -0,0 +1 @@
+ALTER TABLE groups ADD COLUMN nonce BIGINT DEFAULT 0;
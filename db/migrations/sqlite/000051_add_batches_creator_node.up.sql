@@ -0,0 +1 @@
+ALTER TABLE batches ADD COLUMN creator_node UUID;
@@ -0,0 +1 @@
+ALTER TABLE batches ADD COLUMN schema_version INTEGER DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE nodes ADD COLUMN public_key TEXT DEFAULT '';
@@ -0,0 +1,2 @@
+DROP INDEX data_created_from;
+ALTER TABLE data DROP COLUMN created_from;
@@ -0,0 +1,2 @@
+ALTER TABLE data ADD COLUMN created_from UUID;
+CREATE INDEX data_created_from ON data(created_from);
@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN forwarded BOOLEAN DEFAULT false;
+ALTER TABLE messages ADD COLUMN forwarded_from UUID;
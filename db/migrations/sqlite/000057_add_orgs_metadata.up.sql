@@ -0,0 +1 @@
+ALTER TABLE orgs ADD COLUMN metadata BYTEA;
@@ -0,0 +1 @@
+ALTER TABLE messages ADD COLUMN content_type VARCHAR(256) DEFAULT '';
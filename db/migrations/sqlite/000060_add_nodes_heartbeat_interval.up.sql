@@ -0,0 +1 @@
+ALTER TABLE nodes ADD COLUMN heartbeat_interval VARCHAR(64);
@@ -0,0 +1,2 @@
+DROP INDEX operations_created_by;
+ALTER TABLE operations DROP COLUMN created_by;
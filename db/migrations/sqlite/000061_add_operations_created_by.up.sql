@@ -0,0 +1,2 @@
+ALTER TABLE operations ADD COLUMN created_by VARCHAR(1024) DEFAULT '';
+CREATE INDEX operations_created_by ON operations(created_by);
@@ -0,0 +1 @@
+ALTER TABLE batches ADD COLUMN payload_compressed BOOLEAN DEFAULT false;
@@ -0,0 +1 @@
+ALTER TABLE orgs ADD COLUMN country VARCHAR(2) DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE batches ADD COLUMN pin_count INTEGER DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP INDEX data_expires_at;
+ALTER TABLE data DROP COLUMN expires_at;
@@ -0,0 +1,2 @@
+ALTER TABLE data ADD COLUMN expires_at BIGINT;
+CREATE INDEX data_expires_at ON data(expires_at);
@@ -0,0 +1 @@
+ALTER TABLE batches ADD COLUMN published_at BIGINT;
@@ -0,0 +1,2 @@
+ALTER TABLE blobs DROP COLUMN uploaded_by;
+ALTER TABLE data DROP COLUMN blob_uploaded_by;
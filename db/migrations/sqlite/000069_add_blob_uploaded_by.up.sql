@@ -0,0 +1,2 @@
+ALTER TABLE blobs ADD COLUMN uploaded_by VARCHAR(1024) DEFAULT '';
+ALTER TABLE data ADD COLUMN blob_uploaded_by VARCHAR(1024) DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE subscriptions DROP COLUMN max_delivery_attempts;
@@ -0,0 +1 @@
+ALTER TABLE subscriptions ADD COLUMN max_delivery_attempts INTEGER DEFAULT 0;
@@ -0,0 +1 @@
+DROP INDEX tokenpool_symbol;
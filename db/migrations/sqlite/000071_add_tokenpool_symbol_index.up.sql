@@ -0,0 +1 @@
+CREATE UNIQUE INDEX tokenpool_symbol ON tokenpool(namespace,symbol) WHERE symbol <> '';
@@ -0,0 +1,2 @@
+ALTER TABLE transactions DROP COLUMN gas_limit;
+ALTER TABLE transactions DROP COLUMN gas_price;
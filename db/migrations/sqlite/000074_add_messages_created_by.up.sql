@@ -0,0 +1 @@
+ALTER TABLE messages ADD COLUMN created_by VARCHAR(1024) DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE groups ADD COLUMN previous CHAR(64);
+CREATE UNIQUE INDEX groups_previous ON groups(previous);
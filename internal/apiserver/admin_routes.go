@@ -19,10 +19,18 @@ package apiserver
 import "github.com/hyperledger/firefly/internal/oapispec"
 
 var adminRoutes = []*oapispec.Route{
+	getCacheStats,
 	getConfig,
 	getConfigRecord,
 	getConfigRecords,
+	getNetworkConsistency,
+	postDataPurge,
+	postPurgeOperations,
+	postPublicStorageGC,
 	postResetConfig,
+	postTokenPoolEventReplay,
+	postSubscriptionRewind,
 	putConfigRecord,
 	deleteConfigRecord,
+	deleteGroupCache,
 }
@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/hyperledger/firefly/internal/config"
@@ -53,6 +54,8 @@ const (
 	HTTPConfTLSEnabled = "tls.enabled"
 	// HTTPConfTLSKeyFile the private key file for TLS on the server
 	HTTPConfTLSKeyFile = "tls.keyFile"
+	// HTTPConfMTLSEnabled whether requests are authenticated by resolving the client certificate to an identity (requires tls.enabled and tls.clientAuth)
+	HTTPConfMTLSEnabled = "mtls.enabled"
 )
 
 type IServer interface {
@@ -84,6 +87,7 @@ func initHTTPConfPrefx(prefix config.Prefix, defaultPort int) {
 	prefix.AddKnownKey(HTTPConfTLSClientAuth)
 	prefix.AddKnownKey(HTTPConfTLSEnabled, false)
 	prefix.AddKnownKey(HTTPConfTLSKeyFile)
+	prefix.AddKnownKey(HTTPConfMTLSEnabled, false)
 }
 
 func newHTTPServer(ctx context.Context, name string, r *mux.Router, onClose chan error, conf config.Prefix) (hs *httpServer, err error) {
@@ -95,6 +99,10 @@ func newHTTPServer(ctx context.Context, name string, r *mux.Router, onClose chan
 		tlsCertFile: conf.GetString(HTTPConfTLSCertFile),
 		tlsKeyFile:  conf.GetString(HTTPConfTLSKeyFile),
 	}
+	// Identities are resolved from the verified client certificate, so there must be one
+	if conf.GetBool(HTTPConfMTLSEnabled) && !(hs.tlsEnabled && conf.GetBool(HTTPConfTLSClientAuth)) {
+		return nil, i18n.NewError(ctx, i18n.MsgMTLSRequiresClientAuth, name)
+	}
 	hs.l, err = hs.createListener(ctx)
 	if err == nil {
 		hs.s, err = hs.createServer(ctx, r)
@@ -142,7 +150,7 @@ func (hs *httpServer) createServer(ctx context.Context, r *mux.Router) (srv ISer
 	}
 
 	srv = &http.Server{
-		Handler:      wrapCorsIfEnabled(ctx, r),
+		Handler:      wrapRequestID(wrapCorsIfEnabled(ctx, wrapRateLimitIfEnabled(ctx, wrapCompression(wrapETag(r))))),
 		WriteTimeout: hs.conf.GetDuration(HTTPConfWriteTimeout),
 		ReadTimeout:  hs.conf.GetDuration(HTTPConfReadTimeout),
 		TLSConfig: &tls.Config{
@@ -160,12 +168,29 @@ func (hs *httpServer) createServer(ctx context.Context, r *mux.Router) (srv ISer
 			l := log.L(ctx).WithField("req", fftypes.ShortID())
 			newCtx = log.WithLogger(newCtx, l)
 			l.Debugf("New HTTP connection: remote=%s local=%s", c.RemoteAddr().String(), c.LocalAddr().String())
-			return newCtx
+			return withConn(newCtx, c)
 		},
 	}
 	return srv, nil
 }
 
+type connContextKey struct{}
+
+// withConn stores the connection in the context, for handlers that need to manage it directly
+func withConn(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
+
+// clearWriteDeadline removes the write timeout of the server from the connection of a request, for
+// long-lived streaming responses
+func clearWriteDeadline(ctx context.Context) {
+	if c, ok := ctx.Value(connContextKey{}).(net.Conn); ok {
+		if err := c.SetWriteDeadline(time.Time{}); err != nil {
+			log.L(ctx).Warnf("Failed to clear write deadline: %s", err)
+		}
+	}
+}
+
 func (hs *httpServer) serveHTTP(ctx context.Context) {
 	serverEnded := make(chan struct{})
 	go func() {
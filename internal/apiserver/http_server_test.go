@@ -50,6 +50,15 @@ func TestInvalidListener(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestMTLSWithoutClientAuth(t *testing.T) {
+	cp := config.NewPluginConfig("ut")
+	initHTTPConfPrefx(cp, 0)
+	cp.Set(HTTPConfMTLSEnabled, true)
+	cp.Set(HTTPConfTLSEnabled, true)
+	_, err := newHTTPServer(context.Background(), "ut", mux.NewRouter(), make(chan error), cp)
+	assert.Regexp(t, "FF10357", err)
+}
+
 func TestServeFail(t *testing.T) {
 	config.Reset()
 	cp := config.NewPluginConfig("ut")
@@ -196,3 +205,43 @@ func TestTLSServerSelfSignedWithClientAuth(t *testing.T) {
 	err = <-errChan
 	assert.NoError(t, err)
 }
+
+func TestClearWriteDeadlineStreaming(t *testing.T) {
+	config.Reset()
+	cp := config.NewPluginConfig("ut")
+	initHTTPConfPrefx(cp, 0)
+	cp.Set(HTTPConfAddress, "127.0.0.1")
+	cp.Set(HTTPConfWriteTimeout, "50ms")
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	r := mux.NewRouter()
+	r.HandleFunc("/stream", func(res http.ResponseWriter, req *http.Request) {
+		clearWriteDeadline(req.Context())
+		res.WriteHeader(200)
+		res.(http.Flusher).Flush()
+		// Write after the server write timeout has passed
+		time.Sleep(200 * time.Millisecond)
+		_, _ = res.Write([]byte("done"))
+	})
+	errChan := make(chan error)
+	hs, err := newHTTPServer(context.Background(), "ut", r, errChan, cp)
+	assert.NoError(t, err)
+	go hs.serveHTTP(ctx)
+
+	res, err := http.Get(fmt.Sprintf("http://%s/stream", hs.l.Addr().String()))
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", string(b))
+
+	cancelCtx()
+	err = <-errChan
+	assert.NoError(t, err)
+}
+
+func TestClearWriteDeadlineClosedConn(t *testing.T) {
+	c1, c2 := net.Pipe()
+	c1.Close()
+	c2.Close()
+	clearWriteDeadline(withConn(context.Background(), c1)) // just logs
+	clearWriteDeadline(context.Background())               // no-op without a connection
+}
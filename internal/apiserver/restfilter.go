@@ -74,6 +74,10 @@ func (as *apiServer) buildFilter(req *http.Request, ff database.QueryFactory) (d
 	_ = req.ParseForm()
 	for _, field := range possibleFields {
 		values := as.getValues(req.Form, field)
+		// A "field~=value" query is shorthand for a contains (SQL LIKE) match ("field=@value")
+		for _, fuzzyValue := range as.getValues(req.Form, field+"~") {
+			values = append(values, "@"+fuzzyValue)
+		}
 		if len(values) == 1 {
 			filter.Condition(as.getCondition(fb, field, values[0]))
 		} else if len(values) > 0 {
@@ -0,0 +1,104 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// jsonFilterRegex matches "field.path.to.value <operator> value", such as "value.customer.id=123"
+var jsonFilterRegex = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+(?:\.[A-Za-z0-9_-]+)+)\s*(!=|=|>|<|(?i:\s+CONTAINS\s+))\s*([^=].*?)\s*$`)
+
+// JSONPathFilter is a filter on a value at a path within a JSON field, parsed from a "jsonFilter" query parameter
+type JSONPathFilter struct {
+	Field string
+	Path  []string
+	Op    database.FilterOp
+	Value driver.Value
+}
+
+func parseJSONPathFilter(ctx context.Context, expr string) (*JSONPathFilter, error) {
+	match := jsonFilterRegex.FindStringSubmatch(expr)
+	if match == nil {
+		return nil, i18n.NewError(ctx, i18n.MsgInvalidJSONFilter, expr)
+	}
+	parts := strings.Split(match[1], ".")
+	jf := &JSONPathFilter{
+		Field: parts[0],
+		Path:  parts[1:],
+	}
+	switch op := strings.ToUpper(strings.TrimSpace(match[2])); op {
+	case "=":
+		jf.Op = database.FilterOpEq
+	case "!=":
+		jf.Op = database.FilterOpNe
+	case ">":
+		jf.Op = database.FilterOpGt
+	case "<":
+		jf.Op = database.FilterOpLt
+	default: // CONTAINS
+		jf.Op = database.FilterOpCont
+	}
+
+	// Quoted values are always strings. Unquoted values are numbers where they parse as such,
+	// except for CONTAINS which is always a string match
+	rawValue := match[3]
+	switch {
+	case strings.HasPrefix(rawValue, `"`):
+		var s string
+		if err := json.Unmarshal([]byte(rawValue), &s); err != nil {
+			return nil, i18n.NewError(ctx, i18n.MsgInvalidJSONFilter, expr)
+		}
+		jf.Value = s
+	case jf.Op == database.FilterOpCont:
+		jf.Value = rawValue
+	default:
+		if i, err := strconv.ParseInt(rawValue, 10, 64); err == nil {
+			jf.Value = i
+		} else if f, err := strconv.ParseFloat(rawValue, 64); err == nil {
+			jf.Value = f
+		} else {
+			jf.Value = rawValue
+		}
+	}
+	return jf, nil
+}
+
+// Filter returns the database filter for this JSON path filter
+func (jf *JSONPathFilter) Filter(fb database.FilterBuilder) database.Filter {
+	name := jf.Field + "." + strings.Join(jf.Path, ".")
+	switch jf.Op {
+	case database.FilterOpNe:
+		return fb.Neq(name, jf.Value)
+	case database.FilterOpGt:
+		return fb.Gt(name, jf.Value)
+	case database.FilterOpLt:
+		return fb.Lt(name, jf.Value)
+	case database.FilterOpCont:
+		return fb.Contains(name, jf.Value)
+	default:
+		return fb.Eq(name, jf.Value)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSONPathFilterOps(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		expr  string
+		op    database.FilterOp
+		value interface{}
+	}{
+		{"value.customer.id=123", database.FilterOpEq, int64(123)},
+		{"value.customer.id != 123", database.FilterOpNe, int64(123)},
+		{"value.amount>1.5", database.FilterOpGt, float64(1.5)},
+		{"value.amount < -10", database.FilterOpLt, int64(-10)},
+		{`value.customer.name="Acme, Inc."`, database.FilterOpEq, "Acme, Inc."},
+		{`value.customer.id="123"`, database.FilterOpEq, "123"},
+		{"value.customer.name=acme", database.FilterOpEq, "acme"},
+		{"value.customer.name contains 123", database.FilterOpCont, "123"},
+		{`value.customer.name CONTAINS "Acme "`, database.FilterOpCont, "Acme "},
+	} {
+		jf, err := parseJSONPathFilter(ctx, test.expr)
+		assert.NoError(t, err, test.expr)
+		assert.Equal(t, "value", jf.Field, test.expr)
+		assert.Equal(t, test.op, jf.Op, test.expr)
+		assert.Equal(t, test.value, jf.Value, test.expr)
+	}
+}
+
+func TestParseJSONPathFilterPath(t *testing.T) {
+	jf, err := parseJSONPathFilter(context.Background(), "value.Customer.addresses.0.post_code=AB1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Customer", "addresses", "0", "post_code"}, jf.Path)
+}
+
+func TestParseJSONPathFilterInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"value=123",
+		"value.customer.id",
+		"value.customer.id=",
+		"value.customer.id>=10",
+		"value..id=123",
+		"value.customer id=123",
+		"value.customer.idCONTAINS123",
+		`value.customer.id="unterminated`,
+	} {
+		_, err := parseJSONPathFilter(context.Background(), expr)
+		assert.Regexp(t, "FF10337", err, expr)
+	}
+}
+
+func TestJSONPathFilterToFilter(t *testing.T) {
+	fb := database.DataQueryFactory.NewFilter(context.Background())
+	for expr, expected := range map[string]string{
+		"value.customer.id=123":          "value.customer.id == 123",
+		"value.customer.id!=abc":         "value.customer.id != 'abc'",
+		"value.amount>1.5":               "value.amount > 1.5",
+		"value.amount<10":                "value.amount < 10",
+		"value.customer.name CONTAINS a": "value.customer.name %= 'a'",
+	} {
+		jf, err := parseJSONPathFilter(context.Background(), expr)
+		assert.NoError(t, err)
+		fi, err := jf.Filter(fb).Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, fi.String())
+	}
+}
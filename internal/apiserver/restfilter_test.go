@@ -71,3 +71,17 @@ func TestBuildFilterLimitLimit(t *testing.T) {
 	_, err := as.buildFilter(req, database.MessageQueryFactory)
 	assert.Regexp(t, "FF10184.*500", err)
 }
+
+func TestBuildFilterContainsShorthand(t *testing.T) {
+	as := &apiServer{
+		maxFilterLimit: 250,
+	}
+
+	req := httptest.NewRequest("GET", "/things?description~=supply&description~=chain", nil)
+	filter, err := as.buildFilter(req, database.GroupQueryFactory)
+	assert.NoError(t, err)
+	fi, err := filter.Finalize()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "( ( description %= 'chain' ) || ( description %= 'supply' ) )", fi.String())
+}
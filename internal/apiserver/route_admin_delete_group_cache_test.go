@@ -0,0 +1,40 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteGroupCache(t *testing.T) {
+	o, r := newTestAdminServer()
+	mpm := &privatemessagingmocks.Manager{}
+	o.On("PrivateMessaging").Return(mpm)
+	req := httptest.NewRequest("DELETE", "/admin/api/v1/cache/groups", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mpm.On("FlushGroupCache").Return()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 204, res.Result().StatusCode)
+	mpm.AssertExpectations(t)
+}
@@ -0,0 +1,45 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCacheStats(t *testing.T) {
+	o, r := newTestAdminServer()
+	mpm := &privatemessagingmocks.Manager{}
+	o.On("PrivateMessaging").Return(mpm)
+	req := httptest.NewRequest("GET", "/admin/api/v1/cache/stats", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mpm.On("GroupCacheStats").Return(&fftypes.CacheStats{Size: 3, Hits: 10, Misses: 4})
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var status fftypes.CacheStatus
+	err := json.NewDecoder(res.Body).Decode(&status)
+	assert.NoError(t, err)
+	assert.Equal(t, &fftypes.CacheStats{Size: 3, Hits: 10, Misses: 4}, status.Groups)
+}
@@ -0,0 +1,49 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/oapispec"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+var postDataPurge = &oapispec.Route{
+	Name:   "postDataPurge",
+	Path:   "namespaces/{ns}/data/purge",
+	Method: http.MethodPost,
+	PathParams: []*oapispec.PathParam{
+		{Name: "ns", ExampleFromConf: config.NamespacesDefault, Description: i18n.MsgTBD},
+	},
+	QueryParams: []*oapispec.QueryParam{
+		{Name: "dryrun", Description: i18n.MsgTBD, IsBool: true},
+	},
+	FilterFactory:   nil,
+	Description:     i18n.MsgTBD,
+	JSONInputValue:  func() interface{} { return &fftypes.Byteable{} },
+	JSONOutputValue: func() interface{} { return &fftypes.PurgeResult{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	JSONInputSchema: func(ctx context.Context) string { return emptyObjectSchema },
+	JSONHandler: func(r *oapispec.APIRequest) (output interface{}, err error) {
+		return r.Or.PurgeExpiredData(r.Ctx, r.PP["ns"], strings.EqualFold(r.QP["dryrun"], "true"))
+	},
+}
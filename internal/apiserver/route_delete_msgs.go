@@ -0,0 +1,74 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/oapispec"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+var deleteMsgs = &oapispec.Route{
+	Name:   "deleteMsgs",
+	Path:   "namespaces/{ns}/messages",
+	Method: http.MethodDelete,
+	PathParams: []*oapispec.PathParam{
+		{Name: "ns", ExampleFromConf: config.NamespacesDefault, Description: i18n.MsgTBD},
+	},
+	QueryParams: []*oapispec.QueryParam{
+		{Name: "confirm", Description: i18n.MsgTBD, IsBool: true},
+		{Name: "status", Description: i18n.MsgTBD},
+		{Name: "createdBefore", Description: i18n.MsgTBD},
+	},
+	FilterFactory:   database.MessageQueryFactory,
+	Description:     i18n.MsgTBD,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return &fftypes.DeleteResult{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	JSONHandler: func(r *oapispec.APIRequest) (output interface{}, err error) {
+		if !strings.EqualFold(r.QP["confirm"], "true") {
+			return nil, i18n.NewError(r.Ctx, i18n.MsgDeleteNotConfirmed)
+		}
+		fb := r.Filter.Builder()
+		switch status := strings.ToLower(r.QP["status"]); status {
+		case "":
+		case "confirmed":
+			r.Filter.Condition(fb.Neq("confirmed", nil), fb.Eq("rejected", false))
+		case "rejected":
+			r.Filter.Condition(fb.Eq("rejected", true))
+		default:
+			return nil, i18n.NewError(r.Ctx, i18n.MsgInvalidMessageStatus, status)
+		}
+		if createdBefore := r.QP["createdBefore"]; createdBefore != "" {
+			r.Filter.Condition(fb.Lt("created", createdBefore))
+		}
+		// Every matching message is deleted, unless the caller explicitly asked for a limit
+		if _, explicitLimit := r.Req.Form["limit"]; !explicitLimit {
+			r.Filter.Limit(0)
+		}
+		deleted, err := r.Or.DeleteMessages(r.Ctx, r.PP["ns"], r.Filter)
+		if err != nil {
+			return nil, err
+		}
+		return &fftypes.DeleteResult{Deleted: deleted}, nil
+	},
+}
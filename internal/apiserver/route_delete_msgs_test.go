@@ -0,0 +1,119 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteMessages(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("DELETE", "/api/v1/namespaces/mynamespace/messages?confirm=true&type=broadcast&status=confirmed&createdBefore=2024-01-01T00:00:00Z", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("DeleteMessages", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "type == 'broadcast'") &&
+			strings.Contains(fi.String(), "confirmed != null") &&
+			strings.Contains(fi.String(), "rejected == false") &&
+			strings.Contains(fi.String(), "created < 1704067200000000000") &&
+			fi.Limit == 0
+	})).Return(int64(5), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.JSONEq(t, `{"deleted":5}`, res.Body.String())
+}
+
+func TestDeleteMessagesExplicitLimit(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("DELETE", "/api/v1/namespaces/mynamespace/messages?confirm=true&limit=10", nil)
+	res := httptest.NewRecorder()
+
+	o.On("DeleteMessages", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return fi.Limit == 10
+	})).Return(int64(10), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.JSONEq(t, `{"deleted":10}`, res.Body.String())
+}
+
+func TestDeleteMessagesByStatus(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("DeleteMessages", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "confirmed != null")
+	})).Return(int64(0), nil).Once()
+	o.On("DeleteMessages", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "rejected == true")
+	})).Return(int64(0), nil).Once()
+
+	for _, status := range []string{"confirmed", "rejected"} {
+		req := httptest.NewRequest("DELETE", "/api/v1/namespaces/mynamespace/messages?confirm=true&status="+status, nil)
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, 200, res.Result().StatusCode)
+	}
+	o.AssertExpectations(t)
+}
+
+func TestDeleteMessagesNotConfirmed(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("DELETE", "/api/v1/namespaces/mynamespace/messages?type=broadcast", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10333", res.Body.String())
+	o.AssertNotCalled(t, "DeleteMessages", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteMessagesBadStatus(t *testing.T) {
+	_, r := newTestAPIServer()
+	for _, status := range []string{"unknown", "pending"} {
+		req := httptest.NewRequest("DELETE", "/api/v1/namespaces/mynamespace/messages?confirm=true&status="+status, nil)
+		res := httptest.NewRecorder()
+
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, 400, res.Result().StatusCode)
+		assert.Regexp(t, "FF10334", res.Body.String())
+	}
+}
+
+func TestDeleteMessagesFail(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("DELETE", "/api/v1/namespaces/mynamespace/messages?confirm=true", nil)
+	res := httptest.NewRecorder()
+
+	o.On("DeleteMessages", mock.Anything, "mynamespace", mock.Anything).Return(int64(0), fmt.Errorf("pop"))
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+}
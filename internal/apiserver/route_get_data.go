@@ -33,13 +33,22 @@ var getData = &oapispec.Route{
 	PathParams: []*oapispec.PathParam{
 		{Name: "ns", ExampleFromConf: config.NamespacesDefault, Description: i18n.MsgTBD},
 	},
-	QueryParams:     nil,
+	QueryParams: []*oapispec.QueryParam{
+		{Name: "jsonFilter", Description: i18n.MsgTBD},
+	},
 	FilterFactory:   database.DataQueryFactory,
 	Description:     i18n.MsgTBD,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return []*fftypes.Data{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	JSONHandler: func(r *oapispec.APIRequest) (output interface{}, err error) {
+		if jsonFilter := r.QP["jsonFilter"]; jsonFilter != "" {
+			jf, err := parseJSONPathFilter(r.Ctx, jsonFilter)
+			if err != nil {
+				return nil, err
+			}
+			r.Filter.Condition(jf.Filter(r.Filter.Builder()))
+		}
 		return filterResult(r.Or.GetData(r.Ctx, r.PP["ns"], r.Filter))
 	},
 }
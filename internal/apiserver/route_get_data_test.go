@@ -18,8 +18,10 @@ package apiserver
 
 import (
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -37,3 +39,31 @@ func TestGetData(t *testing.T) {
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestGetDataJSONFilter(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data?jsonFilter=value.customer.id%3D123", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetData", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "value.customer.id == 123")
+	})).Return([]*fftypes.Data{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetDataJSONFilterInvalid(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data?jsonFilter=value%3D123", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10337", res.Body.String())
+	o.AssertNotCalled(t, "GetData", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -18,6 +18,7 @@ package apiserver
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/i18n"
@@ -33,13 +34,30 @@ var getMsgs = &oapispec.Route{
 	PathParams: []*oapispec.PathParam{
 		{Name: "ns", ExampleFromConf: config.NamespacesDefault, Description: i18n.MsgTBD},
 	},
-	QueryParams:     nil,
+	QueryParams: []*oapispec.QueryParam{
+		{Name: "read", Description: i18n.MsgTBD},
+		{Name: "labelKey", Description: i18n.MsgTBD},
+		{Name: "labelValue", Description: i18n.MsgTBD},
+	},
 	FilterFactory:   database.MessageQueryFactory,
 	Description:     i18n.MsgTBD,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return []*fftypes.Message{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	JSONHandler: func(r *oapispec.APIRequest) (output interface{}, err error) {
+		switch strings.ToLower(r.QP["read"]) {
+		case "true":
+			r.Filter.Condition(r.Filter.Builder().Neq("readat", nil))
+		case "false":
+			r.Filter.Condition(r.Filter.Builder().Eq("readat", nil))
+		}
+		if labelKey := r.QP["labelKey"]; labelKey != "" {
+			if labelValue := r.QP["labelValue"]; labelValue != "" {
+				r.Filter.Condition(r.Filter.Builder().JSONContains("labels", fftypes.JSONObject{labelKey: labelValue}))
+			} else {
+				r.Filter.Condition(r.Filter.Builder().JSONHasKey("labels", labelKey))
+			}
+		}
 		return filterResult(r.Or.GetMessages(r.Ctx, r.PP["ns"], r.Filter))
 	},
 }
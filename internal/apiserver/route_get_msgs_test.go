@@ -19,6 +19,7 @@ package apiserver
 import (
 	"encoding/json"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/hyperledger/firefly/pkg/database"
@@ -61,3 +62,94 @@ func TestGetMessagesWithCount(t *testing.T) {
 	assert.Equal(t, int64(0), resWithCount.Count)
 	assert.Equal(t, int64(10), resWithCount.Total)
 }
+
+func TestGetMessagesReadFilter(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?read=true", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "readat != null")
+	})).Return([]*fftypes.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetMessagesUnreadFilter(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?read=false", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "readat == null")
+	})).Return([]*fftypes.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetMessagesSensitivityFilter(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?sensitivity=restricted", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "sensitivity == 'restricted'")
+	})).Return([]*fftypes.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetMessagesThreadFilter(t *testing.T) {
+	o, r := newTestAPIServer()
+	threadID := fftypes.NewUUID()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?thread="+threadID.String(), nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "thread == '"+threadID.String()+"'")
+	})).Return([]*fftypes.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetMessagesLabelFilter(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?labelKey=department&labelValue=finance", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), `labels @> '{"department":"finance"}'`)
+	})).Return([]*fftypes.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetMessagesLabelKeyFilter(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?labelKey=department", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), `labels ? 'department'`)
+	})).Return([]*fftypes.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
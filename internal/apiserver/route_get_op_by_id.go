@@ -18,6 +18,7 @@ package apiserver
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/i18n"
@@ -33,14 +34,23 @@ var getOpByID = &oapispec.Route{
 		{Name: "ns", ExampleFromConf: config.NamespacesDefault, Description: i18n.MsgTBD},
 		{Name: "opid", Description: i18n.MsgTBD},
 	},
-	QueryParams:     nil,
+	QueryParams: []*oapispec.QueryParam{
+		{Name: "include_deleted", IsBool: true, Description: i18n.MsgTBD},
+	},
 	FilterFactory:   nil,
 	Description:     i18n.MsgTBD,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return &fftypes.Operation{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	JSONHandler: func(r *oapispec.APIRequest) (output interface{}, err error) {
-		output, err = r.Or.GetOperationByID(r.Ctx, r.PP["ns"], r.PP["opid"])
-		return output, err
+		op, err := r.Or.GetOperationByID(r.Ctx, r.PP["ns"], r.PP["opid"])
+		if err != nil || op == nil {
+			return nil, err
+		}
+		// A soft deleted operation is not found, unless explicitly requested
+		if op.DeletedAt != nil && !strings.EqualFold(r.QP["include_deleted"], "true") {
+			return nil, nil
+		}
+		return op, nil
 	},
 }
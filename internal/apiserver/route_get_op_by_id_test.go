@@ -17,6 +17,7 @@
 package apiserver
 
 import (
+	"fmt"
 	"net/http/httptest"
 	"testing"
 
@@ -37,3 +38,30 @@ func TestGetOperationByID(t *testing.T) {
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestGetOperationByIDDeleted(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("GetOperationByID", mock.Anything, "mynamespace", "abcd12345").
+		Return(&fftypes.Operation{DeletedAt: fftypes.Now()}, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations/abcd12345", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 404, res.Result().StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations/abcd12345?include_deleted=true", nil)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetOperationByIDFail(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("GetOperationByID", mock.Anything, "mynamespace", "abcd12345").
+		Return(nil, fmt.Errorf("pop"))
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations/abcd12345", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 500, res.Result().StatusCode)
+}
@@ -18,6 +18,7 @@ package apiserver
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/i18n"
@@ -33,13 +34,18 @@ var getOps = &oapispec.Route{
 	PathParams: []*oapispec.PathParam{
 		{Name: "ns", ExampleFromConf: config.NamespacesDefault, Description: i18n.MsgTBD},
 	},
-	QueryParams:     nil,
+	QueryParams: []*oapispec.QueryParam{
+		{Name: "include_deleted", IsBool: true, Description: i18n.MsgTBD},
+	},
 	FilterFactory:   database.OperationQueryFactory,
 	Description:     i18n.MsgTBD,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return []*fftypes.Operation{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	JSONHandler: func(r *oapispec.APIRequest) (output interface{}, err error) {
+		if !strings.EqualFold(r.QP["include_deleted"], "true") {
+			r.Filter.Condition(r.Filter.Builder().Eq("deleted", nil))
+		}
 		return filterResult(r.Or.GetOperations(r.Ctx, r.PP["ns"], r.Filter))
 	},
 }
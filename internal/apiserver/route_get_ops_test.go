@@ -18,8 +18,10 @@ package apiserver
 
 import (
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -31,8 +33,25 @@ func TestGetOperations(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	res := httptest.NewRecorder()
 
-	o.On("GetOperations", mock.Anything, "mynamespace", mock.Anything).
-		Return([]*fftypes.Operation{}, nil, nil)
+	o.On("GetOperations", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "deleted == null")
+	})).Return([]*fftypes.Operation{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetOperationsIncludeDeleted(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations?include_deleted=true", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetOperations", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return !strings.Contains(fi.String(), "deleted")
+	})).Return([]*fftypes.Operation{}, nil, nil)
 	r.ServeHTTP(res, req)
 
 	assert.Equal(t, 200, res.Result().StatusCode)
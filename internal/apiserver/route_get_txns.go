@@ -33,13 +33,23 @@ var getTxns = &oapispec.Route{
 	PathParams: []*oapispec.PathParam{
 		{Name: "ns", ExampleFromConf: config.NamespacesDefault, Description: i18n.MsgTBD},
 	},
-	QueryParams:     nil,
+	QueryParams: []*oapispec.QueryParam{
+		{Name: "gasPriceMin", Description: i18n.MsgTBD},
+		{Name: "gasPriceMax", Description: i18n.MsgTBD},
+	},
 	FilterFactory:   database.TransactionQueryFactory,
 	Description:     i18n.MsgTBD,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return []*fftypes.Transaction{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	JSONHandler: func(r *oapispec.APIRequest) (output interface{}, err error) {
+		fb := r.Filter.Builder()
+		if gasPriceMin := r.QP["gasPriceMin"]; gasPriceMin != "" {
+			r.Filter.Condition(fb.Gte("gasprice", gasPriceMin))
+		}
+		if gasPriceMax := r.QP["gasPriceMax"]; gasPriceMax != "" {
+			r.Filter.Condition(fb.Lte("gasprice", gasPriceMax))
+		}
 		return filterResult(r.Or.GetTransactions(r.Ctx, r.PP["ns"], r.Filter))
 	},
 }
@@ -18,8 +18,10 @@ package apiserver
 
 import (
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -37,3 +39,21 @@ func TestGetTxns(t *testing.T) {
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestGetTxnsGasPriceRange(t *testing.T) {
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/transactions?gasPriceMin=1000&gasPriceMax=0x4a817c800", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetTransactions", mock.Anything, "mynamespace", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		return strings.Contains(fi.String(), "gasprice >= '1"+strings.Repeat("0", 61)+"3e8'") &&
+			strings.Contains(fi.String(), "gasprice <= '1"+strings.Repeat("0", 55)+"4a817c800'")
+	})).Return([]*fftypes.Transaction{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	o.AssertExpectations(t)
+}
@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"mime/multipart"
 	"net/http/httptest"
+	"net/textproto"
 	"testing"
 
 	"github.com/hyperledger/firefly/internal/log"
@@ -74,6 +75,34 @@ func TestPostDataBinary(t *testing.T) {
 	assert.Equal(t, 201, res.Result().StatusCode)
 }
 
+func TestPostDataBinaryPartContentType(t *testing.T) {
+	o, r := newTestAPIServer()
+	mdm := &datamocks.Manager{}
+	o.On("Data").Return(mdm)
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="file"; filename="filename.csv"`)
+	h.Set("Content-Type", "text/csv")
+	writer, err := w.CreatePart(h)
+	assert.NoError(t, err)
+	writer.Write([]byte(`a,b,c`))
+	w.Close()
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/data", &b)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	res := httptest.NewRecorder()
+
+	mdm.On("UploadBLOB", mock.Anything, "ns1", mock.AnythingOfType("*fftypes.DataRefOrValue"), mock.MatchedBy(func(mp *fftypes.Multipart) bool {
+		return mp.Mimetype == "text/csv" && mp.Filename == "filename.csv"
+	}), false).Return(&fftypes.Data{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 201, res.Result().StatusCode)
+	mdm.AssertExpectations(t)
+}
+
 func TestPostDataBinaryObjAutoMeta(t *testing.T) {
 	log.SetLevel("debug")
 
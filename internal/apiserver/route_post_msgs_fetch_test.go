@@ -0,0 +1,50 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostMsgsFetch(t *testing.T) {
+	o, r := newTestAPIServer()
+	input := fftypes.MessageFetchInput{
+		IDs: []*fftypes.UUID{fftypes.NewUUID(), fftypes.NewUUID()},
+	}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/fetch", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessagesByIDs", mock.Anything, "ns1", input.IDs).
+		Return([]*fftypes.Message{{Header: fftypes.MessageHeader{ID: input.IDs[1]}}}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var msgs []*fftypes.Message
+	json.NewDecoder(res.Body).Decode(&msgs)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, *input.IDs[1], *msgs[0].Header.ID)
+}
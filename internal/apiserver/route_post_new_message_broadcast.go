@@ -20,6 +20,7 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/i18n"
@@ -34,6 +35,7 @@ var broadcastSchema = `{
 					 "properties": {
 							"id": {"type": "string"},
 							"hash": {"type": "string"},
+							"optional": {"type": "boolean"},
 							"validator": {"type": "string"},
 							"datatype": {
 								"type": "object",
@@ -50,6 +52,11 @@ var broadcastSchema = `{
 				},
 				"type": "array"
 		 },
+		 "inReplyTo": {},
+		 "labels": {
+				"type": "object",
+				"additionalProperties": {"type": "string"}
+		 },
 		 "header": {
 				"properties": {
 					 "author": {
@@ -60,9 +67,13 @@ var broadcastSchema = `{
 							"type": "string"
 					 },
 					 "group": {},
+					 "priority": {
+							"type": "integer"
+					 },
 					 "tag": {
 							"type": "string"
 					 },
+					 "thread": {},
 					 "topics": {
 						 	"items": {
 								 "type": "string"
@@ -93,6 +104,7 @@ var postNewMessageBroadcast = &oapispec.Route{
 	},
 	QueryParams: []*oapispec.QueryParam{
 		{Name: "confirm", Description: i18n.MsgConfirmQueryParam, IsBool: true},
+		{Name: "timeout", Example: "30s", Description: i18n.MsgBroadcastTimeoutQueryParam},
 	},
 	FilterFactory:   nil,
 	Description:     i18n.MsgTBD,
@@ -101,6 +113,14 @@ var postNewMessageBroadcast = &oapispec.Route{
 	JSONOutputValue: func() interface{} { return &fftypes.Message{} },
 	JSONOutputCodes: []int{http.StatusAccepted, http.StatusOK},
 	JSONHandler: func(r *oapispec.APIRequest) (output interface{}, err error) {
+		if timeout := r.QP["timeout"]; timeout != "" {
+			d, err := fftypes.ParseDurationString(timeout, time.Millisecond)
+			if err != nil {
+				return nil, err
+			}
+			r.SuccessStatus = http.StatusOK
+			return r.Or.Broadcast().BroadcastWithTimeout(r.Ctx, r.PP["ns"], r.Input.(*fftypes.MessageInOut), time.Duration(d))
+		}
 		waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
 		r.SuccessStatus = syncRetcode(waitConfirm)
 		output, err = r.Or.Broadcast().BroadcastMessage(r.Ctx, r.PP["ns"], r.Input.(*fftypes.MessageInOut), waitConfirm)
@@ -0,0 +1,45 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostNewMessageBroadcastRaw(t *testing.T) {
+	o, r := newTestAPIServer()
+	mbm := &broadcastmocks.Manager{}
+	o.On("Broadcast").Return(mbm)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast/raw?tag=boot", bytes.NewBufferString(`{"any":["json",1]}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mbm.On("BroadcastRawJSON", mock.Anything, "ns1", mock.MatchedBy(func(data json.RawMessage) bool {
+		return string(data) == `{"any":["json",1]}`
+	}), "boot").Return(&fftypes.Message{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Result().StatusCode)
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly/mocks/broadcastmocks"
 	"github.com/hyperledger/firefly/pkg/fftypes"
@@ -63,3 +64,36 @@ func TestPostNewMessageBroadcastSync(t *testing.T) {
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestPostNewMessageBroadcastTimeout(t *testing.T) {
+	o, r := newTestAPIServer()
+	mbm := &broadcastmocks.Manager{}
+	o.On("Broadcast").Return(mbm)
+	input := fftypes.MessageInOut{}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast?timeout=30s", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mbm.On("BroadcastWithTimeout", mock.Anything, "ns1", mock.AnythingOfType("*fftypes.MessageInOut"), 30*time.Second).
+		Return(&fftypes.Message{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestPostNewMessageBroadcastBadTimeout(t *testing.T) {
+	_, r := newTestAPIServer()
+	input := fftypes.MessageInOut{}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast?timeout=soon", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10167", res.Body.String())
+}
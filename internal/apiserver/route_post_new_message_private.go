@@ -62,6 +62,9 @@ var privateSendSchema = `{
 								},
 								"node": {
 									"type": "string"
+								},
+								"alias": {
+									"type": "string"
 								}
 							},
 							"required": ["identity"],
@@ -82,6 +85,9 @@ var privateSendSchema = `{
 							"type": "string"
 					 },
 					 "group": {},
+					 "priority": {
+							"type": "integer"
+					 },
 					 "tag": {
 							"type": "string"
 					 },
@@ -101,6 +107,10 @@ var privateSendSchema = `{
 					 }
 				},
 				"type": "object"
+		 },
+		 "labels": {
+				"type": "object",
+				"additionalProperties": {"type": "string"}
 		 }
 	},
 	"type": "object"
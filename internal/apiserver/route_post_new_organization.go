@@ -36,7 +36,7 @@ var postNewOrganization = &oapispec.Route{
 	FilterFactory:   nil,
 	Description:     i18n.MsgTBD,
 	JSONInputValue:  func() interface{} { return &fftypes.Organization{} },
-	JSONInputMask:   []string{"ID", "Created", "Message", "Type"},
+	JSONInputMask:   []string{"ID", "Created", "Message", "Type", "Signature"},
 	JSONOutputValue: func() interface{} { return &fftypes.Organization{} },
 	JSONOutputCodes: []int{http.StatusAccepted, http.StatusOK},
 	JSONHandler: func(r *oapispec.APIRequest) (output interface{}, err error) {
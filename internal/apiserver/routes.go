@@ -24,6 +24,7 @@ var routes = []*oapispec.Route{
 	postNewDatatype,
 	postNewNamespace,
 	postNewMessageBroadcast,
+	postNewMessageBroadcastRaw,
 	postNewMessagePrivate,
 	postNewMessageRequestReply,
 	postNodesSelf,
@@ -34,7 +35,13 @@ var routes = []*oapispec.Route{
 	postBroadcastMessage,
 	postBroadcastNamespace,
 	postData,
+	postMsgRead,
+	postMsgsFetch,
 	postNewSubscription,
+	postSubscriptionDeadLetterRedeliver,
+	postSubscriptionPause,
+	postSubscriptionResume,
+	postSubscriptionRewindDeprecated,
 	postRegisterOrg,
 	postRegisterNode,
 	postRegisterNodeOrg,
@@ -43,6 +50,10 @@ var routes = []*oapispec.Route{
 
 	putSubscription,
 
+	patchSubscription,
+
+	deleteMsgs,
+	deleteOp,
 	deleteSubscription,
 
 	getBatchByID,
@@ -54,31 +65,45 @@ var routes = []*oapispec.Route{
 	getDatatypeByName,
 	getDatatypes,
 	getDataMsgs,
+	getDataVersions,
 	getEventByID,
 	getEvents,
+	getGroups,
 	getMsgByID,
 	getMsgData,
+	getMsgDeliveries,
 	getMsgEvents,
 	getMsgOps,
 	getMsgTxn,
 	getMsgs,
 	getNetworkOrg,
+	getNetworkOrgDID,
 	getNetworkOrgs,
 	getNetworkNode,
+	getNetworkNodeHeartbeat,
 	getNetworkNodes,
 	getNamespace,
 	getNamespaces,
 	getOpByID,
+	getOpDelivery,
+	getOpHistory,
 	getOps,
+	getPublicStorageItems,
 	getStatus,
+	getStatusBlockchain,
 	getSubscriptionByID,
+	getSubscriptionDeadLetter,
 	getSubscriptions,
 	getTxnByID,
 	getTxnOps,
 	getTxns,
 
 	postTokenPool,
+	postTokenPoolFreeze,
+	postTokenPoolUnfreeze,
 	getTokenPools,
 	getTokenPoolByName,
+	getTokenPoolBySymbol,
 	getTokenAccounts,
+	getTokenBalances,
 }
@@ -61,6 +61,7 @@ type apiServer struct {
 	maxFilterSkip      uint64
 	apiTimeout         time.Duration
 	apiMaxTimeout      time.Duration
+	ssePollInterval    time.Duration
 }
 
 func InitConfig() {
@@ -75,6 +76,7 @@ func NewAPIServer() Server {
 		maxFilterSkip:      uint64(config.GetUint(config.APIMaxFilterSkip)),
 		apiTimeout:         config.GetDuration(config.APIRequestTimeout),
 		apiMaxTimeout:      config.GetDuration(config.APIRequestMaxTimeout),
+		ssePollInterval:    config.GetDuration(config.APISSEPollInterval),
 	}
 }
 
@@ -140,7 +142,7 @@ func (as *apiServer) getFilePart(req *http.Request) (*multipartState, error) {
 			mp := &fftypes.Multipart{
 				Data:     part,
 				Filename: part.FileName(),
-				Mimetype: part.Header.Get("Content-Disposition"),
+				Mimetype: part.Header.Get("Content-Type"),
 			}
 			return &multipartState{
 				mpr:        mpr,
@@ -356,11 +358,7 @@ func (as *apiServer) apiWrapper(handler func(res http.ResponseWriter, req *http.
 				status = 500
 			}
 			l.Infof("<-- %s %s [%d] (%.2fms): %s", req.Method, req.URL.Path, status, durationMS, err)
-			res.Header().Add("Content-Type", "application/json")
-			res.WriteHeader(status)
-			_ = json.NewEncoder(res).Encode(&fftypes.RESTError{
-				Error: err.Error(),
-			})
+			WriteError(res, status, err)
 		} else {
 			l.Infof("<-- %s %s [%d] (%.2fms)", req.Method, req.URL.Path, status, durationMS)
 		}
@@ -415,6 +413,8 @@ func (as *apiServer) swaggerHandler(routes []*oapispec.Route, url string) func(r
 
 func (as *apiServer) createMuxRouter(ctx context.Context, o orchestrator.Orchestrator) *mux.Router {
 	r := mux.NewRouter()
+	// Registered ahead of the routes, so it is not matched as the ID of an event
+	r.HandleFunc(`/api/v1/namespaces/{ns}/events/sse`, as.sseHandler(ctx, o)).Methods(http.MethodGet)
 	for _, route := range routes {
 		if route.JSONHandler != nil {
 			r.HandleFunc(fmt.Sprintf("/api/v1/%s", route.Path), as.routeHandler(o, route)).
@@ -434,6 +434,11 @@ func (as *apiServer) createMuxRouter(ctx context.Context, o orchestrator.Orchest
 		r.PathPrefix(`/ui`).Handler(newStaticHandler(uiPath, "index.html", `/ui`))
 	}
 
+	if apiConfigPrefix.GetBool(HTTPConfMTLSEnabled) {
+		r.Use(as.mtlsAuth(o))
+	}
+	r.Use(as.namespaceReadOnlyAuth())
+
 	r.NotFoundHandler = as.apiWrapper(as.notFoundHandler)
 	return r
 }
@@ -451,5 +456,10 @@ func (as *apiServer) createAdminMuxRouter(o orchestrator.Orchestrator) *mux.Rout
 	r.HandleFunc(`/admin/api`, as.apiWrapper(as.swaggerUIHandler(publicURL)))
 	r.HandleFunc(`/favicon{any:.*}.png`, favIcons)
 
+	if adminConfigPrefix.GetBool(HTTPConfMTLSEnabled) {
+		r.Use(as.mtlsAuth(o))
+	}
+	r.Use(as.adminNamespaceAuth())
+
 	return r
 }
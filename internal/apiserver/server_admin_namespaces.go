@@ -0,0 +1,57 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+)
+
+// namespaceAdminEnabled uses the "adminEnabled" setting of the predefined namespace if set,
+// falling back to the node-wide default
+func namespaceAdminEnabled(ns string) bool {
+	enabled := config.GetBool(config.NamespacesAdminEnabled)
+	for _, nsObject := range config.GetObjectArray(config.NamespacesPredefined) {
+		if nsObject.GetString("name") == ns {
+			if _, ok := nsObject["adminEnabled"]; ok {
+				enabled = nsObject.GetBool("adminEnabled")
+			}
+		}
+	}
+	return enabled
+}
+
+// adminNamespaceAuth rejects admin routes that target a namespace, where admin operations
+// have not been enabled for that namespace
+func (as *apiServer) adminNamespaceAuth() mux.MiddlewareFunc {
+	return func(chain http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			ns, isNamespaced := mux.Vars(req)["ns"]
+			if isNamespaced && !namespaceAdminEnabled(ns) {
+				err := i18n.NewError(req.Context(), i18n.MsgAdminNotEnabledForNamespace, ns)
+				log.L(req.Context()).Warnf("<-- %s %s [%d]: %s", req.Method, req.URL.Path, http.StatusForbidden, err)
+				WriteError(res, http.StatusForbidden, err)
+				return
+			}
+			chain.ServeHTTP(res, req)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testAdminNamespaceRewind(t *testing.T, expectedStatus int) {
+	o, r := newTestAdminServer()
+	req := httptest.NewRequest("POST", "/admin/api/v1/namespaces/ns1/subscriptions/sub1/rewind", bytes.NewReader([]byte(`{"fromSequence":10}`)))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("RewindSubscription", mock.Anything, "ns1", "sub1", int64(10)).Return(nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, expectedStatus, res.Result().StatusCode)
+	if expectedStatus == 403 {
+		assert.Regexp(t, "FF10300.*ns1", res.Body.String())
+		o.AssertNotCalled(t, "RewindSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestAdminNamespaceDisabledByDefault(t *testing.T) {
+	config.Reset()
+	testAdminNamespaceRewind(t, 403)
+}
+
+func TestAdminNamespaceEnabledGlobally(t *testing.T) {
+	config.Reset()
+	config.Set(config.NamespacesAdminEnabled, true)
+	testAdminNamespaceRewind(t, 204)
+}
+
+func TestAdminNamespaceEnabledOverride(t *testing.T) {
+	config.Reset()
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "ns1", "adminEnabled": true},
+	})
+	testAdminNamespaceRewind(t, 204)
+}
+
+func TestAdminNamespaceDisabledOverride(t *testing.T) {
+	config.Reset()
+	config.Set(config.NamespacesAdminEnabled, true)
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "ns1", "adminEnabled": false},
+		{"name": "ns2", "adminEnabled": true},
+	})
+	testAdminNamespaceRewind(t, 403)
+}
+
+func TestAdminNamespaceOverrideOtherNamespace(t *testing.T) {
+	config.Reset()
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "ns2", "adminEnabled": true},
+	})
+	testAdminNamespaceRewind(t, 403)
+}
+
+func TestAdminNonNamespacedRouteUnaffected(t *testing.T) {
+	config.Reset()
+	o, r := newTestAdminServer()
+	req := httptest.NewRequest("POST", "/admin/api/v1/operations/purge?olderThan=30d", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("PurgeOperations", mock.Anything, "30d").Return(nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 204, res.Result().StatusCode)
+}
@@ -0,0 +1,128 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly/internal/config"
+)
+
+// wrapCompression gzip compresses JSON responses larger than api.compression.minSize,
+// for clients that send an Accept-Encoding header including gzip
+func wrapCompression(chain http.Handler) http.Handler {
+	minSize := int(config.GetByteSize(config.APICompressionMinSize))
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Vary", "Accept-Encoding")
+		// Upgraded connections (websockets) need direct access to the underlying connection
+		if !acceptsGzip(req) || req.Header.Get("Upgrade") != "" {
+			chain.ServeHTTP(res, req)
+			return
+		}
+		gw := &gzipResponseWriter{ResponseWriter: res, minSize: minSize, status: http.StatusOK}
+		defer gw.finish()
+		chain.ServeHTTP(gw, req)
+	})
+}
+
+func acceptsGzip(req *http.Request) bool {
+	for _, encoding := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(strings.Split(encoding, ";")[0]) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of the response, until it knows whether the body
+// is large enough to be worth compressing (or the handler completes)
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize int
+	status  int
+	buffer  []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (gw *gzipResponseWriter) WriteHeader(status int) {
+	gw.status = status
+}
+
+func (gw *gzipResponseWriter) Write(b []byte) (int, error) {
+	switch {
+	case gw.gz != nil:
+		return gw.gz.Write(b)
+	case gw.decided:
+		return gw.ResponseWriter.Write(b)
+	}
+	gw.buffer = append(gw.buffer, b...)
+	if len(gw.buffer) > gw.minSize {
+		if err := gw.decide(isJSONResponse(gw.Header())); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func isJSONResponse(header http.Header) bool {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return mediaType == "application/json"
+}
+
+func (gw *gzipResponseWriter) decide(compress bool) (err error) {
+	gw.decided = true
+	if compress {
+		gw.Header().Set("Content-Encoding", "gzip")
+		gw.Header().Del("Content-Length")
+	}
+	gw.ResponseWriter.WriteHeader(gw.status)
+	if compress {
+		gw.gz = gzip.NewWriter(gw.ResponseWriter)
+		_, err = gw.gz.Write(gw.buffer)
+	} else if len(gw.buffer) > 0 {
+		_, err = gw.ResponseWriter.Write(gw.buffer)
+	}
+	gw.buffer = nil
+	return err
+}
+
+// Flush sends anything written so far, so streamed responses (such as server-sent events) are not held back
+// waiting for enough data to decide on compression
+func (gw *gzipResponseWriter) Flush() {
+	if !gw.decided {
+		_ = gw.decide(isJSONResponse(gw.Header()))
+	}
+	if gw.gz != nil {
+		_ = gw.gz.Flush()
+	}
+	if f, ok := gw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (gw *gzipResponseWriter) finish() {
+	if !gw.decided {
+		_ = gw.decide(false)
+	}
+	if gw.gz != nil {
+		_ = gw.gz.Close()
+	}
+}
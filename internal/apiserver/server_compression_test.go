@@ -0,0 +1,220 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (fw *failingResponseWriter) Write(b []byte) (int, error) {
+	return 0, fmt.Errorf("pop")
+}
+
+func jsonHandler(status int, output interface{}) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "application/json")
+		res.WriteHeader(status)
+		_ = json.NewEncoder(res).Encode(output)
+	})
+}
+
+func TestCompressionLargeJSONArray(t *testing.T) {
+	config.Reset()
+	ops := make([]*fftypes.Operation, 100)
+	for i := range ops {
+		ops[i] = &fftypes.Operation{ID: fftypes.NewUUID(), Namespace: "ns1", Type: fftypes.OpTypeBlockchainBatchPin}
+	}
+	handler := wrapCompression(jsonHandler(202, ops))
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/operations", nil)
+	req.Header.Set("Accept-Encoding", "deflate, gzip;q=1.0")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Code)
+	assert.Equal(t, "gzip", res.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", res.Header().Get("Vary"))
+
+	uncompressed, _ := json.Marshal(ops)
+	assert.Less(t, res.Body.Len(), len(uncompressed))
+
+	gz, err := gzip.NewReader(res.Body)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(gz)
+	assert.NoError(t, err)
+	var opsRead []*fftypes.Operation
+	err = json.Unmarshal(b, &opsRead)
+	assert.NoError(t, err)
+	assert.Equal(t, len(ops), len(opsRead))
+	assert.Equal(t, *ops[99].ID, *opsRead[99].ID)
+}
+
+func TestCompressionSmallResponse(t *testing.T) {
+	config.Reset()
+	handler := wrapCompression(jsonHandler(200, map[string]string{"small": "response"}))
+
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Empty(t, res.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", res.Header().Get("Vary"))
+	assert.Equal(t, "{\"small\":\"response\"}\n", res.Body.String())
+}
+
+func TestCompressionNoBody(t *testing.T) {
+	config.Reset()
+	handler := wrapCompression(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(204)
+	}))
+
+	req := httptest.NewRequest("DELETE", "/api/v1/things", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, 204, res.Code)
+	assert.Empty(t, res.Header().Get("Content-Encoding"))
+	assert.Zero(t, res.Body.Len())
+}
+
+func TestCompressionLargeNonJSON(t *testing.T) {
+	config.Reset()
+	body := strings.Repeat("a", 4096)
+	handler := wrapCompression(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = res.Write([]byte(body[0:2048]))
+		_, _ = res.Write([]byte(body[2048:]))
+	}))
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/data/abcd/blob", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Empty(t, res.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, res.Body.String())
+}
+
+func TestCompressionNotAccepted(t *testing.T) {
+	config.Reset()
+	handler := wrapCompression(jsonHandler(200, strings.Repeat("a", 4096)))
+
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Empty(t, res.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", res.Header().Get("Vary"))
+	assert.Equal(t, 4099, res.Body.Len())
+}
+
+func TestCompressionSkippedForUpgrade(t *testing.T) {
+	config.Reset()
+	handler := wrapCompression(jsonHandler(200, strings.Repeat("a", 4096)))
+
+	req := httptest.NewRequest("GET", "/ws", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Upgrade", "websocket")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Empty(t, res.Header().Get("Content-Encoding"))
+}
+
+func TestCompressionWriteFail(t *testing.T) {
+	config.Reset()
+	gw := &gzipResponseWriter{ResponseWriter: &failingResponseWriter{httptest.NewRecorder()}, minSize: 1, status: 200}
+	_, err := gw.Write([]byte("not json"))
+	assert.Regexp(t, "pop", err)
+}
+
+func TestCompressionMultipleWrites(t *testing.T) {
+	config.Reset()
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json; charset=utf-8")
+	gw := &gzipResponseWriter{ResponseWriter: rec, minSize: 4, status: 200}
+	_, err := gw.Write([]byte(`["first",`))
+	assert.NoError(t, err)
+	_, err = gw.Write([]byte(`"second"]`))
+	assert.NoError(t, err)
+	gw.finish()
+
+	gz, err := gzip.NewReader(rec.Body)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, `["first","second"]`, string(b))
+}
+
+func TestCompressionFlushStreamed(t *testing.T) {
+	config.Reset()
+	handler := wrapCompression(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/event-stream")
+		res.(http.Flusher).Flush()
+		_, _ = res.Write([]byte("data: {}\n\n"))
+		res.(http.Flusher).Flush()
+	}))
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.True(t, res.Flushed)
+	assert.Empty(t, res.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: {}\n\n", res.Body.String())
+}
+
+func TestCompressionFlushJSON(t *testing.T) {
+	config.Reset()
+	handler := wrapCompression(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "application/json")
+		_, _ = res.Write([]byte(`{"some":"json"}`))
+		res.(http.Flusher).Flush()
+	}))
+
+	req := httptest.NewRequest("GET", "/api/v1/things", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, "gzip", res.Header().Get("Content-Encoding"))
+	gz, err := gzip.NewReader(res.Body)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"some":"json"}`, string(b))
+}
@@ -0,0 +1,60 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/pkg/errors"
+)
+
+// APIError is the body of every error response from the API server
+type APIError struct {
+	Error        string             `json:"error"` // the full error string, kept for clients that only understand this field
+	ErrorCode    string             `json:"errorCode,omitempty"`
+	ErrorMessage string             `json:"errorMessage"`
+	Details      fftypes.JSONObject `json:"details,omitempty"`
+}
+
+// newAPIError splits a FireFly coded error, such as "FF10109: Not found", into its code and message.
+// If the error wraps another error, the underlying cause is returned in the details
+func newAPIError(err error) *APIError {
+	apiErr := &APIError{
+		Error:        err.Error(),
+		ErrorMessage: err.Error(),
+	}
+	if ffcodeExtract := ffcodeExtractor.FindStringSubmatch(apiErr.Error); len(ffcodeExtract) >= 2 {
+		apiErr.ErrorCode = ffcodeExtract[1]
+		apiErr.ErrorMessage = strings.TrimSpace(strings.TrimPrefix(apiErr.Error, ffcodeExtract[0]))
+	}
+	if cause := errors.Cause(err); cause != err {
+		apiErr.Details = fftypes.JSONObject{
+			"cause": cause.Error(),
+		}
+	}
+	return apiErr
+}
+
+// WriteError writes an error response, with the supplied HTTP status code
+func WriteError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(newAPIError(err))
+}
@@ -0,0 +1,96 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/oapispec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteErrorCoded(t *testing.T) {
+	res := httptest.NewRecorder()
+	WriteError(res, 400, i18n.NewError(context.Background(), i18n.MsgNilDataReferenceSealFail, 1))
+
+	assert.Equal(t, 400, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	var resJSON map[string]interface{}
+	err := json.NewDecoder(res.Body).Decode(&resJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"error":        "FF10144: Invalid message: nil data reference at index 1",
+		"errorCode":    "FF10144",
+		"errorMessage": "Invalid message: nil data reference at index 1",
+	}, resJSON)
+}
+
+func TestWriteErrorWrapped(t *testing.T) {
+	res := httptest.NewRecorder()
+	WriteError(res, 500, i18n.WrapError(context.Background(), fmt.Errorf("pop"), i18n.MsgDBQueryFailed))
+
+	var apiErr APIError
+	err := json.NewDecoder(res.Body).Decode(&apiErr)
+	assert.NoError(t, err)
+	assert.Equal(t, "FF10115", apiErr.ErrorCode)
+	assert.Regexp(t, "^Database query failed: pop$", apiErr.ErrorMessage)
+	assert.Equal(t, "pop", apiErr.Details.GetString("cause"))
+}
+
+func TestWriteErrorUncoded(t *testing.T) {
+	res := httptest.NewRecorder()
+	WriteError(res, 500, fmt.Errorf("pop"))
+
+	var resJSON map[string]interface{}
+	err := json.NewDecoder(res.Body).Decode(&resJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"error":        "pop",
+		"errorMessage": "pop",
+	}, resJSON)
+}
+
+func TestRouteErrorResponseStructure(t *testing.T) {
+	mo, as := newTestServer()
+	handler := as.routeHandler(mo, &oapispec.Route{
+		Name:            "testRoute",
+		Path:            "/test",
+		Method:          "GET",
+		JSONOutputCodes: []int{200},
+		JSONHandler: func(r *oapispec.APIRequest) (output interface{}, err error) {
+			return nil, i18n.NewError(r.Ctx, i18n.MsgNilDataReferenceSealFail, 0)
+		},
+	})
+	s := httptest.NewServer(http.HandlerFunc(handler))
+	defer s.Close()
+
+	res, err := http.Get(fmt.Sprintf("http://%s/test", s.Listener.Addr()))
+	assert.NoError(t, err)
+	assert.Equal(t, 400, res.StatusCode)
+	var apiErr APIError
+	err = json.NewDecoder(res.Body).Decode(&apiErr)
+	assert.NoError(t, err)
+	assert.Equal(t, "FF10144", apiErr.ErrorCode)
+	assert.Equal(t, "Invalid message: nil data reference at index 0", apiErr.ErrorMessage)
+	assert.Nil(t, apiErr.Details)
+}
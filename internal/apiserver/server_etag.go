@@ -0,0 +1,147 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/karlseguin/ccache"
+)
+
+// etagHandler sets an ETag of the SHA-256 hash of the body on successful JSON responses to GET requests,
+// and replies 304 Not Modified when the request carries a matching If-None-Match header.
+// The body has to be generated to know whether it has changed, but is not sent if it hasn't.
+// The ETag is weak, as the hash is of the uncompressed body, and the same tag is sent with the gzip and
+// identity encodings of the response.
+// The last ETag issued for each request URI is remembered in an LRU cache bounded by api.cache.maxEntries,
+// and only an ETag issued for the same URI (that has not been evicted) can result in a 304.
+type etagHandler struct {
+	chain http.Handler
+	cache *ccache.Cache
+	ttl   time.Duration
+}
+
+func wrapETag(chain http.Handler) http.Handler {
+	return &etagHandler{
+		chain: chain,
+		cache: ccache.New(
+			ccache.Configure().
+				MaxSize(config.GetInt64(config.APICacheMaxEntries)).
+				ItemsToPrune(1),
+		),
+		ttl: config.GetDuration(config.APICacheTTL),
+	}
+}
+
+func (eh *etagHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	// Upgraded connections (websockets) need direct access to the underlying connection
+	if req.Method != http.MethodGet || req.Header.Get("Upgrade") != "" {
+		eh.chain.ServeHTTP(res, req)
+		return
+	}
+	ew := &etagResponseWriter{ResponseWriter: res, req: req, status: http.StatusOK, cache: eh.cache, ttl: eh.ttl}
+	defer ew.finish()
+	eh.chain.ServeHTTP(ew, req)
+}
+
+// etagMatches uses the weak comparison required for If-None-Match, ignoring any W/ prefix
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// etagResponseWriter buffers successful JSON responses, so the ETag can be calculated
+// before the headers are sent. Anything else (such as blob downloads) is passed straight through.
+type etagResponseWriter struct {
+	http.ResponseWriter
+	req       *http.Request
+	cache     *ccache.Cache
+	ttl       time.Duration
+	status    int
+	buffer    bytes.Buffer
+	decided   bool
+	buffering bool
+}
+
+func (ew *etagResponseWriter) WriteHeader(status int) {
+	ew.status = status
+}
+
+func (ew *etagResponseWriter) decide() {
+	if !ew.decided {
+		ew.decided = true
+		ew.buffering = ew.status == http.StatusOK && isJSONResponse(ew.Header())
+		if !ew.buffering {
+			ew.ResponseWriter.WriteHeader(ew.status)
+		}
+	}
+}
+
+func (ew *etagResponseWriter) Write(b []byte) (int, error) {
+	ew.decide()
+	if ew.buffering {
+		return ew.buffer.Write(b)
+	}
+	return ew.ResponseWriter.Write(b)
+}
+
+// Flush passes through for streamed responses (such as server-sent events). Buffered JSON is not flushed.
+func (ew *etagResponseWriter) Flush() {
+	ew.decide()
+	if f, ok := ew.ResponseWriter.(http.Flusher); ok && !ew.buffering {
+		f.Flush()
+	}
+}
+
+func (ew *etagResponseWriter) finish() {
+	if !ew.decided {
+		ew.ResponseWriter.WriteHeader(ew.status)
+		return
+	}
+	if !ew.buffering {
+		return
+	}
+	hash := sha256.Sum256(ew.buffer.Bytes())
+	etag := `W/"` + hex.EncodeToString(hash[:]) + `"`
+	ew.Header().Set("ETag", etag)
+	uri := ew.req.URL.RequestURI()
+	var issued string
+	if cached := ew.cache.Get(uri); cached != nil && !cached.Expired() {
+		issued = cached.Value().(string)
+	}
+	ew.cache.Set(uri, etag, ew.ttl)
+	if ifNoneMatch := ew.req.Header.Get("If-None-Match"); ifNoneMatch != "" && issued == etag && etagMatches(ifNoneMatch, etag) {
+		ew.Header().Del("Content-Type")
+		ew.Header().Del("Content-Length")
+		ew.ResponseWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+	ew.ResponseWriter.WriteHeader(http.StatusOK)
+	_, _ = ew.ResponseWriter.Write(ew.buffer.Bytes())
+}
@@ -0,0 +1,196 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETagNotModifiedUntilChanged(t *testing.T) {
+	config.Reset()
+	op := &fftypes.Operation{ID: fftypes.NewUUID(), Status: fftypes.OpStatusPending}
+	handler := wrapETag(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		jsonHandler(200, op).ServeHTTP(res, req)
+	}))
+
+	// First request gets the full response, and an ETag
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/operations/"+op.ID.String(), nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+	etag := res.Header().Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]{64}"$`, etag)
+	assert.Contains(t, res.Body.String(), string(fftypes.OpStatusPending))
+
+	// Second request with the ETag is not modified
+	req = httptest.NewRequest("GET", "/api/v1/namespaces/ns1/operations/"+op.ID.String(), nil)
+	req.Header.Set("If-None-Match", etag)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 304, res.Code)
+	assert.Equal(t, etag, res.Header().Get("ETag"))
+	assert.Empty(t, res.Header().Get("Content-Type"))
+	assert.Empty(t, res.Body.Bytes())
+
+	// Once the resource changes, the full response is returned with a new ETag
+	op.Status = fftypes.OpStatusSucceeded
+	req = httptest.NewRequest("GET", "/api/v1/namespaces/ns1/operations/"+op.ID.String(), nil)
+	req.Header.Set("If-None-Match", etag)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+	assert.NotEqual(t, etag, res.Header().Get("ETag"))
+	assert.Contains(t, res.Body.String(), string(fftypes.OpStatusSucceeded))
+}
+
+func TestETagWeakWithCompression(t *testing.T) {
+	config.Reset()
+	config.Set(config.APICompressionMinSize, "0")
+	defer config.Reset()
+	handler := wrapCompression(wrapETag(jsonHandler(200, map[string]string{"some": "data"})))
+
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+	identityETag := res.Header().Get("ETag")
+
+	// The gzip encoding has the same weak ETag, and is validated by it
+	req = httptest.NewRequest("GET", "/api/v1/status", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "gzip", res.Header().Get("Content-Encoding"))
+	assert.Equal(t, identityETag, res.Header().Get("ETag"))
+
+	req = httptest.NewRequest("GET", "/api/v1/status", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("If-None-Match", identityETag)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 304, res.Code)
+}
+
+func TestETagEvictedNotMatched(t *testing.T) {
+	config.Reset()
+	config.Set(config.APICacheMaxEntries, 1)
+	defer config.Reset()
+	eh := wrapETag(jsonHandler(200, map[string]string{"some": "data"})).(*etagHandler)
+
+	res := httptest.NewRecorder()
+	eh.ServeHTTP(res, httptest.NewRequest("GET", "/a", nil))
+	etag := res.Header().Get("ETag")
+	eh.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/b", nil))
+	assert.Eventually(t, func() bool { return eh.cache.ItemCount() == 1 }, time.Second, time.Millisecond)
+
+	// The ETag issued for /a has been evicted, so the full response is returned
+	req := httptest.NewRequest("GET", "/a", nil)
+	req.Header.Set("If-None-Match", etag)
+	res = httptest.NewRecorder()
+	eh.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, etag, res.Header().Get("ETag"))
+
+	// An ETag issued for a different URI does not match
+	req = httptest.NewRequest("GET", "/c", nil)
+	req.Header.Set("If-None-Match", etag)
+	res = httptest.NewRecorder()
+	eh.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+}
+
+func TestETagMatchesList(t *testing.T) {
+	assert.True(t, etagMatches(`"aaa", W/"bbb"`, `W/"bbb"`))
+	assert.True(t, etagMatches(`"bbb"`, `W/"bbb"`))
+	assert.True(t, etagMatches(`*`, `"bbb"`))
+	assert.False(t, etagMatches(`"aaa"`, `"bbb"`))
+}
+
+func TestETagSkippedForNonGET(t *testing.T) {
+	handler := wrapETag(jsonHandler(200, map[string]string{"some": "data"}))
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+	assert.Empty(t, res.Header().Get("ETag"))
+}
+
+func TestETagSkippedForUpgrade(t *testing.T) {
+	handler := wrapETag(jsonHandler(200, map[string]string{"some": "data"}))
+	req := httptest.NewRequest("GET", "/ws", nil)
+	req.Header.Set("Upgrade", "websocket")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Empty(t, res.Header().Get("ETag"))
+}
+
+func TestETagSkippedForErrors(t *testing.T) {
+	handler := wrapETag(jsonHandler(404, map[string]string{"error": "not found"}))
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/operations/12345", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 404, res.Code)
+	assert.Empty(t, res.Header().Get("ETag"))
+	assert.Contains(t, res.Body.String(), "not found")
+}
+
+func TestETagSkippedForNonJSON(t *testing.T) {
+	handler := wrapETag(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = res.Write([]byte("blob"))
+	}))
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/data/12345/blob", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+	assert.Empty(t, res.Header().Get("ETag"))
+	assert.Equal(t, "blob", res.Body.String())
+}
+
+func TestETagNoBody(t *testing.T) {
+	handler := wrapETag(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(204)
+	}))
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/operations/12345", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 204, res.Code)
+	assert.Empty(t, res.Header().Get("ETag"))
+}
+
+func TestETagFlushStreamed(t *testing.T) {
+	handler := wrapETag(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/event-stream")
+		res.(http.Flusher).Flush()
+		_, _ = res.Write([]byte("data: {}\n\n"))
+		res.(http.Flusher).Flush()
+	}))
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.True(t, res.Flushed)
+	assert.Empty(t, res.Header().Get("ETag"))
+	assert.Equal(t, "data: {}\n\n", res.Body.String())
+}
@@ -0,0 +1,110 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+type authIdentityCtxKey struct{}
+
+// withAuthIdentity stores the identity authenticated for the request on the context
+func withAuthIdentity(ctx context.Context, identity *fftypes.Identity) context.Context {
+	return context.WithValue(ctx, authIdentityCtxKey{}, identity)
+}
+
+// GetAuthIdentity returns the identity authenticated for the request, or nil if there is none
+func GetAuthIdentity(ctx context.Context) *fftypes.Identity {
+	identity, _ := ctx.Value(authIdentityCtxKey{}).(*fftypes.Identity)
+	return identity
+}
+
+// certIdentifiers returns the candidate identifiers from a client certificate - the CN first, then any SANs
+func certIdentifiers(cert *x509.Certificate) []string {
+	identifiers := []string{}
+	if cert.Subject.CommonName != "" {
+		identifiers = append(identifiers, cert.Subject.CommonName)
+	}
+	identifiers = append(identifiers, cert.DNSNames...)
+	identifiers = append(identifiers, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		identifiers = append(identifiers, u.String())
+	}
+	return identifiers
+}
+
+// certOrganization finds the registered organization for a client certificate identifier, which can be
+// either the identity the organization registered with, or its name. Returns nil if there is none.
+func certOrganization(ctx context.Context, o orchestrator.Orchestrator, identifier string) (*fftypes.Organization, error) {
+	org, err := o.NetworkMap().GetOrganizationByIdentity(ctx, identifier)
+	if err != nil || org != nil {
+		return org, err
+	}
+	return o.NetworkMap().GetOrganizationByName(ctx, identifier)
+}
+
+// resolveClientCert authenticates the client certificate as a registered organization. Only an identifier
+// matching an organization in the network map is accepted, and the identity returned is the signing
+// identity of that organization.
+func (as *apiServer) resolveClientCert(ctx context.Context, o orchestrator.Orchestrator, req *http.Request) (*fftypes.Identity, int, error) {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return nil, http.StatusUnauthorized, i18n.NewError(ctx, i18n.MsgClientCertRequired)
+	}
+	cert := req.TLS.PeerCertificates[0]
+	for _, identifier := range certIdentifiers(cert) {
+		org, err := certOrganization(ctx, o, identifier)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if org == nil {
+			log.L(ctx).Debugf("Client certificate identifier '%s' does not match a registered organization", identifier)
+			continue
+		}
+		identity, err := o.Identity().Resolve(ctx, org.Identity)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return identity, http.StatusOK, nil
+	}
+	return nil, http.StatusUnauthorized, i18n.NewError(ctx, i18n.MsgClientCertIdentityUnknown, cert.Subject)
+}
+
+// mtlsAuth authenticates each request by resolving the identity of the client certificate,
+// and stores the resolved identity on the request context
+func (as *apiServer) mtlsAuth(o orchestrator.Orchestrator) mux.MiddlewareFunc {
+	return func(chain http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+			identity, status, err := as.resolveClientCert(ctx, o, req)
+			if err != nil {
+				log.L(ctx).Warnf("<-- %s %s [%d]: %s", req.Method, req.URL.Path, status, err)
+				WriteError(res, status, err)
+				return
+			}
+			ctx = fftypes.WithCreatedBy(withAuthIdentity(ctx, identity), identity.Identifier)
+			chain.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
+}
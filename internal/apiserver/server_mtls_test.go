@@ -0,0 +1,211 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/mocks/identitymocks"
+	"github.com/hyperledger/firefly/mocks/networkmapmocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSelfSignedTestCert(t *testing.T, cn string, dnsNames []string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     dnsNames,
+		URIs:         []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/org1"}},
+		NotBefore:    time.Now().Add(-1 * time.Minute),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert
+}
+
+func newTestMTLSRequest(cert *x509.Certificate) *http.Request {
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	if cert != nil {
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	}
+	return req
+}
+
+func TestMTLSAuthResolvesCN(t *testing.T) {
+	o, as := newTestServer()
+	mnm := &networkmapmocks.Manager{}
+	mii := &identitymocks.Plugin{}
+	o.On("NetworkMap").Return(mnm)
+	o.On("Identity").Return(mii)
+	identity := &fftypes.Identity{Identifier: "org1", OnChain: "0x12345"}
+	mnm.On("GetOrganizationByIdentity", mock.Anything, "org1").Return(&fftypes.Organization{Name: "org1", Identity: "org1"}, nil)
+	mii.On("Resolve", mock.Anything, "org1").Return(identity, nil)
+
+	var resolved *fftypes.Identity
+	var createdBy string
+	handler := as.mtlsAuth(o)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		resolved = GetAuthIdentity(req.Context())
+		createdBy = fftypes.GetCreatedBy(req.Context())
+		res.WriteHeader(http.StatusOK)
+	}))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, newTestMTLSRequest(newSelfSignedTestCert(t, "org1", nil)))
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, identity, resolved)
+	assert.Equal(t, "org1", createdBy)
+}
+
+func TestMTLSAuthFallsBackToSANOrgName(t *testing.T) {
+	o, as := newTestServer()
+	mnm := &networkmapmocks.Manager{}
+	mii := &identitymocks.Plugin{}
+	o.On("NetworkMap").Return(mnm)
+	o.On("Identity").Return(mii)
+	identity := &fftypes.Identity{Identifier: "0x12345", OnChain: "0x12345"}
+	mnm.On("GetOrganizationByIdentity", mock.Anything, mock.Anything).Return(nil, nil)
+	mnm.On("GetOrganizationByName", mock.Anything, "unknown").Return(nil, nil)
+	mnm.On("GetOrganizationByName", mock.Anything, "org1.example.com").Return(&fftypes.Organization{Name: "org1.example.com", Identity: "0x12345"}, nil)
+	mii.On("Resolve", mock.Anything, "0x12345").Return(identity, nil)
+
+	var resolved *fftypes.Identity
+	handler := as.mtlsAuth(o)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		resolved = GetAuthIdentity(req.Context())
+		res.WriteHeader(http.StatusOK)
+	}))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, newTestMTLSRequest(newSelfSignedTestCert(t, "unknown", []string{"org1.example.com"})))
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, identity, resolved)
+}
+
+func TestMTLSAuthUnknownCN(t *testing.T) {
+	o, as := newTestServer()
+	mnm := &networkmapmocks.Manager{}
+	mii := &identitymocks.Plugin{}
+	o.On("NetworkMap").Return(mnm)
+	o.On("Identity").Return(mii)
+	mnm.On("GetOrganizationByIdentity", mock.Anything, mock.Anything).Return(nil, nil)
+	mnm.On("GetOrganizationByName", mock.Anything, mock.Anything).Return(nil, nil)
+
+	handler := as.mtlsAuth(o)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Fail(t, "should not be called")
+	}))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, newTestMTLSRequest(newSelfSignedTestCert(t, "unknown", []string{"unknown.example.com"})))
+
+	assert.Equal(t, 401, res.Result().StatusCode)
+	assert.Regexp(t, "FF10279", res.Body.String())
+	mnm.AssertNumberOfCalls(t, "GetOrganizationByIdentity", 3)
+	mnm.AssertNumberOfCalls(t, "GetOrganizationByName", 3)
+	// Any name the identity plugin could resolve is not enough, without a registered organization
+	mii.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything)
+}
+
+func TestMTLSAuthLookupFail(t *testing.T) {
+	o, as := newTestServer()
+	mnm := &networkmapmocks.Manager{}
+	o.On("NetworkMap").Return(mnm)
+	mnm.On("GetOrganizationByIdentity", mock.Anything, "org1").Return(nil, fmt.Errorf("pop"))
+
+	handler := as.mtlsAuth(o)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Fail(t, "should not be called")
+	}))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, newTestMTLSRequest(newSelfSignedTestCert(t, "org1", nil)))
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+	assert.Regexp(t, "pop", res.Body.String())
+}
+
+func TestMTLSAuthResolveOrgIdentityFail(t *testing.T) {
+	o, as := newTestServer()
+	mnm := &networkmapmocks.Manager{}
+	mii := &identitymocks.Plugin{}
+	o.On("NetworkMap").Return(mnm)
+	o.On("Identity").Return(mii)
+	mnm.On("GetOrganizationByIdentity", mock.Anything, "org1").Return(&fftypes.Organization{Identity: "org1"}, nil)
+	mii.On("Resolve", mock.Anything, "org1").Return(nil, fmt.Errorf("pop"))
+
+	handler := as.mtlsAuth(o)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Fail(t, "should not be called")
+	}))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, newTestMTLSRequest(newSelfSignedTestCert(t, "org1", nil)))
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+	assert.Regexp(t, "pop", res.Body.String())
+}
+
+func TestMTLSAuthNoCert(t *testing.T) {
+	config.Reset()
+	InitConfig()
+	apiConfigPrefix.Set(HTTPConfMTLSEnabled, true)
+	defer config.Reset()
+	_, r := newTestAPIServer()
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, newTestMTLSRequest(nil))
+
+	assert.Equal(t, 401, res.Result().StatusCode)
+	assert.Regexp(t, "FF10278", res.Body.String())
+}
+
+func TestMTLSAuthAdminEnabled(t *testing.T) {
+	config.Reset()
+	InitConfig()
+	adminConfigPrefix.Set(HTTPConfMTLSEnabled, true)
+	defer config.Reset()
+	o, r := newTestAdminServer()
+	mnm := &networkmapmocks.Manager{}
+	mii := &identitymocks.Plugin{}
+	o.On("NetworkMap").Return(mnm)
+	o.On("Identity").Return(mii)
+	mnm.On("GetOrganizationByIdentity", mock.Anything, "org1").Return(&fftypes.Organization{Identity: "org1"}, nil)
+	mii.On("Resolve", mock.Anything, "org1").Return(&fftypes.Identity{Identifier: "org1"}, nil)
+	o.On("GetConfig", mock.Anything).Return(fftypes.JSONObject{})
+
+	req := httptest.NewRequest("GET", "/admin/api/v1/config", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{newSelfSignedTestCert(t, "org1", nil)}}
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
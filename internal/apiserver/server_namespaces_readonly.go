@@ -0,0 +1,54 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+)
+
+// namespaceReadOnly uses the "readOnly" setting of the predefined namespace, which defaults to false
+func namespaceReadOnly(ns string) bool {
+	for _, nsObject := range config.GetObjectArray(config.NamespacesPredefined) {
+		if nsObject.GetString("name") == ns {
+			return nsObject.GetBool("readOnly")
+		}
+	}
+	return false
+}
+
+// namespaceReadOnlyAuth rejects requests that write to a namespace configured as read-only,
+// so a node can observe the history of a namespace without risking accidental writes
+func (as *apiServer) namespaceReadOnlyAuth() mux.MiddlewareFunc {
+	return func(chain http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			ns, isNamespaced := mux.Vars(req)["ns"]
+			isWrite := req.Method != http.MethodGet && req.Method != http.MethodHead && req.Method != http.MethodOptions
+			if isNamespaced && isWrite && namespaceReadOnly(ns) {
+				err := i18n.NewError(req.Context(), i18n.MsgNamespaceReadOnly, ns)
+				log.L(req.Context()).Warnf("<-- %s %s [%d]: %s", req.Method, req.URL.Path, http.StatusMethodNotAllowed, err)
+				WriteError(res, http.StatusMethodNotAllowed, err)
+				return
+			}
+			chain.ServeHTTP(res, req)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testNamespaceBroadcast(t *testing.T, expectedStatus int) {
+	o, r := newTestAPIServer()
+	mbm := &broadcastmocks.Manager{}
+	o.On("Broadcast").Return(mbm)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mbm.On("BroadcastMessage", mock.Anything, "ns1", mock.AnythingOfType("*fftypes.MessageInOut"), false).
+		Return(&fftypes.Message{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, expectedStatus, res.Result().StatusCode)
+	if expectedStatus == 405 {
+		assert.Regexp(t, "FF10331.*ns1", res.Body.String())
+		mbm.AssertNotCalled(t, "BroadcastMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestNamespaceWritableByDefault(t *testing.T) {
+	config.Reset()
+	testNamespaceBroadcast(t, 202)
+}
+
+func TestNamespaceReadOnlyRejectsWrites(t *testing.T) {
+	config.Reset()
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "ns1", "readOnly": true},
+	})
+	testNamespaceBroadcast(t, 405)
+}
+
+func TestNamespaceReadOnlyOtherNamespace(t *testing.T) {
+	config.Reset()
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "ns1", "readOnly": false},
+		{"name": "ns2", "readOnly": true},
+	})
+	testNamespaceBroadcast(t, 202)
+}
+
+func TestNamespaceReadOnlyAllowsReads(t *testing.T) {
+	config.Reset()
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "ns1", "readOnly": true},
+	})
+	o, r := newTestAPIServer()
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/messages", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
@@ -0,0 +1,119 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"golang.org/x/time/rate"
+)
+
+type ipLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type ipRateLimiter struct {
+	mux         sync.Mutex
+	limit       rate.Limit
+	burst       int
+	evictAfter  time.Duration
+	lastEvicted time.Time
+	ips         map[string]*ipLimiter
+}
+
+func wrapRateLimitIfEnabled(ctx context.Context, chain http.Handler) http.Handler {
+	if !config.GetBool(config.APIRateLimitEnabled) {
+		return chain
+	}
+	rps := config.GetFloat64(config.APIRateLimitRequestsPerSecond)
+	burst := config.GetInt(config.APIRateLimitBurst)
+	log.L(ctx).Debugf("Rate limit requestsPerSecond=%f burst=%d", rps, burst)
+	return RateLimitMiddleware(rps, burst)(chain)
+}
+
+// RateLimitMiddleware limits the rate of requests from each remote IP address, returning
+// a 429 with a Retry-After header to callers that exceed it. Limiter state for an IP is
+// discarded once it has been idle for longer than api.rateLimit.ipEvictAfter.
+func RateLimitMiddleware(requestsPerSecond float64, burst int) func(http.Handler) http.Handler {
+	rl := &ipRateLimiter{
+		limit:       rate.Limit(requestsPerSecond),
+		burst:       burst,
+		evictAfter:  config.GetDuration(config.APIRateLimitIPEvictAfter),
+		lastEvicted: time.Now(),
+		ips:         make(map[string]*ipLimiter),
+	}
+	return func(chain http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			reservation := rl.getLimiter(remoteIP(req), time.Now()).Reserve()
+			delay := reservation.Delay()
+			if !reservation.OK() || delay > 0 {
+				reservation.Cancel()
+				retryAfter := int(math.Ceil(delay.Seconds()))
+				if !reservation.OK() || retryAfter < 1 {
+					retryAfter = 1
+				}
+				ctx := req.Context()
+				log.L(ctx).Warnf("Rate limit exceeded for %s %s from %s", req.Method, req.URL.Path, req.RemoteAddr)
+				res.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				WriteError(res, http.StatusTooManyRequests, i18n.NewError(ctx, i18n.MsgRateLimitExceeded, retryAfter))
+				return
+			}
+			chain.ServeHTTP(res, req)
+		})
+	}
+}
+
+func (rl *ipRateLimiter) getLimiter(ip string, now time.Time) *rate.Limiter {
+	rl.mux.Lock()
+	defer rl.mux.Unlock()
+
+	// Evict idle IPs as part of normal processing, rather than with a separate routine
+	if rl.evictAfter > 0 && now.Sub(rl.lastEvicted) > rl.evictAfter {
+		for k, l := range rl.ips {
+			if now.Sub(l.lastSeen) > rl.evictAfter {
+				delete(rl.ips, k)
+			}
+		}
+		rl.lastEvicted = now
+	}
+
+	l, ok := rl.ips[ip]
+	if !ok {
+		l = &ipLimiter{limiter: rate.NewLimiter(rl.limit, rl.burst)}
+		rl.ips[ip] = l
+	}
+	l.lastSeen = now
+	return l.limiter
+}
+
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
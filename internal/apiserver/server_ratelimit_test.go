@@ -0,0 +1,120 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimitedHandler(requestsPerSecond float64, burst int) http.Handler {
+	return RateLimitMiddleware(requestsPerSecond, burst)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}))
+}
+
+func testRateLimitRequest(handler http.Handler, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+	req.RemoteAddr = remoteAddr
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	config.Reset()
+	assert.Nil(t, wrapRateLimitIfEnabled(context.Background(), nil))
+}
+
+func TestRateLimitEnabled(t *testing.T) {
+	config.Reset()
+	config.Set(config.APIRateLimitEnabled, true)
+	config.Set(config.APIRateLimitRequestsPerSecond, 0.001)
+	config.Set(config.APIRateLimitBurst, 1)
+	handler := wrapRateLimitIfEnabled(context.Background(), http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusNoContent)
+	}))
+	assert.Equal(t, http.StatusNoContent, testRateLimitRequest(handler, "10.0.0.1:12345").Code)
+	assert.Equal(t, http.StatusTooManyRequests, testRateLimitRequest(handler, "10.0.0.1:12345").Code)
+}
+
+func TestRateLimitAllowed(t *testing.T) {
+	config.Reset()
+	handler := newTestRateLimitedHandler(1000, 5)
+	for i := 0; i < 5; i++ {
+		res := testRateLimitRequest(handler, "10.0.0.1:12345")
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Empty(t, res.Header().Get("Retry-After"))
+	}
+}
+
+func TestRateLimitExceeded(t *testing.T) {
+	config.Reset()
+	handler := newTestRateLimitedHandler(0.1, 2)
+
+	assert.Equal(t, http.StatusOK, testRateLimitRequest(handler, "10.0.0.1:12345").Code)
+	assert.Equal(t, http.StatusOK, testRateLimitRequest(handler, "10.0.0.1:23456").Code)
+
+	res := testRateLimitRequest(handler, "10.0.0.1:34567")
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.Equal(t, "10", res.Header().Get("Retry-After"))
+	assert.Regexp(t, "FF10289", res.Body.String())
+
+	// A different IP has its own limit
+	assert.Equal(t, http.StatusOK, testRateLimitRequest(handler, "10.0.0.2:12345").Code)
+}
+
+func TestRateLimitZeroBurst(t *testing.T) {
+	config.Reset()
+	handler := newTestRateLimitedHandler(1, 0)
+	res := testRateLimitRequest(handler, "10.0.0.1:12345")
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.Equal(t, "1", res.Header().Get("Retry-After"))
+}
+
+func TestRateLimitNoPort(t *testing.T) {
+	config.Reset()
+	handler := newTestRateLimitedHandler(0.1, 1)
+	assert.Equal(t, http.StatusOK, testRateLimitRequest(handler, "pipe").Code)
+	assert.Equal(t, http.StatusTooManyRequests, testRateLimitRequest(handler, "pipe").Code)
+}
+
+func TestRateLimitEvictIdleIPs(t *testing.T) {
+	rl := &ipRateLimiter{
+		limit:       1,
+		burst:       1,
+		evictAfter:  10 * time.Minute,
+		lastEvicted: time.Now(),
+		ips:         make(map[string]*ipLimiter),
+	}
+	start := time.Now()
+	l1 := rl.getLimiter("10.0.0.1", start)
+	rl.getLimiter("10.0.0.2", start.Add(8*time.Minute))
+	assert.Len(t, rl.ips, 2)
+
+	// Only the IP idle for longer than the eviction time is removed
+	rl.getLimiter("10.0.0.3", start.Add(11*time.Minute))
+	assert.Len(t, rl.ips, 2)
+	assert.NotContains(t, rl.ips, "10.0.0.1")
+	assert.NotSame(t, l1, rl.getLimiter("10.0.0.1", start.Add(12*time.Minute)))
+}
@@ -0,0 +1,41 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/internal/restclient"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// wrapRequestID ensures every request has an X-Request-ID, using the one supplied by the
+// caller if there is one. The ID is added to the context for logging, and propagation on
+// any outbound REST calls made while processing the request, and is returned on the response.
+func wrapRequestID(chain http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requestID := req.Header.Get(restclient.HeaderRequestID)
+		if requestID == "" {
+			requestID = fftypes.NewUUID().String()
+		}
+		ctx := restclient.WithRequestID(req.Context(), requestID)
+		ctx = log.WithLogField(ctx, "requestId", requestID)
+		res.Header().Set(restclient.HeaderRequestID, requestID)
+		chain.ServeHTTP(res, req.WithContext(ctx))
+	})
+}
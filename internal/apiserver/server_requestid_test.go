@@ -0,0 +1,53 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/restclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDGenerated(t *testing.T) {
+	var ctxRequestID string
+	handler := wrapRequestID(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ctxRequestID = restclient.GetRequestID(req.Context())
+	}))
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Len(t, ctxRequestID, 36)
+	assert.Equal(t, ctxRequestID, res.Header().Get(restclient.HeaderRequestID))
+}
+
+func TestRequestIDFromHeader(t *testing.T) {
+	var ctxRequestID string
+	handler := wrapRequestID(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ctxRequestID = restclient.GetRequestID(req.Context())
+	}))
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	req.Header.Set(restclient.HeaderRequestID, "req12345")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, "req12345", ctxRequestID)
+	assert.Equal(t, "req12345", res.Header().Get(restclient.HeaderRequestID))
+}
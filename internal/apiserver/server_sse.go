@@ -0,0 +1,122 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// sseHandler streams the events of a namespace using server-sent events, for clients that cannot use
+// a WebSocket. Each event is sent as JSON, with the event sequence as the SSE id. A reconnecting client
+// sends that back in a Last-Event-ID header, and is replayed every event after it. New clients start
+// from the newest event.
+//
+// The HTTP write timeout does not apply to the stream, which ends when the client disconnects or
+// the server shuts down.
+func (as *apiServer) sseHandler(ctx context.Context, o orchestrator.Orchestrator) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		reqCtx := log.WithLogField(req.Context(), "httpreq", fftypes.ShortID())
+		l := log.L(reqCtx)
+		l.Infof("--> %s %s", req.Method, req.URL.Path)
+		startTime := time.Now()
+		status, err := as.streamEvents(ctx, reqCtx, o, res, req)
+		durationMS := float64(time.Since(startTime)) / float64(time.Millisecond)
+		if err != nil {
+			l.Infof("<-- %s %s [%d] (%.2fms): %s", req.Method, req.URL.Path, status, durationMS, err)
+			WriteError(res, status, err)
+		} else {
+			l.Infof("<-- %s %s [%d] (%.2fms)", req.Method, req.URL.Path, status, durationMS)
+		}
+	}
+}
+
+func (as *apiServer) streamEvents(serverCtx, ctx context.Context, o orchestrator.Orchestrator, res http.ResponseWriter, req *http.Request) (int, error) {
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		return 500, i18n.NewError(ctx, i18n.MsgSSENotSupported)
+	}
+	ns := mux.Vars(req)["ns"]
+
+	var lastSeq int64
+	if lastEventID := req.Header.Get("Last-Event-ID"); lastEventID != "" {
+		var err error
+		if lastSeq, err = strconv.ParseInt(lastEventID, 10, 64); err != nil {
+			return 400, i18n.NewError(ctx, i18n.MsgInvalidLastEventID, lastEventID)
+		}
+	} else {
+		fb := database.EventQueryFactory.NewFilter(ctx)
+		filter := fb.And()
+		filter.Sort("sequence").Descending().Limit(1)
+		latest, _, err := o.GetEvents(ctx, ns, filter)
+		if err != nil {
+			return 500, err
+		}
+		if len(latest) > 0 {
+			lastSeq = latest[0].Sequence
+		}
+	}
+
+	clearWriteDeadline(ctx)
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		fb := database.EventQueryFactory.NewFilter(ctx)
+		filter := fb.And(fb.Gt("sequence", lastSeq))
+		filter.Sort("sequence").Ascending().Limit(as.defaultFilterLimit)
+		events, _, err := o.GetEvents(ctx, ns, filter)
+		if err != nil {
+			// The headers are sent, so all we can do is end the stream. The client reconnects from the last event it received
+			log.L(ctx).Errorf("Event stream failed: %s", err)
+			return 200, nil
+		}
+		for _, event := range events {
+			b, _ := json.Marshal(event)
+			if _, err := fmt.Fprintf(res, "id: %d\ndata: %s\n\n", event.Sequence, b); err != nil {
+				return 200, nil
+			}
+			lastSeq = event.Sequence
+		}
+		flusher.Flush()
+
+		// Carry straight on if we have not caught up
+		if len(events) > 0 && uint64(len(events)) >= as.defaultFilterLimit {
+			continue
+		}
+		select {
+		case <-time.After(as.ssePollInterval):
+		case <-ctx.Done():
+			return 200, nil
+		case <-serverCtx.Done():
+			return 200, nil
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type noFlushResponseWriter struct {
+	http.ResponseWriter
+}
+
+type failingSSEResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (fw *failingSSEResponseWriter) Write(b []byte) (int, error) {
+	return 0, fmt.Errorf("pop")
+}
+
+func newTestSSEServer() (*orchestratormocks.Orchestrator, http.Handler, *apiServer) {
+	mor, as := newTestServer()
+	as.defaultFilterLimit = 2
+	as.ssePollInterval = time.Millisecond
+	return mor, as.createMuxRouter(context.Background(), mor), as
+}
+
+func matchEventsAfter(seq int64) interface{} {
+	return mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), fmt.Sprintf("sequence > %d", seq))
+	})
+}
+
+func TestSSEReplayFromLastEventID(t *testing.T) {
+	o, r, _ := newTestSSEServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ev := func(seq int64) *fftypes.Event {
+		return &fftypes.Event{ID: fftypes.MustParseUUID(fmt.Sprintf("7a9e6d9a-0000-0000-0000-%012d", seq)), Type: fftypes.EventTypeMessageConfirmed, Namespace: "ns1", Sequence: seq}
+	}
+	o.On("GetEvents", mock.Anything, "ns1", matchEventsAfter(10)).Return([]*fftypes.Event{ev(11), ev(12)}, nil, nil).Once()
+	o.On("GetEvents", mock.Anything, "ns1", matchEventsAfter(12)).Return([]*fftypes.Event{ev(13)}, nil, nil).Once()
+	o.On("GetEvents", mock.Anything, "ns1", matchEventsAfter(13)).Return([]*fftypes.Event{}, nil, nil).
+		Run(func(args mock.Arguments) { cancel() })
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil).WithContext(ctx)
+	req.Header.Set("Last-Event-ID", "10")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "text/event-stream", res.Header().Get("Content-Type"))
+	assert.True(t, res.Flushed)
+	frames := strings.Split(strings.TrimSuffix(res.Body.String(), "\n\n"), "\n\n")
+	assert.Len(t, frames, 3)
+	assert.Equal(t, `id: 11
+data: {"id":"7a9e6d9a-0000-0000-0000-000000000011","sequence":11,"type":"message_confirmed","namespace":"ns1","reference":null,"created":null}`, frames[0])
+	assert.True(t, strings.HasPrefix(frames[2], "id: 13\ndata: {"))
+	o.AssertExpectations(t)
+}
+
+func TestSSEStartFromLatest(t *testing.T) {
+	o, r, _ := newTestSSEServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o.On("GetEvents", mock.Anything, "ns1", mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return fi.Limit == 1 && fi.Sort[0].Descending
+	})).Return([]*fftypes.Event{{Sequence: 5}}, nil, nil).Once()
+	o.On("GetEvents", mock.Anything, "ns1", matchEventsAfter(5)).Return([]*fftypes.Event{}, nil, nil).
+		Run(func(args mock.Arguments) { cancel() })
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil).WithContext(ctx)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Empty(t, res.Body.String())
+	o.AssertExpectations(t)
+}
+
+func TestSSEStartNoEvents(t *testing.T) {
+	o, r, _ := newTestSSEServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.Event{}, nil, nil).Once()
+	o.On("GetEvents", mock.Anything, "ns1", matchEventsAfter(0)).Return([]*fftypes.Event{}, nil, nil).
+		Run(func(args mock.Arguments) { cancel() })
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil).WithContext(ctx)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Code)
+	o.AssertExpectations(t)
+}
+
+func TestSSEServerShutdown(t *testing.T) {
+	o, as := newTestServer()
+	as.ssePollInterval = time.Minute
+	serverCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	o.On("GetEvents", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Event{}, nil, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	res := httptest.NewRecorder()
+	as.sseHandler(serverCtx, o).ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Code)
+}
+
+func TestSSEBadLastEventID(t *testing.T) {
+	o, r, _ := newTestSSEServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Code)
+	assert.Regexp(t, "FF10342.*abc", res.Body.String())
+	o.AssertNotCalled(t, "GetEvents", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSSELatestEventFail(t *testing.T) {
+	o, r, _ := newTestSSEServer()
+	o.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Code)
+	assert.Regexp(t, "pop", res.Body.String())
+}
+
+func TestSSEStreamFail(t *testing.T) {
+	o, r, _ := newTestSSEServer()
+	o.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	// The stream had started, so it simply ends for the client to reconnect
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "text/event-stream", res.Header().Get("Content-Type"))
+	assert.Empty(t, res.Body.String())
+}
+
+func TestSSEWriteFail(t *testing.T) {
+	o, _, as := newTestSSEServer()
+	o.On("GetEvents", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Event{{Sequence: 2}}, nil, nil).Once()
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	res := &failingSSEResponseWriter{httptest.NewRecorder()}
+	as.sseHandler(context.Background(), o).ServeHTTP(res, req)
+
+	o.AssertExpectations(t)
+}
+
+func TestSSENotFlusher(t *testing.T) {
+	o, as := newTestServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events/sse", nil)
+	rec := httptest.NewRecorder()
+	as.sseHandler(context.Background(), o).ServeHTTP(&noFlushResponseWriter{rec}, req)
+
+	assert.Equal(t, 500, rec.Code)
+	assert.Regexp(t, "FF10341", rec.Body.String())
+}
@@ -17,7 +17,6 @@
 package apiserver
 
 import (
-	"encoding/json"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -25,7 +24,6 @@ import (
 
 	"github.com/hyperledger/firefly/internal/i18n"
 	"github.com/hyperledger/firefly/internal/log"
-	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
 type staticHandler struct {
@@ -48,7 +46,7 @@ func (h staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	path, err := filepath.Rel(h.urlPrefix, r.URL.Path)
 	if err != nil {
 		// if we failed to get the path respond with a 404
-		http.Error(w, err.Error(), http.StatusNotFound)
+		WriteError(w, http.StatusNotFound, i18n.WrapError(r.Context(), err, i18n.Msg404NotFound))
 		return
 	}
 
@@ -65,10 +63,7 @@ func (h staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		// if we got an error (that wasn't that the file doesn't exist) stating the
 		// file, return a 500 internal server error and stop
 		log.L(r.Context()).Errorf("Failed to serve file: %s", err)
-		w.WriteHeader(500)
-		_ = json.NewEncoder(w).Encode(&fftypes.RESTError{
-			Error: i18n.ExpandWithCode(r.Context(), i18n.MsgAPIServerStaticFail),
-		})
+		WriteError(w, 500, i18n.NewError(r.Context(), i18n.MsgAPIServerStaticFail))
 		return
 	}
 
@@ -17,6 +17,7 @@
 package apiserver
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -47,6 +48,10 @@ func Test404UnRelablePath(t *testing.T) {
 	req := httptest.NewRequest("GET", "/test", nil)
 	handler(res, req)
 	assert.Equal(t, 404, res.Result().StatusCode)
+	var apiErr APIError
+	err := json.NewDecoder(res.Body).Decode(&apiErr)
+	assert.NoError(t, err)
+	assert.Equal(t, "FF10109", apiErr.ErrorCode)
 }
 
 func TestServeDefault(t *testing.T) {
@@ -38,11 +38,19 @@ type Manager interface {
 	CreateTokenPoolWithID(ctx context.Context, ns string, id *fftypes.UUID, typeName string, pool *fftypes.TokenPool, waitConfirm bool) (*fftypes.TokenPool, error)
 	GetTokenPools(ctx context.Context, ns, typeName string, filter database.AndFilter) ([]*fftypes.TokenPool, *database.FilterResult, error)
 	GetTokenPool(ctx context.Context, ns, typeName, name string) (*fftypes.TokenPool, error)
+	GetTokenPoolBySymbol(ctx context.Context, ns, symbol string) (*fftypes.TokenPool, error)
 	GetTokenAccounts(ctx context.Context, ns, typeName, name string, filter database.AndFilter) ([]*fftypes.TokenAccount, *database.FilterResult, error)
+	GetTokenBalances(ctx context.Context, ns, poolID string, filter database.AndFilter) ([]*fftypes.TokenBalance, *database.FilterResult, error)
+	BatchMint(ctx context.Context, ns, typeName, poolName string, recipients []tokens.TokenRecipient) ([]*fftypes.Operation, error)
+	FreezeTokenPool(ctx context.Context, ns, typeName, name string) (*fftypes.TokenPool, error)
+	UnfreezeTokenPool(ctx context.Context, ns, typeName, name string) (*fftypes.TokenPool, error)
 	ValidateTokenPoolTx(ctx context.Context, pool *fftypes.TokenPool, protocolTxID string) error
+	ReplayTokenPoolEvents(ctx context.Context, typeName string, from, to *fftypes.FFTime) error
 
 	// Bound token callbacks
 	TokenPoolCreated(tk tokens.Plugin, tokenType fftypes.TokenType, tx *fftypes.UUID, protocolID, signingIdentity, protocolTxID string, additionalInfo fftypes.JSONObject) error
+	TokensTransferred(tk tokens.Plugin, poolProtocolID, signingKey, from, to string, amount *fftypes.BigInt, protocolTxID string, additionalInfo fftypes.JSONObject) error
+	TokenPoolStatusChanged(tk tokens.Plugin, poolProtocolID string, status fftypes.TokenPoolStatus, protocolTxID string, additionalInfo fftypes.JSONObject) error
 
 	Start() error
 	WaitStop()
@@ -79,9 +87,20 @@ func NewAssetManager(ctx context.Context, di database.Plugin, ii identity.Plugin
 		},
 		txhelper: txcommon.NewTransactionHelper(di),
 	}
+	router := tokenRouter(ti)
+	for _, plugin := range ti {
+		plugin.SetRouter(router)
+	}
 	return am, nil
 }
 
+// tokenRouter allows each tokens plugin to look up the others, to dispatch events it receives on their behalf
+type tokenRouter map[string]tokens.Plugin
+
+func (r tokenRouter) Lookup(name string) tokens.Plugin {
+	return r[name]
+}
+
 func (am *assetManager) selectTokenPlugin(ctx context.Context, name string) (tokens.Plugin, error) {
 	for pluginName, plugin := range am.tokens {
 		if pluginName == name {
@@ -96,6 +115,8 @@ func addTokenPoolCreateInputs(op *fftypes.Operation, pool *fftypes.TokenPool) {
 		"id":        pool.ID.String(),
 		"namespace": pool.Namespace,
 		"name":      pool.Name,
+		"symbol":    pool.Symbol,
+		"connector": pool.Connector,
 		"config":    pool.Config,
 	}
 }
@@ -111,6 +132,8 @@ func retrieveTokenPoolCreateInputs(ctx context.Context, op *fftypes.Operation, p
 	if pool.Namespace == "" || pool.Name == "" {
 		return fmt.Errorf("namespace or name missing from inputs")
 	}
+	pool.Symbol = input.GetString("symbol")
+	pool.Connector = input.GetString("connector")
 	pool.Config = input.GetObject("config")
 	return nil
 }
@@ -163,6 +186,8 @@ func (am *assetManager) CreateTokenPoolWithID(ctx context.Context, ns string, id
 
 	pool.ID = id
 	pool.Namespace = ns
+	pool.Connector = typeName
+	pool.Status = fftypes.TokenPoolStatusActive
 	pool.TX = fftypes.TransactionRef{
 		ID:   tx.ID,
 		Type: tx.Subject.Type,
@@ -175,7 +200,8 @@ func (am *assetManager) CreateTokenPoolWithID(ctx context.Context, ns string, id
 		"",
 		fftypes.OpTypeTokensCreatePool,
 		fftypes.OpStatusPending,
-		author.Identifier)
+		author.Identifier,
+		fftypes.GetCreatedBy(ctx))
 	addTokenPoolCreateInputs(op, pool)
 	err = am.database.UpsertOperation(ctx, op, false)
 	if err != nil {
@@ -212,6 +238,20 @@ func (am *assetManager) GetTokenPool(ctx context.Context, ns, typeName, name str
 	return am.database.GetTokenPool(ctx, ns, name)
 }
 
+func (am *assetManager) GetTokenPoolBySymbol(ctx context.Context, ns, symbol string) (*fftypes.TokenPool, error) {
+	if err := fftypes.ValidateFFNameField(ctx, ns, "namespace"); err != nil {
+		return nil, err
+	}
+	pool, err := am.database.GetTokenPoolBySymbol(ctx, ns, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	return pool, nil
+}
+
 func (am *assetManager) GetTokenAccounts(ctx context.Context, ns, typeName, name string, filter database.AndFilter) ([]*fftypes.TokenAccount, *database.FilterResult, error) {
 	pool, err := am.GetTokenPool(ctx, ns, typeName, name)
 	if err != nil {
@@ -220,6 +260,24 @@ func (am *assetManager) GetTokenAccounts(ctx context.Context, ns, typeName, name
 	return am.database.GetTokenAccounts(ctx, filter.Condition(filter.Builder().Eq("protocolid", pool.ProtocolID)))
 }
 
+func (am *assetManager) GetTokenBalances(ctx context.Context, ns, poolID string, filter database.AndFilter) ([]*fftypes.TokenBalance, *database.FilterResult, error) {
+	if err := fftypes.ValidateFFNameField(ctx, ns, "namespace"); err != nil {
+		return nil, nil, err
+	}
+	id, err := fftypes.ParseUUID(ctx, poolID)
+	if err != nil {
+		return nil, nil, err
+	}
+	pool, err := am.database.GetTokenPoolByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pool == nil || pool.Namespace != ns {
+		return nil, nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	return am.database.GetTokenBalances(ctx, filter.Condition(filter.Builder().Eq("pool", pool.ID)))
+}
+
 func (am *assetManager) ValidateTokenPoolTx(ctx context.Context, pool *fftypes.TokenPool, protocolTxID string) error {
 	// TODO: validate that the given token pool was created with the given protocolTxId
 	return nil
@@ -44,11 +44,12 @@ func newTestAssets(t *testing.T) (*assetManager, func()) {
 	mbm := &broadcastmocks.Manager{}
 	mti := &tokenmocks.Plugin{}
 	mti.On("Name").Return("ut_tokens").Maybe()
+	mti.On("SetRouter", mock.Anything).Maybe()
 	defaultIdentity := &fftypes.Identity{Identifier: "UTNodeID", OnChain: "0x12345"}
 	mii.On("Resolve", mock.Anything, "UTNodeID").Return(defaultIdentity, nil).Maybe()
 	ctx, cancel := context.WithCancel(context.Background())
 	a, err := NewAssetManager(ctx, mdi, mii, mdm, msa, mbm, map[string]tokens.Plugin{"magic-tokens": mti})
-	rag := mdi.On("RunAsGroup", ctx, mock.Anything).Maybe()
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything).Maybe()
 	rag.RunFn = func(a mock.Arguments) {
 		rag.ReturnArguments = mock.Arguments{a[1].(func(context.Context) error)(a[0].(context.Context))}
 	}
@@ -61,6 +62,21 @@ func TestInitFail(t *testing.T) {
 	assert.Regexp(t, "FF10128", err)
 }
 
+func TestTokenRouter(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	var router tokens.Router
+	for _, call := range mti.Calls {
+		if call.Method == "SetRouter" {
+			router = call.Arguments[0].(tokens.Router)
+		}
+	}
+	assert.Equal(t, mti, router.Lookup("magic-tokens"))
+	assert.Nil(t, router.Lookup("unknown"))
+}
+
 func TestStartStop(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -163,10 +179,35 @@ func TestCreateTokenPoolSuccess(t *testing.T) {
 	mdi.On("UpsertTransaction", context.Background(), mock.MatchedBy(func(tx *fftypes.Transaction) bool {
 		return tx.Subject.Type == fftypes.TransactionTypeTokenPool
 	}), false).Return(nil)
-	mdi.On("UpsertOperation", mock.Anything, mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", mock.Anything, mock.MatchedBy(func(op *fftypes.Operation) bool {
+		return op.Input.GetString("symbol") == "COIN" && op.Input.GetString("connector") == "magic-tokens"
+	}), false).Return(nil)
 
-	_, err := am.CreateTokenPool(context.Background(), "ns1", "magic-tokens", &fftypes.TokenPool{}, false)
+	pool, err := am.CreateTokenPool(context.Background(), "ns1", "magic-tokens", &fftypes.TokenPool{Symbol: "COIN"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "magic-tokens", pool.Connector)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCreateTokenPoolCreatedBy(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	ctx := fftypes.WithCreatedBy(context.Background(), "org1")
+	mdi := am.database.(*databasemocks.Plugin)
+	mdm := am.data.(*datamocks.Manager)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdm.On("VerifyNamespaceExists", ctx, "ns1").Return(nil)
+	mti.On("CreateTokenPool", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("UpsertTransaction", ctx, mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", ctx, mock.MatchedBy(func(op *fftypes.Operation) bool {
+		return op.CreatedBy == "org1"
+	}), false).Return(nil)
+
+	_, err := am.CreateTokenPool(ctx, "ns1", "magic-tokens", &fftypes.TokenPool{}, false)
 	assert.NoError(t, err)
+	mdi.AssertExpectations(t)
 }
 
 func TestCreateTokenPoolConfirm(t *testing.T) {
@@ -232,6 +273,46 @@ func TestGetTokenPoolBadName(t *testing.T) {
 	assert.Regexp(t, "FF10131", err)
 }
 
+func TestGetTokenPoolBySymbol(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), Symbol: "COIN"}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolBySymbol", context.Background(), "ns1", "COIN").Return(pool, nil)
+	result, err := am.GetTokenPoolBySymbol(context.Background(), "ns1", "COIN")
+	assert.NoError(t, err)
+	assert.Equal(t, pool, result)
+}
+
+func TestGetTokenPoolBySymbolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolBySymbol", context.Background(), "ns1", "COIN").Return(nil, nil)
+	_, err := am.GetTokenPoolBySymbol(context.Background(), "ns1", "COIN")
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetTokenPoolBySymbolFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolBySymbol", context.Background(), "ns1", "COIN").Return(nil, fmt.Errorf("pop"))
+	_, err := am.GetTokenPoolBySymbol(context.Background(), "ns1", "COIN")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenPoolBySymbolBadNamespace(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.GetTokenPoolBySymbol(context.Background(), "", "COIN")
+	assert.Regexp(t, "FF10131", err)
+}
+
 func TestGetTokenPools(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -292,6 +373,72 @@ func TestGetTokenAccountsBadPool(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestGetTokenBalances(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &fftypes.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenBalanceQueryFactory.NewFilter(context.Background())
+	f := fb.And(fb.Eq("address", "0x0"))
+	mdi.On("GetTokenPoolByID", context.Background(), pool.ID).Return(pool, nil)
+	mdi.On("GetTokenBalances", context.Background(), mock.MatchedBy(func(filter database.AndFilter) bool {
+		info, _ := filter.Finalize()
+		return info.String() == "( address == '0x0' ) && ( pool == '"+pool.ID.String()+"' )"
+	})).Return([]*fftypes.TokenBalance{}, nil, nil)
+	_, _, err := am.GetTokenBalances(context.Background(), "ns1", pool.ID.String(), f)
+	assert.NoError(t, err)
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenBalancesBadNamespace(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	fb := database.TokenBalanceQueryFactory.NewFilter(context.Background())
+	_, _, err := am.GetTokenBalances(context.Background(), "", fftypes.NewUUID().String(), fb.And())
+	assert.Regexp(t, "FF10131", err)
+}
+
+func TestGetTokenBalancesBadPoolID(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	fb := database.TokenBalanceQueryFactory.NewFilter(context.Background())
+	_, _, err := am.GetTokenBalances(context.Background(), "ns1", "bad", fb.And())
+	assert.Regexp(t, "FF10142", err)
+}
+
+func TestGetTokenBalancesGetPoolFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	poolID := fftypes.NewUUID()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), poolID).Return(nil, fmt.Errorf("pop"))
+	fb := database.TokenBalanceQueryFactory.NewFilter(context.Background())
+	_, _, err := am.GetTokenBalances(context.Background(), "ns1", poolID.String(), fb.And())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenBalancesPoolWrongNamespace(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &fftypes.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns2",
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), pool.ID).Return(pool, nil)
+	fb := database.TokenBalanceQueryFactory.NewFilter(context.Background())
+	_, _, err := am.GetTokenBalances(context.Background(), "ns1", pool.ID.String(), fb.And())
+	assert.Regexp(t, "FF10109", err)
+}
+
 func TestValidateTokenPoolTx(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
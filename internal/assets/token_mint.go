@@ -0,0 +1,135 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/tokens"
+)
+
+func addTokenMintInputs(op *fftypes.Operation, pool *fftypes.TokenPool, recipient *tokens.TokenRecipient) {
+	op.Input = fftypes.JSONObject{
+		"pool":    pool.ID.String(),
+		"address": recipient.Address,
+		"amount":  recipient.Amount,
+	}
+}
+
+// BatchMint mints tokens to each of the recipients under a single transaction, with an operation
+// recorded per recipient. Where the plugin supports it, a single batch request is submitted
+// (correlated by the transaction ID), otherwise each recipient is minted individually.
+func (am *assetManager) BatchMint(ctx context.Context, ns, typeName, poolName string, recipients []tokens.TokenRecipient) ([]*fftypes.Operation, error) {
+	if len(recipients) == 0 {
+		return nil, i18n.NewError(ctx, i18n.MsgNoMintRecipients)
+	}
+
+	plugin, err := am.selectTokenPlugin(ctx, typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := am.GetTokenPool(ctx, ns, typeName, poolName)
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	if pool.Status == fftypes.TokenPoolStatusFrozen {
+		return nil, i18n.NewError(ctx, i18n.MsgTokenPoolFrozen, pool.Name)
+	}
+
+	author, err := am.identity.Resolve(ctx, config.GetString(config.OrgIdentity))
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgAuthorInvalid)
+	}
+
+	tx := &fftypes.Transaction{
+		ID: fftypes.NewUUID(),
+		Subject: fftypes.TransactionSubject{
+			Namespace: ns,
+			Type:      fftypes.TransactionTypeTokenMint,
+			Signer:    author.OnChain, // The transaction records on the on-chain identity
+			Reference: pool.ID,
+		},
+		Created: fftypes.Now(),
+		Status:  fftypes.OpStatusPending,
+	}
+	tx.Hash = tx.Subject.Hash()
+	ops := make([]*fftypes.Operation, len(recipients))
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if err := am.database.UpsertTransaction(ctx, tx, false /* should be new, or idempotent replay */); err != nil {
+			return err
+		}
+		for i := range recipients {
+			op := fftypes.NewTXOperation(
+				plugin,
+				ns,
+				tx.ID,
+				"",
+				fftypes.OpTypeTokensMint,
+				fftypes.OpStatusPending,
+				author.Identifier,
+				fftypes.GetCreatedBy(ctx))
+			addTokenMintInputs(op, pool, &recipients[i])
+			if err := am.database.UpsertOperation(ctx, op, false); err != nil {
+				return err
+			}
+			ops[i] = op
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if plugin.Capabilities().SupportsBatchMint {
+		if err = plugin.BatchMint(ctx, tx.ID, author, pool, recipients); err != nil {
+			// The batch was never submitted - so none of the mints will receive an update
+			am.failUnsentOperations(ctx, ops, err)
+			return ops, err
+		}
+		return ops, nil
+	}
+	for i, op := range ops {
+		if err = plugin.MintTokens(ctx, op.ID, author, pool, recipients[i]); err != nil {
+			// This mint, and those after it, were never submitted - so will never receive an update
+			am.failUnsentOperations(ctx, ops[i:], err)
+			return ops, err
+		}
+	}
+	return ops, nil
+}
+
+func (am *assetManager) failUnsentOperations(ctx context.Context, ops []*fftypes.Operation, submitErr error) {
+	update := database.OperationQueryFactory.NewUpdate(ctx).
+		Set("status", fftypes.OpStatusFailed).
+		Set("error", submitErr.Error())
+	for _, op := range ops {
+		op.Status = fftypes.OpStatusFailed
+		op.Error = submitErr.Error()
+		if err := am.database.UpdateOperation(ctx, op.ID, update); err != nil {
+			log.L(ctx).Errorf("Failed to record failure of operation %s: %s", op.ID, err)
+		}
+	}
+}
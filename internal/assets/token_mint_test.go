@@ -0,0 +1,261 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testRecipients = []tokens.TokenRecipient{
+	{Address: "0x111", Amount: 10},
+	{Address: "0x222", Amount: 20},
+}
+
+func TestBatchMintBatched(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), ProtocolID: "F1"}
+	var txID *fftypes.UUID
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.MatchedBy(func(tx *fftypes.Transaction) bool {
+		txID = tx.ID
+		return tx.Subject.Type == fftypes.TransactionTypeTokenMint && *tx.Subject.Reference == *pool.ID
+	}), false).Return(nil)
+	mdi.On("UpsertOperation", context.Background(), mock.MatchedBy(func(op *fftypes.Operation) bool {
+		return op.Type == fftypes.OpTypeTokensMint
+	}), false).Return(nil).Twice()
+	mti.On("Capabilities").Return(&tokens.Capabilities{SupportsBatchMint: true})
+	mti.On("BatchMint", context.Background(), mock.MatchedBy(func(id *fftypes.UUID) bool {
+		return *id == *txID
+	}), mock.Anything, pool, testRecipients).Return(nil)
+
+	ops, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.NoError(t, err)
+	assert.Len(t, ops, 2)
+	assert.Equal(t, "0x111", ops[0].Input.GetString("address"))
+	assert.Equal(t, "0x222", ops[1].Input.GetString("address"))
+	assert.Equal(t, *txID, *ops[0].Transaction)
+	assert.Equal(t, *txID, *ops[1].Transaction)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestBatchMintBatchedFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), ProtocolID: "F1"}
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", context.Background(), mock.Anything, false).Return(nil).Twice()
+	mti.On("Capabilities").Return(&tokens.Capabilities{SupportsBatchMint: true})
+	mti.On("BatchMint", context.Background(), mock.Anything, mock.Anything, pool, testRecipients).Return(fmt.Errorf("pop"))
+	mdi.On("UpdateOperation", context.Background(), mock.Anything, mock.Anything).Return(nil).Twice()
+
+	ops, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.EqualError(t, err, "pop")
+	assert.Len(t, ops, 2)
+	for _, op := range ops {
+		assert.Equal(t, fftypes.OpStatusFailed, op.Status)
+		assert.Equal(t, "pop", op.Error)
+		mdi.AssertCalled(t, "UpdateOperation", context.Background(), op.ID, mock.Anything)
+	}
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestBatchMintIndividualFallback(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), ProtocolID: "F1"}
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", context.Background(), mock.Anything, false).Return(nil).Twice()
+	mti.On("Capabilities").Return(&tokens.Capabilities{})
+	mti.On("MintTokens", context.Background(), mock.Anything, mock.Anything, pool, testRecipients[0]).Return(nil).Once()
+	mti.On("MintTokens", context.Background(), mock.Anything, mock.Anything, pool, testRecipients[1]).Return(nil).Once()
+
+	ops, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.NoError(t, err)
+	assert.Len(t, ops, 2)
+	assert.NotEqual(t, *ops[0].ID, *ops[1].ID)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestBatchMintIndividualFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), ProtocolID: "F1"}
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", context.Background(), mock.Anything, false).Return(nil)
+	mti.On("Capabilities").Return(&tokens.Capabilities{})
+	mti.On("MintTokens", context.Background(), mock.Anything, mock.Anything, pool, testRecipients[0]).Return(nil)
+	mti.On("MintTokens", context.Background(), mock.Anything, mock.Anything, pool, testRecipients[1]).Return(fmt.Errorf("pop"))
+	mdi.On("UpdateOperation", context.Background(), mock.Anything, mock.Anything).Return(nil).Once()
+
+	ops, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.EqualError(t, err, "pop")
+	assert.Len(t, ops, 2)
+	assert.Equal(t, fftypes.OpStatusPending, ops[0].Status)
+	assert.Equal(t, fftypes.OpStatusFailed, ops[1].Status)
+	assert.Equal(t, "pop", ops[1].Error)
+	mdi.AssertCalled(t, "UpdateOperation", context.Background(), ops[1].ID, mock.Anything)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestBatchMintIndividualFailUpdateFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), ProtocolID: "F1"}
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", context.Background(), mock.Anything, false).Return(nil)
+	mti.On("Capabilities").Return(&tokens.Capabilities{})
+	mti.On("MintTokens", context.Background(), mock.Anything, mock.Anything, pool, testRecipients[0]).Return(fmt.Errorf("pop"))
+	mdi.On("UpdateOperation", context.Background(), mock.Anything, mock.Anything).Return(fmt.Errorf("pop2")).Twice()
+
+	ops, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, fftypes.OpStatusFailed, ops[0].Status)
+	assert.Equal(t, fftypes.OpStatusFailed, ops[1].Status)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestBatchMintNoRecipients(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", []tokens.TokenRecipient{})
+	assert.Regexp(t, "FF10294", err)
+}
+
+func TestBatchMintBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.BatchMint(context.Background(), "ns1", "bad", "pool1", testRecipients)
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestBatchMintPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+
+	_, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestBatchMintPoolFrozen(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&fftypes.TokenPool{
+		ID:     fftypes.NewUUID(),
+		Name:   "pool1",
+		Status: fftypes.TokenPoolStatusFrozen,
+	}, nil)
+
+	_, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.Regexp(t, "FF10304.*pool1", err)
+	mdi.AssertNotCalled(t, "UpsertTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBatchMintPoolLookupFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestBatchMintBadIdentity(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mii := am.identity.(*identitymocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&fftypes.TokenPool{}, nil)
+	mii.ExpectedCalls = nil
+	mii.On("Resolve", context.Background(), "UTNodeID").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestBatchMintUpsertTXFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&fftypes.TokenPool{ID: fftypes.NewUUID()}, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.Anything, false).Return(fmt.Errorf("pop"))
+
+	_, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestBatchMintUpsertOpFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&fftypes.TokenPool{ID: fftypes.NewUUID()}, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", context.Background(), mock.Anything, false).Return(fmt.Errorf("pop"))
+
+	_, err := am.BatchMint(context.Background(), "ns1", "magic-tokens", "pool1", testRecipients)
+	assert.EqualError(t, err, "pop")
+}
@@ -17,6 +17,9 @@
 package assets
 
 import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/i18n"
 	"github.com/hyperledger/firefly/internal/log"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
@@ -28,10 +31,24 @@ func (am *assetManager) TokenPoolCreated(tk tokens.Plugin, tokenType fftypes.Tok
 	fb := database.OperationQueryFactory.NewFilter(am.ctx)
 	filter := fb.And(
 		fb.Eq("tx", tx),
-		fb.Eq("type", fftypes.OpTypeTokensCreatePool),
 	)
 	operations, _, err := am.database.GetOperations(am.ctx, filter)
-	if err != nil || len(operations) == 0 {
+	if err != nil {
+		log.L(am.ctx).Debugf("Token pool transaction '%s' ignored, as operations could not be queried: %s", tx, err)
+		return nil
+	}
+	var createOp *fftypes.Operation
+	for _, op := range operations {
+		switch op.Type {
+		case fftypes.OpTypeTokensCreatePool:
+			createOp = op
+		case fftypes.OpTypeTokensAnnouncePool:
+			// The event has been seen before (such as during a replay from the connector)
+			log.L(am.ctx).Debugf("Token pool transaction '%s' ignored, as it has already been announced", tx)
+			return nil
+		}
+	}
+	if createOp == nil {
 		log.L(am.ctx).Debugf("Token pool transaction '%s' ignored, as it did not match an operation submitted by this node", tx)
 		return nil
 	}
@@ -44,9 +61,9 @@ func (am *assetManager) TokenPoolCreated(tk tokens.Plugin, tokenType fftypes.Tok
 		},
 		ProtocolTxID: protocolTxID,
 	}
-	err = retrieveTokenPoolCreateInputs(am.ctx, operations[0], &pool.TokenPool)
+	err = retrieveTokenPoolCreateInputs(am.ctx, createOp, &pool.TokenPool)
 	if err != nil {
-		log.L(am.ctx).Errorf("Error retrieving pool info from transaction '%s' (%s) - ignoring: %v", tx, err, operations[0].Input)
+		log.L(am.ctx).Errorf("Error retrieving pool info from transaction '%s' (%s) - ignoring: %v", tx, err, createOp.Input)
 		return nil
 	}
 
@@ -78,14 +95,12 @@ func (am *assetManager) TokenPoolCreated(tk tokens.Plugin, tokenType fftypes.Tok
 		"",
 		fftypes.OpTypeTokensAnnouncePool,
 		fftypes.OpStatusPending,
-		signingIdentity)
+		signingIdentity,
+		createOp.CreatedBy)
 
 	var valid bool
 	err = am.retry.Do(am.ctx, "persist token pool transaction", func(attempt int) (bool, error) {
 		valid, err = am.txhelper.PersistTransaction(am.ctx, transaction)
-		if valid && err == nil {
-			err = am.database.UpsertOperation(am.ctx, op, false)
-		}
 		return err != nil, err
 	})
 	if !valid || err != nil {
@@ -93,6 +108,27 @@ func (am *assetManager) TokenPoolCreated(tk tokens.Plugin, tokenType fftypes.Tok
 	}
 
 	// Announce the details of the new token pool
-	_, err = am.broadcast.BroadcastTokenPool(am.ctx, pool.Namespace, pool, false)
-	return err
+	if _, err = am.broadcast.BroadcastTokenPool(am.ctx, pool.Namespace, pool, false); err != nil {
+		return err
+	}
+
+	// The announce operation is only written once the broadcast has been sent, as it is what
+	// marks the event as handled - so an event replayed after a failed broadcast is announced again
+	return am.retry.Do(am.ctx, "persist token pool announce operation", func(attempt int) (bool, error) {
+		err := am.database.UpsertOperation(am.ctx, op, false)
+		return err != nil, err
+	})
+}
+
+// ReplayTokenPoolEvents asks a token connector to deliver its token pool events in the time range again,
+// to recover pools whose creation event was missed. Pools that are already announced are skipped.
+func (am *assetManager) ReplayTokenPoolEvents(ctx context.Context, typeName string, from, to *fftypes.FFTime) error {
+	plugin, err := am.selectTokenPlugin(ctx, typeName)
+	if err != nil {
+		return err
+	}
+	if from != nil && to != nil && to.Before(from) {
+		return i18n.NewError(ctx, i18n.MsgInvalidReplayRange, to, from)
+	}
+	return plugin.ReplayTokenPoolEvents(ctx, from, to)
 }
@@ -17,7 +17,10 @@
 package assets
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly/mocks/broadcastmocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
@@ -39,11 +42,14 @@ func TestTokenPoolCreatedSuccess(t *testing.T) {
 	txID := fftypes.NewUUID()
 	operations := []*fftypes.Operation{
 		{
-			ID: fftypes.NewUUID(),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.OpTypeTokensCreatePool,
 			Input: fftypes.JSONObject{
 				"id":        poolID.String(),
 				"namespace": "test-ns",
 				"name":      "my-pool",
+				"symbol":    "COIN",
+				"connector": "magic-tokens",
 			},
 		},
 	}
@@ -58,7 +64,8 @@ func TestTokenPoolCreatedSuccess(t *testing.T) {
 		return op.Type == fftypes.OpTypeTokensAnnouncePool
 	}), false).Return(nil)
 	mbm.On("BroadcastTokenPool", am.ctx, "test-ns", mock.MatchedBy(func(pool *fftypes.TokenPoolAnnouncement) bool {
-		return pool.Namespace == "test-ns" && pool.Name == "my-pool" && *pool.ID == *poolID
+		return pool.Namespace == "test-ns" && pool.Name == "my-pool" && *pool.ID == *poolID &&
+			pool.Symbol == "COIN" && pool.Connector == "magic-tokens"
 	}), false).Return(nil, nil)
 
 	info := fftypes.JSONObject{"some": "info"}
@@ -69,6 +76,77 @@ func TestTokenPoolCreatedSuccess(t *testing.T) {
 	mbm.AssertExpectations(t)
 }
 
+func TestTokenPoolCreatedBroadcastFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+	mbm := am.broadcast.(*broadcastmocks.Manager)
+
+	txID := fftypes.NewUUID()
+	operations := []*fftypes.Operation{
+		{
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.OpTypeTokensCreatePool,
+			Input: fftypes.JSONObject{
+				"id":        fftypes.NewUUID().String(),
+				"namespace": "test-ns",
+				"name":      "my-pool",
+			},
+		},
+	}
+
+	mti.On("Name").Return("mock-tokens")
+	mdi.On("GetOperations", am.ctx, mock.Anything).Return(operations, nil, nil)
+	mdi.On("GetTransactionByID", mock.Anything, txID).Return(nil, nil)
+	mdi.On("UpsertTransaction", am.ctx, mock.Anything, false).Return(nil)
+	mbm.On("BroadcastTokenPool", am.ctx, "test-ns", mock.Anything, false).Return(nil, fmt.Errorf("pop"))
+
+	err := am.TokenPoolCreated(mti, fftypes.TokenTypeFungible, txID, "123", "0x0", "tx1", fftypes.JSONObject{})
+	assert.EqualError(t, err, "pop")
+
+	// No announce operation is written, so a replay of the event will announce the pool again
+	mdi.AssertNotCalled(t, "UpsertOperation", mock.Anything, mock.Anything, mock.Anything)
+	mdi.AssertExpectations(t)
+	mbm.AssertExpectations(t)
+}
+
+func TestTokenPoolCreatedOpUpsertFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+	mbm := am.broadcast.(*broadcastmocks.Manager)
+
+	txID := fftypes.NewUUID()
+	operations := []*fftypes.Operation{
+		{
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.OpTypeTokensCreatePool,
+			Input: fftypes.JSONObject{
+				"id":        fftypes.NewUUID().String(),
+				"namespace": "test-ns",
+				"name":      "my-pool",
+			},
+		},
+	}
+
+	mti.On("Name").Return("mock-tokens")
+	mdi.On("GetOperations", am.ctx, mock.Anything).Return(operations, nil, nil)
+	mdi.On("GetTransactionByID", mock.Anything, txID).Return(nil, nil)
+	mdi.On("UpsertTransaction", am.ctx, mock.Anything, false).Return(nil)
+	mbm.On("BroadcastTokenPool", am.ctx, "test-ns", mock.Anything, false).Return(nil, nil)
+	mdi.On("UpsertOperation", am.ctx, mock.Anything, false).Return(fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	err := am.TokenPoolCreated(mti, fftypes.TokenTypeFungible, txID, "123", "0x0", "tx1", fftypes.JSONObject{})
+	assert.Regexp(t, "FF10158", err)
+
+	mdi.AssertExpectations(t)
+	mbm.AssertExpectations(t)
+}
+
 func TestTokenPoolCreatedOpNotFound(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -90,6 +168,54 @@ func TestTokenPoolCreatedOpNotFound(t *testing.T) {
 	mbm.AssertExpectations(t)
 }
 
+func TestTokenPoolCreatedOpQueryFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	txID := fftypes.NewUUID()
+	mdi.On("GetOperations", am.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := am.TokenPoolCreated(mti, fftypes.TokenTypeFungible, txID, "123", "0x0", "tx1", fftypes.JSONObject{})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestTokenPoolCreatedAlreadyAnnounced(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+	mbm := am.broadcast.(*broadcastmocks.Manager)
+
+	txID := fftypes.NewUUID()
+	operations := []*fftypes.Operation{
+		{
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.OpTypeTokensCreatePool,
+			Input: fftypes.JSONObject{
+				"id":        fftypes.NewUUID().String(),
+				"namespace": "test-ns",
+				"name":      "my-pool",
+			},
+		},
+		{
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.OpTypeTokensAnnouncePool,
+		},
+	}
+
+	mdi.On("GetOperations", am.ctx, mock.Anything).Return(operations, nil, nil)
+
+	err := am.TokenPoolCreated(mti, fftypes.TokenTypeFungible, txID, "123", "0x0", "tx1", fftypes.JSONObject{})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mbm.AssertNotCalled(t, "BroadcastTokenPool", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestTokenPoolMissingID(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -101,6 +227,7 @@ func TestTokenPoolMissingID(t *testing.T) {
 	operations := []*fftypes.Operation{
 		{
 			ID:    fftypes.NewUUID(),
+			Type:  fftypes.OpTypeTokensCreatePool,
 			Input: fftypes.JSONObject{},
 		},
 	}
@@ -127,7 +254,8 @@ func TestTokenPoolCreatedMissingNamespace(t *testing.T) {
 	txID := fftypes.NewUUID()
 	operations := []*fftypes.Operation{
 		{
-			ID: fftypes.NewUUID(),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.OpTypeTokensCreatePool,
 			Input: fftypes.JSONObject{
 				"id": poolID.String(),
 			},
@@ -156,7 +284,8 @@ func TestTokenPoolCreatedUpsertFail(t *testing.T) {
 	txID := fftypes.NewUUID()
 	operations := []*fftypes.Operation{
 		{
-			ID: fftypes.NewUUID(),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.OpTypeTokensCreatePool,
 			Input: fftypes.JSONObject{
 				"id":        poolID.String(),
 				"namespace": "test-ns",
@@ -179,3 +308,35 @@ func TestTokenPoolCreatedUpsertFail(t *testing.T) {
 	mdi.AssertExpectations(t)
 	mbm.AssertExpectations(t)
 }
+
+func TestReplayTokenPoolEvents(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	from := fftypes.Now()
+	to := from.Add(time.Hour)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("ReplayTokenPoolEvents", context.Background(), from, to).Return(nil)
+
+	err := am.ReplayTokenPoolEvents(context.Background(), "magic-tokens", from, to)
+	assert.NoError(t, err)
+
+	mti.AssertExpectations(t)
+}
+
+func TestReplayTokenPoolEventsBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	err := am.ReplayTokenPoolEvents(context.Background(), "", nil, nil)
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestReplayTokenPoolEventsBadRange(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	from := fftypes.Now()
+	err := am.ReplayTokenPoolEvents(context.Background(), "magic-tokens", from, from.Add(-time.Hour))
+	assert.Regexp(t, "FF10349", err)
+}
@@ -0,0 +1,133 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/tokens"
+)
+
+func (am *assetManager) FreezeTokenPool(ctx context.Context, ns, typeName, name string) (*fftypes.TokenPool, error) {
+	return am.setTokenPoolFrozen(ctx, ns, typeName, name, true)
+}
+
+func (am *assetManager) UnfreezeTokenPool(ctx context.Context, ns, typeName, name string) (*fftypes.TokenPool, error) {
+	return am.setTokenPoolFrozen(ctx, ns, typeName, name, false)
+}
+
+// setTokenPoolFrozen submits a request to the token connector to freeze or unfreeze a pool.
+// The new status of the pool is recorded by TokenPoolStatusChanged, once confirmed on the blockchain
+func (am *assetManager) setTokenPoolFrozen(ctx context.Context, ns, typeName, name string, frozen bool) (*fftypes.TokenPool, error) {
+	plugin, err := am.selectTokenPlugin(ctx, typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := am.GetTokenPool(ctx, ns, typeName, name)
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+
+	author, err := am.identity.Resolve(ctx, config.GetString(config.OrgIdentity))
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgAuthorInvalid)
+	}
+
+	tx := &fftypes.Transaction{
+		ID: fftypes.NewUUID(),
+		Subject: fftypes.TransactionSubject{
+			Namespace: ns,
+			Type:      fftypes.TransactionTypeTokenFreeze,
+			Signer:    author.OnChain, // The transaction records on the on-chain identity
+			Reference: pool.ID,
+		},
+		Created: fftypes.Now(),
+		Status:  fftypes.OpStatusPending,
+	}
+	tx.Hash = tx.Subject.Hash()
+
+	opType := fftypes.OpTypeTokensUnfreezePool
+	if frozen {
+		opType = fftypes.OpTypeTokensFreezePool
+	}
+	op := fftypes.NewTXOperation(
+		plugin,
+		ns,
+		tx.ID,
+		"",
+		opType,
+		fftypes.OpStatusPending,
+		author.Identifier,
+		fftypes.GetCreatedBy(ctx))
+	op.Input = fftypes.JSONObject{
+		"pool": pool.ID.String(),
+	}
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if err := am.database.UpsertTransaction(ctx, tx, false /* should be new, or idempotent replay */); err != nil {
+			return err
+		}
+		return am.database.UpsertOperation(ctx, op, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if frozen {
+		err = plugin.FreezeTokenPool(ctx, op.ID, pool)
+	} else {
+		err = plugin.UnfreezeTokenPool(ctx, op.ID, pool)
+	}
+	if err != nil {
+		// The request was never submitted - so will never receive an update
+		am.failUnsentOperations(ctx, []*fftypes.Operation{op}, err)
+		return nil, err
+	}
+
+	// The status of the pool is not changed here, but only once the connector notifies us
+	// that the freeze (or unfreeze) has been confirmed on the blockchain
+	return pool, nil
+}
+
+// TokenPoolStatusChanged records the new status of a pool, once it has been frozen or unfrozen on the blockchain
+func (am *assetManager) TokenPoolStatusChanged(tk tokens.Plugin, poolProtocolID string, status fftypes.TokenPoolStatus, protocolTxID string, additionalInfo fftypes.JSONObject) error {
+	return am.retry.Do(am.ctx, "persist token pool status", func(attempt int) (bool, error) {
+		pool, err := am.database.GetTokenPoolByProtocolID(am.ctx, poolProtocolID)
+		if err != nil {
+			return true, err
+		}
+		if pool == nil {
+			log.L(am.ctx).Debugf("Token pool status change '%s' ignored, as pool '%s' is not known", protocolTxID, poolProtocolID)
+			return false, nil
+		}
+		if pool.Status == status {
+			log.L(am.ctx).Debugf("Token pool '%s' is already %s", pool.ID, status)
+			return false, nil
+		}
+		log.L(am.ctx).Infof("Token pool '%s' is now %s (tx=%s)", pool.ID, status, protocolTxID)
+		pool.Status = status
+		err = am.database.UpsertTokenPool(am.ctx, pool)
+		return err != nil, err
+	})
+}
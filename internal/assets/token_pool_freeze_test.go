@@ -0,0 +1,230 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFreezeTokenPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), ProtocolID: "F1", Status: fftypes.TokenPoolStatusActive}
+	var opID *fftypes.UUID
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.MatchedBy(func(tx *fftypes.Transaction) bool {
+		return tx.Subject.Type == fftypes.TransactionTypeTokenFreeze && *tx.Subject.Reference == *pool.ID
+	}), false).Return(nil)
+	mdi.On("UpsertOperation", context.Background(), mock.MatchedBy(func(op *fftypes.Operation) bool {
+		opID = op.ID
+		return op.Type == fftypes.OpTypeTokensFreezePool && op.Input.GetString("pool") == pool.ID.String()
+	}), false).Return(nil)
+	mti.On("FreezeTokenPool", context.Background(), mock.MatchedBy(func(id *fftypes.UUID) bool {
+		return *id == *opID
+	}), pool).Return(nil)
+
+	result, err := am.FreezeTokenPool(context.Background(), "ns1", "magic-tokens", "pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.TokenPoolStatusActive, result.Status) // until confirmed on the blockchain
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestUnfreezeTokenPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), ProtocolID: "F1", Status: fftypes.TokenPoolStatusFrozen}
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", context.Background(), mock.MatchedBy(func(op *fftypes.Operation) bool {
+		return op.Type == fftypes.OpTypeTokensUnfreezePool
+	}), false).Return(nil)
+	mti.On("UnfreezeTokenPool", context.Background(), mock.Anything, pool).Return(nil)
+
+	result, err := am.UnfreezeTokenPool(context.Background(), "ns1", "magic-tokens", "pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.TokenPoolStatusFrozen, result.Status) // until confirmed on the blockchain
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestFreezeTokenPoolBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.FreezeTokenPool(context.Background(), "ns1", "", "pool1")
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestFreezeTokenPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+
+	_, err := am.FreezeTokenPool(context.Background(), "ns1", "magic-tokens", "pool1")
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestFreezeTokenPoolLookupFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.FreezeTokenPool(context.Background(), "ns1", "magic-tokens", "pool1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestFreezeTokenPoolBadIdentity(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mii := am.identity.(*identitymocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&fftypes.TokenPool{}, nil)
+	mii.ExpectedCalls = nil
+	mii.On("Resolve", context.Background(), "UTNodeID").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.FreezeTokenPool(context.Background(), "ns1", "magic-tokens", "pool1")
+	assert.Regexp(t, "pop", err)
+}
+
+func TestFreezeTokenPoolUpsertTXFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&fftypes.TokenPool{ID: fftypes.NewUUID()}, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.Anything, false).Return(fmt.Errorf("pop"))
+
+	_, err := am.FreezeTokenPool(context.Background(), "ns1", "magic-tokens", "pool1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestFreezeTokenPoolUpsertOpFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&fftypes.TokenPool{ID: fftypes.NewUUID()}, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", context.Background(), mock.Anything, false).Return(fmt.Errorf("pop"))
+
+	_, err := am.FreezeTokenPool(context.Background(), "ns1", "magic-tokens", "pool1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestFreezeTokenPoolPluginFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), Status: fftypes.TokenPoolStatusActive}
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", context.Background(), mock.Anything, false).Return(nil)
+	mti.On("FreezeTokenPool", context.Background(), mock.Anything, pool).Return(fmt.Errorf("pop"))
+	mdi.On("UpdateOperation", context.Background(), mock.Anything, mock.Anything).Return(nil)
+
+	_, err := am.FreezeTokenPool(context.Background(), "ns1", "magic-tokens", "pool1")
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, fftypes.TokenPoolStatusActive, pool.Status)
+	mdi.AssertNotCalled(t, "UpsertTokenPool", mock.Anything, mock.Anything)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestTokenPoolStatusChanged(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), ProtocolID: "F1", Status: fftypes.TokenPoolStatusActive}
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+	mdi.On("UpsertTokenPool", am.ctx, mock.MatchedBy(func(p *fftypes.TokenPool) bool {
+		return p.Status == fftypes.TokenPoolStatusFrozen
+	})).Return(nil)
+
+	err := am.TokenPoolStatusChanged(mti, "F1", fftypes.TokenPoolStatusFrozen, "tx1", fftypes.JSONObject{})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestTokenPoolStatusChangedUnchanged(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID(), ProtocolID: "F1", Status: fftypes.TokenPoolStatusFrozen}
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+
+	err := am.TokenPoolStatusChanged(mti, "F1", fftypes.TokenPoolStatusFrozen, "tx1", fftypes.JSONObject{})
+	assert.NoError(t, err)
+
+	mdi.AssertNotCalled(t, "UpsertTokenPool", mock.Anything, mock.Anything)
+}
+
+func TestTokenPoolStatusChangedUnknownPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(nil, nil)
+
+	err := am.TokenPoolStatusChanged(mti, "F1", fftypes.TokenPoolStatusFrozen, "tx1", fftypes.JSONObject{})
+	assert.NoError(t, err)
+
+	mdi.AssertNotCalled(t, "UpsertTokenPool", mock.Anything, mock.Anything)
+}
+
+func TestTokenPoolStatusChangedLookupFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	err := am.TokenPoolStatusChanged(mti, "F1", fftypes.TokenPoolStatusFrozen, "tx1", fftypes.JSONObject{})
+	assert.Regexp(t, "FF10158", err)
+}
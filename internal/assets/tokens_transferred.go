@@ -0,0 +1,135 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/tokens"
+)
+
+func (am *assetManager) adjustTokenBalance(ctx context.Context, poolID *fftypes.UUID, address string, delta *big.Int, updated *fftypes.FFTime) error {
+	balance, err := am.database.GetTokenBalance(ctx, poolID, address)
+	if err != nil {
+		return err
+	}
+	if balance == nil {
+		balance = &fftypes.TokenBalance{
+			Pool:    poolID,
+			Address: address,
+		}
+	}
+	balance.Amount.Int().Add(balance.Amount.Int(), delta)
+	balance.LastUpdated = updated
+	return am.database.UpsertTokenBalance(ctx, balance)
+}
+
+// tokenTransferType classifies a transfer event. An empty from address is a mint, and an empty to address is a burn.
+func tokenTransferType(from, to string) fftypes.TransactionType {
+	switch {
+	case from == "":
+		return fftypes.TransactionTypeTokenMint
+	case to == "":
+		return fftypes.TransactionTypeTokenBurn
+	default:
+		return fftypes.TransactionTypeTokenTransfer
+	}
+}
+
+// tokenTransferTxID derives the ID of the transaction recorded for a transfer event, from the pool, the
+// blockchain transaction and the index of the event within it. A single blockchain transaction can emit
+// multiple transfers (such as a batch mint), so the index is required to tell them apart. Redelivery of
+// the same event always results in the same ID.
+func tokenTransferTxID(pool *fftypes.TokenPool, protocolTxID, logIndex string) *fftypes.UUID {
+	return fftypes.NewNamedUUID(pool.ID, fmt.Sprintf("%s/%s", protocolTxID, logIndex))
+}
+
+// tokenTransferNoIndexTxID derives the ID for a transfer event from a connector that does not supply the
+// log index, using the details of the transfer instead. Identical transfers within a single blockchain
+// transaction cannot be told apart in this case, so only the first of them is applied.
+func tokenTransferNoIndexTxID(pool *fftypes.TokenPool, protocolTxID, from, to string, amount *fftypes.BigInt) *fftypes.UUID {
+	return fftypes.NewNamedUUID(pool.ID, fmt.Sprintf("%s/%s/%s/%s", protocolTxID, from, to, amount))
+}
+
+func (am *assetManager) TokensTransferred(tk tokens.Plugin, poolProtocolID, signingKey, from, to string, amount *fftypes.BigInt, protocolTxID string, additionalInfo fftypes.JSONObject) error {
+	return am.retry.Do(am.ctx, "persist token transfer", func(attempt int) (bool, error) {
+		pool, err := am.database.GetTokenPoolByProtocolID(am.ctx, poolProtocolID)
+		if err != nil {
+			return true, err
+		}
+		if pool == nil {
+			log.L(am.ctx).Debugf("Token transfer '%s' ignored, as pool '%s' is not known", protocolTxID, poolProtocolID)
+			return false, nil
+		}
+
+		var txID *fftypes.UUID
+		logIndex := additionalInfo.GetString("logIndex")
+		if logIndex != "" {
+			txID = tokenTransferTxID(pool, protocolTxID, logIndex)
+		} else {
+			log.L(am.ctx).Warnf("Token transfer '%s' in pool '%s' has no log index, so is identified by its from/to/amount: %+v", protocolTxID, pool.ID, additionalInfo)
+			txID = tokenTransferNoIndexTxID(pool, protocolTxID, from, to, amount)
+		}
+
+		// The balances of both sides of the transfer are updated atomically, along with a record
+		// of the transfer as a confirmed transaction of the matching type.
+		// An empty from address is a mint, and an empty to address is a burn.
+		updated := fftypes.Now()
+		txType := tokenTransferType(from, to)
+		err = am.database.RunAsGroup(am.ctx, func(ctx context.Context) error {
+			// Events can be redelivered, so check we have not already applied this one
+			existing, err := am.database.GetTransactionByID(ctx, txID)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				log.L(ctx).Infof("Token transfer '%s' (log index %s) in pool '%s' already applied as transaction '%s'", protocolTxID, logIndex, pool.ID, txID)
+				return nil
+			}
+			if from != "" {
+				if err := am.adjustTokenBalance(ctx, pool.ID, from, new(big.Int).Neg(amount.Int()), updated); err != nil {
+					return err
+				}
+			}
+			if to != "" {
+				if err := am.adjustTokenBalance(ctx, pool.ID, to, amount.Int(), updated); err != nil {
+					return err
+				}
+			}
+			tx := &fftypes.Transaction{
+				ID: txID,
+				Subject: fftypes.TransactionSubject{
+					Namespace: pool.Namespace,
+					Type:      txType,
+					Signer:    signingKey,
+					Reference: pool.ID,
+				},
+				Created:    updated,
+				Status:     fftypes.OpStatusSucceeded,
+				ProtocolID: protocolTxID,
+				Info:       additionalInfo,
+			}
+			tx.Hash = tx.Subject.Hash()
+			return am.database.UpsertTransaction(ctx, tx, false)
+		})
+		return err != nil, err
+	})
+}
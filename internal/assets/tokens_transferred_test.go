@@ -0,0 +1,279 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTokensTransferredAccumulatesBalances(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	pool := &fftypes.TokenPool{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		ProtocolID: "F1",
+	}
+	balances := make(map[string]*fftypes.TokenBalance)
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+	mdi.On("GetTransactionByID", am.ctx, mock.Anything).Return(nil, nil)
+	mdi.On("GetTokenBalance", am.ctx, pool.ID, mock.Anything).Return(func(ctx context.Context, poolID *fftypes.UUID, address string) *fftypes.TokenBalance {
+		return balances[address]
+	}, nil)
+	mdi.On("UpsertTokenBalance", am.ctx, mock.Anything).Return(func(ctx context.Context, balance *fftypes.TokenBalance) error {
+		balances[balance.Address] = balance
+		return nil
+	})
+	txTypes := make(map[string]fftypes.TransactionType)
+	mdi.On("UpsertTransaction", am.ctx, mock.Anything, false).Return(func(ctx context.Context, tx *fftypes.Transaction, allowHashUpdate bool) error {
+		assert.Equal(t, "ns1", tx.Subject.Namespace)
+		assert.Equal(t, *pool.ID, *tx.Subject.Reference)
+		assert.Equal(t, fftypes.OpStatusSucceeded, tx.Status)
+		assert.Equal(t, "0x9", tx.Subject.Signer) // including on mint, where there is no from address
+		txTypes[tx.ProtocolID] = tx.Subject.Type
+		return nil
+	})
+
+	err := am.TokensTransferred(mti, "F1", "0x9", "", "0x1", fftypes.NewBigInt(10), "tx1", fftypes.JSONObject{"logIndex": "0"})
+	assert.NoError(t, err)
+	err = am.TokensTransferred(mti, "F1", "0x9", "0x1", "0x2", fftypes.NewBigInt(3), "tx2", fftypes.JSONObject{"logIndex": "0"})
+	assert.NoError(t, err)
+	err = am.TokensTransferred(mti, "F1", "0x9", "0x1", "0x2", fftypes.NewBigInt(2), "tx3", fftypes.JSONObject{"logIndex": "0"})
+	assert.NoError(t, err)
+	err = am.TokensTransferred(mti, "F1", "0x9", "0x2", "", fftypes.NewBigInt(1), "tx4", fftypes.JSONObject{"logIndex": "0"})
+	assert.NoError(t, err)
+
+	assert.Len(t, balances, 2)
+	assert.Equal(t, "5", balances["0x1"].Amount.String())
+	assert.Equal(t, "4", balances["0x2"].Amount.String())
+	assert.Equal(t, *pool.ID, *balances["0x2"].Pool)
+	assert.NotNil(t, balances["0x2"].LastUpdated)
+	assert.Equal(t, map[string]fftypes.TransactionType{
+		"tx1": fftypes.TransactionTypeTokenMint,
+		"tx2": fftypes.TransactionTypeTokenTransfer,
+		"tx3": fftypes.TransactionTypeTokenTransfer,
+		"tx4": fftypes.TransactionTypeTokenBurn,
+	}, txTypes)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestTokensTransferredPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(nil, nil)
+
+	err := am.TokensTransferred(mti, "F1", "0x9", "0x1", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{"logIndex": "0"})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestTokensTransferredGetPoolFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	err := am.TokensTransferred(mti, "F1", "0x9", "0x1", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{"logIndex": "0"})
+	assert.Regexp(t, "FF10158", err)
+}
+
+func TestTokensTransferredUpsertFromFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID()}
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+	mdi.On("GetTransactionByID", am.ctx, mock.Anything).Return(nil, nil)
+	mdi.On("GetTokenBalance", am.ctx, pool.ID, "0x1").Return(nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	err := am.TokensTransferred(mti, "F1", "0x9", "0x1", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{"logIndex": "0"})
+	assert.Regexp(t, "FF10158", err)
+}
+
+func TestTokensTransferredUpsertToFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID()}
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+	mdi.On("GetTransactionByID", am.ctx, mock.Anything).Return(nil, nil)
+	mdi.On("GetTokenBalance", am.ctx, pool.ID, "0x1").Return(nil, nil)
+	mdi.On("GetTokenBalance", am.ctx, pool.ID, "0x2").Return(nil, nil)
+	mdi.On("UpsertTokenBalance", am.ctx, mock.MatchedBy(func(b *fftypes.TokenBalance) bool { return b.Address == "0x1" })).Return(nil)
+	mdi.On("UpsertTokenBalance", am.ctx, mock.MatchedBy(func(b *fftypes.TokenBalance) bool { return b.Address == "0x2" })).Return(fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	err := am.TokensTransferred(mti, "F1", "0x9", "0x1", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{"logIndex": "0"})
+	assert.Regexp(t, "FF10158", err)
+}
+
+func TestTokensTransferredUpsertTXFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID()}
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+	mdi.On("GetTransactionByID", am.ctx, mock.Anything).Return(nil, nil)
+	mdi.On("GetTokenBalance", am.ctx, pool.ID, "0x2").Return(nil, nil)
+	mdi.On("UpsertTokenBalance", am.ctx, mock.Anything).Return(nil)
+	mdi.On("UpsertTransaction", am.ctx, mock.Anything, false).Return(fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	err := am.TokensTransferred(mti, "F1", "0x9", "", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{"logIndex": "0"})
+	assert.Regexp(t, "FF10158", err)
+}
+
+func TestTokensTransferredLargeAmount(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID()}
+	existing := &fftypes.TokenBalance{Amount: *fftypes.NewBigInt(1)}
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+	mdi.On("GetTransactionByID", am.ctx, mock.Anything).Return(nil, nil)
+	mdi.On("GetTokenBalance", am.ctx, pool.ID, "0x1").Return(existing, nil)
+	mdi.On("UpsertTokenBalance", am.ctx, mock.MatchedBy(func(b *fftypes.TokenBalance) bool {
+		return b.Amount.String() == "100000000000000000001"
+	})).Return(nil)
+	mdi.On("UpsertTransaction", am.ctx, mock.Anything, false).Return(nil)
+
+	amount, _ := new(big.Int).SetString("100000000000000000000", 10)
+	err := am.TokensTransferred(mti, "F1", "0x9", "", "0x1", (*fftypes.BigInt)(amount), "tx1", fftypes.JSONObject{"logIndex": "0"})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestTokensTransferredAlreadyApplied(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID()}
+	txID := tokenTransferTxID(pool, "tx1", "1")
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+	mdi.On("GetTransactionByID", am.ctx, txID).Return(&fftypes.Transaction{ID: txID}, nil)
+
+	err := am.TokensTransferred(mti, "F1", "0x9", "0x1", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{"logIndex": "1"})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdi.AssertNotCalled(t, "UpsertTokenBalance", mock.Anything, mock.Anything)
+}
+
+func TestTokensTransferredSameTXDifferentLogIndex(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID()}
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+	mdi.On("GetTransactionByID", am.ctx, mock.Anything).Return(nil, nil)
+	mdi.On("GetTokenBalance", am.ctx, pool.ID, "0x2").Return(nil, nil)
+	mdi.On("UpsertTokenBalance", am.ctx, mock.Anything).Return(nil)
+	txIDs := make(map[fftypes.UUID]bool)
+	mdi.On("UpsertTransaction", am.ctx, mock.Anything, false).Return(nil).Run(func(args mock.Arguments) {
+		txIDs[*args[1].(*fftypes.Transaction).ID] = true
+	})
+
+	err := am.TokensTransferred(mti, "F1", "0x9", "", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{"logIndex": "1"})
+	assert.NoError(t, err)
+	err = am.TokensTransferred(mti, "F1", "0x9", "", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{"logIndex": "2"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[fftypes.UUID]bool{
+		*tokenTransferTxID(pool, "tx1", "1"): true,
+		*tokenTransferTxID(pool, "tx1", "2"): true,
+	}, txIDs)
+	mdi.AssertExpectations(t)
+}
+
+func TestTokensTransferredNoLogIndex(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID()}
+	txID := tokenTransferNoIndexTxID(pool, "tx1", "0x1", "0x2", fftypes.NewBigInt(1))
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+	mdi.On("GetTransactionByID", am.ctx, txID).Return(nil, nil).Once()
+	mdi.On("GetTokenBalance", am.ctx, pool.ID, mock.Anything).Return(nil, nil)
+	mdi.On("UpsertTokenBalance", am.ctx, mock.Anything).Return(nil)
+	mdi.On("UpsertTransaction", am.ctx, mock.MatchedBy(func(tx *fftypes.Transaction) bool {
+		return tx.ID.Equals(txID)
+	}), false).Return(nil).Once()
+
+	// The transfer is applied, rather than dropped
+	err := am.TokensTransferred(mti, "F1", "0x9", "0x1", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{})
+	assert.NoError(t, err)
+
+	// A redelivery of the same event maps to the same transaction, so is only applied once
+	mdi.On("GetTransactionByID", am.ctx, txID).Return(&fftypes.Transaction{ID: txID}, nil).Once()
+	err = am.TokensTransferred(mti, "F1", "0x9", "0x1", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdi.AssertNumberOfCalls(t, "UpsertTransaction", 1)
+	mdi.AssertNumberOfCalls(t, "UpsertTokenBalance", 2)
+}
+
+func TestTokensTransferredGetTransactionFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	pool := &fftypes.TokenPool{ID: fftypes.NewUUID()}
+	mdi.On("GetTokenPoolByProtocolID", am.ctx, "F1").Return(pool, nil)
+	mdi.On("GetTransactionByID", am.ctx, mock.Anything).Return(nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	err := am.TokensTransferred(mti, "F1", "0x9", "0x1", "0x2", fftypes.NewBigInt(1), "tx1", fftypes.JSONObject{"logIndex": "0"})
+	assert.Regexp(t, "FF10158", err)
+}
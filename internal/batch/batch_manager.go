@@ -47,10 +47,13 @@ func NewBatchManager(ctx context.Context, di database.Plugin, dm data.Manager) (
 		readPageSize:               uint64(readPageSize),
 		messagePollTimeout:         config.GetDuration(config.BatchManagerReadPollTimeout),
 		startupOffsetRetryAttempts: config.GetInt(config.OrchestratorStartupAttempts),
+		localOrgIdentity:           config.GetString(config.OrgIdentity),
+		localNodeName:              config.GetString(config.NodeName),
 		dispatchers:                make(map[fftypes.MessageType]*dispatcher),
 		shoulderTap:                make(chan bool, 1),
 		newMessages:                make(chan int64, readPageSize),
 		sequencerClosed:            make(chan struct{}),
+		messageWaiters:             make(map[fftypes.UUID][]chan *fftypes.Batch),
 		retry: &retry.Retry{
 			InitialDelay: config.GetDuration(config.BatchRetryInitDelay),
 			MaximumDelay: config.GetDuration(config.BatchRetryMaxDelay),
@@ -62,7 +65,10 @@ func NewBatchManager(ctx context.Context, di database.Plugin, dm data.Manager) (
 
 type Manager interface {
 	RegisterDispatcher(msgTypes []fftypes.MessageType, handler DispatchHandler, batchOptions Options)
+	RegisterInspector(i Inspector)
+	SetDispatcher(msgTypes []fftypes.MessageType, handler DispatchHandler, batchOptions Options) error
 	NewMessages() chan<- int64
+	WaitForMessage(ctx context.Context, msgID *fftypes.UUID) (*fftypes.Batch, error)
 	Start() error
 	Close()
 	WaitStop()
@@ -73,6 +79,7 @@ type batchManager struct {
 	database                   database.Plugin
 	data                       data.Manager
 	dispatchers                map[fftypes.MessageType]*dispatcher
+	dispatcherMux              sync.RWMutex
 	shoulderTap                chan bool
 	newMessages                chan int64
 	sequencerClosed            chan struct{}
@@ -83,6 +90,14 @@ type batchManager struct {
 	readPageSize               uint64
 	messagePollTimeout         time.Duration
 	startupOffsetRetryAttempts int
+	localOrgIdentity           string
+	localNodeName              string
+	localNodeID                *fftypes.UUID
+	localNodeMux               sync.Mutex
+	inspectors                 []Inspector
+	inspectorMux               sync.RWMutex
+	messageWaiters             map[fftypes.UUID][]chan *fftypes.Batch
+	messageWaitersMux          sync.Mutex
 }
 
 type DispatchHandler func(context.Context, *fftypes.Batch, []*fftypes.Bytes32) error
@@ -100,17 +115,152 @@ type dispatcher struct {
 	batchOptions Options
 }
 
-func (bm *batchManager) RegisterDispatcher(msgTypes []fftypes.MessageType, handler DispatchHandler, batchOptions Options) {
-	dispatcher := &dispatcher{
+func newDispatcher(handler DispatchHandler, batchOptions Options) *dispatcher {
+	return &dispatcher{
 		handler:      handler,
 		batchOptions: batchOptions,
 		processors:   make(map[string]*batchProcessor),
 	}
+}
+
+func (bm *batchManager) RegisterDispatcher(msgTypes []fftypes.MessageType, handler DispatchHandler, batchOptions Options) {
+	dispatcher := newDispatcher(handler, batchOptions)
+	bm.dispatcherMux.Lock()
+	defer bm.dispatcherMux.Unlock()
 	for _, msgType := range msgTypes {
 		bm.dispatchers[msgType] = dispatcher
 	}
 }
 
+// RegisterInspector adds an observer of batch assembly. Inspectors are called in addition to,
+// and have no effect on, the dispatchers.
+func (bm *batchManager) RegisterInspector(i Inspector) {
+	bm.inspectorMux.Lock()
+	defer bm.inspectorMux.Unlock()
+	bm.inspectors = append(bm.inspectors, i)
+}
+
+func (bm *batchManager) messageQueued(msg *fftypes.Message) {
+	bm.inspectorMux.RLock()
+	defer bm.inspectorMux.RUnlock()
+	for _, i := range bm.inspectors {
+		i.OnMessageQueued(msg)
+	}
+}
+
+func (bm *batchManager) batchFlushed(batch *fftypes.Batch) {
+	bm.inspectorMux.RLock()
+	defer bm.inspectorMux.RUnlock()
+	for _, i := range bm.inspectors {
+		i.OnBatchFlushed(batch)
+	}
+}
+
+func (bm *batchManager) batchDispatched(batch *fftypes.Batch) {
+	bm.messageWaitersMux.Lock()
+	defer bm.messageWaitersMux.Unlock()
+	for _, msg := range batch.Payload.Messages {
+		for _, waiter := range bm.messageWaiters[*msg.Header.ID] {
+			waiter <- batch
+		}
+		delete(bm.messageWaiters, *msg.Header.ID)
+	}
+}
+
+// WaitForMessage blocks until the batch containing the message has been dispatched, and returns that batch.
+// Once the waiter is registered the database is checked, in case the batch was dispatched before the wait began.
+func (bm *batchManager) WaitForMessage(ctx context.Context, msgID *fftypes.UUID) (*fftypes.Batch, error) {
+	waiter := make(chan *fftypes.Batch, 1)
+	bm.messageWaitersMux.Lock()
+	bm.messageWaiters[*msgID] = append(bm.messageWaiters[*msgID], waiter)
+	bm.messageWaitersMux.Unlock()
+
+	batch, err := bm.dispatchedBatchForMessage(ctx, msgID)
+	if err != nil || batch != nil {
+		bm.removeMessageWaiter(msgID, waiter)
+		return batch, err
+	}
+
+	select {
+	case batch := <-waiter:
+		return batch, nil
+	case <-ctx.Done():
+		bm.removeMessageWaiter(msgID, waiter)
+		return nil, i18n.NewError(ctx, i18n.MsgContextCanceled)
+	}
+}
+
+// dispatchedBatchForMessage returns the batch containing the message, if that batch has been dispatched.
+// Every dispatch ends by writing the transaction of the batch, so its presence shows the dispatch completed.
+func (bm *batchManager) dispatchedBatchForMessage(ctx context.Context, msgID *fftypes.UUID) (*fftypes.Batch, error) {
+	msg, err := bm.database.GetMessageByID(ctx, msgID)
+	if err != nil || msg == nil || msg.BatchID == nil {
+		return nil, err
+	}
+	batch, err := bm.database.GetBatchByID(ctx, msg.BatchID)
+	if err != nil || batch == nil || batch.Payload.TX.ID == nil {
+		return nil, err
+	}
+	tx, err := bm.database.GetTransactionByID(ctx, batch.Payload.TX.ID)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (bm *batchManager) removeMessageWaiter(msgID *fftypes.UUID, waiter chan *fftypes.Batch) {
+	bm.messageWaitersMux.Lock()
+	defer bm.messageWaitersMux.Unlock()
+	waiters := bm.messageWaiters[*msgID]
+	for i, w := range waiters {
+		if w == waiter {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(bm.messageWaiters, *msgID)
+	} else {
+		bm.messageWaiters[*msgID] = waiters
+	}
+}
+
+// SetDispatcher replaces the dispatcher for already registered message types at runtime.
+// Once the new dispatcher is in place, the processors of the old dispatcher are told to stop
+// accepting work, and we wait for them to seal and dispatch any in-flight batch before returning.
+func (bm *batchManager) SetDispatcher(msgTypes []fftypes.MessageType, handler DispatchHandler, batchOptions Options) error {
+	newDispatcher := newDispatcher(handler, batchOptions)
+
+	bm.dispatcherMux.Lock()
+	for _, msgType := range msgTypes {
+		if _, ok := bm.dispatchers[msgType]; !ok {
+			bm.dispatcherMux.Unlock()
+			return i18n.NewError(bm.ctx, i18n.MsgUnregisteredBatchType, msgType)
+		}
+	}
+	var processors []*batchProcessor
+	for _, msgType := range msgTypes {
+		oldDispatcher := bm.dispatchers[msgType]
+		bm.dispatchers[msgType] = newDispatcher
+		oldDispatcher.mux.Lock()
+		for key, p := range oldDispatcher.processors {
+			processors = append(processors, p)
+			delete(oldDispatcher.processors, key)
+		}
+		oldDispatcher.mux.Unlock()
+	}
+	bm.dispatcherMux.Unlock()
+
+	log.L(bm.ctx).Infof("Replaced batch dispatcher for %v - draining %d processors", msgTypes, len(processors))
+	for _, p := range processors {
+		p.drain()
+	}
+	for _, p := range processors {
+		p.waitClosed()
+	}
+	return nil
+}
+
 func (bm *batchManager) Start() error {
 	if err := bm.restoreOffset(); err != nil {
 		return err
@@ -151,24 +301,31 @@ func (bm *batchManager) removeProcessor(dispatcher *dispatcher, key string) {
 	dispatcher.mux.Unlock()
 }
 
-func (bm *batchManager) getProcessor(batchType fftypes.MessageType, group *fftypes.Bytes32, namespace, author string) (*batchProcessor, error) {
+// getProcessor returns the processor for a batch type, group, namespace and author. Ephemeral messages
+// are batched separately, as a batch is only ephemeral when every message in it is ephemeral.
+// Messages delivered to additional groups are batched with others delivered to the same groups.
+func (bm *batchManager) getProcessor(batchType fftypes.MessageType, group *fftypes.Bytes32, groups fftypes.GroupRefs, namespace, author string, ephemeral bool) (*batchProcessor, error) {
 	dispatcher, ok := bm.dispatchers[batchType]
 	if !ok {
 		return nil, i18n.NewError(bm.ctx, i18n.MsgUnregisteredBatchType, batchType)
 	}
 	dispatcher.mux.Lock()
-	key := fmt.Sprintf("%s:%s[group=%v]", namespace, author, group)
+	key := fmt.Sprintf("%s:%s[group=%v,groups=%s,ephemeral=%t]", namespace, author, group, groups, ephemeral)
 	processor, ok := dispatcher.processors[key]
 	if !ok {
 		processor = newBatchProcessor(
 			bm.ctx, // Background context, not the call context
 			bm.database,
 			&batchProcessorConf{
-				Options:   dispatcher.batchOptions,
-				namespace: namespace,
-				author:    author,
-				group:     group,
-				dispatch:  dispatcher.handler,
+				Options:         dispatcher.batchOptions,
+				namespace:       namespace,
+				author:          author,
+				group:           group,
+				groups:          groups,
+				dispatch:        dispatcher.handler,
+				localNode:       bm.resolveLocalNode,
+				batchFlushed:    bm.batchFlushed,
+				batchDispatched: bm.batchDispatched,
 				processorQuiescing: func() {
 					bm.removeProcessor(dispatcher, key)
 				},
@@ -182,8 +339,36 @@ func (bm *batchManager) getProcessor(batchType fftypes.MessageType, group *fftyp
 	return processor, nil
 }
 
+// resolveLocalNode finds the ID of this node, as registered under the local org identity.
+// The node will not be registered yet while the org/node registration broadcasts are
+// in flight, so nil is returned (without caching) in that case.
+func (bm *batchManager) resolveLocalNode(ctx context.Context) (*fftypes.UUID, error) {
+	bm.localNodeMux.Lock()
+	defer bm.localNodeMux.Unlock()
+	if bm.localNodeID != nil {
+		return bm.localNodeID, nil
+	}
+	fb := database.NodeQueryFactory.NewFilterLimit(ctx, 1)
+	filter := fb.And(
+		fb.Eq("owner", bm.localOrgIdentity),
+		fb.Eq("name", bm.localNodeName),
+	)
+	nodes, _, err := bm.database.GetNodes(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		log.L(ctx).Debugf("Local node '%s' not yet registered for org '%s'", bm.localNodeName, bm.localOrgIdentity)
+		return nil, nil
+	}
+	bm.localNodeID = nodes[0].ID
+	return bm.localNodeID, nil
+}
+
 func (bm *batchManager) Close() {
 	if bm != nil && !bm.closed {
+		bm.dispatcherMux.RLock()
+		defer bm.dispatcherMux.RUnlock()
 		for _, d := range bm.dispatchers {
 			d.mux.Lock()
 			for _, p := range d.processors {
@@ -249,6 +434,8 @@ func (bm *batchManager) messageSequencer() {
 
 		if len(msgs) > 0 {
 			batchWasFull = (uint64(len(msgs)) == bm.readPageSize)
+			lastSequence := msgs[len(msgs)-1].Sequence
+			prioritizeMessages(msgs)
 			var dispatchCount int
 			for _, msg := range msgs {
 				data, err := bm.assembleMessageData(msg)
@@ -277,7 +464,7 @@ func (bm *batchManager) messageSequencer() {
 			}
 
 			if !bm.closed {
-				_ = bm.updateOffset(true, msgs[len(msgs)-1].Sequence)
+				_ = bm.updateOffset(true, lastSequence)
 			}
 		}
 
@@ -288,6 +475,50 @@ func (bm *batchManager) messageSequencer() {
 	}
 }
 
+// prioritizeMessages orders a page of messages so that higher priority messages are dispatched first.
+// A message is never moved ahead of an earlier message from the same author on any of the same topics,
+// as the order within each of those contexts must be preserved. Otherwise messages keep their sequence order.
+func prioritizeMessages(msgs []*fftypes.Message) {
+	// Each message waits for the previous message in each of its author/topic contexts
+	blockedBy := make([]int, len(msgs))
+	unblocks := make([][]int, len(msgs))
+	lastInContext := make(map[string]int)
+	for i, msg := range msgs {
+		topics := msg.Header.Topics
+		if len(topics) == 0 {
+			topics = fftypes.FFNameArray{""}
+		}
+		waitingOn := make(map[int]bool)
+		for _, topic := range topics {
+			key := fmt.Sprintf("%s:%s:%s", msg.Header.Namespace, msg.Header.Author, topic)
+			if prev, ok := lastInContext[key]; ok && !waitingOn[prev] {
+				waitingOn[prev] = true
+				unblocks[prev] = append(unblocks[prev], i)
+				blockedBy[i]++
+			}
+			lastInContext[key] = i
+		}
+	}
+
+	// Repeatedly take the earliest of the highest priority messages that are not waiting on another
+	ordered := make([]*fftypes.Message, 0, len(msgs))
+	taken := make([]bool, len(msgs))
+	for len(ordered) < len(msgs) {
+		next := -1
+		for i, msg := range msgs {
+			if !taken[i] && blockedBy[i] == 0 && (next < 0 || msg.Header.Priority > msgs[next].Header.Priority) {
+				next = i
+			}
+		}
+		taken[next] = true
+		ordered = append(ordered, msgs[next])
+		for _, i := range unblocks[next] {
+			blockedBy[i]--
+		}
+	}
+	copy(msgs, ordered)
+}
+
 // newEventNotifications just consumes new messags, logs them, then ensures there's a shoulderTap
 // in the channel - without blocking. This is important as we must not block the notifier
 func (bm *batchManager) newEventNotifications() {
@@ -345,7 +576,10 @@ func (bm *batchManager) updateOffset(infiniteRetry bool, newOffset int64) (err e
 
 func (bm *batchManager) dispatchMessage(dispatched chan *batchDispatch, msg *fftypes.Message, data ...*fftypes.Data) error {
 	l := log.L(bm.ctx)
-	processor, err := bm.getProcessor(msg.Header.Type, msg.Header.Group, msg.Header.Namespace, msg.Header.Author)
+	// Hold the read lock until the work is handed over, so the processor cannot be drained by SetDispatcher under us
+	bm.dispatcherMux.RLock()
+	defer bm.dispatcherMux.RUnlock()
+	processor, err := bm.getProcessor(msg.Header.Type, msg.Header.Group, msg.Groups, msg.Header.Namespace, msg.Header.Author, msg.Ephemeral)
 	if err != nil {
 		return err
 	}
@@ -356,12 +590,14 @@ func (bm *batchManager) dispatchMessage(dispatched chan *batchDispatch, msg *fft
 		dispatched: dispatched,
 	}
 	processor.newWork <- work
+	bm.messageQueued(msg)
 	return nil
 }
 
 func (bm *batchManager) WaitStop() {
 	<-bm.sequencerClosed
 	var processors []*batchProcessor
+	bm.dispatcherMux.RLock()
 	for _, d := range bm.dispatchers {
 		d.mux.Lock()
 		for _, p := range d.processors {
@@ -369,6 +605,7 @@ func (bm *batchManager) WaitStop() {
 		}
 		d.mux.Unlock()
 	}
+	bm.dispatcherMux.RUnlock()
 	for _, p := range processors {
 		p.waitClosed()
 	}
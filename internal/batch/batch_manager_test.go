@@ -19,6 +19,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -32,6 +33,24 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+type testInspector struct {
+	mux     sync.Mutex
+	queued  []*fftypes.Message
+	flushed []*fftypes.Batch
+}
+
+func (ti *testInspector) OnMessageQueued(msg *fftypes.Message) {
+	ti.mux.Lock()
+	defer ti.mux.Unlock()
+	ti.queued = append(ti.queued, msg)
+}
+
+func (ti *testInspector) OnBatchFlushed(batch *fftypes.Batch) {
+	ti.mux.Lock()
+	defer ti.mux.Unlock()
+	ti.flushed = append(ti.flushed, batch)
+}
+
 func TestE2EDispatchBroadcast(t *testing.T) {
 	log.SetLevel("debug")
 
@@ -79,6 +98,8 @@ func TestE2EDispatchBroadcast(t *testing.T) {
 		BatchTimeout:   0,
 		DisposeTimeout: 120 * time.Second,
 	})
+	inspector := &testInspector{}
+	bm.RegisterInspector(inspector)
 
 	dataID1 := fftypes.NewUUID()
 	dataHash := fftypes.NewRandB32()
@@ -103,6 +124,8 @@ func TestE2EDispatchBroadcast(t *testing.T) {
 	mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
 	mdi.On("UpsertBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mdi.On("UpdateBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	localNodeID := fftypes.NewUUID()
+	mdi.On("GetNodes", mock.Anything, mock.Anything).Return([]*fftypes.Node{{ID: localNodeID}}, nil, nil)
 	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	rag.RunFn = func(a mock.Arguments) {
 		ctx := a.Get(0).(context.Context)
@@ -116,21 +139,44 @@ func TestE2EDispatchBroadcast(t *testing.T) {
 		return true
 	}), mock.Anything).Return(nil)
 
+	mdi.On("GetMessageByID", mock.Anything, msg.Header.ID).Return(nil, nil)
+
 	err := bm.Start()
 	assert.NoError(t, err)
 
+	waitResult := make(chan *fftypes.Batch)
+	go func() {
+		wb, err := bm.WaitForMessage(ctx, msg.Header.ID)
+		assert.NoError(t, err)
+		waitResult <- wb
+	}()
+	assert.Eventually(t, func() bool {
+		bm.messageWaitersMux.Lock()
+		defer bm.messageWaitersMux.Unlock()
+		return len(bm.messageWaiters[*msg.Header.ID]) == 1
+	}, 5*time.Second, time.Millisecond)
+
 	bm.NewMessages() <- msg.Sequence
 
 	readyForDispatch <- true
 	b := <-waitForDispatch
+	assert.Equal(t, b, <-waitResult)
 	assert.Equal(t, *msg.Header.ID, *b.Payload.Messages[0].Header.ID)
 	assert.Equal(t, *data.ID, *b.Payload.Data[0].ID)
+	assert.Equal(t, *localNodeID, *b.CreatorNodeID)
+	assert.Equal(t, fftypes.BatchSchemaVersion, b.SchemaVersion)
 
 	// Wait until everything closes
 	close(readyForDispatch)
 	cancel()
 	bm.WaitStop()
 
+	// The inspector saw the message queued, and the batch flushed before it was dispatched
+	inspector.mux.Lock()
+	defer inspector.mux.Unlock()
+	assert.Equal(t, []*fftypes.Message{msg}, inspector.queued)
+	assert.Equal(t, []*fftypes.Batch{b}, inspector.flushed)
+
 }
 
 func TestE2EDispatchPrivate(t *testing.T) {
@@ -208,6 +254,7 @@ func TestE2EDispatchPrivate(t *testing.T) {
 	mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
 	mdi.On("UpsertBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mdi.On("UpdateBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("GetNodes", mock.Anything, mock.Anything).Return([]*fftypes.Node{}, nil, nil)
 	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	rag.RunFn = func(a mock.Arguments) {
 		ctx := a.Get(0).(context.Context)
@@ -363,7 +410,7 @@ func TestMessageSequencerDispatchFail(t *testing.T) {
 	gmMock.RunFn = func(a mock.Arguments) {
 		bm.Close() // so we only go round once
 	}
-	mdm.On("GetMessageData", mock.Anything, mock.Anything, true).Return([]*fftypes.Data{{ID: dataID}}, true, nil)
+	mdm.On("GetMessageData", mock.Anything, mock.Anything, true).Return([]*fftypes.Data{{ID: dataID, Hash: fftypes.NewRandB32()}}, true, nil)
 
 	bm.(*batchManager).messageSequencer()
 	mdi.AssertExpectations(t)
@@ -391,7 +438,7 @@ func TestMessageSequencerUpdateMessagesFail(t *testing.T) {
 				{ID: dataID},
 			}},
 	}, nil, nil)
-	mdm.On("GetMessageData", mock.Anything, mock.Anything, true).Return([]*fftypes.Data{{ID: dataID}}, true, nil)
+	mdm.On("GetMessageData", mock.Anything, mock.Anything, true).Return([]*fftypes.Data{{ID: dataID, Hash: fftypes.NewRandB32()}}, true, nil)
 	mdi.On("UpdateMessages", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("fizzle"))
 	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything, mock.Anything)
 	rag.RunFn = func(a mock.Arguments) {
@@ -431,9 +478,10 @@ func TestMessageSequencerUpdateBatchFail(t *testing.T) {
 				{ID: dataID},
 			}},
 	}, nil, nil)
-	mdm.On("GetMessageData", mock.Anything, mock.Anything, true).Return([]*fftypes.Data{{ID: dataID}}, true, nil)
+	mdm.On("GetMessageData", mock.Anything, mock.Anything, true).Return([]*fftypes.Data{{ID: dataID, Hash: fftypes.NewRandB32()}}, true, nil)
 	mdi.On("UpdateMessages", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mdi.On("UpsertBatch", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("fizzle"))
+	mdi.On("GetNodes", mock.Anything, mock.Anything).Return([]*fftypes.Node{}, nil, nil)
 	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything, mock.Anything)
 	rag.RunFn = func(a mock.Arguments) {
 		ctx := a.Get(0).(context.Context)
@@ -539,3 +587,364 @@ func TestWaitForShoulderTap(t *testing.T) {
 	bm.(*batchManager).shoulderTap <- true
 	bm.(*batchManager).waitForShoulderTapOrPollTimeout()
 }
+
+func TestSetDispatcherDrainsOldDispatcher(t *testing.T) {
+	log.SetLevel("debug")
+
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	mdi.On("UpsertBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("UpdateMessages", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("GetNodes", mock.Anything, mock.Anything).Return([]*fftypes.Node{}, nil, nil)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	rag.RunFn = func(a mock.Arguments) {
+		ctx := a.Get(0).(context.Context)
+		fn := a.Get(1).(func(context.Context) error)
+		fn(ctx)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bmi, _ := NewBatchManager(ctx, mdi, mdm)
+	bm := bmi.(*batchManager)
+
+	oldDispatched := make(chan *fftypes.Batch, 1)
+	bm.RegisterDispatcher([]fftypes.MessageType{fftypes.MessageTypeBroadcast}, func(c context.Context, b *fftypes.Batch, s []*fftypes.Bytes32) error {
+		oldDispatched <- b
+		return nil
+	}, Options{
+		BatchMaxSize:   10,
+		BatchTimeout:   1 * time.Minute,
+		DisposeTimeout: 1 * time.Minute,
+	})
+
+	newMessage := func() *fftypes.Message {
+		return &fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Type:      fftypes.MessageTypeBroadcast,
+				ID:        fftypes.NewUUID(),
+				Topics:    []string{"topic1"},
+				Namespace: "ns1",
+				Author:    "0x12345",
+			},
+		}
+	}
+	dispatched := make(chan *batchDispatch, 2)
+
+	// Put a message in-flight in a batch that will not seal on its own for a minute
+	msg1 := newMessage()
+	err := bm.dispatchMessage(dispatched, msg1)
+	assert.NoError(t, err)
+	<-dispatched
+	assert.Empty(t, oldDispatched)
+
+	newDispatched := make(chan *fftypes.Batch, 1)
+	err = bm.SetDispatcher([]fftypes.MessageType{fftypes.MessageTypeBroadcast}, func(c context.Context, b *fftypes.Batch, s []*fftypes.Bytes32) error {
+		newDispatched <- b
+		return nil
+	}, Options{
+		BatchMaxSize:   1,
+		BatchTimeout:   0,
+		DisposeTimeout: 1 * time.Minute,
+	})
+	assert.NoError(t, err)
+
+	// The in-flight batch must have been dispatched to the old dispatcher before we returned
+	assert.Len(t, oldDispatched, 1)
+	b := <-oldDispatched
+	assert.Equal(t, *msg1.Header.ID, *b.Payload.Messages[0].Header.ID)
+
+	// New messages go to the new dispatcher
+	msg2 := newMessage()
+	err = bm.dispatchMessage(dispatched, msg2)
+	assert.NoError(t, err)
+	b = <-newDispatched
+	assert.Equal(t, *msg2.Header.ID, *b.Payload.Messages[0].Header.ID)
+	assert.Empty(t, oldDispatched)
+
+	bm.Close()
+}
+
+func TestGetProcessorSeparatesEphemeral(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+	defer bm.Close()
+	bm.RegisterDispatcher([]fftypes.MessageType{fftypes.MessageTypePrivate}, nil, Options{})
+
+	group := fftypes.NewRandB32()
+	p1, err := bm.getProcessor(fftypes.MessageTypePrivate, group, nil, "ns1", "org1", false)
+	assert.NoError(t, err)
+	p2, err := bm.getProcessor(fftypes.MessageTypePrivate, group, nil, "ns1", "org1", true)
+	assert.NoError(t, err)
+	p3, err := bm.getProcessor(fftypes.MessageTypePrivate, group, nil, "ns1", "org1", true)
+	assert.NoError(t, err)
+	assert.NotSame(t, p1, p2)
+	assert.Same(t, p2, p3)
+	assert.Len(t, bm.dispatchers[fftypes.MessageTypePrivate].processors, 2)
+}
+
+func TestGetProcessorSeparatesAdditionalGroups(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+	defer bm.Close()
+	bm.RegisterDispatcher([]fftypes.MessageType{fftypes.MessageTypePrivate}, nil, Options{})
+
+	group := fftypes.NewRandB32()
+	groups := fftypes.GroupRefs{{Hash: fftypes.NewRandB32()}, {Hash: fftypes.NewRandB32()}}
+	p1, err := bm.getProcessor(fftypes.MessageTypePrivate, group, nil, "ns1", "org1", false)
+	assert.NoError(t, err)
+	p2, err := bm.getProcessor(fftypes.MessageTypePrivate, group, groups, "ns1", "org1", false)
+	assert.NoError(t, err)
+	p3, err := bm.getProcessor(fftypes.MessageTypePrivate, group, groups, "ns1", "org1", false)
+	assert.NoError(t, err)
+	assert.NotSame(t, p1, p2)
+	assert.Same(t, p2, p3)
+
+	batch := p2.createOrAddToBatch(nil, []*batchWork{{msg: &fftypes.Message{}}})
+	assert.Equal(t, groups, batch.Payload.Groups)
+	assert.Equal(t, []*fftypes.Bytes32{group, groups[0].Hash, groups[1].Hash}, batch.TargetGroups())
+}
+
+func TestSetDispatcherUnregisteredType(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+	bm.RegisterDispatcher([]fftypes.MessageType{fftypes.MessageTypeBroadcast}, nil, Options{})
+	err := bm.SetDispatcher([]fftypes.MessageType{fftypes.MessageTypeBroadcast, fftypes.MessageTypePrivate}, nil, Options{})
+	assert.Regexp(t, "FF10126", err)
+	assert.NotNil(t, bm.dispatchers[fftypes.MessageTypeBroadcast])
+	assert.Nil(t, bm.dispatchers[fftypes.MessageTypePrivate])
+}
+
+func TestResolveLocalNodeCached(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+	localNodeID := fftypes.NewUUID()
+	mdi.On("GetNodes", mock.Anything, mock.Anything).Return([]*fftypes.Node{{ID: localNodeID}}, nil, nil).Once()
+	nodeID, err := bm.resolveLocalNode(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, *localNodeID, *nodeID)
+	nodeID, err = bm.resolveLocalNode(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, *localNodeID, *nodeID)
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveLocalNodeNotRegistered(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+	mdi.On("GetNodes", mock.Anything, mock.Anything).Return([]*fftypes.Node{}, nil, nil)
+	nodeID, err := bm.resolveLocalNode(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, nodeID)
+	assert.Nil(t, bm.localNodeID)
+}
+
+func TestResolveLocalNodeFail(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+	mdi.On("GetNodes", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := bm.resolveLocalNode(context.Background())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestWaitForMessageContextCancelled(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+
+	msgID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", mock.Anything, msgID).Return(nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := bm.WaitForMessage(ctx, msgID)
+	assert.Regexp(t, "FF10158", err)
+	assert.Empty(t, bm.messageWaiters)
+}
+
+func TestWaitForMessageAlreadyDispatched(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+
+	msgID := fftypes.NewUUID()
+	batch := &fftypes.Batch{
+		ID: fftypes.NewUUID(),
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{ID: fftypes.NewUUID()},
+		},
+	}
+	mdi.On("GetMessageByID", mock.Anything, msgID).Return(&fftypes.Message{BatchID: batch.ID}, nil)
+	mdi.On("GetBatchByID", mock.Anything, batch.ID).Return(batch, nil)
+	mdi.On("GetTransactionByID", mock.Anything, batch.Payload.TX.ID).Return(&fftypes.Transaction{ID: batch.Payload.TX.ID}, nil)
+
+	wb, err := bm.WaitForMessage(context.Background(), msgID)
+	assert.NoError(t, err)
+	assert.Equal(t, batch, wb)
+	assert.Empty(t, bm.messageWaiters)
+	mdi.AssertExpectations(t)
+}
+
+func TestWaitForMessageBatchNotDispatched(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+
+	msgID := fftypes.NewUUID()
+	batch := &fftypes.Batch{
+		ID: fftypes.NewUUID(),
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{ID: fftypes.NewUUID()},
+		},
+	}
+	mdi.On("GetMessageByID", mock.Anything, msgID).Return(&fftypes.Message{BatchID: batch.ID}, nil)
+	mdi.On("GetBatchByID", mock.Anything, batch.ID).Return(batch, nil)
+	mdi.On("GetTransactionByID", mock.Anything, batch.Payload.TX.ID).Return(nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := bm.WaitForMessage(ctx, msgID)
+	assert.Regexp(t, "FF10158", err)
+	assert.Empty(t, bm.messageWaiters)
+	mdi.AssertExpectations(t)
+}
+
+func TestWaitForMessageLookupFail(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+
+	msgID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", mock.Anything, msgID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := bm.WaitForMessage(context.Background(), msgID)
+	assert.Regexp(t, "pop", err)
+	assert.Empty(t, bm.messageWaiters)
+}
+
+func TestWaitForMessageMultipleWaiters(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	bmi, _ := NewBatchManager(context.Background(), mdi, mdm)
+	bm := bmi.(*batchManager)
+
+	msgID := fftypes.NewUUID()
+	other := make(chan *fftypes.Batch, 1)
+	bm.messageWaiters[*msgID] = []chan *fftypes.Batch{other}
+	mdi.On("GetMessageByID", mock.Anything, msgID).Return(nil, nil)
+
+	// A cancelled waiter leaves the other waiter in place
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := bm.WaitForMessage(ctx, msgID)
+	assert.Regexp(t, "FF10158", err)
+	assert.Equal(t, []chan *fftypes.Batch{other}, bm.messageWaiters[*msgID])
+
+	batch := &fftypes.Batch{
+		ID: fftypes.NewUUID(),
+		Payload: fftypes.BatchPayload{
+			Messages: []*fftypes.Message{
+				{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}},
+				{Header: fftypes.MessageHeader{ID: msgID}},
+			},
+		},
+	}
+	bm.batchDispatched(batch)
+	assert.Equal(t, batch, <-other)
+	assert.Empty(t, bm.messageWaiters)
+}
+
+func TestMessageSequencerDispatchesHighPriorityFirst(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	bmi, _ := NewBatchManager(ctx, mdi, mdm)
+	bm := bmi.(*batchManager)
+	bm.RegisterDispatcher([]fftypes.MessageType{fftypes.MessageTypeBroadcast}, func(c context.Context, b *fftypes.Batch, s []*fftypes.Bytes32) error {
+		return nil
+	}, Options{BatchMaxSize: 1, DisposeTimeout: 0})
+	inspector := &testInspector{}
+	bm.RegisterInspector(inspector)
+
+	dataID := fftypes.NewUUID()
+	newMsg := func(sequence int64, priority uint8) *fftypes.Message {
+		return &fftypes.Message{
+			Header: fftypes.MessageHeader{
+				ID:        fftypes.NewUUID(),
+				Type:      fftypes.MessageTypeBroadcast,
+				Namespace: "ns1",
+				Author:    "0x12345",
+				Topics:    fftypes.FFNameArray{fmt.Sprintf("topic%d", sequence)},
+				Priority:  priority,
+			},
+			Sequence: sequence,
+			Data:     []*fftypes.DataRef{{ID: dataID}},
+		}
+	}
+	low1 := newMsg(1, 0)
+	low2 := newMsg(2, 0)
+	high := newMsg(3, 10)
+	mdi.On("GetMessages", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{low1, low2, high}, nil, nil)
+	mdm.On("GetMessageData", mock.Anything, mock.Anything, true).Return([]*fftypes.Data{{ID: dataID, Hash: fftypes.NewRandB32()}}, true, nil)
+	mdi.On("UpdateMessages", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("UpsertBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("UpdateBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("GetNodes", mock.Anything, mock.Anything).Return([]*fftypes.Node{}, nil, nil)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		ctx := a.Get(0).(context.Context)
+		fn := a.Get(1).(func(context.Context) error)
+		rag.ReturnArguments = mock.Arguments{fn(ctx)}
+	}
+	mdi.On("UpdateOffset", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+		cancelCtx() // so we only go round once
+		bm.Close()
+	})
+
+	bm.messageSequencer()
+
+	inspector.mux.Lock()
+	defer inspector.mux.Unlock()
+	assert.Equal(t, []*fftypes.Message{high, low1, low2}, inspector.queued)
+	assert.Equal(t, int64(3), bm.offset)
+}
+
+func TestPrioritizeMessagesKeepsContextOrder(t *testing.T) {
+	newMsg := func(author string, priority uint8, topics ...string) *fftypes.Message {
+		return &fftypes.Message{
+			Header: fftypes.MessageHeader{
+				ID:        fftypes.NewUUID(),
+				Namespace: "ns1",
+				Author:    author,
+				Topics:    topics,
+				Priority:  priority,
+			},
+		}
+	}
+	low1 := newMsg("0x12345", 0, "topic1")
+	low2 := newMsg("0x23456", 0, "topic2")
+	low3 := newMsg("0x12345", 0, "topic2", "topic3")
+	low4 := newMsg("0x34567", 0)
+	high1 := newMsg("0x12345", 10, "topic3")
+	high2 := newMsg("0x23456", 10, "topic1")
+	high3 := newMsg("0x34567", 5)
+	msgs := []*fftypes.Message{low1, low2, low3, low4, high1, high2, high3}
+	prioritizeMessages(msgs)
+
+	// high1 stays behind low3 (same author on topic3), and high3 behind low4 (same author with no topics)
+	assert.Equal(t, []*fftypes.Message{high2, low1, low2, low3, high1, low4, high3}, msgs)
+}
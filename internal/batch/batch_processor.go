@@ -22,8 +22,10 @@ import (
 	"database/sql/driver"
 	"encoding/binary"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/hyperledger/firefly/internal/i18n"
 	"github.com/hyperledger/firefly/internal/log"
 	"github.com/hyperledger/firefly/internal/retry"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -47,8 +49,12 @@ type batchProcessorConf struct {
 	namespace          string
 	author             string
 	group              *fftypes.Bytes32
+	groups             fftypes.GroupRefs
 	dispatch           DispatchHandler
+	localNode          func(ctx context.Context) (*fftypes.UUID, error)
 	processorQuiescing func()
+	batchFlushed       func(batch *fftypes.Batch)
+	batchDispatched    func(batch *fftypes.Batch)
 }
 
 type batchProcessor struct {
@@ -63,6 +69,7 @@ type batchProcessor struct {
 	batchSealed chan bool
 	retry       *retry.Retry
 	conf        *batchProcessorConf
+	closeWork   sync.Once
 }
 
 func newBatchProcessor(ctx context.Context, di database.Plugin, conf *batchProcessorConf, retry *retry.Retry) *batchProcessor {
@@ -90,22 +97,30 @@ func newBatchProcessor(ctx context.Context, di database.Plugin, conf *batchProce
 // calling back each piece of work once persisted into a batch
 // (doesn't wait until that batch is sealed/dispatched).
 // The assemblyLoop seals batches when they are full, or timeout.
+// A batch is also sealed early if any message in it has a deadline that
+// falls before the batch timeout - the tightest deadline in the batch wins.
 func (bp *batchProcessor) assemblyLoop() {
 	defer bp.close()
 	defer close(bp.sealBatch) // close persitenceLoop when we exit
 	l := log.L(bp.ctx)
 	var batchSize uint
 	var lastBatchSealed = time.Now()
+	var batchDeadline time.Time
 	var quiescing bool
 	for {
 		// We timeout waiting at the point we think we're ready for disposal,
 		// unless we've started a batch in which case we wait for what's left
-		// of the batch timeout
+		// of the batch timeout (or until the earliest message deadline)
 		timeToWait := bp.conf.DisposeTimeout
 		if quiescing {
 			timeToWait = 100 * time.Millisecond
 		} else if batchSize > 0 {
 			timeToWait = bp.conf.BatchTimeout - time.Since(lastBatchSealed)
+			if !batchDeadline.IsZero() {
+				if untilDeadline := time.Until(batchDeadline); untilDeadline < timeToWait {
+					timeToWait = untilDeadline
+				}
+			}
 		}
 		timeout := time.NewTimer(timeToWait)
 
@@ -117,6 +132,12 @@ func (bp *batchProcessor) assemblyLoop() {
 		case work, ok := <-bp.newWork:
 			if ok && !work.abandoned {
 				batchSize++
+				if work.msg != nil && work.msg.Deadline != nil {
+					deadline := time.Time(*work.msg.Deadline)
+					if batchDeadline.IsZero() || deadline.Before(batchDeadline) {
+						batchDeadline = deadline
+					}
+				}
 				bp.persistWork <- work
 			} else {
 				closed = true
@@ -125,7 +146,8 @@ func (bp *batchProcessor) assemblyLoop() {
 
 		// Don't include the sealing time in the duration
 		batchFull := batchSize >= bp.conf.BatchMaxSize
-		l.Debugf("Assembly batch loop: Size=%d Full=%t", batchSize, batchFull)
+		deadlineReached := !batchDeadline.IsZero() && !time.Now().Before(batchDeadline)
+		l.Debugf("Assembly batch loop: Size=%d Full=%t DeadlineReached=%t", batchSize, batchFull, deadlineReached)
 
 		batchDuration := time.Since(lastBatchSealed)
 		if quiescing && batchSize == 0 {
@@ -138,11 +160,12 @@ func (bp *batchProcessor) assemblyLoop() {
 			quiescing = true
 		}
 
-		if (quiescing || timedOut || batchFull) && batchSize > 0 {
+		if (quiescing || timedOut || batchFull || deadlineReached) && batchSize > 0 {
 			bp.sealBatch <- true
 			<-bp.batchSealed
 			l.Debugf("Assembly batch sealed")
 			lastBatchSealed = time.Now()
+			batchDeadline = time.Time{}
 			batchSize = 0
 		}
 
@@ -155,12 +178,13 @@ func (bp *batchProcessor) createOrAddToBatch(batch *fftypes.Batch, newWork []*ba
 		batchID := fftypes.NewUUID()
 		l.Debugf("New batch %s", batchID)
 		batch = &fftypes.Batch{
-			ID:        batchID,
-			Namespace: bp.conf.namespace,
-			Author:    bp.conf.author,
-			Group:     bp.conf.group,
-			Payload:   fftypes.BatchPayload{},
-			Created:   fftypes.Now(),
+			ID:            batchID,
+			Namespace:     bp.conf.namespace,
+			Author:        bp.conf.author,
+			SchemaVersion: fftypes.BatchSchemaVersion,
+			Group:         bp.conf.group,
+			Payload:       fftypes.BatchPayload{Groups: bp.conf.groups},
+			Created:       fftypes.Now(),
 		}
 	}
 	for _, w := range newWork {
@@ -169,12 +193,20 @@ func (bp *batchProcessor) createOrAddToBatch(batch *fftypes.Batch, newWork []*ba
 			w.msg.Local = false
 			batch.Payload.Messages = append(batch.Payload.Messages, w.msg)
 		}
-		batch.Payload.Data = append(batch.Payload.Data, w.data...)
+		for _, d := range w.data {
+			// The expiry of data is local to this node, so is not shared with the other members of the network
+			if d.ExpiresAt != nil {
+				dCopy := *d
+				dCopy.ExpiresAt = nil
+				d = &dCopy
+			}
+			batch.Payload.Data = append(batch.Payload.Data, d)
+		}
 	}
 	return batch
 }
 
-func (bp *batchProcessor) maskContext(ctx context.Context, msg *fftypes.Message, topic string) (contextOrPin *fftypes.Bytes32, err error) {
+func (bp *batchProcessor) maskContext(ctx context.Context, msg *fftypes.Message, group *fftypes.Bytes32, topic string) (contextOrPin *fftypes.Bytes32, err error) {
 
 	hashBuilder := sha256.New()
 	hashBuilder.Write([]byte(topic))
@@ -182,13 +214,13 @@ func (bp *batchProcessor) maskContext(ctx context.Context, msg *fftypes.Message,
 	// For broadcast we do not need to mask the context, which is just the hash
 	// of the topic. There would be no way to unmask it if we did, because we don't have
 	// the full list of senders to know what their next hashes should be.
-	if msg.Header.Group == nil {
+	if group == nil {
 		return fftypes.HashResult(hashBuilder), nil
 	}
 
 	// For private groups, we need to make the topic specific to the group (which is
 	// a salt for the hash as it is not on chain)
-	hashBuilder.Write((*msg.Header.Group)[:])
+	hashBuilder.Write((*group)[:])
 
 	// The combination of the topic and group is the context
 	contextHash := fftypes.HashResult(hashBuilder)
@@ -197,7 +229,7 @@ func (bp *batchProcessor) maskContext(ctx context.Context, msg *fftypes.Message,
 	// as we are the sender.
 	gc := &fftypes.Nonce{
 		Context: contextHash,
-		Group:   msg.Header.Group,
+		Group:   group,
 		Topic:   topic,
 	}
 	err = bp.database.UpsertNonceNext(ctx, gc)
@@ -220,7 +252,7 @@ func (bp *batchProcessor) maskContexts(ctx context.Context, batch *fftypes.Batch
 	contextsOrPins := make([]*fftypes.Bytes32, 0, len(batch.Payload.Messages))
 	for _, msg := range batch.Payload.Messages {
 		for _, topic := range msg.Header.Topics {
-			contextOrPin, err := bp.maskContext(ctx, msg, topic)
+			contextOrPin, err := bp.maskContext(ctx, msg, msg.Header.Group, topic)
 			if err != nil {
 				return nil, err
 			}
@@ -230,18 +262,75 @@ func (bp *batchProcessor) maskContexts(ctx context.Context, batch *fftypes.Batch
 			}
 		}
 	}
+	// Batches delivered to additional groups carry a pin for each message topic in each of those
+	// groups, after the pins of the batch group, so they are all submitted in one blockchain transaction.
+	// The pins are recorded on the messages in the same order, so receivers can match them.
+	for _, group := range batch.AdditionalGroups() {
+		for _, msg := range batch.Payload.Messages {
+			for _, topic := range msg.Header.Topics {
+				pin, err := bp.maskContext(ctx, msg, group, topic)
+				if err != nil {
+					return nil, err
+				}
+				contextsOrPins = append(contextsOrPins, pin)
+				msg.Pins = append(msg.Pins, pin.String())
+			}
+		}
+	}
 	return contextsOrPins, nil
 }
 
 func (bp *batchProcessor) dispatchBatch(batch *fftypes.Batch, pins []*fftypes.Bytes32) {
+	// The batch, and every message in it, must belong to the namespace this processor is configured for.
+	// Neither a namespace mismatch nor a payload that fails validation can succeed on a retry, so the
+	// batch is dropped and its messages are failed, rather than being left pending
+	err := bp.validateNamespace(batch)
+	if err == nil {
+		err = batch.Payload.Validate(bp.ctx)
+	}
+	if err != nil {
+		log.L(bp.ctx).Errorf("Batch %s cannot be dispatched: %s", batch.ID, err)
+		bp.failMessages(batch, err)
+		return
+	}
+
 	// Call the dispatcher to do the heavy lifting - will only exit if we're closed
-	_ = bp.retry.Do(bp.ctx, "batch dispatch", func(attempt int) (retry bool, err error) {
+	err = bp.retry.Do(bp.ctx, "batch dispatch", func(attempt int) (retry bool, err error) {
 		err = bp.conf.dispatch(bp.ctx, batch, pins)
 		if err != nil {
 			return !bp.closed, err
 		}
 		return false, nil
 	})
+	if err == nil {
+		bp.conf.batchDispatched(batch)
+	}
+}
+
+func (bp *batchProcessor) validateNamespace(batch *fftypes.Batch) error {
+	if err := batch.ValidateNamespace(bp.ctx, bp.conf.namespace); err != nil {
+		return err
+	}
+	for _, msg := range batch.Payload.Messages {
+		if msg.Header.Namespace != batch.Namespace {
+			return i18n.NewError(bp.ctx, i18n.MsgMessageNamespaceMismatch, msg.Header.ID, msg.Header.Namespace, batch.Namespace)
+		}
+	}
+	return nil
+}
+
+// failMessages records the reason a batch could not be dispatched as the error of each of its messages
+func (bp *batchProcessor) failMessages(batch *fftypes.Batch, reason error) {
+	msgIDs := make([]driver.Value, 0, len(batch.Payload.Messages))
+	for _, msg := range batch.Payload.Messages {
+		msgIDs = append(msgIDs, msg.Header.ID)
+	}
+	filter := database.MessageQueryFactory.NewFilter(bp.ctx).In("id", msgIDs)
+	update := database.MessageQueryFactory.NewUpdate(bp.ctx).Set("error", reason.Error())
+	_ = bp.retry.Do(bp.ctx, "batch fail", func(attempt int) (retry bool, err error) {
+		err = bp.database.UpdateMessages(bp.ctx, filter, update)
+		return err != nil && !bp.closed, err
+	})
 }
 
 func (bp *batchProcessor) persistBatch(batch *fftypes.Batch, newWork []*batchWork, seal bool) (contexts []*fftypes.Bytes32, err error) {
@@ -267,12 +356,17 @@ func (bp *batchProcessor) persistBatch(batch *fftypes.Batch, newWork []*batchWor
 					Type: fftypes.TransactionTypeBatchPin,
 					ID:   fftypes.NewUUID(),
 				}
-				contexts, err = bp.maskContexts(ctx, batch)
+				// Record which node in the org is dispatching the batch, for auditing
+				batch.CreatorNodeID, err = bp.conf.localNode(ctx)
+				if err == nil {
+					contexts, err = bp.maskContexts(ctx, batch)
+				}
 				batch.Hash = batch.Payload.Hash()
 				log.L(ctx).Debugf("Batch %s sealed. Hash=%s", batch.ID, batch.Hash)
 			}
 			if err == nil {
-				// Persist the batch itself
+				// Persist the batch itself, with the tags of all the messages it contains for searchability
+				batch.Tags = batch.Payload.MessageTags()
 				err = bp.database.UpsertBatch(ctx, batch, seal /* we set the hash as it seals */)
 			}
 			return err
@@ -349,6 +443,7 @@ func (bp *batchProcessor) persistenceLoop() {
 			// they start blocking waiting for us to complete database of
 			// the current batch.
 			bp.batchSealed <- true
+			bp.conf.batchFlushed(currentBatch)
 
 			// Synchronously dispatch the batch. Must be last thing we do in the loop, as we
 			// will break out of the retry in the case that we close
@@ -366,11 +461,19 @@ func (bp *batchProcessor) close() {
 	if !bp.closed {
 		// We don't cancel the context here, as we use close during quiesce and don't want the
 		// persistence loop to have its context cancelled, and fail to perform DB operations
-		close(bp.newWork)
+		bp.drain()
 		bp.closed = true
 	}
 }
 
+// drain stops the processor accepting new work, but unlike close it leaves the persistence
+// loop free to retry the sealing and dispatch of any in-flight batch until it succeeds
+func (bp *batchProcessor) drain() {
+	bp.closeWork.Do(func() {
+		close(bp.newWork)
+	})
+}
+
 func (bp *batchProcessor) waitClosed() {
 	<-bp.sealBatch
 	<-bp.batchSealed
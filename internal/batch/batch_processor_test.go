@@ -17,6 +17,7 @@ package batch
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"github.com/hyperledger/firefly/internal/log"
 	"github.com/hyperledger/firefly/internal/retry"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -35,7 +37,10 @@ func newTestBatchProcessor(dispatch DispatchHandler) (*databasemocks.Plugin, *ba
 		namespace:          "ns1",
 		author:             "0x12345",
 		dispatch:           dispatch,
+		localNode:          func(ctx context.Context) (*fftypes.UUID, error) { return nil, nil },
 		processorQuiescing: func() {},
+		batchFlushed:       func(batch *fftypes.Batch) {},
+		batchDispatched:    func(batch *fftypes.Batch) {},
 		Options: Options{
 			BatchMaxSize:   10,
 			BatchTimeout:   10 * time.Millisecond,
@@ -78,7 +83,7 @@ func TestUnfilledBatch(t *testing.T) {
 	for i := 0; i < 5; i++ {
 		msgid := fftypes.NewUUID()
 		work[i] = &batchWork{
-			msg:        &fftypes.Message{Header: fftypes.MessageHeader{ID: msgid}},
+			msg:        &fftypes.Message{Header: fftypes.MessageHeader{ID: msgid, Namespace: "ns1", Tag: fmt.Sprintf("tag%d", i%2)}},
 			dispatched: make(chan *batchDispatch),
 		}
 	}
@@ -101,6 +106,57 @@ func TestUnfilledBatch(t *testing.T) {
 
 	// Check we got all the messages in a single batch
 	assert.Equal(t, len(dispatched[0].Payload.Messages), 5)
+	assert.Equal(t, []string{"tag0", "tag1"}, dispatched[0].Tags)
+	assert.Equal(t, fftypes.TransactionTypeBatchPin, dispatched[0].Payload.TX.Type)
+
+	bp.close()
+	bp.waitClosed()
+
+}
+
+func TestUnfilledBatchSealedAtEarliestDeadline(t *testing.T) {
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	dispatched := []*fftypes.Batch{}
+	mdi, bp := newTestBatchProcessor(func(c context.Context, b *fftypes.Batch, s []*fftypes.Bytes32) error {
+		dispatched = append(dispatched, b)
+		wg.Done()
+		return nil
+	})
+	bp.conf.BatchTimeout = 1 * time.Hour   // Must be sealed by the deadline
+	bp.conf.DisposeTimeout = 1 * time.Hour // Must not quiesce
+	mockRunAsGroupPassthrough(mdi)
+	mdi.On("UpdateMessages", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("UpsertBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("UpdateBatch", mock.Anything, mock.Anything).Return(nil)
+
+	lateDeadline := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	earlyDeadline := fftypes.FFTime(time.Now().Add(10 * time.Millisecond))
+	work := []*batchWork{
+		{
+			msg:        &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1"}, Deadline: &lateDeadline},
+			dispatched: make(chan *batchDispatch),
+		},
+		{
+			msg:        &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1"}, Deadline: &earlyDeadline},
+			dispatched: make(chan *batchDispatch),
+		},
+	}
+	go func() {
+		for _, w := range work {
+			<-w.dispatched
+		}
+		wg.Done()
+	}()
+	for _, w := range work {
+		bp.newWork <- w
+	}
+
+	wg.Wait()
+
+	assert.Len(t, dispatched, 1)
+	assert.Len(t, dispatched[0].Payload.Messages, 2)
 
 	bp.close()
 	bp.waitClosed()
@@ -134,12 +190,12 @@ func TestFilledBatchSlowPersistence(t *testing.T) {
 		msgid := fftypes.NewUUID()
 		if i%2 == 0 {
 			work[i] = &batchWork{
-				msg:        &fftypes.Message{Header: fftypes.MessageHeader{ID: msgid}},
+				msg:        &fftypes.Message{Header: fftypes.MessageHeader{ID: msgid, Namespace: "ns1"}},
 				dispatched: make(chan *batchDispatch),
 			}
 		} else {
 			work[i] = &batchWork{
-				data:       []*fftypes.Data{{ID: msgid}},
+				data:       []*fftypes.Data{{ID: msgid, Hash: fftypes.NewRandB32(), ExpiresAt: fftypes.Now()}},
 				dispatched: make(chan *batchDispatch),
 			}
 		}
@@ -169,6 +225,10 @@ func TestFilledBatchSlowPersistence(t *testing.T) {
 	// Check we got all the messages in a single batch
 	assert.Equal(t, len(dispatched[0].Payload.Messages), 5)
 	assert.Equal(t, len(dispatched[0].Payload.Data), 5)
+	for _, d := range dispatched[0].Payload.Data {
+		assert.Nil(t, d.ExpiresAt)
+	}
+	assert.NotNil(t, work[1].data[0].ExpiresAt)
 
 	bp.close()
 	bp.waitClosed()
@@ -180,7 +240,80 @@ func TestCloseToUnblockDispatch(t *testing.T) {
 		return fmt.Errorf("pop")
 	})
 	bp.close()
-	bp.dispatchBatch(&fftypes.Batch{}, []*fftypes.Bytes32{})
+	bp.dispatchBatch(&fftypes.Batch{
+		Namespace: "ns1",
+		Payload:   fftypes.BatchPayload{TX: fftypes.TransactionRef{ID: fftypes.NewUUID()}},
+	}, []*fftypes.Bytes32{})
+}
+
+func TestDispatchBatchNamespaceMismatch(t *testing.T) {
+	mdi, bp := newTestBatchProcessor(func(c context.Context, b *fftypes.Batch, s []*fftypes.Bytes32) error {
+		panic("should not be dispatched")
+	})
+	defer bp.close()
+	bp.conf.batchDispatched = func(batch *fftypes.Batch) {
+		panic("should not be marked dispatched")
+	}
+	mdi.On("UpdateMessages", mock.Anything, mock.Anything, mock.MatchedBy(func(update database.Update) bool {
+		info, _ := update.Finalize()
+		v, _ := info.SetOperations[0].Value.Value()
+		return info.SetOperations[0].Field == "error" && strings.Contains(v.(string), "FF10338")
+	})).Return(nil).Once()
+
+	// Returns without retrying, while the processor is still running, and the messages are failed
+	bp.dispatchBatch(&fftypes.Batch{
+		Namespace: "ns2",
+		Payload: fftypes.BatchPayload{
+			Messages: []*fftypes.Message{{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns2"}}},
+		},
+	}, []*fftypes.Bytes32{})
+	mdi.AssertExpectations(t)
+}
+
+func TestDispatchBatchMessageNamespaceMismatch(t *testing.T) {
+	mdi, bp := newTestBatchProcessor(func(c context.Context, b *fftypes.Batch, s []*fftypes.Bytes32) error {
+		panic("should not be dispatched")
+	})
+	defer bp.close()
+	mdi.On("UpdateMessages", mock.Anything, mock.Anything, mock.MatchedBy(func(update database.Update) bool {
+		info, _ := update.Finalize()
+		v, _ := info.SetOperations[0].Value.Value()
+		return info.SetOperations[0].Field == "error" && strings.Contains(v.(string), "FF10359")
+	})).Return(nil).Once()
+
+	bp.dispatchBatch(&fftypes.Batch{
+		Namespace: "ns1",
+		Payload: fftypes.BatchPayload{
+			Messages: []*fftypes.Message{{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns2"}}},
+		},
+	}, []*fftypes.Bytes32{})
+	mdi.AssertExpectations(t)
+}
+
+func TestDispatchBatchInvalidPayload(t *testing.T) {
+	mdi, bp := newTestBatchProcessor(func(c context.Context, b *fftypes.Batch, s []*fftypes.Bytes32) error {
+		panic("should not be dispatched")
+	})
+	defer bp.close()
+	bp.conf.batchDispatched = func(batch *fftypes.Batch) {
+		panic("should not be marked dispatched")
+	}
+	mdi.On("UpdateMessages", mock.Anything, mock.Anything, mock.MatchedBy(func(update database.Update) bool {
+		info, _ := update.Finalize()
+		v, _ := info.SetOperations[0].Value.Value()
+		return info.SetOperations[0].Field == "error" && strings.Contains(v.(string), "FF10287")
+	})).Return(fmt.Errorf("pop")).Once()
+	mdi.On("UpdateMessages", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+	// The batch has no transaction, so is never dispatched, and its messages are failed
+	bp.dispatchBatch(&fftypes.Batch{
+		Namespace: "ns1",
+		Payload: fftypes.BatchPayload{
+			Messages: []*fftypes.Message{{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1"}}},
+		},
+	}, []*fftypes.Bytes32{})
+
+	mdi.AssertExpectations(t)
 }
 
 func TestCloseToUnblockUpsertBatch(t *testing.T) {
@@ -205,7 +338,7 @@ func TestCloseToUnblockUpsertBatch(t *testing.T) {
 	// Generate the work the work
 	msgid := fftypes.NewUUID()
 	work := &batchWork{
-		msg:        &fftypes.Message{Header: fftypes.MessageHeader{ID: msgid}},
+		msg:        &fftypes.Message{Header: fftypes.MessageHeader{ID: msgid, Namespace: "ns1"}},
 		dispatched: make(chan *batchDispatch),
 	}
 
@@ -244,3 +377,82 @@ func TestCalcPinsFail(t *testing.T) {
 	})
 	assert.Regexp(t, "pop", err)
 }
+
+func TestCalcPinsMultipleGroups(t *testing.T) {
+	_, bp := newTestBatchProcessor(func(c context.Context, b *fftypes.Batch, s []*fftypes.Bytes32) error {
+		return nil
+	})
+	defer bp.close()
+	mdi := bp.database.(*databasemocks.Plugin)
+	mdi.On("UpsertNonceNext", mock.Anything, mock.Anything).Return(nil)
+
+	gid1 := fftypes.NewRandB32()
+	gid2 := fftypes.NewRandB32()
+	gid3 := fftypes.NewRandB32()
+	msg := &fftypes.Message{Header: fftypes.MessageHeader{
+		Group:  gid1,
+		Topics: fftypes.FFNameArray{"topic1", "topic2"},
+	}}
+	pins, err := bp.maskContexts(bp.ctx, &fftypes.Batch{
+		Group: gid1,
+		Payload: fftypes.BatchPayload{
+			Messages: []*fftypes.Message{msg},
+			Groups:   []fftypes.GroupRef{{Hash: gid1}, {Hash: gid2}, {Hash: gid3}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, pins, 6)
+	// All pins are recorded on the message, with the message group's pins first
+	assert.Equal(t, fftypes.FFNameArray{
+		pins[0].String(), pins[1].String(),
+		pins[2].String(), pins[3].String(),
+		pins[4].String(), pins[5].String(),
+	}, msg.Pins)
+	for _, gid := range []*fftypes.Bytes32{gid1, gid2, gid3} {
+		mdi.AssertCalled(t, "UpsertNonceNext", mock.Anything, mock.MatchedBy(func(n *fftypes.Nonce) bool {
+			return *n.Group == *gid && n.Topic == "topic2"
+		}))
+	}
+}
+
+func TestCalcPinsMultipleGroupsFail(t *testing.T) {
+	_, bp := newTestBatchProcessor(func(c context.Context, b *fftypes.Batch, s []*fftypes.Bytes32) error {
+		return nil
+	})
+	defer bp.close()
+	mdi := bp.database.(*databasemocks.Plugin)
+	gid1 := fftypes.NewRandB32()
+	gid2 := fftypes.NewRandB32()
+	mdi.On("UpsertNonceNext", mock.Anything, mock.MatchedBy(func(n *fftypes.Nonce) bool {
+		return *n.Group == *gid1
+	})).Return(nil)
+	mdi.On("UpsertNonceNext", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := bp.maskContexts(bp.ctx, &fftypes.Batch{
+		Group: gid1,
+		Payload: fftypes.BatchPayload{
+			Messages: []*fftypes.Message{
+				{Header: fftypes.MessageHeader{
+					Group:  gid1,
+					Topics: fftypes.FFNameArray{"topic1"},
+				}},
+			},
+			Groups: []fftypes.GroupRef{{Hash: gid2}},
+		},
+	})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestPersistBatchResolveLocalNodeFail(t *testing.T) {
+	_, bp := newTestBatchProcessor(func(c context.Context, b *fftypes.Batch, s []*fftypes.Bytes32) error {
+		return nil
+	})
+	mdi := bp.database.(*databasemocks.Plugin)
+	mockRunAsGroupPassthrough(mdi)
+	bp.conf.localNode = func(ctx context.Context) (*fftypes.UUID, error) {
+		return nil, fmt.Errorf("pop")
+	}
+	bp.close()
+	_, err := bp.persistBatch(&fftypes.Batch{ID: fftypes.NewUUID()}, []*batchWork{}, true)
+	assert.Regexp(t, "pop", err)
+}
@@ -0,0 +1,54 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// Inspector observes batch assembly, without any ability to affect dispatch.
+// Callbacks are made synchronously on the batch assembly routines, so must not block.
+type Inspector interface {
+	// OnMessageQueued is called when a message has been handed to a batch processor for assembly
+	OnMessageQueued(msg *fftypes.Message)
+	// OnBatchFlushed is called when a batch has been sealed, immediately before it is dispatched
+	OnBatchFlushed(batch *fftypes.Batch)
+}
+
+// LoggingInspector is an Inspector that logs batch assembly at debug level
+type LoggingInspector struct {
+	ctx context.Context
+}
+
+func NewLoggingInspector(ctx context.Context) *LoggingInspector {
+	return &LoggingInspector{
+		ctx: log.WithLogField(ctx, "role", "batch-inspector"),
+	}
+}
+
+func (li *LoggingInspector) OnMessageQueued(msg *fftypes.Message) {
+	log.L(li.ctx).Debugf("Message queued: id=%s type=%s namespace=%s author=%s group=%v pinned=%t",
+		msg.Header.ID, msg.Header.Type, msg.Header.Namespace, msg.Header.Author, msg.Header.Group, msg.Pinned)
+}
+
+func (li *LoggingInspector) OnBatchFlushed(batch *fftypes.Batch) {
+	log.L(li.ctx).Debugf("Batch flushed: id=%s type=%s namespace=%s author=%s group=%v messages=%d data=%d",
+		batch.ID, batch.Type, batch.Namespace, batch.Author, batch.Group, len(batch.Payload.Messages), len(batch.Payload.Data))
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/internal/nsplugins"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
@@ -34,27 +35,41 @@ type batchPinSubmitter struct {
 	database   database.Plugin
 	identity   identity.Plugin
 	blockchain blockchain.Plugin
+	nsPlugins  nsplugins.Overrides
 }
 
-func NewBatchPinSubmitter(di database.Plugin, ii identity.Plugin, bi blockchain.Plugin) Submitter {
+func NewBatchPinSubmitter(di database.Plugin, ii identity.Plugin, bi blockchain.Plugin, np nsplugins.Overrides) Submitter {
 	return &batchPinSubmitter{
 		database:   di,
 		identity:   ii,
 		blockchain: bi,
+		nsPlugins:  np,
 	}
 }
 
 func (bp *batchPinSubmitter) SubmitPinnedBatch(ctx context.Context, batch *fftypes.Batch, contexts []*fftypes.Bytes32) error {
 
+	// The batch is pinned by the blockchain plugin of its namespace, which might be overridden in its configuration
+	bi := bp.nsPlugins.Blockchain(batch.Namespace, bp.blockchain)
+
 	signingIdentity, err := bp.identity.Resolve(ctx, batch.Author)
 	if err == nil {
-		err = bp.blockchain.VerifyIdentitySyntax(ctx, signingIdentity)
+		err = bi.VerifyIdentitySyntax(ctx, signingIdentity)
 	}
 	if err != nil {
 		log.L(ctx).Errorf("Invalid signing identity '%s': %s", batch.Author, err)
 		return err
 	}
 
+	pin := &blockchain.BatchPin{
+		Namespace:      batch.Namespace,
+		TransactionID:  batch.Payload.TX.ID,
+		BatchID:        batch.ID,
+		BatchHash:      batch.Hash,
+		BatchPaylodRef: batch.PayloadRef,
+		Contexts:       contexts,
+	}
+
 	tx := &fftypes.Transaction{
 		ID: batch.Payload.TX.ID,
 		Subject: fftypes.TransactionSubject{
@@ -67,6 +82,18 @@ func (bp *batchPinSubmitter) SubmitPinnedBatch(ctx context.Context, batch *fftyp
 		Status:  fftypes.OpStatusPending,
 	}
 	tx.Hash = tx.Subject.Hash()
+
+	// The estimated gas is submitted with the batch pin, and recorded on the transaction. Estimation is
+	// best effort, and if it fails the pin is submitted without gas, for the connector to decide
+	gasLimit, gasPrice, err := bi.EstimateGas(ctx, signingIdentity, pin)
+	if err != nil {
+		log.L(ctx).Warnf("Unable to estimate gas for batch %s - submitting without it: %s", batch.ID, err)
+	} else {
+		pin.GasLimit, pin.GasPrice = gasLimit, gasPrice
+		tx.GasLimit = fftypes.NewHexBigInt(gasLimit)
+		tx.GasPrice = fftypes.NewHexBigInt(gasPrice)
+	}
+
 	err = bp.database.UpsertTransaction(ctx, tx, false /* should be new, or idempotent replay */)
 	if err != nil {
 		return err
@@ -74,25 +101,27 @@ func (bp *batchPinSubmitter) SubmitPinnedBatch(ctx context.Context, batch *fftyp
 
 	// The pending blockchain transaction
 	op := fftypes.NewTXOperation(
-		bp.blockchain,
+		bi,
 		batch.Namespace,
 		batch.Payload.TX.ID,
 		"",
 		fftypes.OpTypeBlockchainBatchPin,
 		fftypes.OpStatusPending,
-		"")
+		"",
+		fftypes.GetCreatedBy(ctx))
+	op.Priority = batch.Payload.Priority()
 	err = bp.database.UpsertOperation(ctx, op, false)
 	if err != nil {
 		return err
 	}
 
+	// Record how many pins the batch is submitted with
+	batch.PinCount = len(contexts)
+	err = bp.database.UpdateBatch(ctx, batch.ID, database.BatchQueryFactory.NewUpdate(ctx).Set("pincount", batch.PinCount))
+	if err != nil {
+		return err
+	}
+
 	// Write the batch pin to the blockchain
-	return bp.blockchain.SubmitBatchPin(ctx, op.ID, nil /* TODO: ledger selection */, signingIdentity, &blockchain.BatchPin{
-		Namespace:      batch.Namespace,
-		TransactionID:  batch.Payload.TX.ID,
-		BatchID:        batch.ID,
-		BatchHash:      batch.Hash,
-		BatchPaylodRef: batch.PayloadRef,
-		Contexts:       contexts,
-	})
+	return bi.SubmitBatchPin(ctx, op.ID, nil /* TODO: ledger selection */, signingIdentity, pin)
 }
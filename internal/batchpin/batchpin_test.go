@@ -19,11 +19,15 @@ package batchpin
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/hyperledger/firefly/internal/nsplugins"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/identitymocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -34,7 +38,7 @@ func newTestBatchPinSubmitter(t *testing.T) *batchPinSubmitter {
 	mii := &identitymocks.Plugin{}
 	mbi := &blockchainmocks.Plugin{}
 	mbi.On("Name").Return("ut").Maybe()
-	return NewBatchPinSubmitter(mdi, mii, mbi).(*batchPinSubmitter)
+	return NewBatchPinSubmitter(mdi, mii, mbi, nil).(*batchPinSubmitter)
 }
 
 func TestSubmitPinnedBatchOk(t *testing.T) {
@@ -59,22 +63,203 @@ func TestSubmitPinnedBatchOk(t *testing.T) {
 			},
 		},
 	}
-	contexts := []*fftypes.Bytes32{}
+	contexts := []*fftypes.Bytes32{fftypes.NewRandB32(), fftypes.NewRandB32()}
 
 	mii.On("Resolve", ctx, "id1").Return(identity, nil)
 	mbi.On("VerifyIdentitySyntax", ctx, identity).Return(nil)
-	mdi.On("UpsertTransaction", ctx, mock.Anything, false).Return(nil)
+	mbi.On("EstimateGas", ctx, identity, mock.MatchedBy(func(pin *blockchain.BatchPin) bool {
+		return *pin.BatchID == *batch.ID && len(pin.Contexts) == 2
+	})).Return(big.NewInt(21000), big.NewInt(20000000000), nil)
+	mdi.On("UpsertTransaction", ctx, mock.MatchedBy(func(tx *fftypes.Transaction) bool {
+		return tx.GasLimit.Int().Int64() == 21000 && tx.GasPrice.Int().Int64() == 20000000000
+	}), false).Return(nil)
 	mdi.On("UpsertOperation", ctx, mock.MatchedBy(func(op *fftypes.Operation) bool {
 		assert.Equal(t, fftypes.OpTypeBlockchainBatchPin, op.Type)
 		assert.Equal(t, "ut", op.Plugin)
 		assert.Equal(t, *batch.Payload.TX.ID, *op.Transaction)
 		return true
 	}), false).Return(nil)
-	mbi.On("SubmitBatchPin", ctx, mock.Anything, (*fftypes.UUID)(nil), identity, mock.Anything).Return(nil)
+	mdi.On("UpdateBatch", ctx, batch.ID, mock.MatchedBy(func(u database.Update) bool {
+		info, _ := u.Finalize()
+		v, _ := info.SetOperations[0].Value.Value()
+		return info.SetOperations[0].Field == "pincount" && v == int64(2)
+	})).Return(nil)
+	mbi.On("SubmitBatchPin", ctx, mock.Anything, (*fftypes.UUID)(nil), identity, mock.MatchedBy(func(pin *blockchain.BatchPin) bool {
+		return pin.GasLimit.Int64() == 21000 && pin.GasPrice.Int64() == 20000000000
+	})).Return(nil)
+
+	err := bp.SubmitPinnedBatch(ctx, batch, contexts)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, batch.PinCount)
+
+}
+
+func TestSubmitPinnedBatchNamespaceOverride(t *testing.T) {
+
+	bp := newTestBatchPinSubmitter(t)
+	ctx := context.Background()
+
+	mii := bp.identity.(*identitymocks.Plugin)
+	mdi := bp.database.(*databasemocks.Plugin)
+	mbi2 := &blockchainmocks.Plugin{}
+	mbi2.On("Name").Return("ut2")
+	bp.nsPlugins = nsplugins.Overrides{"ns1": {Blockchain: mbi2}}
+
+	identity := &fftypes.Identity{
+		Identifier: "id1",
+		OnChain:    "0x12345",
+	}
+	batch := &fftypes.Batch{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Author:    "id1",
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{
+				ID: fftypes.NewUUID(),
+			},
+		},
+	}
+	contexts := []*fftypes.Bytes32{fftypes.NewRandB32()}
+
+	mii.On("Resolve", ctx, "id1").Return(identity, nil)
+	mbi2.On("VerifyIdentitySyntax", ctx, identity).Return(nil)
+	mbi2.On("EstimateGas", ctx, identity, mock.Anything).Return(big.NewInt(21000), big.NewInt(1), nil)
+	mdi.On("UpsertTransaction", ctx, mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", ctx, mock.MatchedBy(func(op *fftypes.Operation) bool {
+		return op.Plugin == "ut2"
+	}), false).Return(nil)
+	mdi.On("UpdateBatch", ctx, batch.ID, mock.Anything).Return(nil)
+	mbi2.On("SubmitBatchPin", ctx, mock.Anything, (*fftypes.UUID)(nil), identity, mock.Anything).Return(nil)
+
+	err := bp.SubmitPinnedBatch(ctx, batch, contexts)
+	assert.NoError(t, err)
+
+	mbi2.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestSubmitPinnedBatchEstimateGasFail(t *testing.T) {
+
+	bp := newTestBatchPinSubmitter(t)
+	ctx := context.Background()
+
+	mii := bp.identity.(*identitymocks.Plugin)
+	mbi := bp.blockchain.(*blockchainmocks.Plugin)
+	mdi := bp.database.(*databasemocks.Plugin)
+
+	identity := &fftypes.Identity{
+		Identifier: "id1",
+		OnChain:    "0x12345",
+	}
+	batch := &fftypes.Batch{
+		ID:     fftypes.NewUUID(),
+		Author: "id1",
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{
+				ID: fftypes.NewUUID(),
+			},
+		},
+	}
+	contexts := []*fftypes.Bytes32{fftypes.NewRandB32()}
+
+	mii.On("Resolve", ctx, "id1").Return(identity, nil)
+	mbi.On("VerifyIdentitySyntax", ctx, identity).Return(nil)
+	mbi.On("EstimateGas", ctx, identity, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mdi.On("UpsertTransaction", ctx, mock.MatchedBy(func(tx *fftypes.Transaction) bool {
+		return tx.GasLimit == nil && tx.GasPrice == nil
+	}), false).Return(nil)
+	mdi.On("UpsertOperation", ctx, mock.Anything, false).Return(nil)
+	mdi.On("UpdateBatch", ctx, batch.ID, mock.Anything).Return(nil)
+	mbi.On("SubmitBatchPin", ctx, mock.Anything, (*fftypes.UUID)(nil), identity, mock.MatchedBy(func(pin *blockchain.BatchPin) bool {
+		return pin.GasLimit == nil && pin.GasPrice == nil
+	})).Return(nil)
 
+	// The pin is still submitted, without any gas
 	err := bp.SubmitPinnedBatch(ctx, batch, contexts)
 	assert.NoError(t, err)
 
+	mdi.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestSubmitPinnedBatchNoGasPrice(t *testing.T) {
+
+	bp := newTestBatchPinSubmitter(t)
+	ctx := context.Background()
+
+	mii := bp.identity.(*identitymocks.Plugin)
+	mbi := bp.blockchain.(*blockchainmocks.Plugin)
+	mdi := bp.database.(*databasemocks.Plugin)
+
+	identity := &fftypes.Identity{
+		Identifier: "id1",
+		OnChain:    "0x12345",
+	}
+	batch := &fftypes.Batch{
+		ID:     fftypes.NewUUID(),
+		Author: "id1",
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{
+				ID: fftypes.NewUUID(),
+			},
+		},
+	}
+	contexts := []*fftypes.Bytes32{fftypes.NewRandB32()}
+
+	mii.On("Resolve", ctx, "id1").Return(identity, nil)
+	mbi.On("VerifyIdentitySyntax", ctx, identity).Return(nil)
+	mbi.On("EstimateGas", ctx, identity, mock.Anything).Return(big.NewInt(21000), nil, nil)
+	mdi.On("UpsertTransaction", ctx, mock.MatchedBy(func(tx *fftypes.Transaction) bool {
+		return tx.GasLimit.Int().Int64() == 21000 && tx.GasPrice == nil
+	}), false).Return(nil)
+	mdi.On("UpsertOperation", ctx, mock.Anything, false).Return(nil)
+	mdi.On("UpdateBatch", ctx, batch.ID, mock.Anything).Return(nil)
+	mbi.On("SubmitBatchPin", ctx, mock.Anything, (*fftypes.UUID)(nil), identity, mock.MatchedBy(func(pin *blockchain.BatchPin) bool {
+		return pin.GasLimit.Int64() == 21000 && pin.GasPrice == nil
+	})).Return(nil)
+
+	// The estimated gas limit is submitted, leaving the gas price to the connector
+	err := bp.SubmitPinnedBatch(ctx, batch, contexts)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestSubmitPinnedBatchUpdateBatchFail(t *testing.T) {
+
+	bp := newTestBatchPinSubmitter(t)
+	ctx := context.Background()
+
+	mii := bp.identity.(*identitymocks.Plugin)
+	mbi := bp.blockchain.(*blockchainmocks.Plugin)
+	mdi := bp.database.(*databasemocks.Plugin)
+
+	identity := &fftypes.Identity{
+		Identifier: "id1",
+		OnChain:    "0x12345",
+	}
+	batch := &fftypes.Batch{
+		ID:     fftypes.NewUUID(),
+		Author: "id1",
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{
+				ID: fftypes.NewUUID(),
+			},
+		},
+	}
+	contexts := []*fftypes.Bytes32{}
+
+	mii.On("Resolve", ctx, "id1").Return(identity, nil)
+	mbi.On("VerifyIdentitySyntax", ctx, identity).Return(nil)
+	mbi.On("EstimateGas", ctx, identity, mock.Anything).Return(big.NewInt(21000), big.NewInt(1), nil)
+	mdi.On("UpsertTransaction", ctx, mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", ctx, mock.Anything, false).Return(nil)
+	mdi.On("UpdateBatch", ctx, batch.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := bp.SubmitPinnedBatch(ctx, batch, contexts)
+	assert.Regexp(t, "pop", err)
+
 }
 
 func TestSubmitPinnedBatchOpFail(t *testing.T) {
@@ -103,6 +288,7 @@ func TestSubmitPinnedBatchOpFail(t *testing.T) {
 
 	mii.On("Resolve", ctx, "id1").Return(identity, nil)
 	mbi.On("VerifyIdentitySyntax", ctx, identity).Return(nil)
+	mbi.On("EstimateGas", ctx, identity, mock.Anything).Return(big.NewInt(21000), big.NewInt(1), nil)
 	mdi.On("UpsertTransaction", ctx, mock.Anything, false).Return(nil)
 	mdi.On("UpsertOperation", ctx, mock.Anything, false).Return(fmt.Errorf("pop"))
 
@@ -137,6 +323,7 @@ func TestSubmitPinnedBatchTxInsertFail(t *testing.T) {
 
 	mii.On("Resolve", ctx, "id1").Return(identity, nil)
 	mbi.On("VerifyIdentitySyntax", ctx, identity).Return(nil)
+	mbi.On("EstimateGas", ctx, identity, mock.Anything).Return(big.NewInt(21000), big.NewInt(1), nil)
 	mdi.On("UpsertTransaction", ctx, mock.Anything, false).Return(fmt.Errorf("pop"))
 
 	err := bp.SubmitPinnedBatch(ctx, batch, contexts)
@@ -18,6 +18,7 @@ package ethereum
 
 import (
 	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/restclient"
 	"github.com/hyperledger/firefly/internal/wsclient"
 )
 
@@ -49,6 +50,12 @@ const (
 	EthconnectPrefixLong = "prefixLong"
 )
 
+const (
+	// RPCConfigKey is a sub-key in the config for the JSON-RPC endpoint of the ethereum node, used to
+	// query chain state (such as the block number and gas price) that ethconnect does not expose over REST
+	RPCConfigKey = "rpc"
+)
+
 func (e *Ethereum) InitPrefix(prefix config.Prefix) {
 	ethconnectConf := prefix.SubPrefix(EthconnectConfigKey)
 	wsclient.InitPrefix(ethconnectConf)
@@ -59,4 +66,6 @@ func (e *Ethereum) InitPrefix(prefix config.Prefix) {
 	ethconnectConf.AddKnownKey(EthconnectConfigBatchTimeout, defaultBatchTimeout)
 	ethconnectConf.AddKnownKey(EthconnectPrefixShort, defaultPrefixShort)
 	ethconnectConf.AddKnownKey(EthconnectPrefixLong, defaultPrefixLong)
+
+	restclient.InitPrefix(prefix.SubPrefix(RPCConfigKey))
 }
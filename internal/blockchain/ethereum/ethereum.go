@@ -21,7 +21,9 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/go-resty/resty/v2"
@@ -47,6 +49,7 @@ type Ethereum struct {
 	capabilities *blockchain.Capabilities
 	callbacks    blockchain.Callbacks
 	client       *resty.Client
+	rpcClient    *resty.Client
 	initInfo     struct {
 		stream *eventStream
 		subs   []*subscription
@@ -89,6 +92,24 @@ type ethBatchPinInput struct {
 	Contexts   []string `json:"contexts"`
 }
 
+type ethGasEstimate struct {
+	Gas string `json:"gas"`
+}
+
+type ethRPCRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      string        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type ethRPCResponse struct {
+	Result string `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
 type ethWSCommandPayload struct {
 	Type  string `json:"type"`
 	Topic string `json:"topic,omitempty"`
@@ -127,6 +148,12 @@ func (e *Ethereum) Init(ctx context.Context, prefix config.Prefix, callbacks blo
 	e.prefixLong = ethconnectConf.GetString(EthconnectPrefixLong)
 
 	e.client = restclient.New(e.ctx, ethconnectConf)
+
+	// The JSON-RPC endpoint is optional, and only required to query chain state
+	rpcConf := prefix.SubPrefix(RPCConfigKey)
+	if rpcConf.GetString(restclient.HTTPConfigURL) != "" {
+		e.rpcClient = restclient.New(e.ctx, rpcConf)
+	}
 	e.capabilities = &blockchain.Capabilities{
 		GlobalSequencer: true,
 	}
@@ -428,6 +455,16 @@ func (e *Ethereum) eventLoop() {
 }
 
 func (e *Ethereum) VerifyIdentitySyntax(ctx context.Context, identity *fftypes.Identity) (err error) {
+	// Identities without a key type pre-date support for multiple algorithms, so are secp256k1
+	if identity.KeyType == "" {
+		identity.KeyType = fftypes.KeyTypeSecp256k1
+	}
+	if err = fftypes.ValidateKeyType(ctx, identity.KeyType); err != nil {
+		return err
+	}
+	if identity.KeyType != fftypes.KeyTypeSecp256k1 {
+		return i18n.NewError(ctx, i18n.MsgUnsupportedKeyType, identity.KeyType, e.Name())
+	}
 	identity.OnChain, err = e.validateEthAddress(ctx, identity.OnChain)
 	return
 }
@@ -440,19 +477,100 @@ func (e *Ethereum) validateEthAddress(ctx context.Context, identity string) (str
 	return "0x" + identity, nil
 }
 
-func (e *Ethereum) invokeContractMethod(ctx context.Context, method string, identity *fftypes.Identity, requestID string, input interface{}, output interface{}) (*resty.Response, error) {
+func (e *Ethereum) contractMethodRequest(ctx context.Context, identity *fftypes.Identity, requestID string, input interface{}, output interface{}) *resty.Request {
 	return e.client.R().
 		SetContext(ctx).
 		SetQueryParam(e.prefixShort+"-from", identity.OnChain).
 		SetQueryParam(e.prefixShort+"-sync", "false").
 		SetQueryParam(e.prefixShort+"-id", requestID).
 		SetBody(input).
-		SetResult(output).
-		Post(e.instancePath + "/" + method)
+		SetResult(output)
 }
 
-func (e *Ethereum) SubmitBatchPin(ctx context.Context, operationID *fftypes.UUID, ledgerID *fftypes.UUID, identity *fftypes.Identity, batch *blockchain.BatchPin) error {
-	tx := &asyncTXSubmission{}
+// invokeRPC calls a method on the JSON-RPC endpoint of the ethereum node, as the ethconnect
+// REST gateway does not proxy JSON-RPC requests through to the node
+func (e *Ethereum) invokeRPC(ctx context.Context, method string) (string, error) {
+	if e.rpcClient == nil {
+		return "", i18n.NewError(ctx, i18n.MsgEthRPCNotConfigured)
+	}
+	var rpcRes ethRPCResponse
+	res, err := e.rpcClient.R().
+		SetContext(ctx).
+		SetBody(&ethRPCRequest{
+			JSONRPC: "2.0",
+			ID:      fftypes.ShortID(),
+			Method:  method,
+			Params:  []interface{}{},
+		}).
+		SetResult(&rpcRes).
+		Post("")
+	if err != nil || !res.IsSuccess() {
+		return "", restclient.WrapRestErr(ctx, res, err, i18n.MsgEthRPCErr)
+	}
+	if rpcRes.Error != nil {
+		return "", i18n.NewError(ctx, i18n.MsgEthRPCErr, rpcRes.Error.Message)
+	}
+	return rpcRes.Result, nil
+}
+
+func (e *Ethereum) GetBlockNumber(ctx context.Context) (uint64, error) {
+	result, err := e.invokeRPC(ctx, "eth_blockNumber")
+	if err != nil {
+		return 0, err
+	}
+	blockNumber, err := strconv.ParseUint(strings.TrimPrefix(result, "0x"), 16, 64)
+	if err != nil {
+		return 0, i18n.NewError(ctx, i18n.MsgEthInvalidBlockNumber, result)
+	}
+	return blockNumber, nil
+}
+
+// parseGas parses a gas value returned by ethconnect, which is either 0x prefixed hex or decimal
+func parseGas(ctx context.Context, s string) (*big.Int, error) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if strings.HasPrefix(s, "0x") {
+		i, ok = new(big.Int).SetString(s[2:], 16)
+	}
+	if !ok {
+		return nil, i18n.NewError(ctx, i18n.MsgEthInvalidGas, s)
+	}
+	return i, nil
+}
+
+// EstimateGas asks ethconnect to estimate the gas for the pinBatch call, without submitting it,
+// and queries the current gas price of the node if a JSON-RPC endpoint is configured.
+// The gas price is optional, so failing to get it does not discard the estimated gas limit.
+func (e *Ethereum) EstimateGas(ctx context.Context, identity *fftypes.Identity, batch *blockchain.BatchPin) (gasLimit, gasPrice *big.Int, err error) {
+	var estimate ethGasEstimate
+	res, err := e.client.R().
+		SetContext(ctx).
+		SetQueryParam(e.prefixShort+"-from", identity.OnChain).
+		SetQueryParam(e.prefixShort+"-estimategas", "true").
+		SetBody(batchPinInput(batch)).
+		SetResult(&estimate).
+		Post(e.instancePath + "/pinBatch")
+	if err != nil || !res.IsSuccess() {
+		return nil, nil, restclient.WrapRestErr(ctx, res, err, i18n.MsgEthconnectRESTErr)
+	}
+	if gasLimit, err = parseGas(ctx, estimate.Gas); err != nil {
+		return nil, nil, err
+	}
+	if e.rpcClient == nil {
+		return gasLimit, nil, nil
+	}
+
+	result, err := e.invokeRPC(ctx, "eth_gasPrice")
+	if err == nil {
+		gasPrice, err = parseGas(ctx, result)
+	}
+	if err != nil {
+		log.L(ctx).Warnf("Unable to get the gas price - using estimated gas limit %s without it: %s", gasLimit, err)
+		return gasLimit, nil, nil
+	}
+	return gasLimit, gasPrice, nil
+}
+
+func batchPinInput(batch *blockchain.BatchPin) *ethBatchPinInput {
 	ethHashes := make([]string, len(batch.Contexts))
 	for i, v := range batch.Contexts {
 		ethHashes[i] = ethHexFormatB32(v)
@@ -460,14 +578,26 @@ func (e *Ethereum) SubmitBatchPin(ctx context.Context, operationID *fftypes.UUID
 	var uuids fftypes.Bytes32
 	copy(uuids[0:16], (*batch.TransactionID)[:])
 	copy(uuids[16:32], (*batch.BatchID)[:])
-	input := &ethBatchPinInput{
+	return &ethBatchPinInput{
 		Namespace:  batch.Namespace,
 		UUIDs:      ethHexFormatB32(&uuids),
 		BatchHash:  ethHexFormatB32(batch.BatchHash),
 		PayloadRef: batch.BatchPaylodRef,
 		Contexts:   ethHashes,
 	}
-	res, err := e.invokeContractMethod(ctx, "pinBatch", identity, operationID.String(), input, tx)
+}
+
+func (e *Ethereum) SubmitBatchPin(ctx context.Context, operationID *fftypes.UUID, ledgerID *fftypes.UUID, identity *fftypes.Identity, batch *blockchain.BatchPin) error {
+	tx := &asyncTXSubmission{}
+	req := e.contractMethodRequest(ctx, identity, operationID.String(), batchPinInput(batch), tx)
+	// Submit with the gas that was estimated, and recorded on the transaction
+	if batch.GasLimit != nil {
+		req.SetQueryParam(e.prefixShort+"-gas", batch.GasLimit.String())
+	}
+	if batch.GasPrice != nil {
+		req.SetQueryParam(e.prefixShort+"-gasprice", batch.GasPrice.String())
+	}
+	res, err := req.Post(e.instancePath + "/pinBatch")
 	if err != nil || !res.IsSuccess() {
 		return restclient.WrapRestErr(ctx, res, err, i18n.MsgEthconnectRESTErr)
 	}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/url"
 	"testing"
@@ -40,6 +41,7 @@ import (
 
 var utConfPrefix = config.NewPluginConfig("eth_unit_tests")
 var utEthconnectConf = utConfPrefix.SubPrefix(EthconnectConfigKey)
+var utRPCConf = utConfPrefix.SubPrefix(RPCConfigKey)
 
 func resetConf() {
 	config.Reset()
@@ -51,9 +53,11 @@ func newTestEthereum() (*Ethereum, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
 	em := &blockchainmocks.Callbacks{}
 	wsm := &wsmocks.WSClient{}
+	client := resty.New().SetHostURL("http://localhost:12345")
 	e := &Ethereum{
 		ctx:          ctx,
-		client:       resty.New().SetHostURL("http://localhost:12345"),
+		client:       client,
+		rpcClient:    resty.NewWithClient(client.GetClient()).SetHostURL("http://localhost:8545"),
 		instancePath: "/instances/0x12345",
 		topic:        "topic1",
 		prefixShort:  defaultPrefixShort,
@@ -358,6 +362,8 @@ func TestSubmitBatchPinOK(t *testing.T) {
 			fftypes.NewRandB32(),
 			fftypes.NewRandB32(),
 		},
+		GasLimit: big.NewInt(21000),
+		GasPrice: big.NewInt(20000000000),
 	}
 
 	httpmock.RegisterResponder("POST", `http://localhost:12345/instances/0x12345/pinBatch`,
@@ -366,6 +372,8 @@ func TestSubmitBatchPinOK(t *testing.T) {
 			json.NewDecoder(req.Body).Decode(&body)
 			assert.Equal(t, addr, req.FormValue(defaultPrefixShort+"-from"))
 			assert.Equal(t, "false", req.FormValue(defaultPrefixShort+"-sync"))
+			assert.Equal(t, "21000", req.FormValue(defaultPrefixShort+"-gas"))
+			assert.Equal(t, "20000000000", req.FormValue(defaultPrefixShort+"-gasprice"))
 			assert.Equal(t, "0x9ffc50ff6bfe4502adc793aea54cc059c5df767cfe444e038eb51c5523097db5", body["uuids"])
 			assert.Equal(t, ethHexFormatB32(batch.BatchHash), body["batchHash"])
 			assert.Equal(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", body["payloadRef"])
@@ -405,6 +413,7 @@ func TestSubmitBatchEmptyPayloadRef(t *testing.T) {
 			assert.Equal(t, "0x9ffc50ff6bfe4502adc793aea54cc059c5df767cfe444e038eb51c5523097db5", body["uuids"])
 			assert.Equal(t, ethHexFormatB32(batch.BatchHash), body["batchHash"])
 			assert.Equal(t, "", body["payloadRef"])
+			assert.Empty(t, req.FormValue("fly-gas"))
 			return httpmock.NewJsonResponderOrPanic(200, asyncTXSubmission{})(req)
 		})
 
@@ -455,9 +464,29 @@ func TestVerifyEthAddress(t *testing.T) {
 	err = e.VerifyIdentitySyntax(context.Background(), id)
 	assert.NoError(t, err)
 	assert.Equal(t, "0x2a7c9d5248681ce6c393117e641ad037f5c079f6", id.OnChain)
+	assert.Equal(t, fftypes.KeyTypeSecp256k1, id.KeyType)
 
 }
 
+func TestVerifyIdentityKeyType(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	id := &fftypes.Identity{OnChain: "0x2a7c9D5248681CE6c393117E641aD037F5C079F6", KeyType: fftypes.KeyTypeSecp256k1}
+	err := e.VerifyIdentitySyntax(context.Background(), id)
+	assert.NoError(t, err)
+
+	for _, kt := range []string{fftypes.KeyTypeEd25519, fftypes.KeyTypeECDSAP256} {
+		id = &fftypes.Identity{OnChain: "0x2a7c9D5248681CE6c393117E641aD037F5C079F6", KeyType: kt}
+		err = e.VerifyIdentitySyntax(context.Background(), id)
+		assert.Regexp(t, "FF10340.*"+kt+".*ethereum", err)
+	}
+
+	id = &fftypes.Identity{OnChain: "0x2a7c9D5248681CE6c393117E641aD037F5C079F6", KeyType: "rsa"}
+	err = e.VerifyIdentitySyntax(context.Background(), id)
+	assert.Regexp(t, "FF10339.*rsa", err)
+}
+
 func TestHandleMessageBatchPinOK(t *testing.T) {
 	data := []byte(`
 [
@@ -963,3 +992,287 @@ func TestHandleReceiptBadRequestID(t *testing.T) {
 func TestFormatNil(t *testing.T) {
 	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", ethHexFormatB32(nil))
 }
+
+func TestGetBlockNumberOK(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:8545`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "eth_blockNumber", body["method"])
+			return httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      body["id"],
+				"result":  "0x1b4",
+			})(req)
+		})
+
+	blockNumber, err := e.GetBlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(436), blockNumber)
+
+}
+
+func TestGetBlockNumberRPCNotConfigured(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	e.rpcClient = nil
+
+	_, err := e.GetBlockNumber(context.Background())
+	assert.Regexp(t, "FF10362", err)
+
+}
+
+func TestGetBlockNumberConfiguredRPC(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	// The JSON-RPC request is sent to the configured URL of the node, not to ethconnect
+	httpmock.RegisterResponder("POST", `http://localhost:8545/rpc`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "2.0", body["jsonrpc"])
+			assert.Equal(t, "eth_blockNumber", body["method"])
+			return httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      body["id"],
+				"result":  "0x10",
+			})(req)
+		})
+
+	resetConf()
+	utEthconnectConf.Set(restclient.HTTPConfigURL, "http://localhost:12345")
+	utEthconnectConf.Set(EthconnectConfigInstancePath, "/instances/0x12345")
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+	utEthconnectConf.Set(EthconnectConfigSkipEventstreamInit, true)
+	utRPCConf.Set(restclient.HTTPConfigURL, "http://localhost:8545/rpc")
+	utRPCConf.Set(restclient.HTTPCustomClient, mockedClient)
+
+	err := e.Init(e.ctx, utConfPrefix, &blockchainmocks.Callbacks{})
+	assert.NoError(t, err)
+
+	blockNumber, err := e.GetBlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(16), blockNumber)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+
+}
+
+func TestGetBlockNumberHTTPFail(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:8545`,
+		httpmock.NewStringResponder(500, "pop"))
+
+	_, err := e.GetBlockNumber(context.Background())
+	assert.Regexp(t, "FF10363", err)
+
+}
+
+func TestGetBlockNumberRPCError(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:8545`,
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+			"jsonrpc": "2.0",
+			"error":   map[string]interface{}{"message": "pop"},
+		}))
+
+	_, err := e.GetBlockNumber(context.Background())
+	assert.Regexp(t, "FF10363.*pop", err)
+
+}
+
+func TestGetBlockNumberBadResult(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:8545`,
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+			"jsonrpc": "2.0",
+			"result":  "not hex",
+		}))
+
+	_, err := e.GetBlockNumber(context.Background())
+	assert.Regexp(t, "FF10292", err)
+
+}
+
+func newTestBatchPin() *blockchain.BatchPin {
+	return &blockchain.BatchPin{
+		TransactionID:  fftypes.NewUUID(),
+		BatchID:        fftypes.NewUUID(),
+		BatchHash:      fftypes.NewRandB32(),
+		BatchPaylodRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+		Contexts:       []*fftypes.Bytes32{fftypes.NewRandB32()},
+	}
+}
+
+func TestEstimateGasOK(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	batch := newTestBatchPin()
+	httpmock.RegisterResponder("POST", `http://localhost:12345/instances/0x12345/pinBatch`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "0x23456", req.FormValue(defaultPrefixShort+"-from"))
+			assert.Equal(t, "true", req.FormValue(defaultPrefixShort+"-estimategas"))
+			assert.Equal(t, ethHexFormatB32(batch.BatchHash), body["batchHash"])
+			return httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+				"gas": "21000",
+			})(req)
+		})
+	httpmock.RegisterResponder("POST", `http://localhost:8545`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "eth_gasPrice", body["method"])
+			return httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      body["id"],
+				"result":  "0x4a817c800",
+			})(req)
+		})
+
+	gasLimit, gasPrice, err := e.EstimateGas(context.Background(), &fftypes.Identity{OnChain: "0x23456"}, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000), gasLimit.Int64())
+	assert.Equal(t, int64(20000000000), gasPrice.Int64())
+
+}
+
+func TestEstimateGasHexOK(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/instances/0x12345/pinBatch`,
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{"gas": "0x5208"}))
+	httpmock.RegisterResponder("POST", `http://localhost:8545`,
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{"result": "0x1"}))
+
+	gasLimit, gasPrice, err := e.EstimateGas(context.Background(), &fftypes.Identity{OnChain: "0x23456"}, newTestBatchPin())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000), gasLimit.Int64())
+	assert.Equal(t, int64(1), gasPrice.Int64())
+
+}
+
+func TestEstimateGasNoRPC(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	e.rpcClient = nil
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/instances/0x12345/pinBatch`,
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{"gas": "21000"}))
+
+	gasLimit, gasPrice, err := e.EstimateGas(context.Background(), &fftypes.Identity{OnChain: "0x23456"}, newTestBatchPin())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000), gasLimit.Int64())
+	assert.Nil(t, gasPrice)
+
+}
+
+func TestEstimateGasHTTPFail(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/instances/0x12345/pinBatch`,
+		httpmock.NewStringResponder(500, "pop"))
+
+	_, _, err := e.EstimateGas(context.Background(), &fftypes.Identity{OnChain: "0x23456"}, newTestBatchPin())
+	assert.Regexp(t, "FF10111", err)
+
+}
+
+func TestEstimateGasBadGas(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/instances/0x12345/pinBatch`,
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{"gas": "lots"}))
+
+	_, _, err := e.EstimateGas(context.Background(), &fftypes.Identity{OnChain: "0x23456"}, newTestBatchPin())
+	assert.Regexp(t, "FF10348.*lots", err)
+
+}
+
+func TestEstimateGasPriceFail(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/instances/0x12345/pinBatch`,
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{"gas": "21000"}))
+	httpmock.RegisterResponder("POST", `http://localhost:8545`,
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+			"error": map[string]interface{}{"message": "pop"},
+		}))
+
+	// The gas limit is kept without a gas price
+	gasLimit, gasPrice, err := e.EstimateGas(context.Background(), &fftypes.Identity{OnChain: "0x23456"}, newTestBatchPin())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000), gasLimit.Int64())
+	assert.Nil(t, gasPrice)
+
+}
+
+func TestEstimateGasBadGasPrice(t *testing.T) {
+
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/instances/0x12345/pinBatch`,
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{"gas": "21000"}))
+	httpmock.RegisterResponder("POST", `http://localhost:8545`,
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{"result": "0xzz"}))
+
+	gasLimit, gasPrice, err := e.EstimateGas(context.Background(), &fftypes.Identity{OnChain: "0x23456"}, newTestBatchPin())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000), gasLimit.Int64())
+	assert.Nil(t, gasPrice)
+
+}
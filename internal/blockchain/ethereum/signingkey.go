@@ -0,0 +1,79 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// oidSecp256k1 is the named curve OID that accompanies a secp256k1 key in SEC 1 format
+var oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+// sec1PrivateKey is the ASN.1 structure of an "EC PRIVATE KEY" PEM block, as defined in RFC 5915.
+// The Go standard library cannot parse these for secp256k1, as it does not support the curve
+type sec1PrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+func (e *Ethereum) parseSigningKey(ctx context.Context, keyMaterial []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyMaterial)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, i18n.NewError(ctx, i18n.MsgInvalidSigningKey, "expected a PEM encoded EC PRIVATE KEY")
+	}
+	var sec1 sec1PrivateKey
+	if _, err := asn1.Unmarshal(block.Bytes, &sec1); err != nil {
+		return nil, i18n.NewError(ctx, i18n.MsgInvalidSigningKey, err)
+	}
+	if sec1.NamedCurveOID != nil && !sec1.NamedCurveOID.Equal(oidSecp256k1) {
+		return nil, i18n.NewError(ctx, i18n.MsgInvalidSigningKey, "curve must be secp256k1")
+	}
+	key, err := crypto.ToECDSA(sec1.PrivateKey)
+	if err != nil {
+		return nil, i18n.NewError(ctx, i18n.MsgInvalidSigningKey, err)
+	}
+	return key, nil
+}
+
+// SignPayload signs the keccak256 hash of the payload with a PEM encoded secp256k1 private key, in the 65 byte
+// [R || S || V] format used by Ethereum. The key must be the key of the signing identity's address.
+func (e *Ethereum) SignPayload(ctx context.Context, identity *fftypes.Identity, keyMaterial []byte, payload []byte) (string, error) {
+	key, err := e.parseSigningKey(ctx, keyMaterial)
+	if err != nil {
+		return "", err
+	}
+	address := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+	if address != identity.OnChain {
+		return "", i18n.NewError(ctx, i18n.MsgSigningKeyMismatch, address, identity.OnChain)
+	}
+	signature, err := crypto.Sign(crypto.Keccak256(payload), key)
+	if err != nil {
+		return "", i18n.NewError(ctx, i18n.MsgInvalidSigningKey, err)
+	}
+	return "0x" + hex.EncodeToString(signature), nil
+}
@@ -0,0 +1,106 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSigningKey(t *testing.T, curve asn1.ObjectIdentifier) (*ecdsa.PrivateKey, []byte) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	der, err := asn1.Marshal(sec1PrivateKey{
+		Version:       1,
+		PrivateKey:    crypto.FromECDSA(key),
+		NamedCurveOID: curve,
+	})
+	assert.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func TestSignPayloadOK(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	key, keyPEM := newTestSigningKey(t, oidSecp256k1)
+	address := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	signature, err := e.SignPayload(context.Background(), &fftypes.Identity{OnChain: address}, keyPEM, []byte("payload"))
+	assert.NoError(t, err)
+
+	sigBytes, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	assert.NoError(t, err)
+	pub, err := crypto.SigToPub(crypto.Keccak256([]byte("payload")), sigBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, address, strings.ToLower(crypto.PubkeyToAddress(*pub).Hex()))
+}
+
+func TestSignPayloadNotPEM(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	_, err := e.SignPayload(context.Background(), &fftypes.Identity{OnChain: "0x12345"}, []byte("!pem"), []byte("payload"))
+	assert.Regexp(t, "FF10346", err)
+}
+
+func TestSignPayloadBadASN1(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("!asn1")})
+	_, err := e.SignPayload(context.Background(), &fftypes.Identity{OnChain: "0x12345"}, keyPEM, []byte("payload"))
+	assert.Regexp(t, "FF10346", err)
+}
+
+func TestSignPayloadWrongCurve(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	_, keyPEM := newTestSigningKey(t, asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})
+	_, err := e.SignPayload(context.Background(), &fftypes.Identity{OnChain: "0x12345"}, keyPEM, []byte("payload"))
+	assert.Regexp(t, "FF10346.*secp256k1", err)
+}
+
+func TestSignPayloadBadKey(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	der, err := asn1.Marshal(sec1PrivateKey{Version: 1, PrivateKey: []byte{0x01}})
+	assert.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	_, err = e.SignPayload(context.Background(), &fftypes.Identity{OnChain: "0x12345"}, keyPEM, []byte("payload"))
+	assert.Regexp(t, "FF10346", err)
+}
+
+func TestSignPayloadIdentityMismatch(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	_, keyPEM := newTestSigningKey(t, oidSecp256k1)
+	_, err := e.SignPayload(context.Background(), &fftypes.Identity{OnChain: "0x12345"}, keyPEM, []byte("payload"))
+	assert.Regexp(t, "FF10347", err)
+}
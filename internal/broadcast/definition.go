@@ -19,8 +19,12 @@ package broadcast
 import (
 	"context"
 	"encoding/json"
+	"math/rand"
+	"time"
 
 	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
@@ -33,17 +37,34 @@ func (bm *broadcastManager) broadcastDefinitionAsNode(ctx context.Context, def f
 }
 
 func (bm *broadcastManager) BroadcastDefinition(ctx context.Context, def fftypes.Definition, signingIdentity *fftypes.Identity, tag fftypes.SystemTag, waitConfirm bool) (msg *fftypes.Message, err error) {
+	return bm.broadcastDefinitionType(ctx, def, signingIdentity, fftypes.MessageTypeDefinition, tag, waitConfirm)
+}
 
-	err = bm.blockchain.VerifyIdentitySyntax(ctx, signingIdentity)
+func (bm *broadcastManager) broadcastDefinitionType(ctx context.Context, def fftypes.Definition, signingIdentity *fftypes.Identity, msgType fftypes.MessageType, tag fftypes.SystemTag, waitConfirm bool) (msg *fftypes.Message, err error) {
+	data, msg, err := bm.prepareDefinition(ctx, def, signingIdentity, msgType, tag)
 	if err != nil {
 		return nil, err
 	}
+	msg, err = bm.sendDefinition(ctx, data, msg, waitConfirm)
+	if retryable, ok := err.(*retryableError); ok {
+		return nil, retryable.error
+	}
+	return msg, err
+}
+
+// prepareDefinition builds the data and message for a definition broadcast, without persisting anything
+func (bm *broadcastManager) prepareDefinition(ctx context.Context, def fftypes.Definition, signingIdentity *fftypes.Identity, msgType fftypes.MessageType, tag fftypes.SystemTag) (data *fftypes.Data, msg *fftypes.Message, err error) {
+
+	err = bm.blockchainFor(fftypes.SystemNamespace).VerifyIdentitySyntax(ctx, signingIdentity)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Ensure the broadcast message is nil on the sending side - only set on receiving side
 	def.SetBroadcastMessage(nil)
 
 	// Serialize it into a data object, as a piece of data we can write to a message
-	data := &fftypes.Data{
+	data = &fftypes.Data{
 		Validator: fftypes.ValidatorTypeSystemDefinition,
 		ID:        fftypes.NewUUID(),
 		Namespace: fftypes.SystemNamespace,
@@ -54,29 +75,182 @@ func (bm *broadcastManager) BroadcastDefinition(ctx context.Context, def fftypes
 		err = data.Seal(ctx)
 	}
 	if err != nil {
-		return nil, i18n.WrapError(ctx, err, i18n.MsgSerializationFailed)
-	}
-
-	// Write as data to the local store
-	if err = bm.database.UpsertData(ctx, data, true, false /* we just generated the ID, so it is new */); err != nil {
-		return nil, err
+		return nil, nil, i18n.WrapError(ctx, err, i18n.MsgSerializationFailed)
 	}
 
 	// Create a broadcast message referring to the data
 	msg = &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			Namespace: fftypes.SystemNamespace,
-			Type:      fftypes.MessageTypeDefinition,
+			Type:      msgType,
 			Author:    signingIdentity.Identifier,
 			Topics:    fftypes.FFNameArray{def.Topic()},
 			Tag:       string(tag),
 			TxType:    fftypes.TransactionTypeBatchPin,
 		},
+		Pinned: true,
 		Data: fftypes.DataRefs{
 			{ID: data.ID, Hash: data.Hash},
 		},
 	}
+	return data, msg, nil
+}
+
+// sendDefinition persists the data and message of a definition broadcast. Failures to persist are
+// marked retryable, as the data upsert is idempotent and the message is only stored if it succeeds.
+// Once the message is stored, any failure waiting for confirmation is final.
+func (bm *broadcastManager) sendDefinition(ctx context.Context, data *fftypes.Data, msg *fftypes.Message, waitConfirm bool) (*fftypes.Message, error) {
+
+	// Write as data to the local store
+	if err := bm.database.UpsertData(ctx, data, true, false /* a retry writes the same data again */); err != nil {
+		return nil, &retryableError{err}
+	}
+
+	if !waitConfirm {
+		if err := msg.Seal(ctx); err != nil {
+			return nil, err
+		}
+		msg.CreatedBy = fftypes.GetCreatedBy(ctx)
+		if err := bm.database.InsertMessageLocal(ctx, msg); err != nil {
+			return nil, &retryableError{err}
+		}
+		return msg, nil
+	}
 
 	// Broadcast the message
-	return bm.broadcastMessageCommon(ctx, msg, waitConfirm)
+	return bm.broadcastMessageCommon(ctx, msg, true)
+}
+
+// definitionKey hashes the content of a definition that identifies it, leaving out the fields that are
+// generated afresh every time the definition is built - such as the ID and creation time of a node or org.
+// Data that cannot be parsed as a JSON object has no key.
+func definitionKey(value []byte) *fftypes.Bytes32 {
+	var content fftypes.JSONObject
+	if err := json.Unmarshal(value, &content); err != nil || content == nil {
+		return nil
+	}
+	delete(content, "id")
+	delete(content, "created")
+	delete(content, "message")
+	b, _ := json.Marshal(content)
+	return fftypes.Byteable(b).Hash()
+}
+
+// BroadcastSignedDefinition signs the definition with key material supplied by the caller, before broadcasting it.
+// The signature covers the definition exactly as it is broadcast, other than the signature itself.
+// The key material is only passed to the blockchain plugin to sign, and is never stored.
+func (bm *broadcastManager) BroadcastSignedDefinition(ctx context.Context, def fftypes.SignedDefinition, signingIdentity *fftypes.Identity, keyMaterial []byte, tag fftypes.SystemTag, waitConfirm bool) (msg *fftypes.Message, err error) {
+
+	bi := bm.blockchainFor(fftypes.SystemNamespace)
+	err = bi.VerifyIdentitySyntax(ctx, signingIdentity)
+	if err != nil {
+		return nil, err
+	}
+
+	def.SetBroadcastMessage(nil)
+	def.SetSignature("")
+	payload, err := json.Marshal(def)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgSerializationFailed)
+	}
+	signature, err := bi.SignPayload(ctx, signingIdentity, keyMaterial, payload)
+	if err != nil {
+		return nil, err
+	}
+	def.SetSignature(signature)
+
+	return bm.BroadcastDefinition(ctx, def, signingIdentity, tag, waitConfirm)
+}
+
+// BroadcastDefinitionIfNotExists makes re-broadcasting an unchanged definition a no-op. If a definition message
+// that has not been rejected exists from the same author with the same tag, and its data has the same definition key,
+// that message is returned with created=false. Messages still awaiting confirmation are included, so a definition
+// broadcast just before a restart is not sent twice. Only the most recent definitionLookupLimit messages are checked.
+// Otherwise the definition is broadcast with retry as normal.
+func (bm *broadcastManager) BroadcastDefinitionIfNotExists(ctx context.Context, def fftypes.Definition, signingIdentity *fftypes.Identity, tag fftypes.SystemTag, waitConfirm bool, maxRetries int, retryDelay time.Duration) (msg *fftypes.Message, created bool, err error) {
+
+	// Key the definition exactly as prepareDefinition would serialize it. A serialization failure
+	// simply finds no match, and is then reported by the broadcast
+	def.SetBroadcastMessage(nil)
+	value, _ := json.Marshal(&def)
+	key := definitionKey(value)
+
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	existing, _, err := bm.database.GetMessages(ctx, fb.And(
+		fb.Eq("namespace", fftypes.SystemNamespace),
+		fb.Eq("author", signingIdentity.Identifier),
+		fb.Eq("tag", string(tag)),
+		fb.Eq("topics", def.Topic()),
+		fb.Eq("rejected", false),
+	).Sort("sequence").Descending().Limit(definitionLookupLimit))
+	if err != nil {
+		return nil, false, err
+	}
+	for _, m := range existing {
+		if key == nil || len(m.Data) != 1 {
+			continue
+		}
+		data, err := bm.database.GetDataByID(ctx, m.Data[0].ID, true)
+		if err != nil {
+			return nil, false, err
+		}
+		if data != nil && key.Equals(definitionKey(data.Value)) {
+			log.L(ctx).Debugf("Definition already broadcast in message %s", m.Header.ID)
+			return m, false, nil
+		}
+	}
+
+	msg, err = bm.BroadcastDefinitionWithRetry(ctx, def, signingIdentity, tag, waitConfirm, maxRetries, retryDelay)
+	return msg, err == nil, err
+}
+
+// definitionLookupLimit bounds how many previous definition messages are compared against a new definition
+const definitionLookupLimit = 25
+
+// maxDefinitionRetryDelay caps the backoff between definition broadcast attempts
+const maxDefinitionRetryDelay = 1 * time.Minute
+
+// retryableError marks a failure that can safely be retried, without duplicating anything already persisted
+type retryableError struct {
+	error
+}
+
+// BroadcastDefinitionWithRetry broadcasts a definition, retrying up to maxRetries times if the
+// failure is retryable. The same data and message are used on every attempt, so a retry never
+// persists a duplicate. Between attempts we wait retryDelay * 2^attempt, capped at one minute,
+// plus up to 50% jitter.
+// Once retries are exhausted the final failure is wrapped, and can be retrieved with errors.Cause
+func (bm *broadcastManager) BroadcastDefinitionWithRetry(ctx context.Context, def fftypes.Definition, signingIdentity *fftypes.Identity, tag fftypes.SystemTag, waitConfirm bool, maxRetries int, retryDelay time.Duration) (msg *fftypes.Message, err error) {
+
+	data, msg, err := bm.prepareDefinition(ctx, def, signingIdentity, fftypes.MessageTypeDefinition, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		result, err := bm.sendDefinition(ctx, data, msg, waitConfirm)
+		if err == nil {
+			return result, nil
+		}
+		retryable, ok := err.(*retryableError)
+		if !ok {
+			return nil, err
+		}
+		if attempt >= maxRetries {
+			return nil, i18n.WrapError(ctx, retryable.error, i18n.MsgDefinitionBroadcastFailed, attempt+1)
+		}
+
+		if delay > maxDefinitionRetryDelay {
+			delay = maxDefinitionRetryDelay
+		}
+		wait := delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+		log.L(ctx).Warnf("Definition broadcast attempt %d failed (retrying in %s): %s", attempt+1, wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, i18n.NewError(ctx, i18n.MsgContextCanceled)
+		}
+		delay *= 2
+	}
 }
@@ -17,13 +17,23 @@
 package broadcast
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/nsplugins"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/identitymocks"
+	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -52,3 +62,342 @@ func TestBroadcastDefinitionAsNodeBadSigningId(t *testing.T) {
 	_, err := bm.broadcastDefinitionAsNode(bm.ctx, &fftypes.Namespace{}, fftypes.SystemTagDefineNamespace, false)
 	assert.Regexp(t, "pop", err)
 }
+
+func TestBroadcastDefinitionIfNotExistsFound(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	// A node definition built again has a new ID and creation time, but is otherwise unchanged
+	previous := &fftypes.Node{ID: fftypes.NewUUID(), Owner: "org1", Name: "node1", Created: fftypes.Now()}
+	value, _ := json.Marshal(previous)
+	existing := &fftypes.Message{
+		Header: fftypes.MessageHeader{ID: fftypes.NewUUID()},
+		Data:   fftypes.DataRefs{{ID: fftypes.NewUUID(), Hash: fftypes.Byteable(value).Hash()}},
+	}
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", mock.Anything, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "tag == 'ff_define_node'") &&
+			strings.Contains(fi.String(), "author == '0x12345'") &&
+			!strings.Contains(fi.String(), "confirmed") &&
+			strings.Contains(fi.String(), "rejected == false") &&
+			strings.Contains(fi.String(), "sort=-sequence") &&
+			strings.Contains(fi.String(), "limit=25")
+	})).Return([]*fftypes.Message{
+		{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}, // no data, so skipped
+		existing,
+	}, nil, nil)
+	mdi.On("GetDataByID", mock.Anything, existing.Data[0].ID, true).Return(&fftypes.Data{Value: value}, nil)
+
+	id := &fftypes.Identity{OnChain: "0x12345", Identifier: "0x12345"}
+	node := &fftypes.Node{ID: fftypes.NewUUID(), Owner: "org1", Name: "node1", Created: fftypes.Now()}
+	msg, created, err := bm.BroadcastDefinitionIfNotExists(bm.ctx, node, id, fftypes.SystemTagDefineNode, false, 3, time.Millisecond)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, existing, msg)
+
+	mdi.AssertExpectations(t)
+	mdi.AssertNotCalled(t, "UpsertData", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBroadcastDefinitionIfNotExistsChanged(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	value, _ := json.Marshal(&fftypes.Namespace{Name: "ns1", Description: "unchanged"})
+	previous := &fftypes.Message{
+		Header: fftypes.MessageHeader{ID: fftypes.NewUUID()},
+		Data:   fftypes.DataRefs{{ID: fftypes.NewUUID(), Hash: fftypes.Byteable(value).Hash()}},
+	}
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{previous}, nil, nil)
+	mdi.On("GetDataByID", mock.Anything, previous.Data[0].ID, true).Return(&fftypes.Data{Value: value}, nil)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(fmt.Errorf("pop")).Once()
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(nil)
+	mdi.On("InsertMessageLocal", mock.Anything, mock.Anything).Return(nil)
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	msg, created, err := bm.BroadcastDefinitionIfNotExists(bm.ctx, &fftypes.Namespace{Name: "ns1", Description: "changed"}, id, fftypes.SystemTagDefineNamespace, false, 3, time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEqual(t, previous.Header.ID, msg.Header.ID)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestBroadcastDefinitionIfNotExistsQueryFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	_, created, err := bm.BroadcastDefinitionIfNotExists(bm.ctx, &fftypes.Namespace{Name: "ns1"}, id, fftypes.SystemTagDefineNamespace, false, 3, time.Millisecond)
+	assert.EqualError(t, err, "pop")
+	assert.False(t, created)
+}
+
+func TestBroadcastDefinitionIfNotExistsGetDataFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{
+		{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}, Data: fftypes.DataRefs{{ID: fftypes.NewUUID()}}},
+	}, nil, nil)
+	mdi.On("GetDataByID", mock.Anything, mock.Anything, true).Return(nil, fmt.Errorf("pop"))
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	_, created, err := bm.BroadcastDefinitionIfNotExists(bm.ctx, &fftypes.Namespace{Name: "ns1"}, id, fftypes.SystemTagDefineNamespace, false, 3, time.Millisecond)
+	assert.EqualError(t, err, "pop")
+	assert.False(t, created)
+}
+
+func TestDefinitionKey(t *testing.T) {
+	n1, _ := json.Marshal(&fftypes.Node{ID: fftypes.NewUUID(), Message: fftypes.NewUUID(), Name: "node1", Created: fftypes.Now()})
+	n2, _ := json.Marshal(&fftypes.Node{ID: fftypes.NewUUID(), Name: "node1"})
+	n3, _ := json.Marshal(&fftypes.Node{ID: fftypes.NewUUID(), Name: "node2"})
+	assert.Equal(t, definitionKey(n1), definitionKey(n2))
+	assert.NotEqual(t, definitionKey(n1), definitionKey(n3))
+	assert.Nil(t, definitionKey([]byte("null")))
+	assert.Nil(t, definitionKey([]byte("!json")))
+}
+
+func TestBroadcastDefinitionWithRetrySecondAttempt(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(fmt.Errorf("pop")).Once()
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(nil).Once()
+	mdi.On("InsertMessageLocal", mock.Anything, mock.Anything).Return(nil)
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	msg, err := bm.BroadcastDefinitionWithRetry(bm.ctx, &fftypes.Namespace{}, id, fftypes.SystemTagDefineNamespace, false, 3, time.Millisecond)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestBroadcastDefinitionWithRetryExhausted(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(fmt.Errorf("pop")).Times(3)
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	_, err = bm.BroadcastDefinitionWithRetry(bm.ctx, &fftypes.Namespace{}, id, fftypes.SystemTagDefineNamespace, false, 2, time.Millisecond)
+	assert.Regexp(t, "FF10296.*3", err)
+	assert.EqualError(t, errors.Cause(err), "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestBroadcastDefinitionWithRetryBadSigningId(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mbi := bm.blockchain.(*blockchainmocks.Plugin)
+	badID := &fftypes.Identity{OnChain: "0x99999"}
+	mbi.On("VerifyIdentitySyntax", mock.Anything, badID).Return(fmt.Errorf("pop")).Once()
+	_, err := bm.BroadcastDefinitionWithRetry(bm.ctx, &fftypes.Namespace{}, badID, fftypes.SystemTagDefineNamespace, false, 5, time.Millisecond)
+	assert.EqualError(t, err, "pop")
+
+	mbi.AssertExpectations(t)
+}
+
+func TestBroadcastDefinitionWithRetryContextCancelled(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(fmt.Errorf("pop")).Once()
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	ctx, ctxCancel := context.WithCancel(bm.ctx)
+	ctxCancel()
+	_, err = bm.BroadcastDefinitionWithRetry(ctx, &fftypes.Namespace{}, id, fftypes.SystemTagDefineNamespace, false, 5, time.Minute)
+	assert.Regexp(t, "FF10158", err)
+}
+
+func TestBroadcastDefinitionWithRetrySameMessage(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	var msgIDs []*fftypes.UUID
+	captureID := func(args mock.Arguments) {
+		msgIDs = append(msgIDs, args[1].(*fftypes.Message).Header.ID)
+	}
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(nil).Twice()
+	mdi.On("InsertMessageLocal", mock.Anything, mock.Anything).Run(captureID).Return(fmt.Errorf("pop")).Once()
+	mdi.On("InsertMessageLocal", mock.Anything, mock.Anything).Run(captureID).Return(nil).Once()
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	msg, err := bm.BroadcastDefinitionWithRetry(bm.ctx, &fftypes.Namespace{}, id, fftypes.SystemTagDefineNamespace, false, 3, time.Millisecond)
+	assert.NoError(t, err)
+	assert.Len(t, msgIDs, 2)
+	assert.Equal(t, *msgIDs[0], *msgIDs[1])
+	assert.Equal(t, *msgIDs[1], *msg.Header.ID)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestBroadcastDefinitionWithRetryNotRetryable(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(nil).Once()
+	msa := bm.syncasync.(*syncasyncmocks.Bridge)
+	msa.On("SendConfirm", mock.Anything, fftypes.SystemNamespace, mock.Anything).Return(nil, fmt.Errorf("pop")).Once()
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	_, err = bm.BroadcastDefinitionWithRetry(bm.ctx, &fftypes.Namespace{}, id, fftypes.SystemTagDefineNamespace, true, 5, time.Millisecond)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	msa.AssertExpectations(t)
+}
+
+func TestBroadcastDefinitionWithRetryDelayCapped(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(fmt.Errorf("pop")).Once()
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	ctx, ctxCancel := context.WithCancel(bm.ctx)
+	ctxCancel()
+	_, err = bm.BroadcastDefinitionWithRetry(ctx, &fftypes.Namespace{}, id, fftypes.SystemTagDefineNamespace, false, 5, time.Duration(math.MaxInt64))
+	assert.Regexp(t, "FF10158", err)
+}
+
+func TestBroadcastDefinitionRetryableUnwrapped(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(fmt.Errorf("pop"))
+
+	_, err := bm.broadcastDefinitionAsNode(bm.ctx, &fftypes.Namespace{}, fftypes.SystemTagDefineNamespace, false)
+	assert.EqualError(t, err, "pop")
+	_, ok := err.(*retryableError)
+	assert.False(t, ok)
+}
+
+func TestSendDefinitionSealFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(nil)
+
+	dataID := fftypes.NewUUID()
+	msg := &fftypes.Message{
+		Data: fftypes.DataRefs{{ID: dataID}, {ID: dataID}},
+	}
+	_, err := bm.sendDefinition(bm.ctx, &fftypes.Data{}, msg, false)
+	assert.Regexp(t, "FF10144", err)
+	_, ok := err.(*retryableError)
+	assert.False(t, ok)
+}
+
+type unserializableDefinition struct {
+	fftypes.Organization
+	Bad chan bool `json:"bad"`
+}
+
+func TestBroadcastSignedDefinitionOK(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mbi := bm.blockchain.(*blockchainmocks.Plugin)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(nil)
+	mdi.On("InsertMessageLocal", mock.Anything, mock.Anything).Return(nil)
+	org := &fftypes.Organization{Name: "org1", Signature: "stale"}
+	mbi.On("SignPayload", mock.Anything, mock.Anything, []byte("key"), mock.MatchedBy(func(payload []byte) bool {
+		return !strings.Contains(string(payload), "stale")
+	})).Return("0x12345", nil)
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	msg, err := bm.BroadcastSignedDefinition(bm.ctx, org, id, []byte("key"), fftypes.SystemTagDefineOrganization, false)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, "0x12345", org.Signature)
+
+	mdi.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestBroadcastSignedDefinitionNamespaceOverride(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mbi2 := &blockchainmocks.Plugin{}
+	bm.nsPlugins = nsplugins.Overrides{fftypes.SystemNamespace: {Blockchain: mbi2}}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(nil)
+	mdi.On("InsertMessageLocal", mock.Anything, mock.Anything).Return(nil)
+	mbi2.On("VerifyIdentitySyntax", mock.Anything, mock.Anything).Return(nil)
+	mbi2.On("SignPayload", mock.Anything, mock.Anything, []byte("key"), mock.Anything).Return("0x12345", nil)
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	org := &fftypes.Organization{Name: "org1"}
+	_, err = bm.BroadcastSignedDefinition(bm.ctx, org, id, []byte("key"), fftypes.SystemTagDefineOrganization, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x12345", org.Signature)
+
+	mbi2.AssertExpectations(t)
+	bm.blockchain.(*blockchainmocks.Plugin).AssertNotCalled(t, "SignPayload", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBroadcastSignedDefinitionBadSigningId(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mbi := bm.blockchain.(*blockchainmocks.Plugin)
+	badID := &fftypes.Identity{OnChain: "0x99999"}
+	mbi.On("VerifyIdentitySyntax", mock.Anything, badID).Return(fmt.Errorf("pop"))
+	_, err := bm.BroadcastSignedDefinition(bm.ctx, &fftypes.Organization{}, badID, []byte("key"), fftypes.SystemTagDefineOrganization, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestBroadcastSignedDefinitionSerializeFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	_, err = bm.BroadcastSignedDefinition(bm.ctx, &unserializableDefinition{}, id, []byte("key"), fftypes.SystemTagDefineOrganization, false)
+	assert.Regexp(t, "FF10137", err)
+}
+
+func TestBroadcastSignedDefinitionSignFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mbi := bm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("SignPayload", mock.Anything, mock.Anything, []byte("key"), mock.Anything).Return("", fmt.Errorf("pop"))
+
+	id, err := bm.GetNodeSigningIdentity(bm.ctx)
+	assert.NoError(t, err)
+	_, err = bm.BroadcastSignedDefinition(bm.ctx, &fftypes.Organization{}, id, []byte("key"), fftypes.SystemTagDefineOrganization, false)
+	assert.EqualError(t, err, "pop")
+}
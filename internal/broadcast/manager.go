@@ -20,12 +20,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/hyperledger/firefly/internal/batch"
 	"github.com/hyperledger/firefly/internal/batchpin"
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/nsplugins"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -39,7 +41,13 @@ type Manager interface {
 	BroadcastDatatype(ctx context.Context, ns string, datatype *fftypes.Datatype, waitConfirm bool) (msg *fftypes.Message, err error)
 	BroadcastNamespace(ctx context.Context, ns *fftypes.Namespace, waitConfirm bool) (msg *fftypes.Message, err error)
 	BroadcastMessage(ctx context.Context, ns string, in *fftypes.MessageInOut, waitConfirm bool) (out *fftypes.Message, err error)
+	BroadcastWithTimeout(ctx context.Context, ns string, in *fftypes.MessageInOut, timeout time.Duration) (out *fftypes.Message, err error)
+	BroadcastStream(ctx context.Context, ns string, in <-chan *fftypes.MessageInOut, errors chan<- error) error
+	BroadcastRawJSON(ctx context.Context, ns string, data json.RawMessage, tag string) (*fftypes.Message, error)
 	BroadcastDefinition(ctx context.Context, def fftypes.Definition, signingIdentity *fftypes.Identity, tag fftypes.SystemTag, waitConfirm bool) (msg *fftypes.Message, err error)
+	BroadcastSignedDefinition(ctx context.Context, def fftypes.SignedDefinition, signingIdentity *fftypes.Identity, keyMaterial []byte, tag fftypes.SystemTag, waitConfirm bool) (msg *fftypes.Message, err error)
+	BroadcastDefinitionIfNotExists(ctx context.Context, def fftypes.Definition, signingIdentity *fftypes.Identity, tag fftypes.SystemTag, waitConfirm bool, maxRetries int, retryDelay time.Duration) (msg *fftypes.Message, created bool, err error)
+	BroadcastDefinitionWithRetry(ctx context.Context, def fftypes.Definition, signingIdentity *fftypes.Identity, tag fftypes.SystemTag, waitConfirm bool, maxRetries int, retryDelay time.Duration) (msg *fftypes.Message, err error)
 	BroadcastTokenPool(ctx context.Context, ns string, pool *fftypes.TokenPoolAnnouncement, waitConfirm bool) (msg *fftypes.Message, err error)
 	GetNodeSigningIdentity(ctx context.Context) (*fftypes.Identity, error)
 	Start() error
@@ -54,12 +62,13 @@ type broadcastManager struct {
 	blockchain    blockchain.Plugin
 	exchange      dataexchange.Plugin
 	publicstorage publicstorage.Plugin
+	nsPlugins     nsplugins.Overrides
 	batch         batch.Manager
 	syncasync     syncasync.Bridge
 	batchpin      batchpin.Submitter
 }
 
-func NewBroadcastManager(ctx context.Context, di database.Plugin, ii identity.Plugin, dm data.Manager, bi blockchain.Plugin, dx dataexchange.Plugin, pi publicstorage.Plugin, ba batch.Manager, sa syncasync.Bridge, bp batchpin.Submitter) (Manager, error) {
+func NewBroadcastManager(ctx context.Context, di database.Plugin, ii identity.Plugin, dm data.Manager, bi blockchain.Plugin, dx dataexchange.Plugin, pi publicstorage.Plugin, np nsplugins.Overrides, ba batch.Manager, sa syncasync.Bridge, bp batchpin.Submitter) (Manager, error) {
 	if di == nil || ii == nil || dm == nil || bi == nil || dx == nil || pi == nil || ba == nil {
 		return nil, i18n.NewError(ctx, i18n.MsgInitializationNilDepError)
 	}
@@ -71,6 +80,7 @@ func NewBroadcastManager(ctx context.Context, di database.Plugin, ii identity.Pl
 		blockchain:    bi,
 		exchange:      dx,
 		publicstorage: pi,
+		nsPlugins:     np,
 		batch:         ba,
 		syncasync:     sa,
 		batchpin:      bp,
@@ -84,9 +94,27 @@ func NewBroadcastManager(ctx context.Context, di database.Plugin, ii identity.Pl
 		fftypes.MessageTypeBroadcast,
 		fftypes.MessageTypeDefinition,
 	}, bm.dispatchBatch, bo)
+	ba.RegisterDispatcher([]fftypes.MessageType{
+		fftypes.MessageTypeTokenPoolDefinition,
+	}, bm.dispatchBatch, bo)
 	return bm, nil
 }
 
+// blockchainFor returns the blockchain plugin for a namespace, which might be overridden in its configuration
+func (bm *broadcastManager) blockchainFor(ns string) blockchain.Plugin {
+	return bm.nsPlugins.Blockchain(ns, bm.blockchain)
+}
+
+// exchangeFor returns the data exchange plugin for a namespace, which might be overridden in its configuration
+func (bm *broadcastManager) exchangeFor(ns string) dataexchange.Plugin {
+	return bm.nsPlugins.DataExchange(ns, bm.exchange)
+}
+
+// publicstorageFor returns the public storage plugin for a namespace, which might be overridden in its configuration
+func (bm *broadcastManager) publicstorageFor(ns string) publicstorage.Plugin {
+	return bm.nsPlugins.PublicStorage(ns, bm.publicstorage)
+}
+
 func (bm *broadcastManager) GetNodeSigningIdentity(ctx context.Context) (*fftypes.Identity, error) {
 	orgIdentity := config.GetString(config.OrgIdentity)
 	id, err := bm.identity.Resolve(ctx, orgIdentity)
@@ -98,7 +126,17 @@ func (bm *broadcastManager) GetNodeSigningIdentity(ctx context.Context) (*fftype
 
 func (bm *broadcastManager) dispatchBatch(ctx context.Context, batch *fftypes.Batch, pins []*fftypes.Bytes32) error {
 
-	// Serialize the full payload, which has already been sealed for us by the BatchManager
+	// The operations for the batch are attributed to the identities that submitted its messages
+	if createdBy := batch.Payload.CreatedBy(); createdBy != "" {
+		ctx = fftypes.WithCreatedBy(ctx, createdBy)
+	}
+
+	// Re-seal the data in the payload, so the hashes we publish are verified against the values
+	if err := fftypes.SealAll(ctx, batch.Payload.Data); err != nil {
+		return err
+	}
+
+	// Serialize the full payload
 	payload, err := json.Marshal(batch)
 	if err != nil {
 		return i18n.WrapError(ctx, err, i18n.MsgSerializationFailed)
@@ -106,10 +144,11 @@ func (bm *broadcastManager) dispatchBatch(ctx context.Context, batch *fftypes.Ba
 
 	// Write it to IPFS to get a payload reference
 	// The payload ref will be persisted back to the batch, as well as being used in the TX
-	batch.PayloadRef, err = bm.publicstorage.PublishData(ctx, bytes.NewReader(payload))
+	batch.PayloadRef, err = bm.publicstorageFor(batch.Namespace).PublishData(ctx, bytes.NewReader(payload))
 	if err != nil {
 		return err
 	}
+	batch.PublishedAt = fftypes.Now()
 
 	return bm.database.RunAsGroup(ctx, func(ctx context.Context) error {
 		return bm.submitTXAndUpdateDB(ctx, batch, pins)
@@ -118,21 +157,30 @@ func (bm *broadcastManager) dispatchBatch(ctx context.Context, batch *fftypes.Ba
 
 func (bm *broadcastManager) submitTXAndUpdateDB(ctx context.Context, batch *fftypes.Batch, contexts []*fftypes.Bytes32) error {
 
-	// Update the batch to store the payloadRef
-	err := bm.database.UpdateBatch(ctx, batch.ID, database.BatchQueryFactory.NewUpdate(ctx).Set("payloadref", batch.PayloadRef))
+	// Update the batch to store the payloadRef, and when it was published
+	err := bm.database.UpdateBatch(ctx, batch.ID, database.BatchQueryFactory.NewUpdate(ctx).
+		Set("payloadref", batch.PayloadRef).
+		Set("publishedat", batch.PublishedAt))
 	if err != nil {
 		return err
 	}
 
+	if batch.Payload.Ephemeral() {
+		// No operation is recorded for the upload, as ephemeral messages leave no trace once confirmed
+		return bm.batchpin.SubmitPinnedBatch(ctx, batch, contexts)
+	}
+
 	// The completed PublicStorage upload
 	op := fftypes.NewTXOperation(
-		bm.publicstorage,
+		bm.publicstorageFor(batch.Namespace),
 		batch.Namespace,
 		batch.Payload.TX.ID,
 		batch.PayloadRef,
 		fftypes.OpTypePublicStorageBatchBroadcast,
 		fftypes.OpStatusSucceeded, // Note we performed the action synchronously above
-		"")
+		"",
+		fftypes.GetCreatedBy(ctx))
+	op.Priority = batch.Payload.Priority()
 	err = bm.database.UpsertOperation(ctx, op, false)
 	if err != nil {
 		return err
@@ -150,6 +198,7 @@ func (bm *broadcastManager) broadcastMessageCommon(ctx context.Context, msg *fft
 		}
 
 		// Store the message - this asynchronously triggers the next step in process
+		msg.CreatedBy = fftypes.GetCreatedBy(ctx)
 		return msg, bm.database.InsertMessageLocal(ctx, msg)
 	}
 
@@ -22,6 +22,8 @@ import (
 	"testing"
 
 	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/nsplugins"
+	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/mocks/batchmocks"
 	"github.com/hyperledger/firefly/mocks/batchpinmocks"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
@@ -31,6 +33,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/identitymocks"
 	"github.com/hyperledger/firefly/mocks/publicstoragemocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -53,14 +56,15 @@ func newTestBroadcast(t *testing.T) (*broadcastManager, func()) {
 	mii.On("Resolve", mock.Anything, "UTNodeID").Return(defaultIdentity, nil).Maybe()
 	mbi.On("VerifyIdentitySyntax", mock.Anything, defaultIdentity).Return(nil).Maybe()
 	mba.On("RegisterDispatcher", []fftypes.MessageType{fftypes.MessageTypeBroadcast, fftypes.MessageTypeDefinition}, mock.Anything, mock.Anything).Return()
+	mba.On("RegisterDispatcher", []fftypes.MessageType{fftypes.MessageTypeTokenPoolDefinition}, mock.Anything, mock.Anything).Return()
 	ctx, cancel := context.WithCancel(context.Background())
-	b, err := NewBroadcastManager(ctx, mdi, mii, mdm, mbi, mdx, mpi, mba, msa, mbp)
+	b, err := NewBroadcastManager(ctx, mdi, mii, mdm, mbi, mdx, mpi, nil, mba, msa, mbp)
 	assert.NoError(t, err)
 	return b.(*broadcastManager), cancel
 }
 
 func TestInitFail(t *testing.T) {
-	_, err := NewBroadcastManager(context.Background(), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := NewBroadcastManager(context.Background(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -79,6 +83,41 @@ func TestBroadcastMessageGood(t *testing.T) {
 	bm.WaitStop()
 }
 
+func TestBroadcastMessageWaitConfirm(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	msa := bm.syncasync.(*syncasyncmocks.Bridge)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		var fn = a[1].(func(context.Context) error)
+		rag.ReturnArguments = mock.Arguments{fn(a[0].(context.Context))}
+	}
+	requestID := fftypes.NewUUID()
+	msa.On("SendConfirm", mock.Anything, "ns1", mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args[2].(syncasync.RequestSender)
+			send(requestID)
+		}).
+		Return(nil, nil)
+	mdi.On("InsertMessageLocal", mock.Anything, mock.MatchedBy(func(msg *fftypes.Message) bool {
+		return msg.Header.ID == requestID
+	})).Return(nil)
+
+	msg := &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			Namespace: "ns1",
+			Author:    "0x12345",
+		},
+	}
+	_, err := bm.broadcastMessageCommon(context.Background(), msg, true)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	msa.AssertExpectations(t)
+}
+
 func TestBroadcastMessageBad(t *testing.T) {
 	bm, cancel := newTestBroadcast(t)
 	defer cancel()
@@ -103,20 +142,37 @@ func TestDispatchBatchInvalidData(t *testing.T) {
 	err := bm.dispatchBatch(context.Background(), &fftypes.Batch{
 		Payload: fftypes.BatchPayload{
 			Data: []*fftypes.Data{
-				{Value: fftypes.Byteable(`!json`)},
+				{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`!json`)},
 			},
 		},
 	}, []*fftypes.Bytes32{fftypes.NewRandB32()})
 	assert.Regexp(t, "FF10137", err)
 }
 
+func TestDispatchBatchSealFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	err := bm.dispatchBatch(context.Background(), &fftypes.Batch{
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{ID: fftypes.NewUUID()},
+			Data: []*fftypes.Data{
+				{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+			},
+		},
+	}, []*fftypes.Bytes32{fftypes.NewRandB32()})
+	assert.Regexp(t, "FF10199", err)
+}
+
 func TestDispatchBatchUploadFail(t *testing.T) {
 	bm, cancel := newTestBroadcast(t)
 	defer cancel()
 
 	bm.publicstorage.(*publicstoragemocks.Plugin).On("PublishData", mock.Anything, mock.Anything).Return("", fmt.Errorf("pop"))
 
-	err := bm.dispatchBatch(context.Background(), &fftypes.Batch{}, []*fftypes.Bytes32{fftypes.NewRandB32()})
+	err := bm.dispatchBatch(context.Background(), &fftypes.Batch{
+		Payload: fftypes.BatchPayload{TX: fftypes.TransactionRef{ID: fftypes.NewUUID()}},
+	}, []*fftypes.Bytes32{fftypes.NewRandB32()})
 	assert.EqualError(t, err, "pop")
 }
 
@@ -128,8 +184,118 @@ func TestDispatchBatchSubmitBatchPinSucceed(t *testing.T) {
 	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(nil)
 	bm.publicstorage.(*publicstoragemocks.Plugin).On("PublishData", mock.Anything, mock.Anything).Return("id1", nil)
 
-	err := bm.dispatchBatch(context.Background(), &fftypes.Batch{}, []*fftypes.Bytes32{fftypes.NewRandB32()})
+	err := bm.dispatchBatch(context.Background(), &fftypes.Batch{
+		Namespace: "ns1",
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{ID: fftypes.NewUUID()},
+			Messages: []*fftypes.Message{
+				{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1"}},
+			},
+		},
+	}, []*fftypes.Bytes32{fftypes.NewRandB32()})
+	assert.NoError(t, err)
+}
+
+func TestDispatchBatchAttributesOperationsToCreator(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mbp := bm.batchpin.(*batchpinmocks.Submitter)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	bm.publicstorage.(*publicstoragemocks.Plugin).On("PublishData", mock.Anything, mock.Anything).Return("id1", nil)
+	bm.publicstorage.(*publicstoragemocks.Plugin).On("Name").Return("ut_publicstorage")
+	mdi.On("UpdateBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("UpsertOperation", mock.Anything, mock.MatchedBy(func(op *fftypes.Operation) bool {
+		return op.CreatedBy == "org1,org2"
+	}), false).Return(nil)
+	mbp.On("SubmitPinnedBatch", mock.MatchedBy(func(ctx context.Context) bool {
+		return fftypes.GetCreatedBy(ctx) == "org1,org2"
+	}), mock.Anything, mock.Anything).Return(nil)
+
+	err := bm.dispatchBatch(context.Background(), &fftypes.Batch{
+		Namespace: "ns1",
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{ID: fftypes.NewUUID()},
+			Messages: []*fftypes.Message{
+				{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1"}, CreatedBy: "org1"},
+				{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1"}, CreatedBy: "org2"},
+			},
+		},
+	}, []*fftypes.Bytes32{fftypes.NewRandB32()})
 	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mbp.AssertExpectations(t)
+}
+
+func TestDispatchBatchNamespaceOverride(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mps2 := &publicstoragemocks.Plugin{}
+	bm.nsPlugins = nsplugins.Overrides{"ns1": {PublicStorage: mps2}}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(nil)
+	mps2.On("PublishData", mock.Anything, mock.Anything).Return("id1", nil)
+
+	err := bm.dispatchBatch(context.Background(), &fftypes.Batch{
+		Namespace: "ns1",
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{ID: fftypes.NewUUID()},
+			Messages: []*fftypes.Message{
+				{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1"}},
+			},
+		},
+	}, []*fftypes.Bytes32{fftypes.NewRandB32()})
+	assert.NoError(t, err)
+
+	mps2.AssertExpectations(t)
+	bm.publicstorage.(*publicstoragemocks.Plugin).AssertNotCalled(t, "PublishData", mock.Anything, mock.Anything)
+}
+
+func TestDispatchBatchSetsPublishedAtBeforePin(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mps := bm.publicstorage.(*publicstoragemocks.Plugin)
+	mbp := bm.batchpin.(*batchpinmocks.Submitter)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{a[1].(func(context.Context) error)(a[0].(context.Context))}
+	}
+	mps.On("PublishData", mock.Anything, mock.Anything).Return("id1", nil)
+	mps.On("Name").Return("ut_publicstorage")
+	mdi.On("UpdateBatch", mock.Anything, mock.Anything, mock.MatchedBy(func(u database.Update) bool {
+		info, _ := u.Finalize()
+		return len(info.SetOperations) == 2 &&
+			info.SetOperations[0].Field == "payloadref" &&
+			info.SetOperations[1].Field == "publishedat"
+	})).Return(nil)
+	mdi.On("UpsertOperation", mock.Anything, mock.Anything, false).Return(nil)
+	mbp.On("SubmitPinnedBatch", mock.Anything, mock.MatchedBy(func(batch *fftypes.Batch) bool {
+		return batch.PayloadRef == "id1" && batch.PublishedAt != nil
+	}), mock.Anything).Return(nil)
+
+	batch := &fftypes.Batch{
+		Payload: fftypes.BatchPayload{TX: fftypes.TransactionRef{
+			Type: fftypes.TransactionTypeBatchPin,
+			ID:   fftypes.NewUUID(),
+		}},
+	}
+	err := bm.dispatchBatch(context.Background(), batch, []*fftypes.Bytes32{fftypes.NewRandB32()})
+	assert.NoError(t, err)
+	assert.NotNil(t, batch.PublishedAt)
+
+	mdi.AssertExpectations(t)
+	mps.AssertExpectations(t)
+	mbp.AssertExpectations(t)
 }
 
 func TestGetOrgIdentityEmpty(t *testing.T) {
@@ -156,7 +322,10 @@ func TestDispatchBatchSubmitBroadcastFail(t *testing.T) {
 	mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything).Return(nil)
 	mps.On("Name").Return("ut_publicstorage")
 
-	err := bm.dispatchBatch(context.Background(), &fftypes.Batch{Author: "wrong"}, []*fftypes.Bytes32{fftypes.NewRandB32()})
+	err := bm.dispatchBatch(context.Background(), &fftypes.Batch{
+		Author:  "wrong",
+		Payload: fftypes.BatchPayload{TX: fftypes.TransactionRef{ID: fftypes.NewUUID()}},
+	}, []*fftypes.Bytes32{fftypes.NewRandB32()})
 	assert.NoError(t, err)
 
 	mdi.On("UpdateBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -233,7 +402,8 @@ func TestSubmitTXAndUpdateDBSucceed(t *testing.T) {
 			},
 			Messages: []*fftypes.Message{
 				{Header: fftypes.MessageHeader{
-					ID: msgID,
+					ID:       msgID,
+					Priority: 3,
 				}},
 			},
 		},
@@ -248,5 +418,37 @@ func TestSubmitTXAndUpdateDBSucceed(t *testing.T) {
 	assert.Equal(t, "ut_publicstorage", op.Plugin)
 	assert.Equal(t, "ipfs_id", op.BackendID)
 	assert.Equal(t, fftypes.OpTypePublicStorageBatchBroadcast, op.Type)
+	assert.Equal(t, uint8(3), op.Priority)
+
+}
+
+func TestSubmitTXAndUpdateDBEphemeral(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mbp := bm.batchpin.(*batchpinmocks.Submitter)
+	mdi.On("UpdateBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mbp.On("SubmitPinnedBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	batch := &fftypes.Batch{
+		Author: "UTNodeID",
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{
+				Type: fftypes.TransactionTypeBatchPin,
+				ID:   fftypes.NewUUID(),
+			},
+			Messages: []*fftypes.Message{
+				{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}, Ephemeral: true},
+			},
+		},
+		PayloadRef: "ipfs_id",
+	}
+
+	err := bm.submitTXAndUpdateDB(context.Background(), batch, []*fftypes.Bytes32{fftypes.NewRandB32()})
+	assert.NoError(t, err)
 
+	mdi.AssertNotCalled(t, "UpsertOperation", mock.Anything, mock.Anything, mock.Anything)
+	mdi.AssertExpectations(t)
+	mbp.AssertExpectations(t)
 }
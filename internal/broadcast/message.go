@@ -18,6 +18,8 @@ package broadcast
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/i18n"
@@ -30,9 +32,76 @@ func (bm *broadcastManager) BroadcastMessage(ctx context.Context, ns string, in
 	return bm.broadcastMessageWithID(ctx, ns, nil, in, nil, waitConfirm)
 }
 
+// BroadcastWithTimeout broadcasts a message and waits for it to be confirmed, giving up once the
+// timeout has elapsed. The deadline is stored on the message, so the batch assembler seals the batch
+// in time for it to be dispatched. A message that misses its deadline is marked with a "timeout" error,
+// which the aggregator clears if the message is confirmed later.
+func (bm *broadcastManager) BroadcastWithTimeout(ctx context.Context, ns string, in *fftypes.MessageInOut, timeout time.Duration) (out *fftypes.Message, err error) {
+	deadline := fftypes.FFTime(time.Now().Add(timeout))
+	in.Deadline = &deadline
+
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	out, err = bm.BroadcastMessage(tctx, ns, in, true)
+	if err == nil || tctx.Err() != context.DeadlineExceeded {
+		return out, err
+	}
+
+	if in.Header.ID != nil {
+		in.Error = "timeout"
+		// Only mark the message if the aggregator has not already confirmed it
+		fb := database.MessageQueryFactory.NewFilter(ctx)
+		filter := fb.And(
+			fb.Eq("id", in.Header.ID),
+			fb.Eq("confirmed", nil),
+		)
+		update := database.MessageQueryFactory.NewUpdate(ctx).Set("error", in.Error)
+		if updateErr := bm.database.UpdateMessages(ctx, filter, update); updateErr != nil {
+			log.L(ctx).Errorf("Failed to mark message %s as timed out: %s", in.Header.ID, updateErr)
+		}
+	}
+	return nil, i18n.NewError(ctx, i18n.MsgBroadcastTimeout, in.Header.ID, timeout)
+}
+
+// BroadcastRawJSON broadcasts an opaque JSON payload, without a datatype or any schema validation.
+// The data is still sealed, and its hash pinned, in exactly the same way as any other broadcast.
+func (bm *broadcastManager) BroadcastRawJSON(ctx context.Context, ns string, data json.RawMessage, tag string) (*fftypes.Message, error) {
+	if !json.Valid(data) {
+		return nil, i18n.NewError(ctx, i18n.MsgSerializationFailed)
+	}
+	in := &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Tag: tag,
+			},
+		},
+		InlineData: fftypes.InlineData{
+			{Validator: fftypes.ValidatorTypeNone, Value: fftypes.Byteable(data)},
+		},
+	}
+	return bm.BroadcastMessage(ctx, ns, in, false)
+}
+
 func (bm *broadcastManager) broadcastMessageWithID(ctx context.Context, ns string, id *fftypes.UUID, unresolved *fftypes.MessageInOut, resolved *fftypes.Message, waitConfirm bool) (out *fftypes.Message, err error) {
+	if unresolved != nil && waitConfirm {
+		// The message ID is allocated by the sync-async bridge when we wait for confirmation, and we
+		// need to know it before we store any inline data - so the data can refer back to the message
+		return bm.syncasync.SendConfirm(ctx, ns, func(requestID *fftypes.UUID) error {
+			_, err := bm.broadcastMessageWithID(ctx, ns, requestID, unresolved, nil, false)
+			return err
+		})
+	}
+	if id == nil {
+		id = fftypes.NewUUID()
+	}
+
 	if unresolved != nil {
 		resolved = &unresolved.Message
+		if unresolved.Pinned != nil && !*unresolved.Pinned {
+			// Other members only discover a broadcast through the pin on the blockchain
+			return nil, i18n.NewError(ctx, i18n.MsgBroadcastMustBePinned)
+		}
+		resolved.Pinned = true
 	}
 	resolved.Header.ID = id
 	resolved.Header.Namespace = ns
@@ -48,8 +117,12 @@ func (bm *broadcastManager) broadcastMessageWithID(ctx context.Context, ns strin
 	var dataToPublish []*fftypes.DataAndBlob
 	err = bm.database.RunAsGroup(ctx, func(ctx context.Context) error {
 		if unresolved != nil {
+			if err = bm.resolveThread(ctx, ns, unresolved); err != nil {
+				return err
+			}
+
 			// The data manager is responsible for the heavy lifting of storing/validating all our in-line data elements
-			resolved.Data, dataToPublish, err = bm.data.ResolveInlineDataBroadcast(ctx, ns, unresolved.InlineData)
+			resolved.Data, dataToPublish, err = bm.data.ResolveInlineDataBroadcast(ctx, ns, resolved.Header.ID, unresolved.InlineData)
 			if err != nil {
 				return err
 			}
@@ -57,9 +130,7 @@ func (bm *broadcastManager) broadcastMessageWithID(ctx context.Context, ns strin
 
 		// If we have data to publish, we break out of the DB transaction, do the publishes, then
 		// do the send later - as that could take a long time (multiple seconds) depending on the size
-		//
-		// Same for waiting for confirmation of the send from the blockchain
-		if len(dataToPublish) > 0 || waitConfirm {
+		if len(dataToPublish) > 0 {
 			return nil
 		}
 
@@ -72,28 +143,54 @@ func (bm *broadcastManager) broadcastMessageWithID(ctx context.Context, ns strin
 
 	// Perform deferred processing
 	if len(dataToPublish) > 0 {
-		return bm.publishBlobsAndSend(ctx, resolved, dataToPublish, waitConfirm)
-	} else if waitConfirm {
-		return bm.broadcastMessageCommon(ctx, resolved, true)
+		return bm.publishBlobsAndSend(ctx, resolved, dataToPublish, false)
 	}
 
 	// The broadcastMessage function modifies the input message to create all the refs
 	return out, err
 }
 
+// resolveThread populates the thread of a message that is a reply to another message, and checks
+// that any thread that is set explicitly refers to the root message of that thread
+func (bm *broadcastManager) resolveThread(ctx context.Context, ns string, in *fftypes.MessageInOut) error {
+	parentID := in.InReplyTo
+	if parentID == nil {
+		parentID = in.Header.Thread
+	}
+	if parentID == nil {
+		return nil
+	}
+	parent, err := bm.database.GetMessageByID(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil || parent.Header.Namespace != ns {
+		return i18n.NewError(ctx, i18n.MsgThreadMessageNotFound, parentID, ns)
+	}
+	root := parent.Header.Thread
+	if root == nil {
+		root = parent.Header.ID
+	}
+	if in.Header.Thread != nil && !in.Header.Thread.Equals(root) {
+		return i18n.NewError(ctx, i18n.MsgThreadNotRoot, in.Header.Thread, root)
+	}
+	in.Header.Thread = root
+	return nil
+}
+
 func (bm *broadcastManager) publishBlobsAndSend(ctx context.Context, msg *fftypes.Message, dataToPublish []*fftypes.DataAndBlob, waitConfirm bool) (*fftypes.Message, error) {
 
 	for _, d := range dataToPublish {
 
 		// Stream from the local data exchange ...
-		reader, err := bm.exchange.DownloadBLOB(ctx, d.Blob.PayloadRef)
+		reader, err := bm.exchangeFor(msg.Header.Namespace).DownloadBLOB(ctx, d.Blob.PayloadRef)
 		if err != nil {
 			return nil, i18n.WrapError(ctx, err, i18n.MsgDownloadBlobFailed, d.Blob.PayloadRef)
 		}
 		defer reader.Close()
 
 		// ... to the public storage
-		publicRef, err := bm.publicstorage.PublishData(ctx, reader)
+		publicRef, err := bm.publicstorageFor(msg.Header.Namespace).PublishData(ctx, reader)
 		if err != nil {
 			return nil, err
 		}
@@ -19,10 +19,12 @@ package broadcast
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
@@ -31,6 +33,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/publicstoragemocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -43,17 +46,19 @@ func TestBroadcastMessageOk(t *testing.T) {
 	mdm := bm.data.(*datamocks.Manager)
 	mbi := bm.blockchain.(*blockchainmocks.Plugin)
 
-	ctx := context.Background()
+	ctx := fftypes.WithCreatedBy(context.Background(), "org1")
 	rag := mdi.On("RunAsGroup", ctx, mock.Anything)
 	rag.RunFn = func(a mock.Arguments) {
 		var fn = a[1].(func(context.Context) error)
 		rag.ReturnArguments = mock.Arguments{fn(a[0].(context.Context))}
 	}
 	mbi.On("VerifyIdentitySyntax", ctx, "0x12345").Return("0x12345", nil)
-	mdm.On("ResolveInlineDataBroadcast", ctx, "ns1", mock.Anything).Return(fftypes.DataRefs{
+	mdm.On("ResolveInlineDataBroadcast", ctx, "ns1", mock.Anything, mock.Anything).Return(fftypes.DataRefs{
 		{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
 	}, []*fftypes.DataAndBlob{}, nil)
-	mdi.On("InsertMessageLocal", ctx, mock.Anything).Return(nil)
+	mdi.On("InsertMessageLocal", ctx, mock.MatchedBy(func(msg *fftypes.Message) bool {
+		return msg.CreatedBy == "org1"
+	})).Return(nil)
 
 	msg, err := bm.BroadcastMessage(ctx, "ns1", &fftypes.MessageInOut{
 		Message: fftypes.Message{
@@ -69,11 +74,72 @@ func TestBroadcastMessageOk(t *testing.T) {
 	assert.NotNil(t, msg.Data[0].ID)
 	assert.NotNil(t, msg.Data[0].Hash)
 	assert.Equal(t, "ns1", msg.Header.Namespace)
+	assert.True(t, msg.Pinned)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageUnpinned(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	pinned := false
+	_, err := bm.BroadcastMessage(context.Background(), "ns1", &fftypes.MessageInOut{
+		InlineData: fftypes.InlineData{
+			{Value: fftypes.Byteable(`{"hello": "world"}`)},
+		},
+		Pinned: &pinned,
+	}, false)
+	assert.Regexp(t, "FF10345", err)
+}
+
+func TestBroadcastRawJSONOk(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdm := bm.data.(*datamocks.Manager)
+	mbi := bm.blockchain.(*blockchainmocks.Plugin)
+
+	ctx := context.Background()
+	rag := mdi.On("RunAsGroup", ctx, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		var fn = a[1].(func(context.Context) error)
+		rag.ReturnArguments = mock.Arguments{fn(a[0].(context.Context))}
+	}
+	mbi.On("VerifyIdentitySyntax", ctx, mock.Anything).Return("0x12345", nil)
+	var createdFrom *fftypes.UUID
+	mdm.On("ResolveInlineDataBroadcast", ctx, "ns1", mock.Anything, mock.MatchedBy(func(in fftypes.InlineData) bool {
+		return len(in) == 1 &&
+			in[0].Validator == fftypes.ValidatorTypeNone &&
+			in[0].Datatype == nil &&
+			string(in[0].Value) == `[1,"two",{"three":3}]`
+	})).Run(func(args mock.Arguments) {
+		createdFrom = args[2].(*fftypes.UUID)
+	}).Return(fftypes.DataRefs{
+		{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+	}, []*fftypes.DataAndBlob{}, nil)
+	mdi.On("InsertMessageLocal", ctx, mock.Anything).Return(nil)
+
+	msg, err := bm.BroadcastRawJSON(ctx, "ns1", json.RawMessage(`[1,"two",{"three":3}]`), "bootstrap")
+	assert.NoError(t, err)
+	assert.Equal(t, createdFrom, msg.Header.ID)
+	assert.Equal(t, "bootstrap", msg.Header.Tag)
+	assert.Equal(t, fftypes.MessageTypeBroadcast, msg.Header.Type)
+	assert.NotNil(t, msg.Header.DataHash)
 
 	mdi.AssertExpectations(t)
 	mdm.AssertExpectations(t)
 }
 
+func TestBroadcastRawJSONInvalid(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	_, err := bm.BroadcastRawJSON(context.Background(), "ns1", json.RawMessage(`{"not":json`), "")
+	assert.Regexp(t, "FF10137", err)
+}
+
 func TestBroadcastMessageWaitConfirmOk(t *testing.T) {
 	bm, cancel := newTestBroadcast(t)
 	defer cancel()
@@ -89,11 +155,11 @@ func TestBroadcastMessageWaitConfirmOk(t *testing.T) {
 		rag.ReturnArguments = mock.Arguments{fn(a[0].(context.Context))}
 	}
 	mbi.On("VerifyIdentitySyntax", ctx, "0x12345").Return("0x12345", nil)
-	mdm.On("ResolveInlineDataBroadcast", ctx, "ns1", mock.Anything).Return(fftypes.DataRefs{
+	requestID := fftypes.NewUUID()
+	mdm.On("ResolveInlineDataBroadcast", ctx, "ns1", requestID, mock.Anything).Return(fftypes.DataRefs{
 		{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
 	}, []*fftypes.DataAndBlob{}, nil)
 
-	requestID := fftypes.NewUUID()
 	replyMsg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			Namespace: "ns1",
@@ -128,6 +194,97 @@ func TestBroadcastMessageWaitConfirmOk(t *testing.T) {
 	mdm.AssertExpectations(t)
 }
 
+func newTestBroadcastTimeoutMocks(bm *broadcastManager) {
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdm := bm.data.(*datamocks.Manager)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		var fn = a[1].(func(context.Context) error)
+		rag.ReturnArguments = mock.Arguments{fn(a[0].(context.Context))}
+	}
+	mdm.On("ResolveInlineDataBroadcast", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(fftypes.DataRefs{
+		{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+	}, []*fftypes.DataAndBlob{}, nil)
+	mdi.On("InsertMessageLocal", mock.Anything, mock.Anything).Return(nil)
+}
+
+func TestBroadcastWithTimeoutOk(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	newTestBroadcastTimeoutMocks(bm)
+	msa := bm.syncasync.(*syncasyncmocks.Bridge)
+
+	replyMsg := &fftypes.Message{}
+	msa.On("SendConfirm", mock.Anything, "ns1", mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args[2].(syncasync.RequestSender)
+			send(fftypes.NewUUID())
+		}).
+		Return(replyMsg, nil)
+
+	in := &fftypes.MessageInOut{
+		InlineData: fftypes.InlineData{
+			{Value: fftypes.Byteable(`{"hello": "world"}`)},
+		},
+	}
+	msg, err := bm.BroadcastWithTimeout(context.Background(), "ns1", in, 1*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, replyMsg, msg)
+	assert.NotNil(t, in.Deadline)
+	assert.Empty(t, in.Error)
+}
+
+func TestBroadcastWithTimeoutExpired(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	newTestBroadcastTimeoutMocks(bm)
+	mdi := bm.database.(*databasemocks.Plugin)
+	msa := bm.syncasync.(*syncasyncmocks.Bridge)
+
+	requestID := fftypes.NewUUID()
+	msa.On("SendConfirm", mock.Anything, "ns1", mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args[2].(syncasync.RequestSender)
+			send(requestID)
+			<-args[0].(context.Context).Done()
+		}).
+		Return(nil, fmt.Errorf("pop"))
+	mdi.On("UpdateMessages", mock.Anything, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == fmt.Sprintf("( id == '%s' ) && ( confirmed == null )", requestID)
+	}), mock.Anything).Return(fmt.Errorf("pop"))
+
+	in := &fftypes.MessageInOut{
+		InlineData: fftypes.InlineData{
+			{Value: fftypes.Byteable(`{"hello": "world"}`)},
+		},
+	}
+	_, err := bm.BroadcastWithTimeout(context.Background(), "ns1", in, 10*time.Millisecond)
+	assert.Regexp(t, "FF10305", err)
+	assert.Equal(t, "timeout", in.Error)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestBroadcastWithTimeoutExpiredBeforeSend(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	newTestBroadcastTimeoutMocks(bm)
+	mdi := bm.database.(*databasemocks.Plugin)
+	msa := bm.syncasync.(*syncasyncmocks.Bridge)
+
+	msa.On("SendConfirm", mock.Anything, "ns1", mock.Anything).
+		Run(func(args mock.Arguments) {
+			<-args[0].(context.Context).Done()
+		}).
+		Return(nil, fmt.Errorf("pop"))
+
+	_, err := bm.BroadcastWithTimeout(context.Background(), "ns1", &fftypes.MessageInOut{}, 10*time.Millisecond)
+	assert.Regexp(t, "FF10305", err)
+
+	mdi.AssertNotCalled(t, "UpdateMessages", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestBroadcastMessageWithBlobsOk(t *testing.T) {
 	bm, cancel := newTestBroadcast(t)
 	defer cancel()
@@ -147,7 +304,7 @@ func TestBroadcastMessageWithBlobsOk(t *testing.T) {
 		rag.ReturnArguments = mock.Arguments{fn(a[0].(context.Context))}
 	}
 	mbi.On("VerifyIdentitySyntax", ctx, "0x12345").Return("0x12345", nil)
-	mdm.On("ResolveInlineDataBroadcast", ctx, "ns1", mock.Anything).Return(fftypes.DataRefs{
+	mdm.On("ResolveInlineDataBroadcast", ctx, "ns1", mock.Anything, mock.Anything).Return(fftypes.DataRefs{
 		{ID: dataID, Hash: fftypes.NewRandB32()},
 	}, []*fftypes.DataAndBlob{
 		{
@@ -208,7 +365,7 @@ func TestBroadcastMessageBadInput(t *testing.T) {
 		var fn = a[1].(func(context.Context) error)
 		rag.ReturnArguments = mock.Arguments{fn(a[0].(context.Context))}
 	}
-	mdm.On("ResolveInlineDataBroadcast", ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mdm.On("ResolveInlineDataBroadcast", ctx, "ns1", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
 
 	_, err := bm.BroadcastMessage(ctx, "ns1", &fftypes.MessageInOut{
 		InlineData: fftypes.InlineData{
@@ -368,3 +525,114 @@ func TestPublishBlobsDownloadFail(t *testing.T) {
 
 	mdi.AssertExpectations(t)
 }
+
+func newTestThreadedBroadcast(t *testing.T) (*broadcastManager, func(), *databasemocks.Plugin) {
+	bm, cancel := newTestBroadcast(t)
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdm := bm.data.(*datamocks.Manager)
+	mbi := bm.blockchain.(*blockchainmocks.Plugin)
+
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		var fn = a[1].(func(context.Context) error)
+		rag.ReturnArguments = mock.Arguments{fn(a[0].(context.Context))}
+	}
+	mbi.On("VerifyIdentitySyntax", mock.Anything, "0x12345").Return("0x12345", nil)
+	mdm.On("ResolveInlineDataBroadcast", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(fftypes.DataRefs{
+		{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+	}, []*fftypes.DataAndBlob{}, nil)
+	mdi.On("InsertMessageLocal", mock.Anything, mock.Anything).Return(nil)
+	return bm, cancel, mdi
+}
+
+func newTestThreadedMessage(inReplyTo, thread *fftypes.UUID) *fftypes.MessageInOut {
+	return &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Author: "0x12345",
+				Thread: thread,
+			},
+		},
+		InlineData: fftypes.InlineData{
+			{Value: fftypes.Byteable(`{"hello": "world"}`)},
+		},
+		InReplyTo: inReplyTo,
+	}
+}
+
+func TestBroadcastMessageThreadContinuity(t *testing.T) {
+	bm, cancel, mdi := newTestThreadedBroadcast(t)
+	defer cancel()
+	ctx := context.Background()
+
+	root := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1"}}
+	reply1 := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1", Thread: root.Header.ID}}
+	mdi.On("GetMessageByID", ctx, root.Header.ID).Return(root, nil)
+	mdi.On("GetMessageByID", ctx, reply1.Header.ID).Return(reply1, nil)
+
+	// Reply to the root message starts the thread
+	msg, err := bm.BroadcastMessage(ctx, "ns1", newTestThreadedMessage(root.Header.ID, nil), false)
+	assert.NoError(t, err)
+	assert.Equal(t, *root.Header.ID, *msg.Header.Thread)
+
+	// Reply to a reply continues the same thread
+	msg, err = bm.BroadcastMessage(ctx, "ns1", newTestThreadedMessage(reply1.Header.ID, nil), false)
+	assert.NoError(t, err)
+	assert.Equal(t, *root.Header.ID, *msg.Header.Thread)
+
+	// Reply with the matching thread set explicitly
+	msg, err = bm.BroadcastMessage(ctx, "ns1", newTestThreadedMessage(reply1.Header.ID, root.Header.ID), false)
+	assert.NoError(t, err)
+	assert.Equal(t, *root.Header.ID, *msg.Header.Thread)
+
+	// Thread set explicitly to the root, without a reply
+	msg, err = bm.BroadcastMessage(ctx, "ns1", newTestThreadedMessage(nil, root.Header.ID), false)
+	assert.NoError(t, err)
+	assert.Equal(t, *root.Header.ID, *msg.Header.Thread)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestBroadcastMessageThreadNotRoot(t *testing.T) {
+	bm, cancel, mdi := newTestThreadedBroadcast(t)
+	defer cancel()
+	ctx := context.Background()
+
+	rootID := fftypes.NewUUID()
+	reply1 := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1", Thread: rootID}}
+	mdi.On("GetMessageByID", ctx, reply1.Header.ID).Return(reply1, nil)
+
+	_, err := bm.BroadcastMessage(ctx, "ns1", newTestThreadedMessage(nil, reply1.Header.ID), false)
+	assert.Regexp(t, "FF10291", err)
+
+	_, err = bm.BroadcastMessage(ctx, "ns1", newTestThreadedMessage(reply1.Header.ID, fftypes.NewUUID()), false)
+	assert.Regexp(t, "FF10291", err)
+}
+
+func TestBroadcastMessageThreadParentNotFound(t *testing.T) {
+	bm, cancel, mdi := newTestThreadedBroadcast(t)
+	defer cancel()
+	ctx := context.Background()
+
+	otherNS := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns2"}}
+	mdi.On("GetMessageByID", ctx, otherNS.Header.ID).Return(otherNS, nil)
+	missingID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", ctx, missingID).Return(nil, nil)
+
+	_, err := bm.BroadcastMessage(ctx, "ns1", newTestThreadedMessage(missingID, nil), false)
+	assert.Regexp(t, "FF10290", err)
+
+	_, err = bm.BroadcastMessage(ctx, "ns1", newTestThreadedMessage(otherNS.Header.ID, nil), false)
+	assert.Regexp(t, "FF10290", err)
+}
+
+func TestBroadcastMessageThreadLookupFail(t *testing.T) {
+	bm, cancel, mdi := newTestThreadedBroadcast(t)
+	defer cancel()
+	ctx := context.Background()
+
+	mdi.On("GetMessageByID", ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := bm.BroadcastMessage(ctx, "ns1", newTestThreadedMessage(fftypes.NewUUID(), nil), false)
+	assert.Regexp(t, "pop", err)
+}
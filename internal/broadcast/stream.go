@@ -0,0 +1,92 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broadcast
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// StreamError is written to the errors channel of BroadcastStream when an individual
+// message fails to be submitted, carrying the ExternalID the caller supplied on the input
+type StreamError struct {
+	ExternalID string
+	Err        error
+}
+
+func (se *StreamError) Error() string {
+	return se.Err.Error()
+}
+
+func (se *StreamError) Unwrap() error {
+	return se.Err
+}
+
+// BroadcastStream submits every message read from the input channel, until the channel is closed
+// or the context is cancelled. Up to broadcast.stream.concurrency messages are submitted in parallel,
+// and reading from the input blocks while that many are in flight. Failures for individual messages
+// are written to the errors channel (if non-nil) as a *StreamError, and do not stop the stream.
+// This is an experimental feature, that must be enabled with features.broadcastStream=true
+func (bm *broadcastManager) BroadcastStream(ctx context.Context, ns string, in <-chan *fftypes.MessageInOut, errors chan<- error) error {
+	if err := config.RequireFeature(ctx, config.FeatureBroadcastStream); err != nil {
+		return err
+	}
+	concurrency := config.GetInt(config.BroadcastStreamConcurrency)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		var msg *fftypes.MessageInOut
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return i18n.NewError(ctx, i18n.MsgContextCanceled)
+		case msg, ok = <-in:
+			if !ok {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return i18n.NewError(ctx, i18n.MsgContextCanceled)
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(msg *fftypes.MessageInOut) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if _, err := bm.BroadcastMessage(ctx, ns, msg, false); err != nil && errors != nil {
+				select {
+				case errors <- &StreamError{ExternalID: msg.ExternalID, Err: err}:
+				case <-ctx.Done():
+				}
+			}
+		}(msg)
+	}
+}
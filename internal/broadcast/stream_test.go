@@ -0,0 +1,146 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBroadcastStreamOneFailure(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	config.SetFeatureEnabled(config.FeatureBroadcastStream, true)
+	config.Set(config.BroadcastStreamConcurrency, 3)
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdm := bm.data.(*datamocks.Manager)
+
+	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	isBad := func(data fftypes.InlineData) bool {
+		return data[0].Value.String() == `"bad"`
+	}
+	mdm.On("ResolveInlineDataBroadcast", mock.Anything, "ns1", mock.Anything, mock.MatchedBy(isBad)).Return(nil, nil, fmt.Errorf("pop"))
+	mdm.On("ResolveInlineDataBroadcast", mock.Anything, "ns1", mock.Anything, mock.MatchedBy(func(data fftypes.InlineData) bool {
+		return !isBad(data)
+	})).Return(fftypes.DataRefs{
+		{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+	}, []*fftypes.DataAndBlob{}, nil)
+	mdi.On("InsertMessageLocal", mock.Anything, mock.Anything).Return(nil)
+
+	in := make(chan *fftypes.MessageInOut)
+	errors := make(chan error, 10)
+	go func() {
+		for i := 0; i < 10; i++ {
+			value := fftypes.Byteable(`"good"`)
+			if i == 7 {
+				value = fftypes.Byteable(`"bad"`)
+			}
+			in <- &fftypes.MessageInOut{
+				InlineData: fftypes.InlineData{{Value: value}},
+				ExternalID: fmt.Sprintf("ext%d", i),
+			}
+		}
+		close(in)
+	}()
+
+	err := bm.BroadcastStream(context.Background(), "ns1", in, errors)
+	assert.NoError(t, err)
+	close(errors)
+
+	var streamErrors []error
+	for err := range errors {
+		streamErrors = append(streamErrors, err)
+	}
+	assert.Len(t, streamErrors, 1)
+	assert.Equal(t, "ext7", streamErrors[0].(*StreamError).ExternalID)
+	assert.Regexp(t, "pop", streamErrors[0])
+	assert.Regexp(t, "pop", streamErrors[0].(*StreamError).Unwrap())
+	mdi.AssertNumberOfCalls(t, "InsertMessageLocal", 9)
+}
+
+func TestBroadcastStreamNoErrorChannel(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	config.SetFeatureEnabled(config.FeatureBroadcastStream, true)
+	config.Set(config.BroadcastStreamConcurrency, 0)
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	in := make(chan *fftypes.MessageInOut, 1)
+	in <- &fftypes.MessageInOut{}
+	close(in)
+
+	err := bm.BroadcastStream(context.Background(), "ns1", in, nil)
+	assert.NoError(t, err)
+	mdi.AssertExpectations(t)
+}
+
+func TestBroadcastStreamCancelledWaitingInput(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	config.SetFeatureEnabled(config.FeatureBroadcastStream, true)
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	ctxCancel()
+	err := bm.BroadcastStream(ctx, "ns1", make(chan *fftypes.MessageInOut), nil)
+	assert.Regexp(t, "FF10158", err)
+}
+
+func TestBroadcastStreamCancelledWaitingConcurrency(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	config.SetFeatureEnabled(config.FeatureBroadcastStream, true)
+	config.Set(config.BroadcastStreamConcurrency, 1)
+	mdi := bm.database.(*databasemocks.Plugin)
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	blocked := make(chan struct{})
+	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		close(blocked)
+		<-ctx.Done()
+		return fmt.Errorf("pop")
+	})
+
+	in := make(chan *fftypes.MessageInOut, 2)
+	in <- &fftypes.MessageInOut{}
+	in <- &fftypes.MessageInOut{}
+	go func() {
+		<-blocked
+		ctxCancel()
+	}()
+
+	err := bm.BroadcastStream(ctx, "ns1", in, make(chan error))
+	assert.Regexp(t, "FF10158", err)
+}
+
+func TestBroadcastStreamFeatureNotEnabled(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	err := bm.BroadcastStream(context.Background(), "ns1", make(chan *fftypes.MessageInOut), nil)
+	assert.Regexp(t, "FF10323.*broadcastStream", err)
+}
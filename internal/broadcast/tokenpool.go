@@ -30,7 +30,13 @@ func (bm *broadcastManager) BroadcastTokenPool(ctx context.Context, ns string, p
 		return nil, err
 	}
 
-	msg, err = bm.broadcastDefinitionAsNode(ctx, pool, fftypes.SystemTagDefinePool, waitConfirm)
+	signingIdentity, err := bm.GetNodeSigningIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pools are sent with their own message type, so they can be filtered separately from other definitions
+	msg, err = bm.broadcastDefinitionType(ctx, pool, signingIdentity, fftypes.MessageTypeTokenPoolDefinition, fftypes.SystemTagDefinePool, waitConfirm)
 	if msg != nil {
 		pool.Message = msg.Header.ID
 	}
@@ -21,8 +21,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/mocks/identitymocks"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -78,6 +80,36 @@ func TestBroadcastTokenPoolInvalid(t *testing.T) {
 	mdm.AssertExpectations(t)
 }
 
+func TestBroadcastTokenPoolIdentityFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mii := bm.identity.(*identitymocks.Plugin)
+
+	config.Set(config.OrgIdentity, "wrong")
+
+	pool := &fftypes.TokenPoolAnnouncement{
+		TokenPool: fftypes.TokenPool{
+			ID:         fftypes.NewUUID(),
+			Namespace:  "ns1",
+			Name:       "mypool",
+			Type:       fftypes.TokenTypeNonFungible,
+			ProtocolID: "N1",
+			Symbol:     "COIN",
+		},
+		ProtocolTxID: "tx123",
+	}
+
+	mdm.On("VerifyNamespaceExists", mock.Anything, "ns1").Return(nil)
+	mii.On("Resolve", mock.Anything, "wrong").Return(nil, fmt.Errorf("pop"))
+
+	_, err := bm.BroadcastTokenPool(context.Background(), "ns1", pool, false)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+	mii.AssertExpectations(t)
+}
+
 func TestBroadcastTokenPoolBroadcastFail(t *testing.T) {
 	bm, cancel := newTestBroadcast(t)
 	defer cancel()
@@ -127,7 +159,9 @@ func TestBroadcastTokenPoolOk(t *testing.T) {
 
 	mdm.On("VerifyNamespaceExists", mock.Anything, "ns1").Return(nil)
 	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(nil)
-	mdi.On("InsertMessageLocal", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("InsertMessageLocal", mock.Anything, mock.MatchedBy(func(msg *fftypes.Message) bool {
+		return msg.Header.Type == fftypes.MessageTypeTokenPoolDefinition && msg.Header.Tag == string(fftypes.SystemTagDefinePool)
+	})).Return(nil)
 
 	_, err := bm.BroadcastTokenPool(context.Background(), "ns1", pool, false)
 	assert.NoError(t, err)
@@ -41,6 +41,12 @@ import (
 // The following keys can be access from the root configuration.
 // Plugins are resonsible for defining their own keys using the Config interface
 var (
+	// APICacheMaxEntries is the maximum number of request URIs for which the last issued ETag is remembered
+	APICacheMaxEntries = rootKey("api.cache.maxEntries")
+	// APICacheTTL is how long the ETag issued for a request URI is remembered
+	APICacheTTL = rootKey("api.cache.ttl")
+	// APICompressionMinSize is the minimum size of a JSON response body before it is gzip compressed, for clients that accept it
+	APICompressionMinSize = rootKey("api.compression.minSize")
 	// APIDefaultFilterLimit is the default limit that will be applied to filtered queries on the API
 	APIDefaultFilterLimit = rootKey("api.defaultFilterLimit")
 	// APIMaxFilterLimit is the maximum limit that can be specified by an API call
@@ -51,6 +57,16 @@ var (
 	APIRequestTimeout = rootKey("api.requestTimeout")
 	// APIRequestMaxTimeout is the maximum timeout an application can set using a Request-Timeout header
 	APIRequestMaxTimeout = rootKey("api.requestMaxTimeout")
+	// APIRateLimitEnabled is whether per-IP rate limiting is applied to requests on the API
+	APIRateLimitEnabled = rootKey("api.rateLimit.enabled")
+	// APIRateLimitRequestsPerSecond is the sustained number of requests per second allowed from a single IP
+	APIRateLimitRequestsPerSecond = rootKey("api.rateLimit.requestsPerSecond")
+	// APIRateLimitBurst is the number of requests a single IP can make in a burst, above the sustained rate
+	APIRateLimitBurst = rootKey("api.rateLimit.burst")
+	// APIRateLimitIPEvictAfter is how long an IP must be idle before its rate limiting state is discarded
+	APIRateLimitIPEvictAfter = rootKey("api.rateLimit.ipEvictAfter")
+	// APISSEPollInterval is how often a server-sent events stream checks for new events, once it has caught up
+	APISSEPollInterval = rootKey("api.sse.pollInterval")
 	// APIShutdownTimeout is the amount of time to wait for any in-flight requests to finish before killing the HTTP server
 	APIShutdownTimeout = rootKey("api.shutdownTimeout")
 	// BatchManagerReadPageSize is the size of each page of messages read from the database into memory when assembling batches
@@ -63,6 +79,8 @@ var (
 	BatchRetryInitDelay = rootKey("batch.retry.initDelay")
 	// BatchRetryMaxDelay is the maximum delay between retry attempts
 	BatchRetryMaxDelay = rootKey("batch.retry.maxDelay")
+	// BatchStorageCompress enables compression of batch payloads stored in the database
+	BatchStorageCompress = rootKey("batch.storage.compress")
 	// BlockchainType is the name of the blockchain interface plugin being used by this firefly node
 	BlockchainType = rootKey("blockchain.type")
 	// BroadcastBatchAgentTimeout how long to keep around a batching agent for a sending identity before disposal
@@ -71,15 +89,25 @@ var (
 	BroadcastBatchSize = rootKey("broadcast.batch.size")
 	// BroadcastBatchTimeout is the timeout to wait for a batch to fill, before sending
 	BroadcastBatchTimeout = rootKey("broadcast.batch.timeout")
+	// BroadcastDefinitionRetryCount is the number of times a failed broadcast of an org or node definition is retried
+	BroadcastDefinitionRetryCount = rootKey("broadcast.definition.retry.count")
+	// BroadcastDefinitionRetryInitDelay is the initial delay before retrying a failed broadcast of an org or node definition
+	BroadcastDefinitionRetryInitDelay = rootKey("broadcast.definition.retry.initDelay")
+	// BroadcastStreamConcurrency is the maximum number of messages from a broadcast stream that are submitted in parallel
+	BroadcastStreamConcurrency = rootKey("broadcast.stream.concurrency")
 	// PrivateMessagingBatchAgentTimeout how long to keep around a batching agent for a sending identity before disposal
 	PrivateMessagingBatchAgentTimeout = rootKey("privatemessaging.batch.agentTimeout")
 	// PrivateMessagingBatchSize is the maximum size of a batch for broadcast messages
 	PrivateMessagingBatchSize = rootKey("privatemessaging.batch.size")
 	// PrivateMessagingBatchTimeout is the timeout to wait for a batch to fill, before sending
 	PrivateMessagingBatchTimeout = rootKey("privatemessaging.batch.timeout")
+	// PrivateMessagingGroupMaxSize is the maximum number of members allowed in a private messaging group
+	PrivateMessagingGroupMaxSize = rootKey("privatemessaging.group.maxSize")
 	// PrivateMessagingOpCorrelationRetries how many times to correlate an event for an operation (such as tx submission) back to an operation.
 	// Needed because the operation update might come back before we are finished persisting the ID of the request
 	PrivateMessagingOpCorrelationRetries = rootKey("privatemessaging.opCorrelationRetries")
+	// PrivateMessagingReadReceipts whether to send a read receipt back to the group when a private message is marked as read
+	PrivateMessagingReadReceipts = rootKey("privatemessaging.readReceipts")
 	// PrivateMessagingRetryFactor the backoff factor to use for retry of database operations
 	PrivateMessagingRetryFactor = rootKey("privatemessaging.retry.factor")
 	// PrivateMessagingRetryInitDelay the initial delay to use for retry of data base operations
@@ -100,8 +128,12 @@ var (
 	CorsEnabled = rootKey("cors.enabled")
 	// CorsMaxAge is the maximum age a browser should rely on CORS checks
 	CorsMaxAge = rootKey("cors.maxAge")
+	// CryptoType the type of the crypto plugin used to manage the node's encryption key
+	CryptoType = rootKey("crypto.type")
 	// DataexchangeType is the name of the data exchange plugin being used by this firefly node
 	DataexchangeType = rootKey("dataexchange.type")
+	// DataPurgeInterval is how often data items that have passed their expiry time are purged. Zero disables the purge
+	DataPurgeInterval = rootKey("data.purge.interval")
 	// DatabaseType the type of the database interface plugin to use
 	DatabaseType = rootKey("database.type")
 	// TokensList is the root key containing a list of supported token connectors
@@ -129,6 +161,8 @@ var (
 	EventAggregatorRetryInitDelay = rootKey("event.aggregator.retry.initDelay")
 	// EventAggregatorRetryMaxDelay the maximum delay to use for retry of data base operations
 	EventAggregatorRetryMaxDelay = rootKey("event.aggregator.retry.maxDelay")
+	// EventDeadLetterMaxRetries how many times delivery of an event to a subscription is retried, before the event is moved to the dead letter table
+	EventDeadLetterMaxRetries = rootKey("event.deadLetter.maxRetries")
 	// EventDispatcherPollTimeout the time to wait without a notification of new events, before trying a select on the table
 	EventDispatcherPollTimeout = rootKey("event.dispatcher.pollTimeout")
 	// EventDispatcherBufferLength the number of events + attachments an individual dispatcher should hold in memory ready for delivery to the subscription
@@ -143,6 +177,8 @@ var (
 	EventDispatcherRetryMaxDelay = rootKey("event.dispatcher.retry.maxDelay")
 	// EventDBEventsBufferSize the size of the buffer of change events
 	EventDBEventsBufferSize = rootKey("event.dbevents.bufferSize")
+	// EventsSubscriptionPauseBuffer is the maximum number of events buffered for a paused subscription, before further events are dropped
+	EventsSubscriptionPauseBuffer = rootKey("events.subscription.pauseBuffer")
 	// GroupCacheSize cache size for private group addresses
 	GroupCacheSize = rootKey("group.cache.size")
 	// GroupCacheTTL cache time-to-live for private group addresses
@@ -175,24 +211,53 @@ var (
 	LogMaxAge = rootKey("log.maxAge")
 	// LogCompress sets whether to compress backups
 	LogCompress = rootKey("log.compress")
+	// MessageDeleteReadPageSize is the number of messages read from the database in each page, when deleting the messages that match a filter
+	MessageDeleteReadPageSize = rootKey("message.delete.readPageSize")
+	// MessageEphemeralPurgeInterval is how often confirmed ephemeral messages are removed from the database, once their events have been delivered. The purge cannot be disabled, and a non-positive value falls back to the default
+	MessageEphemeralPurgeInterval = rootKey("message.ephemeral.purgeInterval")
+	// NamespacesAdminEnabled is whether namespaced admin operations are allowed, for namespaces that do not set "adminEnabled" in their predefined entry
+	NamespacesAdminEnabled = rootKey("namespaces.adminEnabled")
 	// NamespacesDefault is the default namespace - must be in the predefines list
 	NamespacesDefault = rootKey("namespaces.default")
-	// NamespacesPredefined is a list of namespaces to ensure exists, without requiring a broadcast from the network
+	// NamespacesPredefined is a list of namespaces to ensure exists, without requiring a broadcast from the network.
+	// Each entry can contain a "plugins" object, overriding the blockchain, dataexchange and publicstorage plugin types for that namespace
+	// Each entry can also set "adminEnabled", overriding namespaces.adminEnabled for that namespace
+	// Each entry can also set "readOnly", which rejects API requests that would write to that namespace
 	NamespacesPredefined = rootKey("namespaces.predefined")
 	// NodeName is a description for the node
 	NodeName = rootKey("node.name")
 	// NodeDescription is a description for the node
 	NodeDescription = rootKey("node.description")
+	// NodeHeartbeatInterval is how often heartbeats are sent to the nodes in our groups. Zero disables heartbeats
+	NodeHeartbeatInterval = rootKey("node.heartbeatInterval")
 	// OrgName is the short name o the org
 	OrgName = rootKey("org.name")
 	// OrgIdentity is the signing identity allocated to the organization (can be the same as the nodes)
 	OrgIdentity = rootKey("org.identity")
 	// OrgDescription is a description for the org
 	OrgDescription = rootKey("org.description")
+	// OrgCountry is the ISO 3166-1 alpha-2 code of the country the org is based in
+	OrgCountry = rootKey("org.country")
+	// OperationsDefaultTimeout is the timeout applied to the submission of an operation, if it does not specify its own
+	OperationsDefaultTimeout = rootKey("operations.defaultTimeout")
 	// OrchestratorStartupAttempts is how many time to attempt to connect to core infrastructure on startup
 	OrchestratorStartupAttempts = rootKey("orchestrator.startupAttempts")
+	// PublicStorageGCMinAge is how old the failed pin of a batch must be before its upload to the Public Storage can be garbage collected
+	PublicStorageGCMinAge = rootKey("publicstorage.gc.minAge")
+	// PublicStorageGCReadPageSize is the number of failed batch pins read from the database in each page, when garbage collecting the Public Storage
+	PublicStorageGCReadPageSize = rootKey("publicstorage.gc.readPageSize")
 	// PublicStorageType specifies which public storage interface plugin to use
 	PublicStorageType = rootKey("publicstorage.type")
+	// SecretsProvider selects where secrets referenced from config are retrieved from - "vault", or empty for none
+	SecretsProvider = rootKey("secrets.provider")
+	// SecretsVaultURL is the base URL of the HashiCorp Vault server
+	SecretsVaultURL = rootKey("secrets.vault.url")
+	// SecretsVaultToken is the token used to authenticate to Vault (recommended to be supplied via the FIREFLY_SECRETS_VAULT_TOKEN env var)
+	SecretsVaultToken = rootKey("secrets.vault.token")
+	// SecretsVaultRequestTimeout is the timeout for requests to Vault
+	SecretsVaultRequestTimeout = rootKey("secrets.vault.requestTimeout")
+	// SecretsVaultCacheTTL is how long a secret retrieved from Vault is cached before it is retrieved again
+	SecretsVaultCacheTTL = rootKey("secrets.vault.cacheTTL")
 	// SubscriptionDefaultsReadAhead default read ahead to enable for subscriptions that do not explicitly configure readahead
 	SubscriptionDefaultsReadAhead = rootKey("subscription.defaults.batchSize")
 	// SubscriptionMax maximum number of pre-defined subscriptions that can exist (note for high fan-out consider connecting a dedicated pub/sub broker to the dispatcher)
@@ -263,15 +328,24 @@ type RootKey string
 
 func Reset() {
 	viper.Reset()
+	resetSecretManager()
 
 	// Set defaults
+	viper.SetDefault(string(APICacheMaxEntries), 1000)
+	viper.SetDefault(string(APICacheTTL), "1h")
+	viper.SetDefault(string(APICompressionMinSize), "1Kb")
 	viper.SetDefault(string(APIDefaultFilterLimit), 25)
 	viper.SetDefault(string(APIRequestTimeout), "120s")
 	viper.SetDefault(string(APIRequestMaxTimeout), "10m")
 	viper.SetDefault(string(APIMaxFilterLimit), 250)
 	viper.SetDefault(string(APIMaxFilterSkip), 1000) // protects database (skip+limit pagination is not for bulk operations)
 	viper.SetDefault(string(APIRequestTimeout), "120s")
+	viper.SetDefault(string(APISSEPollInterval), "500ms")
 	viper.SetDefault(string(APIShutdownTimeout), "10s")
+	viper.SetDefault(string(APIRateLimitEnabled), false)
+	viper.SetDefault(string(APIRateLimitRequestsPerSecond), 10)
+	viper.SetDefault(string(APIRateLimitBurst), 20)
+	viper.SetDefault(string(APIRateLimitIPEvictAfter), "10m")
 	viper.SetDefault(string(BatchManagerReadPageSize), 100)
 	viper.SetDefault(string(BatchManagerReadPollTimeout), "30s")
 	viper.SetDefault(string(BatchRetryFactor), 2.0)
@@ -280,16 +354,22 @@ func Reset() {
 	viper.SetDefault(string(BatchRetryInitDelay), "250ms")
 	viper.SetDefault(string(BatchRetryMaxDelay), "30s")
 	viper.SetDefault(string(BatchRetryMaxDelay), "30s")
+	viper.SetDefault(string(BatchStorageCompress), false)
 	viper.SetDefault(string(BroadcastBatchAgentTimeout), "2m")
 	viper.SetDefault(string(BroadcastBatchSize), 200)
 	viper.SetDefault(string(BroadcastBatchTimeout), "1s")
+	viper.SetDefault(string(BroadcastDefinitionRetryCount), 5)
+	viper.SetDefault(string(BroadcastDefinitionRetryInitDelay), "250ms")
+	viper.SetDefault(string(BroadcastStreamConcurrency), 10)
 	viper.SetDefault(string(CorsAllowCredentials), true)
 	viper.SetDefault(string(CorsAllowedHeaders), []string{"*"})
 	viper.SetDefault(string(CorsAllowedMethods), []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete})
 	viper.SetDefault(string(CorsAllowedOrigins), []string{"*"})
 	viper.SetDefault(string(CorsEnabled), true)
 	viper.SetDefault(string(CorsMaxAge), 600)
+	viper.SetDefault(string(CryptoType), "localkeys")
 	viper.SetDefault(string(DataexchangeType), "https")
+	viper.SetDefault(string(DataPurgeInterval), "0")
 	viper.SetDefault(string(DebugPort), -1)
 	viper.SetDefault(string(EventAggregatorFirstEvent), fftypes.SubOptsFirstEventOldest)
 	viper.SetDefault(string(EventAggregatorBatchSize), 50)
@@ -300,6 +380,8 @@ func Reset() {
 	viper.SetDefault(string(EventAggregatorRetryMaxDelay), "30s")
 	viper.SetDefault(string(EventAggregatorOpCorrelationRetries), 3)
 	viper.SetDefault(string(EventDBEventsBufferSize), 100)
+	viper.SetDefault(string(EventsSubscriptionPauseBuffer), 100)
+	viper.SetDefault(string(EventDeadLetterMaxRetries), 3)
 	viper.SetDefault(string(EventDispatcherBufferLength), 5)
 	viper.SetDefault(string(EventDispatcherBatchTimeout), "0")
 	viper.SetDefault(string(EventDispatcherPollTimeout), "30s")
@@ -316,16 +398,27 @@ func Reset() {
 	viper.SetDefault(string(LogFilesize), "100m")
 	viper.SetDefault(string(LogMaxAge), "24h")
 	viper.SetDefault(string(LogMaxBackups), 2)
+	viper.SetDefault(string(MessageDeleteReadPageSize), 100)
+	viper.SetDefault(string(MessageEphemeralPurgeInterval), "1m")
+	viper.SetDefault(string(NamespacesAdminEnabled), false)
 	viper.SetDefault(string(NamespacesDefault), "default")
 	viper.SetDefault(string(NamespacesPredefined), fftypes.JSONObjectArray{{"name": "default", "description": "Default predefined namespace"}})
+	viper.SetDefault(string(NodeHeartbeatInterval), "0")
+	viper.SetDefault(string(OperationsDefaultTimeout), "5m")
 	viper.SetDefault(string(OrchestratorStartupAttempts), 5)
 	viper.SetDefault(string(PrivateMessagingRetryFactor), 2.0)
 	viper.SetDefault(string(PrivateMessagingRetryInitDelay), "100ms")
 	viper.SetDefault(string(PrivateMessagingRetryMaxDelay), "30s")
 	viper.SetDefault(string(PrivateMessagingOpCorrelationRetries), 3)
+	viper.SetDefault(string(PrivateMessagingGroupMaxSize), 100)
+	viper.SetDefault(string(PrivateMessagingReadReceipts), false)
 	viper.SetDefault(string(PrivateMessagingBatchAgentTimeout), "2m")
 	viper.SetDefault(string(PrivateMessagingBatchSize), 200)
 	viper.SetDefault(string(PrivateMessagingBatchTimeout), "1s")
+	viper.SetDefault(string(PublicStorageGCMinAge), "24h")
+	viper.SetDefault(string(PublicStorageGCReadPageSize), 100)
+	viper.SetDefault(string(SecretsVaultCacheTTL), "5m")
+	viper.SetDefault(string(SecretsVaultRequestTimeout), "30s")
 	viper.SetDefault(string(SubscriptionDefaultsReadAhead), 0)
 	viper.SetDefault(string(SubscriptionMax), 500)
 	viper.SetDefault(string(SubscriptionsRetryInitialDelay), "250ms")
@@ -0,0 +1,51 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/spf13/viper"
+)
+
+// featuresPrefix is the section of the config where experimental features are switched on,
+// with "features.{name}: true". The names are not known keys, as features come and go.
+const featuresPrefix = "features."
+
+const (
+	// FeatureBroadcastStream enables submitting a stream of broadcast messages in one call
+	FeatureBroadcastStream = "broadcastStream"
+)
+
+// IsFeatureEnabled returns true if the named experimental feature has been switched on
+func IsFeatureEnabled(name string) bool {
+	return viper.GetBool(featuresPrefix + name)
+}
+
+// RequireFeature returns an error if the named experimental feature has not been switched on
+func RequireFeature(ctx context.Context, name string) error {
+	if !IsFeatureEnabled(name) {
+		return i18n.NewError(ctx, i18n.MsgFeatureNotEnabled, name)
+	}
+	return nil
+}
+
+// SetFeatureEnabled switches an experimental feature on or off (primarily for unit tests)
+func SetFeatureEnabled(name string, enabled bool) {
+	viper.Set(featuresPrefix+name, enabled)
+}
@@ -0,0 +1,58 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureEnabled(t *testing.T) {
+	Reset()
+	defer Reset()
+	SetFeatureEnabled("myFeature", true)
+	assert.True(t, IsFeatureEnabled("myFeature"))
+	assert.NoError(t, RequireFeature(context.Background(), "myFeature"))
+}
+
+func TestFeatureEnabledFromConfig(t *testing.T) {
+	Reset()
+	defer Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader("features:\n  myFeature: true\n"))
+	assert.NoError(t, err)
+	assert.True(t, IsFeatureEnabled("myFeature"))
+}
+
+func TestFeatureDisabled(t *testing.T) {
+	Reset()
+	defer Reset()
+	SetFeatureEnabled("myFeature", false)
+	assert.False(t, IsFeatureEnabled("myFeature"))
+	assert.Regexp(t, "FF10323.*myFeature", RequireFeature(context.Background(), "myFeature"))
+}
+
+func TestFeatureUnknown(t *testing.T) {
+	Reset()
+	defer Reset()
+	assert.False(t, IsFeatureEnabled("unknown"))
+	assert.Regexp(t, "FF10323.*unknown", RequireFeature(context.Background(), "unknown"))
+}
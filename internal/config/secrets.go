@@ -0,0 +1,176 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+)
+
+// SecretManager retrieves secrets, such as private keys and API tokens, that are
+// referenced by path from the config rather than being stored in it as plaintext
+type SecretManager interface {
+	GetSecret(ctx context.Context, path string) (string, error)
+}
+
+const (
+	// SecretsProviderVault retrieves secrets from a HashiCorp Vault server
+	SecretsProviderVault = "vault"
+
+	// defaultSecretField is the field read from a secret, if the path does not specify one with a "#field" suffix
+	defaultSecretField = "value"
+)
+
+var (
+	secretManager    SecretManager
+	secretManagerMux sync.Mutex
+)
+
+func resetSecretManager() {
+	secretManagerMux.Lock()
+	defer secretManagerMux.Unlock()
+	secretManager = nil
+}
+
+// SetSecretManager overrides the secret manager built from config (primarily for unit tests)
+func SetSecretManager(sm SecretManager) {
+	secretManagerMux.Lock()
+	defer secretManagerMux.Unlock()
+	secretManager = sm
+}
+
+// GetSecretManager returns the secret manager for the configured provider, or nil if none is configured
+func GetSecretManager(ctx context.Context) (SecretManager, error) {
+	secretManagerMux.Lock()
+	defer secretManagerMux.Unlock()
+	if secretManager != nil {
+		return secretManager, nil
+	}
+	switch provider := GetString(SecretsProvider); provider {
+	case "":
+		return nil, nil
+	case SecretsProviderVault:
+		secretManager = NewHashiCorpVaultSecretManager(
+			GetString(SecretsVaultURL),
+			GetString(SecretsVaultToken),
+			GetDuration(SecretsVaultRequestTimeout),
+			GetDuration(SecretsVaultCacheTTL),
+		)
+		return secretManager, nil
+	default:
+		return nil, i18n.NewError(ctx, i18n.MsgUnknownSecretsProvider, provider)
+	}
+}
+
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+// HashiCorpVaultSecretManager reads secrets using the Vault HTTP API. Paths are of the
+// form "secret/data/firefly/ethconnect#token", where the optional field suffix selects
+// the key within the secret. Both KV version 1 and version 2 secret engines are supported.
+type HashiCorpVaultSecretManager struct {
+	url      string
+	token    string
+	cacheTTL time.Duration
+	client   *http.Client
+	cache    map[string]*cachedSecret
+	cacheMux sync.Mutex
+}
+
+func NewHashiCorpVaultSecretManager(url, token string, requestTimeout, cacheTTL time.Duration) *HashiCorpVaultSecretManager {
+	return &HashiCorpVaultSecretManager{
+		url:      strings.TrimSuffix(url, "/"),
+		token:    token,
+		cacheTTL: cacheTTL,
+		client:   &http.Client{Timeout: requestTimeout},
+		cache:    make(map[string]*cachedSecret),
+	}
+}
+
+func (v *HashiCorpVaultSecretManager) GetSecret(ctx context.Context, path string) (string, error) {
+	v.cacheMux.Lock()
+	cached, ok := v.cache[path]
+	v.cacheMux.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	// The lock is not held while reading from Vault, so a slow read does not block other secrets
+
+	secretPath, field := path, defaultSecretField
+	if hashIdx := strings.LastIndex(path, "#"); hashIdx >= 0 {
+		secretPath, field = path[0:hashIdx], path[hashIdx+1:]
+	}
+	values, err := v.readSecret(ctx, path, strings.TrimPrefix(secretPath, "/"))
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[field].(string)
+	if !ok {
+		return "", i18n.NewError(ctx, i18n.MsgSecretNotFound, path)
+	}
+	v.cacheMux.Lock()
+	v.cache[path] = &cachedSecret{
+		value:   value,
+		expires: time.Now().Add(v.cacheTTL),
+	}
+	v.cacheMux.Unlock()
+	return value, nil
+}
+
+func (v *HashiCorpVaultSecretManager) readSecret(ctx context.Context, path, secretPath string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s", v.url, secretPath), nil)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgSecretRetrievalFailed, path)
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	res, err := v.client.Do(req)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgSecretRetrievalFailed, path)
+	}
+	defer res.Body.Close()
+	log.L(ctx).Debugf("Vault secret '%s' status=%d", secretPath, res.StatusCode)
+	if res.StatusCode == http.StatusNotFound {
+		return nil, i18n.NewError(ctx, i18n.MsgSecretNotFound, path)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, i18n.WrapError(ctx, fmt.Errorf("status=%d", res.StatusCode), i18n.MsgSecretRetrievalFailed, path)
+	}
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgSecretRetrievalFailed, path)
+	}
+	// KV version 2 nests the secret values, alongside the version metadata
+	if nested, ok := body.Data["data"].(map[string]interface{}); ok {
+		if _, hasMetadata := body.Data["metadata"]; hasMetadata {
+			return nested, nil
+		}
+	}
+	return body.Data, nil
+}
@@ -0,0 +1,193 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVault(t *testing.T, handler http.HandlerFunc) (*HashiCorpVaultSecretManager, func()) {
+	server := httptest.NewServer(handler)
+	Reset()
+	Set(SecretsProvider, SecretsProviderVault)
+	Set(SecretsVaultURL, server.URL+"/")
+	Set(SecretsVaultToken, "vaulttoken")
+	sm, err := GetSecretManager(context.Background())
+	assert.NoError(t, err)
+	return sm.(*HashiCorpVaultSecretManager), func() {
+		server.Close()
+		Reset()
+	}
+}
+
+func TestGetSecretManagerNone(t *testing.T) {
+	Reset()
+	sm, err := GetSecretManager(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, sm)
+}
+
+func TestGetSecretManagerUnknown(t *testing.T) {
+	Reset()
+	Set(SecretsProvider, "wrong")
+	_, err := GetSecretManager(context.Background())
+	assert.Regexp(t, "FF10308", err)
+}
+
+func TestVaultGetSecretKVv2Cached(t *testing.T) {
+	calls := 0
+	sm, done := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/v1/secret/data/firefly/ethconnect", r.URL.Path)
+		assert.Equal(t, "vaulttoken", r.Header.Get("X-Vault-Token"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"data":{"token":"abcd"},"metadata":{"version":1}}}`))
+	})
+	defer done()
+
+	secret, err := sm.GetSecret(context.Background(), "secret/data/firefly/ethconnect#token")
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", secret)
+	secret, err = sm.GetSecret(context.Background(), "secret/data/firefly/ethconnect#token")
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", secret)
+	assert.Equal(t, 1, calls)
+
+	// Check the secret manager is retained
+	sm2, err := GetSecretManager(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, sm, sm2)
+}
+
+func TestVaultGetSecretNotBlockedBySlowRead(t *testing.T) {
+	slowStarted := make(chan struct{})
+	releaseSlow := make(chan struct{})
+	sm, done := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/kv/slow" {
+			close(slowStarted)
+			<-releaseSlow
+		}
+		w.Write([]byte(`{"data":{"value":"abcd"}}`))
+	})
+	defer done()
+
+	_, err := sm.GetSecret(context.Background(), "kv/fast")
+	assert.NoError(t, err)
+
+	slowDone := make(chan error)
+	go func() {
+		_, err := sm.GetSecret(context.Background(), "kv/slow")
+		slowDone <- err
+	}()
+	<-slowStarted
+
+	// The cached secret is returned while the other secret is still being read from Vault
+	secret, err := sm.GetSecret(context.Background(), "kv/fast")
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", secret)
+
+	close(releaseSlow)
+	assert.NoError(t, <-slowDone)
+}
+
+func TestVaultGetSecretKVv1DefaultFieldExpired(t *testing.T) {
+	calls := 0
+	sm, done := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/v1/kv/firefly", r.URL.Path)
+		w.Write([]byte(`{"data":{"value":"abcd","data":{"nested":"true"}}}`))
+	})
+	defer done()
+	sm.cacheTTL = -1 * time.Second
+
+	secret, err := sm.GetSecret(context.Background(), "/kv/firefly")
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", secret)
+	_, err = sm.GetSecret(context.Background(), "/kv/firefly")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestVaultGetSecretFieldMissing(t *testing.T) {
+	sm, done := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"other":"abcd"}}`))
+	})
+	defer done()
+
+	_, err := sm.GetSecret(context.Background(), "kv/firefly")
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestVaultGetSecretNotFound(t *testing.T) {
+	sm, done := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	})
+	defer done()
+
+	_, err := sm.GetSecret(context.Background(), "kv/firefly")
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestVaultGetSecretForbidden(t *testing.T) {
+	sm, done := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(403)
+	})
+	defer done()
+
+	_, err := sm.GetSecret(context.Background(), "kv/firefly")
+	assert.Regexp(t, "FF10309.*403", err)
+}
+
+func TestVaultGetSecretBadJSON(t *testing.T) {
+	sm, done := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`!json`))
+	})
+	defer done()
+
+	_, err := sm.GetSecret(context.Background(), "kv/firefly")
+	assert.Regexp(t, "FF10309", err)
+}
+
+func TestVaultGetSecretConnectFail(t *testing.T) {
+	sm, done := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {})
+	done()
+
+	_, err := sm.GetSecret(context.Background(), "kv/firefly")
+	assert.Regexp(t, "FF10309", err)
+}
+
+func TestVaultGetSecretBadURL(t *testing.T) {
+	sm := NewHashiCorpVaultSecretManager(":::", "", 0, 0)
+	_, err := sm.GetSecret(context.Background(), "kv/firefly")
+	assert.Regexp(t, "FF10309", err)
+}
+
+func TestSetSecretManager(t *testing.T) {
+	Reset()
+	defer Reset()
+	sm := NewHashiCorpVaultSecretManager("http://localhost:8200", "", 0, 0)
+	SetSecretManager(sm)
+	sm2, err := GetSecretManager(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, sm, sm2)
+}
@@ -0,0 +1,30 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localkeys
+
+import (
+	"github.com/hyperledger/firefly/internal/config"
+)
+
+const (
+	// LocalKeysConfKeyFile the file the node's private key is stored in. When unset it is stored in $HOME/.firefly/localkeys/node.key
+	LocalKeysConfKeyFile = "keyFile"
+)
+
+func (lk *LocalKeys) InitPrefix(prefix config.Prefix) {
+	prefix.AddKnownKey(LocalKeysConfKeyFile)
+}
@@ -0,0 +1,139 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localkeys
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+)
+
+var randReader = rand.Reader
+
+var userHomeDir = os.UserHomeDir
+
+// defaultKeyFile is where the node key is kept when no key file is configured, relative to the home directory
+// of the user (alongside the $HOME/.firefly configuration directory)
+var defaultKeyFile = filepath.Join(".firefly", "localkeys", "node.key")
+
+const (
+	pemTypePublicKey  = "PUBLIC KEY"
+	pemTypePrivateKey = "EC PRIVATE KEY"
+)
+
+// LocalKeys is a crypto plugin that manages an ECDH P-256 key pair in a local PEM file
+type LocalKeys struct {
+	keyFile    string
+	mux        sync.Mutex
+	publicKey  string
+	privateKey string
+}
+
+func (lk *LocalKeys) Name() string {
+	return "localkeys"
+}
+
+func (lk *LocalKeys) Init(ctx context.Context, prefix config.Prefix) (err error) {
+	lk.keyFile = prefix.GetString(LocalKeysConfKeyFile)
+	if lk.keyFile == "" {
+		// The key must be the same across restarts, as it is registered with the network
+		home, err := userHomeDir()
+		if err != nil {
+			return i18n.WrapError(ctx, err, i18n.MsgCryptoKeyFileRequired)
+		}
+		lk.keyFile = filepath.Join(home, defaultKeyFile)
+		log.L(ctx).Infof("No crypto key file configured - using '%s'", lk.keyFile)
+	}
+	return nil
+}
+
+func (lk *LocalKeys) GenerateOrLoadKey(ctx context.Context) (publicKey, privateKey string, err error) {
+	lk.mux.Lock()
+	defer lk.mux.Unlock()
+
+	if lk.privateKey == "" {
+		var key *ecdsa.PrivateKey
+		if key, err = lk.loadKey(ctx); err == nil && key == nil {
+			key, err = lk.generateKey(ctx)
+		}
+		if err != nil {
+			return "", "", err
+		}
+		lk.publicKey, lk.privateKey = encodeKeyPair(key)
+	}
+	return lk.publicKey, lk.privateKey, nil
+}
+
+func (lk *LocalKeys) loadKey(ctx context.Context) (*ecdsa.PrivateKey, error) {
+	b, err := ioutil.ReadFile(lk.keyFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgCryptoKeyLoadFailed, lk.keyFile)
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != pemTypePrivateKey {
+		return nil, i18n.NewError(ctx, i18n.MsgCryptoKeyLoadFailed, lk.keyFile)
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgCryptoKeyLoadFailed, lk.keyFile)
+	}
+	if key.Curve != elliptic.P256() {
+		return nil, i18n.NewError(ctx, i18n.MsgCryptoKeyLoadFailed, lk.keyFile)
+	}
+	log.L(ctx).Infof("Loaded node key from '%s'", lk.keyFile)
+	return key, nil
+}
+
+func (lk *LocalKeys) generateKey(ctx context.Context) (*ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), randReader)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgCryptoKeyGenerateFailed)
+	}
+	b, _ := x509.MarshalECPrivateKey(key)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: b})
+	if err = os.MkdirAll(filepath.Dir(lk.keyFile), 0700); err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgCryptoKeyGenerateFailed)
+	}
+	if err = ioutil.WriteFile(lk.keyFile, keyPEM, 0600); err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgCryptoKeyGenerateFailed)
+	}
+	log.L(ctx).Infof("Generated new node key in '%s'", lk.keyFile)
+	return key, nil
+}
+
+// encodeKeyPair PEM encodes a P-256 key pair - marshalling cannot fail for a known curve
+func encodeKeyPair(key *ecdsa.PrivateKey) (publicKey, privateKey string) {
+	pubBytes, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	privBytes, _ := x509.MarshalECPrivateKey(key)
+	publicKey = string(pem.EncodeToMemory(&pem.Block{Type: pemTypePublicKey, Bytes: pubBytes}))
+	privateKey = string(pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: privBytes}))
+	return publicKey, privateKey
+}
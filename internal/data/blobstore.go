@@ -20,9 +20,12 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"hash"
+	"hash/crc32"
 	"io"
 
 	"github.com/docker/go-units"
+	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/i18n"
 	"github.com/hyperledger/firefly/internal/log"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -38,10 +41,11 @@ type blobStore struct {
 	exchange      dataexchange.Plugin
 }
 
-func (bs *blobStore) uploadVerifyBLOB(ctx context.Context, ns string, id *fftypes.UUID, expectedHash *fftypes.Bytes32, reader io.Reader) (hash *fftypes.Bytes32, written int64, payloadRef string, err error) {
+func (bs *blobStore) uploadVerifyBLOB(ctx context.Context, ns string, id *fftypes.UUID, expectedHash *fftypes.Bytes32, reader io.Reader) (hash *fftypes.Bytes32, checksum uint32, written int64, payloadRef string, err error) {
 	hashCalc := sha256.New()
+	checksumCalc := crc32.NewIEEE()
 	dxReader, dx := io.Pipe()
-	storeAndHash := io.MultiWriter(hashCalc, dx)
+	storeAndHash := io.MultiWriter(hashCalc, checksumCalc, dx)
 
 	copyDone := make(chan error, 1)
 	go func() {
@@ -56,24 +60,25 @@ func (bs *blobStore) uploadVerifyBLOB(ctx context.Context, ns string, id *fftype
 	dxReader.Close()
 	copyErr := <-copyDone
 	if dxErr != nil {
-		return nil, -1, "", dxErr
+		return nil, 0, -1, "", dxErr
 	}
 	if copyErr != nil {
-		return nil, -1, "", i18n.WrapError(ctx, copyErr, i18n.MsgBlobStreamingFailed)
+		return nil, 0, -1, "", i18n.WrapError(ctx, copyErr, i18n.MsgBlobStreamingFailed)
 	}
 
 	hash = fftypes.HashResult(hashCalc)
-	log.L(ctx).Debugf("Upload BLOB size=%d hashes: calculated=%s upload=%s (expected=%v)", written, hash, uploadHash, expectedHash)
+	checksum = checksumCalc.Sum32()
+	log.L(ctx).Debugf("Upload BLOB size=%d checksum=%d hashes: calculated=%s upload=%s (expected=%v)", written, checksum, hash, uploadHash, expectedHash)
 
 	if !uploadHash.Equals(hash) {
-		return nil, -1, "", i18n.NewError(ctx, i18n.MsgDXBadHash, uploadHash, hash)
+		return nil, 0, -1, "", i18n.NewError(ctx, i18n.MsgDXBadHash, uploadHash, hash)
 	}
 
 	if expectedHash != nil && !uploadHash.Equals(expectedHash) {
-		return nil, -1, "", i18n.NewError(ctx, i18n.MsgDXBadHash, uploadHash, expectedHash)
+		return nil, 0, -1, "", i18n.NewError(ctx, i18n.MsgDXBadHash, uploadHash, expectedHash)
 	}
 
-	return hash, written, payloadRef, nil
+	return hash, checksum, written, payloadRef, nil
 
 }
 
@@ -92,12 +97,13 @@ func (bs *blobStore) UploadBLOB(ctx context.Context, ns string, inData *fftypes.
 	data.Namespace = ns
 	data.Created = fftypes.Now()
 
-	hash, written, payloadRef, err := bs.uploadVerifyBLOB(ctx, ns, data.ID, nil /* we don't have an expected hash for a new upload */, blob.Data)
+	hash, checksum, written, payloadRef, err := bs.uploadVerifyBLOB(ctx, ns, data.ID, nil /* we don't have an expected hash for a new upload */, blob.Data)
 	if err != nil {
 		return nil, err
 	}
 	data.Blob = &fftypes.BlobRef{
-		Hash: hash,
+		Hash:       hash,
+		UploadedBy: config.GetString(config.OrgIdentity),
 	}
 
 	// autoMeta will create/update JSON metadata with the upload details
@@ -113,6 +119,9 @@ func (bs *blobStore) UploadBLOB(ctx context.Context, ns string, inData *fftypes.
 	}
 
 	err = bs.dm.checkValidation(ctx, ns, data.Validator, data.Datatype, data.Value)
+	if err == nil {
+		err = bs.dm.resolvePrevious(ctx, ns, data, inData.Previous)
+	}
 	if err == nil {
 		err = data.Seal(ctx)
 	}
@@ -126,8 +135,10 @@ func (bs *blobStore) UploadBLOB(ctx context.Context, ns string, inData *fftypes.
 		if err == nil {
 			err = bs.database.InsertBlob(ctx, &fftypes.Blob{
 				Hash:       hash,
+				Checksum:   checksum,
 				PayloadRef: payloadRef,
 				Created:    fftypes.Now(),
+				UploadedBy: data.Blob.UploadedBy,
 			})
 		}
 		return err
@@ -151,7 +162,7 @@ func (bs *blobStore) CopyBlobPStoDX(ctx context.Context, data *fftypes.Data) (bl
 	}
 	defer reader.Close()
 
-	hash, written, payloadRef, err := bs.uploadVerifyBLOB(ctx, data.Namespace, data.ID, data.Blob.Hash, reader)
+	hash, checksum, written, payloadRef, err := bs.uploadVerifyBLOB(ctx, data.Namespace, data.ID, data.Blob.Hash, reader)
 	if err != nil {
 		return nil, err
 	}
@@ -159,8 +170,10 @@ func (bs *blobStore) CopyBlobPStoDX(ctx context.Context, data *fftypes.Data) (bl
 
 	blob = &fftypes.Blob{
 		Hash:       hash,
+		Checksum:   checksum,
 		PayloadRef: payloadRef,
 		Created:    fftypes.Now(),
+		UploadedBy: data.Blob.UploadedBy, // the originating node, not the node copying from public storage
 	}
 	err = bs.database.InsertBlob(ctx, blob)
 	if err != nil {
@@ -198,5 +211,39 @@ func (bs *blobStore) DownloadBLOB(ctx context.Context, ns, dataID string) (io.Re
 		return nil, i18n.NewError(ctx, i18n.MsgBlobNotFound, data.Blob.Hash)
 	}
 
-	return bs.exchange.DownloadBLOB(ctx, blob.PayloadRef)
+	reader, err := bs.exchange.DownloadBLOB(ctx, blob.PayloadRef)
+	if err != nil || blob.Unverifiable {
+		// Blobs received from peers, or stored before checksums were introduced, cannot be verified
+		return reader, err
+	}
+	return &checksumReader{
+		ctx:          ctx,
+		blob:         blob,
+		reader:       reader,
+		checksumCalc: crc32.NewIEEE(),
+	}, nil
+}
+
+// checksumReader calculates the CRC32 checksum of a blob as it is streamed, without the cost of
+// recalculating the SHA-256 hash. A mismatch is returned as an error in place of io.EOF
+type checksumReader struct {
+	ctx          context.Context
+	blob         *fftypes.Blob
+	reader       io.ReadCloser
+	checksumCalc hash.Hash32
+}
+
+func (cr *checksumReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	cr.checksumCalc.Write(p[:n])
+	if err == io.EOF {
+		if checksum := cr.checksumCalc.Sum32(); checksum != cr.blob.Checksum {
+			return n, i18n.NewError(cr.ctx, i18n.MsgBlobChecksumMismatch, cr.blob.Hash, cr.blob.Checksum, checksum)
+		}
+	}
+	return n, err
+}
+
+func (cr *checksumReader) Close() error {
+	return cr.reader.Close()
 }
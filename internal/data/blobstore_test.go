@@ -21,12 +21,14 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"io/ioutil"
 	"math/rand"
 	"testing"
 	"testing/iotest"
 
+	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/publicstoragemocks"
@@ -37,6 +39,8 @@ import (
 
 func TestUploadBlobOk(t *testing.T) {
 
+	config.Reset()
+	config.Set(config.OrgIdentity, "org1")
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
 
@@ -53,7 +57,9 @@ func TestUploadBlobOk(t *testing.T) {
 		}
 	}
 	mdi.On("UpsertData", mock.Anything, mock.Anything, false, false).Return(nil)
-	mdi.On("InsertBlob", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("InsertBlob", mock.Anything, mock.MatchedBy(func(blob *fftypes.Blob) bool {
+		return blob.Checksum == crc32.ChecksumIEEE(b) && blob.UploadedBy == "org1"
+	})).Return(nil)
 
 	dxID := make(chan fftypes.UUID, 1)
 	mdx := dm.exchange.(*dataexchangemocks.Plugin)
@@ -76,6 +82,7 @@ func TestUploadBlobOk(t *testing.T) {
 	assert.Equal(t, <-dxID, *data.ID)
 	assert.Equal(t, fftypes.ValidatorTypeJSON, data.Validator)
 	assert.Nil(t, data.Datatype)
+	assert.Equal(t, "org1", data.Blob.UploadedBy)
 
 	mdi.AssertExpectations(t)
 	mdx.AssertExpectations(t)
@@ -252,12 +259,14 @@ func TestCopyBlobPStoDXOk(t *testing.T) {
 		Namespace: "ns1",
 		ID:        fftypes.NewUUID(),
 		Blob: &fftypes.BlobRef{
-			Hash:   &hash,
-			Public: "public-ref",
+			Hash:       &hash,
+			Public:     "public-ref",
+			UploadedBy: "org2",
 		},
 	})
 	assert.NoError(t, err)
 	assert.Equal(t, "/private/loc", blob.PayloadRef)
+	assert.Equal(t, "org2", blob.UploadedBy)
 	assert.Equal(t, hash, *blob.Hash)
 
 }
@@ -446,6 +455,41 @@ func TestDownloadBlobOk(t *testing.T) {
 	blobHash := fftypes.NewRandB32()
 	dataID := fftypes.NewUUID()
 
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", ctx, dataID, false).Return(&fftypes.Data{
+		ID:        dataID,
+		Namespace: "ns1",
+		Blob: &fftypes.BlobRef{
+			Hash: blobHash,
+		},
+	}, nil)
+	mdi.On("GetBlobMatchingHash", ctx, blobHash).Return(&fftypes.Blob{
+		Hash:         blobHash,
+		PayloadRef:   "ns1/blob1",
+		Unverifiable: true,
+	}, nil)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBLOB", ctx, "ns1/blob1").Return(
+		ioutil.NopCloser(bytes.NewReader([]byte("some blob"))),
+		nil)
+
+	reader, err := dm.DownloadBLOB(ctx, "ns1", dataID.String())
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(reader)
+	reader.Close()
+	assert.Equal(t, "some blob", string(b))
+
+}
+
+func TestDownloadBlobChecksumOk(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+
 	mdi := dm.database.(*databasemocks.Plugin)
 	mdi.On("GetDataByID", ctx, dataID, false).Return(&fftypes.Data{
 		ID:        dataID,
@@ -456,6 +500,7 @@ func TestDownloadBlobOk(t *testing.T) {
 	}, nil)
 	mdi.On("GetBlobMatchingHash", ctx, blobHash).Return(&fftypes.Blob{
 		Hash:       blobHash,
+		Checksum:   crc32.ChecksumIEEE([]byte("some blob")),
 		PayloadRef: "ns1/blob1",
 	}, nil)
 
@@ -472,6 +517,76 @@ func TestDownloadBlobOk(t *testing.T) {
 
 }
 
+func TestDownloadBlobChecksumMismatch(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", ctx, dataID, false).Return(&fftypes.Data{
+		ID:        dataID,
+		Namespace: "ns1",
+		Blob: &fftypes.BlobRef{
+			Hash: blobHash,
+		},
+	}, nil)
+	mdi.On("GetBlobMatchingHash", ctx, blobHash).Return(&fftypes.Blob{
+		Hash:       blobHash,
+		Checksum:   crc32.ChecksumIEEE([]byte("some blob")),
+		PayloadRef: "ns1/blob1",
+	}, nil)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBLOB", ctx, "ns1/blob1").Return(
+		ioutil.NopCloser(bytes.NewReader([]byte("corrupted"))),
+		nil)
+
+	reader, err := dm.DownloadBLOB(ctx, "ns1", dataID.String())
+	assert.NoError(t, err)
+	_, err = ioutil.ReadAll(reader)
+	reader.Close()
+	assert.Regexp(t, "FF10297", err)
+
+}
+
+func TestDownloadBlobChecksumReadFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", ctx, dataID, false).Return(&fftypes.Data{
+		ID:        dataID,
+		Namespace: "ns1",
+		Blob: &fftypes.BlobRef{
+			Hash: blobHash,
+		},
+	}, nil)
+	mdi.On("GetBlobMatchingHash", ctx, blobHash).Return(&fftypes.Blob{
+		Hash:       blobHash,
+		Checksum:   12345,
+		PayloadRef: "ns1/blob1",
+	}, nil)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBLOB", ctx, "ns1/blob1").Return(
+		ioutil.NopCloser(iotest.ErrReader(fmt.Errorf("pop"))),
+		nil)
+
+	reader, err := dm.DownloadBLOB(ctx, "ns1", dataID.String())
+	assert.NoError(t, err)
+	_, err = ioutil.ReadAll(reader)
+	reader.Close()
+	assert.EqualError(t, err, "pop")
+
+}
+
 func TestDownloadBlobNotFound(t *testing.T) {
 
 	dm, ctx, cancel := newTestDataManager(t)
@@ -592,3 +707,38 @@ func TestDownloadBlobBadID(t *testing.T) {
 	assert.Regexp(t, "FF10142", err)
 
 }
+
+func TestDownloadBlobChecksumZeroVerified(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", ctx, dataID, false).Return(&fftypes.Data{
+		ID:        dataID,
+		Namespace: "ns1",
+		Blob: &fftypes.BlobRef{
+			Hash: blobHash,
+		},
+	}, nil)
+	mdi.On("GetBlobMatchingHash", ctx, blobHash).Return(&fftypes.Blob{
+		Hash:       blobHash,
+		Checksum:   0,
+		PayloadRef: "ns1/blob1",
+	}, nil)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBLOB", ctx, "ns1/blob1").Return(
+		ioutil.NopCloser(bytes.NewReader([]byte("some blob"))),
+		nil)
+
+	reader, err := dm.DownloadBLOB(ctx, "ns1", dataID.String())
+	assert.NoError(t, err)
+	_, err = ioutil.ReadAll(reader)
+	reader.Close()
+	assert.Regexp(t, "FF10297", err)
+
+}
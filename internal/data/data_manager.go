@@ -35,9 +35,10 @@ import (
 type Manager interface {
 	CheckDatatype(ctx context.Context, ns string, datatype *fftypes.Datatype) error
 	ValidateAll(ctx context.Context, data []*fftypes.Data) (valid bool, err error)
+	ValidateAgainstDatatype(ctx context.Context, ns string, datatypeRef *fftypes.DatatypeRef, data []*fftypes.Data) error
 	GetMessageData(ctx context.Context, msg *fftypes.Message, withValue bool) (data []*fftypes.Data, foundAll bool, err error)
 	ResolveInlineDataPrivate(ctx context.Context, ns string, inData fftypes.InlineData) (fftypes.DataRefs, error)
-	ResolveInlineDataBroadcast(ctx context.Context, ns string, inData fftypes.InlineData) (fftypes.DataRefs, []*fftypes.DataAndBlob, error)
+	ResolveInlineDataBroadcast(ctx context.Context, ns string, msgID *fftypes.UUID, inData fftypes.InlineData) (fftypes.DataRefs, []*fftypes.DataAndBlob, error)
 	VerifyNamespaceExists(ctx context.Context, ns string) error
 
 	UploadJSON(ctx context.Context, ns string, inData *fftypes.DataRefOrValue) (*fftypes.Data, error)
@@ -147,6 +148,10 @@ func (dm *dataManager) GetMessageData(ctx context.Context, msg *fftypes.Message,
 			return nil, false, err
 		}
 		if d == nil {
+			if dataRef != nil && dataRef.Optional {
+				log.L(ctx).Debugf("Message %v optional data %d missing", msg.Header.ID, i)
+				continue
+			}
 			log.L(ctx).Warnf("Message %v data %d mising", msg.Header.ID, i)
 			foundAll = false
 			continue
@@ -176,6 +181,24 @@ func (dm *dataManager) ValidateAll(ctx context.Context, data []*fftypes.Data) (v
 	return true, nil
 }
 
+// ValidateAgainstDatatype checks the value of every data item conforms to the specified datatype,
+// regardless of the datatype the data itself references (if any)
+func (dm *dataManager) ValidateAgainstDatatype(ctx context.Context, ns string, datatypeRef *fftypes.DatatypeRef, data []*fftypes.Data) error {
+	v, err := dm.getValidatorForDatatype(ctx, ns, fftypes.ValidatorTypeJSON, datatypeRef)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return i18n.NewError(ctx, i18n.MsgDatatypeNotFound, datatypeRef)
+	}
+	for _, d := range data {
+		if err = v.ValidateValue(ctx, d.Value, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (dm *dataManager) resolveRef(ctx context.Context, ns string, dataRef *fftypes.DataRef, withValue bool) (*fftypes.Data, error) {
 	if dataRef == nil || dataRef.ID == nil {
 		log.L(ctx).Warnf("data is nil")
@@ -240,25 +263,47 @@ func (dm *dataManager) checkValidation(ctx context.Context, ns string, validator
 	return nil
 }
 
-func (dm *dataManager) validateAndStore(ctx context.Context, ns string, validator fftypes.ValidatorType, datatype *fftypes.DatatypeRef, value fftypes.Byteable, blobRef *fftypes.BlobRef) (data *fftypes.Data, blob *fftypes.Blob, err error) {
+// resolvePrevious links new data to the previous version it updates, if one is specified
+func (dm *dataManager) resolvePrevious(ctx context.Context, ns string, data *fftypes.Data, previous *fftypes.UUID) error {
+	if previous == nil {
+		return nil
+	}
+	prev, err := dm.database.GetDataByID(ctx, previous, false)
+	if err != nil {
+		return err
+	}
+	if prev == nil || prev.Namespace != ns {
+		return i18n.NewError(ctx, i18n.MsgPreviousDataNotFound, previous)
+	}
+	data.Previous = prev.ID
+	data.Version = prev.Version + 1
+	return nil
+}
+
+func (dm *dataManager) validateAndStore(ctx context.Context, ns string, inData *fftypes.DataRefOrValue, createdFrom *fftypes.UUID) (data *fftypes.Data, blob *fftypes.Blob, err error) {
 
-	if err := dm.checkValidation(ctx, ns, validator, datatype, value); err != nil {
+	if err := dm.checkValidation(ctx, ns, inData.Validator, inData.Datatype, inData.Value); err != nil {
 		return nil, nil, err
 	}
 
-	if blob, err = dm.resolveBlob(ctx, blobRef); err != nil {
+	if blob, err = dm.resolveBlob(ctx, inData.Blob); err != nil {
 		return nil, nil, err
 	}
 
 	// Ok, we're good to generate the full data payload and save it
 	data = &fftypes.Data{
-		Validator: validator,
-		Datatype:  datatype,
-		Namespace: ns,
-		Value:     value,
-		Blob:      blobRef,
+		Validator:   inData.Validator,
+		Datatype:    inData.Datatype,
+		Namespace:   ns,
+		Value:       inData.Value,
+		Blob:        inData.Blob,
+		CreatedFrom: createdFrom,
+		ExpiresAt:   inData.ExpiresAt,
+	}
+	err = dm.resolvePrevious(ctx, ns, data, inData.Previous)
+	if err == nil {
+		err = data.Seal(ctx)
 	}
-	err = data.Seal(ctx)
 	if err == nil {
 		err = dm.database.UpsertData(ctx, data, false, false)
 	}
@@ -268,8 +313,8 @@ func (dm *dataManager) validateAndStore(ctx context.Context, ns string, validato
 	return data, blob, nil
 }
 
-func (dm *dataManager) validateAndStoreInlined(ctx context.Context, ns string, value *fftypes.DataRefOrValue) (*fftypes.Data, *fftypes.Blob, *fftypes.DataRef, error) {
-	data, blob, err := dm.validateAndStore(ctx, ns, value.Validator, value.Datatype, value.Value, value.Blob)
+func (dm *dataManager) validateAndStoreInlined(ctx context.Context, ns string, value *fftypes.DataRefOrValue, createdFrom *fftypes.UUID) (*fftypes.Data, *fftypes.Blob, *fftypes.DataRef, error) {
+	data, blob, err := dm.validateAndStore(ctx, ns, value, createdFrom)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -282,12 +327,12 @@ func (dm *dataManager) validateAndStoreInlined(ctx context.Context, ns string, v
 }
 
 func (dm *dataManager) UploadJSON(ctx context.Context, ns string, inData *fftypes.DataRefOrValue) (*fftypes.Data, error) {
-	data, _, err := dm.validateAndStore(ctx, ns, inData.Validator, inData.Datatype, inData.Value, inData.Blob)
+	data, _, err := dm.validateAndStore(ctx, ns, inData, nil)
 	return data, err
 }
 
 func (dm *dataManager) ResolveInlineDataPrivate(ctx context.Context, ns string, inData fftypes.InlineData) (refs fftypes.DataRefs, err error) {
-	refs, _, err = dm.resolveInlineData(ctx, ns, inData, false)
+	refs, _, err = dm.resolveInlineData(ctx, ns, nil, inData, false)
 	return refs, err
 }
 
@@ -295,11 +340,12 @@ func (dm *dataManager) ResolveInlineDataPrivate(ctx context.Context, ns string,
 // have a public storage reference, and hence must be published to publicstorage before a broadcast message can be sent.
 // We deliberately do NOT perform those publishes inside of this action, as we expect to be in a RunAsGroup (trnasaction)
 // at this point, and hence expensive things like a multi-megabyte upload should be decoupled by our caller.
-func (dm *dataManager) ResolveInlineDataBroadcast(ctx context.Context, ns string, inData fftypes.InlineData) (refs fftypes.DataRefs, dataToPublish []*fftypes.DataAndBlob, err error) {
-	return dm.resolveInlineData(ctx, ns, inData, true)
+// Any data created from inline values is recorded as having been created from the message with msgID.
+func (dm *dataManager) ResolveInlineDataBroadcast(ctx context.Context, ns string, msgID *fftypes.UUID, inData fftypes.InlineData) (refs fftypes.DataRefs, dataToPublish []*fftypes.DataAndBlob, err error) {
+	return dm.resolveInlineData(ctx, ns, msgID, inData, true)
 }
 
-func (dm *dataManager) resolveInlineData(ctx context.Context, ns string, inData fftypes.InlineData, broadcast bool) (refs fftypes.DataRefs, dataToPublish []*fftypes.DataAndBlob, err error) {
+func (dm *dataManager) resolveInlineData(ctx context.Context, ns string, msgID *fftypes.UUID, inData fftypes.InlineData, broadcast bool) (refs fftypes.DataRefs, dataToPublish []*fftypes.DataAndBlob, err error) {
 
 	refs = make(fftypes.DataRefs, len(inData))
 	if broadcast {
@@ -316,18 +362,28 @@ func (dm *dataManager) resolveInlineData(ctx context.Context, ns string, inData
 				return nil, nil, err
 			}
 			if data == nil {
+				if dataOrValue.Optional && dataOrValue.Hash != nil {
+					// Optional data does not need to be available now, but the hash must be known
+					refs[i] = &fftypes.DataRef{
+						ID:       dataOrValue.ID,
+						Hash:     dataOrValue.Hash,
+						Optional: true,
+					}
+					continue
+				}
 				return nil, nil, i18n.NewError(ctx, i18n.MsgDataReferenceUnresolvable, i)
 			}
 			refs[i] = &fftypes.DataRef{
-				ID:   data.ID,
-				Hash: data.Hash,
+				ID:       data.ID,
+				Hash:     data.Hash,
+				Optional: dataOrValue.Optional,
 			}
 			if blob, err = dm.resolveBlob(ctx, data.Blob); err != nil {
 				return nil, nil, err
 			}
 		case dataOrValue.Value != nil || dataOrValue.Blob != nil:
 			// We've got a Value, so we can validate + store it
-			if data, blob, refs[i], err = dm.validateAndStoreInlined(ctx, ns, dataOrValue); err != nil {
+			if data, blob, refs[i], err = dm.validateAndStoreInlined(ctx, ns, dataOrValue, msgID); err != nil {
 				return nil, nil, err
 			}
 		default:
@@ -201,6 +201,32 @@ func TestGetMessageDataNotFound(t *testing.T) {
 
 }
 
+func TestGetMessageDataOptionalNotFound(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	dataID := fftypes.NewUUID()
+	hash := fftypes.NewRandB32()
+	mdi.On("GetDataByID", mock.Anything, dataID, true).Return(&fftypes.Data{
+		ID:   dataID,
+		Hash: hash,
+	}, nil)
+	mdi.On("GetDataByID", mock.Anything, mock.Anything, true).Return(nil, nil)
+	data, foundAll, err := dm.GetMessageData(ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{ID: fftypes.NewUUID()},
+		Data: fftypes.DataRefs{
+			{ID: dataID, Hash: hash},
+			{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32(), Optional: true},
+		},
+	}, true)
+	assert.Len(t, data, 1)
+	assert.Equal(t, *dataID, *data[0].ID)
+	assert.True(t, foundAll)
+	assert.NoError(t, err)
+
+}
+
 func TestGetMessageDataHashMismatch(t *testing.T) {
 
 	dm, ctx, cancel := newTestDataManager(t)
@@ -306,7 +332,7 @@ func TestResolveInlineDataBroadcastDataToPublish(t *testing.T) {
 		PayloadRef: "blob/1",
 	}, nil)
 
-	refs, dtp, err := dm.ResolveInlineDataBroadcast(ctx, "ns1", fftypes.InlineData{
+	refs, dtp, err := dm.ResolveInlineDataBroadcast(ctx, "ns1", nil, fftypes.InlineData{
 		{DataRef: fftypes.DataRef{ID: dataID}},
 	})
 	assert.NoError(t, err)
@@ -337,7 +363,7 @@ func TestResolveInlineDataBroadcastResolveBlobFail(t *testing.T) {
 	}, nil)
 	mdi.On("GetBlobMatchingHash", ctx, blobHash).Return(nil, fmt.Errorf("pop"))
 
-	_, _, err := dm.ResolveInlineDataBroadcast(ctx, "ns1", fftypes.InlineData{
+	_, _, err := dm.ResolveInlineDataBroadcast(ctx, "ns1", nil, fftypes.InlineData{
 		{DataRef: fftypes.DataRef{ID: dataID}},
 	})
 	assert.EqualError(t, err, "pop")
@@ -385,6 +411,41 @@ func TestResolveInlineDataRefBadHash(t *testing.T) {
 	assert.Empty(t, refs)
 }
 
+func TestResolveInlineDataRefOptionalNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	dataID := fftypes.NewUUID()
+	dataHash := fftypes.NewRandB32()
+
+	mdi.On("GetDataByID", ctx, dataID, false).Return(nil, nil)
+
+	refs, err := dm.ResolveInlineDataPrivate(ctx, "ns1", fftypes.InlineData{
+		{DataRef: fftypes.DataRef{ID: dataID, Hash: dataHash, Optional: true}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, refs, 1)
+	assert.Equal(t, dataID, refs[0].ID)
+	assert.Equal(t, dataHash, refs[0].Hash)
+	assert.True(t, refs[0].Optional)
+}
+
+func TestResolveInlineDataRefOptionalNotFoundNoHash(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	dataID := fftypes.NewUUID()
+
+	mdi.On("GetDataByID", ctx, dataID, false).Return(nil, nil)
+
+	_, err := dm.ResolveInlineDataPrivate(ctx, "ns1", fftypes.InlineData{
+		{DataRef: fftypes.DataRef{ID: dataID, Optional: true}},
+	})
+	assert.Regexp(t, "FF10204", err)
+}
+
 func TestResolveInlineDataRefLookkupFail(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
@@ -416,6 +477,33 @@ func TestResolveInlineDataValueNoValidatorOK(t *testing.T) {
 	assert.NotNil(t, refs[0].Hash)
 }
 
+func TestResolveInlineDataBroadcastCreatedFrom(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	msgID := fftypes.NewUUID()
+	existingID := fftypes.NewUUID()
+	mdi.On("GetDataByID", ctx, existingID, false).Return(&fftypes.Data{
+		ID:        existingID,
+		Namespace: "ns1",
+		Hash:      fftypes.NewRandB32(),
+	}, nil)
+	mdi.On("UpsertData", ctx, mock.MatchedBy(func(data *fftypes.Data) bool {
+		return *data.CreatedFrom == *msgID
+	}), false, false).Return(nil)
+
+	refs, _, err := dm.ResolveInlineDataBroadcast(ctx, "ns1", msgID, fftypes.InlineData{
+		{Value: fftypes.Byteable(`{"some":"json"}`)},
+		{DataRef: fftypes.DataRef{ID: existingID}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, refs, 2)
+
+	// Only the newly created data is linked to the message
+	mdi.AssertNumberOfCalls(t, "UpsertData", 1)
+}
+
 func TestResolveInlineDataValueNoValidatorStoreFail(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
@@ -502,6 +590,90 @@ func TestUploadJSONLoadDatatypeFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestUploadJSONNewVersion(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	prevID := fftypes.NewUUID()
+	mdi.On("GetDataByID", ctx, prevID, false).Return(&fftypes.Data{
+		ID:        prevID,
+		Namespace: "ns1",
+		Version:   2,
+	}, nil)
+	mdi.On("UpsertData", ctx, mock.Anything, false, false).Return(nil)
+	data, err := dm.UploadJSON(ctx, "ns1", &fftypes.DataRefOrValue{
+		Value:    fftypes.Byteable(`{"some":"update"}`),
+		Previous: prevID,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, data.Version)
+	assert.Equal(t, *prevID, *data.Previous)
+}
+
+func TestUploadJSONFirstVersion(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	mdi.On("UpsertData", ctx, mock.Anything, false, false).Return(nil)
+	data, err := dm.UploadJSON(ctx, "ns1", &fftypes.DataRefOrValue{
+		Value: fftypes.Byteable(`{"some":"data"}`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, data.Version)
+	assert.Nil(t, data.Previous)
+}
+
+func TestUploadJSONExpiresAt(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	expiresAt := fftypes.Now()
+	mdi.On("UpsertData", ctx, mock.MatchedBy(func(data *fftypes.Data) bool {
+		return data.ExpiresAt == expiresAt
+	}), false, false).Return(nil)
+	data, err := dm.UploadJSON(ctx, "ns1", &fftypes.DataRefOrValue{
+		Value:     fftypes.Byteable(`{"some":"data"}`),
+		ExpiresAt: expiresAt,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, expiresAt, data.ExpiresAt)
+	mdi.AssertExpectations(t)
+}
+
+func TestUploadJSONPreviousNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	prevID := fftypes.NewUUID()
+	mdi.On("GetDataByID", ctx, prevID, false).Return(&fftypes.Data{
+		ID:        prevID,
+		Namespace: "ns2",
+	}, nil)
+	_, err := dm.UploadJSON(ctx, "ns1", &fftypes.DataRefOrValue{
+		Value:    fftypes.Byteable(`{"some":"update"}`),
+		Previous: prevID,
+	})
+	assert.Regexp(t, "FF10302", err)
+}
+
+func TestUploadJSONPreviousLookupFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	prevID := fftypes.NewUUID()
+	mdi.On("GetDataByID", ctx, prevID, false).Return(nil, fmt.Errorf("pop"))
+	_, err := dm.UploadJSON(ctx, "ns1", &fftypes.DataRefOrValue{
+		Value:    fftypes.Byteable(`{"some":"update"}`),
+		Previous: prevID,
+	})
+	assert.EqualError(t, err, "pop")
+}
+
 func TestValidateAndStoreLoadNilRef(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
@@ -509,7 +681,7 @@ func TestValidateAndStoreLoadNilRef(t *testing.T) {
 	_, _, _, err := dm.validateAndStoreInlined(ctx, "ns1", &fftypes.DataRefOrValue{
 		Validator: fftypes.ValidatorTypeJSON,
 		Datatype:  nil,
-	})
+	}, nil)
 	assert.Regexp(t, "FF10199", err)
 }
 
@@ -525,7 +697,7 @@ func TestValidateAndStoreLoadValidatorUnknown(t *testing.T) {
 			Name:    "customer",
 			Version: "0.0.1",
 		},
-	})
+	}, nil)
 	assert.Regexp(t, "FF10200.*wrong", err)
 
 }
@@ -540,7 +712,7 @@ func TestValidateAndStoreLoadBadRef(t *testing.T) {
 		Datatype: &fftypes.DatatypeRef{
 			// Missing name
 		},
-	})
+	}, nil)
 	assert.Regexp(t, "FF10195", err)
 }
 
@@ -555,7 +727,7 @@ func TestValidateAndStoreNotFound(t *testing.T) {
 			Name:    "customer",
 			Version: "0.0.1",
 		},
-	})
+	}, nil)
 	assert.Regexp(t, "FF10195", err)
 }
 
@@ -570,7 +742,7 @@ func TestValidateAndStoreBlobError(t *testing.T) {
 		Blob: &fftypes.BlobRef{
 			Hash: blobHash,
 		},
-	})
+	}, nil)
 	assert.Regexp(t, "pop", err)
 }
 
@@ -585,7 +757,7 @@ func TestValidateAndStoreBlobNotFound(t *testing.T) {
 		Blob: &fftypes.BlobRef{
 			Hash: blobHash,
 		},
-	})
+	}, nil)
 	assert.Regexp(t, "FF10239", err)
 }
 
@@ -674,3 +846,66 @@ func TestVerifyNamespaceExistsOk(t *testing.T) {
 	err := dm.VerifyNamespaceExists(ctx, "ns1")
 	assert.NoError(t, err)
 }
+
+func TestValidateAgainstDatatype(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	dtRef := &fftypes.DatatypeRef{
+		Name:    "customer",
+		Version: "0.0.1",
+	}
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(&fftypes.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: fftypes.ValidatorTypeJSON,
+		Value: fftypes.Byteable(`{
+			"properties": {
+				"field1": {
+					"type": "string"
+				}
+			},
+			"additionalProperties": false
+		}`),
+		Namespace: "ns1",
+		Name:      "customer",
+		Version:   "0.0.1",
+	}, nil)
+
+	// Conformant
+	err := dm.ValidateAgainstDatatype(ctx, "ns1", dtRef, []*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`{"field1":"value1"}`)},
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`{}`)},
+	})
+	assert.NoError(t, err)
+
+	// Non-conformant
+	err = dm.ValidateAgainstDatatype(ctx, "ns1", dtRef, []*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`{"field1":"value1"}`)},
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`{"field2":"value2"}`)},
+	})
+	assert.Regexp(t, "FF10198", err)
+
+}
+
+func TestValidateAgainstDatatypeNotFound(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(nil, nil)
+	err := dm.ValidateAgainstDatatype(ctx, "ns1", &fftypes.DatatypeRef{Name: "customer", Version: "0.0.1"}, []*fftypes.Data{})
+	assert.Regexp(t, "FF10195", err)
+
+}
+
+func TestValidateAgainstDatatypeLookupError(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(nil, fmt.Errorf("pop"))
+	err := dm.ValidateAgainstDatatype(ctx, "ns1", &fftypes.DatatypeRef{Name: "customer", Version: "0.0.1"}, []*fftypes.Data{})
+	assert.Regexp(t, "pop", err)
+
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 
 	"database/sql"
+	"fmt"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	migratedb "github.com/golang-migrate/migrate/v4/database"
@@ -27,6 +29,7 @@ import (
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/database/sqlcommon"
 	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
 
 	// Import pq driver
 	_ "github.com/lib/pq"
@@ -64,3 +67,32 @@ func (psql *Postgres) Open(url string) (*sql.DB, error) {
 func (psql *Postgres) GetMigrationDriver(db *sql.DB) (migratedb.Driver, error) {
 	return postgres.WithInstance(db, &postgres.Config{})
 }
+
+func (psql *Postgres) JSONExtract(column string, path []string, numeric bool) (string, []interface{}) {
+	// The JSON is stored as BYTEA, so is converted before applying the path operators
+	jsonb := fmt.Sprintf("convert_from(%s, 'UTF8')::jsonb", column)
+	pathArg := postgresTextArray(path)
+	if numeric {
+		return fmt.Sprintf("(CASE WHEN jsonb_typeof(%[1]s #> ?::text[]) = 'number' THEN (%[1]s #>> ?::text[])::numeric END)", jsonb),
+			[]interface{}{pathArg, pathArg}
+	}
+	return fmt.Sprintf("(%s #>> ?::text[])", jsonb), []interface{}{pathArg}
+}
+
+func (psql *Postgres) JSONContains(column string, obj fftypes.JSONObject) sq.Sqlizer {
+	return sq.Expr(fmt.Sprintf("%s @> ?::jsonb", column), obj.String())
+}
+
+func (psql *Postgres) JSONHasKey(column, key string) sq.Sqlizer {
+	// The ? operator cannot be used, as it clashes with the placeholders
+	return sq.Expr(fmt.Sprintf("(%s -> ?::text) IS NOT NULL", column), key)
+}
+
+func postgresTextArray(elems []string) string {
+	quoted := make([]string, len(elems))
+	for i, e := range elems {
+		e = strings.ReplaceAll(e, `\`, `\\`)
+		quoted[i] = `"` + strings.ReplaceAll(e, `"`, `\"`) + `"`
+	}
+	return "{" + strings.Join(quoted, ",") + "}"
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/database/sqlcommon"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -48,3 +49,27 @@ func TestPostgresProvider(t *testing.T) {
 	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)  RETURNING seq", sql)
 	assert.True(t, query)
 }
+
+func TestPostgresJSONExtract(t *testing.T) {
+	psql := &Postgres{}
+
+	expr, args := psql.JSONExtract("value", []string{"customer", "id"}, false)
+	assert.Equal(t, "(convert_from(value, 'UTF8')::jsonb #>> ?::text[])", expr)
+	assert.Equal(t, []interface{}{`{"customer","id"}`}, args)
+
+	expr, args = psql.JSONExtract("data.value", []string{`a"b`, `c\d`}, true)
+	assert.Equal(t, "(CASE WHEN jsonb_typeof(convert_from(data.value, 'UTF8')::jsonb #> ?::text[]) = 'number' THEN (convert_from(data.value, 'UTF8')::jsonb #>> ?::text[])::numeric END)", expr)
+	assert.Equal(t, []interface{}{`{"a\"b","c\\d"}`, `{"a\"b","c\\d"}`}, args)
+}
+
+func TestPostgresJSONContains(t *testing.T) {
+	psql := &Postgres{}
+
+	sel := sq.Select("*").From("messages").PlaceholderFormat(psql.PlaceholderFormat()).
+		Where(psql.JSONContains("messages.labels", fftypes.JSONObject{"department": "finance"})).
+		Where(psql.JSONHasKey("messages.labels", "region"))
+	sql, args, err := sel.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM messages WHERE messages.labels @> $1::jsonb AND (messages.labels -> $2::text) IS NOT NULL", sql)
+	assert.Equal(t, []interface{}{`{"department":"finance"}`, "region"}, args)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/hyperledger/firefly/internal/log"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/klauspost/compress/zstd"
 )
 
 var (
@@ -41,13 +42,24 @@ var (
 		"confirmed",
 		"tx_type",
 		"tx_id",
+		"creator_node",
+		"schema_version",
+		"payload_compressed",
+		"confirmed_at",
+		"pin_count",
+		"published_at",
 	}
 	batchFilterFieldMap = map[string]string{
+		"creatornode":      "creator_node",
 		"type":             "btype",
 		"payloadref":       "payload_ref",
 		"transaction.type": "tx_type",
 		"transaction.id":   "tx_id",
 		"group":            "group_hash",
+		"confirmedat":      "confirmed_at",
+		"pincount":         "pin_count",
+		"publishedat":      "published_at",
+		"tags":             "batch_tags.tag",
 	}
 )
 
@@ -80,6 +92,11 @@ func (s *SQLCommon) UpsertBatch(ctx context.Context, batch *fftypes.Batch, allow
 	}
 	batchRows.Close()
 
+	payload, compressed, err := s.batchPayloadValue(batch)
+	if err != nil {
+		return err
+	}
+
 	if existing {
 
 		// Update the batch
@@ -91,11 +108,17 @@ func (s *SQLCommon) UpsertBatch(ctx context.Context, batch *fftypes.Batch, allow
 				Set("group_hash", batch.Group).
 				Set("created", batch.Created).
 				Set("hash", batch.Hash).
-				Set("payload", batch.Payload).
+				Set("payload", payload).
 				Set("payload_ref", batch.PayloadRef).
 				Set("confirmed", batch.Confirmed).
 				Set("tx_type", batch.Payload.TX.Type).
 				Set("tx_id", batch.Payload.TX.ID).
+				Set("creator_node", batch.CreatorNodeID).
+				Set("schema_version", batch.SchemaVersion).
+				Set("payload_compressed", compressed).
+				Set("confirmed_at", batch.ConfirmedAt).
+				Set("pin_count", batch.PinCount).
+				// published_at is not updated, as batches retrieved from the public storage do not carry it
 				Where(sq.Eq{"id": batch.ID}),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionBatches, fftypes.ChangeEventTypeUpdated, batch.Namespace, batch.ID)
@@ -116,11 +139,17 @@ func (s *SQLCommon) UpsertBatch(ctx context.Context, batch *fftypes.Batch, allow
 					batch.Group,
 					batch.Created,
 					batch.Hash,
-					batch.Payload,
+					payload,
 					batch.PayloadRef,
 					batch.Confirmed,
 					batch.Payload.TX.Type,
 					batch.Payload.TX.ID,
+					batch.CreatorNodeID,
+					batch.SchemaVersion,
+					compressed,
+					batch.ConfirmedAt,
+					batch.PinCount,
+					batch.PublishedAt,
 				),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionBatches, fftypes.ChangeEventTypeCreated, batch.Namespace, batch.ID)
@@ -130,11 +159,101 @@ func (s *SQLCommon) UpsertBatch(ctx context.Context, batch *fftypes.Batch, allow
 		}
 	}
 
+	if err = s.updateBatchTags(ctx, tx, batch, existing); err != nil {
+		return err
+	}
+
 	return s.commitTx(ctx, tx, autoCommit)
 }
 
+func (s *SQLCommon) updateBatchTags(ctx context.Context, tx *txWrapper, batch *fftypes.Batch, existing bool) error {
+
+	if existing {
+		if err := s.deleteTx(ctx, tx,
+			sq.Delete("batch_tags").
+				Where(sq.Eq{"batch_id": batch.ID}),
+			nil, // no change event
+		); err != nil && err != database.DeleteRecordNotFound {
+			return err
+		}
+	}
+
+	for tagIdx, tag := range batch.Tags {
+		if _, err := s.insertTx(ctx, tx,
+			sq.Insert("batch_tags").
+				Columns(
+					"batch_id",
+					"tag",
+					"tag_idx",
+				).
+				Values(
+					batch.ID,
+					tag,
+					tagIdx,
+				),
+			nil, // no change event
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLCommon) loadBatchTags(ctx context.Context, batches []*fftypes.Batch) error {
+
+	batchIDs := make([]string, len(batches))
+	for i, b := range batches {
+		batchIDs[i] = b.ID.String()
+	}
+
+	tagRows, _, err := s.query(ctx,
+		sq.Select(
+			"batch_id",
+			"tag",
+		).
+			From("batch_tags").
+			Where(sq.Eq{"batch_id": batchIDs}).
+			OrderBy("tag_idx"),
+	)
+	if err != nil {
+		return err
+	}
+	defer tagRows.Close()
+
+	for tagRows.Next() {
+		var batchID fftypes.UUID
+		var tag string
+		if err = tagRows.Scan(&batchID, &tag); err != nil {
+			return i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "batch_tags")
+		}
+		for _, b := range batches {
+			if *b.ID == batchID {
+				b.Tags = append(b.Tags, tag)
+			}
+		}
+	}
+
+	return nil
+}
+
+// batchPayloadValue returns the value to store in the payload column, which is the
+// compressed batch when compression is enabled
+func (s *SQLCommon) batchPayloadValue(batch *fftypes.Batch) (interface{}, bool, error) {
+	if !s.compress {
+		return batch.Payload, false, nil
+	}
+	b, err := batch.Compress(zstd.SpeedDefault)
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
 func (s *SQLCommon) batchResult(ctx context.Context, row *sql.Rows) (*fftypes.Batch, error) {
 	var batch fftypes.Batch
+	var payload []byte
+	var compressed bool
 	err := row.Scan(
 		&batch.ID,
 		&batch.Type,
@@ -143,12 +262,30 @@ func (s *SQLCommon) batchResult(ctx context.Context, row *sql.Rows) (*fftypes.Ba
 		&batch.Group,
 		&batch.Created,
 		&batch.Hash,
-		&batch.Payload,
+		&payload,
 		&batch.PayloadRef,
 		&batch.Confirmed,
 		&batch.Payload.TX.Type,
 		&batch.Payload.TX.ID,
+		&batch.CreatorNodeID,
+		&batch.SchemaVersion,
+		&compressed,
+		&batch.ConfirmedAt,
+		&batch.PinCount,
+		&batch.PublishedAt,
 	)
+	if err == nil {
+		txRef := batch.Payload.TX
+		if compressed {
+			var stored *fftypes.Batch
+			if stored, err = fftypes.DecompressBatch(payload); err == nil {
+				batch.Payload = stored.Payload
+			}
+		} else {
+			err = batch.Payload.Scan(payload)
+		}
+		batch.Payload.TX = txRef
+	}
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "batches")
 	}
@@ -176,6 +313,11 @@ func (s *SQLCommon) GetBatchByID(ctx context.Context, id *fftypes.UUID) (message
 	if err != nil {
 		return nil, err
 	}
+	rows.Close()
+
+	if err = s.loadBatchTags(ctx, []*fftypes.Batch{batch}); err != nil {
+		return nil, err
+	}
 
 	return batch, nil
 }
@@ -202,6 +344,13 @@ func (s *SQLCommon) GetBatches(ctx context.Context, filter database.Filter) (mes
 		batches = append(batches, batch)
 	}
 
+	rows.Close()
+	if len(batches) > 0 {
+		if err = s.loadBatchTags(ctx, batches); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	return batches, s.queryRes(ctx, tx, "batches", fop, fi), err
 
 }
@@ -227,3 +376,28 @@ func (s *SQLCommon) UpdateBatch(ctx context.Context, id *fftypes.UUID, update da
 
 	return s.commitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) DeleteBatch(ctx context.Context, id *fftypes.UUID) (err error) {
+
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	err = s.deleteTx(ctx, tx, sq.Delete("batch_tags").Where(sq.Eq{
+		"batch_id": id,
+	}), nil /* no change events for purges */)
+	if err != nil && err != database.DeleteRecordNotFound {
+		return err
+	}
+
+	err = s.deleteTx(ctx, tx, sq.Delete("batches").Where(sq.Eq{
+		"id": id,
+	}), nil /* no change events for purges */)
+	if err != nil {
+		return err
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
@@ -18,6 +18,7 @@ package sqlcommon
 
 import (
 	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -39,12 +40,13 @@ func TestBatch2EWithDB(t *testing.T) {
 	batchID := fftypes.NewUUID()
 	msgID1 := fftypes.NewUUID()
 	batch := &fftypes.Batch{
-		ID:        batchID,
-		Type:      fftypes.MessageTypeBroadcast,
-		Author:    "0x12345",
-		Namespace: "ns1",
-		Hash:      fftypes.NewRandB32(),
-		Created:   fftypes.Now(),
+		ID:          batchID,
+		Type:        fftypes.MessageTypeBroadcast,
+		Author:      "0x12345",
+		Namespace:   "ns1",
+		Hash:        fftypes.NewRandB32(),
+		Created:     fftypes.Now(),
+		PublishedAt: fftypes.Now(),
 		Payload: fftypes.BatchPayload{
 			Messages: []*fftypes.Message{
 				{Header: fftypes.MessageHeader{ID: msgID1}},
@@ -75,24 +77,29 @@ func TestBatch2EWithDB(t *testing.T) {
 	msgID2 := fftypes.NewUUID()
 	payloadRef := ""
 	batchUpdated := &fftypes.Batch{
-		ID:        batchID,
-		Type:      fftypes.MessageTypeBroadcast,
-		Author:    "0x12345",
-		Namespace: "ns1",
-		Hash:      fftypes.NewRandB32(),
-		Created:   fftypes.Now(),
+		ID:            batchID,
+		Type:          fftypes.MessageTypeBroadcast,
+		Author:        "0x12345",
+		CreatorNodeID: fftypes.NewUUID(),
+		SchemaVersion: fftypes.BatchSchemaVersion,
+		Namespace:     "ns1",
+		Hash:          fftypes.NewRandB32(),
+		Created:       fftypes.Now(),
 		Payload: fftypes.BatchPayload{
 			TX: fftypes.TransactionRef{
 				ID:   txid,
 				Type: fftypes.TransactionTypeBatchPin,
 			},
 			Messages: []*fftypes.Message{
-				{Header: fftypes.MessageHeader{ID: msgID1}},
-				{Header: fftypes.MessageHeader{ID: msgID2}},
+				{Header: fftypes.MessageHeader{ID: msgID1, Tag: "tag1"}},
+				{Header: fftypes.MessageHeader{ID: msgID2, Tag: "tag2"}},
 			},
 		},
-		PayloadRef: payloadRef,
-		Confirmed:  fftypes.Now(),
+		PayloadRef:  payloadRef,
+		Confirmed:   fftypes.Now(),
+		ConfirmedAt: fftypes.Now(),
+		PinCount:    2,
+		Tags:        []string{"tag1", "tag2"},
 	}
 
 	// Rejects hash change
@@ -105,6 +112,9 @@ func TestBatch2EWithDB(t *testing.T) {
 	// Check we get the exact same message back - note the removal of one of the batch elements
 	batchRead, err = s.GetBatchByID(ctx, batchID)
 	assert.NoError(t, err)
+	// The publish time is retained, as it is not updated by an upsert
+	assert.Equal(t, batch.PublishedAt.String(), batchRead.PublishedAt.String())
+	batchUpdated.PublishedAt = batch.PublishedAt
 	batchJson, _ = json.Marshal(&batchUpdated)
 	batchReadJson, _ = json.Marshal(&batchRead)
 	assert.Equal(t, string(batchJson), string(batchReadJson))
@@ -115,8 +125,13 @@ func TestBatch2EWithDB(t *testing.T) {
 		fb.Eq("id", batchUpdated.ID.String()),
 		fb.Eq("namespace", batchUpdated.Namespace),
 		fb.Eq("author", batchUpdated.Author),
+		fb.Eq("creatornode", batchUpdated.CreatorNodeID),
 		fb.Gt("created", "0"),
 		fb.Gt("confirmed", "0"),
+		fb.Gt("confirmedat", "0"),
+		fb.Gte("pincount", 2),
+		fb.Gt("publishedat", "0"),
+		fb.Eq("tags", "tag2"),
 	)
 	batches, _, err := s.GetBatches(ctx, filter)
 	assert.NoError(t, err)
@@ -133,6 +148,44 @@ func TestBatch2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(batches))
 
+	// Tags are stored without limit, and matched as whole elements
+	batch2 := &fftypes.Batch{
+		ID:        fftypes.NewUUID(),
+		Type:      fftypes.MessageTypeBroadcast,
+		Namespace: "ns1",
+		Hash:      fftypes.NewRandB32(),
+		Created:   fftypes.Now(),
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{
+				Type: fftypes.TransactionTypeNone,
+			},
+		},
+	}
+	for i := 20; i < 40; i++ {
+		batch2.Tags = append(batch2.Tags, fmt.Sprintf("tag%d", i))
+	}
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionBatches, fftypes.ChangeEventTypeCreated, "ns1", batch2.ID, mock.Anything).Return()
+	err = s.UpsertBatch(ctx, batch2, true)
+	assert.NoError(t, err)
+	batchRead, err = s.GetBatchByID(ctx, batch2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, batch2.Tags, batchRead.Tags)
+	for i, test := range []struct {
+		filter   database.Filter
+		expected int
+	}{
+		{fb.Eq("tags", "tag2"), 1},
+		{fb.Eq("tags", "tag39"), 1},
+		{fb.Eq("tags", "tag"), 0},
+		{fb.In("tags", []driver.Value{"tag1", "tag20"}), 2},
+		{fb.Neq("tags", "tag1"), 1},
+		{fb.NotIn("tags", []driver.Value{"tag2", "tag20"}), 0},
+	} {
+		batches, _, err = s.GetBatches(ctx, test.filter.Sort("tags"))
+		assert.NoError(t, err)
+		assert.Len(t, batches, test.expected, "filter %d", i)
+	}
+
 	// Update
 	author2 := "0x222222"
 	up := database.BatchQueryFactory.NewUpdate(ctx).Set("author", author2)
@@ -152,6 +205,79 @@ func TestBatch2EWithDB(t *testing.T) {
 	s.callbacks.AssertExpectations(t)
 }
 
+func TestBatchE2EWithDBCompressed(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	s.compress = true
+	ctx := context.Background()
+
+	batchID := fftypes.NewUUID()
+	batch := &fftypes.Batch{
+		ID:        batchID,
+		Type:      fftypes.MessageTypeBroadcast,
+		Author:    "0x12345",
+		Namespace: "ns1",
+		Hash:      fftypes.NewRandB32(),
+		Created:   fftypes.Now(),
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{
+				ID:   fftypes.NewUUID(),
+				Type: fftypes.TransactionTypeBatchPin,
+			},
+			Messages: []*fftypes.Message{
+				{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}},
+			},
+			Data: []*fftypes.Data{
+				{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`{"some":"data"}`)},
+			},
+		},
+	}
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionBatches, fftypes.ChangeEventTypeCreated, "ns1", batchID, mock.Anything).Return()
+	err := s.UpsertBatch(ctx, batch, true)
+	assert.NoError(t, err)
+
+	// Check the payload is stored compressed
+	var compressed bool
+	err = s.db.QueryRow("SELECT payload_compressed FROM batches WHERE id = ?", batchID).Scan(&compressed)
+	assert.NoError(t, err)
+	assert.True(t, compressed)
+
+	// Check we get the exact same batch back
+	batchRead, err := s.GetBatchByID(ctx, batchID)
+	assert.NoError(t, err)
+	batchJson, _ := json.Marshal(&batch)
+	batchReadJson, _ := json.Marshal(&batchRead)
+	assert.Equal(t, string(batchJson), string(batchReadJson))
+
+	// Uncompressed batches stored previously can still be read
+	s.compress = false
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionBatches, fftypes.ChangeEventTypeUpdated, "ns1", batchID, mock.Anything).Return()
+	err = s.UpsertBatch(ctx, batch, true)
+	assert.NoError(t, err)
+	batchRead, err = s.GetBatchByID(ctx, batchID)
+	assert.NoError(t, err)
+	batchReadJson, _ = json.Marshal(&batchRead)
+	assert.Equal(t, string(batchJson), string(batchReadJson))
+}
+
+func TestUpsertBatchCompressFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	s.compress = true
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+	err := s.UpsertBatch(context.Background(), &fftypes.Batch{
+		ID: fftypes.NewUUID(),
+		Payload: fftypes.BatchPayload{
+			Data: []*fftypes.Data{{Value: fftypes.Byteable(`!json`)}},
+		},
+	}, true)
+	assert.Regexp(t, "FF10326", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpsertBatchFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
@@ -195,6 +321,32 @@ func TestUpsertBatchFailUpdate(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUpsertBatchFailUpdateTags(t *testing.T) {
+	s, mock := newMockProvider().init()
+	batchID := fftypes.NewUUID()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(batchID.String()))
+	mock.ExpectExec("UPDATE .*").WillReturnResult(driver.ResultNoRows)
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertBatch(context.Background(), &fftypes.Batch{ID: batchID}, true)
+	assert.Regexp(t, "FF10118", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBatchTagsAddFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	tx, _ := s.db.Begin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	err := s.updateBatchTags(context.Background(), &txWrapper{sqlTX: tx}, &fftypes.Batch{
+		ID:   fftypes.NewUUID(),
+		Tags: []string{"tag1"},
+	}, false)
+	assert.Regexp(t, "FF10116", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpsertBatchFailCommit(t *testing.T) {
 	s, mock := newMockProvider().init()
 	batchID := fftypes.NewUUID()
@@ -235,6 +387,39 @@ func TestGetBatchByIDScanFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetBatchByIDDecompressFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	batchID := fftypes.NewUUID()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(batchColumns).AddRow(
+		batchID.String(), "broadcast", "ns1", "0x12345", nil, nil, nil, []byte("!zstd"), "", nil, "", nil, nil, 1, true, nil, 0, nil))
+	_, err := s.GetBatchByID(context.Background(), batchID)
+	assert.Regexp(t, "FF10121.*FF10327", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBatchByIDLoadTagsFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	batchID := fftypes.NewUUID()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(batchColumns).AddRow(
+		batchID.String(), "broadcast", "ns1", "0x12345", nil, nil, nil, []byte("{}"), "", nil, "", nil, nil, 1, false, nil, 0, nil))
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetBatchByID(context.Background(), batchID)
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBatchesLoadTagsFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	batchID := fftypes.NewUUID()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(batchColumns).AddRow(
+		batchID.String(), "broadcast", "ns1", "0x12345", nil, nil, nil, []byte("{}"), "", nil, "", nil, nil, 1, false, nil, 0, nil))
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"batch_id"}).AddRow("only one"))
+	f := database.BatchQueryFactory.NewFilter(context.Background()).Eq("tags", "tag1")
+	_, _, err := s.GetBatches(context.Background(), f)
+	assert.Regexp(t, "FF10121.*batch_tags", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetBatchesQueryFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
@@ -285,3 +470,43 @@ func TestBatchUpdateFail(t *testing.T) {
 	err := s.UpdateBatch(context.Background(), fftypes.NewUUID(), u)
 	assert.Regexp(t, "FF10117", err)
 }
+
+func TestDeleteBatch(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := s.DeleteBatch(context.Background(), fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBatchFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteBatch(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10114", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBatchFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteBatch(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10118", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBatchFailDeleteBatch(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteBatch(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10118", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -30,12 +30,16 @@ import (
 var (
 	blobColumns = []string{
 		"hash",
+		"checksum",
+		"unverifiable",
 		"payload_ref",
 		"peer",
 		"created",
+		"uploaded_by",
 	}
 	blobFilterFieldMap = map[string]string{
 		"payloadref": "payload_ref",
+		"uploadedby": "uploaded_by",
 	}
 )
 
@@ -51,9 +55,12 @@ func (s *SQLCommon) InsertBlob(ctx context.Context, blob *fftypes.Blob) (err err
 			Columns(blobColumns...).
 			Values(
 				blob.Hash,
+				blob.Checksum,
+				blob.Unverifiable,
 				blob.PayloadRef,
 				blob.Peer,
 				blob.Created,
+				blob.UploadedBy,
 			),
 		nil, // no change events for blobs
 	)
@@ -69,9 +76,12 @@ func (s *SQLCommon) blobResult(ctx context.Context, row *sql.Rows) (*fftypes.Blo
 	blob := fftypes.Blob{}
 	err := row.Scan(
 		&blob.Hash,
+		&blob.Checksum,
+		&blob.Unverifiable,
 		&blob.PayloadRef,
 		&blob.Peer,
 		&blob.Created,
+		&blob.UploadedBy,
 		&blob.Sequence,
 	)
 	if err != nil {
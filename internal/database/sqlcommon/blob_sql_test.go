@@ -38,10 +38,13 @@ func TestBlobsE2EWithDB(t *testing.T) {
 
 	// Create a new blob entry
 	blob := &fftypes.Blob{
-		Hash:       fftypes.NewRandB32(),
-		PayloadRef: fftypes.NewRandB32().String(),
-		Peer:       "peer1",
-		Created:    fftypes.Now(),
+		Hash:         fftypes.NewRandB32(),
+		Checksum:     12345,
+		Unverifiable: true,
+		PayloadRef:   fftypes.NewRandB32().String(),
+		Peer:         "peer1",
+		Created:      fftypes.Now(),
+		UploadedBy:   "org1",
 	}
 	err := s.InsertBlob(ctx, blob)
 	assert.NoError(t, err)
@@ -60,6 +63,7 @@ func TestBlobsE2EWithDB(t *testing.T) {
 		fb.Eq("hash", blob.Hash),
 		fb.Eq("payloadref", blob.PayloadRef),
 		fb.Eq("created", blob.Created),
+		fb.Eq("uploadedby", "org1"),
 	)
 	blobRes, res, err := s.GetBlobs(ctx, filter.Count(true))
 	assert.NoError(t, err)
@@ -19,6 +19,7 @@ package sqlcommon
 import (
 	"context"
 	"database/sql"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/hyperledger/firefly/internal/i18n"
@@ -27,6 +28,11 @@ import (
 	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
+const (
+	// maxDataVersionHistory is the longest chain of versions returned by GetDataVersionHistory
+	maxDataVersionHistory = 1000
+)
+
 var (
 	dataColumnsNoValue = []string{
 		"id",
@@ -38,6 +44,11 @@ var (
 		"created",
 		"blob_hash",
 		"blob_public",
+		"version",
+		"previous_id",
+		"created_from",
+		"expires_at",
+		"blob_uploaded_by",
 	}
 	dataColumnsWithValue = append(append([]string{}, dataColumnsNoValue...), "value")
 	dataFilterFieldMap   = map[string]string{
@@ -46,6 +57,10 @@ var (
 		"datatype.version": "datatype_version",
 		"blob.hash":        "blob_hash",
 		"blob.public":      "blob_public",
+		"previous":         "previous_id",
+		"createdfrom":      "created_from",
+		"expiresat":        "expires_at",
+		"blob.uploadedby":  "blob_uploaded_by",
 	}
 )
 
@@ -92,7 +107,8 @@ func (s *SQLCommon) UpsertData(ctx context.Context, data *fftypes.Data, allowExi
 	}
 
 	if existing {
-		// Update the data
+		// Update the data. The expiry is local to this node, and only set when the data is first inserted,
+		// so is not overwritten by a copy of the data received in a batch.
 		if err = s.updateTx(ctx, tx,
 			sq.Update("data").
 				Set("validator", string(data.Validator)).
@@ -103,6 +119,10 @@ func (s *SQLCommon) UpsertData(ctx context.Context, data *fftypes.Data, allowExi
 				Set("created", data.Created).
 				Set("blob_hash", blob.Hash).
 				Set("blob_public", blob.Public).
+				Set("version", data.Version).
+				Set("previous_id", data.Previous).
+				Set("created_from", data.CreatedFrom).
+				Set("blob_uploaded_by", blob.UploadedBy).
 				Set("value", data.Value).
 				Where(sq.Eq{"id": data.ID}),
 			func() {
@@ -125,6 +145,11 @@ func (s *SQLCommon) UpsertData(ctx context.Context, data *fftypes.Data, allowExi
 					data.Created,
 					blob.Hash,
 					blob.Public,
+					data.Version,
+					data.Previous,
+					data.CreatedFrom,
+					data.ExpiresAt,
+					blob.UploadedBy,
 					data.Value,
 				),
 			func() {
@@ -153,6 +178,11 @@ func (s *SQLCommon) dataResult(ctx context.Context, row *sql.Rows, withValue boo
 		&data.Created,
 		&data.Blob.Hash,
 		&data.Blob.Public,
+		&data.Version,
+		&data.Previous,
+		&data.CreatedFrom,
+		&data.ExpiresAt,
+		&data.Blob.UploadedBy,
 	}
 	if withValue {
 		results = append(results, &data.Value)
@@ -227,6 +257,67 @@ func (s *SQLCommon) GetData(ctx context.Context, filter database.Filter) (messag
 
 }
 
+func (s *SQLCommon) GetDataVersionHistory(ctx context.Context, rootDataID *fftypes.UUID) (history []*fftypes.Data, err error) {
+
+	current, err := s.GetDataByID(ctx, rootDataID, true)
+	if err != nil || current == nil {
+		return nil, err
+	}
+	history = []*fftypes.Data{current}
+	visited := map[fftypes.UUID]bool{*current.ID: true}
+
+	// Walk forwards through the chain, one version at a time, until we find the latest
+	for {
+		if len(history) > maxDataVersionHistory {
+			return nil, i18n.NewError(ctx, i18n.MsgDataVersionHistoryTooLong, rootDataID, maxDataVersionHistory)
+		}
+		next, err := s.getNextDataVersions(ctx, current.ID)
+		if err != nil {
+			return nil, err
+		}
+		switch len(next) {
+		case 0:
+			return history, nil
+		case 1:
+			current = next[0]
+			if visited[*current.ID] {
+				return nil, i18n.NewError(ctx, i18n.MsgDataVersionCycle, current.ID)
+			}
+			visited[*current.ID] = true
+			history = append(history, current)
+		default:
+			nextIDs := make([]string, len(next))
+			for i, d := range next {
+				nextIDs[i] = d.ID.String()
+			}
+			return nil, i18n.NewError(ctx, i18n.MsgDataVersionFork, current.ID, strings.Join(nextIDs, ","))
+		}
+	}
+}
+
+func (s *SQLCommon) getNextDataVersions(ctx context.Context, id *fftypes.UUID) ([]*fftypes.Data, error) {
+	rows, _, err := s.query(ctx,
+		sq.Select(dataColumnsWithValue...).
+			From("data").
+			Where(sq.Eq{"previous_id": id}).
+			OrderBy("seq"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	data := []*fftypes.Data{}
+	for rows.Next() {
+		d, err := s.dataResult(ctx, rows, true)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, d)
+	}
+	return data, nil
+}
+
 func (s *SQLCommon) GetDataRefs(ctx context.Context, filter database.Filter) (message fftypes.DataRefs, res *database.FilterResult, err error) {
 
 	query, fop, fi, err := s.filterSelect(ctx, "", sq.Select("id", "hash").From("data"), filter, dataFilterFieldMap, []string{"sequence"})
@@ -278,3 +369,27 @@ func (s *SQLCommon) UpdateData(ctx context.Context, id *fftypes.UUID, update dat
 
 	return s.commitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) DeleteData(ctx context.Context, id *fftypes.UUID) (err error) {
+
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	data, err := s.GetDataByID(ctx, id, false)
+	if err == nil && data != nil {
+		err = s.deleteTx(ctx, tx, sq.Delete("data").Where(sq.Eq{
+			"id": id,
+		}),
+			func() {
+				s.callbacks.UUIDCollectionNSEvent(database.CollectionData, fftypes.ChangeEventTypeDeleted, data.Namespace, data.ID)
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,74 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build sqlite_json
+// +build sqlite_json
+
+package sqlcommon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// The JSON functions are only compiled into the SQLite driver with the sqlite_json build tag
+
+func TestDataJSONPathFilterE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionData, fftypes.ChangeEventTypeCreated, "ns1", mock.Anything).Return()
+
+	for _, value := range []string{
+		`{"customer":{"id":123,"name":"Acme Inc."},"total":15.5}`,
+		`{"customer":{"id":"123","name":"Other Corp"},"total":5}`,
+		`{"customer":{"id":456,"name":"Acme Ltd."}}`,
+	} {
+		err := s.UpsertData(ctx, &fftypes.Data{
+			ID:        fftypes.NewUUID(),
+			Validator: fftypes.ValidatorTypeJSON,
+			Namespace: "ns1",
+			Hash:      fftypes.NewRandB32(),
+			Created:   fftypes.Now(),
+			Value:     fftypes.Byteable(value),
+		}, false, false)
+		assert.NoError(t, err)
+	}
+
+	fb := database.DataQueryFactory.NewFilter(ctx)
+	for i, test := range []struct {
+		filter   database.Filter
+		expected int
+	}{
+		{fb.Eq("value.customer.id", 123), 1},
+		{fb.Eq("value.customer.id", "123"), 1},
+		// Numeric comparisons only consider numeric values
+		{fb.Neq("value.customer.id", 123), 1},
+		{fb.Gt("value.customer.id", 100), 2},
+		{fb.Lt("value.total", 10), 1},
+		{fb.Contains("value.customer.name", "Acme"), 2},
+	} {
+		data, _, err := s.GetData(ctx, test.filter)
+		assert.NoError(t, err)
+		assert.Len(t, data, test.expected, "filter %d", i)
+	}
+}
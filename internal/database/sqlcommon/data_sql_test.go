@@ -52,6 +52,7 @@ func TestDataE2EWithDB(t *testing.T) {
 		Hash:      fftypes.NewRandB32(),
 		Created:   fftypes.Now(),
 		Value:     []byte(val.String()),
+		ExpiresAt: fftypes.Now(),
 	}
 
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionData, fftypes.ChangeEventTypeCreated, "ns1", dataID, mock.Anything).Return()
@@ -94,9 +95,11 @@ func TestDataE2EWithDB(t *testing.T) {
 		Created: fftypes.Now(),
 		Value:   []byte(val2.String()),
 		Blob: &fftypes.BlobRef{
-			Hash:   fftypes.NewRandB32(),
-			Public: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+			Hash:       fftypes.NewRandB32(),
+			Public:     "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+			UploadedBy: "org1",
 		},
+		CreatedFrom: fftypes.NewUUID(),
 	}
 
 	// Check disallows hash update
@@ -106,6 +109,9 @@ func TestDataE2EWithDB(t *testing.T) {
 	err = s.UpsertData(context.Background(), dataUpdated, true, true)
 	assert.NoError(t, err)
 
+	// The expiry is retained from the original insert
+	dataUpdated.ExpiresAt = data.ExpiresAt
+
 	// Check we get the exact same message back - note the removal of one of the data elements
 	dataRead, err = s.GetDataByID(ctx, dataID, true)
 	assert.NoError(t, err)
@@ -126,7 +132,10 @@ func TestDataE2EWithDB(t *testing.T) {
 		fb.Eq("datatype.name", dataUpdated.Datatype.Name),
 		fb.Eq("datatype.version", dataUpdated.Datatype.Version),
 		fb.Eq("hash", dataUpdated.Hash),
+		fb.Eq("createdfrom", dataUpdated.CreatedFrom),
 		fb.Gt("created", 0),
+		fb.Lte("expiresat", dataUpdated.ExpiresAt),
+		fb.Eq("blob.uploadedby", "org1"),
 	)
 	dataRes, _, err := s.GetData(ctx, filter)
 	assert.NoError(t, err)
@@ -156,9 +165,74 @@ func TestDataE2EWithDB(t *testing.T) {
 	assert.Equal(t, 1, len(dataRes))
 	assert.Equal(t, int64(1), *res.TotalCount)
 
+	// Delete the data
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionData, fftypes.ChangeEventTypeDeleted, "ns1", dataID, mock.Anything).Return()
+	err = s.DeleteData(ctx, dataID)
+	assert.NoError(t, err)
+	dataRead, err = s.GetDataByID(ctx, dataID, false)
+	assert.NoError(t, err)
+	assert.Nil(t, dataRead)
+
 	s.callbacks.AssertExpectations(t)
 }
 
+func TestDataVersionHistoryE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionData, fftypes.ChangeEventTypeCreated, "ns1", mock.Anything).Return()
+
+	newVersion := func(previous *fftypes.Data) *fftypes.Data {
+		data := &fftypes.Data{
+			ID:        fftypes.NewUUID(),
+			Validator: fftypes.ValidatorTypeJSON,
+			Namespace: "ns1",
+			Hash:      fftypes.NewRandB32(),
+			Created:   fftypes.Now(),
+			Value:     fftypes.Byteable(`{"some":"data"}`),
+			Version:   1,
+		}
+		if previous != nil {
+			data.Previous = previous.ID
+			data.Version = previous.Version + 1
+		}
+		err := s.UpsertData(ctx, data, false, false)
+		assert.NoError(t, err)
+		return data
+	}
+
+	// Build a linear chain of three versions
+	v1 := newVersion(nil)
+	v2 := newVersion(v1)
+	v3 := newVersion(v2)
+
+	history, err := s.GetDataVersionHistory(ctx, v1.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 3)
+	for i, d := range []*fftypes.Data{v1, v2, v3} {
+		assert.Equal(t, *d.ID, *history[i].ID)
+		assert.Equal(t, i+1, history[i].Version)
+	}
+	assert.Nil(t, history[0].Previous)
+	assert.Equal(t, *v2.ID, *history[2].Previous)
+
+	// Starting part way through the chain gives the remaining versions
+	history, err = s.GetDataVersionHistory(ctx, v2.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+
+	// Unknown data gives no history
+	history, err = s.GetDataVersionHistory(ctx, fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.Nil(t, history)
+
+	// Fork the chain by adding a second update to v2
+	v3fork := newVersion(v2)
+	_, err = s.GetDataVersionHistory(ctx, v1.ID)
+	assert.Regexp(t, "FF10303.*"+v3.ID.String()+","+v3fork.ID.String(), err)
+}
+
 func TestUpsertDataFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
@@ -242,6 +316,63 @@ func TestGetDataByIDScanFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func newTestDataRows() *sqlmock.Rows {
+	return newTestDataRowsWithID(fftypes.NewUUID())
+}
+
+func newTestDataRowsWithID(id *fftypes.UUID) *sqlmock.Rows {
+	return sqlmock.NewRows(dataColumnsWithValue).
+		AddRow(id.String(), "json", "ns1", "", "", nil, nil, nil, "", 1, nil, nil, nil, "", []byte(`{}`))
+}
+
+func TestGetDataVersionHistoryRootFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetDataVersionHistory(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDataVersionHistoryNextFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(newTestDataRows())
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetDataVersionHistory(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDataVersionHistoryCycle(t *testing.T) {
+	s, mock := newMockProvider().init()
+	v1 := fftypes.NewUUID()
+	v2 := fftypes.NewUUID()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(newTestDataRowsWithID(v1))
+	mock.ExpectQuery("SELECT .*").WillReturnRows(newTestDataRowsWithID(v2))
+	mock.ExpectQuery("SELECT .*").WillReturnRows(newTestDataRowsWithID(v1))
+	_, err := s.GetDataVersionHistory(context.Background(), v1)
+	assert.Regexp(t, "FF10360.*"+v1.String(), err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDataVersionHistoryTooLong(t *testing.T) {
+	s, mock := newMockProvider().init()
+	for i := 0; i <= maxDataVersionHistory; i++ {
+		mock.ExpectQuery("SELECT .*").WillReturnRows(newTestDataRows())
+	}
+	_, err := s.GetDataVersionHistory(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10361", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDataVersionHistoryNextScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(newTestDataRows())
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetDataVersionHistory(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetDataQueryFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
@@ -317,3 +448,21 @@ func TestDataUpdateFail(t *testing.T) {
 	err := s.UpdateData(context.Background(), fftypes.NewUUID(), u)
 	assert.Regexp(t, "FF10117", err)
 }
+
+func TestDataDeleteBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteData(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10114", err)
+}
+
+func TestDataDeleteFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(dataColumnsNoValue).AddRow(
+		fftypes.NewUUID(), "json", "ns1", "", "", fftypes.NewRandB32(), fftypes.Now(), nil, "", 0, nil, nil, nil, ""),
+	)
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteData(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10118", err)
+}
@@ -0,0 +1,129 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+var (
+	deadLetterEventColumns = []string{
+		"id",
+		"namespace",
+		"subscription_id",
+		"event_id",
+		"attempts",
+		"created",
+	}
+	deadLetterEventFilterFieldMap = map[string]string{
+		"subscription": "subscription_id",
+		"event":        "event_id",
+	}
+)
+
+func (s *SQLCommon) InsertDeadLetterEvent(ctx context.Context, dle *fftypes.DeadLetterEvent) (err error) {
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	_, err = s.insertTx(ctx, tx,
+		sq.Insert("dead_letter_events").
+			Columns(deadLetterEventColumns...).
+			Values(
+				dle.ID,
+				dle.Namespace,
+				dle.Subscription,
+				dle.Event,
+				dle.Attempts,
+				dle.Created,
+			),
+		nil, // no change events for dead lettered events
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) deadLetterEventResult(ctx context.Context, row *sql.Rows) (*fftypes.DeadLetterEvent, error) {
+	dle := fftypes.DeadLetterEvent{}
+	err := row.Scan(
+		&dle.ID,
+		&dle.Namespace,
+		&dle.Subscription,
+		&dle.Event,
+		&dle.Attempts,
+		&dle.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "dead_letter_events")
+	}
+	return &dle, nil
+}
+
+func (s *SQLCommon) GetDeadLetterEvents(ctx context.Context, filter database.Filter) (dles []*fftypes.DeadLetterEvent, res *database.FilterResult, err error) {
+
+	query, fop, fi, err := s.filterSelect(ctx, "", sq.Select(deadLetterEventColumns...).From("dead_letter_events"), filter, deadLetterEventFilterFieldMap, []string{"sequence"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.query(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	dles = []*fftypes.DeadLetterEvent{}
+	for rows.Next() {
+		dle, err := s.deadLetterEventResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		dles = append(dles, dle)
+	}
+
+	return dles, s.queryRes(ctx, tx, "dead_letter_events", fop, fi), err
+
+}
+
+func (s *SQLCommon) DeleteDeadLetterEvent(ctx context.Context, id *fftypes.UUID) (err error) {
+
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	err = s.deleteTx(ctx, tx, sq.Delete("dead_letter_events").Where(sq.Eq{
+		"id": id,
+	}), nil /* no change events for dead lettered events */)
+	if err != nil {
+		return err
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
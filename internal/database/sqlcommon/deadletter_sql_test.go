@@ -0,0 +1,144 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetterEventsE2EWithDB(t *testing.T) {
+	log.SetLevel("debug")
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new dead letter entry
+	dle := &fftypes.DeadLetterEvent{
+		ID:           fftypes.NewUUID(),
+		Namespace:    "ns1",
+		Subscription: fftypes.NewUUID(),
+		Event:        fftypes.NewUUID(),
+		Attempts:     4,
+		Created:      fftypes.Now(),
+	}
+	err := s.InsertDeadLetterEvent(ctx, dle)
+	assert.NoError(t, err)
+
+	// Query back the entry
+	fb := database.DeadLetterEventQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("namespace", dle.Namespace),
+		fb.Eq("subscription", dle.Subscription),
+		fb.Eq("event", dle.Event),
+		fb.Eq("attempts", 4),
+		fb.Eq("created", dle.Created),
+	)
+	dles, res, err := s.GetDeadLetterEvents(ctx, filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(dles))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	dleJson, _ := json.Marshal(&dle)
+	dleReadJson, _ := json.Marshal(dles[0])
+	assert.Equal(t, string(dleJson), string(dleReadJson))
+
+	// Test delete
+	err = s.DeleteDeadLetterEvent(ctx, dle.ID)
+	assert.NoError(t, err)
+	dles, _, err = s.GetDeadLetterEvents(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(dles))
+
+}
+
+func TestInsertDeadLetterEventFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertDeadLetterEvent(context.Background(), &fftypes.DeadLetterEvent{})
+	assert.Regexp(t, "FF10114", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertDeadLetterEventFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertDeadLetterEvent(context.Background(), &fftypes.DeadLetterEvent{ID: fftypes.NewUUID()})
+	assert.Regexp(t, "FF10116", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertDeadLetterEventFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertDeadLetterEvent(context.Background(), &fftypes.DeadLetterEvent{ID: fftypes.NewUUID()})
+	assert.Regexp(t, "FF10119", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLetterEventsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.DeadLetterEventQueryFactory.NewFilter(context.Background()).Eq("namespace", "")
+	_, _, err := s.GetDeadLetterEvents(context.Background(), f)
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLetterEventsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.DeadLetterEventQueryFactory.NewFilter(context.Background()).Eq("namespace", map[bool]bool{true: false})
+	_, _, err := s.GetDeadLetterEvents(context.Background(), f)
+	assert.Regexp(t, "FF10149.*namespace", err)
+}
+
+func TestGetDeadLetterEventsReadFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.DeadLetterEventQueryFactory.NewFilter(context.Background()).Eq("namespace", "")
+	_, _, err := s.GetDeadLetterEvents(context.Background(), f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeadLetterEventDeleteBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteDeadLetterEvent(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10114", err)
+}
+
+func TestDeadLetterEventDeleteFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteDeadLetterEvent(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10118", err)
+}
@@ -0,0 +1,156 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+var (
+	deliveryFailureColumns = []string{
+		"namespace",
+		"subscription_id",
+		"event_id",
+		"attempts",
+		"updated",
+	}
+	deliveryFailureFilterFieldMap = map[string]string{
+		"subscription": "subscription_id",
+		"event":        "event_id",
+	}
+)
+
+func (s *SQLCommon) UpsertDeliveryFailure(ctx context.Context, df *fftypes.DeliveryFailure) (err error) {
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	// Do a select within the transaction to detemine if the failure is already counted
+	dfRows, _, err := s.queryTx(ctx, tx,
+		sq.Select("seq").
+			From("delivery_failures").
+			Where(sq.Eq{
+				"subscription_id": df.Subscription,
+				"event_id":        df.Event,
+			}),
+	)
+	if err != nil {
+		return err
+	}
+	existing := dfRows.Next()
+	dfRows.Close()
+
+	if existing {
+		if err = s.updateTx(ctx, tx,
+			sq.Update("delivery_failures").
+				Set("attempts", df.Attempts).
+				Set("updated", df.Updated).
+				Where(sq.Eq{
+					"subscription_id": df.Subscription,
+					"event_id":        df.Event,
+				}),
+			nil, // no change events for delivery failures
+		); err != nil {
+			return err
+		}
+	} else {
+		if _, err = s.insertTx(ctx, tx,
+			sq.Insert("delivery_failures").
+				Columns(deliveryFailureColumns...).
+				Values(
+					df.Namespace,
+					df.Subscription,
+					df.Event,
+					df.Attempts,
+					df.Updated,
+				),
+			nil, // no change events for delivery failures
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) deliveryFailureResult(ctx context.Context, row *sql.Rows) (*fftypes.DeliveryFailure, error) {
+	df := fftypes.DeliveryFailure{}
+	err := row.Scan(
+		&df.Namespace,
+		&df.Subscription,
+		&df.Event,
+		&df.Attempts,
+		&df.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "delivery_failures")
+	}
+	return &df, nil
+}
+
+func (s *SQLCommon) GetDeliveryFailures(ctx context.Context, filter database.Filter) (dfs []*fftypes.DeliveryFailure, res *database.FilterResult, err error) {
+
+	query, fop, fi, err := s.filterSelect(ctx, "", sq.Select(deliveryFailureColumns...).From("delivery_failures"), filter, deliveryFailureFilterFieldMap, []string{"sequence"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.query(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	dfs = []*fftypes.DeliveryFailure{}
+	for rows.Next() {
+		df, err := s.deliveryFailureResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		dfs = append(dfs, df)
+	}
+
+	return dfs, s.queryRes(ctx, tx, "delivery_failures", fop, fi), err
+
+}
+
+func (s *SQLCommon) DeleteDeliveryFailure(ctx context.Context, subscription, event *fftypes.UUID) (err error) {
+
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	err = s.deleteTx(ctx, tx, sq.Delete("delivery_failures").Where(sq.Eq{
+		"subscription_id": subscription,
+		"event_id":        event,
+	}), nil /* no change events for delivery failures */)
+	if err != nil {
+		return err
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,172 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryFailuresE2EWithDB(t *testing.T) {
+	log.SetLevel("debug")
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Record the first failure
+	df := &fftypes.DeliveryFailure{
+		Namespace:    "ns1",
+		Subscription: fftypes.NewUUID(),
+		Event:        fftypes.NewUUID(),
+		Attempts:     1,
+		Updated:      fftypes.Now(),
+	}
+	err := s.UpsertDeliveryFailure(ctx, df)
+	assert.NoError(t, err)
+
+	// Update the count
+	df.Attempts = 2
+	df.Updated = fftypes.Now()
+	err = s.UpsertDeliveryFailure(ctx, df)
+	assert.NoError(t, err)
+
+	// Query back the count
+	fb := database.DeliveryFailureQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("namespace", df.Namespace),
+		fb.Eq("subscription", df.Subscription),
+		fb.Eq("event", df.Event),
+		fb.Eq("attempts", 2),
+		fb.Eq("updated", df.Updated),
+	)
+	dfs, res, err := s.GetDeliveryFailures(ctx, filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(dfs))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	dfJson, _ := json.Marshal(&df)
+	dfReadJson, _ := json.Marshal(dfs[0])
+	assert.Equal(t, string(dfJson), string(dfReadJson))
+
+	// Test delete
+	err = s.DeleteDeliveryFailure(ctx, df.Subscription, df.Event)
+	assert.NoError(t, err)
+	dfs, _, err = s.GetDeliveryFailures(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(dfs))
+
+}
+
+func TestUpsertDeliveryFailureFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertDeliveryFailure(context.Background(), &fftypes.DeliveryFailure{})
+	assert.Regexp(t, "FF10114", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertDeliveryFailureFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertDeliveryFailure(context.Background(), &fftypes.DeliveryFailure{})
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertDeliveryFailureFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertDeliveryFailure(context.Background(), &fftypes.DeliveryFailure{})
+	assert.Regexp(t, "FF10116", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertDeliveryFailureFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(1))
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertDeliveryFailure(context.Background(), &fftypes.DeliveryFailure{})
+	assert.Regexp(t, "FF10117", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertDeliveryFailureFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertDeliveryFailure(context.Background(), &fftypes.DeliveryFailure{})
+	assert.Regexp(t, "FF10119", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeliveryFailuresQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.DeliveryFailureQueryFactory.NewFilter(context.Background()).Eq("namespace", "")
+	_, _, err := s.GetDeliveryFailures(context.Background(), f)
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeliveryFailuresBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.DeliveryFailureQueryFactory.NewFilter(context.Background()).Eq("namespace", map[bool]bool{true: false})
+	_, _, err := s.GetDeliveryFailures(context.Background(), f)
+	assert.Regexp(t, "FF10149.*namespace", err)
+}
+
+func TestGetDeliveryFailuresReadFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"namespace"}).AddRow("only one"))
+	f := database.DeliveryFailureQueryFactory.NewFilter(context.Background()).Eq("namespace", "")
+	_, _, err := s.GetDeliveryFailures(context.Background(), f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeliveryFailureDeleteBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteDeliveryFailure(context.Background(), fftypes.NewUUID(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10114", err)
+}
+
+func TestDeliveryFailureDeleteFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteDeliveryFailure(context.Background(), fftypes.NewUUID(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10118", err)
+}
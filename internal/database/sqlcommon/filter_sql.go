@@ -18,19 +18,44 @@ package sqlcommon
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/hyperledger/firefly/internal/i18n"
 	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
+// childTableField is a filter field holding multiple values, which are stored as rows of a child table
+// rather than in a column. The field is filtered by whether the parent has a row with a matching value.
+type childTableField struct {
+	table     string
+	parentKey string // column of the child table holding the id of the parent
+	column    string // column of the child table holding the values
+}
+
+// childTableFields are keyed by the name the field is mapped to in the filter field map of the parent
+var childTableFields = map[string]*childTableField{
+	"batch_tags.tag": {table: "batch_tags", parentKey: "batch_id", column: "tag"},
+}
+
 func (s *SQLCommon) filterSelect(ctx context.Context, tableName string, sel sq.SelectBuilder, filter database.Filter, typeMap map[string]string, defaultSort []string, preconditions ...sq.Sqlizer) (sq.SelectBuilder, sq.Sqlizer, *database.FilterInfo, error) {
 	fi, err := filter.Finalize()
 	if err != nil {
 		return sel, nil, nil, err
 	}
+	// Fields held in a child table have no single value to sort by
+	sortFields := make([]*database.SortField, 0, len(fi.Sort))
+	for _, sf := range fi.Sort {
+		if _, isChild := childTableFields[s.mapField("", sf.Field, typeMap)]; !isChild {
+			sortFields = append(sortFields, sf)
+		}
+	}
+	fi.Sort = sortFields
 	if len(fi.Sort) == 0 {
 		for _, s := range defaultSort {
 			fi.Sort = append(fi.Sort, &database.SortField{Field: s, Descending: true})
@@ -102,7 +127,13 @@ func (s *SQLCommon) filterUpdate(ctx context.Context, tableName string, update s
 
 func (s *SQLCommon) escapeLike(value database.FieldSerialization) string {
 	v, _ := value.Value()
-	vs, _ := v.(string)
+	var vs string
+	switch tv := v.(type) {
+	case string:
+		vs = tv
+	case []byte:
+		vs = string(tv)
+	}
 	vs = strings.ReplaceAll(vs, "[", "[[]")
 	vs = strings.ReplaceAll(vs, "%", "[%]")
 	vs = strings.ReplaceAll(vs, "_", "[_]")
@@ -129,6 +160,12 @@ func (s *SQLCommon) mapField(tableName, fieldName string, tm map[string]string)
 }
 
 func (s *SQLCommon) filterOp(ctx context.Context, tableName string, op *database.FilterInfo, tm map[string]string) (sq.Sqlizer, error) {
+	if len(op.JSONPath) > 0 {
+		return s.filterJSONPathOp(ctx, tableName, op, tm)
+	}
+	if ctf, ok := childTableFields[s.mapField("", op.Field, tm)]; ok {
+		return s.filterChildTableOp(ctx, tableName, ctf, op)
+	}
 	switch op.Op {
 	case database.FilterOpOr:
 		return s.filterOr(ctx, tableName, op, tm)
@@ -158,11 +195,129 @@ func (s *SQLCommon) filterOp(ctx context.Context, tableName string, op *database
 		return sq.Lt{s.mapField(tableName, op.Field, tm): op.Value}, nil
 	case database.FilterOpLte:
 		return sq.LtOrEq{s.mapField(tableName, op.Field, tm): op.Value}, nil
+	case database.FilterOpJSONContains:
+		return s.filterJSONContains(ctx, tableName, op, tm)
+	case database.FilterOpJSONHasKey:
+		key, _ := op.Value.Value()
+		return s.provider.JSONHasKey(s.mapField(tableName, op.Field, tm), key.(string)), nil
 	default:
 		return nil, i18n.NewError(ctx, i18n.MsgUnsupportedSQLOpInFilter, op.Op)
 	}
 }
 
+func (s *SQLCommon) filterChildTableOp(ctx context.Context, tableName string, ctf *childTableField, op *database.FilterInfo) (sq.Sqlizer, error) {
+	var match interface{}
+	var not bool
+	switch op.Op {
+	case database.FilterOpEq:
+		match = op.Value
+	case database.FilterOpNe:
+		match, not = op.Value, true
+	case database.FilterOpIn:
+		match = op.Values
+	case database.FilterOpNotIn:
+		match, not = op.Values, true
+	default:
+		return nil, i18n.NewError(ctx, i18n.MsgUnsupportedSQLOpInFilter, op.Op)
+	}
+	subQuery, args, _ := sq.Select(ctf.parentKey).From(ctf.table).Where(sq.Eq{ctf.column: match}).ToSql()
+	sqlOp := "IN"
+	if not {
+		sqlOp = "NOT IN"
+	}
+	return sq.Expr(fmt.Sprintf("%s %s (%s)", s.mapField(tableName, "id", nil), sqlOp, subQuery), args...), nil
+}
+
+func (s *SQLCommon) filterJSONPathOp(ctx context.Context, tableName string, op *database.FilterInfo, tm map[string]string) (sq.Sqlizer, error) {
+	v, _ := op.Value.Value()
+	var numeric bool
+	switch v.(type) {
+	case int64, float64:
+		numeric = true
+	}
+	expr, args := s.provider.JSONExtract(s.mapField(tableName, op.Field, tm), op.JSONPath, numeric)
+	var sqlOp string
+	switch op.Op {
+	case database.FilterOpEq:
+		sqlOp = "="
+	case database.FilterOpNe:
+		sqlOp = "<>"
+	case database.FilterOpGt:
+		sqlOp = ">"
+	case database.FilterOpGte:
+		sqlOp = ">="
+	case database.FilterOpLt:
+		sqlOp = "<"
+	case database.FilterOpLte:
+		sqlOp = "<="
+	case database.FilterOpCont:
+		sqlOp = "LIKE"
+		v = fmt.Sprintf("%%%s%%", s.escapeLike(op.Value))
+	case database.FilterOpNotCont:
+		sqlOp = "NOT LIKE"
+		v = fmt.Sprintf("%%%s%%", s.escapeLike(op.Value))
+	default:
+		return nil, i18n.NewError(ctx, i18n.MsgUnsupportedSQLOpInFilter, op.Op)
+	}
+	return sq.Expr(fmt.Sprintf("%s %s ?", expr, sqlOp), append(args, v)...), nil
+}
+
+func (s *SQLCommon) filterJSONContains(ctx context.Context, tableName string, op *database.FilterInfo, tm map[string]string) (sq.Sqlizer, error) {
+	v, _ := op.Value.Value()
+	var obj fftypes.JSONObject
+	if err := json.Unmarshal(v.([]byte), &obj); err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgInvalidValueForFilterField, op.Field)
+	}
+	return s.provider.JSONContains(s.mapField(tableName, op.Field, tm), obj), nil
+}
+
+// SQLiteJSONContains builds a JSONContains condition for SQLite, comparing the value extracted
+// at each key of the object. The values of the object must be strings, numbers or booleans.
+func SQLiteJSONContains(column string, obj fftypes.JSONObject) sq.Sqlizer {
+	and := sq.And{}
+	for _, k := range sortedKeys(obj) {
+		and = append(and, sq.Expr(fmt.Sprintf("json_extract(CAST(%s AS TEXT), ?) = ?", column), sqliteJSONKeyPath(k), obj[k]))
+	}
+	return and
+}
+
+// SQLiteJSONHasKey builds a JSONHasKey condition for SQLite
+func SQLiteJSONHasKey(column, key string) sq.Sqlizer {
+	return sq.Expr(fmt.Sprintf("json_type(CAST(%s AS TEXT), ?) IS NOT NULL", column), sqliteJSONKeyPath(key))
+}
+
+func sqliteJSONKeyPath(key string) string {
+	return fmt.Sprintf("$.%s", strconv.Quote(key))
+}
+
+func sortedKeys(obj fftypes.JSONObject) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SQLiteJSONExtract builds a JSONExtract expression for SQLite, where json_extract returns numbers
+// as numeric values. SQLite orders all strings after all numbers, so strings are excluded from
+// numeric comparisons rather than matching every "greater than" query.
+func SQLiteJSONExtract(column string, path []string, numeric bool) (string, []interface{}) {
+	jsonPath := "$"
+	for _, p := range path {
+		if _, err := strconv.ParseUint(p, 10, 32); err == nil {
+			jsonPath += fmt.Sprintf("[%s]", p)
+		} else {
+			jsonPath += fmt.Sprintf(".%s", strconv.Quote(p))
+		}
+	}
+	if numeric {
+		return fmt.Sprintf("(CASE WHEN json_type(CAST(%[1]s AS TEXT), ?) IN ('integer','real') THEN json_extract(CAST(%[1]s AS TEXT), ?) END)", column),
+			[]interface{}{jsonPath, jsonPath}
+	}
+	return fmt.Sprintf("json_extract(CAST(%s AS TEXT), ?)", column), []interface{}{jsonPath}
+}
+
 func (s *SQLCommon) filterOr(ctx context.Context, tableName string, op *database.FilterInfo, tm map[string]string) (sq.Sqlizer, error) {
 	var err error
 	or := make(sq.Or, len(op.Children))
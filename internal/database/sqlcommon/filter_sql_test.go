@@ -93,6 +93,116 @@ func TestSQLQueryFactoryExtraOps(t *testing.T) {
 	assert.Equal(t, "SELECT * FROM mytable AS mt WHERE (mt.created IN (?,?,?) AND mt.created NOT IN (?,?,?) AND mt.id = ? AND mt.id IN (?) AND mt.id IS NOT NULL AND mt.created < ? AND mt.created <= ? AND mt.created >= ? AND mt.created <> ? AND mt.seq > ? AND mt.topics LIKE ? AND mt.topics NOT LIKE ? AND mt.topics ILIKE ? AND mt.topics NOT ILIKE ?) ORDER BY mt.seq DESC", sqlFilter)
 }
 
+func TestSQLQueryFactoryJSONPath(t *testing.T) {
+
+	s, _ := newMockProvider().init()
+	fb := database.DataQueryFactory.NewFilter(context.Background())
+	f := fb.And(
+		fb.Eq("value.customer.id", 123),
+		fb.Neq("value.customer.id", "abc"),
+		fb.Gt("value.items.0.price", 1.5),
+		fb.Gte("value.total", 10),
+		fb.Lt("value.total", 20),
+		fb.Lte("value.total", 30),
+		fb.Contains("value.customer.name", "acme"),
+		fb.NotContains("value.customer.name", "corp"),
+	)
+
+	sel := squirrel.Select("*").From("data")
+	sel, _, _, err := s.filterSelect(context.Background(), "", sel, f, nil, []string{"sequence"})
+	assert.NoError(t, err)
+
+	sqlFilter, args, err := sel.ToSql()
+	assert.NoError(t, err)
+	num := "(CASE WHEN json_type(CAST(value AS TEXT), ?) IN ('integer','real') THEN json_extract(CAST(value AS TEXT), ?) END)"
+	str := "json_extract(CAST(value AS TEXT), ?)"
+	assert.Equal(t, "SELECT * FROM data WHERE ("+
+		num+" = ? AND "+
+		str+" <> ? AND "+
+		num+" > ? AND "+
+		num+" >= ? AND "+
+		num+" < ? AND "+
+		num+" <= ? AND "+
+		str+" LIKE ? AND "+
+		str+" NOT LIKE ?) ORDER BY seq DESC", sqlFilter)
+	assert.Equal(t, `$."customer"."id"`, args[0])
+	assert.Equal(t, int64(123), args[2])
+	assert.Equal(t, "abc", args[4])
+	assert.Equal(t, `$."items"[0]."price"`, args[5])
+	assert.Equal(t, float64(1.5), args[7])
+	assert.Equal(t, "%acme%", args[18])
+}
+
+func TestSQLQueryFactoryJSONPathBadOp(t *testing.T) {
+
+	s, _ := newMockProvider().init()
+	fb := database.DataQueryFactory.NewFilter(context.Background())
+	fi, err := fb.IContains("value.customer.name", "acme").Finalize()
+	assert.NoError(t, err)
+	_, err = s.filterSelectFinalized(context.Background(), "", fi, nil)
+	assert.Regexp(t, "FF10150", err)
+}
+
+func TestSQLQueryFactoryJSONContains(t *testing.T) {
+
+	s, _ := newMockProvider().init()
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	f := fb.And(
+		fb.JSONContains("labels", fftypes.JSONObject{"region": "eu", "department": "finance"}),
+		fb.JSONHasKey("labels", "priority"),
+	)
+
+	sel := squirrel.Select("*").From("messages")
+	sel, _, _, err := s.filterSelect(context.Background(), "", sel, f, nil, []string{"sequence"})
+	assert.NoError(t, err)
+
+	sqlFilter, args, err := sel.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM messages WHERE ((json_extract(CAST(labels AS TEXT), ?) = ? AND json_extract(CAST(labels AS TEXT), ?) = ?) AND "+
+		"json_type(CAST(labels AS TEXT), ?) IS NOT NULL) ORDER BY seq DESC", sqlFilter)
+	assert.Equal(t, []interface{}{`$."department"`, "finance", `$."region"`, "eu", `$."priority"`}, args)
+}
+
+func TestSQLQueryFactoryJSONContainsNotObject(t *testing.T) {
+
+	s, _ := newMockProvider().init()
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	fi, err := fb.JSONContains("labels", "[]").Finalize()
+	assert.NoError(t, err)
+	_, err = s.filterSelectFinalized(context.Background(), "", fi, nil)
+	assert.Regexp(t, "FF10149.*labels", err)
+}
+
+func TestSQLQueryFactoryChildTable(t *testing.T) {
+
+	s, _ := newMockProvider().init()
+	fb := database.BatchQueryFactory.NewFilter(context.Background())
+	f := fb.And(
+		fb.Eq("tags", "tag1"),
+		fb.NotIn("tags", []driver.Value{"tag2", "tag3"}),
+	).Sort("tags")
+
+	sel := squirrel.Select("*").From("batches")
+	sel, _, _, err := s.filterSelect(context.Background(), "", sel, f, batchFilterFieldMap, []string{"sequence"})
+	assert.NoError(t, err)
+
+	sqlFilter, args, err := sel.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM batches WHERE (id IN (SELECT batch_id FROM batch_tags WHERE tag = ?) AND "+
+		"id NOT IN (SELECT batch_id FROM batch_tags WHERE tag IN (?,?))) ORDER BY seq DESC", sqlFilter)
+	assert.Len(t, args, 3)
+}
+
+func TestSQLQueryFactoryChildTableBadOp(t *testing.T) {
+
+	s, _ := newMockProvider().init()
+	fb := database.BatchQueryFactory.NewFilter(context.Background())
+	fi, err := fb.Contains("tags", "tag").Finalize()
+	assert.NoError(t, err)
+	_, err = s.filterSelectFinalized(context.Background(), "", fi, batchFilterFieldMap)
+	assert.Regexp(t, "FF10150", err)
+}
+
 func TestSQLQueryFactoryFinalizeFail(t *testing.T) {
 	s, _ := newMockProvider().init()
 	fb := database.MessageQueryFactory.NewFilter(context.Background())
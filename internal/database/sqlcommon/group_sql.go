@@ -35,9 +35,17 @@ var (
 		"ledger",
 		"hash",
 		"created",
+		"schema_name",
+		"schema_version",
+		"description",
+		"nonce",
+		"acl",
+		"previous",
 	}
 	groupFilterFieldMap = map[string]string{
-		"message": "message_id",
+		"message":        "message_id",
+		"schema.name":    "schema_name",
+		"schema.version": "schema_version",
 	}
 )
 
@@ -48,6 +56,11 @@ func (s *SQLCommon) UpsertGroup(ctx context.Context, group *fftypes.Group, allow
 	}
 	defer s.rollbackTx(ctx, tx, autoCommit)
 
+	schema := group.Schema
+	if schema == nil {
+		schema = &fftypes.DatatypeRef{}
+	}
+
 	existing := false
 	if allowExisting {
 		// Do a select within the transaction to detemine if the UUID already exists
@@ -74,6 +87,12 @@ func (s *SQLCommon) UpsertGroup(ctx context.Context, group *fftypes.Group, allow
 				Set("ledger", group.Ledger).
 				Set("hash", group.Hash).
 				Set("created", group.Created).
+				Set("schema_name", schema.Name).
+				Set("schema_version", schema.Version).
+				Set("description", group.Description).
+				Set("nonce", group.Nonce).
+				Set("acl", group.ACL).
+				Set("previous", group.Previous).
 				Where(sq.Eq{"hash": group.Hash}),
 			func() {
 				s.callbacks.HashCollectionNSEvent(database.CollectionGroups, fftypes.ChangeEventTypeUpdated, group.Namespace, group.Hash)
@@ -92,6 +111,12 @@ func (s *SQLCommon) UpsertGroup(ctx context.Context, group *fftypes.Group, allow
 					group.Ledger,
 					group.Hash,
 					group.Created,
+					schema.Name,
+					schema.Version,
+					group.Description,
+					group.Nonce,
+					group.ACL,
+					group.Previous,
 				),
 			func() {
 				s.callbacks.HashCollectionNSEvent(database.CollectionGroups, fftypes.ChangeEventTypeCreated, group.Namespace, group.Hash)
@@ -139,12 +164,14 @@ func (s *SQLCommon) updateMembers(ctx context.Context, tx *txWrapper, group *fft
 					"identity",
 					"node_id",
 					"idx",
+					"alias",
 				).
 				Values(
 					group.Hash,
 					requiredMember.Identity,
 					requiredMember.Node,
 					requiredIdx,
+					requiredMember.Alias,
 				),
 			nil, // no db change event for this sub update
 		); err != nil {
@@ -171,6 +198,7 @@ func (s *SQLCommon) loadMembers(ctx context.Context, groups []*fftypes.Group) er
 			"identity",
 			"node_id",
 			"idx",
+			"alias",
 		).
 			From("members").
 			Where(sq.Eq{"group_hash": groupIDs}).
@@ -185,7 +213,7 @@ func (s *SQLCommon) loadMembers(ctx context.Context, groups []*fftypes.Group) er
 		var groupID fftypes.Bytes32
 		member := &fftypes.Member{}
 		var idx int
-		if err = members.Scan(&groupID, &member.Identity, &member.Node, &idx); err != nil {
+		if err = members.Scan(&groupID, &member.Identity, &member.Node, &idx, &member.Alias); err != nil {
 			return i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "members")
 		}
 		for _, g := range groups {
@@ -205,7 +233,8 @@ func (s *SQLCommon) loadMembers(ctx context.Context, groups []*fftypes.Group) er
 }
 
 func (s *SQLCommon) groupResult(ctx context.Context, row *sql.Rows) (*fftypes.Group, error) {
-	var group fftypes.Group
+	group := fftypes.Group{}
+	group.Schema = &fftypes.DatatypeRef{}
 	err := row.Scan(
 		&group.Message,
 		&group.Namespace,
@@ -213,10 +242,19 @@ func (s *SQLCommon) groupResult(ctx context.Context, row *sql.Rows) (*fftypes.Gr
 		&group.Ledger,
 		&group.Hash,
 		&group.Created,
+		&group.Schema.Name,
+		&group.Schema.Version,
+		&group.Description,
+		&group.Nonce,
+		&group.ACL,
+		&group.Previous,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "groups")
 	}
+	if group.Schema.Name == "" && group.Schema.Version == "" {
+		group.Schema = nil
+	}
 	return &group, nil
 }
 
@@ -45,7 +45,7 @@ func TestUpsertGroupE2EWithDB(t *testing.T) {
 			Name:      "group1",
 			Namespace: "ns1",
 			Members: fftypes.Members{
-				{Identity: "0x12345", Node: fftypes.NewUUID()},
+				{Identity: "0x12345", Node: fftypes.NewUUID(), Alias: "alice"},
 				{Identity: "0x23456", Node: fftypes.NewUUID()},
 			},
 		},
@@ -68,15 +68,24 @@ func TestUpsertGroupE2EWithDB(t *testing.T) {
 
 	// Update the group (this is testing what's possible at the database layer,
 	// and does not account for the verification that happens at the higher level)
+	previous := fftypes.NewRandB32()
 	groupUpdated := &fftypes.Group{
 		GroupIdentity: fftypes.GroupIdentity{
-			Name:      "group1",
-			Namespace: "ns1",
+			Name:        "group1",
+			Description: "Supply chain partners",
+			Namespace:   "ns1",
 			Members: fftypes.Members{
 				{Identity: "0x12345", Node: fftypes.NewUUID()},
 				group.Members[0],
 			},
-			Ledger: fftypes.NewUUID(),
+			Ledger:   fftypes.NewUUID(),
+			ACL:      fftypes.GroupACL{"0x12345"},
+			Previous: previous,
+			Nonce:    5,
+			Schema: &fftypes.DatatypeRef{
+				Name:    "customer",
+				Version: "0.0.1",
+			},
 		},
 		Created: fftypes.Now(),
 		Message: fftypes.NewUUID(),
@@ -100,7 +109,14 @@ func TestUpsertGroupE2EWithDB(t *testing.T) {
 		fb.Eq("namespace", groupUpdated.Namespace),
 		fb.Eq("message", groupUpdated.Message),
 		fb.Eq("ledger", groupUpdated.Ledger),
+		fb.Eq("schema.name", "customer"),
+		fb.Eq("schema.version", "0.0.1"),
+		fb.Contains("description", "chain"),
 		fb.Gt("created", "0"),
+		fb.Eq("name", "group1"),
+		fb.Eq("nonce", 5),
+		fb.Eq("previous", previous),
+		fb.Contains("acl", fftypes.GroupACLMatch("0x12345")),
 	)
 	groups, _, err := s.GetGroups(ctx, filter)
 	assert.NoError(t, err)
@@ -324,7 +340,7 @@ func TestGetGroupByIDLoadMembersFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	groupID := fftypes.NewRandB32()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(groupColumns).
-		AddRow(nil, "ns1", "name1", fftypes.NewUUID(), fftypes.NewRandB32(), fftypes.Now()))
+		AddRow(nil, "ns1", "name1", fftypes.NewUUID(), fftypes.NewRandB32(), fftypes.Now(), "", "", "", 0, nil, nil))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	_, err := s.GetGroupByHash(context.Background(), groupID)
 	assert.Regexp(t, "FF10115", err)
@@ -359,7 +375,7 @@ func TestGetGroupsReadGroupFail(t *testing.T) {
 func TestGetGroupsLoadMembersFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(groupColumns).
-		AddRow(nil, "ns1", "group1", fftypes.NewUUID(), fftypes.NewRandB32(), fftypes.Now()))
+		AddRow(nil, "ns1", "group1", fftypes.NewUUID(), fftypes.NewRandB32(), fftypes.Now(), "", "", "", 0, nil, nil))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	f := database.GroupQueryFactory.NewFilter(context.Background()).Gt("created", "0")
 	_, _, err := s.GetGroups(context.Background(), f)
@@ -48,12 +48,32 @@ var (
 		"tx_type",
 		"batch_id",
 		"local",
+		"read_at",
+		"sensitivity",
+		"thread_id",
+		"pinned",
+		"datadigest",
+		"deadline",
+		"error",
+		"labels",
+		"forwarded",
+		"forwarded_from",
+		"content_type",
+		"ephemeral",
+		"version",
+		"created_by",
+		"extra_groups",
 	}
 	msgFilterFieldMap = map[string]string{
-		"type":   "mtype",
-		"txtype": "tx_type",
-		"batch":  "batch_id",
-		"group":  "group_hash",
+		"type":          "mtype",
+		"txtype":        "tx_type",
+		"batch":         "batch_id",
+		"group":         "group_hash",
+		"readat":        "read_at",
+		"thread":        "thread_id",
+		"forwardedfrom": "forwarded_from",
+		"contenttype":   "content_type",
+		"createdby":     "created_by",
 	}
 )
 
@@ -119,7 +139,20 @@ func (s *SQLCommon) upsertMessageCommon(ctx context.Context, message *fftypes.Me
 				Set("confirmed", message.Confirmed).
 				Set("tx_type", message.Header.TxType).
 				Set("batch_id", message.BatchID).
-				// Intentionally does NOT include the "local" column
+				Set("sensitivity", message.Header.Sensitivity).
+				Set("thread_id", message.Header.Thread).
+				Set("pinned", message.Pinned).
+				Set("datadigest", message.DataDigest).
+				Set("deadline", message.Deadline).
+				Set("error", message.Error).
+				Set("labels", message.Labels).
+				Set("forwarded", message.Header.Forwarded).
+				Set("forwarded_from", message.Header.ForwardedFrom).
+				Set("content_type", message.Header.ContentType).
+				Set("ephemeral", message.Ephemeral).
+				Set("version", message.Header.Version).
+				Set("extra_groups", message.Groups).
+				// Intentionally does NOT include the "local", "created_by" or "read_at" columns
 				Where(sq.Eq{"id": message.Header.ID}),
 			func() {
 				s.callbacks.OrderedUUIDCollectionNSEvent(database.CollectionMessages, fftypes.ChangeEventTypeUpdated, message.Header.Namespace, message.Header.ID, message.Sequence)
@@ -128,6 +161,11 @@ func (s *SQLCommon) upsertMessageCommon(ctx context.Context, message *fftypes.Me
 			return err
 		}
 	} else {
+		// The initiator is only meaningful on the node it was submitted to, so it is not taken from received messages
+		createdBy := ""
+		if isLocal {
+			createdBy = message.CreatedBy
+		}
 		message.Sequence, err = s.insertTx(ctx, tx,
 			sq.Insert("messages").
 				Columns(msgColumns...).
@@ -150,6 +188,21 @@ func (s *SQLCommon) upsertMessageCommon(ctx context.Context, message *fftypes.Me
 					message.Header.TxType,
 					message.BatchID,
 					isLocal,
+					message.ReadAt,
+					message.Header.Sensitivity,
+					message.Header.Thread,
+					message.Pinned,
+					message.DataDigest,
+					message.Deadline,
+					message.Error,
+					message.Labels,
+					message.Header.Forwarded,
+					message.Header.ForwardedFrom,
+					message.Header.ContentType,
+					message.Ephemeral,
+					message.Header.Version,
+					createdBy,
+					message.Groups,
 				),
 			func() {
 				s.callbacks.OrderedUUIDCollectionNSEvent(database.CollectionMessages, fftypes.ChangeEventTypeCreated, message.Header.Namespace, message.Header.ID, message.Sequence)
@@ -197,12 +250,14 @@ func (s *SQLCommon) updateMessageDataRefs(ctx context.Context, tx *txWrapper, me
 					"data_id",
 					"data_hash",
 					"data_idx",
+					"optional",
 				).
 				Values(
 					message.Header.ID,
 					msgDataRef.ID,
 					msgDataRef.Hash,
 					msgDataRefIDx,
+					msgDataRef.Optional,
 				),
 			nil, // no change event
 		); err != nil {
@@ -234,6 +289,7 @@ func (s *SQLCommon) loadDataRefs(ctx context.Context, msgs []*fftypes.Message) e
 			"data_id",
 			"data_hash",
 			"data_idx",
+			"optional",
 		).
 			From("messages_data").
 			Where(sq.Eq{"message_id": msgIDs}).
@@ -249,14 +305,16 @@ func (s *SQLCommon) loadDataRefs(ctx context.Context, msgs []*fftypes.Message) e
 		var dataID fftypes.UUID
 		var dataHash fftypes.Bytes32
 		var dataIDx int
-		if err = existingRefs.Scan(&msgID, &dataID, &dataHash, &dataIDx); err != nil {
+		var optional bool
+		if err = existingRefs.Scan(&msgID, &dataID, &dataHash, &dataIDx, &optional); err != nil {
 			return i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "messages_data")
 		}
 		for _, m := range msgs {
 			if *m.Header.ID == msgID {
 				m.Data = append(m.Data, &fftypes.DataRef{
-					ID:   &dataID,
-					Hash: &dataHash,
+					ID:       &dataID,
+					Hash:     &dataHash,
+					Optional: optional,
 				})
 			}
 		}
@@ -292,6 +350,21 @@ func (s *SQLCommon) msgResult(ctx context.Context, row *sql.Rows) (*fftypes.Mess
 		&msg.Header.TxType,
 		&msg.BatchID,
 		&msg.Local,
+		&msg.ReadAt,
+		&msg.Header.Sensitivity,
+		&msg.Header.Thread,
+		&msg.Pinned,
+		&msg.DataDigest,
+		&msg.Deadline,
+		&msg.Error,
+		&msg.Labels,
+		&msg.Header.Forwarded,
+		&msg.Header.ForwardedFrom,
+		&msg.Header.ContentType,
+		&msg.Ephemeral,
+		&msg.Header.Version,
+		&msg.CreatedBy,
+		&msg.Groups,
 		// Must be added to the list of columns in all selects
 		&msg.Sequence,
 	)
@@ -333,6 +406,41 @@ func (s *SQLCommon) GetMessageByID(ctx context.Context, id *fftypes.UUID) (messa
 	return msg, nil
 }
 
+func (s *SQLCommon) DeleteMessage(ctx context.Context, id *fftypes.UUID) (err error) {
+
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	msg, err := s.GetMessageByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if msg != nil {
+		// The data itself is left in place, as it might be referred to by other messages
+		if err = s.deleteTx(ctx, tx,
+			sq.Delete("messages_data").
+				Where(sq.Eq{"message_id": id}),
+			nil, // no change event
+		); err != nil && err != database.DeleteRecordNotFound {
+			return err
+		}
+		if err = s.deleteTx(ctx, tx,
+			sq.Delete("messages").
+				Where(sq.Eq{"id": id}),
+			func() {
+				s.callbacks.OrderedUUIDCollectionNSEvent(database.CollectionMessages, fftypes.ChangeEventTypeDeleted, msg.Header.Namespace, msg.Header.ID, msg.Sequence)
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
+
 func (s *SQLCommon) getMessagesQuery(ctx context.Context, query sq.SelectBuilder, fop sq.Sqlizer, fi *database.FilterInfo, allowCount bool) (message []*fftypes.Message, fr *database.FilterResult, err error) {
 	if fi.Count && !allowCount {
 		return nil, nil, i18n.NewError(ctx, i18n.MsgFilterCountNotSupported)
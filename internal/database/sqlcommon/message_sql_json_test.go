@@ -0,0 +1,74 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build sqlite_json
+// +build sqlite_json
+
+package sqlcommon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMessageLabelsFilterE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionMessages, fftypes.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+
+	for _, labels := range []fftypes.Labels{
+		{"department": "finance", "region": "eu"},
+		{"department": "finance", "region": "us"},
+		{"department": "sales"},
+		nil,
+	} {
+		err := s.UpsertMessage(ctx, &fftypes.Message{
+			Header: fftypes.MessageHeader{
+				ID:        fftypes.NewUUID(),
+				Type:      fftypes.MessageTypeBroadcast,
+				Created:   fftypes.Now(),
+				DataHash:  fftypes.NewRandB32(),
+				Namespace: "ns1",
+			},
+			Hash:   fftypes.NewRandB32(),
+			Labels: labels,
+		}, false, false)
+		assert.NoError(t, err)
+	}
+
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	for i, test := range []struct {
+		filter   database.Filter
+		expected int
+	}{
+		{fb.JSONContains("labels", fftypes.JSONObject{"department": "finance"}), 2},
+		{fb.JSONContains("labels", fftypes.JSONObject{"department": "finance", "region": "eu"}), 1},
+		{fb.JSONContains("labels", fftypes.JSONObject{"department": "fin"}), 0},
+		{fb.JSONHasKey("labels", "region"), 2},
+		{fb.JSONHasKey("labels", "reg"), 0},
+	} {
+		msgs, _, err := s.GetMessages(ctx, test.filter)
+		assert.NoError(t, err)
+		assert.Len(t, msgs, test.expected, "filter %d", i)
+	}
+}
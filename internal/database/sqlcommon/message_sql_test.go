@@ -60,6 +60,8 @@ func TestUpsertE2EWithDB(t *testing.T) {
 		Hash:      fftypes.NewRandB32(),
 		Pending:   true,
 		Confirmed: nil,
+		CreatedBy: "org1",
+		ReadAt:    fftypes.Now(),
 		Data: []*fftypes.DataRef{
 			{ID: dataID1, Hash: rand1},
 			{ID: dataID2, Hash: rand2},
@@ -68,6 +70,7 @@ func TestUpsertE2EWithDB(t *testing.T) {
 
 	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionMessages, fftypes.ChangeEventTypeCreated, "ns12345", msgID, mock.Anything).Return()
 	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionMessages, fftypes.ChangeEventTypeUpdated, "ns12345", msgID, mock.Anything).Return()
+	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionMessages, fftypes.ChangeEventTypeDeleted, "ns12345", msgID, mock.Anything).Return()
 
 	err := s.InsertMessageLocal(ctx, msg)
 	assert.NoError(t, err)
@@ -76,6 +79,7 @@ func TestUpsertE2EWithDB(t *testing.T) {
 	msgRead, err := s.GetMessageByID(ctx, msgID)
 	assert.NoError(t, err)
 	assert.True(t, msgRead.Local)
+	assert.Equal(t, msg.ReadAt.String(), msgRead.ReadAt.String())
 	// The generated sequence will have been added
 	msg.Sequence = msgRead.Sequence
 	assert.NoError(t, err)
@@ -90,31 +94,48 @@ func TestUpsertE2EWithDB(t *testing.T) {
 	cid := fftypes.NewUUID()
 	gid := fftypes.NewRandB32()
 	bid := fftypes.NewUUID()
+	threadID := fftypes.NewUUID()
+	forwardedFrom := fftypes.NewUUID()
 	msgUpdated := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:        msgID,
-			CID:       cid,
-			Type:      fftypes.MessageTypeBroadcast,
-			Author:    "0x12345",
-			Created:   fftypes.Now(),
-			Namespace: "ns12345",
-			Topics:    []string{"topic1", "topic2"},
-			Tag:       "tag1",
-			Group:     gid,
-			DataHash:  fftypes.NewRandB32(),
-			TxType:    fftypes.TransactionTypeBatchPin,
+			ID:            msgID,
+			CID:           cid,
+			Type:          fftypes.MessageTypeBroadcast,
+			Author:        "0x12345",
+			Created:       fftypes.Now(),
+			Namespace:     "ns12345",
+			Topics:        []string{"topic1", "topic2"},
+			Tag:           "tag1",
+			Group:         gid,
+			DataHash:      fftypes.NewRandB32(),
+			TxType:        fftypes.TransactionTypeBatchPin,
+			Sensitivity:   fftypes.DataSensitivityConfidential,
+			Thread:        threadID,
+			Forwarded:     true,
+			ForwardedFrom: forwardedFrom,
+			ContentType:   "application/xml",
+			Version:       fftypes.MessageVersion,
 		},
-		Hash:      fftypes.NewRandB32(),
-		Pins:      []string{fftypes.NewRandB32().String(), fftypes.NewRandB32().String()},
-		Rejected:  true,
-		Pending:   false,
-		Confirmed: fftypes.Now(),
-		BatchID:   bid,
+		Hash:       fftypes.NewRandB32(),
+		DataDigest: fftypes.NewRandB32(),
+		Pins:       []string{fftypes.NewRandB32().String(), fftypes.NewRandB32().String()},
+		Rejected:   true,
+		Pending:    false,
+		Pinned:     true,
+		Ephemeral:  true,
+		Confirmed:  fftypes.Now(),
+		ReadAt:     nil, // must be ignored
+		Deadline:   fftypes.Now(),
+		Error:      "timeout",
+		Labels:     fftypes.Labels{"department": "finance", "region": "eu"},
+		Groups:     fftypes.GroupRefs{{Hash: fftypes.NewRandB32()}},
+		BatchID:    bid,
 		Data: []*fftypes.DataRef{
 			{ID: dataID2, Hash: rand2},
-			{ID: dataID3, Hash: rand3},
+			{ID: dataID3, Hash: rand3, Optional: true},
 		},
-		Local: false, // must be ignored
+		Local:     false,  // must be ignored
+		CreatedBy: "org2", // must be ignored
 	}
 
 	// Ensure hash change rejected
@@ -127,9 +148,12 @@ func TestUpsertE2EWithDB(t *testing.T) {
 	// Check we get the exact same message back - note the removal of one of the data elements
 	msgRead, err = s.GetMessageByID(ctx, msgID)
 	assert.True(t, msgRead.Local) // Must not have been overridden with the update
+	assert.Equal(t, msg.ReadAt.String(), msgRead.ReadAt.String())
+	assert.Equal(t, "timeout", msgRead.Error)
 	// The generated sequence will have been added
 	msgUpdated.Sequence = msgRead.Sequence
-	msgUpdated.Local = true // retained
+	msgUpdated.Local = true       // retained
+	msgUpdated.CreatedBy = "org1" // retained
 	assert.NoError(t, err)
 	msgJson, _ = json.Marshal(&msgUpdated)
 	msgReadJson, _ = json.Marshal(&msgRead)
@@ -148,6 +172,17 @@ func TestUpsertE2EWithDB(t *testing.T) {
 		fb.Eq("local", true),
 		fb.Gt("created", "0"),
 		fb.Gt("confirmed", "0"),
+		fb.Gt("readat", "0"),
+		fb.Eq("sensitivity", fftypes.DataSensitivityConfidential),
+		fb.Eq("thread", threadID),
+		fb.Eq("pinned", true),
+		fb.Gt("deadline", "0"),
+		fb.Eq("error", "timeout"),
+		fb.Eq("forwarded", true),
+		fb.Eq("forwardedfrom", forwardedFrom),
+		fb.Eq("contenttype", "application/xml"),
+		fb.Eq("ephemeral", true),
+		fb.Eq("createdby", "org1"),
 	)
 	msgs, res, err := s.GetMessages(ctx, filter.Count(true))
 	assert.NoError(t, err)
@@ -202,9 +237,59 @@ func TestUpsertE2EWithDB(t *testing.T) {
 	assert.Equal(t, 1, len(msgs))
 	assert.Equal(t, *bid2, *msgs[0].BatchID)
 
+	// Delete the message, and check the data references go with it
+	err = s.DeleteMessage(ctx, msgID)
+	assert.NoError(t, err)
+	msgRead, err = s.GetMessageByID(ctx, msgID)
+	assert.NoError(t, err)
+	assert.Nil(t, msgRead)
+	msgs, _, err = s.GetMessagesForData(ctx, dataID3, fb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	// Deleting a message that does not exist is a no-op
+	err = s.DeleteMessage(ctx, msgID)
+	assert.NoError(t, err)
+
 	s.callbacks.AssertExpectations(t)
 }
 
+func TestGetMessagesByIDListWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionMessages, fftypes.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+
+	msgIDs := []*fftypes.UUID{fftypes.NewUUID(), fftypes.NewUUID()}
+	for _, msgID := range msgIDs {
+		err := s.UpsertMessage(ctx, &fftypes.Message{
+			Header: fftypes.MessageHeader{
+				ID:        msgID,
+				Type:      fftypes.MessageTypeBroadcast,
+				Namespace: "ns1",
+				Created:   fftypes.Now(),
+				DataHash:  fftypes.NewRandB32(),
+			},
+			Hash: fftypes.NewRandB32(),
+		}, false, false)
+		assert.NoError(t, err)
+	}
+
+	// Query for both messages, and one that does not exist - getting back the two that do
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	msgs, _, err := s.GetMessages(ctx, fb.And(
+		fb.Eq("namespace", "ns1"),
+		fb.In("id", []driver.Value{msgIDs[0], fftypes.NewUUID(), msgIDs[1]}),
+	))
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 2)
+	for _, msg := range msgs {
+		assert.Contains(t, msgIDs, msg.Header.ID)
+	}
+}
+
 func TestUpsertMessageFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
@@ -400,13 +485,66 @@ func TestGetMessageByIDLoadRefsFail(t *testing.T) {
 	cols := append([]string{}, msgColumns...)
 	cols = append(cols, "id()")
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).
-		AddRow(msgID.String(), nil, fftypes.MessageTypeBroadcast, "0x12345", 0, "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), true, true, 0, "pin", nil, false, 0))
+		AddRow(msgID.String(), nil, fftypes.MessageTypeBroadcast, "0x12345", 0, "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), true, true, 0, "pin", nil, false, nil, "", nil, true, nil, nil, "", nil, false, nil, "", false, 0, "", nil, 0))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	_, err := s.GetMessageByID(context.Background(), msgID)
 	assert.Regexp(t, "FF10115", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestDeleteMessageFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteMessage(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10114", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMessageFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteMessage(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMessageFailDeleteRefs(t *testing.T) {
+	s, mock := newMockProvider().init()
+	msgID := fftypes.NewUUID()
+	b32 := fftypes.NewRandB32()
+	cols := append([]string{}, msgColumns...)
+	cols = append(cols, "id()")
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).
+		AddRow(msgID.String(), nil, fftypes.MessageTypeBroadcast, "0x12345", 0, "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), true, true, 0, "pin", nil, false, nil, "", nil, true, nil, nil, "", nil, false, nil, "", false, 0, "", nil, 0))
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteMessage(context.Background(), msgID)
+	assert.Regexp(t, "FF10118", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMessageFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	msgID := fftypes.NewUUID()
+	b32 := fftypes.NewRandB32()
+	cols := append([]string{}, msgColumns...)
+	cols = append(cols, "id()")
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).
+		AddRow(msgID.String(), nil, fftypes.MessageTypeBroadcast, "0x12345", 0, "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), true, true, 0, "pin", nil, false, nil, "", nil, true, nil, nil, "", nil, false, nil, "", false, 0, "", nil, 0))
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("DELETE .*").WillReturnResult(driver.RowsAffected(1))
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteMessage(context.Background(), msgID)
+	assert.Regexp(t, "FF10118", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetMessagesBuildQueryFail(t *testing.T) {
 	s, _ := newMockProvider().init()
 	f := database.MessageQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
@@ -447,7 +585,7 @@ func TestGetMessagesLoadRefsFail(t *testing.T) {
 	cols := append([]string{}, msgColumns...)
 	cols = append(cols, "id()")
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).
-		AddRow(msgID.String(), nil, fftypes.MessageTypeBroadcast, "0x12345", 0, "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), true, true, 0, "pin", nil, false, 0))
+		AddRow(msgID.String(), nil, fftypes.MessageTypeBroadcast, "0x12345", 0, "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), true, true, 0, "pin", nil, false, nil, "", nil, true, nil, nil, "", nil, false, nil, "", false, 0, "", nil, 0))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	f := database.MessageQueryFactory.NewFilter(context.Background()).Gt("confirmed", "0")
 	_, _, err := s.GetMessages(context.Background(), f)
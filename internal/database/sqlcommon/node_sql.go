@@ -37,7 +37,9 @@ var (
 		"description",
 		"dx_peer",
 		"dx_endpoint",
+		"public_key",
 		"created",
+		"heartbeat_interval",
 	}
 	nodeFilterFieldMap = map[string]string{
 		"message":     "message_id",
@@ -94,7 +96,9 @@ func (s *SQLCommon) UpsertNode(ctx context.Context, node *fftypes.Node, allowExi
 				Set("description", node.Description).
 				Set("dx_peer", node.DX.Peer).
 				Set("dx_endpoint", node.DX.Endpoint).
+				Set("public_key", node.PublicKey).
 				Set("created", node.Created).
+				Set("heartbeat_interval", node.HeartbeatInterval).
 				Where(sq.Eq{"id": node.ID}),
 			func() {
 				s.callbacks.UUIDCollectionEvent(database.CollectionNodes, fftypes.ChangeEventTypeUpdated, node.ID)
@@ -114,7 +118,9 @@ func (s *SQLCommon) UpsertNode(ctx context.Context, node *fftypes.Node, allowExi
 					node.Description,
 					node.DX.Peer,
 					node.DX.Endpoint,
+					node.PublicKey,
 					node.Created,
+					node.HeartbeatInterval,
 				),
 			func() {
 				s.callbacks.UUIDCollectionEvent(database.CollectionNodes, fftypes.ChangeEventTypeCreated, node.ID)
@@ -137,7 +143,9 @@ func (s *SQLCommon) nodeResult(ctx context.Context, row *sql.Rows) (*fftypes.Nod
 		&node.Description,
 		&node.DX.Peer,
 		&node.DX.Endpoint,
+		&node.PublicKey,
 		&node.Created,
+		&node.HeartbeatInterval,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "nodes")
@@ -225,3 +233,29 @@ func (s *SQLCommon) UpdateNode(ctx context.Context, id *fftypes.UUID, update dat
 
 	return s.commitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) GetGhostNodes(ctx context.Context) (ids []*fftypes.UUID, err error) {
+
+	rows, _, err := s.query(ctx,
+		sq.Select("m.node_id").
+			Distinct().
+			From("members AS m").
+			LeftJoin("nodes AS n ON n.id = m.node_id").
+			Where(sq.Eq{"n.id": nil}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids = []*fftypes.UUID{}
+	for rows.Next() {
+		var id fftypes.UUID
+		if err = rows.Scan(&id); err != nil {
+			return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "members")
+		}
+		ids = append(ids, &id)
+	}
+
+	return ids, nil
+}
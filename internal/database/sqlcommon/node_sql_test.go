@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/hyperledger/firefly/internal/log"
@@ -71,6 +72,7 @@ func TestNodesE2EWithDB(t *testing.T) {
 
 	// Update the node (this is testing what's possible at the database layer,
 	// and does not account for the verification that happens at the higher level)
+	heartbeatInterval := fftypes.FFDuration(30 * time.Second)
 	nodeUpdated := &fftypes.Node{
 		ID:          nil, // as long as we don't specify one we're fine
 		Message:     fftypes.NewUUID(),
@@ -81,7 +83,9 @@ func TestNodesE2EWithDB(t *testing.T) {
 			Peer:     "peer1",
 			Endpoint: fftypes.JSONObject{"some": "info"},
 		},
-		Created: fftypes.Now(),
+		PublicKey:         "-----BEGIN PUBLIC KEY-----\n...\n-----END PUBLIC KEY-----\n",
+		HeartbeatInterval: &heartbeatInterval,
+		Created:           fftypes.Now(),
 	}
 	err = s.UpsertNode(context.Background(), nodeUpdated, true)
 	assert.NoError(t, err)
@@ -251,3 +255,57 @@ func TestNodeUpdateFail(t *testing.T) {
 	err := s.UpdateNode(context.Background(), fftypes.NewUUID(), u)
 	assert.Regexp(t, "FF10117", err)
 }
+
+func TestGetGhostNodesE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Register one node, and create a group that references it plus a ghost node
+	node := &fftypes.Node{
+		ID:      fftypes.NewUUID(),
+		Message: fftypes.NewUUID(),
+		Owner:   "0x12345",
+		Name:    "node1",
+		Created: fftypes.Now(),
+	}
+	ghostNodeID := fftypes.NewUUID()
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "0x12345", Node: node.ID},
+				{Identity: "0x23456", Node: ghostNodeID},
+			},
+		},
+		Hash:    fftypes.NewRandB32(),
+		Created: fftypes.Now(),
+	}
+	s.callbacks.On("UUIDCollectionEvent", database.CollectionNodes, fftypes.ChangeEventTypeCreated, node.ID).Return()
+	s.callbacks.On("HashCollectionNSEvent", database.CollectionGroups, fftypes.ChangeEventTypeCreated, "ns1", group.Hash).Return()
+	err := s.UpsertNode(ctx, node, false)
+	assert.NoError(t, err)
+	err = s.UpsertGroup(ctx, group, false)
+	assert.NoError(t, err)
+
+	ghostNodes, err := s.GetGhostNodes(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*fftypes.UUID{ghostNodeID}, ghostNodes)
+}
+
+func TestGetGhostNodesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetGhostNodes(context.Background())
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetGhostNodesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"node_id"}).AddRow("!not a uuid"))
+	_, err := s.GetGhostNodes(context.Background())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -42,12 +42,22 @@ var (
 		"error",
 		"input",
 		"output",
+		"deleted",
+		"timeout",
+		"priority",
+		"created_by",
 	}
 	opFilterFieldMap = map[string]string{
 		"tx":        "tx_id",
 		"type":      "optype",
 		"status":    "opstatus",
 		"backendid": "backend_id",
+		"createdby": "created_by",
+	}
+	opHistoryColumns = []string{
+		"opstatus",
+		"timestamp",
+		"error",
 	}
 )
 
@@ -60,10 +70,11 @@ func (s *SQLCommon) UpsertOperation(ctx context.Context, operation *fftypes.Oper
 	defer s.rollbackTx(ctx, tx, autoCommit)
 
 	existing := false
+	var existingStatus fftypes.OpStatus
 	if allowExisting {
 		// Do a select within the transaction to detemine if the UUID already exists
 		opRows, _, err := s.queryTx(ctx, tx,
-			sq.Select("id").
+			sq.Select("opstatus").
 				From("operations").
 				Where(sq.Eq{"id": operation.ID}),
 		)
@@ -72,7 +83,13 @@ func (s *SQLCommon) UpsertOperation(ctx context.Context, operation *fftypes.Oper
 		}
 
 		existing = opRows.Next()
+		if existing {
+			err = opRows.Scan(&existingStatus)
+		}
 		opRows.Close()
+		if err != nil {
+			return i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "operations")
+		}
 	}
 
 	if existing {
@@ -91,6 +108,10 @@ func (s *SQLCommon) UpsertOperation(ctx context.Context, operation *fftypes.Oper
 				Set("error", operation.Error).
 				Set("input", operation.Input).
 				Set("output", operation.Output).
+				Set("deleted", operation.DeletedAt).
+				Set("timeout", operation.Timeout).
+				Set("priority", operation.Priority).
+				Set("created_by", operation.CreatedBy).
 				Where(sq.Eq{"id": operation.ID}),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionOperations, fftypes.ChangeEventTypeUpdated, operation.Namespace, operation.ID)
@@ -116,6 +137,10 @@ func (s *SQLCommon) UpsertOperation(ctx context.Context, operation *fftypes.Oper
 					operation.Error,
 					operation.Input,
 					operation.Output,
+					operation.DeletedAt,
+					operation.Timeout,
+					operation.Priority,
+					operation.CreatedBy,
 				),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionOperations, fftypes.ChangeEventTypeCreated, operation.Namespace, operation.ID)
@@ -125,9 +150,32 @@ func (s *SQLCommon) UpsertOperation(ctx context.Context, operation *fftypes.Oper
 		}
 	}
 
+	if !existing || existingStatus != operation.Status {
+		timestamp := operation.Created
+		if existing {
+			timestamp = nil // now
+		}
+		if err = s.insertOpTransition(ctx, tx, operation.ID, operation.Status, timestamp, operation.Error); err != nil {
+			return err
+		}
+	}
+
 	return s.commitTx(ctx, tx, autoCommit)
 }
 
+func (s *SQLCommon) insertOpTransition(ctx context.Context, tx *txWrapper, id *fftypes.UUID, status fftypes.OpStatus, timestamp *fftypes.FFTime, errMsg string) error {
+	if timestamp == nil {
+		timestamp = fftypes.Now()
+	}
+	_, err := s.insertTx(ctx, tx,
+		sq.Insert("operation_history").
+			Columns(append([]string{"op_id"}, opHistoryColumns...)...).
+			Values(id, string(status), timestamp, errMsg),
+		nil, // no change events for operation history
+	)
+	return err
+}
+
 func (s *SQLCommon) opResult(ctx context.Context, row *sql.Rows) (*fftypes.Operation, error) {
 	var op fftypes.Operation
 	err := row.Scan(
@@ -144,6 +192,10 @@ func (s *SQLCommon) opResult(ctx context.Context, row *sql.Rows) (*fftypes.Opera
 		&op.Error,
 		&op.Input,
 		&op.Output,
+		&op.DeletedAt,
+		&op.Timeout,
+		&op.Priority,
+		&op.CreatedBy,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "operations")
@@ -213,7 +265,40 @@ func (s *SQLCommon) UpdateOperation(ctx context.Context, id *fftypes.UUID, updat
 	if err != nil {
 		return err
 	}
-	query = query.Set("updated", fftypes.Now())
+
+	// Determine if this update transitions the status of the operation, so we can record it in the history
+	var newStatus, newError string
+	ui, _ := update.Finalize() // errors would have been returned by buildUpdate
+	for _, so := range ui.SetOperations {
+		v, _ := so.Value.Value()
+		switch so.Field {
+		case "status":
+			newStatus, _ = v.(string)
+		case "error":
+			newError, _ = v.(string)
+		}
+	}
+	var existingStatus string
+	if newStatus != "" {
+		opRows, _, err := s.queryTx(ctx, tx,
+			sq.Select("opstatus").
+				From("operations").
+				Where(sq.Eq{"id": id}),
+		)
+		if err != nil {
+			return err
+		}
+		if opRows.Next() {
+			err = opRows.Scan(&existingStatus)
+		}
+		opRows.Close()
+		if err != nil {
+			return i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "operations")
+		}
+	}
+
+	now := fftypes.Now()
+	query = query.Set("updated", now)
 	query = query.Where(sq.Eq{"id": id})
 
 	err = s.updateTx(ctx, tx, query, nil /* no change events for filter based updates */)
@@ -221,5 +306,110 @@ func (s *SQLCommon) UpdateOperation(ctx context.Context, id *fftypes.UUID, updat
 		return err
 	}
 
+	if existingStatus != "" && existingStatus != newStatus {
+		if err = s.insertOpTransition(ctx, tx, id, fftypes.OpStatus(newStatus), now, newError); err != nil {
+			return err
+		}
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) GetOperationHistory(ctx context.Context, id *fftypes.UUID) (history []*fftypes.OperationTransition, err error) {
+
+	rows, _, err := s.query(ctx,
+		sq.Select(opHistoryColumns...).
+			From("operation_history").
+			Where(sq.Eq{"op_id": id}).
+			OrderBy("seq"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history = []*fftypes.OperationTransition{}
+	for rows.Next() {
+		var transition fftypes.OperationTransition
+		if err = rows.Scan(&transition.Status, &transition.Timestamp, &transition.Error); err != nil {
+			return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "operation_history")
+		}
+		history = append(history, &transition)
+	}
+
+	return history, nil
+}
+
+func (s *SQLCommon) SoftDeleteOperation(ctx context.Context, id *fftypes.UUID) (err error) {
+
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	now := fftypes.Now()
+	err = s.updateTx(ctx, tx,
+		sq.Update("operations").
+			Set("deleted", now).
+			Set("updated", now).
+			Where(sq.Eq{"id": id}),
+		nil, /* no change events for soft deletes */
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) PurgeOperations(ctx context.Context, deletedBefore *fftypes.FFTime) (err error) {
+
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	err = s.deleteTx(ctx, tx, sq.Delete("operation_history").Where(
+		sq.Expr("op_id IN (SELECT id FROM operations WHERE deleted IS NOT NULL AND deleted < ?)", deletedBefore),
+	), nil /* no change events for purges */)
+	if err != nil && err != database.DeleteRecordNotFound {
+		return err
+	}
+
+	err = s.deleteTx(ctx, tx, sq.Delete("operations").Where(sq.And{
+		sq.NotEq{"deleted": nil},
+		sq.Lt{"deleted": deletedBefore},
+	}), nil /* no change events for purges */)
+	if err != nil && err != database.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) DeleteOperation(ctx context.Context, id *fftypes.UUID) (err error) {
+
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	err = s.deleteTx(ctx, tx, sq.Delete("operation_history").Where(sq.Eq{
+		"op_id": id,
+	}), nil /* no change events for purges */)
+	if err != nil && err != database.DeleteRecordNotFound {
+		return err
+	}
+
+	err = s.deleteTx(ctx, tx, sq.Delete("operations").Where(sq.Eq{
+		"id": id,
+	}), nil /* no change events for purges */)
+	if err != nil {
+		return err
+	}
+
 	return s.commitTx(ctx, tx, autoCommit)
 }
@@ -21,11 +21,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestOperationE2EWithDB(t *testing.T) {
@@ -73,6 +75,8 @@ func TestOperationE2EWithDB(t *testing.T) {
 		Output:      fftypes.JSONObject{"some": "output-info"},
 		Created:     fftypes.Now(),
 		Updated:     fftypes.Now(),
+		Priority:    5,
+		CreatedBy:   "org1",
 	}
 	err = s.UpsertOperation(context.Background(), operationUpdated, true)
 	assert.NoError(t, err)
@@ -95,6 +99,8 @@ func TestOperationE2EWithDB(t *testing.T) {
 		fb.Eq("error", operationUpdated.Error),
 		fb.Eq("plugin", operationUpdated.Plugin),
 		fb.Eq("backendid", operationUpdated.BackendID),
+		fb.Eq("priority", int(operationUpdated.Priority)),
+		fb.Eq("createdby", operationUpdated.CreatedBy),
 		fb.Gt("created", 0),
 		fb.Gt("updated", 0),
 	)
@@ -134,9 +140,97 @@ func TestOperationE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(operations))
 
+	// Each status change was recorded in the history
+	history, err := s.GetOperationHistory(ctx, operationID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(history))
+	assert.Equal(t, fftypes.OpStatusPending, history[0].Status)
+	assert.Equal(t, operation.Created.String(), history[0].Timestamp.String())
+	assert.Equal(t, fftypes.OpStatusFailed, history[1].Status)
+	assert.Equal(t, "pop", history[1].Error)
+	assert.Equal(t, fftypes.OpStatusSucceeded, history[2].Status)
+	assert.Empty(t, history[2].Error)
+
+	// Soft delete, and check it is excluded when filtering on deleted
+	err = s.SoftDeleteOperation(ctx, operationUpdated.ID)
+	assert.NoError(t, err)
+	operationRead, err = s.GetOperationByID(ctx, operationID)
+	assert.NoError(t, err)
+	assert.NotNil(t, operationRead.DeletedAt)
+	filter = fb.And(
+		fb.Eq("id", operationUpdated.ID.String()),
+		fb.Eq("deleted", nil),
+	)
+	operations, _, err = s.GetOperations(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(operations))
+
+	// Purge with a cutoff before the deletion leaves it in place
+	err = s.PurgeOperations(ctx, operationRead.DeletedAt.Add(-1*time.Second))
+	assert.NoError(t, err)
+	operationRead, err = s.GetOperationByID(ctx, operationID)
+	assert.NoError(t, err)
+	assert.NotNil(t, operationRead)
+
+	// Purge with a cutoff after the deletion removes it
+	err = s.PurgeOperations(ctx, operationRead.DeletedAt.Add(1*time.Second))
+	assert.NoError(t, err)
+	operationRead, err = s.GetOperationByID(ctx, operationID)
+	assert.NoError(t, err)
+	assert.Nil(t, operationRead)
+	history, err = s.GetOperationHistory(ctx, operationID)
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+
 	s.callbacks.AssertExpectations(t)
 }
 
+func TestOperationHistoryRetryCycleWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	operation := &fftypes.Operation{
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Type:        fftypes.OpTypeDataExchangeBatchSend,
+		Transaction: fftypes.NewUUID(),
+		Status:      fftypes.OpStatusPending,
+		Created:     fftypes.Now(),
+	}
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, fftypes.ChangeEventTypeCreated, "ns1", operation.ID).Return()
+	err := s.UpsertOperation(ctx, operation, false)
+	assert.NoError(t, err)
+
+	// Fail, then retry
+	err = s.UpdateOperation(ctx, operation.ID, database.OperationQueryFactory.NewUpdate(ctx).
+		Set("status", fftypes.OpStatusFailed).
+		Set("error", "pop"))
+	assert.NoError(t, err)
+	err = s.UpdateOperation(ctx, operation.ID, database.OperationQueryFactory.NewUpdate(ctx).
+		Set("status", fftypes.OpStatusPending).
+		Set("error", ""))
+	assert.NoError(t, err)
+
+	// Updates that do not change the status are not recorded
+	err = s.UpdateOperation(ctx, operation.ID, database.OperationQueryFactory.NewUpdate(ctx).
+		Set("status", fftypes.OpStatusPending))
+	assert.NoError(t, err)
+	err = s.UpdateOperation(ctx, operation.ID, database.OperationQueryFactory.NewUpdate(ctx).
+		Set("backendid", "12345"))
+	assert.NoError(t, err)
+
+	history, err := s.GetOperationHistory(ctx, operation.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(history))
+	assert.Equal(t, fftypes.OpStatusPending, history[0].Status)
+	assert.Equal(t, fftypes.OpStatusFailed, history[1].Status)
+	assert.Equal(t, "pop", history[1].Error)
+	assert.Equal(t, fftypes.OpStatusPending, history[2].Status)
+	assert.Empty(t, history[2].Error)
+}
+
 func TestUpsertOperationFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
@@ -186,12 +280,37 @@ func TestUpsertOperationFailCommit(t *testing.T) {
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
 	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
 	err := s.UpsertOperation(context.Background(), &fftypes.Operation{ID: operationID}, true)
 	assert.Regexp(t, "FF10119", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUpsertOperationFailScanStatus(t *testing.T) {
+	s, mock := newMockProvider().init()
+	operationID := fftypes.NewUUID()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"opstatus", "other"}).AddRow("Pending", "other"))
+	mock.ExpectRollback()
+	err := s.UpsertOperation(context.Background(), &fftypes.Operation{ID: operationID}, true)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertOperationFailInsertHistory(t *testing.T) {
+	s, mock := newMockProvider().init()
+	operationID := fftypes.NewUUID()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"opstatus"}).AddRow("Pending"))
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertOperation(context.Background(), &fftypes.Operation{ID: operationID, Status: fftypes.OpStatusFailed}, true)
+	assert.Regexp(t, "FF10116", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetOperationByIDSelectFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	operationID := fftypes.NewUUID()
@@ -270,3 +389,196 @@ func TestOperationUpdateFail(t *testing.T) {
 	err := s.UpdateOperation(context.Background(), fftypes.NewUUID(), u)
 	assert.Regexp(t, "FF10117", err)
 }
+
+func TestOperationUpdateSelectStatusFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.OperationQueryFactory.NewUpdate(context.Background()).Set("status", fftypes.OpStatusFailed)
+	err := s.UpdateOperation(context.Background(), fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOperationUpdateScanStatusFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"opstatus", "other"}).AddRow("Pending", "other"))
+	mock.ExpectRollback()
+	u := database.OperationQueryFactory.NewUpdate(context.Background()).Set("status", fftypes.OpStatusFailed)
+	err := s.UpdateOperation(context.Background(), fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOperationUpdateInsertHistoryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"opstatus"}).AddRow("Pending"))
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.OperationQueryFactory.NewUpdate(context.Background()).Set("status", fftypes.OpStatusFailed)
+	err := s.UpdateOperation(context.Background(), fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF10116", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOperationHistorySelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetOperationHistory(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOperationHistoryScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"opstatus"}).AddRow("Pending"))
+	_, err := s.GetOperationHistory(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSoftDeleteOperationBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.SoftDeleteOperation(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10114", err)
+}
+
+func TestSoftDeleteOperationFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.SoftDeleteOperation(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10117", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOperationsPriorityOrder(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, fftypes.ChangeEventTypeCreated, "ns1", mock.Anything).Return()
+
+	// Insert a low priority operation, followed by a high priority one
+	opLow := &fftypes.Operation{
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Transaction: fftypes.NewUUID(),
+		Type:        fftypes.OpTypeDataExchangeBatchSend,
+		Status:      fftypes.OpStatusPending,
+		Created:     fftypes.Now(),
+		Priority:    1,
+	}
+	err := s.UpsertOperation(ctx, opLow, false)
+	assert.NoError(t, err)
+	opHigh := &fftypes.Operation{
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Transaction: fftypes.NewUUID(),
+		Type:        fftypes.OpTypeDataExchangeBatchSend,
+		Status:      fftypes.OpStatusPending,
+		Created:     fftypes.Now(),
+		Priority:    10,
+	}
+	err = s.UpsertOperation(ctx, opHigh, false)
+	assert.NoError(t, err)
+
+	// The high priority operation is returned first, despite being inserted later
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	operations, _, err := s.GetOperations(ctx, fb.Eq("status", fftypes.OpStatusPending).Sort("priority").Descending())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(operations))
+	assert.Equal(t, *opHigh.ID, *operations[0].ID)
+	assert.Equal(t, *opLow.ID, *operations[1].ID)
+
+	// Filter to only the high priority operations
+	operations, _, err = s.GetOperations(ctx, fb.Gt("priority", 5))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(operations))
+	assert.Equal(t, *opHigh.ID, *operations[0].ID)
+}
+
+func TestPurgeOperationsBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.PurgeOperations(context.Background(), fftypes.Now())
+	assert.Regexp(t, "FF10114", err)
+}
+
+func TestPurgeOperationsFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.PurgeOperations(context.Background(), fftypes.Now())
+	assert.Regexp(t, "FF10118", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeOperationsDeleteOperationsFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM operation_history .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM operations .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.PurgeOperations(context.Background(), fftypes.Now())
+	assert.Regexp(t, "FF10118", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeOperationsNoneFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	err := s.PurgeOperations(context.Background(), fftypes.Now())
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOperation(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := s.DeleteOperation(context.Background(), fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOperationFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteOperation(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10114", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOperationFailDeleteHistory(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteOperation(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10118", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOperationFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteOperation(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10118", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
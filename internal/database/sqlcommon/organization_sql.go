@@ -37,6 +37,8 @@ var (
 		"description",
 		"profile",
 		"created",
+		"metadata",
+		"country",
 	}
 	organizationFilterFieldMap = map[string]string{
 		"message": "message_id",
@@ -88,6 +90,8 @@ func (s *SQLCommon) UpsertOrganization(ctx context.Context, organization *fftype
 				Set("description", organization.Description).
 				Set("profile", organization.Profile).
 				Set("created", organization.Created).
+				Set("metadata", organization.Metadata).
+				Set("country", organization.Country).
 				Where(sq.Eq{"identity": organization.Identity}),
 			func() {
 				s.callbacks.UUIDCollectionEvent(database.CollectionOrganizations, fftypes.ChangeEventTypeUpdated, organization.ID)
@@ -108,6 +112,8 @@ func (s *SQLCommon) UpsertOrganization(ctx context.Context, organization *fftype
 					organization.Description,
 					organization.Profile,
 					organization.Created,
+					organization.Metadata,
+					organization.Country,
 				),
 			func() {
 				s.callbacks.UUIDCollectionEvent(database.CollectionOrganizations, fftypes.ChangeEventTypeCreated, organization.ID)
@@ -131,6 +137,8 @@ func (s *SQLCommon) organizationResult(ctx context.Context, row *sql.Rows) (*fft
 		&organization.Description,
 		&organization.Profile,
 		&organization.Created,
+		&organization.Metadata,
+		&organization.Country,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "orgs")
@@ -222,3 +230,48 @@ func (s *SQLCommon) UpdateOrganization(ctx context.Context, id *fftypes.UUID, up
 
 	return s.commitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) readIdentities(ctx context.Context, table string, query sq.SelectBuilder) (identities []string, err error) {
+	rows, _, err := s.query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities = []string{}
+	for rows.Next() {
+		var identity string
+		if err = rows.Scan(&identity); err != nil {
+			return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, table)
+		}
+		identities = append(identities, identity)
+	}
+	return identities, nil
+}
+
+func (s *SQLCommon) GetMissingOrganizations(ctx context.Context) (identities []string, err error) {
+	// The UNION removes identities that are referenced both as node owners and as group members
+	return s.readIdentities(ctx, "orgs",
+		sq.Select("n.owner").
+			From("nodes AS n").
+			LeftJoin("orgs AS o ON o.identity = n.owner").
+			Where(sq.Eq{"o.identity": nil}).
+			SuffixExpr(sq.ConcatExpr("UNION ",
+				sq.Select("m.identity").
+					From("members AS m").
+					LeftJoin("orgs AS o ON o.identity = m.identity").
+					Where(sq.Eq{"o.identity": nil}),
+			)),
+	)
+}
+
+func (s *SQLCommon) GetDuplicateIdentities(ctx context.Context) (identities []string, err error) {
+	// Each distinct identity/node pair is counted once, so an identity is only reported
+	// if it is bound to different nodes across the groups it is a member of
+	return s.readIdentities(ctx, "members",
+		sq.Select("b.identity").
+			FromSelect(sq.Select("identity", "node_id").Distinct().From("members"), "b").
+			GroupBy("b.identity").
+			Having("COUNT(*) > 1"),
+	)
+}
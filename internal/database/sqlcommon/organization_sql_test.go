@@ -77,6 +77,8 @@ func TestOrganizationsE2EWithDB(t *testing.T) {
 		Identity:    "0x12345",
 		Description: "organization1",
 		Profile:     fftypes.JSONObject{"some": "info"},
+		Metadata:    fftypes.JSONObject{"role": "auditor"},
+		Country:     "GB",
 		Created:     fftypes.Now(),
 	}
 	err = s.UpsertOrganization(context.Background(), organizationUpdated, true)
@@ -94,6 +96,7 @@ func TestOrganizationsE2EWithDB(t *testing.T) {
 	filter := fb.And(
 		fb.Eq("description", string(organizationUpdated.Description)),
 		fb.Eq("identity", organizationUpdated.Identity),
+		fb.Eq("country", "GB"),
 	)
 	organizationRes, res, err := s.GetOrganizations(ctx, filter.Count(true))
 	assert.NoError(t, err)
@@ -117,6 +120,15 @@ func TestOrganizationsE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(organizations))
 
+	// Negative test on the country filter
+	filter = fb.And(
+		fb.Eq("identity", organizationUpdated.Identity),
+		fb.Eq("country", "US"),
+	)
+	organizations, _, err = s.GetOrganizations(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(organizations))
+
 	s.callbacks.AssertExpectations(t)
 }
 
@@ -263,3 +275,112 @@ func TestOrganizationUpdateFail(t *testing.T) {
 	err := s.UpdateOrganization(context.Background(), fftypes.NewUUID(), u)
 	assert.Regexp(t, "FF10117", err)
 }
+
+func TestGetMissingOrganizationsE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	org := &fftypes.Organization{
+		ID:       fftypes.NewUUID(),
+		Message:  fftypes.NewUUID(),
+		Name:     "org1",
+		Identity: "0x12345",
+		Created:  fftypes.Now(),
+	}
+	node := &fftypes.Node{
+		ID:      fftypes.NewUUID(),
+		Message: fftypes.NewUUID(),
+		Owner:   "0x23456",
+		Name:    "node1",
+		Created: fftypes.Now(),
+	}
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "0x12345", Node: node.ID},
+				{Identity: "0x23456", Node: node.ID},
+				{Identity: "0x34567", Node: node.ID},
+			},
+		},
+		Hash:    fftypes.NewRandB32(),
+		Created: fftypes.Now(),
+	}
+	s.callbacks.On("UUIDCollectionEvent", database.CollectionOrganizations, fftypes.ChangeEventTypeCreated, org.ID).Return()
+	s.callbacks.On("UUIDCollectionEvent", database.CollectionNodes, fftypes.ChangeEventTypeCreated, node.ID).Return()
+	s.callbacks.On("HashCollectionNSEvent", database.CollectionGroups, fftypes.ChangeEventTypeCreated, "ns1", group.Hash).Return()
+	err := s.UpsertOrganization(ctx, org, false)
+	assert.NoError(t, err)
+	err = s.UpsertNode(ctx, node, false)
+	assert.NoError(t, err)
+	err = s.UpsertGroup(ctx, group, false)
+	assert.NoError(t, err)
+
+	missing, err := s.GetMissingOrganizations(ctx)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"0x23456", "0x34567"}, missing)
+
+	duplicates, err := s.GetDuplicateIdentities(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, duplicates)
+
+	// Bind one of the identities to a different node in another group
+	group2 := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group2",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "0x12345", Node: node.ID},
+				{Identity: "0x34567", Node: fftypes.NewUUID()},
+			},
+		},
+		Hash:    fftypes.NewRandB32(),
+		Created: fftypes.Now(),
+	}
+	s.callbacks.On("HashCollectionNSEvent", database.CollectionGroups, fftypes.ChangeEventTypeCreated, "ns1", group2.Hash).Return()
+	err = s.UpsertGroup(ctx, group2, false)
+	assert.NoError(t, err)
+
+	duplicates, err = s.GetDuplicateIdentities(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x34567"}, duplicates)
+
+	missing, err = s.GetMissingOrganizations(ctx)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"0x23456", "0x34567"}, missing)
+}
+
+func TestGetMissingOrganizationsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .* UNION SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetMissingOrganizations(context.Background())
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMissingOrganizationsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"owner", "extra"}).AddRow("0x12345", "extra"))
+	_, err := s.GetMissingOrganizations(context.Background())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDuplicateIdentities(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .* GROUP BY b.identity HAVING COUNT\\(\\*\\) > 1").WillReturnRows(sqlmock.NewRows([]string{"identity"}).AddRow("0x12345"))
+	duplicates, err := s.GetDuplicateIdentities(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x12345"}, duplicates)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDuplicateIdentitiesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetDuplicateIdentities(context.Background())
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -21,6 +21,7 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
 const (
@@ -47,4 +48,16 @@ type Provider interface {
 
 	// UpdateInsertForReturn updates the insert query for returning the Sequenc, and returns whether it needs to be run as a query to return the Sequence field
 	UpdateInsertForSequenceReturn(insert sq.InsertBuilder) (updatedInsert sq.InsertBuilder, runAsQuery bool)
+
+	// JSONExtract returns an expression that extracts the value at a path within the JSON held in a column, with the arguments
+	// to bind into it. When numeric is set the extracted value must compare numerically (NULL for non-numeric values)
+	JSONExtract(column string, path []string, numeric bool) (expr string, args []interface{})
+
+	// JSONContains returns a condition that matches when the JSON object held in a column contains every key of the object,
+	// with the same value. The column holds JSON natively (JSONB on PostgreSQL)
+	JSONContains(column string, obj fftypes.JSONObject) sq.Sqlizer
+
+	// JSONHasKey returns a condition that matches when the JSON object held in a column has the key. The column holds
+	// JSON natively (JSONB on PostgreSQL)
+	JSONHasKey(column, key string) sq.Sqlizer
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
 // testProvider uses the datadog mocking framework
@@ -85,3 +86,15 @@ func (mp *mockProvider) Open(url string) (*sql.DB, error) {
 func (mp *mockProvider) GetMigrationDriver(db *sql.DB) (migratedb.Driver, error) {
 	return nil, mp.getMigrationDriverError
 }
+
+func (mp *mockProvider) JSONExtract(column string, path []string, numeric bool) (string, []interface{}) {
+	return SQLiteJSONExtract(column, path, numeric)
+}
+
+func (mp *mockProvider) JSONContains(column string, obj fftypes.JSONObject) sq.Sqlizer {
+	return SQLiteJSONContains(column, obj)
+}
+
+func (mp *mockProvider) JSONHasKey(column, key string) sq.Sqlizer {
+	return SQLiteJSONHasKey(column, key)
+}
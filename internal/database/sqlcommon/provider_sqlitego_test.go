@@ -30,6 +30,7 @@ import (
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 
 	// Import SQLite driver
@@ -95,3 +96,15 @@ func (tp *sqliteGoTestProvider) Open(url string) (*sql.DB, error) {
 func (tp *sqliteGoTestProvider) GetMigrationDriver(db *sql.DB) (migratedb.Driver, error) {
 	return sqlite3.WithInstance(db, &sqlite3.Config{})
 }
+
+func (tp *sqliteGoTestProvider) JSONExtract(column string, path []string, numeric bool) (string, []interface{}) {
+	return SQLiteJSONExtract(column, path, numeric)
+}
+
+func (tp *sqliteGoTestProvider) JSONContains(column string, obj fftypes.JSONObject) sq.Sqlizer {
+	return SQLiteJSONContains(column, obj)
+}
+
+func (tp *sqliteGoTestProvider) JSONHasKey(column, key string) sq.Sqlizer {
+	return SQLiteJSONHasKey(column, key)
+}
@@ -37,6 +37,7 @@ type SQLCommon struct {
 	capabilities *database.Capabilities
 	callbacks    database.Callbacks
 	provider     Provider
+	compress     bool
 }
 
 type txContextKey struct{}
@@ -50,6 +51,7 @@ func (s *SQLCommon) Init(ctx context.Context, provider Provider, prefix config.P
 	s.capabilities = capabilities
 	s.callbacks = callbacks
 	s.provider = provider
+	s.compress = config.GetBool(config.BatchStorageCompress)
 	if s.provider == nil || s.provider.PlaceholderFormat() == nil || sequenceColumn == "" {
 		log.L(ctx).Errorf("Invalid SQL options from provider '%T'", s.provider)
 		return i18n.NewError(ctx, i18n.MsgDBInitFailed)
@@ -41,12 +41,15 @@ var (
 		"options",
 		"created",
 		"updated",
+		"max_delivery_attempts",
+		"paused",
 	}
 	subscriptionFilterFieldMap = map[string]string{
-		"filter.events": "filter_events",
-		"filter.topics": "filter_topics",
-		"filter.tag":    "filter_tag",
-		"filter.group":  "filter_group",
+		"filter.events":       "filter_events",
+		"filter.topics":       "filter_topics",
+		"filter.tag":          "filter_tag",
+		"filter.group":        "filter_group",
+		"maxdeliveryattempts": "max_delivery_attempts",
 	}
 )
 
@@ -102,6 +105,8 @@ func (s *SQLCommon) UpsertSubscription(ctx context.Context, subscription *fftype
 				Set("options", subscription.Options).
 				Set("created", subscription.Created).
 				Set("updated", subscription.Updated).
+				Set("max_delivery_attempts", subscription.MaxDeliveryAttempts).
+				Set("paused", subscription.Paused).
 				Where(sq.Eq{
 					"namespace": subscription.Namespace,
 					"name":      subscription.Name,
@@ -132,6 +137,8 @@ func (s *SQLCommon) UpsertSubscription(ctx context.Context, subscription *fftype
 					subscription.Options,
 					subscription.Created,
 					subscription.Updated,
+					subscription.MaxDeliveryAttempts,
+					subscription.Paused,
 				),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionSubscriptions, fftypes.ChangeEventTypeCreated, subscription.Namespace, subscription.ID)
@@ -159,6 +166,8 @@ func (s *SQLCommon) subscriptionResult(ctx context.Context, row *sql.Rows) (*fft
 		&subscription.Options,
 		&subscription.Created,
 		&subscription.Updated,
+		&subscription.MaxDeliveryAttempts,
+		&subscription.Paused,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "subscriptions")
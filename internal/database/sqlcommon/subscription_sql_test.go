@@ -82,9 +82,11 @@ func TestSubscriptionsE2EWithDB(t *testing.T) {
 			Tag:    "tag.*",
 			Group:  "group.*",
 		},
-		Options: subOpts,
-		Created: fftypes.Now(),
-		Updated: fftypes.Now(),
+		Options:             subOpts,
+		Created:             fftypes.Now(),
+		Updated:             fftypes.Now(),
+		MaxDeliveryAttempts: 5,
+		Paused:              true,
 	}
 
 	// Rejects attempt to update ID
@@ -110,6 +112,8 @@ func TestSubscriptionsE2EWithDB(t *testing.T) {
 	filter := fb.And(
 		fb.Eq("namespace", subscriptionUpdated.Namespace),
 		fb.Eq("name", subscriptionUpdated.Name),
+		fb.Eq("maxdeliveryattempts", 5),
+		fb.Eq("paused", true),
 	)
 	subscriptionRes, res, err := s.GetSubscriptions(ctx, filter.Count(true))
 	assert.NoError(t, err)
@@ -258,7 +262,7 @@ func TestSubscriptionUpdateBuildQueryFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(subscriptionColumns).AddRow(
-		fftypes.NewUUID(), "ns1", "sub1", "websockets", "", "", "", "", `{}`, fftypes.Now(), fftypes.Now()),
+		fftypes.NewUUID(), "ns1", "sub1", "websockets", "", "", "", "", `{}`, fftypes.Now(), fftypes.Now(), 0, false),
 	)
 	u := database.SubscriptionQueryFactory.NewUpdate(context.Background()).Set("name", map[bool]bool{true: false})
 	err := s.UpdateSubscription(context.Background(), "ns1", "name1", u)
@@ -289,7 +293,7 @@ func TestSubscriptionUpdateFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(subscriptionColumns).AddRow(
-		fftypes.NewUUID(), "ns1", "sub1", "websockets", "", "", "", "", `{}`, fftypes.Now(), fftypes.Now()),
+		fftypes.NewUUID(), "ns1", "sub1", "websockets", "", "", "", "", `{}`, fftypes.Now(), fftypes.Now(), 0, false),
 	)
 	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
 	mock.ExpectRollback()
@@ -309,7 +313,7 @@ func TestSubscriptionDeleteFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(subscriptionColumns).AddRow(
-		fftypes.NewUUID(), "ns1", "sub1", "websockets", "", "", "", "", `{}`, fftypes.Now(), fftypes.Now()),
+		fftypes.NewUUID(), "ns1", "sub1", "websockets", "", "", "", "", `{}`, fftypes.Now(), fftypes.Now(), 0, false),
 	)
 	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
 	err := s.DeleteSubscriptionByID(context.Background(), fftypes.NewUUID())
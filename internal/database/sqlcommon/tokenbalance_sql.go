@@ -0,0 +1,154 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+var (
+	tokenBalanceColumns = []string{
+		"pool_id",
+		"address",
+		"amount",
+		"updated",
+	}
+	tokenBalanceFilterFieldMap = map[string]string{
+		"pool":        "pool_id",
+		"lastupdated": "updated",
+	}
+)
+
+func (s *SQLCommon) UpsertTokenBalance(ctx context.Context, balance *fftypes.TokenBalance) (err error) {
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	rows, _, err := s.queryTx(ctx, tx,
+		sq.Select("seq").
+			From("token_balances").
+			Where(sq.And{
+				sq.Eq{"pool_id": balance.Pool},
+				sq.Eq{"address": balance.Address},
+			}),
+	)
+	if err != nil {
+		return err
+	}
+	existing := rows.Next()
+	rows.Close()
+
+	if existing {
+		if err = s.updateTx(ctx, tx,
+			sq.Update("token_balances").
+				Set("amount", balance.Amount).
+				Set("updated", balance.LastUpdated).
+				Where(sq.And{
+					sq.Eq{"pool_id": balance.Pool},
+					sq.Eq{"address": balance.Address},
+				}),
+			nil,
+		); err != nil {
+			return err
+		}
+	} else {
+		if _, err = s.insertTx(ctx, tx,
+			sq.Insert("token_balances").
+				Columns(tokenBalanceColumns...).
+				Values(
+					balance.Pool,
+					balance.Address,
+					balance.Amount,
+					balance.LastUpdated,
+				),
+			nil,
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenBalanceResult(ctx context.Context, row *sql.Rows) (*fftypes.TokenBalance, error) {
+	balance := fftypes.TokenBalance{}
+	err := row.Scan(
+		&balance.Pool,
+		&balance.Address,
+		&balance.Amount,
+		&balance.LastUpdated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "token_balances")
+	}
+	return &balance, nil
+}
+
+func (s *SQLCommon) GetTokenBalance(ctx context.Context, poolID *fftypes.UUID, address string) (*fftypes.TokenBalance, error) {
+	rows, _, err := s.query(ctx,
+		sq.Select(tokenBalanceColumns...).
+			From("token_balances").
+			Where(sq.And{
+				sq.Eq{"pool_id": poolID},
+				sq.Eq{"address": address},
+			}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token balance '%s:%s' not found", poolID, address)
+		return nil, nil
+	}
+
+	return s.tokenBalanceResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTokenBalances(ctx context.Context, filter database.Filter) ([]*fftypes.TokenBalance, *database.FilterResult, error) {
+	query, fop, fi, err := s.filterSelect(ctx, "", sq.Select(tokenBalanceColumns...).From("token_balances"), filter, tokenBalanceFilterFieldMap, []string{"seq"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.query(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	balances := []*fftypes.TokenBalance{}
+	for rows.Next() {
+		d, err := s.tokenBalanceResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		balances = append(balances, d)
+	}
+
+	return balances, s.queryRes(ctx, tx, "token_balances", fop, fi), err
+}
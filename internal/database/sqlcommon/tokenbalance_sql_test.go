@@ -0,0 +1,178 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBalanceE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new token balance
+	poolID := fftypes.NewUUID()
+	balance := &fftypes.TokenBalance{
+		Pool:        poolID,
+		Address:     "0x0",
+		Amount:      *fftypes.NewBigInt(10),
+		LastUpdated: fftypes.Now(),
+	}
+	balanceJson, _ := json.Marshal(&balance)
+
+	err := s.UpsertTokenBalance(ctx, balance)
+	assert.NoError(t, err)
+
+	// Query back the token balance (by pool ID and address)
+	balanceRead, err := s.GetTokenBalance(ctx, poolID, "0x0")
+	assert.NoError(t, err)
+	assert.NotNil(t, balanceRead)
+	balanceReadJson, _ := json.Marshal(&balanceRead)
+	assert.Equal(t, string(balanceJson), string(balanceReadJson))
+
+	// Update the balance to a value beyond the range of an int64
+	balance.Amount.Int().SetString("100000000000000000000", 10)
+	balance.LastUpdated = fftypes.Now()
+	balanceJson, _ = json.Marshal(&balance)
+	err = s.UpsertTokenBalance(ctx, balance)
+	assert.NoError(t, err)
+
+	// Query back the token balance (by query filter)
+	fb := database.TokenBalanceQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("pool", poolID),
+		fb.Eq("address", "0x0"),
+		fb.Gt("lastupdated", 0),
+		fb.Gt("amount", "99999999999999999999"),
+	)
+	balances, res, err := s.GetTokenBalances(ctx, filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(balances))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	balanceReadJson, _ = json.Marshal(balances[0])
+	assert.Equal(t, string(balanceJson), string(balanceReadJson))
+}
+
+func TestUpsertTokenBalanceFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTokenBalance(context.Background(), &fftypes.TokenBalance{})
+	assert.Regexp(t, "FF10114", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenBalanceFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTokenBalance(context.Background(), &fftypes.TokenBalance{})
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenBalanceFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenBalance(context.Background(), &fftypes.TokenBalance{})
+	assert.Regexp(t, "FF10116", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenBalanceFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow("1"))
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenBalance(context.Background(), &fftypes.TokenBalance{})
+	assert.Regexp(t, "FF10117", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenBalanceFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTokenBalance(context.Background(), &fftypes.TokenBalance{})
+	assert.Regexp(t, "FF10119", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenBalance(context.Background(), fftypes.NewUUID(), "0x0")
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}))
+	balance, err := s.GetTokenBalance(context.Background(), fftypes.NewUUID(), "0x0")
+	assert.NoError(t, err)
+	assert.Nil(t, balance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow("only one"))
+	_, err := s.GetTokenBalance(context.Background(), fftypes.NewUUID(), "0x0")
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalancesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenBalanceQueryFactory.NewFilter(context.Background()).Eq("address", "")
+	_, _, err := s.GetTokenBalances(context.Background(), f)
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalancesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenBalanceQueryFactory.NewFilter(context.Background()).Eq("address", map[bool]bool{true: false})
+	_, _, err := s.GetTokenBalances(context.Background(), f)
+	assert.Regexp(t, "FF10149.*address", err)
+}
+
+func TestGetTokenBalancesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"address"}).AddRow("only one"))
+	f := database.TokenBalanceQueryFactory.NewFilter(context.Background()).Eq("address", "")
+	_, _, err := s.GetTokenBalances(context.Background(), f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -40,6 +40,7 @@ var (
 		"created",
 		"tx_type",
 		"tx_id",
+		"status",
 	}
 	tokenPoolFilterFieldMap = map[string]string{
 		"protocolid":       "protocol_id",
@@ -89,6 +90,7 @@ func (s *SQLCommon) UpsertTokenPool(ctx context.Context, pool *fftypes.TokenPool
 				Set("message_id", pool.Message).
 				Set("tx_type", pool.TX.Type).
 				Set("tx_id", pool.TX.ID).
+				Set("status", pool.Status).
 				Where(sq.Eq{"id": pool.ID}),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionTokenPools, fftypes.ChangeEventTypeUpdated, pool.Namespace, pool.ID)
@@ -113,6 +115,7 @@ func (s *SQLCommon) UpsertTokenPool(ctx context.Context, pool *fftypes.TokenPool
 					pool.Created,
 					pool.TX.Type,
 					pool.TX.ID,
+					pool.Status,
 				),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionTokenPools, fftypes.ChangeEventTypeCreated, pool.Namespace, pool.ID)
@@ -139,6 +142,7 @@ func (s *SQLCommon) tokenPoolResult(ctx context.Context, row *sql.Rows) (*fftype
 		&pool.Created,
 		&pool.TX.Type,
 		&pool.TX.ID,
+		&pool.Status,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "tokenpool")
@@ -174,6 +178,10 @@ func (s *SQLCommon) GetTokenPool(ctx context.Context, ns string, name string) (m
 	return s.getTokenPoolPred(ctx, ns+":"+name, sq.And{sq.Eq{"namespace": ns}, sq.Eq{"name": name}})
 }
 
+func (s *SQLCommon) GetTokenPoolBySymbol(ctx context.Context, ns string, symbol string) (*fftypes.TokenPool, error) {
+	return s.getTokenPoolPred(ctx, ns+":"+symbol, sq.And{sq.Eq{"namespace": ns}, sq.Eq{"symbol": symbol}})
+}
+
 func (s *SQLCommon) GetTokenPoolByID(ctx context.Context, id *fftypes.UUID) (message *fftypes.TokenPool, err error) {
 	return s.getTokenPoolPred(ctx, id.String(), sq.Eq{"id": id})
 }
@@ -50,6 +50,7 @@ func TestTokenPoolE2EWithDB(t *testing.T) {
 			Type: fftypes.TransactionTypeTokenPool,
 			ID:   fftypes.NewUUID(),
 		},
+		Status: fftypes.TokenPoolStatusActive,
 	}
 
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionTokenPools, fftypes.ChangeEventTypeCreated, "ns1", poolID, mock.Anything).
@@ -77,6 +78,37 @@ func TestTokenPoolE2EWithDB(t *testing.T) {
 	poolReadJson, _ = json.Marshal(&poolRead)
 	assert.Equal(t, string(poolJson), string(poolReadJson))
 
+	// Query back the token pool (by symbol)
+	poolRead, err = s.GetTokenPoolBySymbol(ctx, pool.Namespace, pool.Symbol)
+	assert.NoError(t, err)
+	assert.NotNil(t, poolRead)
+	poolReadJson, _ = json.Marshal(&poolRead)
+	assert.Equal(t, string(poolJson), string(poolReadJson))
+
+	// Pools without a symbol do not conflict with each other
+	for i := 0; i < 2; i++ {
+		noSymbolPool := &fftypes.TokenPool{
+			ID:         fftypes.NewUUID(),
+			Namespace:  "ns1",
+			Name:       fmt.Sprintf("nosymbol%d", i),
+			ProtocolID: fmt.Sprintf("nosymbol%d", i),
+		}
+		s.callbacks.On("UUIDCollectionNSEvent", database.CollectionTokenPools, fftypes.ChangeEventTypeCreated, "ns1", noSymbolPool.ID, mock.Anything).Return().Once()
+		err = s.UpsertTokenPool(ctx, noSymbolPool)
+		assert.NoError(t, err)
+	}
+
+	// A second pool cannot use the same symbol
+	dupSymbolPool := &fftypes.TokenPool{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		Name:       "dup-symbol",
+		ProtocolID: "dup-symbol",
+		Symbol:     pool.Symbol,
+	}
+	err = s.UpsertTokenPool(ctx, dupSymbolPool)
+	assert.Regexp(t, "FF10116", err)
+
 	// Query back the token pool (by protocol ID)
 	poolRead, err = s.GetTokenPoolByProtocolID(ctx, pool.ProtocolID)
 	assert.NoError(t, err)
@@ -93,6 +125,7 @@ func TestTokenPoolE2EWithDB(t *testing.T) {
 		fb.Eq("protocolid", pool.ProtocolID),
 		fb.Eq("message", pool.Message),
 		fb.Eq("created", pool.Created),
+		fb.Eq("status", pool.Status),
 	)
 	pools, res, err := s.GetTokenPools(ctx, filter.Count(true))
 	assert.NoError(t, err)
@@ -104,6 +137,7 @@ func TestTokenPoolE2EWithDB(t *testing.T) {
 	// Update the token pool
 	pool.ProtocolID = "67890"
 	pool.Type = fftypes.TokenTypeNonFungible
+	pool.Status = fftypes.TokenPoolStatusFrozen
 	err = s.UpsertTokenPool(ctx, pool)
 	assert.NoError(t, err)
 
@@ -212,6 +246,14 @@ func TestGetTokenPoolByIDScanFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetTokenPoolBySymbolSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenPoolBySymbol(context.Background(), "ns1", "COIN")
+	assert.Regexp(t, "FF10115", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetTokenPoolsQueryFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
@@ -39,15 +39,23 @@ var (
 		"protocol_id",
 		"status",
 		"info",
+		"gas_limit",
+		"gas_price",
 	}
 	transactionFilterFieldMap = map[string]string{
 		"type":       "ttype",
 		"protocolid": "protocol_id",
 		"reference":  "ref",
+		"gaslimit":   "gas_limit",
+		"gasprice":   "gas_price",
 	}
 )
 
 func (s *SQLCommon) UpsertTransaction(ctx context.Context, transaction *fftypes.Transaction, allowHashUpdate bool) (err error) {
+	if err = fftypes.FFEnumValid(ctx, "txtype", transaction.Subject.Type); err != nil {
+		return err
+	}
+
 	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
 	if err != nil {
 		return err
@@ -79,18 +87,25 @@ func (s *SQLCommon) UpsertTransaction(ctx context.Context, transaction *fftypes.
 	if existing {
 
 		// Update the transaction
+		update := sq.Update("transactions").
+			Set("ttype", string(transaction.Subject.Type)).
+			Set("namespace", transaction.Subject.Namespace).
+			Set("ref", transaction.Subject.Reference).
+			Set("signer", transaction.Subject.Signer).
+			Set("hash", transaction.Hash).
+			Set("created", transaction.Created).
+			Set("protocol_id", transaction.ProtocolID).
+			Set("status", transaction.Status).
+			Set("info", transaction.Info)
+		// The gas is only known to the submitting node, so updates from events do not clear it
+		if transaction.GasLimit != nil {
+			update = update.Set("gas_limit", transaction.GasLimit)
+		}
+		if transaction.GasPrice != nil {
+			update = update.Set("gas_price", transaction.GasPrice)
+		}
 		if err = s.updateTx(ctx, tx,
-			sq.Update("transactions").
-				Set("ttype", string(transaction.Subject.Type)).
-				Set("namespace", transaction.Subject.Namespace).
-				Set("ref", transaction.Subject.Reference).
-				Set("signer", transaction.Subject.Signer).
-				Set("hash", transaction.Hash).
-				Set("created", transaction.Created).
-				Set("protocol_id", transaction.ProtocolID).
-				Set("status", transaction.Status).
-				Set("info", transaction.Info).
-				Where(sq.Eq{"id": transaction.ID}),
+			update.Where(sq.Eq{"id": transaction.ID}),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionTransactions, fftypes.ChangeEventTypeUpdated, transaction.Subject.Namespace, transaction.ID)
 			},
@@ -113,6 +128,8 @@ func (s *SQLCommon) UpsertTransaction(ctx context.Context, transaction *fftypes.
 					transaction.ProtocolID,
 					transaction.Status,
 					transaction.Info,
+					transaction.GasLimit,
+					transaction.GasPrice,
 				),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionTransactions, fftypes.ChangeEventTypeCreated, transaction.Subject.Namespace, transaction.ID)
@@ -138,6 +155,8 @@ func (s *SQLCommon) transactionResult(ctx context.Context, row *sql.Rows) (*ffty
 		&transaction.ProtocolID,
 		&transaction.Status,
 		&transaction.Info,
+		&transaction.GasLimit,
+		&transaction.GasPrice,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, "transactions")
@@ -217,3 +236,21 @@ func (s *SQLCommon) UpdateTransaction(ctx context.Context, id *fftypes.UUID, upd
 
 	return s.commitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) DeleteTransaction(ctx context.Context, id *fftypes.UUID) (err error) {
+
+	ctx, tx, autoCommit, err := s.beginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.rollbackTx(ctx, tx, autoCommit)
+
+	err = s.deleteTx(ctx, tx, sq.Delete("transactions").Where(sq.Eq{
+		"id": id,
+	}), nil /* no change events for purges */)
+	if err != nil {
+		return err
+	}
+
+	return s.commitTx(ctx, tx, autoCommit)
+}
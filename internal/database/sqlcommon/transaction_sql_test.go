@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -46,8 +47,10 @@ func TestTransactionE2EWithDB(t *testing.T) {
 			Signer:    "0x12345",
 			Reference: fftypes.NewUUID(),
 		},
-		Created: fftypes.Now(),
-		Status:  fftypes.OpStatusPending,
+		Created:  fftypes.Now(),
+		Status:   fftypes.OpStatusPending,
+		GasLimit: fftypes.NewHexBigInt(big.NewInt(21000)),
+		GasPrice: fftypes.NewHexBigInt(big.NewInt(20000000000)),
 	}
 
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionTransactions, fftypes.ChangeEventTypeCreated, "ns1", transactionID, mock.Anything).Return()
@@ -90,9 +93,14 @@ func TestTransactionE2EWithDB(t *testing.T) {
 	err = s.UpsertTransaction(context.Background(), transactionUpdated, true)
 	assert.NoError(t, err)
 
-	// Check we get the exact same message back - note the removal of one of the transaction elements
+	// Check we get the exact same message back - note the removal of one of the transaction elements,
+	// and that the gas is retained as it was not set on the update
 	transactionRead, err = s.GetTransactionByID(ctx, transactionID)
 	assert.NoError(t, err)
+	assert.Equal(t, "0x5208", transactionRead.GasLimit.String())
+	assert.Equal(t, "0x4a817c800", transactionRead.GasPrice.String())
+	transactionUpdated.GasLimit = transactionRead.GasLimit
+	transactionUpdated.GasPrice = transactionRead.GasPrice
 	transactionJson, _ = json.Marshal(&transactionUpdated)
 	transactionReadJson, _ = json.Marshal(&transactionRead)
 	assert.Equal(t, string(transactionJson), string(transactionReadJson))
@@ -104,6 +112,9 @@ func TestTransactionE2EWithDB(t *testing.T) {
 		fb.Eq("protocolid", transactionUpdated.ProtocolID),
 		fb.Eq("signer", transactionUpdated.Subject.Signer),
 		fb.Gt("created", "0"),
+		fb.Gte("gasprice", "20000000000"),
+		fb.Lte("gasprice", "0x4a817c800"),
+		fb.Gt("gaslimit", 1000),
 	)
 	transactions, res, err := s.GetTransactions(ctx, filter.Count(true))
 	assert.NoError(t, err)
@@ -121,6 +132,14 @@ func TestTransactionE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(transactions))
 
+	// Gas is updated if set
+	transactionUpdated.GasPrice = fftypes.NewHexBigInt(big.NewInt(30000000000))
+	err = s.UpsertTransaction(context.Background(), transactionUpdated, false)
+	assert.NoError(t, err)
+	transactionRead, err = s.GetTransactionByID(ctx, transactionID)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x6fc23ac00", transactionRead.GasPrice.String())
+
 	// Update
 	up := database.TransactionQueryFactory.NewUpdate(ctx).
 		Set("status", fftypes.OpStatusSucceeded)
@@ -137,10 +156,17 @@ func TestTransactionE2EWithDB(t *testing.T) {
 	assert.Equal(t, 1, len(transactions))
 }
 
+func TestUpsertTransactionInvalidType(t *testing.T) {
+	s, mock := newMockProvider().init()
+	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{Subject: fftypes.TransactionSubject{Type: "wrong"}}, true)
+	assert.Regexp(t, "FF10277", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpsertTransactionFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
-	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{}, true)
+	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{Subject: fftypes.TransactionSubject{Type: fftypes.TransactionTypeBatchPin}}, true)
 	assert.Regexp(t, "FF10114", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -151,7 +177,7 @@ func TestUpsertTransactionFailSelect(t *testing.T) {
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	mock.ExpectRollback()
 	transactionID := fftypes.NewUUID()
-	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{ID: transactionID}, true)
+	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{ID: transactionID, Subject: fftypes.TransactionSubject{Type: fftypes.TransactionTypeBatchPin}}, true)
 	assert.Regexp(t, "FF10115", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -163,7 +189,7 @@ func TestUpsertTransactionFailInsert(t *testing.T) {
 	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
 	mock.ExpectRollback()
 	transactionID := fftypes.NewUUID()
-	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{ID: transactionID}, true)
+	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{ID: transactionID, Subject: fftypes.TransactionSubject{Type: fftypes.TransactionTypeBatchPin}}, true)
 	assert.Regexp(t, "FF10116", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -175,7 +201,7 @@ func TestUpsertTransactionFailUpdate(t *testing.T) {
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(transactionID.String()))
 	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
 	mock.ExpectRollback()
-	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{ID: transactionID}, true)
+	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{ID: transactionID, Subject: fftypes.TransactionSubject{Type: fftypes.TransactionTypeBatchPin}}, true)
 	assert.Regexp(t, "FF10117", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -187,7 +213,7 @@ func TestUpsertTransactionFailCommit(t *testing.T) {
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
 	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
-	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{ID: transactionID}, true)
+	err := s.UpsertTransaction(context.Background(), &fftypes.Transaction{ID: transactionID, Subject: fftypes.TransactionSubject{Type: fftypes.TransactionTypeBatchPin}}, true)
 	assert.Regexp(t, "FF10119", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -270,3 +296,31 @@ func TestTransactionUpdateFail(t *testing.T) {
 	err := s.UpdateTransaction(context.Background(), fftypes.NewUUID(), u)
 	assert.Regexp(t, "FF10117", err)
 }
+
+func TestDeleteTransaction(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := s.DeleteTransaction(context.Background(), fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTransactionFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteTransaction(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10114", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTransactionFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteTransaction(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10118", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/database/sqlcommon"
 	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
 
 	// Import the derivation of SQLite3 CGO suported by golang-migrate
 	_ "github.com/mattn/go-sqlite3"
@@ -67,3 +68,15 @@ func (sqlite *SQLite3) Open(url string) (*sql.DB, error) {
 func (sqlite *SQLite3) GetMigrationDriver(db *sql.DB) (migratedb.Driver, error) {
 	return migratesqlite3.WithInstance(db, &migratesqlite3.Config{})
 }
+
+func (sqlite *SQLite3) JSONExtract(column string, path []string, numeric bool) (string, []interface{}) {
+	return sqlcommon.SQLiteJSONExtract(column, path, numeric)
+}
+
+func (sqlite *SQLite3) JSONContains(column string, obj fftypes.JSONObject) sq.Sqlizer {
+	return sqlcommon.SQLiteJSONContains(column, obj)
+}
+
+func (sqlite *SQLite3) JSONHasKey(column, key string) sq.Sqlizer {
+	return sqlcommon.SQLiteJSONHasKey(column, key)
+}
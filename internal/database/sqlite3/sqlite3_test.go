@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/database/sqlcommon"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,3 +51,23 @@ func TestSQLite3GoProvider(t *testing.T) {
 	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)", sql)
 	assert.False(t, query)
 }
+
+func TestSQLite3JSONExtract(t *testing.T) {
+	sqlite := &SQLite3{}
+	expr, args := sqlite.JSONExtract("value", []string{"items", "0", "id"}, false)
+	assert.Equal(t, "json_extract(CAST(value AS TEXT), ?)", expr)
+	assert.Equal(t, []interface{}{`$."items"[0]."id"`}, args)
+}
+
+func TestSQLite3JSONContains(t *testing.T) {
+	sqlite := &SQLite3{}
+	sql, args, err := sqlite.JSONContains("labels", fftypes.JSONObject{"department": "finance"}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(json_extract(CAST(labels AS TEXT), ?) = ?)", sql)
+	assert.Equal(t, []interface{}{`$."department"`, "finance"}, args)
+
+	sql, args, err = sqlite.JSONHasKey("labels", "region").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "json_type(CAST(labels AS TEXT), ?) IS NOT NULL", sql)
+	assert.Equal(t, []interface{}{`$."region"`}, args)
+}
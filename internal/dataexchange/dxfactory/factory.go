@@ -21,12 +21,14 @@ import (
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/dataexchange/dxhttps"
+	"github.com/hyperledger/firefly/internal/dataexchange/ffdx"
 	"github.com/hyperledger/firefly/internal/i18n"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
 )
 
 var plugins = []dataexchange.Plugin{
 	&dxhttps.HTTPS{},
+	&ffdx.FFDX{},
 }
 
 var pluginsByName = make(map[string]dataexchange.Plugin)
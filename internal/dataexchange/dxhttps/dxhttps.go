@@ -147,7 +147,7 @@ func (h *HTTPS) UploadBLOB(ctx context.Context, ns string, id fftypes.UUID, cont
 		err = restclient.WrapRestErr(ctx, res, err, i18n.MsgDXRESTErr)
 		return "", nil, err
 	}
-	if hash, err = fftypes.ParseBytes32(ctx, upload.Hash); err != nil {
+	if hash, err = fftypes.ParseBytes32Flexible(ctx, upload.Hash); err != nil {
 		return "", nil, i18n.WrapError(ctx, err, i18n.MsgDXBadResponse, "hash", upload.Hash)
 	}
 	return payloadRef, hash, nil
@@ -157,6 +157,7 @@ func (h *HTTPS) DownloadBLOB(ctx context.Context, payloadRef string) (content io
 	res, err := h.client.R().SetContext(ctx).
 		SetDoNotParseResponse(true).
 		Get(fmt.Sprintf("/api/v1/blobs/%s", payloadRef))
+	restclient.OnAfterResponse(h.client, res) // required using SetDoNotParseResponse
 	if err != nil || !res.IsSuccess() {
 		if err == nil {
 			_ = res.RawBody().Close()
@@ -166,6 +167,15 @@ func (h *HTTPS) DownloadBLOB(ctx context.Context, payloadRef string) (content io
 	return res.RawBody(), nil
 }
 
+func (h *HTTPS) DeleteBLOB(ctx context.Context, payloadRef string) (err error) {
+	res, err := h.client.R().SetContext(ctx).
+		Delete(fmt.Sprintf("/api/v1/blobs/%s", payloadRef))
+	if err != nil || !res.IsSuccess() {
+		return restclient.WrapRestErr(ctx, res, err, i18n.MsgDXRESTErr)
+	}
+	return nil
+}
+
 func (h *HTTPS) SendMessage(ctx context.Context, peerID string, data []byte) (trackingID string, err error) {
 	var responseData responseWithRequestID
 	res, err := h.client.R().SetContext(ctx).
@@ -181,6 +191,12 @@ func (h *HTTPS) SendMessage(ctx context.Context, peerID string, data []byte) (tr
 	return responseData.RequestID, nil
 }
 
+// GetMessageStatus always reports the tracking ID as unknown, as the https connector does not
+// track acknowledgements of delivered messages
+func (h *HTTPS) GetMessageStatus(ctx context.Context, trackingID string) (*fftypes.MessageDeliveryStatus, error) {
+	return nil, nil
+}
+
 func (h *HTTPS) TransferBLOB(ctx context.Context, peerID, payloadRef string) (trackingID string, err error) {
 	var responseData responseWithRequestID
 	res, err := h.client.R().SetContext(ctx).
@@ -208,7 +224,7 @@ func (h *HTTPS) CheckBLOBReceived(ctx context.Context, peerID, ns string, id fft
 		return nil, restclient.WrapRestErr(ctx, res, err, i18n.MsgDXRESTErr)
 	}
 	hashString := res.Header().Get(dxHTTPHeaderHash)
-	if hash, err = fftypes.ParseBytes32(ctx, hashString); err != nil {
+	if hash, err = fftypes.ParseBytes32Flexible(ctx, hashString); err != nil {
 		return nil, i18n.WrapError(ctx, err, i18n.MsgDXBadResponse, "hash", hashString)
 	}
 	return hash, nil
@@ -251,7 +267,7 @@ func (h *HTTPS) eventLoop() {
 				err = h.callbacks.TransferResult(msg.RequestID, fftypes.OpStatusSucceeded, "", nil)
 			case blobReceived:
 				var hash *fftypes.Bytes32
-				hash, err = fftypes.ParseBytes32(ctx, msg.Hash)
+				hash, err = fftypes.ParseBytes32Flexible(ctx, msg.Hash)
 				if err != nil {
 					l.Errorf("Invalid hash received in DX event: '%s'", msg.Hash)
 					err = nil // still confirm the message
@@ -314,6 +314,30 @@ func TestDownloadBLOBError(t *testing.T) {
 	assert.Regexp(t, "FF10229", err)
 }
 
+func TestDeleteBLOB(t *testing.T) {
+
+	h, _, _, httpURL, done := newTestHTTPS(t)
+	defer done()
+
+	u := fftypes.NewUUID()
+	httpmock.RegisterResponder("DELETE", fmt.Sprintf("%s/api/v1/blobs/ns1/%s", httpURL, u),
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{}))
+
+	err := h.DeleteBLOB(context.Background(), fmt.Sprintf("ns1/%s", u))
+	assert.NoError(t, err)
+}
+
+func TestDeleteBLOBError(t *testing.T) {
+	h, _, _, httpURL, done := newTestHTTPS(t)
+	defer done()
+
+	httpmock.RegisterResponder("DELETE", fmt.Sprintf("%s/api/v1/blobs/bad", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	err := h.DeleteBLOB(context.Background(), "bad")
+	assert.Regexp(t, "FF10229", err)
+}
+
 func TestSendMessage(t *testing.T) {
 
 	h, _, _, httpURL, done := newTestHTTPS(t)
@@ -340,6 +364,15 @@ func TestSendMessageError(t *testing.T) {
 	assert.Regexp(t, "FF10229", err)
 }
 
+func TestGetMessageStatusUnknown(t *testing.T) {
+	h, _, _, _, done := newTestHTTPS(t)
+	defer done()
+
+	status, err := h.GetMessageStatus(context.Background(), "abcd1234")
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+}
+
 func TestTransferBLOB(t *testing.T) {
 
 	h, _, _, httpURL, done := newTestHTTPS(t)
@@ -0,0 +1,88 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ffdx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/dataexchange/dxhttps"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/internal/restclient"
+	"github.com/hyperledger/firefly/pkg/dataexchange"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// FFDX is a stub connector for the FireFly Data Exchange. It shares the message and blob transfer API
+// of the https connector, and additionally reports the acknowledgement status of sent messages.
+type FFDX struct {
+	dxhttps.HTTPS
+	client *resty.Client
+}
+
+type messageStatus struct {
+	Status     string          `json:"status"`
+	ReceivedAt *fftypes.FFTime `json:"receivedAt,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+const (
+	messageDelivered = "message-delivered"
+	messageFailed    = "message-failed"
+)
+
+func (f *FFDX) Name() string {
+	return "ffdx"
+}
+
+func (f *FFDX) Init(ctx context.Context, prefix config.Prefix, callbacks dataexchange.Callbacks) (err error) {
+	if err = f.HTTPS.Init(ctx, prefix, callbacks); err != nil {
+		return err
+	}
+	f.client = restclient.New(log.WithLogField(ctx, "dx", "ffdx"), prefix)
+	return nil
+}
+
+func (f *FFDX) GetMessageStatus(ctx context.Context, trackingID string) (*fftypes.MessageDeliveryStatus, error) {
+	var responseData messageStatus
+	res, err := f.client.R().SetContext(ctx).
+		SetResult(&responseData).
+		Get(fmt.Sprintf("/api/v1/messages/%s", url.PathEscape(trackingID)))
+	if err == nil && res.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil || !res.IsSuccess() {
+		return nil, restclient.WrapRestErr(ctx, res, err, i18n.MsgDXRESTErr)
+	}
+	status := &fftypes.MessageDeliveryStatus{
+		Status:     fftypes.OpStatusPending,
+		ReceivedAt: responseData.ReceivedAt,
+		Error:      responseData.Error,
+	}
+	switch responseData.Status {
+	case messageDelivered:
+		status.Status = fftypes.OpStatusSucceeded
+	case messageFailed:
+		status.Status = fftypes.OpStatusFailed
+	}
+	return status, nil
+}
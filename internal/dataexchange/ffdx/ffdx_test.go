@@ -0,0 +1,140 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ffdx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/restclient"
+	"github.com/hyperledger/firefly/internal/wsclient"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var utConfPrefix = config.NewPluginConfig("ffdx_unit_tests")
+
+func newTestFFDX(t *testing.T) (f *FFDX, httpURL string, done func()) {
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+
+	_, _, wsURL, cancel := wsclient.NewTestWSServer(nil)
+
+	u, _ := url.Parse(wsURL)
+	u.Scheme = "http"
+	httpURL = u.String()
+
+	config.Reset()
+	f = &FFDX{}
+	f.InitPrefix(utConfPrefix)
+	utConfPrefix.Set(restclient.HTTPConfigURL, httpURL)
+	utConfPrefix.Set(restclient.HTTPCustomClient, mockedClient)
+
+	err := f.Init(context.Background(), utConfPrefix, &dataexchangemocks.Callbacks{})
+	assert.NoError(t, err)
+	assert.Equal(t, "ffdx", f.Name())
+	return f, httpURL, func() {
+		cancel()
+		httpmock.DeactivateAndReset()
+	}
+}
+
+func TestInitMissingURL(t *testing.T) {
+	config.Reset()
+	f := &FFDX{}
+	f.InitPrefix(utConfPrefix)
+	err := f.Init(context.Background(), utConfPrefix, &dataexchangemocks.Callbacks{})
+	assert.Regexp(t, "FF10138", err)
+}
+
+func TestGetMessageStatus(t *testing.T) {
+
+	f, httpURL, done := newTestFFDX(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/messages/delivered1", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"status":     "message-delivered",
+			"receivedAt": "2021-11-01T00:00:00Z",
+		}))
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/messages/failed1", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"status": "message-failed",
+			"error":  "pop",
+		}))
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/messages/pending1", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"status": "message-sent",
+		}))
+
+	status, err := f.GetMessageStatus(context.Background(), "delivered1")
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.OpStatusSucceeded, status.Status)
+	assert.Equal(t, "2021-11-01T00:00:00Z", status.ReceivedAt.String())
+
+	status, err = f.GetMessageStatus(context.Background(), "failed1")
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.OpStatusFailed, status.Status)
+	assert.Equal(t, "pop", status.Error)
+
+	status, err = f.GetMessageStatus(context.Background(), "pending1")
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.OpStatusPending, status.Status)
+}
+
+func TestGetMessageStatusNotFound(t *testing.T) {
+	f, httpURL, done := newTestFFDX(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/messages/unknown", httpURL),
+		httpmock.NewJsonResponderOrPanic(404, fftypes.JSONObject{}))
+
+	status, err := f.GetMessageStatus(context.Background(), "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+}
+
+func TestGetMessageStatusError(t *testing.T) {
+	f, httpURL, done := newTestFFDX(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/messages/abcd1234", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	_, err := f.GetMessageStatus(context.Background(), "abcd1234")
+	assert.Regexp(t, "FF10229", err)
+}
+
+func TestGetMessageStatusEscapesTrackingID(t *testing.T) {
+	f, httpURL, done := newTestFFDX(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/messages/a%%2Fb%%3Fc", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"status": "message-delivered",
+		}))
+
+	status, err := f.GetMessageStatus(context.Background(), "a/b?c")
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.OpStatusSucceeded, status.Status)
+}
@@ -35,6 +35,12 @@ const (
 	aggregatorOffsetName = "ff_aggregator"
 )
 
+// msgGroupKey identifies a message within one of the groups it was pinned in, by the index of the group
+type msgGroupKey struct {
+	msg   fftypes.UUID
+	group int
+}
+
 type aggregator struct {
 	ctx             context.Context
 	database        database.Plugin
@@ -164,7 +170,8 @@ func (ag *aggregator) processPins(ctx context.Context, pins []*fftypes.Pin) (err
 	var batch *fftypes.Batch
 	// As messages can have multiple topics, we need to avoid processing the message twice in the same poll loop.
 	// We must check all the contexts in the message, and mark them dispatched together.
-	dupMsgCheck := make(map[fftypes.UUID]bool)
+	// Messages delivered to additional groups are processed once for each group they are pinned in.
+	dupMsgCheck := make(map[msgGroupKey]bool)
 	for _, pin := range pins {
 		l.Debugf("Aggregating pin %.10d batch=%s hash=%s masked=%t", pin.Sequence, pin.Batch, pin.Hash, pin.Masked)
 
@@ -179,17 +186,24 @@ func (ag *aggregator) processPins(ctx context.Context, pins []*fftypes.Pin) (err
 			}
 		}
 
+		// Pins beyond the message-topics of a private batch are for the additional groups it is delivered to,
+		// with a pin for every message-topic in each of those groups in turn
+		index, groupIdx := pin.Index, 0
+		if pin.Masked {
+			index, groupIdx = ag.groupPinIndex(batch, pin.Index)
+		}
+
 		// Extract the message from the batch - where the index is of a topic within a message
 		var msg *fftypes.Message
 		var i int64 = -1
-		for iM := 0; i < pin.Index && iM < len(batch.Payload.Messages); iM++ {
+		for iM := 0; i < index && iM < len(batch.Payload.Messages); iM++ {
 			msg = batch.Payload.Messages[iM]
-			for iT := 0; i < pin.Index && iT < len(msg.Header.Topics); iT++ {
+			for iT := 0; i < index && iT < len(msg.Header.Topics); iT++ {
 				i++
 			}
 		}
 
-		if i < pin.Index {
+		if i < index {
 			l.Errorf("Batch %s does not have message-topic index %d - pin %s is invalid", pin.Batch, pin.Index, pin.Hash)
 			continue
 		}
@@ -198,13 +212,14 @@ func (ag *aggregator) processPins(ctx context.Context, pins []*fftypes.Pin) (err
 			l.Errorf("null message entry %d in batch '%s'", pin.Index, batch.ID)
 			continue
 		}
-		if dupMsgCheck[*msg.Header.ID] {
+		dupKey := msgGroupKey{msg: *msg.Header.ID, group: groupIdx}
+		if dupMsgCheck[dupKey] {
 			continue
 		}
-		dupMsgCheck[*msg.Header.ID] = true
+		dupMsgCheck[dupKey] = true
 
 		// Attempt to process the message (only returns errors for database persistence issues)
-		if err = ag.processMessage(ctx, batch, pin.Masked, pin.Sequence, msg); err != nil {
+		if err = ag.processMessage(ctx, batch, pin.Masked, pin.Sequence, msg, groupIdx); err != nil {
 			return err
 		}
 	}
@@ -213,6 +228,24 @@ func (ag *aggregator) processPins(ctx context.Context, pins []*fftypes.Pin) (err
 	return err
 }
 
+// groupPinIndex splits the index of a pin in a private batch into the message-topic index, and the
+// index of the group it was pinned in - where zero is the message group, and later indexes are the
+// additional groups of the batch. Indexes beyond the last group are returned unchanged, as invalid.
+func (ag *aggregator) groupPinIndex(batch *fftypes.Batch, index int64) (int64, int) {
+	var topicCount int64
+	for _, msg := range batch.Payload.Messages {
+		topicCount += int64(len(msg.Header.Topics))
+	}
+	if topicCount == 0 || index < topicCount {
+		return index, 0
+	}
+	groupIdx := index / topicCount
+	if groupIdx > int64(len(batch.AdditionalGroups())) {
+		return index, 0
+	}
+	return index % topicCount, int(groupIdx)
+}
+
 func (ag *aggregator) calcHash(topic string, groupID *fftypes.Bytes32, identity string, nonce int64) *fftypes.Bytes32 {
 	h := sha256.New()
 	h.Write([]byte(topic))
@@ -224,26 +257,42 @@ func (ag *aggregator) calcHash(topic string, groupID *fftypes.Bytes32, identity
 	return fftypes.HashResult(h)
 }
 
-func (ag *aggregator) processMessage(ctx context.Context, batch *fftypes.Batch, masked bool, pinnedSequence int64, msg *fftypes.Message) (err error) {
+func (ag *aggregator) processMessage(ctx context.Context, batch *fftypes.Batch, masked bool, pinnedSequence int64, msg *fftypes.Message, groupIdx int) (err error) {
 	l := log.L(ctx)
 
 	// Check if it's ready to be processed
-	nextPins := make([]*fftypes.NextPin, len(msg.Pins))
+	additionalGroups := batch.AdditionalGroups()
+	group := msg.Header.Group
+	if groupIdx > 0 {
+		group = additionalGroups[groupIdx-1]
+	}
+	nextPins := make([]*fftypes.NextPin, len(msg.Header.Topics))
 	if masked {
 		// Private messages have one or more masked "pin" hashes that allow us to work
-		// out if it's the next message in the sequence, given the previous messages
-		if msg.Header.Group == nil || len(msg.Pins) == 0 || len(msg.Header.Topics) != len(msg.Pins) {
+		// out if it's the next message in the sequence, given the previous messages.
+		// There is a pin for each topic, in the message group and then each additional group.
+		if msg.Header.Group == nil || len(msg.Pins) == 0 || len(msg.Header.Topics)*(1+len(additionalGroups)) != len(msg.Pins) {
 			log.L(ctx).Errorf("Message '%s' in batch '%s' has invalid pin data pins=%v topics=%v", msg.Header.ID, batch.ID, msg.Pins, msg.Header.Topics)
 			return nil
 		}
-		for i, pinStr := range msg.Pins {
+		if groupIdx > 0 {
+			// A message is only delivered through an additional group if the author is a member of that group
+			if isMember, err := ag.isGroupMember(ctx, group, msg.Header.Author); err != nil || !isMember {
+				if err == nil {
+					l.Errorf("Message '%s' in batch '%s' author '%s' is not a member of additional group '%s'", msg.Header.ID, batch.ID, msg.Header.Author, group)
+				}
+				return err
+			}
+		}
+		groupPins := msg.Pins[groupIdx*len(msg.Header.Topics) : (groupIdx+1)*len(msg.Header.Topics)]
+		for i, pinStr := range groupPins {
 			var pin fftypes.Bytes32
 			err := pin.UnmarshalText([]byte(pinStr))
 			if err != nil {
 				log.L(ctx).Errorf("Message '%s' in batch '%s' has invalid pin at index %d: '%s'", msg.Header.ID, batch.ID, i, pinStr)
 				return nil
 			}
-			nextPin, err := ag.checkMaskedContextReady(ctx, msg, msg.Header.Topics[i], pinnedSequence, &pin)
+			nextPin, err := ag.checkMaskedContextReady(ctx, msg, group, msg.Header.Topics[i], pinnedSequence, &pin)
 			if err != nil || nextPin == nil {
 				return err
 			}
@@ -273,9 +322,21 @@ func (ag *aggregator) processMessage(ctx context.Context, batch *fftypes.Batch,
 		}
 	}
 
-	dispatched, err := ag.attemptMessageDispatch(ctx, msg)
-	if err != nil || !dispatched {
-		return err
+	// A message delivered to additional groups is dispatched once, when it is first ready in any of its groups.
+	// Its pins in the other groups must still move those groups forwards, or later messages would be blocked.
+	alreadyDispatched := false
+	if masked && len(additionalGroups) > 0 {
+		existing, err := ag.database.GetMessageByID(ctx, msg.Header.ID)
+		if err != nil {
+			return err
+		}
+		alreadyDispatched = existing != nil && existing.Confirmed != nil
+	}
+	if !alreadyDispatched {
+		dispatched, err := ag.attemptMessageDispatch(ctx, msg)
+		if err != nil || !dispatched {
+			return err
+		}
 	}
 
 	// Move the nextPin forwards to the next sequence for this sender, on all
@@ -283,7 +344,7 @@ func (ag *aggregator) processMessage(ctx context.Context, batch *fftypes.Batch,
 	if masked {
 		for i, nextPin := range nextPins {
 			nextPin.Nonce++
-			nextPin.Hash = ag.calcHash(msg.Header.Topics[i], msg.Header.Group, nextPin.Identity, nextPin.Nonce)
+			nextPin.Hash = ag.calcHash(msg.Header.Topics[i], group, nextPin.Identity, nextPin.Nonce)
 			if err = ag.database.UpdateNextPin(ctx, nextPin.Sequence, database.NextPinQueryFactory.NewUpdate(ctx).
 				Set("nonce", nextPin.Nonce).
 				Set("hash", nextPin.Hash),
@@ -297,7 +358,16 @@ func (ag *aggregator) processMessage(ctx context.Context, batch *fftypes.Batch,
 	return ag.database.SetPinDispatched(ctx, pinnedSequence)
 }
 
-func (ag *aggregator) checkMaskedContextReady(ctx context.Context, msg *fftypes.Message, topic string, pinnedSequence int64, pin *fftypes.Bytes32) (*fftypes.NextPin, error) {
+// isGroupMember returns true if the identity is a member of a group known to this node
+func (ag *aggregator) isGroupMember(ctx context.Context, groupHash *fftypes.Bytes32, identity string) (bool, error) {
+	group, err := ag.database.GetGroupByHash(ctx, groupHash)
+	if err != nil || group == nil {
+		return false, err
+	}
+	return group.Members.Contains(identity), nil
+}
+
+func (ag *aggregator) checkMaskedContextReady(ctx context.Context, msg *fftypes.Message, group *fftypes.Bytes32, topic string, pinnedSequence int64, pin *fftypes.Bytes32) (*fftypes.NextPin, error) {
 	l := log.L(ctx)
 
 	// For masked pins, we can only process if:
@@ -305,20 +375,20 @@ func (ag *aggregator) checkMaskedContextReady(ctx context.Context, msg *fftypes.
 	// - there are no undispatched messages on this context earlier in the stream
 	h := sha256.New()
 	h.Write([]byte(topic))
-	h.Write((*msg.Header.Group)[:])
+	h.Write((*group)[:])
 	contextUnmasked := fftypes.HashResult(h)
 	filter := database.NextPinQueryFactory.NewFilter(ctx).Eq("context", contextUnmasked)
 	nextPins, _, err := ag.database.GetNextPins(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
-	l.Debugf("Group=%s Topic='%s' NextPins=%v Sequence=%d Pin=%s NextPins=%v", msg.Header.Group, topic, nextPins, pinnedSequence, pin, nextPins)
+	l.Debugf("Group=%s Topic='%s' NextPins=%v Sequence=%d Pin=%s NextPins=%v", group, topic, nextPins, pinnedSequence, pin, nextPins)
 
 	if len(nextPins) == 0 {
 		// If this is the first time we've seen the context, then this message is read as long as it is
 		// the first (nonce=0) message on the context, for one of the members, and there aren't any earlier
 		// messages that are nonce=0.
-		return ag.attemptContextInit(ctx, msg, topic, pinnedSequence, contextUnmasked, pin)
+		return ag.attemptContextInit(ctx, msg, group, topic, pinnedSequence, contextUnmasked, pin)
 	}
 
 	// This message must be the next hash for the author
@@ -330,17 +400,28 @@ func (ag *aggregator) checkMaskedContextReady(ctx context.Context, msg *fftypes.
 		}
 	}
 	if nextPin == nil || nextPin.Identity != msg.Header.Author {
-		l.Debugf("Mismatched nexthash or author group=%s topic=%s context=%s pin=%s nextHash=%+v", msg.Header.Group, topic, contextUnmasked, pin, nextPin)
+		l.Debugf("Mismatched nexthash or author group=%s topic=%s context=%s pin=%s nextHash=%+v", group, topic, contextUnmasked, pin, nextPin)
 		return nil, nil
 	}
 	return nextPin, nil
 }
 
-func (ag *aggregator) attemptContextInit(ctx context.Context, msg *fftypes.Message, topic string, pinnedSequence int64, contextUnmasked, pin *fftypes.Bytes32) (*fftypes.NextPin, error) {
+func (ag *aggregator) attemptContextInit(ctx context.Context, msg *fftypes.Message, groupHash *fftypes.Bytes32, topic string, pinnedSequence int64, contextUnmasked, pin *fftypes.Bytes32) (*fftypes.NextPin, error) {
 	l := log.L(ctx)
 
-	// It might be the system topic/context initializing the group
-	group, err := ag.syshandlers.ResolveInitGroup(ctx, msg)
+	var group *fftypes.Group
+	var err error
+	if *groupHash == *msg.Header.Group {
+		// It might be the system topic/context initializing the group
+		var valid bool
+		group, valid, err = ag.syshandlers.ResolveInitGroup(ctx, msg)
+		if err == nil && !valid {
+			return nil, ag.rejectGroupInit(ctx, msg, pinnedSequence)
+		}
+	} else {
+		// An additional group the message is delivered to must already be known
+		group, err = ag.database.GetGroupByHash(ctx, groupHash)
+	}
 	if err != nil || group == nil {
 		return nil, err
 	}
@@ -350,7 +431,7 @@ func (ag *aggregator) attemptContextInit(ctx context.Context, msg *fftypes.Messa
 	var nextPin *fftypes.NextPin
 	nextPins := make([]*fftypes.NextPin, len(group.Members))
 	for i, member := range group.Members {
-		zeroHash := ag.calcHash(topic, msg.Header.Group, member.Identity, 0)
+		zeroHash := ag.calcHash(topic, groupHash, member.Identity, 0)
 		np := &fftypes.NextPin{
 			Context:  contextUnmasked,
 			Identity: member.Identity,
@@ -359,7 +440,7 @@ func (ag *aggregator) attemptContextInit(ctx context.Context, msg *fftypes.Messa
 		}
 		if *pin == *zeroHash {
 			if member.Identity != msg.Header.Author {
-				l.Warnf("Author mismatch for zerohash on context: group=%s topic=%s context=%s pin=%s", msg.Header.Group, topic, contextUnmasked, pin)
+				l.Warnf("Author mismatch for zerohash on context: group=%s topic=%s context=%s pin=%s", groupHash, topic, contextUnmasked, pin)
 				return nil, nil
 			}
 			nextPin = np
@@ -367,9 +448,9 @@ func (ag *aggregator) attemptContextInit(ctx context.Context, msg *fftypes.Messa
 		zeroHashes[i] = zeroHash
 		nextPins[i] = np
 	}
-	l.Debugf("Group=%s topic=%s context=%s zeroHashes=%v", msg.Header.Group, topic, contextUnmasked, zeroHashes)
+	l.Debugf("Group=%s topic=%s context=%s zeroHashes=%v", groupHash, topic, contextUnmasked, zeroHashes)
 	if nextPin == nil {
-		l.Warnf("No match for zerohash on context: group=%s topic=%s context=%s pin=%s", msg.Header.Group, topic, contextUnmasked, pin)
+		l.Warnf("No match for zerohash on context: group=%s topic=%s context=%s pin=%s", groupHash, topic, contextUnmasked, pin)
 		return nil, nil
 	}
 
@@ -385,7 +466,7 @@ func (ag *aggregator) attemptContextInit(ctx context.Context, msg *fftypes.Messa
 		return nil, err
 	}
 	if len(earlier) > 0 {
-		l.Debugf("Group=%s topic=%s context=%s earlier=%v", msg.Header.Group, topic, contextUnmasked, earlier)
+		l.Debugf("Group=%s topic=%s context=%s earlier=%v", groupHash, topic, contextUnmasked, earlier)
 		return nil, nil
 	}
 
@@ -399,6 +480,24 @@ func (ag *aggregator) attemptContextInit(ctx context.Context, msg *fftypes.Messa
 	return nextPin, err
 }
 
+// rejectGroupInit confirms a group init message that failed validation as rejected, so the rejection is
+// visible to applications as an event, rather than the message staying pending
+func (ag *aggregator) rejectGroupInit(ctx context.Context, msg *fftypes.Message, pinnedSequence int64) error {
+	setRejected := database.MessageQueryFactory.NewUpdate(ctx).
+		Set("pending", false).
+		Set("confirmed", fftypes.Now()).
+		Set("rejected", true)
+	if err := ag.database.UpdateMessage(ctx, msg.Header.ID, setRejected); err != nil {
+		return err
+	}
+	event := fftypes.NewEvent(fftypes.EventTypeMessageRejected, msg.Header.Namespace, msg.Header.ID)
+	if err := ag.database.InsertEvent(ctx, event); err != nil {
+		return err
+	}
+	log.L(ctx).Infof("Emitting %s for group init message %s:%s", fftypes.EventTypeMessageRejected, msg.Header.Namespace, msg.Header.ID)
+	return ag.database.SetPinDispatched(ctx, pinnedSequence)
+}
+
 func (ag *aggregator) attemptMessageDispatch(ctx context.Context, msg *fftypes.Message) (bool, error) {
 
 	// If we don't find all the data, then we don't dispatch
@@ -416,6 +515,11 @@ func (ag *aggregator) attemptMessageDispatch(ctx context.Context, msg *fftypes.M
 	valid := true
 	eventType := fftypes.EventTypeMessageConfirmed
 	switch {
+	case msg.Header.Type == fftypes.MessageTypeTokenPoolDefinition:
+		// Token pool definitions have a dedicated handler, but are otherwise processed in-line like system events
+		if valid, err = ag.syshandlers.HandleTokenPoolDefinition(ctx, msg, data); err != nil {
+			return false, err
+		}
 	case msg.Header.Namespace == fftypes.SystemNamespace:
 		// We handle system events in-line on the aggregator, as it would be confusing for apps to be
 		// dispatched subsequent events before we have processed the system events they depend on.
@@ -437,7 +541,8 @@ func (ag *aggregator) attemptMessageDispatch(ctx context.Context, msg *fftypes.M
 	setConfirmed := database.MessageQueryFactory.NewUpdate(ctx).
 		Set("pending", false).           // the sequence is locked
 		Set("confirmed", fftypes.Now()). // the timestamp of the aggregator provides ordering
-		Set("rejected", !valid)          // mark if the message was not accepted
+		Set("rejected", !valid).         // mark if the message was not accepted
+		Set("error", "")                 // clear any error recorded while waiting (such as a send timeout)
 	err = ag.database.UpdateMessage(ctx, msg.Header.ID, setConfirmed)
 	if err != nil {
 		return false, err
@@ -95,7 +95,7 @@ func TestAggregationMaskedZeroNonceMatch(t *testing.T) {
 				{Identity: member2},
 			},
 		},
-	}, nil)
+	}, true, nil)
 	// Look for any earlier pins - none found
 	mdi.On("GetPins", ag.ctx, mock.Anything).Return([]*fftypes.Pin{}, nil, nil).Once()
 	// Insert all the zero pins
@@ -135,7 +135,7 @@ func TestAggregationMaskedZeroNonceMatch(t *testing.T) {
 	mdi.On("UpdateMessage", ag.ctx, mock.Anything, mock.MatchedBy(func(u database.Update) bool {
 		update, err := u.Finalize()
 		assert.NoError(t, err)
-		assert.Len(t, update.SetOperations, 3)
+		assert.Len(t, update.SetOperations, 4)
 
 		assert.Equal(t, "pending", update.SetOperations[0].Field)
 		v, err := update.SetOperations[0].Value.Value()
@@ -152,6 +152,11 @@ func TestAggregationMaskedZeroNonceMatch(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, false, v)
 
+		assert.Equal(t, "error", update.SetOperations[3].Field)
+		v, err = update.SetOperations[3].Value.Value()
+		assert.NoError(t, err)
+		assert.Equal(t, "", v)
+
 		return true
 	})).Return(nil)
 	// Confirm the offset
@@ -264,6 +269,226 @@ func TestAggregationMaskedNextSequenceMatch(t *testing.T) {
 	mdm.AssertExpectations(t)
 }
 
+func TestAggregationMaskedAdditionalGroups(t *testing.T) {
+
+	ag, cancel := newTestAggregator()
+	defer cancel()
+
+	// A message pinned in its own group, and two additional groups
+	member1 := "0x12345"
+	member2 := "0x23456"
+	topic := "some-topic"
+	batchID := fftypes.NewUUID()
+	group1 := fftypes.NewRandB32()
+	group2 := fftypes.NewRandB32()
+	group3 := fftypes.NewRandB32()
+	msgID := fftypes.NewUUID()
+	group1NonceFive := ag.calcHash(topic, group1, member1, 5)
+	group2NonceTwo := ag.calcHash(topic, group2, member1, 2)
+	group3NonceZero := ag.calcHash(topic, group3, member1, 0)
+
+	mdi := ag.database.(*databasemocks.Plugin)
+	mdm := ag.data.(*datamocks.Manager)
+
+	mdi.On("GetBatchByID", ag.ctx, batchID).Return(&fftypes.Batch{
+		ID:    batchID,
+		Group: group1,
+		Payload: fftypes.BatchPayload{
+			Messages: []*fftypes.Message{
+				{
+					Header: fftypes.MessageHeader{
+						ID:     msgID,
+						Group:  group1,
+						Topics: []string{topic},
+						Author: member1,
+					},
+					Pins: []string{group1NonceFive.String(), group2NonceTwo.String(), group3NonceZero.String()},
+				},
+			},
+			Groups: fftypes.GroupRefs{{Hash: group2}, {Hash: group3}},
+		},
+	}, nil)
+	// The message is next in the first two groups, and the first on the context in the third
+	// The author is a member of each of the additional groups
+	for _, g := range []*fftypes.Bytes32{group2, group3} {
+		mdi.On("GetGroupByHash", ag.ctx, g).Return(&fftypes.Group{
+			GroupIdentity: fftypes.GroupIdentity{
+				Members: fftypes.Members{
+					{Identity: member1},
+					{Identity: member2},
+				},
+			},
+		}, nil)
+	}
+	mdi.On("GetNextPins", ag.ctx, mock.Anything).Return([]*fftypes.NextPin{
+		{Sequence: 10011, Identity: member1, Hash: group1NonceFive, Nonce: 5},
+	}, nil, nil).Once()
+	mdi.On("GetNextPins", ag.ctx, mock.Anything).Return([]*fftypes.NextPin{
+		{Sequence: 10021, Identity: member1, Hash: group2NonceTwo, Nonce: 2},
+	}, nil, nil).Once()
+	mdi.On("GetNextPins", ag.ctx, mock.Anything).Return([]*fftypes.NextPin{}, nil, nil).Once()
+	mdi.On("GetPins", ag.ctx, mock.Anything).Return([]*fftypes.Pin{}, nil, nil).Once()
+	mdi.On("InsertNextPin", ag.ctx, mock.MatchedBy(func(np *fftypes.NextPin) bool {
+		np.Sequence = 10031
+		return *np.Hash == *group3NonceZero
+	})).Return(nil).Once()
+	mdi.On("InsertNextPin", ag.ctx, mock.MatchedBy(func(np *fftypes.NextPin) bool {
+		return np.Identity == member2
+	})).Return(nil).Once()
+	// The message is only dispatched once, in the first group
+	mdi.On("GetMessageByID", ag.ctx, msgID).Return(&fftypes.Message{}, nil).Once()
+	mdi.On("GetMessageByID", ag.ctx, msgID).Return(&fftypes.Message{Confirmed: fftypes.Now()}, nil).Twice()
+	mdm.On("GetMessageData", ag.ctx, mock.Anything, true).Return([]*fftypes.Data{}, true, nil).Once()
+	mdi.On("UpdateMessage", ag.ctx, msgID, mock.Anything).Return(nil).Once()
+	mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *fftypes.Event) bool {
+		return *e.Reference == *msgID && e.Type == fftypes.EventTypeMessageConfirmed
+	})).Return(nil).Once()
+	// Each group moves on to the next nonce
+	for seq, nextHash := range map[int64]*fftypes.Bytes32{
+		10011: ag.calcHash(topic, group1, member1, 6),
+		10021: ag.calcHash(topic, group2, member1, 3),
+		10031: ag.calcHash(topic, group3, member1, 1),
+	} {
+		expectedHash := nextHash.String()
+		mdi.On("UpdateNextPin", ag.ctx, seq, mock.MatchedBy(func(update database.Update) bool {
+			ui, _ := update.Finalize()
+			v, _ := ui.SetOperations[1].Value.Value()
+			return v == expectedHash
+		})).Return(nil).Once()
+	}
+	mdi.On("SetPinDispatched", ag.ctx, int64(10001)).Return(nil)
+	mdi.On("SetPinDispatched", ag.ctx, int64(10002)).Return(nil)
+	mdi.On("SetPinDispatched", ag.ctx, int64(10003)).Return(nil)
+	mdi.On("UpdateOffset", ag.ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := ag.processPins(ag.ctx, []*fftypes.Pin{
+		{Sequence: 10001, Masked: true, Hash: group1NonceFive, Batch: batchID, Index: 0},
+		{Sequence: 10002, Masked: true, Hash: group2NonceTwo, Batch: batchID, Index: 1},
+		{Sequence: 10003, Masked: true, Hash: group3NonceZero, Batch: batchID, Index: 2},
+	})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestProcessPinsAdditionalGroupIndexInvalid(t *testing.T) {
+	ag, cancel := newTestAggregator()
+	defer cancel()
+
+	mdi := ag.database.(*databasemocks.Plugin)
+	mdi.On("GetBatchByID", ag.ctx, mock.Anything).Return(&fftypes.Batch{
+		ID:    fftypes.NewUUID(),
+		Group: fftypes.NewRandB32(),
+		Payload: fftypes.BatchPayload{
+			Messages: []*fftypes.Message{
+				{Header: fftypes.MessageHeader{
+					ID:     fftypes.NewUUID(),
+					Topics: fftypes.FFNameArray{"topic1"},
+				}},
+			},
+			Groups: fftypes.GroupRefs{{Hash: fftypes.NewRandB32()}},
+		},
+	}, nil)
+	mdi.On("UpdateOffset", ag.ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := ag.processPins(ag.ctx, []*fftypes.Pin{
+		{Sequence: 12345, Masked: true, Batch: fftypes.NewUUID(), Index: 2, Hash: fftypes.NewRandB32()},
+	})
+	assert.NoError(t, err)
+	mdi.AssertExpectations(t)
+}
+
+func TestProcessMsgAdditionalGroupAuthorNotMember(t *testing.T) {
+	ag, cancel := newTestAggregator()
+	defer cancel()
+	group := fftypes.NewRandB32()
+	pin := ag.calcHash("topic1", group, "author1", 0)
+
+	mdi := ag.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", ag.ctx, group).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Members: fftypes.Members{{Identity: "member1"}},
+		},
+	}, nil)
+
+	err := ag.processMessage(ag.ctx, &fftypes.Batch{
+		Group:   fftypes.NewRandB32(),
+		Payload: fftypes.BatchPayload{Groups: fftypes.GroupRefs{{Hash: group}}},
+	}, true, 12345, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Group:  fftypes.NewRandB32(),
+			Topics: fftypes.FFNameArray{"topic1"},
+			Author: "author1",
+		},
+		Pins: fftypes.FFNameArray{fftypes.NewRandB32().String(), pin.String()},
+	}, 1)
+	assert.NoError(t, err)
+	// The message is not dispatched, and its pin is not marked dispatched
+	mdi.AssertExpectations(t)
+	mdi.AssertNotCalled(t, "GetNextPins", mock.Anything, mock.Anything)
+	mdi.AssertNotCalled(t, "SetPinDispatched", mock.Anything, mock.Anything)
+
+}
+
+func TestProcessMsgAdditionalGroupMemberCheckFail(t *testing.T) {
+	ag, cancel := newTestAggregator()
+	defer cancel()
+	group := fftypes.NewRandB32()
+
+	mdi := ag.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", ag.ctx, group).Return(nil, fmt.Errorf("pop"))
+
+	err := ag.processMessage(ag.ctx, &fftypes.Batch{
+		Group:   fftypes.NewRandB32(),
+		Payload: fftypes.BatchPayload{Groups: fftypes.GroupRefs{{Hash: group}}},
+	}, true, 12345, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Group:  fftypes.NewRandB32(),
+			Topics: fftypes.FFNameArray{"topic1"},
+			Author: "author1",
+		},
+		Pins: fftypes.FFNameArray{fftypes.NewRandB32().String(), fftypes.NewRandB32().String()},
+	}, 1)
+	assert.EqualError(t, err, "pop")
+
+}
+
+func TestProcessMsgAdditionalGroupGetMessageFail(t *testing.T) {
+	ag, cancel := newTestAggregator()
+	defer cancel()
+	group := fftypes.NewRandB32()
+	pin := ag.calcHash("topic1", group, "author1", 0)
+
+	mdi := ag.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", ag.ctx, group).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Members: fftypes.Members{{Identity: "author1"}},
+		},
+	}, nil)
+	mdi.On("GetNextPins", ag.ctx, mock.Anything).Return([]*fftypes.NextPin{
+		{Identity: "author1", Hash: pin},
+	}, nil, nil)
+	mdi.On("GetMessageByID", ag.ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	err := ag.processMessage(ag.ctx, &fftypes.Batch{
+		Group:   fftypes.NewRandB32(),
+		Payload: fftypes.BatchPayload{Groups: fftypes.GroupRefs{{Hash: group}}},
+	}, true, 12345, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Group:  fftypes.NewRandB32(),
+			Topics: fftypes.FFNameArray{"topic1"},
+			Author: "author1",
+		},
+		Pins: fftypes.FFNameArray{fftypes.NewRandB32().String(), pin.String()},
+	}, 1)
+	assert.EqualError(t, err, "pop")
+
+}
+
 func TestAggregationBroadcast(t *testing.T) {
 
 	ag, cancel := newTestAggregator()
@@ -507,7 +732,7 @@ func TestProcessMsgFailMissingGroup(t *testing.T) {
 	ag, cancel := newTestAggregator()
 	defer cancel()
 
-	err := ag.processMessage(ag.ctx, &fftypes.Batch{}, true, 12345, &fftypes.Message{})
+	err := ag.processMessage(ag.ctx, &fftypes.Batch{}, true, 12345, &fftypes.Message{}, 0)
 	assert.NoError(t, err)
 
 }
@@ -523,7 +748,7 @@ func TestProcessMsgFailBadPin(t *testing.T) {
 			Topics: fftypes.FFNameArray{"topic1"},
 		},
 		Pins: fftypes.FFNameArray{"!Wrong"},
-	})
+	}, 0)
 	assert.NoError(t, err)
 
 }
@@ -542,7 +767,7 @@ func TestProcessMsgFailGetNextPins(t *testing.T) {
 			Topics: fftypes.FFNameArray{"topic1"},
 		},
 		Pins: fftypes.FFNameArray{fftypes.NewRandB32().String()},
-	})
+	}, 0)
 	assert.EqualError(t, err, "pop")
 
 }
@@ -562,7 +787,7 @@ func TestProcessMsgFailDispatch(t *testing.T) {
 			Topics: fftypes.FFNameArray{"topic1"},
 		},
 		Pins: fftypes.FFNameArray{fftypes.NewRandB32().String()},
-	})
+	}, 0)
 	assert.EqualError(t, err, "pop")
 
 }
@@ -590,7 +815,7 @@ func TestProcessMsgFailPinUpdate(t *testing.T) {
 			Topics: fftypes.FFNameArray{"topic1"},
 		},
 		Pins: fftypes.FFNameArray{pin.String()},
-	})
+	}, 0)
 	assert.EqualError(t, err, "pop")
 
 }
@@ -598,6 +823,8 @@ func TestProcessMsgFailPinUpdate(t *testing.T) {
 func TestCheckMaskedContextReadyMismatchedAuthor(t *testing.T) {
 	ag, cancel := newTestAggregator()
 	defer cancel()
+
+	groupID := fftypes.NewRandB32()
 	pin := fftypes.NewRandB32()
 
 	mdi := ag.database.(*databasemocks.Plugin)
@@ -608,10 +835,10 @@ func TestCheckMaskedContextReadyMismatchedAuthor(t *testing.T) {
 	_, err := ag.checkMaskedContextReady(ag.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:     fftypes.NewUUID(),
-			Group:  fftypes.NewRandB32(),
+			Group:  groupID,
 			Author: "author1",
 		},
-	}, "topic1", 12345, fftypes.NewRandB32())
+	}, groupID, "topic1", 12345, fftypes.NewRandB32())
 	assert.NoError(t, err)
 
 }
@@ -620,16 +847,18 @@ func TestAttemptContextInitGetGroupByIDFail(t *testing.T) {
 	ag, cancel := newTestAggregator()
 	defer cancel()
 
+	groupID := fftypes.NewRandB32()
+
 	msh := ag.syshandlers.(*syshandlersmocks.SystemHandlers)
-	msh.On("ResolveInitGroup", ag.ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	msh.On("ResolveInitGroup", ag.ctx, mock.Anything).Return(nil, false, fmt.Errorf("pop"))
 
 	_, err := ag.attemptContextInit(ag.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:     fftypes.NewUUID(),
-			Group:  fftypes.NewRandB32(),
+			Group:  groupID,
 			Author: "author1",
 		},
-	}, "topic1", 12345, fftypes.NewRandB32(), fftypes.NewRandB32())
+	}, groupID, "topic1", 12345, fftypes.NewRandB32(), fftypes.NewRandB32())
 	assert.EqualError(t, err, "pop")
 
 }
@@ -638,20 +867,97 @@ func TestAttemptContextInitGroupNotFound(t *testing.T) {
 	ag, cancel := newTestAggregator()
 	defer cancel()
 
+	groupID := fftypes.NewRandB32()
+
 	msh := ag.syshandlers.(*syshandlersmocks.SystemHandlers)
-	msh.On("ResolveInitGroup", ag.ctx, mock.Anything).Return(nil, nil)
+	msh.On("ResolveInitGroup", ag.ctx, mock.Anything).Return(nil, true, nil)
 
 	_, err := ag.attemptContextInit(ag.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:     fftypes.NewUUID(),
-			Group:  fftypes.NewRandB32(),
+			Group:  groupID,
 			Author: "author1",
 		},
-	}, "topic1", 12345, fftypes.NewRandB32(), fftypes.NewRandB32())
+	}, groupID, "topic1", 12345, fftypes.NewRandB32(), fftypes.NewRandB32())
 	assert.NoError(t, err)
 
 }
 
+func TestAttemptContextInitGroupRejected(t *testing.T) {
+	ag, cancel := newTestAggregator()
+	defer cancel()
+
+	groupID := fftypes.NewRandB32()
+	msgID := fftypes.NewUUID()
+
+	msh := ag.syshandlers.(*syshandlersmocks.SystemHandlers)
+	msh.On("ResolveInitGroup", ag.ctx, mock.Anything).Return(nil, false, nil)
+	mdi := ag.database.(*databasemocks.Plugin)
+	mdi.On("UpdateMessage", ag.ctx, msgID, mock.Anything).Return(nil)
+	mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(event *fftypes.Event) bool {
+		return event.Type == fftypes.EventTypeMessageRejected && *event.Reference == *msgID
+	})).Return(nil)
+	mdi.On("SetPinDispatched", ag.ctx, int64(12345)).Return(nil)
+
+	np, err := ag.attemptContextInit(ag.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        msgID,
+			Namespace: "ns1",
+			Group:     groupID,
+			Author:    "author1",
+		},
+	}, groupID, "topic1", 12345, fftypes.NewRandB32(), fftypes.NewRandB32())
+	assert.NoError(t, err)
+	assert.Nil(t, np)
+	mdi.AssertExpectations(t)
+
+}
+
+func TestAttemptContextInitGroupRejectedUpdateFail(t *testing.T) {
+	ag, cancel := newTestAggregator()
+	defer cancel()
+
+	groupID := fftypes.NewRandB32()
+
+	msh := ag.syshandlers.(*syshandlersmocks.SystemHandlers)
+	msh.On("ResolveInitGroup", ag.ctx, mock.Anything).Return(nil, false, nil)
+	mdi := ag.database.(*databasemocks.Plugin)
+	mdi.On("UpdateMessage", ag.ctx, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := ag.attemptContextInit(ag.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Group:  groupID,
+			Author: "author1",
+		},
+	}, groupID, "topic1", 12345, fftypes.NewRandB32(), fftypes.NewRandB32())
+	assert.EqualError(t, err, "pop")
+
+}
+
+func TestAttemptContextInitGroupRejectedEventFail(t *testing.T) {
+	ag, cancel := newTestAggregator()
+	defer cancel()
+
+	groupID := fftypes.NewRandB32()
+
+	msh := ag.syshandlers.(*syshandlersmocks.SystemHandlers)
+	msh.On("ResolveInitGroup", ag.ctx, mock.Anything).Return(nil, false, nil)
+	mdi := ag.database.(*databasemocks.Plugin)
+	mdi.On("UpdateMessage", ag.ctx, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("InsertEvent", ag.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := ag.attemptContextInit(ag.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Group:  groupID,
+			Author: "author1",
+		},
+	}, groupID, "topic1", 12345, fftypes.NewRandB32(), fftypes.NewRandB32())
+	assert.EqualError(t, err, "pop")
+
+}
+
 func TestAttemptContextInitAuthorMismatch(t *testing.T) {
 	ag, cancel := newTestAggregator()
 	defer cancel()
@@ -665,7 +971,7 @@ func TestAttemptContextInitAuthorMismatch(t *testing.T) {
 				{Identity: "author2"},
 			},
 		},
-	}, nil)
+	}, true, nil)
 
 	_, err := ag.attemptContextInit(ag.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
@@ -673,7 +979,7 @@ func TestAttemptContextInitAuthorMismatch(t *testing.T) {
 			Group:  groupID,
 			Author: "author1",
 		},
-	}, "topic1", 12345, fftypes.NewRandB32(), zeroHash)
+	}, groupID, "topic1", 12345, fftypes.NewRandB32(), zeroHash)
 	assert.NoError(t, err)
 
 }
@@ -690,7 +996,7 @@ func TestAttemptContextInitNoMatch(t *testing.T) {
 				{Identity: "author2"},
 			},
 		},
-	}, nil)
+	}, true, nil)
 
 	_, err := ag.attemptContextInit(ag.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
@@ -698,7 +1004,7 @@ func TestAttemptContextInitNoMatch(t *testing.T) {
 			Group:  groupID,
 			Author: "author1",
 		},
-	}, "topic1", 12345, fftypes.NewRandB32(), fftypes.NewRandB32())
+	}, groupID, "topic1", 12345, fftypes.NewRandB32(), fftypes.NewRandB32())
 	assert.NoError(t, err)
 
 }
@@ -717,7 +1023,7 @@ func TestAttemptContextInitGetPinsFail(t *testing.T) {
 				{Identity: "author1"},
 			},
 		},
-	}, nil)
+	}, true, nil)
 	mdi.On("GetPins", ag.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
 
 	_, err := ag.attemptContextInit(ag.ctx, &fftypes.Message{
@@ -726,7 +1032,7 @@ func TestAttemptContextInitGetPinsFail(t *testing.T) {
 			Group:  groupID,
 			Author: "author1",
 		},
-	}, "topic1", 12345, fftypes.NewRandB32(), zeroHash)
+	}, groupID, "topic1", 12345, fftypes.NewRandB32(), zeroHash)
 	assert.EqualError(t, err, "pop")
 
 }
@@ -745,7 +1051,7 @@ func TestAttemptContextInitGetPinsBlocked(t *testing.T) {
 				{Identity: "author1"},
 			},
 		},
-	}, nil)
+	}, true, nil)
 	mdi.On("GetPins", ag.ctx, mock.Anything).Return([]*fftypes.Pin{
 		{Sequence: 12345},
 	}, nil, nil)
@@ -756,7 +1062,7 @@ func TestAttemptContextInitGetPinsBlocked(t *testing.T) {
 			Group:  groupID,
 			Author: "author1",
 		},
-	}, "topic1", 12345, fftypes.NewRandB32(), zeroHash)
+	}, groupID, "topic1", 12345, fftypes.NewRandB32(), zeroHash)
 	assert.NoError(t, err)
 	assert.Nil(t, np)
 
@@ -776,7 +1082,7 @@ func TestAttemptContextInitInsertPinsFail(t *testing.T) {
 				{Identity: "author1"},
 			},
 		},
-	}, nil)
+	}, true, nil)
 	mdi.On("GetPins", ag.ctx, mock.Anything).Return([]*fftypes.Pin{}, nil, nil)
 	mdi.On("InsertNextPin", ag.ctx, mock.Anything).Return(fmt.Errorf("pop"))
 
@@ -786,7 +1092,7 @@ func TestAttemptContextInitInsertPinsFail(t *testing.T) {
 			Group:  groupID,
 			Author: "author1",
 		},
-	}, "topic1", 12345, fftypes.NewRandB32(), zeroHash)
+	}, groupID, "topic1", 12345, fftypes.NewRandB32(), zeroHash)
 	assert.Nil(t, np)
 	assert.EqualError(t, err, "pop")
 
@@ -865,7 +1171,7 @@ func TestAttemptMessageDispatchFailValidateBadSystem(t *testing.T) {
 	mdi.On("UpdateMessage", ag.ctx, mock.Anything, mock.MatchedBy(func(u database.Update) bool {
 		update, err := u.Finalize()
 		assert.NoError(t, err)
-		assert.Len(t, update.SetOperations, 3)
+		assert.Len(t, update.SetOperations, 4)
 
 		assert.Equal(t, "pending", update.SetOperations[0].Field)
 		v, err := update.SetOperations[0].Value.Value()
@@ -882,6 +1188,11 @@ func TestAttemptMessageDispatchFailValidateBadSystem(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, true, v)
 
+		assert.Equal(t, "error", update.SetOperations[3].Field)
+		v, err = update.SetOperations[3].Value.Value()
+		assert.NoError(t, err)
+		assert.Equal(t, "", v)
+
 		return true
 	})).Return(nil)
 	mdi.On("InsertEvent", ag.ctx, mock.Anything).Return(nil)
@@ -922,6 +1233,62 @@ func TestAttemptMessageDispatchFailValidateSystemFail(t *testing.T) {
 
 }
 
+func TestAttemptMessageDispatchTokenPoolDefinition(t *testing.T) {
+	ag, cancel := newTestAggregator()
+	defer cancel()
+
+	msh := ag.syshandlers.(*syshandlersmocks.SystemHandlers)
+	msh.On("HandleTokenPoolDefinition", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+	mdm := ag.data.(*datamocks.Manager)
+	mdm.On("GetMessageData", ag.ctx, mock.Anything, true).Return([]*fftypes.Data{}, true, nil)
+
+	mdi := ag.database.(*databasemocks.Plugin)
+	mdi.On("UpdateMessage", ag.ctx, mock.Anything, mock.Anything).Return(nil)
+	mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(event *fftypes.Event) bool {
+		return event.Type == fftypes.EventTypeMessageConfirmed
+	})).Return(nil)
+
+	dispatched, err := ag.attemptMessageDispatch(ag.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Type:      fftypes.MessageTypeTokenPoolDefinition,
+			Namespace: fftypes.SystemNamespace,
+		},
+		Data: fftypes.DataRefs{
+			{ID: fftypes.NewUUID()},
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, dispatched)
+
+	msh.AssertNotCalled(t, "HandleSystemBroadcast", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAttemptMessageDispatchTokenPoolDefinitionFail(t *testing.T) {
+	ag, cancel := newTestAggregator()
+	defer cancel()
+
+	msh := ag.syshandlers.(*syshandlersmocks.SystemHandlers)
+	msh.On("HandleTokenPoolDefinition", mock.Anything, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop"))
+
+	mdm := ag.data.(*datamocks.Manager)
+	mdm.On("GetMessageData", ag.ctx, mock.Anything, true).Return([]*fftypes.Data{}, true, nil)
+
+	_, err := ag.attemptMessageDispatch(ag.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Type:      fftypes.MessageTypeTokenPoolDefinition,
+			Namespace: fftypes.SystemNamespace,
+		},
+		Data: fftypes.DataRefs{
+			{ID: fftypes.NewUUID()},
+		},
+	})
+	assert.EqualError(t, err, "pop")
+
+}
+
 func TestAttemptMessageDispatchEventFail(t *testing.T) {
 	ag, cancel := newTestAggregator()
 	defer cancel()
@@ -23,6 +23,7 @@ import (
 
 	"github.com/hyperledger/firefly/internal/log"
 	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
@@ -57,6 +58,16 @@ func (em *eventManager) handlePrivatePinComplete(batchPin *blockchain.BatchPin,
 			if valid && err == nil {
 				err = em.persistContexts(ctx, batchPin, true)
 			}
+			if valid && err == nil {
+				// The batch might not have arrived yet, in which case this updates nothing and
+				// the details are taken from the pins when the batch is received
+				err = em.database.UpdateBatch(ctx, batchPin.BatchID, database.BatchQueryFactory.NewUpdate(ctx).
+					Set("confirmedat", fftypes.Now()).
+					Set("pincount", len(batchPin.Contexts)))
+			}
+			if valid && err == nil {
+				err = em.deletePrivateEphemeralOperations(ctx, batchPin)
+			}
 			return err
 		})
 		return err != nil, err // retry indefinitely (until context closes)
@@ -95,7 +106,7 @@ func (em *eventManager) persistContexts(ctx context.Context, batchPin *blockchai
 func (em *eventManager) handleBroadcastPinComplete(batchPin *blockchain.BatchPin, signingIdentity string, protocolTxID string, additionalInfo fftypes.JSONObject) error {
 	var body io.ReadCloser
 	if err := em.retry.Do(em.ctx, "retrieve data", func(attempt int) (retry bool, err error) {
-		body, err = em.publicstorage.RetrieveData(em.ctx, batchPin.BatchPaylodRef)
+		body, err = em.publicstorageFor(batchPin.Namespace).RetrieveData(em.ctx, batchPin.BatchPaylodRef)
 		return err != nil, err // retry indefinitely (until context closes)
 	}); err != nil {
 		return err
@@ -122,13 +133,45 @@ func (em *eventManager) handleBroadcastPinComplete(batchPin *blockchain.BatchPin
 			// Note that in the case of a bad batch broadcast, we don't store the pin. Because we know we
 			// are never going to be able to process it (we retrieved it successfully, it's just invalid).
 			if valid && err == nil {
+				batch.ConfirmedAt = fftypes.Now()
+				batch.PinCount = len(batchPin.Contexts)
 				valid, err = em.persistBatchFromBroadcast(ctx, batch, batchPin.BatchHash, signingIdentity)
 				if valid && err == nil {
 					err = em.persistContexts(ctx, batchPin, false)
 				}
+				if valid && err == nil && batch.Payload.Ephemeral() {
+					err = em.deleteEphemeralOperations(ctx, batchPin.TransactionID)
+				}
 			}
 			return err
 		})
 		return err != nil, err // retry indefinitely (until context closes)
 	})
 }
+
+// deletePrivateEphemeralOperations removes the operations of an ephemeral private batch. Only the sending node
+// has operations for the batch, and it always has the batch stored, so a batch that has not arrived is skipped.
+func (em *eventManager) deletePrivateEphemeralOperations(ctx context.Context, batchPin *blockchain.BatchPin) error {
+	batch, err := em.database.GetBatchByID(ctx, batchPin.BatchID)
+	if err != nil || batch == nil || !batch.Payload.Ephemeral() {
+		return err
+	}
+	return em.deleteEphemeralOperations(ctx, batchPin.TransactionID)
+}
+
+// deleteEphemeralOperations permanently removes the operations of a batch that contains only ephemeral messages,
+// once the batch has been confirmed. There will only be operations on the node that sent the batch.
+func (em *eventManager) deleteEphemeralOperations(ctx context.Context, txID *fftypes.UUID) error {
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	ops, _, err := em.database.GetOperations(ctx, fb.And(fb.Eq("tx", txID)))
+	if err != nil {
+		return err
+	}
+	for _, op := range ops {
+		log.L(ctx).Debugf("Removing operation %s for ephemeral batch transaction %s", op.ID, txID)
+		if err := em.database.DeleteOperation(ctx, op.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/hyperledger/firefly/internal/nsplugins"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/identitymocks"
@@ -83,7 +84,85 @@ func TestBatchPinCompleteOkBroadcast(t *testing.T) {
 	mdi.On("GetTransactionByID", mock.Anything, batchData.Payload.TX.ID).Return(nil, nil)
 	mdi.On("UpsertTransaction", mock.Anything, mock.Anything, false).Return(nil)
 	mdi.On("UpsertPin", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("UpsertBatch", mock.Anything, mock.MatchedBy(func(b *fftypes.Batch) bool {
+		return b.ConfirmedAt != nil && b.PinCount == 1
+	}), false).Return(nil)
+	mbi := &blockchainmocks.Plugin{}
+
+	mii := em.identity.(*identitymocks.Plugin)
+	mii.On("Resolve", mock.Anything, "0x12345").Return(&fftypes.Identity{OnChain: "0x12345"}, nil)
+
+	err = em.BatchPinComplete(mbi, batch, "0x12345", "tx1", nil)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestBatchPinCompleteOkBroadcastEphemeral(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	batch := &blockchain.BatchPin{
+		Namespace:      "ns1",
+		TransactionID:  fftypes.NewUUID(),
+		BatchID:        fftypes.NewUUID(),
+		BatchPaylodRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+		Contexts:       []*fftypes.Bytes32{fftypes.NewRandB32()},
+	}
+	batchData := &fftypes.Batch{
+		ID:         batch.BatchID,
+		Namespace:  "ns1",
+		Author:     "0x12345",
+		PayloadRef: batch.BatchPaylodRef,
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{
+				Type: fftypes.TransactionTypeBatchPin,
+				ID:   batch.TransactionID,
+			},
+			Messages: []*fftypes.Message{
+				{
+					Header: fftypes.MessageHeader{
+						ID:     fftypes.NewUUID(),
+						Author: "0x12345",
+					},
+					Ephemeral: true,
+				},
+			},
+			Data: []*fftypes.Data{},
+		},
+	}
+	batchData.Payload.Messages[0].Header.DataHash = batchData.Payload.Messages[0].Data.Hash()
+	batchData.Payload.Messages[0].Hash = batchData.Payload.Messages[0].Header.Hash()
+	batchData.Hash = batchData.Payload.Hash()
+	batch.BatchHash = batchData.Hash
+	batchDataBytes, err := json.Marshal(&batchData)
+	assert.NoError(t, err)
+	batchReadCloser := ioutil.NopCloser(bytes.NewReader(batchDataBytes))
+
+	mpi := em.publicstorage.(*publicstoragemocks.Plugin)
+	mpi.On("RetrieveData", mock.Anything, batch.BatchPaylodRef).Return(batchReadCloser, nil)
+
+	op1 := &fftypes.Operation{ID: fftypes.NewUUID()}
+	op2 := &fftypes.Operation{ID: fftypes.NewUUID()}
+	mdi := em.database.(*databasemocks.Plugin)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(nil)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(ctx context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	mdi.On("GetTransactionByID", mock.Anything, batchData.Payload.TX.ID).Return(nil, nil)
+	mdi.On("UpsertTransaction", mock.Anything, mock.Anything, false).Return(nil)
 	mdi.On("UpsertBatch", mock.Anything, mock.Anything, false).Return(nil)
+	mdi.On("UpsertMessage", mock.Anything, mock.MatchedBy(func(msg *fftypes.Message) bool {
+		return msg.Ephemeral
+	}), true, false).Return(nil)
+	mdi.On("UpsertPin", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{op1, op2}, nil, nil)
+	remainingOps := map[fftypes.UUID]bool{*op1.ID: true, *op2.ID: true}
+	mdi.On("DeleteOperation", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		delete(remainingOps, *args[1].(*fftypes.UUID))
+	})
 	mbi := &blockchainmocks.Plugin{}
 
 	mii := em.identity.(*identitymocks.Plugin)
@@ -91,8 +170,91 @@ func TestBatchPinCompleteOkBroadcast(t *testing.T) {
 
 	err = em.BatchPinComplete(mbi, batch, "0x12345", "tx1", nil)
 	assert.NoError(t, err)
+	assert.Empty(t, remainingOps)
 
 	mdi.AssertExpectations(t)
+	mdi.AssertNotCalled(t, "SoftDeleteOperation", mock.Anything, mock.Anything)
+}
+
+func TestDeleteEphemeralOperationsGetFail(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := em.deleteEphemeralOperations(context.Background(), fftypes.NewUUID())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteEphemeralOperationsDeleteFail(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{{ID: fftypes.NewUUID()}}, nil, nil)
+	mdi.On("DeleteOperation", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.deleteEphemeralOperations(context.Background(), fftypes.NewUUID())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeletePrivateEphemeralOperations(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	batchPin := &blockchain.BatchPin{
+		TransactionID: fftypes.NewUUID(),
+		BatchID:       fftypes.NewUUID(),
+	}
+	opID := fftypes.NewUUID()
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetBatchByID", mock.Anything, batchPin.BatchID).Return(&fftypes.Batch{
+		ID: batchPin.BatchID,
+		Payload: fftypes.BatchPayload{
+			Messages: []*fftypes.Message{{Ephemeral: true}},
+		},
+	}, nil)
+	mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{{ID: opID}}, nil, nil)
+	mdi.On("DeleteOperation", mock.Anything, opID).Return(nil)
+
+	err := em.deletePrivateEphemeralOperations(context.Background(), batchPin)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDeletePrivateEphemeralOperationsNotEphemeral(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	batchPin := &blockchain.BatchPin{
+		TransactionID: fftypes.NewUUID(),
+		BatchID:       fftypes.NewUUID(),
+	}
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetBatchByID", mock.Anything, batchPin.BatchID).Return(&fftypes.Batch{
+		ID: batchPin.BatchID,
+		Payload: fftypes.BatchPayload{
+			Messages: []*fftypes.Message{{Ephemeral: true}, {}},
+		},
+	}, nil)
+
+	err := em.deletePrivateEphemeralOperations(context.Background(), batchPin)
+	assert.NoError(t, err)
+
+	mdi.AssertNotCalled(t, "GetOperations", mock.Anything, mock.Anything)
+}
+
+func TestDeletePrivateEphemeralOperationsGetBatchFail(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetBatchByID", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	err := em.deletePrivateEphemeralOperations(context.Background(), &blockchain.BatchPin{BatchID: fftypes.NewUUID()})
+	assert.EqualError(t, err, "pop")
 }
 
 func TestBatchPinCompleteOkPrivate(t *testing.T) {
@@ -132,6 +294,13 @@ func TestBatchPinCompleteOkPrivate(t *testing.T) {
 	mdi.On("GetTransactionByID", mock.Anything, batchData.Payload.TX.ID).Return(nil, nil)
 	mdi.On("UpsertTransaction", mock.Anything, mock.Anything, false).Return(nil)
 	mdi.On("UpsertPin", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("UpdateBatch", mock.Anything, batch.BatchID, mock.MatchedBy(func(u database.Update) bool {
+		info, _ := u.Finalize()
+		return len(info.SetOperations) == 2 &&
+			info.SetOperations[0].Field == "confirmedat" &&
+			info.SetOperations[1].Field == "pincount"
+	})).Return(nil)
+	mdi.On("GetBatchByID", mock.Anything, batch.BatchID).Return(nil, nil)
 	mbi := &blockchainmocks.Plugin{}
 
 	err = em.BatchPinComplete(mbi, batch, "0x12345", "tx1", nil)
@@ -306,6 +475,27 @@ func TestPersistBatchBadHash(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestPersistBatchUnsupportedSchemaVersion(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+	batch := &fftypes.Batch{
+		ID:            fftypes.NewUUID(),
+		Author:        "0x12345",
+		SchemaVersion: fftypes.BatchSchemaVersion + 1,
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{
+				Type: fftypes.TransactionTypeBatchPin,
+				ID:   fftypes.NewUUID(),
+			},
+		},
+	}
+	batch.Hash = batch.Payload.Hash()
+
+	valid, err := em.persistBatch(context.Background(), batch)
+	assert.False(t, valid)
+	assert.NoError(t, err)
+}
+
 func TestPersistBatchUpsertBatchFail(t *testing.T) {
 	em, cancel := newTestEventManager(t)
 	defer cancel()
@@ -450,6 +640,27 @@ func TestPersistBatchGoodMessageAuthorMismatch(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestBatchPinCompleteBroadcastNamespacePublicStorage(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+	mps2 := &publicstoragemocks.Plugin{}
+	em.nsPlugins = nsplugins.Overrides{"ns2": {PublicStorage: mps2}}
+
+	batch := &blockchain.BatchPin{
+		Namespace:      "ns2",
+		BatchID:        fftypes.NewUUID(),
+		BatchPaylodRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+	}
+	mps2.On("RetrieveData", mock.Anything, batch.BatchPaylodRef).
+		Return(ioutil.NopCloser(bytes.NewReader([]byte("!json"))), nil)
+
+	err := em.handleBroadcastPinComplete(batch, "0x12345", "tx1", nil)
+	assert.NoError(t, err)
+
+	mps2.AssertExpectations(t)
+	em.publicstorage.(*publicstoragemocks.Plugin).AssertNotCalled(t, "RetrieveData", mock.Anything, mock.Anything)
+}
+
 func TestPersistBatchDataNilData(t *testing.T) {
 	em, cancel := newTestEventManager(t)
 	defer cancel()
@@ -520,11 +731,13 @@ func TestPersistBatchDataOk(t *testing.T) {
 		ID: fftypes.NewUUID(),
 	}
 
-	data := &fftypes.Data{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`"test"`)}
+	data := &fftypes.Data{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`"test"`), ExpiresAt: fftypes.Now()}
 	data.Hash = data.Value.Hash()
 
 	mdi := em.database.(*databasemocks.Plugin)
-	mdi.On("UpsertData", mock.Anything, mock.Anything, true, false).Return(nil)
+	mdi.On("UpsertData", mock.Anything, mock.MatchedBy(func(d *fftypes.Data) bool {
+		return d.ExpiresAt == nil
+	}), true, false).Return(nil)
 
 	err := em.persistBatchData(context.Background(), batch, 0, data)
 	assert.NoError(t, err)
@@ -614,6 +827,51 @@ func TestPersistBatchMessageOK(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestPersistBatchMessageUnsupportedVersion(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+	batch := &fftypes.Batch{
+		ID: fftypes.NewUUID(),
+	}
+	msg := &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:      fftypes.NewUUID(),
+			Version: fftypes.MaxSupportedMessageVersion + 1,
+		},
+	}
+	msg.Header.DataHash = msg.Data.Hash()
+	msg.Hash = msg.Header.Hash()
+
+	// The message is skipped, without being written to the database
+	valid, err := em.persistReceivedMessage(context.Background(), 0, msg, "batch", batch.ID)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestPersistBatchMessageCurrentVersion(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+	batch := &fftypes.Batch{
+		ID: fftypes.NewUUID(),
+	}
+	msg := &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:      fftypes.NewUUID(),
+			Version: fftypes.MaxSupportedMessageVersion,
+		},
+	}
+	msg.Header.DataHash = msg.Data.Hash()
+	msg.Hash = msg.Header.Hash()
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("UpsertMessage", mock.Anything, msg, true, false).Return(nil)
+
+	valid, err := em.persistReceivedMessage(context.Background(), 0, msg, "batch", batch.ID)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	mdi.AssertExpectations(t)
+}
+
 func TestPersistContextsFail(t *testing.T) {
 	em, cancel := newTestEventManager(t)
 	defer cancel()
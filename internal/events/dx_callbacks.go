@@ -58,6 +58,12 @@ func (em *eventManager) MessageReceived(dx dataexchange.Plugin, peerID string, d
 			return nil
 		}
 		return em.unpinnedMessageReceived(peerID, wrapper.Message, wrapper.Group, wrapper.Data)
+	case fftypes.TransportPayloadTypeHeartbeat:
+		if wrapper.Message == nil {
+			l.Errorf("Invalid transmission: nil heartbeat")
+			return nil
+		}
+		return em.heartbeatReceived(peerID, wrapper.Message)
 	default:
 		l.Errorf("Invalid transmission: unknonwn type '%s'", wrapper.Type)
 		return nil
@@ -116,6 +122,22 @@ func (em *eventManager) checkReceivedIdentity(ctx context.Context, peerID string
 	return node, nil
 }
 
+func (em *eventManager) heartbeatReceived(peerID string, heartbeat *fftypes.Message) error {
+	node, err := em.checkReceivedIdentity(em.ctx, peerID, heartbeat.Header.Author)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		log.L(em.ctx).Errorf("Heartbeat from '%s' ignored", peerID)
+		return nil
+	}
+	if err := em.messaging.HeartbeatReceived(em.ctx, node, heartbeat); err != nil {
+		// Heartbeats are not retried - a lost reply is reported as a missed heartbeat by the sender
+		log.L(em.ctx).Warnf("Failed to process heartbeat from '%s': %s", peerID, err)
+	}
+	return nil
+}
+
 func (em *eventManager) pinedBatchReceived(peerID string, batch *fftypes.Batch) error {
 
 	// Retry for persistence errors (not validation errors)
@@ -132,6 +154,10 @@ func (em *eventManager) pinedBatchReceived(peerID string, batch *fftypes.Batch)
 				return nil
 			}
 
+			if err = em.setPrivateBatchPinInfo(ctx, batch); err != nil {
+				return err
+			}
+
 			valid, err := em.persistBatch(ctx, batch)
 			if err != nil {
 				l.Errorf("Batch received from %s/%s invalid: %s", node.Owner, node.Name, err)
@@ -147,6 +173,21 @@ func (em *eventManager) pinedBatchReceived(peerID string, batch *fftypes.Batch)
 
 }
 
+// setPrivateBatchPinInfo sets the time the blockchain pin was received, and the number of pins,
+// on a private batch that is delivered after its pin
+func (em *eventManager) setPrivateBatchPinInfo(ctx context.Context, batch *fftypes.Batch) error {
+	fb := database.PinQueryFactory.NewFilter(ctx)
+	pins, _, err := em.database.GetPins(ctx, fb.Eq("batch", batch.ID))
+	if err != nil {
+		return err
+	}
+	if len(pins) > 0 {
+		batch.ConfirmedAt = pins[0].Created
+		batch.PinCount = len(pins)
+	}
+	return nil
+}
+
 func (em *eventManager) BLOBReceived(dx dataexchange.Plugin, peerID string, hash fftypes.Bytes32, payloadRef string) error {
 	l := log.L(em.ctx)
 	l.Debugf("Blob received event from data exhange: Peer='%s' Hash='%v' PayloadRef='%s'", peerID, &hash, payloadRef)
@@ -165,10 +206,11 @@ func (em *eventManager) BLOBReceived(dx dataexchange.Plugin, peerID string, hash
 		err = em.database.RunAsGroup(em.ctx, func(ctx context.Context) error {
 			// Insert the blob into the detabase
 			err := em.database.InsertBlob(ctx, &fftypes.Blob{
-				Peer:       peerID,
-				PayloadRef: payloadRef,
-				Hash:       &hash,
-				Created:    fftypes.Now(),
+				Peer:         peerID,
+				PayloadRef:   payloadRef,
+				Hash:         &hash,
+				Created:      fftypes.Now(),
+				Unverifiable: true, // data exchange does not give us a checksum
 			})
 			if err != nil {
 				return err
@@ -17,12 +17,14 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/mocks/syshandlersmocks"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
@@ -59,7 +61,78 @@ func TestMessageReceiveOK(t *testing.T) {
 	mdi.On("GetOrganizationByIdentity", em.ctx, "parentOrg").Return(&fftypes.Organization{
 		Identity: "parentOrg",
 	}, nil)
-	mdi.On("UpsertBatch", em.ctx, mock.Anything, false).Return(nil, nil)
+	pinCreated := fftypes.Now()
+	mdi.On("GetPins", em.ctx, mock.Anything).Return([]*fftypes.Pin{
+		{Batch: batch.ID, Index: 0, Created: pinCreated},
+		{Batch: batch.ID, Index: 1, Created: pinCreated},
+	}, nil, nil)
+	mdi.On("UpsertBatch", em.ctx, mock.MatchedBy(func(b *fftypes.Batch) bool {
+		return b.ConfirmedAt == pinCreated && b.PinCount == 2
+	}), false).Return(nil, nil)
+	err := em.MessageReceived(mdx, "peer1", b)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func newTestForwardedBatch(t *testing.T, author string) (*fftypes.Batch, []byte) {
+	msg := &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:            fftypes.NewUUID(),
+			Namespace:     "ns1",
+			Type:          fftypes.MessageTypePrivate,
+			Author:        author,
+			Forwarded:     true,
+			ForwardedFrom: fftypes.NewUUID(),
+		},
+	}
+	err := msg.Seal(context.Background())
+	assert.NoError(t, err)
+	batch := &fftypes.Batch{
+		ID:     fftypes.NewUUID(),
+		Author: "signingOrg",
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{
+				ID: fftypes.NewUUID(),
+			},
+			Messages: []*fftypes.Message{msg},
+		},
+	}
+	batch.Hash = batch.Payload.Hash()
+	b, _ := json.Marshal(&fftypes.TransportWrapper{
+		Type:  fftypes.TransportPayloadTypeBatch,
+		Batch: batch,
+	})
+	return batch, b
+}
+
+func TestMessageReceiveForwardedMessage(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	batch, b := newTestForwardedBatch(t, "signingOrg")
+	msg := batch.Payload.Messages[0]
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdx := &dataexchangemocks.Plugin{}
+	mdi.On("GetNodes", em.ctx, mock.Anything).Return([]*fftypes.Node{
+		{Name: "node1", Owner: "parentOrg"},
+	}, nil, nil)
+	mdi.On("GetOrganizationByIdentity", em.ctx, "signingOrg").Return(&fftypes.Organization{
+		Identity: "signingOrg", Parent: "parentOrg",
+	}, nil)
+	mdi.On("GetOrganizationByIdentity", em.ctx, "parentOrg").Return(&fftypes.Organization{
+		Identity: "parentOrg",
+	}, nil)
+	mdi.On("GetPins", em.ctx, mock.Anything).Return([]*fftypes.Pin{}, nil, nil)
+	mdi.On("UpsertBatch", em.ctx, mock.Anything, false).Return(nil)
+	// The relayed message is stored as authored by the relaying org, with forwardedFrom kept as metadata
+	mdi.On("UpsertMessage", em.ctx, mock.MatchedBy(func(m *fftypes.Message) bool {
+		return m.Header.Author == "signingOrg" &&
+			m.Header.Forwarded &&
+			m.Header.ForwardedFrom.Equals(msg.Header.ForwardedFrom)
+	}), true, false).Return(nil)
 	err := em.MessageReceived(mdx, "peer1", b)
 	assert.NoError(t, err)
 
@@ -67,6 +140,33 @@ func TestMessageReceiveOK(t *testing.T) {
 	mdx.AssertExpectations(t)
 }
 
+func TestMessageReceiveForwardedMessageOriginalAuthorSkipped(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	// A relay cannot claim the original author's identity on the message it forwards
+	_, b := newTestForwardedBatch(t, "originalOrg")
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdx := &dataexchangemocks.Plugin{}
+	mdi.On("GetNodes", em.ctx, mock.Anything).Return([]*fftypes.Node{
+		{Name: "node1", Owner: "parentOrg"},
+	}, nil, nil)
+	mdi.On("GetOrganizationByIdentity", em.ctx, "signingOrg").Return(&fftypes.Organization{
+		Identity: "signingOrg", Parent: "parentOrg",
+	}, nil)
+	mdi.On("GetOrganizationByIdentity", em.ctx, "parentOrg").Return(&fftypes.Organization{
+		Identity: "parentOrg",
+	}, nil)
+	mdi.On("GetPins", em.ctx, mock.Anything).Return([]*fftypes.Pin{}, nil, nil)
+	mdi.On("UpsertBatch", em.ctx, mock.Anything, false).Return(nil)
+	err := em.MessageReceived(mdx, "peer1", b)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdi.AssertNotCalled(t, "UpsertMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestMessageReceiveOkBadBatchIgnored(t *testing.T) {
 	em, cancel := newTestEventManager(t)
 	defer cancel()
@@ -91,6 +191,7 @@ func TestMessageReceiveOkBadBatchIgnored(t *testing.T) {
 	mdi.On("GetOrganizationByIdentity", em.ctx, "parentOrg").Return(&fftypes.Organization{
 		Identity: "parentOrg",
 	}, nil)
+	mdi.On("GetPins", em.ctx, mock.Anything).Return([]*fftypes.Pin{}, nil, nil)
 	err := em.MessageReceived(mdx, "peer1", b)
 	assert.NoError(t, err)
 
@@ -128,6 +229,7 @@ func TestMessageReceivePersistBatchError(t *testing.T) {
 	mdi.On("GetOrganizationByIdentity", em.ctx, "parentOrg").Return(&fftypes.Organization{
 		Identity: "parentOrg",
 	}, nil)
+	mdi.On("GetPins", em.ctx, mock.Anything).Return([]*fftypes.Pin{}, nil, nil)
 	mdi.On("UpsertBatch", em.ctx, mock.Anything, false).Return(fmt.Errorf("pop"))
 	err := em.MessageReceived(mdx, "peer1", b)
 	assert.Regexp(t, "FF10158", err)
@@ -136,6 +238,38 @@ func TestMessageReceivePersistBatchError(t *testing.T) {
 	mdx.AssertExpectations(t)
 }
 
+func TestMessageReceiveGetPinsError(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	cancel() // retryable error
+
+	batch := &fftypes.Batch{
+		ID:     fftypes.NewUUID(),
+		Author: "signingOrg",
+	}
+	b, _ := json.Marshal(&fftypes.TransportWrapper{
+		Type:  fftypes.TransportPayloadTypeBatch,
+		Batch: batch,
+	})
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdx := &dataexchangemocks.Plugin{}
+	mdi.On("GetNodes", em.ctx, mock.Anything).Return([]*fftypes.Node{
+		{Name: "node1", Owner: "parentOrg"},
+	}, nil, nil)
+	mdi.On("GetOrganizationByIdentity", em.ctx, "signingOrg").Return(&fftypes.Organization{
+		Identity: "signingOrg", Parent: "parentOrg",
+	}, nil)
+	mdi.On("GetOrganizationByIdentity", em.ctx, "parentOrg").Return(&fftypes.Organization{
+		Identity: "parentOrg",
+	}, nil)
+	mdi.On("GetPins", em.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	err := em.MessageReceived(mdx, "peer1", b)
+	assert.Regexp(t, "FF10158", err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
 func TestMessageReceivedBadData(t *testing.T) {
 	em, cancel := newTestEventManager(t)
 	defer cancel()
@@ -194,6 +328,88 @@ func TestMessageReceivedNilGroup(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestMessageReceivedHeartbeat(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	heartbeat := &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Type:   fftypes.MessageTypePrivate,
+			Author: "parentOrg",
+		},
+	}
+	b, _ := json.Marshal(&fftypes.TransportWrapper{
+		Type:    fftypes.TransportPayloadTypeHeartbeat,
+		Message: heartbeat,
+	})
+
+	node := &fftypes.Node{Name: "node1", Owner: "parentOrg"}
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetNodes", em.ctx, mock.Anything).Return([]*fftypes.Node{node}, nil, nil)
+	mdi.On("GetOrganizationByIdentity", em.ctx, "parentOrg").Return(&fftypes.Organization{
+		Identity: "parentOrg",
+	}, nil)
+	mpm := em.messaging.(*privatemessagingmocks.Manager)
+	mpm.On("HeartbeatReceived", em.ctx, node, mock.MatchedBy(func(msg *fftypes.Message) bool {
+		return msg.Header.ID.Equals(heartbeat.Header.ID)
+	})).Return(fmt.Errorf("pop"))
+
+	mdx := &dataexchangemocks.Plugin{}
+	err := em.MessageReceived(mdx, "peer1", b)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mpm.AssertExpectations(t)
+}
+
+func TestMessageReceivedHeartbeatNodeLookupError(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	b, _ := json.Marshal(&fftypes.TransportWrapper{
+		Type:    fftypes.TransportPayloadTypeHeartbeat,
+		Message: &fftypes.Message{Header: fftypes.MessageHeader{Author: "parentOrg"}},
+	})
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetNodes", em.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	mdx := &dataexchangemocks.Plugin{}
+	err := em.MessageReceived(mdx, "peer1", b)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestMessageReceivedHeartbeatNodeNotFound(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	b, _ := json.Marshal(&fftypes.TransportWrapper{
+		Type:    fftypes.TransportPayloadTypeHeartbeat,
+		Message: &fftypes.Message{Header: fftypes.MessageHeader{Author: "parentOrg"}},
+	})
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetNodes", em.ctx, mock.Anything).Return([]*fftypes.Node{}, nil, nil)
+
+	mdx := &dataexchangemocks.Plugin{}
+	err := em.MessageReceived(mdx, "peer1", b)
+	assert.NoError(t, err)
+}
+
+func TestMessageReceivedNilHeartbeat(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+
+	b, _ := json.Marshal(&fftypes.TransportWrapper{
+		Type: fftypes.TransportPayloadTypeHeartbeat,
+	})
+
+	mdx := &dataexchangemocks.Plugin{}
+	err := em.MessageReceived(mdx, "peer1", b)
+	assert.NoError(t, err)
+}
+
 func TestMessageReceiveNodeLookupError(t *testing.T) {
 	em, cancel := newTestEventManager(t)
 	cancel() // to stop retry
@@ -367,7 +583,9 @@ func TestBLOBReceivedTriggersRewindOk(t *testing.T) {
 	mdx := &dataexchangemocks.Plugin{}
 
 	mdi := em.database.(*databasemocks.Plugin)
-	mdi.On("InsertBlob", em.ctx, mock.Anything).Return(nil)
+	mdi.On("InsertBlob", em.ctx, mock.MatchedBy(func(blob *fftypes.Blob) bool {
+		return blob.Unverifiable
+	})).Return(nil)
 	mdi.On("GetDataRefs", em.ctx, mock.Anything).Return(fftypes.DataRefs{
 		{ID: dataID},
 	}, nil, nil)
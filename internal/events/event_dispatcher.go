@@ -39,6 +39,7 @@ const (
 
 type ackNack struct {
 	id     fftypes.UUID
+	event  *fftypes.Event
 	isNack bool
 	offset int64
 }
@@ -57,12 +58,19 @@ type eventDispatcher struct {
 	eventPoller   *eventPoller
 	inflight      map[fftypes.UUID]*fftypes.Event
 	eventDelivery chan *fftypes.EventDelivery
+	failures      map[fftypes.UUID]int
+	maxAttempts   int
+	redelivering  map[fftypes.UUID]*fftypes.DeadLetterEvent
 	mux           sync.Mutex
 	namespace     string
 	readAhead     int
 	subscription  *subscription
 	cel           *changeEventListener
 	changeEvents  chan *fftypes.ChangeEvent
+	paused        bool
+	pauseBuffer   int
+	pausedEvents  []*fftypes.EventDelivery
+	resumed       chan bool
 }
 
 func newEventDispatcher(ctx context.Context, ei events.Plugin, di database.Plugin, dm data.Manager, sh syshandlers.SystemHandlers, connID string, sub *subscription, en *eventNotifier, cel *changeEventListener) *eventDispatcher {
@@ -74,6 +82,11 @@ func newEventDispatcher(ctx context.Context, ei events.Plugin, di database.Plugi
 	if readAhead > maxReadAhead {
 		readAhead = maxReadAhead
 	}
+	maxAttempts := sub.definition.MaxDeliveryAttempts
+	if maxAttempts <= 0 {
+		// Ephemeral subscriptions, and those created without a limit, fall back to the global retry setting
+		maxAttempts = config.GetInt(config.EventDeadLetterMaxRetries) + 1
+	}
 	ed := &eventDispatcher{
 		ctx: log.WithLogField(log.WithLogField(ctx,
 			"role", fmt.Sprintf("ed[%s]", connID)),
@@ -88,11 +101,17 @@ func newEventDispatcher(ctx context.Context, ei events.Plugin, di database.Plugi
 		namespace:     sub.definition.Namespace,
 		inflight:      make(map[fftypes.UUID]*fftypes.Event),
 		eventDelivery: make(chan *fftypes.EventDelivery, readAhead+1),
+		failures:      make(map[fftypes.UUID]int),
+		maxAttempts:   maxAttempts,
+		redelivering:  make(map[fftypes.UUID]*fftypes.DeadLetterEvent),
 		changeEvents:  make(chan *fftypes.ChangeEvent),
 		readAhead:     int(readAhead),
 		acksNacks:     make(chan ackNack),
 		closed:        make(chan struct{}),
 		cel:           cel,
+		paused:        sub.paused,
+		pauseBuffer:   config.GetInt(config.EventsSubscriptionPauseBuffer),
+		resumed:       make(chan bool, 1),
 	}
 
 	pollerConf := &eventPollerConf{
@@ -109,7 +128,11 @@ func newEventDispatcher(ctx context.Context, ei events.Plugin, di database.Plugi
 		offsetType: fftypes.OffsetTypeSubscription,
 		offsetName: sub.definition.ID.String(),
 		addCriteria: func(af database.AndFilter) database.AndFilter {
-			return af.Condition(af.Builder().Eq("namespace", sub.definition.Namespace))
+			af = af.Condition(af.Builder().Eq("namespace", sub.definition.Namespace)).(database.AndFilter)
+			if sub.expressionFilter != nil {
+				af = af.Condition(sub.expressionFilter).(database.AndFilter)
+			}
+			return af
 		},
 		queryFactory:     database.EventQueryFactory,
 		getItems:         ed.getEvents,
@@ -143,6 +166,7 @@ func (ed *eventDispatcher) electAndStart() {
 	}
 	// We're ready to go - not
 	ed.elected = true
+	ed.loadDeliveryFailures()
 	ed.eventPoller.start()
 	go ed.deliverEvents()
 	// Wait until the event poller closes
@@ -304,6 +328,12 @@ func (ed *eventDispatcher) bufferedDelivery(events []fftypes.LocallySequenced) (
 		case <-ed.ctx.Done():
 			return false, i18n.NewError(ed.ctx, i18n.MsgDispatcherClosing)
 		case an := <-ed.acksNacks:
+			if an.isNack && ed.deadLetterIfExhausted(an.event) {
+				// The event has been moved to the dead letter table, so we ack it to move past it
+				an.isNack = false
+			} else if !an.isNack {
+				ed.clearDeliveryFailures(an.event)
+			}
 			if an.isNack {
 				nacks++
 				ed.handleNackOffsetUpdate(an)
@@ -371,24 +401,20 @@ func (ed *eventDispatcher) deliverEvents() {
 		ed.cel.addDispatcher(*ed.subscription.definition.ID, ed)
 		defer ed.cel.removeDispatcher(*ed.subscription.definition.ID)
 	}
-	withData := ed.subscription.definition.Options.WithData != nil && *ed.subscription.definition.Options.WithData
+	withData := ed.withData()
 	for {
 		select {
 		case event, ok := <-ed.eventDelivery:
 			if !ok {
 				return
 			}
-			log.L(ed.ctx).Debugf("Dispatching %s event: %.10d/%s [%s]: ref=%s/%s", ed.transport.Name(), event.Sequence, event.ID, event.Type, event.Namespace, event.Reference)
-			var data []*fftypes.Data
-			var err error
-			if withData && event.Message != nil {
-				data, _, err = ed.data.GetMessageData(ed.ctx, event.Message, true)
-			}
-			if err == nil {
-				err = ed.transport.DeliveryRequest(ed.connID, ed.subscription.definition, event, data)
+			if ed.bufferIfPaused(event) {
+				continue
 			}
-			if err != nil {
-				ed.deliveryResponse(&fftypes.EventDeliveryResponse{ID: event.ID, Rejected: true})
+			ed.deliverEvent(event, withData)
+		case <-ed.resumed:
+			for _, event := range ed.takePausedEvents() {
+				ed.deliverEvent(event, withData)
 			}
 		case changeEvent := <-ed.changeEvents:
 			ws, ok := ed.transport.(events.ChangeEventListener)
@@ -402,20 +428,95 @@ func (ed *eventDispatcher) deliverEvents() {
 		}
 	}
 }
+
+func (ed *eventDispatcher) withData() bool {
+	return ed.subscription.definition.Options.WithData != nil && *ed.subscription.definition.Options.WithData
+}
+
+func (ed *eventDispatcher) deliverEvent(event *fftypes.EventDelivery, withData bool) {
+	log.L(ed.ctx).Debugf("Dispatching %s event: %.10d/%s [%s]: ref=%s/%s", ed.transport.Name(), event.Sequence, event.ID, event.Type, event.Namespace, event.Reference)
+	var data []*fftypes.Data
+	var err error
+	if withData && event.Message != nil {
+		data, _, err = ed.data.GetMessageData(ed.ctx, event.Message, true)
+	}
+	if err == nil {
+		err = ed.transport.DeliveryRequest(ed.connID, ed.subscription.definition, event, data)
+	}
+	if err != nil {
+		ed.deliveryResponse(&fftypes.EventDeliveryResponse{ID: event.ID, Rejected: true})
+	}
+}
+
+// bufferIfPaused holds on to the event while the dispatcher is paused, returning true if the
+// event should not be delivered now. Once the buffer is full, further events are dropped -
+// they are acknowledged without delivery, so the subscription offset moves past them.
+func (ed *eventDispatcher) bufferIfPaused(event *fftypes.EventDelivery) bool {
+	ed.mux.Lock()
+	if !ed.paused {
+		ed.mux.Unlock()
+		return false
+	}
+	dropped := len(ed.pausedEvents) >= ed.pauseBuffer
+	if !dropped {
+		ed.pausedEvents = append(ed.pausedEvents, event)
+	}
+	ed.mux.Unlock()
+
+	if dropped {
+		log.L(ed.ctx).Warnf("Dropping %s event while paused (buffer full at %d): %.10d/%s [%s]", ed.transport.Name(), ed.pauseBuffer, event.Sequence, event.ID, event.Type)
+		ed.deliveryResponse(&fftypes.EventDeliveryResponse{ID: event.ID})
+	}
+	return true
+}
+
+func (ed *eventDispatcher) takePausedEvents() []*fftypes.EventDelivery {
+	ed.mux.Lock()
+	defer ed.mux.Unlock()
+	events := ed.pausedEvents
+	ed.pausedEvents = nil
+	return events
+}
+
+func (ed *eventDispatcher) pause() {
+	ed.mux.Lock()
+	defer ed.mux.Unlock()
+	ed.paused = true
+}
+
+func (ed *eventDispatcher) resume() {
+	ed.mux.Lock()
+	ed.paused = false
+	ed.mux.Unlock()
+	// Wake the delivery loop to replay anything buffered while we were paused
+	select {
+	case ed.resumed <- true:
+	default:
+	}
+}
+
 func (ed *eventDispatcher) deliveryResponse(response *fftypes.EventDeliveryResponse) {
 	l := log.L(ed.ctx)
 
 	ed.mux.Lock()
 	var an ackNack
+	var redelivery *fftypes.DeadLetterEvent
 	event, found := ed.inflight[*response.ID]
 	if found {
 		an.id = *response.ID
+		an.event = event
 		an.offset = event.Sequence
 		an.isNack = response.Rejected
+	} else if redelivery = ed.redelivering[*response.ID]; redelivery != nil {
+		delete(ed.redelivering, *response.ID)
 	}
 	ed.mux.Unlock()
 
 	// Do some extra logging and persistent actions now we're out of lock
+	if redelivery != nil {
+		ed.redeliveryResponse(redelivery, response)
+		return
+	}
 	if !found {
 		l.Warnf("Response for event not in flight: %s rejected=%t info='%s' (likely previous reject)", response.ID, response.Rejected, response.Info)
 		return
@@ -429,7 +530,8 @@ func (ed *eventDispatcher) deliveryResponse(response *fftypes.EventDeliveryRespo
 
 	l.Debugf("Response for %s event: %.10d/%s [%s]: ref=%s/%s rejected=%t info='%s'", ed.transport.Name(), event.Sequence, event.ID, event.Type, event.Namespace, event.Reference, response.Rejected, response.Info)
 	// We don't do any meaningful work in this call, we just set things up so the right thing
-	// will happen when the poller wakes up. So we need to pass it over
+	// will happen when the poller wakes up - including counting failures, and dead lettering.
+	// So we need to pass it over
 	select {
 	case ed.acksNacks <- an:
 	case <-ed.ctx.Done():
@@ -438,6 +540,130 @@ func (ed *eventDispatcher) deliveryResponse(response *fftypes.EventDeliveryRespo
 	}
 }
 
+// loadDeliveryFailures restores the failed delivery counts of a durable subscription, so
+// retries carry on being counted across restarts
+func (ed *eventDispatcher) loadDeliveryFailures() {
+	if ed.subscription.definition.Ephemeral {
+		return
+	}
+	fb := database.DeliveryFailureQueryFactory.NewFilter(ed.ctx)
+	dfs, _, err := ed.database.GetDeliveryFailures(ed.ctx, fb.Eq("subscription", ed.subscription.definition.ID))
+	if err != nil {
+		log.L(ed.ctx).Errorf("Failed to load delivery failures: %s", err)
+		return
+	}
+	ed.mux.Lock()
+	defer ed.mux.Unlock()
+	for _, df := range dfs {
+		ed.failures[*df.Event] = df.Attempts
+	}
+}
+
+// clearDeliveryFailures removes the failed delivery count of an event, once it has been delivered
+func (ed *eventDispatcher) clearDeliveryFailures(event *fftypes.Event) {
+	ed.mux.Lock()
+	_, failed := ed.failures[*event.ID]
+	delete(ed.failures, *event.ID)
+	ed.mux.Unlock()
+	if !failed || ed.subscription.definition.Ephemeral {
+		return
+	}
+	err := ed.database.DeleteDeliveryFailure(ed.ctx, ed.subscription.definition.ID, event.ID)
+	if err != nil && err != database.DeleteRecordNotFound {
+		log.L(ed.ctx).Errorf("Failed to clear delivery failures for event %s: %s", event.ID, err)
+	}
+}
+
+// deadLetterIfExhausted counts a failed delivery of an event, and once the retries are exhausted
+// records the event in the dead letter table. Returns true if the event was dead lettered.
+func (ed *eventDispatcher) deadLetterIfExhausted(event *fftypes.Event) bool {
+	ed.mux.Lock()
+	ed.failures[*event.ID]++
+	attempts := ed.failures[*event.ID]
+	ed.mux.Unlock()
+	if attempts < ed.maxAttempts {
+		if !ed.subscription.definition.Ephemeral {
+			df := &fftypes.DeliveryFailure{
+				Namespace:    event.Namespace,
+				Subscription: ed.subscription.definition.ID,
+				Event:        event.ID,
+				Attempts:     attempts,
+				Updated:      fftypes.Now(),
+			}
+			if err := ed.database.UpsertDeliveryFailure(ed.ctx, df); err != nil {
+				// The count is still held in memory, so is only lost on restart
+				log.L(ed.ctx).Errorf("Failed to record delivery failure %d of event %s: %s", attempts, event.ID, err)
+			}
+		}
+		return false
+	}
+
+	dle := &fftypes.DeadLetterEvent{
+		ID:           fftypes.NewUUID(),
+		Namespace:    event.Namespace,
+		Subscription: ed.subscription.definition.ID,
+		Event:        event.ID,
+		Attempts:     attempts,
+		Created:      fftypes.Now(),
+	}
+	err := ed.database.RunAsGroup(ed.ctx, func(ctx context.Context) error {
+		if err := ed.database.InsertDeadLetterEvent(ctx, dle); err != nil {
+			return err
+		}
+		if err := ed.database.DeleteDeliveryFailure(ctx, dle.Subscription, event.ID); err != nil && err != database.DeleteRecordNotFound {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		// We will retry delivery, and attempt to dead letter it again on the next failure
+		log.L(ed.ctx).Errorf("Failed to dead letter event %s after %d attempts: %s", event.ID, attempts, err)
+		return false
+	}
+	log.L(ed.ctx).Warnf("Event %.10d/%s [%s] moved to dead letter %s after %d failed delivery attempts", event.Sequence, event.ID, event.Type, dle.ID, attempts)
+
+	ed.mux.Lock()
+	delete(ed.failures, *event.ID)
+	ed.mux.Unlock()
+	return true
+}
+
+// redeliver makes a single attempt to deliver a dead lettered event. The dead letter record
+// is removed if the event is acknowledged, and kept if it is rejected again.
+func (ed *eventDispatcher) redeliver(ctx context.Context, dle *fftypes.DeadLetterEvent) error {
+	event, err := ed.database.GetEventByID(ctx, dle.Event)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	enriched, err := ed.enrichEvents([]fftypes.LocallySequenced{event})
+	if err != nil {
+		return err
+	}
+
+	log.L(ctx).Infof("Redelivering dead lettered event %.10d/%s [%s]", event.Sequence, event.ID, event.Type)
+	ed.mux.Lock()
+	ed.redelivering[*event.ID] = dle
+	ed.mux.Unlock()
+	ed.deliverEvent(enriched[0], ed.withData())
+	return nil
+}
+
+func (ed *eventDispatcher) redeliveryResponse(dle *fftypes.DeadLetterEvent, response *fftypes.EventDeliveryResponse) {
+	l := log.L(ed.ctx)
+	if response.Rejected {
+		l.Warnf("Redelivery of dead lettered event %s rejected info='%s'", dle.Event, response.Info)
+		return
+	}
+	if err := ed.database.DeleteDeadLetterEvent(ed.ctx, dle.ID); err != nil {
+		l.Errorf("Failed to remove dead letter %s after redelivery of event %s: %s", dle.ID, dle.Event, err)
+		return
+	}
+	l.Infof("Dead lettered event %s redelivered", dle.Event)
+}
+
 func (ed *eventDispatcher) close() {
 	log.L(ed.ctx).Infof("Dispatcher closing for conn=%s subscription=%s", ed.connID, ed.subscription.definition.ID)
 	ed.cancelCtx()
@@ -37,6 +37,8 @@ import (
 
 func newTestEventDispatcher(sub *subscription) (*eventDispatcher, func()) {
 	mdi := &databasemocks.Plugin{}
+	mdi.On("GetDeliveryFailures", mock.Anything, mock.Anything).Return([]*fftypes.DeliveryFailure{}, nil, nil).Maybe()
+	mdi.On("UpsertDeliveryFailure", mock.Anything, mock.Anything).Return(nil).Maybe()
 	mei := &eventsmocks.PluginAll{}
 	mei.On("Capabilities").Return(&events.Capabilities{ChangeEvents: true}).Maybe()
 	mei.On("Name").Return("ut").Maybe()
@@ -570,6 +572,7 @@ func TestBufferedDeliveryClosedContext(t *testing.T) {
 }
 
 func TestBufferedDeliveryNackRewind(t *testing.T) {
+	config.Reset()
 
 	sub := &subscription{
 		definition: &fftypes.Subscription{},
@@ -658,6 +661,7 @@ func TestBufferedDeliveryAckFail(t *testing.T) {
 }
 
 func TestBufferedDeliveryFailNack(t *testing.T) {
+	config.Reset()
 	log.SetLevel("trace")
 
 	sub := &subscription{
@@ -791,6 +795,7 @@ func TestGetEvents(t *testing.T) {
 }
 
 func TestDeliverEventsWithDataFail(t *testing.T) {
+	config.Reset()
 	yes := true
 	sub := &subscription{
 		definition: &fftypes.Subscription{
@@ -917,3 +922,433 @@ func TestDispatchChangeEventBlockedClose(t *testing.T) {
 
 	ed.dispatchChangeEvent(&fftypes.ChangeEvent{})
 }
+
+func TestEventDispatcherPauseBufferResume(t *testing.T) {
+	sub := &subscription{
+		definition: &fftypes.Subscription{},
+		paused:     true,
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	assert.True(t, ed.paused)
+	ed.pauseBuffer = 1
+
+	delivered := make(chan *fftypes.EventDelivery, 1)
+	mei := ed.transport.(*eventsmocks.PluginAll)
+	mei.On("DeliveryRequest", ed.connID, sub.definition, mock.Anything, []*fftypes.Data(nil)).Run(func(args mock.Arguments) {
+		delivered <- args[2].(*fftypes.EventDelivery)
+	}).Return(nil)
+
+	id1 := fftypes.NewUUID()
+	id2 := fftypes.NewUUID()
+	ed.inflight[*id1] = &fftypes.Event{ID: id1, Sequence: 1}
+	ed.inflight[*id2] = &fftypes.Event{ID: id2, Sequence: 2}
+	go ed.deliverEvents()
+	ed.eventDelivery <- &fftypes.EventDelivery{Event: fftypes.Event{ID: id1, Sequence: 1}}
+	ed.eventDelivery <- &fftypes.EventDelivery{Event: fftypes.Event{ID: id2, Sequence: 2}}
+
+	// The second event overflows the buffer, so is dropped and acknowledged without delivery
+	an := <-ed.acksNacks
+	assert.False(t, an.isNack)
+	assert.Equal(t, *id2, an.id)
+	assert.Empty(t, delivered)
+
+	// Resuming replays the buffered event
+	ed.resume()
+	event := <-delivered
+	assert.Equal(t, id1, event.ID)
+	assert.Empty(t, ed.takePausedEvents())
+}
+
+func TestEventDispatcherPauseResumeIdempotent(t *testing.T) {
+	sub := &subscription{
+		definition: &fftypes.Subscription{},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	ed.pause()
+	ed.pause()
+	assert.True(t, ed.paused)
+	ed.resume()
+	ed.resume()
+	assert.False(t, ed.paused)
+	assert.Len(t, ed.resumed, 1)
+}
+
+func mockRunAsGroupPassthrough(mdi *databasemocks.Plugin) {
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(ctx context.Context) error)(a[0].(context.Context)),
+		}
+	}
+}
+
+func TestBufferedDeliveryDeadLetterAfterMaxRetries(t *testing.T) {
+
+	sub := &subscription{
+		definition: &fftypes.Subscription{
+			SubscriptionRef: fftypes.SubscriptionRef{ID: fftypes.NewUUID()},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	go ed.deliverEvents()
+	ed.maxAttempts = 2
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mei := ed.transport.(*eventsmocks.PluginAll)
+	mdi.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, nil)
+	mdi.On("UpdateOffset", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	ev1 := fftypes.NewUUID()
+	mockRunAsGroupPassthrough(mdi)
+	mdi.On("InsertDeadLetterEvent", mock.Anything, mock.MatchedBy(func(dle *fftypes.DeadLetterEvent) bool {
+		return *dle.Event == *ev1 && dle.Namespace == "ns1" && *dle.Subscription == *sub.definition.ID && dle.Attempts == 2
+	})).Return(nil)
+	mdi.On("DeleteDeliveryFailure", mock.Anything, sub.definition.ID, ev1).Return(nil)
+
+	// First failure is retried, by rewinding the offset, with the failure recorded
+	ed.eventPoller.pollingOffset = 100000
+	repoll, err := ed.bufferedDelivery([]fftypes.LocallySequenced{&fftypes.Event{ID: ev1, Namespace: "ns1", Sequence: 100001}})
+	assert.NoError(t, err)
+	assert.True(t, repoll)
+	assert.Equal(t, int64(100000), ed.eventPoller.pollingOffset)
+	mdi.AssertNotCalled(t, "InsertDeadLetterEvent", mock.Anything, mock.Anything)
+	mdi.AssertCalled(t, "UpsertDeliveryFailure", mock.Anything, mock.MatchedBy(func(df *fftypes.DeliveryFailure) bool {
+		return *df.Event == *ev1 && *df.Subscription == *sub.definition.ID && df.Attempts == 1
+	}))
+
+	// Second failure exhausts the retries, so the event is dead lettered and we move past it
+	repoll, err = ed.bufferedDelivery([]fftypes.LocallySequenced{&fftypes.Event{ID: ev1, Namespace: "ns1", Sequence: 100001}})
+	assert.NoError(t, err)
+	assert.True(t, repoll)
+	assert.Equal(t, int64(100001), ed.eventPoller.pollingOffset)
+	assert.Empty(t, ed.failures)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestBufferedDeliveryDeadLetterSubscriptionMaxDeliveryAttempts(t *testing.T) {
+
+	sub := &subscription{
+		definition: &fftypes.Subscription{
+			SubscriptionRef:     fftypes.SubscriptionRef{ID: fftypes.NewUUID()},
+			MaxDeliveryAttempts: 1,
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	go ed.deliverEvents()
+	assert.Equal(t, 1, ed.maxAttempts)
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mei := ed.transport.(*eventsmocks.PluginAll)
+	mdi.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, nil)
+	mdi.On("UpdateOffset", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	ev1 := fftypes.NewUUID()
+	mockRunAsGroupPassthrough(mdi)
+	mdi.On("InsertDeadLetterEvent", mock.Anything, mock.MatchedBy(func(dle *fftypes.DeadLetterEvent) bool {
+		return *dle.Event == *ev1 && *dle.Subscription == *sub.definition.ID && dle.Attempts == 1
+	})).Return(nil)
+	mdi.On("DeleteDeliveryFailure", mock.Anything, sub.definition.ID, ev1).Return(database.DeleteRecordNotFound)
+
+	// The first failure is dead lettered straight away, and we move past it
+	ed.eventPoller.pollingOffset = 100000
+	repoll, err := ed.bufferedDelivery([]fftypes.LocallySequenced{&fftypes.Event{ID: ev1, Namespace: "ns1", Sequence: 100001}})
+	assert.NoError(t, err)
+	assert.True(t, repoll)
+	assert.Equal(t, int64(100001), ed.eventPoller.pollingOffset)
+	assert.Empty(t, ed.failures)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMaxAttemptsDefaultsFromConfig(t *testing.T) {
+
+	config.Reset()
+	config.Set(config.EventDeadLetterMaxRetries, 5)
+	sub := &subscription{
+		definition: &fftypes.Subscription{},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	assert.Equal(t, 6, ed.maxAttempts)
+}
+
+func TestDeadLetterInsertFail(t *testing.T) {
+
+	sub := &subscription{
+		definition: &fftypes.Subscription{},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	ed.maxAttempts = 1
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mockRunAsGroupPassthrough(mdi)
+	mdi.On("InsertDeadLetterEvent", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	ev1 := fftypes.NewUUID()
+	deadLettered := ed.deadLetterIfExhausted(&fftypes.Event{ID: ev1})
+	assert.False(t, deadLettered)
+	assert.Equal(t, 1, ed.failures[*ev1])
+}
+
+func TestDeadLetterDeleteFailureFail(t *testing.T) {
+
+	sub := &subscription{
+		definition: &fftypes.Subscription{},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	ed.maxAttempts = 1
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mockRunAsGroupPassthrough(mdi)
+	mdi.On("InsertDeadLetterEvent", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("DeleteDeliveryFailure", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	ev1 := fftypes.NewUUID()
+	deadLettered := ed.deadLetterIfExhausted(&fftypes.Event{ID: ev1})
+	assert.False(t, deadLettered)
+	assert.Equal(t, 1, ed.failures[*ev1])
+}
+
+func TestDeliveryFailureUpsertFail(t *testing.T) {
+
+	sub := &subscription{
+		definition: &fftypes.Subscription{},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	ed.maxAttempts = 2
+
+	mdi := &databasemocks.Plugin{}
+	ed.database = mdi
+	mdi.On("UpsertDeliveryFailure", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	ev1 := fftypes.NewUUID()
+	deadLettered := ed.deadLetterIfExhausted(&fftypes.Event{ID: ev1})
+	assert.False(t, deadLettered)
+	assert.Equal(t, 1, ed.failures[*ev1])
+	mdi.AssertExpectations(t)
+}
+
+func TestDeliveryFailureEphemeralNotPersisted(t *testing.T) {
+
+	sub := &subscription{
+		definition: &fftypes.Subscription{Ephemeral: true},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	ed.maxAttempts = 2
+
+	mdi := &databasemocks.Plugin{}
+	ed.database = mdi
+
+	ev1 := fftypes.NewUUID()
+	deadLettered := ed.deadLetterIfExhausted(&fftypes.Event{ID: ev1})
+	assert.False(t, deadLettered)
+	ed.loadDeliveryFailures()
+	ed.clearDeliveryFailures(&fftypes.Event{ID: ev1})
+	assert.Empty(t, ed.failures)
+	mdi.AssertExpectations(t)
+}
+
+func TestLoadDeliveryFailures(t *testing.T) {
+
+	sub := &subscription{
+		definition: &fftypes.Subscription{
+			SubscriptionRef: fftypes.SubscriptionRef{ID: fftypes.NewUUID()},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	ev1 := fftypes.NewUUID()
+	mdi := &databasemocks.Plugin{}
+	ed.database = mdi
+	mdi.On("GetDeliveryFailures", mock.Anything, mock.Anything).Return([]*fftypes.DeliveryFailure{
+		{Subscription: sub.definition.ID, Event: ev1, Attempts: 2},
+	}, nil, nil)
+
+	ed.loadDeliveryFailures()
+	assert.Equal(t, 2, ed.failures[*ev1])
+	mdi.AssertExpectations(t)
+}
+
+func TestLoadDeliveryFailuresFail(t *testing.T) {
+
+	sub := &subscription{
+		definition: &fftypes.Subscription{},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdi := &databasemocks.Plugin{}
+	ed.database = mdi
+	mdi.On("GetDeliveryFailures", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	ed.loadDeliveryFailures()
+	assert.Empty(t, ed.failures)
+	mdi.AssertExpectations(t)
+}
+
+func TestAckClearsFailures(t *testing.T) {
+
+	sub := &subscription{
+		definition: &fftypes.Subscription{},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("DeleteDeliveryFailure", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop")).Once()
+	mdi.On("DeleteDeliveryFailure", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	id1 := fftypes.NewUUID()
+	ed.inflight[*id1] = &fftypes.Event{ID: id1}
+	ed.failures[*id1] = 2
+	go ed.deliveryResponse(&fftypes.EventDeliveryResponse{ID: id1})
+
+	// The response is handed over without touching the DB, which happens in the delivery loop
+	an := <-ed.acksNacks
+	assert.False(t, an.isNack)
+	assert.Equal(t, 2, ed.failures[*id1])
+	mdi.AssertNotCalled(t, "DeleteDeliveryFailure", mock.Anything, mock.Anything, mock.Anything)
+
+	// Failing to clear the count is only logged
+	ed.clearDeliveryFailures(an.event)
+	assert.Empty(t, ed.failures)
+
+	// Events that never failed do not need clearing
+	ed.clearDeliveryFailures(an.event)
+	mdi.AssertNumberOfCalls(t, "DeleteDeliveryFailure", 1)
+}
+
+func newTestRedeliverDispatcher(t *testing.T) (*eventDispatcher, *fftypes.DeadLetterEvent, func()) {
+	sub := &subscription{
+		definition: &fftypes.Subscription{
+			SubscriptionRef: fftypes.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	dle := &fftypes.DeadLetterEvent{
+		ID:           fftypes.NewUUID(),
+		Namespace:    "ns1",
+		Subscription: sub.definition.ID,
+		Event:        fftypes.NewUUID(),
+	}
+	return ed, dle, cancel
+}
+
+func TestRedeliverDeadLetterAcked(t *testing.T) {
+	ed, dle, cancel := newTestRedeliverDispatcher(t)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mei := ed.transport.(*eventsmocks.PluginAll)
+	mdi.On("GetEventByID", mock.Anything, dle.Event).Return(&fftypes.Event{ID: dle.Event, Sequence: 12345}, nil)
+	mdi.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, nil)
+	mdi.On("DeleteDeadLetterEvent", mock.Anything, dle.ID).Return(nil)
+	deliver := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	deliver.RunFn = func(a mock.Arguments) {
+		ed.deliveryResponse(&fftypes.EventDeliveryResponse{ID: dle.Event})
+	}
+
+	err := ed.redeliver(context.Background(), dle)
+	assert.NoError(t, err)
+	assert.Empty(t, ed.redelivering)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRedeliverDeadLetterRejected(t *testing.T) {
+	ed, dle, cancel := newTestRedeliverDispatcher(t)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mei := ed.transport.(*eventsmocks.PluginAll)
+	mdi.On("GetEventByID", mock.Anything, dle.Event).Return(&fftypes.Event{ID: dle.Event, Sequence: 12345}, nil)
+	mdi.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, nil)
+	mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := ed.redeliver(context.Background(), dle)
+	assert.NoError(t, err)
+	assert.Empty(t, ed.redelivering)
+
+	mdi.AssertNotCalled(t, "DeleteDeadLetterEvent", mock.Anything, mock.Anything)
+}
+
+func TestRedeliverDeadLetterDeleteFail(t *testing.T) {
+	ed, dle, cancel := newTestRedeliverDispatcher(t)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("DeleteDeadLetterEvent", mock.Anything, dle.ID).Return(fmt.Errorf("pop"))
+
+	ed.redelivering[*dle.Event] = dle
+	ed.deliveryResponse(&fftypes.EventDeliveryResponse{ID: dle.Event})
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRedeliverDeadLetterGetEventFail(t *testing.T) {
+	ed, dle, cancel := newTestRedeliverDispatcher(t)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetEventByID", mock.Anything, dle.Event).Return(nil, fmt.Errorf("pop"))
+
+	err := ed.redeliver(context.Background(), dle)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRedeliverDeadLetterEventNotFound(t *testing.T) {
+	ed, dle, cancel := newTestRedeliverDispatcher(t)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetEventByID", mock.Anything, dle.Event).Return(nil, nil)
+
+	err := ed.redeliver(context.Background(), dle)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestRedeliverDeadLetterEnrichFail(t *testing.T) {
+	ed, dle, cancel := newTestRedeliverDispatcher(t)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetEventByID", mock.Anything, dle.Event).Return(&fftypes.Event{ID: dle.Event}, nil)
+	mdi.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := ed.redeliver(context.Background(), dle)
+	assert.EqualError(t, err, "pop")
+	assert.Empty(t, ed.redelivering)
+}
+
+func TestEventDispatcherExpressionFilterCriteria(t *testing.T) {
+	ctx := context.Background()
+	expressionFilter, err := NewFilterCompiler(ctx, database.EventQueryFactory.NewFilter(ctx)).Compile("type==message_confirmed OR type==message_rejected")
+	assert.NoError(t, err)
+	ed, cancel := newTestEventDispatcher(&subscription{
+		dispatcherElection: make(chan bool, 1),
+		definition: &fftypes.Subscription{
+			SubscriptionRef: fftypes.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+		expressionFilter: expressionFilter,
+	})
+	defer cancel()
+
+	fb := database.EventQueryFactory.NewFilter(ctx)
+	filter := ed.eventPoller.conf.addCriteria(fb.And(fb.Gt("sequence", 12345)))
+	fi, err := filter.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( sequence > 12345 ) && ( namespace == 'ns1' ) && ( ( type == 'message_confirmed' ) || ( type == 'message_rejected' ) )", fi.String())
+}
@@ -28,6 +28,8 @@ import (
 	"github.com/hyperledger/firefly/internal/events/system"
 	"github.com/hyperledger/firefly/internal/i18n"
 	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/internal/nsplugins"
+	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/internal/retry"
 	"github.com/hyperledger/firefly/internal/syshandlers"
 	"github.com/hyperledger/firefly/internal/sysmessaging"
@@ -49,6 +51,10 @@ type EventManager interface {
 	ChangeEvents() chan<- *fftypes.ChangeEvent
 	DeleteDurableSubscription(ctx context.Context, subDef *fftypes.Subscription) (err error)
 	CreateUpdateDurableSubscription(ctx context.Context, subDef *fftypes.Subscription, mustNew bool) (err error)
+	PauseSubscription(ctx context.Context, ns, subID string) error
+	ResumeSubscription(ctx context.Context, ns, subID string) error
+	RewindSubscription(ctx context.Context, ns, subID string, fromSequence int64) error
+	RedeliverDeadLetterEvent(ctx context.Context, ns, subID, eventID string) error
 	Start() error
 	WaitStop()
 
@@ -68,10 +74,12 @@ type EventManager interface {
 type eventManager struct {
 	ctx                  context.Context
 	publicstorage        publicstorage.Plugin
+	nsPlugins            nsplugins.Overrides
 	database             database.Plugin
 	identity             identity.Plugin
 	syshandlers          syshandlers.SystemHandlers
 	data                 data.Manager
+	messaging            privatemessaging.Manager
 	subManager           *subscriptionManager
 	retry                retry.Retry
 	txhelper             txcommon.Helper
@@ -83,8 +91,8 @@ type eventManager struct {
 	internalEvents       *system.Events
 }
 
-func NewEventManager(ctx context.Context, pi publicstorage.Plugin, di database.Plugin, ii identity.Plugin, sh syshandlers.SystemHandlers, dm data.Manager) (EventManager, error) {
-	if pi == nil || di == nil || ii == nil || dm == nil {
+func NewEventManager(ctx context.Context, pi publicstorage.Plugin, np nsplugins.Overrides, di database.Plugin, ii identity.Plugin, sh syshandlers.SystemHandlers, dm data.Manager, pm privatemessaging.Manager) (EventManager, error) {
+	if pi == nil || di == nil || ii == nil || dm == nil || pm == nil {
 		return nil, i18n.NewError(ctx, i18n.MsgInitializationNilDepError)
 	}
 	newPinNotifier := newEventNotifier(ctx, "pins")
@@ -92,10 +100,12 @@ func NewEventManager(ctx context.Context, pi publicstorage.Plugin, di database.P
 	em := &eventManager{
 		ctx:           log.WithLogField(ctx, "role", "event-manager"),
 		publicstorage: pi,
+		nsPlugins:     np,
 		database:      di,
 		identity:      ii,
 		syshandlers:   sh,
 		data:          dm,
+		messaging:     pm,
 		retry: retry.Retry{
 			InitialDelay: config.GetDuration(config.EventAggregatorRetryInitDelay),
 			MaximumDelay: config.GetDuration(config.EventAggregatorRetryMaxDelay),
@@ -119,6 +129,11 @@ func NewEventManager(ctx context.Context, pi publicstorage.Plugin, di database.P
 	return em, nil
 }
 
+// publicstorageFor returns the public storage plugin for a namespace, which might be overridden in its configuration
+func (em *eventManager) publicstorageFor(ns string) publicstorage.Plugin {
+	return em.nsPlugins.PublicStorage(ns, em.publicstorage)
+}
+
 func (em *eventManager) Start() (err error) {
 	err = em.subManager.start()
 	if err == nil {
@@ -165,6 +180,13 @@ func (em *eventManager) CreateUpdateDurableSubscription(ctx context.Context, sub
 		subDef.Transport = em.defaultTransport
 	}
 
+	if subDef.MaxDeliveryAttempts == 0 {
+		subDef.MaxDeliveryAttempts = fftypes.SubscriptionDefaultMaxDeliveryAttempts
+	}
+	if err = subDef.ValidateMaxDeliveryAttempts(ctx); err != nil {
+		return err
+	}
+
 	// Check it can be parsed before inserting (the submanager will check again when processing the creation, so we discard the result)
 	if _, err = em.subManager.parseSubscriptionDef(ctx, subDef); err != nil {
 		return err
@@ -181,6 +203,7 @@ func (em *eventManager) CreateUpdateDurableSubscription(ctx context.Context, sub
 		subDef.ID = existing.ID
 		subDef.Updated = fftypes.Now()
 		subDef.Options.FirstEvent = existing.Options.FirstEvent // we do not reset the sub position
+		subDef.Paused = existing.Paused                         // only changed by pausing/resuming the subscription
 		existing.Updated = subDef.Updated
 		def1, _ := json.Marshal(existing)
 		def2, _ := json.Marshal(subDef)
@@ -208,6 +231,22 @@ func (em *eventManager) DeleteDurableSubscription(ctx context.Context, subDef *f
 	return em.database.DeleteSubscriptionByID(ctx, subDef.ID)
 }
 
+func (em *eventManager) PauseSubscription(ctx context.Context, ns, subID string) error {
+	return em.subManager.pauseSubscription(ctx, ns, subID)
+}
+
+func (em *eventManager) ResumeSubscription(ctx context.Context, ns, subID string) error {
+	return em.subManager.resumeSubscription(ctx, ns, subID)
+}
+
+func (em *eventManager) RewindSubscription(ctx context.Context, ns, subID string, fromSequence int64) error {
+	return em.subManager.rewindSubscription(ctx, ns, subID, fromSequence)
+}
+
+func (em *eventManager) RedeliverDeadLetterEvent(ctx context.Context, ns, subID, eventID string) error {
+	return em.subManager.redeliverDeadLetterEvent(ctx, ns, subID, eventID)
+}
+
 func (em *eventManager) AddSystemEventListener(ns string, el system.EventListener) error {
 	return em.internalEvents.AddListener(ns, el)
 }
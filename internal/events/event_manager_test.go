@@ -27,6 +27,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/eventsmocks"
 	"github.com/hyperledger/firefly/mocks/identitymocks"
+	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/mocks/publicstoragemocks"
 	"github.com/hyperledger/firefly/mocks/syshandlersmocks"
 	"github.com/hyperledger/firefly/pkg/fftypes"
@@ -43,8 +44,9 @@ func newTestEventManager(t *testing.T) (*eventManager, func()) {
 	met := &eventsmocks.Plugin{}
 	mdm := &datamocks.Manager{}
 	msh := &syshandlersmocks.SystemHandlers{}
+	mpm := &privatemessagingmocks.Manager{}
 	met.On("Name").Return("ut").Maybe()
-	emi, err := NewEventManager(ctx, mpi, mdi, mii, msh, mdm)
+	emi, err := NewEventManager(ctx, mpi, nil, mdi, mii, msh, mdm, mpm)
 	em := emi.(*eventManager)
 	rag := mdi.On("RunAsGroup", em.ctx, mock.Anything).Maybe()
 	rag.RunFn = func(a mock.Arguments) {
@@ -74,7 +76,7 @@ func TestStartStop(t *testing.T) {
 }
 
 func TestStartStopBadDependencies(t *testing.T) {
-	_, err := NewEventManager(context.Background(), nil, nil, nil, nil, nil)
+	_, err := NewEventManager(context.Background(), nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 
 }
@@ -87,7 +89,8 @@ func TestStartStopBadTransports(t *testing.T) {
 	mpi := &publicstoragemocks.Plugin{}
 	mdm := &datamocks.Manager{}
 	msh := &syshandlersmocks.SystemHandlers{}
-	_, err := NewEventManager(context.Background(), mpi, mdi, mii, msh, mdm)
+	mpm := &privatemessagingmocks.Manager{}
+	_, err := NewEventManager(context.Background(), mpi, nil, mdi, mii, msh, mdm, mpm)
 	assert.Regexp(t, "FF10172", err)
 
 }
@@ -174,6 +177,21 @@ func TestCreateDurableSubscriptionDefaultSubCannotParse(t *testing.T) {
 	assert.Regexp(t, "FF10171", err)
 }
 
+func TestCreateDurableSubscriptionBadMaxDeliveryAttempts(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+	sub := &fftypes.Subscription{
+		SubscriptionRef: fftypes.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		MaxDeliveryAttempts: fftypes.SubscriptionMaxDeliveryAttemptsLimit + 1,
+	}
+	err := em.CreateUpdateDurableSubscription(em.ctx, sub, true)
+	assert.Regexp(t, "FF10335", err)
+}
+
 func TestCreateDurableSubscriptionBadFirstEvent(t *testing.T) {
 	em, cancel := newTestEventManager(t)
 	defer cancel()
@@ -257,6 +275,7 @@ func TestCreateDurableSubscriptionOk(t *testing.T) {
 	assert.NotNil(t, sub.ID)
 	assert.Equal(t, "websockets", sub.Transport)
 	assert.Equal(t, "12345", string(*sub.Options.FirstEvent))
+	assert.Equal(t, fftypes.SubscriptionDefaultMaxDeliveryAttempts, sub.MaxDeliveryAttempts)
 }
 
 func TestUpdateDurableSubscriptionOk(t *testing.T) {
@@ -301,7 +320,8 @@ func TestUpdateDurableSubscriptionNoOp(t *testing.T) {
 			Namespace: "ns1",
 			Name:      "sub1",
 		},
-		Transport: "websockets",
+		Transport:           "websockets",
+		MaxDeliveryAttempts: fftypes.SubscriptionDefaultMaxDeliveryAttempts,
 		Options: fftypes.SubscriptionOptions{
 			SubscriptionCoreOptions: fftypes.SubscriptionCoreOptions{
 				WithData: &no,
@@ -312,9 +332,11 @@ func TestUpdateDurableSubscriptionNoOp(t *testing.T) {
 	subExisting.Created = fftypes.Now()
 	subExisting.Updated = fftypes.Now()
 	subExisting.ID = fftypes.NewUUID()
+	subExisting.Paused = true
 	mdi.On("GetSubscriptionByName", mock.Anything, "ns1", "sub1").Return(&subExisting, nil) // return non-matching existing
 	err := em.CreateUpdateDurableSubscription(em.ctx, sub, false)
 	assert.NoError(t, err)
+	assert.True(t, sub.Paused)
 }
 
 func TestCreateDeleteDurableSubscriptionOk(t *testing.T) {
@@ -329,6 +351,19 @@ func TestCreateDeleteDurableSubscriptionOk(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestPauseResumeDurableSubscriptionNotFound(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+	err := em.PauseSubscription(em.ctx, "ns1", fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+	err = em.ResumeSubscription(em.ctx, "ns1", fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+	err = em.RewindSubscription(em.ctx, "ns1", fftypes.NewUUID().String(), 0)
+	assert.Regexp(t, "FF10109", err)
+	err = em.RedeliverDeadLetterEvent(em.ctx, "ns1", fftypes.NewUUID().String(), fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+}
+
 func TestAddInternalListener(t *testing.T) {
 	em, cancel := newTestEventManager(t)
 	ie := &system.Events{}
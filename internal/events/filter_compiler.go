@@ -0,0 +1,283 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+type filterTokenType int
+
+const (
+	filterTokenEOF filterTokenType = iota
+	filterTokenLParen
+	filterTokenRParen
+	filterTokenAnd
+	filterTokenOr
+	filterTokenNot
+	filterTokenOp
+	filterTokenWord
+	filterTokenString
+)
+
+type filterToken struct {
+	tokenType filterTokenType
+	value     string
+	pos       int
+}
+
+// filterOpNegations maps each comparison operator to its inverse, which allows NOT to be
+// pushed down to the comparisons (De Morgan) as database.Filter has no negation of its own
+var filterOpNegations = map[database.FilterOp]database.FilterOp{
+	database.FilterOpEq:       database.FilterOpNe,
+	database.FilterOpNe:       database.FilterOpEq,
+	database.FilterOpGt:       database.FilterOpLte,
+	database.FilterOpLte:      database.FilterOpGt,
+	database.FilterOpLt:       database.FilterOpGte,
+	database.FilterOpGte:      database.FilterOpLt,
+	database.FilterOpCont:     database.FilterOpNotCont,
+	database.FilterOpNotCont:  database.FilterOpCont,
+	database.FilterOpICont:    database.FilterOpNotICont,
+	database.FilterOpNotICont: database.FilterOpICont,
+}
+
+// FilterCompiler parses boolean filter expressions into a database.Filter, such as
+// `type==message_confirmed AND (namespace==ns1 OR NOT reference=="<uuid>")`.
+// AND binds tighter than OR, and NOT binds tightest of all. The comparison operators are the
+// database.FilterOp strings (== != > >= < <= %= %! ^= ^!), and values containing spaces
+// or operator characters can be double quoted.
+type FilterCompiler struct {
+	ctx    context.Context
+	fb     database.FilterBuilder
+	tokens []*filterToken
+	pos    int
+}
+
+func NewFilterCompiler(ctx context.Context, fb database.FilterBuilder) *FilterCompiler {
+	return &FilterCompiler{
+		ctx: ctx,
+		fb:  fb,
+	}
+}
+
+// Compile parses the expression, and validates the resulting filter against the fields of the builder
+func (fc *FilterCompiler) Compile(expression string) (database.Filter, error) {
+	tokens, err := fc.tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	fc.tokens = tokens
+	fc.pos = 0
+	filter, err := fc.parseOr(false)
+	if err != nil {
+		return nil, err
+	}
+	if t := fc.peek(); t.tokenType != filterTokenEOF {
+		return nil, fc.errorAt(t, fmt.Sprintf("unexpected '%s'", t.value))
+	}
+	if _, err := filter.Finalize(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+func (fc *FilterCompiler) errorAt(t *filterToken, reason string) error {
+	return i18n.NewError(fc.ctx, i18n.MsgFilterExpressionInvalid, t.pos, reason)
+}
+
+func isFilterOpChar(c byte) bool {
+	return strings.IndexByte("=!<>%^", c) >= 0
+}
+
+func (fc *FilterCompiler) tokenize(expression string) ([]*filterToken, error) {
+	tokens := []*filterToken{}
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, &filterToken{tokenType: filterTokenLParen, value: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, &filterToken{tokenType: filterTokenRParen, value: ")", pos: i})
+			i++
+		case isFilterOpChar(c):
+			op := ""
+			if i+1 < len(expression) {
+				if _, ok := filterOpNegations[database.FilterOp(expression[i:i+2])]; ok {
+					op = expression[i : i+2]
+				}
+			}
+			if op == "" {
+				if _, ok := filterOpNegations[database.FilterOp(expression[i:i+1])]; ok {
+					op = expression[i : i+1]
+				}
+			}
+			if op == "" {
+				return nil, i18n.NewError(fc.ctx, i18n.MsgFilterExpressionInvalid, i, fmt.Sprintf("unknown operator '%c'", c))
+			}
+			tokens = append(tokens, &filterToken{tokenType: filterTokenOp, value: op, pos: i})
+			i += len(op)
+		case c == '"':
+			start := i
+			value := strings.Builder{}
+			closed := false
+			for i++; i < len(expression) && !closed; i++ {
+				switch {
+				case expression[i] == '\\' && i+1 < len(expression):
+					i++
+					value.WriteByte(expression[i])
+				case expression[i] == '"':
+					closed = true
+				default:
+					value.WriteByte(expression[i])
+				}
+			}
+			if !closed {
+				return nil, i18n.NewError(fc.ctx, i18n.MsgFilterExpressionInvalid, start, "unterminated string")
+			}
+			tokens = append(tokens, &filterToken{tokenType: filterTokenString, value: value.String(), pos: start})
+		default:
+			start := i
+			for i < len(expression) && strings.IndexByte(" \t\n\r()\"", expression[i]) < 0 && !isFilterOpChar(expression[i]) {
+				i++
+			}
+			word := expression[start:i]
+			t := &filterToken{tokenType: filterTokenWord, value: word, pos: start}
+			switch strings.ToUpper(word) {
+			case "AND":
+				t.tokenType = filterTokenAnd
+			case "OR":
+				t.tokenType = filterTokenOr
+			case "NOT":
+				t.tokenType = filterTokenNot
+			}
+			tokens = append(tokens, t)
+		}
+	}
+	return append(tokens, &filterToken{tokenType: filterTokenEOF, value: "end of expression", pos: len(expression)}), nil
+}
+
+func (fc *FilterCompiler) peek() *filterToken {
+	return fc.tokens[fc.pos]
+}
+
+func (fc *FilterCompiler) next() *filterToken {
+	t := fc.tokens[fc.pos]
+	if t.tokenType != filterTokenEOF {
+		fc.pos++
+	}
+	return t
+}
+
+// parseOr handles "a OR b OR c" - which becomes "NOT a AND NOT b AND NOT c" when negated
+func (fc *FilterCompiler) parseOr(negate bool) (database.Filter, error) {
+	return fc.parseList(negate, filterTokenOr, fc.parseAnd)
+}
+
+// parseAnd handles "a AND b AND c" - which becomes "NOT a OR NOT b OR NOT c" when negated
+func (fc *FilterCompiler) parseAnd(negate bool) (database.Filter, error) {
+	return fc.parseList(negate, filterTokenAnd, fc.parseUnary)
+}
+
+func (fc *FilterCompiler) parseList(negate bool, separator filterTokenType, parseChild func(bool) (database.Filter, error)) (database.Filter, error) {
+	first, err := parseChild(negate)
+	if err != nil {
+		return nil, err
+	}
+	children := []database.Filter{first}
+	for fc.peek().tokenType == separator {
+		fc.next()
+		child, err := parseChild(negate)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	if (separator == filterTokenAnd) != negate {
+		return fc.fb.And(children...), nil
+	}
+	return fc.fb.Or(children...), nil
+}
+
+func (fc *FilterCompiler) parseUnary(negate bool) (database.Filter, error) {
+	t := fc.next()
+	switch t.tokenType {
+	case filterTokenNot:
+		return fc.parseUnary(!negate)
+	case filterTokenLParen:
+		filter, err := fc.parseOr(negate)
+		if err != nil {
+			return nil, err
+		}
+		if t := fc.next(); t.tokenType != filterTokenRParen {
+			return nil, fc.errorAt(t, fmt.Sprintf("expected ')' but found '%s'", t.value))
+		}
+		return filter, nil
+	case filterTokenWord:
+		return fc.parseComparison(t.value, negate)
+	default:
+		return nil, fc.errorAt(t, fmt.Sprintf("expected field name but found '%s'", t.value))
+	}
+}
+
+func (fc *FilterCompiler) parseComparison(field string, negate bool) (database.Filter, error) {
+	opToken := fc.next()
+	if opToken.tokenType != filterTokenOp {
+		return nil, fc.errorAt(opToken, fmt.Sprintf("expected operator after '%s' but found '%s'", field, opToken.value))
+	}
+	valueToken := fc.next()
+	if valueToken.tokenType != filterTokenWord && valueToken.tokenType != filterTokenString {
+		return nil, fc.errorAt(valueToken, fmt.Sprintf("expected value after '%s' but found '%s'", opToken.value, valueToken.value))
+	}
+	op := database.FilterOp(opToken.value)
+	if negate {
+		op = filterOpNegations[op]
+	}
+	value := valueToken.value
+	switch op {
+	case database.FilterOpEq:
+		return fc.fb.Eq(field, value), nil
+	case database.FilterOpNe:
+		return fc.fb.Neq(field, value), nil
+	case database.FilterOpGt:
+		return fc.fb.Gt(field, value), nil
+	case database.FilterOpGte:
+		return fc.fb.Gte(field, value), nil
+	case database.FilterOpLt:
+		return fc.fb.Lt(field, value), nil
+	case database.FilterOpLte:
+		return fc.fb.Lte(field, value), nil
+	case database.FilterOpCont:
+		return fc.fb.Contains(field, value), nil
+	case database.FilterOpNotCont:
+		return fc.fb.NotContains(field, value), nil
+	case database.FilterOpICont:
+		return fc.fb.IContains(field, value), nil
+	default: // database.FilterOpNotICont
+		return fc.fb.NotIContains(field, value), nil
+	}
+}
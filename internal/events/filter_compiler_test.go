@@ -0,0 +1,104 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func compileTestFilter(t *testing.T, expression string) (string, error) {
+	ctx := context.Background()
+	filter, err := NewFilterCompiler(ctx, database.EventQueryFactory.NewFilter(ctx)).Compile(expression)
+	if err != nil {
+		return "", err
+	}
+	fi, err := filter.Finalize()
+	assert.NoError(t, err)
+	return fi.String(), nil
+}
+
+func TestFilterCompilerSingle(t *testing.T) {
+	f, err := compileTestFilter(t, "type==message_confirmed")
+	assert.NoError(t, err)
+	assert.Equal(t, "type == 'message_confirmed'", f)
+}
+
+func TestFilterCompilerAnd(t *testing.T) {
+	f, err := compileTestFilter(t, "type==message_confirmed AND namespace == ns1 and sequence>10")
+	assert.NoError(t, err)
+	assert.Equal(t, "( type == 'message_confirmed' ) && ( namespace == 'ns1' ) && ( sequence > 10 )", f)
+}
+
+func TestFilterCompilerOrPrecedence(t *testing.T) {
+	f, err := compileTestFilter(t, "type==message_confirmed AND namespace==ns1 OR reference==f7f0b2a5-2c3f-4a1e-8a4b-6bb5e4b2f6a1")
+	assert.NoError(t, err)
+	assert.Equal(t, "( ( type == 'message_confirmed' ) && ( namespace == 'ns1' ) ) || ( reference == 'f7f0b2a5-2c3f-4a1e-8a4b-6bb5e4b2f6a1' )", f)
+}
+
+func TestFilterCompilerParentheses(t *testing.T) {
+	f, err := compileTestFilter(t, "type==message_confirmed AND (namespace==ns1 OR namespace==\"ns 2\")")
+	assert.NoError(t, err)
+	assert.Equal(t, "( type == 'message_confirmed' ) && ( ( namespace == 'ns1' ) || ( namespace == 'ns 2' ) )", f)
+}
+
+func TestFilterCompilerNot(t *testing.T) {
+	f, err := compileTestFilter(t, "NOT type==message_confirmed")
+	assert.NoError(t, err)
+	assert.Equal(t, "type != 'message_confirmed'", f)
+
+	f, err = compileTestFilter(t, "not not type!=message_confirmed")
+	assert.NoError(t, err)
+	assert.Equal(t, "type != 'message_confirmed'", f)
+}
+
+func TestFilterCompilerNotParentheses(t *testing.T) {
+	f, err := compileTestFilter(t, "NOT (type==message_confirmed AND (sequence>10 OR sequence<=5)) OR NOT (namespace%=ns AND namespace^=\"N\\\"S\")")
+	assert.NoError(t, err)
+	assert.Equal(t, "( ( type != 'message_confirmed' ) || ( ( sequence <= 10 ) && ( sequence > 5 ) ) ) || ( ( namespace %! 'ns' ) || ( namespace ^! 'N\"S' ) )", f)
+}
+
+func TestFilterCompilerAllOperatorsNegated(t *testing.T) {
+	f, err := compileTestFilter(t, "NOT (sequence>=1 OR sequence<2 OR namespace%!a OR namespace^!b)")
+	assert.NoError(t, err)
+	assert.Equal(t, "( sequence < 1 ) && ( sequence >= 2 ) && ( namespace %= 'a' ) && ( namespace ^= 'b' )", f)
+}
+
+func TestFilterCompilerInvalid(t *testing.T) {
+	for expression, errRegexp := range map[string]string{
+		"":                           "FF10313.*position 0.*expected field name",
+		"type":                       "FF10313.*position 4.*expected operator",
+		"type==":                     "FF10313.*position 6.*expected value",
+		"type==(":                    "FF10313.*position 6.*expected value",
+		"type ~ a":                   "FF10313.*position 5.*expected operator.*'~'",
+		"type = a":                   "FF10313.*position 5.*unknown operator '='",
+		"type==a AND":                "FF10313.*position 11.*expected field name",
+		"type==a OR (namespace==ns1": "FF10313.*position 26.*expected '\\)'",
+		"type==a namespace==ns1":     "FF10313.*position 8.*unexpected 'namespace'",
+		"type==a)":                   "FF10313.*position 7.*unexpected '\\)'",
+		"(type==":                    "FF10313.*position 7.*expected value",
+		"type==\"a":                  "FF10313.*position 6.*unterminated string",
+		"unknown==a":                 "FF10148.*unknown",
+		"reference==notauuid":        "FF10149.*reference",
+	} {
+		_, err := compileTestFilter(t, expression)
+		assert.Regexp(t, errRegexp, err, expression)
+	}
+}
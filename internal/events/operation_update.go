@@ -24,17 +24,34 @@ import (
 
 func (em *eventManager) OperationUpdate(plugin fftypes.Named, operationID *fftypes.UUID, txState fftypes.OpStatus, errorMessage string, opOutput fftypes.JSONObject) error {
 	op, err := em.database.GetOperationByID(em.ctx, operationID)
-	if err != nil || op == nil {
+	if err != nil {
 		log.L(em.ctx).Warnf("Operation update '%s' ignored, as it was not submitted by this node", operationID)
 		return nil
 	}
 
+	ops := []*fftypes.Operation{op}
+	if op == nil {
+		// Batch mint requests are correlated by the ID of the transaction, in which case the
+		// update applies to every mint operation within that transaction
+		fb := database.OperationQueryFactory.NewFilter(em.ctx)
+		ops, _, err = em.database.GetOperations(em.ctx, fb.And(
+			fb.Eq("tx", operationID),
+			fb.Eq("type", fftypes.OpTypeTokensMint),
+		))
+		if err != nil || len(ops) == 0 {
+			log.L(em.ctx).Warnf("Operation update '%s' ignored, as it was not submitted by this node", operationID)
+			return nil
+		}
+	}
+
 	update := database.OperationQueryFactory.NewUpdate(em.ctx).
 		Set("status", txState).
 		Set("error", errorMessage).
 		Set("output", opOutput)
-	if err := em.database.UpdateOperation(em.ctx, op.ID, update); err != nil {
-		return err
+	for _, op := range ops {
+		if err := em.database.UpdateOperation(em.ctx, op.ID, update); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -22,6 +22,8 @@ import (
 
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -79,3 +81,42 @@ func TestOperationUpdateError(t *testing.T) {
 	mdi.AssertExpectations(t)
 	mbi.AssertExpectations(t)
 }
+
+func TestOperationUpdateBatchByTX(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+	mdi := em.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	txID := fftypes.NewUUID()
+	op1 := &fftypes.Operation{ID: fftypes.NewUUID(), Transaction: txID}
+	op2 := &fftypes.Operation{ID: fftypes.NewUUID(), Transaction: txID}
+	mdi.On("GetOperationByID", em.ctx, txID).Return(nil, nil)
+	mdi.On("GetOperations", em.ctx, mock.MatchedBy(func(filter database.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.String() == fmt.Sprintf("( tx == '%s' ) && ( type == 'tokens_mint' )", txID)
+	})).Return([]*fftypes.Operation{op1, op2}, nil, nil)
+	mdi.On("UpdateOperation", em.ctx, op1.ID, mock.Anything).Return(nil)
+	mdi.On("UpdateOperation", em.ctx, op2.ID, mock.Anything).Return(nil)
+
+	err := em.OperationUpdate(mti, txID, fftypes.OpStatusSucceeded, "", fftypes.JSONObject{})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateBatchByTXNotFound(t *testing.T) {
+	em, cancel := newTestEventManager(t)
+	defer cancel()
+	mdi := em.database.(*databasemocks.Plugin)
+	mti := &tokenmocks.Plugin{}
+
+	txID := fftypes.NewUUID()
+	mdi.On("GetOperationByID", em.ctx, txID).Return(nil, nil)
+	mdi.On("GetOperations", em.ctx, mock.Anything).Return([]*fftypes.Operation{}, nil, nil)
+
+	err := em.OperationUpdate(mti, txID, fftypes.OpStatusSucceeded, "", fftypes.JSONObject{})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
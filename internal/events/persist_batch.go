@@ -58,16 +58,24 @@ func (em *eventManager) persistBatch(ctx context.Context /* db TX context*/, bat
 		return false, nil // This is not retryable. skip this batch
 	}
 
+	// Reject batches written by a newer node, in a format we cannot process
+	if err := batch.VerifySchemaVersion(ctx); err != nil {
+		l.Errorf("Invalid batch '%s': %s", batch.ID, err)
+		return false, nil // This is not retryable. skip this batch
+	}
+
 	// Verify the hash calculation
-	hash := batch.Payload.Hash()
-	if batch.Hash == nil || *batch.Hash != *hash {
-		l.Errorf("Invalid batch '%s'. Hash does not match payload. Found=%s Expected=%s", batch.ID, hash, batch.Hash)
+	if err := batch.VerifyHash(ctx); err != nil {
+		l.Errorf("Invalid batch '%s': %s", batch.ID, err)
 		return false, nil // This is not retryable. skip this batch
 	}
 
 	// Set confirmed on the batch (the messages should not be confirmed at this point - that's the aggregator's job)
 	batch.Confirmed = now
 
+	// The tags are not covered by the hash, so we calculate them ourselves from the messages
+	batch.Tags = batch.Payload.MessageTags()
+
 	// Upsert the batch itself, ensuring the hash does not change
 	err = em.database.UpsertBatch(ctx, batch, false)
 	if err != nil {
@@ -121,6 +129,10 @@ func (em *eventManager) persistReceivedData(ctx context.Context /* db TX context
 		return false, nil // skip data entry
 	}
 
+	// The expiry of data is local to each node, so is never accepted from another member of the network.
+	// Our own data keeps its expiry, as that is only set when the data is first inserted.
+	data.ExpiresAt = nil
+
 	// Insert the data, ensuring the hash doesn't change
 	if err := em.database.UpsertData(ctx, data, true, false); err != nil {
 		if err == database.HashMismatch {
@@ -43,6 +43,8 @@ type subscription struct {
 	tagFilter          *regexp.Regexp
 	topicsFilter       *regexp.Regexp
 	authorFilter       *regexp.Regexp
+	expressionFilter   database.Filter
+	paused             bool
 }
 
 type connection struct {
@@ -255,6 +257,156 @@ func (sm *subscriptionManager) deletedDurableSubscription(id *fftypes.UUID) {
 	}
 }
 
+func (sm *subscriptionManager) getDurableSubLocked(ctx context.Context, ns, subID string) (*subscription, error) {
+	u, err := fftypes.ParseUUID(ctx, subID)
+	if err != nil {
+		return nil, err
+	}
+	sub, ok := sm.durableSubs[*u]
+	if !ok || sub.definition.Namespace != ns {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	return sub, nil
+}
+
+func (sm *subscriptionManager) pauseSubscription(ctx context.Context, ns, subID string) error {
+	sm.mux.Lock()
+	defer sm.mux.Unlock()
+	sub, err := sm.getDurableSubLocked(ctx, ns, subID)
+	if err != nil {
+		return err
+	}
+	log.L(ctx).Infof("Pausing subscription %s:%s [%s]", sub.definition.Namespace, sub.definition.Name, sub.definition.ID)
+	update := database.SubscriptionQueryFactory.NewUpdate(ctx).Set("paused", true)
+	if err := sm.database.UpdateSubscription(ctx, ns, sub.definition.Name, update); err != nil {
+		return err
+	}
+	sub.paused = true
+	sub.definition.Paused = true
+	for _, conn := range sm.connections {
+		if dispatcher, ok := conn.dispatchers[*sub.definition.ID]; ok {
+			dispatcher.pause()
+		}
+	}
+	return nil
+}
+
+func (sm *subscriptionManager) resumeSubscription(ctx context.Context, ns, subID string) error {
+	sm.mux.Lock()
+	defer sm.mux.Unlock()
+	sub, err := sm.getDurableSubLocked(ctx, ns, subID)
+	if err != nil {
+		return err
+	}
+	log.L(ctx).Infof("Resuming subscription %s:%s [%s]", sub.definition.Namespace, sub.definition.Name, sub.definition.ID)
+	update := database.SubscriptionQueryFactory.NewUpdate(ctx).Set("paused", false)
+	if err := sm.database.UpdateSubscription(ctx, ns, sub.definition.Name, update); err != nil {
+		return err
+	}
+	sub.paused = false
+	sub.definition.Paused = false
+	for _, conn := range sm.connections {
+		if dispatcher, ok := conn.dispatchers[*sub.definition.ID]; ok {
+			dispatcher.resume()
+		}
+	}
+	return nil
+}
+
+func (sm *subscriptionManager) rewindSubscription(ctx context.Context, ns, subID string, fromSequence int64) error {
+	sm.mux.Lock()
+	defer sm.mux.Unlock()
+	sub, err := sm.getDurableSubLocked(ctx, ns, subID)
+	if err != nil {
+		return err
+	}
+
+	latest := int64(0)
+	f := database.EventQueryFactory.NewFilter(ctx).And().Sort("sequence").Descending().Limit(1)
+	newestEvents, _, err := sm.database.GetEvents(ctx, f)
+	if err != nil {
+		return err
+	}
+	if len(newestEvents) > 0 {
+		latest = newestEvents[0].Sequence
+	}
+	if fromSequence < 0 || fromSequence > latest {
+		return i18n.NewError(ctx, i18n.MsgRewindOutOfRange, fromSequence, latest)
+	}
+	log.L(ctx).Infof("Rewinding subscription %s:%s [%s] to sequence %d", sub.definition.Namespace, sub.definition.Name, sub.definition.ID, fromSequence)
+
+	// Close the active dispatchers, so nothing in-flight can move the offset past the rewind
+	var dispatchers []*eventDispatcher
+	for _, conn := range sm.connections {
+		if dispatcher, ok := conn.dispatchers[*sub.definition.ID]; ok {
+			dispatchers = append(dispatchers, dispatcher)
+			delete(conn.dispatchers, *sub.definition.ID)
+		}
+	}
+	sm.mux.Unlock()
+	for _, dispatcher := range dispatchers {
+		dispatcher.close()
+	}
+	sm.mux.Lock()
+
+	// The offset is the last sequence processed, so the next delivered event is fromSequence
+	err = sm.database.UpsertOffset(ctx, &fftypes.Offset{
+		Type:    fftypes.OffsetTypeSubscription,
+		Name:    sub.definition.ID.String(),
+		Current: fromSequence - 1,
+	}, true)
+	if err != nil {
+		return err
+	}
+
+	// Restart delivery, unless the subscription was removed or replaced while we were unlocked
+	if sm.durableSubs[*sub.definition.ID] == sub {
+		for _, conn := range sm.connections {
+			sm.matchSubToConnLocked(conn, sub)
+		}
+	}
+	return nil
+}
+
+func (sm *subscriptionManager) redeliverDeadLetterEvent(ctx context.Context, ns, subID, eventID string) error {
+	sm.mux.Lock()
+	sub, err := sm.getDurableSubLocked(ctx, ns, subID)
+	var dispatcher *eventDispatcher
+	if err == nil {
+		for _, conn := range sm.connections {
+			if d, ok := conn.dispatchers[*sub.definition.ID]; ok {
+				dispatcher = d
+				break
+			}
+		}
+	}
+	sm.mux.Unlock()
+	if err != nil {
+		return err
+	}
+	if dispatcher == nil {
+		return i18n.NewError(ctx, i18n.MsgSubscriptionNotConnected, sub.definition.ID)
+	}
+
+	eventUUID, err := fftypes.ParseUUID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	fb := database.DeadLetterEventQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("subscription", sub.definition.ID),
+		fb.Eq("event", eventUUID),
+	).Limit(1)
+	dles, _, err := sm.database.GetDeadLetterEvents(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(dles) == 0 {
+		return i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	return dispatcher.redeliver(ctx, dles[0])
+}
+
 func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef *fftypes.Subscription) (sub *subscription, err error) {
 	filter := subDef.Filter
 
@@ -307,6 +459,14 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 		}
 	}
 
+	var expressionFilter database.Filter
+	if filter.Expression != "" {
+		expressionFilter, err = NewFilterCompiler(ctx, database.EventQueryFactory.NewFilter(ctx)).Compile(filter.Expression)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	sub = &subscription{
 		dispatcherElection: make(chan bool, 1),
 		definition:         subDef,
@@ -315,6 +475,8 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 		tagFilter:          tagFilter,
 		topicsFilter:       topicsFilter,
 		authorFilter:       authorFilter,
+		expressionFilter:   expressionFilter,
+		paused:             subDef.Paused,
 	}
 	return sub, err
 }
@@ -47,6 +47,7 @@ func newTestSubManager(t *testing.T, mei *eventsmocks.PluginAll) (*subscriptionM
 	mei.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mdi.On("GetEvents", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Event{}, nil, nil).Maybe()
 	mdi.On("GetOffset", mock.Anything, mock.Anything, mock.Anything).Return(&fftypes.Offset{RowID: 3333333, Current: 0}, nil).Maybe()
+	mdi.On("GetDeliveryFailures", mock.Anything, mock.Anything).Return([]*fftypes.DeliveryFailure{}, nil, nil).Maybe()
 	sm, err := newSubscriptionManager(ctx, mdi, mdm, newEventNotifier(ctx, "ut"), msh)
 	assert.NoError(t, err)
 	sm.transports = map[string]events.Plugin{
@@ -311,6 +312,37 @@ func TestCreateSubscriptionBadGroupFilter(t *testing.T) {
 	assert.Regexp(t, "FF10171.*group", err)
 }
 
+func TestCreateSubscriptionBadExpressionFilter(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &fftypes.Subscription{
+		Filter: fftypes.SubscriptionFilter{
+			Expression: "type==message_confirmed AND",
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10313", err)
+}
+
+func TestCreateSubscriptionExpressionFilter(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	sub, err := sm.parseSubscriptionDef(sm.ctx, &fftypes.Subscription{
+		Filter: fftypes.SubscriptionFilter{
+			Expression: "type==message_confirmed AND NOT namespace==ns2",
+		},
+		Transport: "ut",
+	})
+	assert.NoError(t, err)
+	fi, err := sub.expressionFilter.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( type == 'message_confirmed' ) && ( namespace != 'ns2' )", fi.String())
+}
+
 func TestCreateSubscriptionBadAuthorFilter(t *testing.T) {
 	mei := &eventsmocks.PluginAll{}
 	sm, cancel := newTestSubManager(t, mei)
@@ -543,8 +575,10 @@ func TestUpdatedDurableSubscriptionOK(t *testing.T) {
 	}
 	sub2 := *sub
 	sub2.Updated = fftypes.Now()
+	sub2.Paused = true
 	s := &subscription{
 		definition: sub,
+		paused:     true,
 	}
 	sm.durableSubs[*subID] = s
 
@@ -570,6 +604,8 @@ func TestUpdatedDurableSubscriptionOK(t *testing.T) {
 	assert.NotEqual(t, s, sm.durableSubs[*subID])
 	assert.NotEmpty(t, sm.connections["conn1"].dispatchers)
 	assert.NotEmpty(t, sm.durableSubs)
+	assert.True(t, sm.durableSubs[*subID].paused)
+	assert.True(t, sm.connections["conn1"].dispatchers[*subID].paused)
 }
 
 func TestMatchedSubscriptionWithLockUnknownTransport(t *testing.T) {
@@ -638,3 +674,350 @@ func TestDeleteDurableSubscriptionOk(t *testing.T) {
 	assert.Empty(t, sm.durableSubs)
 	<-ed.closed
 }
+
+func TestPauseResumeSubscription(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+
+	subID := fftypes.NewUUID()
+	sub := &subscription{
+		definition: &fftypes.Subscription{
+			SubscriptionRef: fftypes.SubscriptionRef{
+				ID:        subID,
+				Namespace: "ns1",
+				Name:      "sub1",
+			},
+			Transport: "ut",
+		},
+	}
+	sm.durableSubs[*subID] = sub
+	ed, cancelEd := newTestEventDispatcher(sub)
+	defer cancelEd()
+	sm.connections["conn1"] = &connection{
+		ei:        mei,
+		id:        "conn1",
+		transport: "ut",
+		dispatchers: map[fftypes.UUID]*eventDispatcher{
+			*subID: ed,
+		},
+	}
+	sm.connections["conn2"] = &connection{
+		ei:          mei,
+		id:          "conn2",
+		transport:   "ut",
+		dispatchers: map[fftypes.UUID]*eventDispatcher{},
+	}
+
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.On("UpdateSubscription", mock.Anything, "ns1", "sub1", mock.Anything).Return(nil)
+
+	err := sm.pauseSubscription(sm.ctx, "ns1", subID.String())
+	assert.NoError(t, err)
+	assert.True(t, sub.paused)
+	assert.True(t, sub.definition.Paused)
+	assert.True(t, ed.paused)
+
+	err = sm.resumeSubscription(sm.ctx, "ns1", subID.String())
+	assert.NoError(t, err)
+	assert.False(t, sub.paused)
+	assert.False(t, sub.definition.Paused)
+	assert.False(t, ed.paused)
+	mdi.AssertExpectations(t)
+}
+
+func TestPauseResumeSubscriptionUpdateFail(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.On("UpdateSubscription", mock.Anything, "ns1", "sub1", mock.Anything).Return(fmt.Errorf("pop"))
+
+	subID := fftypes.NewUUID()
+	sub := &subscription{
+		definition: &fftypes.Subscription{
+			SubscriptionRef: fftypes.SubscriptionRef{ID: subID, Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	sm.durableSubs[*subID] = sub
+
+	err := sm.pauseSubscription(sm.ctx, "ns1", subID.String())
+	assert.EqualError(t, err, "pop")
+	assert.False(t, sub.paused)
+	sub.paused = true
+	err = sm.resumeSubscription(sm.ctx, "ns1", subID.String())
+	assert.EqualError(t, err, "pop")
+	assert.True(t, sub.paused)
+}
+
+func TestPauseResumeSubscriptionNotFound(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+
+	subID := fftypes.NewUUID()
+	sm.durableSubs[*subID] = &subscription{
+		definition: &fftypes.Subscription{
+			SubscriptionRef: fftypes.SubscriptionRef{ID: subID, Namespace: "ns1", Name: "sub1"},
+		},
+	}
+
+	err := sm.pauseSubscription(sm.ctx, "ns2", subID.String())
+	assert.Regexp(t, "FF10109", err)
+	err = sm.resumeSubscription(sm.ctx, "ns1", fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+	err = sm.pauseSubscription(sm.ctx, "ns1", "!uuid")
+	assert.Regexp(t, "FF10142", err)
+	err = sm.resumeSubscription(sm.ctx, "ns1", "!uuid")
+	assert.Regexp(t, "FF10142", err)
+}
+
+func newTestRewindSub(t *testing.T, sm *subscriptionManager, mei *eventsmocks.PluginAll) (*subscription, *eventDispatcher, func()) {
+	subID := fftypes.NewUUID()
+	sub := &subscription{
+		definition: &fftypes.Subscription{
+			SubscriptionRef: fftypes.SubscriptionRef{
+				ID:        subID,
+				Namespace: "ns1",
+				Name:      "sub1",
+			},
+			Transport: "ut",
+		},
+	}
+	sm.durableSubs[*subID] = sub
+	ed, cancelEd := newTestEventDispatcher(sub)
+	ed.start()
+	sm.connections["conn1"] = &connection{
+		ei:        mei,
+		id:        "conn1",
+		transport: "ut",
+		matcher:   func(sr fftypes.SubscriptionRef) bool { return *sr.ID == *subID },
+		dispatchers: map[fftypes.UUID]*eventDispatcher{
+			*subID: ed,
+		},
+	}
+	return sub, ed, cancelEd
+}
+
+func TestRewindSubscription(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	sub, ed, cancelEd := newTestRewindSub(t, sm, mei)
+	defer cancelEd()
+
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.ExpectedCalls = nil
+	mdi.On("GetEvents", mock.Anything, mock.Anything).Return([]*fftypes.Event{{Sequence: 100}}, nil, nil)
+	mdi.On("UpsertOffset", mock.Anything, mock.MatchedBy(func(o *fftypes.Offset) bool {
+		return o.Type == fftypes.OffsetTypeSubscription &&
+			o.Name == sub.definition.ID.String() &&
+			o.Current == 9
+	}), true).Return(nil)
+
+	err := sm.rewindSubscription(sm.ctx, "ns1", sub.definition.ID.String(), 10)
+	assert.NoError(t, err)
+
+	// The old dispatcher is closed, and replaced by a new one reading from the rewound offset
+	<-ed.closed
+	newED := sm.connections["conn1"].dispatchers[*sub.definition.ID]
+	assert.NotNil(t, newED)
+	assert.NotEqual(t, ed, newED)
+	newED.close()
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRewindSubscriptionLatestSequence(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	sub, _, cancelEd := newTestRewindSub(t, sm, mei)
+	defer cancelEd()
+
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.ExpectedCalls = nil
+	mdi.On("GetEvents", mock.Anything, mock.Anything).Return([]*fftypes.Event{{Sequence: 100}}, nil, nil)
+	mdi.On("UpsertOffset", mock.Anything, mock.MatchedBy(func(o *fftypes.Offset) bool {
+		return o.Current == 99
+	}), true).Return(nil)
+
+	err := sm.rewindSubscription(sm.ctx, "ns1", sub.definition.ID.String(), 100)
+	assert.NoError(t, err)
+	sm.connections["conn1"].dispatchers[*sub.definition.ID].close()
+}
+
+func TestRewindSubscriptionOutOfRange(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	sub, ed, cancelEd := newTestRewindSub(t, sm, mei)
+	defer cancelEd()
+
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.ExpectedCalls = nil
+	mdi.On("GetEvents", mock.Anything, mock.Anything).Return([]*fftypes.Event{{Sequence: 100}}, nil, nil)
+
+	err := sm.rewindSubscription(sm.ctx, "ns1", sub.definition.ID.String(), 101)
+	assert.Regexp(t, "FF10295.*101.*100", err)
+	err = sm.rewindSubscription(sm.ctx, "ns1", sub.definition.ID.String(), -1)
+	assert.Regexp(t, "FF10295", err)
+
+	// The dispatcher is left untouched
+	assert.Equal(t, ed, sm.connections["conn1"].dispatchers[*sub.definition.ID])
+	mdi.AssertNotCalled(t, "UpsertOffset", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRewindSubscriptionNoEvents(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	sub, _, cancelEd := newTestRewindSub(t, sm, mei)
+	defer cancelEd()
+
+	err := sm.rewindSubscription(sm.ctx, "ns1", sub.definition.ID.String(), 1)
+	assert.Regexp(t, "FF10295", err)
+}
+
+func TestRewindSubscriptionNotFound(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+
+	err := sm.rewindSubscription(sm.ctx, "ns1", fftypes.NewUUID().String(), 0)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestRewindSubscriptionGetEventsFail(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	sub, _, cancelEd := newTestRewindSub(t, sm, mei)
+	defer cancelEd()
+
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.ExpectedCalls = nil
+	mdi.On("GetEvents", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := sm.rewindSubscription(sm.ctx, "ns1", sub.definition.ID.String(), 0)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRewindSubscriptionUpsertOffsetFail(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	sub, _, cancelEd := newTestRewindSub(t, sm, mei)
+	defer cancelEd()
+
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.ExpectedCalls = nil
+	mdi.On("GetEvents", mock.Anything, mock.Anything).Return([]*fftypes.Event{{Sequence: 100}}, nil, nil)
+	mdi.On("UpsertOffset", mock.Anything, mock.Anything, true).Return(fmt.Errorf("pop"))
+
+	err := sm.rewindSubscription(sm.ctx, "ns1", sub.definition.ID.String(), 0)
+	assert.EqualError(t, err, "pop")
+}
+
+func newTestDeadLetterSub(sm *subscriptionManager, mei *eventsmocks.PluginAll) (*subscription, *eventDispatcher, func()) {
+	subID := fftypes.NewUUID()
+	sub := &subscription{
+		definition: &fftypes.Subscription{
+			SubscriptionRef: fftypes.SubscriptionRef{
+				ID:        subID,
+				Namespace: "ns1",
+				Name:      "sub1",
+			},
+			Transport: "ut",
+		},
+	}
+	sm.durableSubs[*subID] = sub
+	ed, cancelEd := newTestEventDispatcher(sub)
+	sm.connections["conn1"] = &connection{
+		ei:        mei,
+		id:        "conn1",
+		transport: "ut",
+		dispatchers: map[fftypes.UUID]*eventDispatcher{
+			*subID: ed,
+		},
+	}
+	return sub, ed, cancelEd
+}
+
+func TestRedeliverDeadLetterEvent(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	sub, ed, cancelEd := newTestDeadLetterSub(sm, mei)
+	defer cancelEd()
+
+	evID := fftypes.NewUUID()
+	dle := &fftypes.DeadLetterEvent{ID: fftypes.NewUUID(), Subscription: sub.definition.ID, Event: evID}
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.On("GetDeadLetterEvents", mock.Anything, mock.Anything).Return([]*fftypes.DeadLetterEvent{dle}, nil, nil)
+	edi := ed.database.(*databasemocks.Plugin)
+	edi.On("GetEventByID", mock.Anything, evID).Return(nil, fmt.Errorf("pop"))
+
+	err := sm.redeliverDeadLetterEvent(sm.ctx, "ns1", sub.definition.ID.String(), evID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	edi.AssertExpectations(t)
+}
+
+func TestRedeliverDeadLetterEventNoDeadLetter(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	sub, _, cancelEd := newTestDeadLetterSub(sm, mei)
+	defer cancelEd()
+
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.On("GetDeadLetterEvents", mock.Anything, mock.Anything).Return([]*fftypes.DeadLetterEvent{}, nil, nil)
+
+	err := sm.redeliverDeadLetterEvent(sm.ctx, "ns1", sub.definition.ID.String(), fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestRedeliverDeadLetterEventQueryFail(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	sub, _, cancelEd := newTestDeadLetterSub(sm, mei)
+	defer cancelEd()
+
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.On("GetDeadLetterEvents", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := sm.redeliverDeadLetterEvent(sm.ctx, "ns1", sub.definition.ID.String(), fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRedeliverDeadLetterEventBadEventID(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	sub, _, cancelEd := newTestDeadLetterSub(sm, mei)
+	defer cancelEd()
+
+	err := sm.redeliverDeadLetterEvent(sm.ctx, "ns1", sub.definition.ID.String(), "!uuid")
+	assert.Regexp(t, "FF10142", err)
+}
+
+func TestRedeliverDeadLetterEventNotConnected(t *testing.T) {
+	mei := &eventsmocks.PluginAll{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+
+	subID := fftypes.NewUUID()
+	sm.durableSubs[*subID] = &subscription{
+		definition: &fftypes.Subscription{
+			SubscriptionRef: fftypes.SubscriptionRef{ID: subID, Namespace: "ns1", Name: "sub1"},
+		},
+	}
+
+	err := sm.redeliverDeadLetterEvent(sm.ctx, "ns1", subID.String(), fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10306", err)
+	err = sm.redeliverDeadLetterEvent(sm.ctx, "ns2", subID.String(), fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+}
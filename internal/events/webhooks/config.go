@@ -21,6 +21,18 @@ import (
 	"github.com/hyperledger/firefly/internal/restclient"
 )
 
+const (
+	// WebhooksMaxRetries is the number of times a webhook request is retried, after a connection failure or a 5xx response
+	WebhooksMaxRetries = "maxRetries"
+	// WebhooksRetryInitialDelay is the delay before the first retry of a webhook request
+	WebhooksRetryInitialDelay = "retryInitialDelay"
+	// WebhooksRetryMaxDelay is the maximum delay between retries of a webhook request
+	WebhooksRetryMaxDelay = "retryMaxDelay"
+)
+
 func (wh *WebHooks) InitPrefix(prefix config.Prefix) {
 	restclient.InitPrefix(prefix)
+	prefix.AddKnownKey(WebhooksMaxRetries, 0)
+	prefix.AddKnownKey(WebhooksRetryInitialDelay, "250ms")
+	prefix.AddKnownKey(WebhooksRetryMaxDelay, "30s")
 }
@@ -19,7 +19,10 @@ package webhooks
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -32,6 +35,7 @@ import (
 	"github.com/hyperledger/firefly/internal/i18n"
 	"github.com/hyperledger/firefly/internal/log"
 	"github.com/hyperledger/firefly/internal/restclient"
+	"github.com/hyperledger/firefly/internal/retry"
 	"github.com/hyperledger/firefly/pkg/events"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 )
@@ -42,8 +46,13 @@ type WebHooks struct {
 	callbacks    events.Callbacks
 	client       *resty.Client
 	connID       string
+	maxRetries   int
+	retry        *retry.Retry
 }
 
+// SignatureHeader is the header set on webhook requests from subscriptions configured with a secret
+const SignatureHeader = "X-FireFly-Signature"
+
 type whRequest struct {
 	r         *resty.Request
 	url       string
@@ -51,6 +60,7 @@ type whRequest struct {
 	body      fftypes.JSONObject
 	forceJSON bool
 	replyTx   string
+	secret    string
 }
 
 type whResponse struct {
@@ -68,6 +78,11 @@ func (wh *WebHooks) Init(ctx context.Context, prefix config.Prefix, callbacks ev
 		callbacks:    callbacks,
 		client:       restclient.New(ctx, prefix),
 		connID:       fftypes.ShortID(),
+		maxRetries:   prefix.GetInt(WebhooksMaxRetries),
+		retry: &retry.Retry{
+			InitialDelay: prefix.GetDuration(WebhooksRetryInitialDelay),
+			MaximumDelay: prefix.GetDuration(WebhooksRetryMaxDelay),
+		},
 	}
 	// We have a single logical connection, that matches all subscriptions
 	return callbacks.RegisterConnection(wh.connID, func(sr fftypes.SubscriptionRef) bool { return true })
@@ -108,6 +123,10 @@ func (wh *WebHooks) GetOptionsSchema(ctx context.Context) string {
 				"type": "string",
 				"description": "%s"
 			},
+			"secret": {
+				"type": "string",
+				"description": "%s"
+			},
 			"headers": {
 				"type": "object",
 				"description": "%s",
@@ -157,6 +176,7 @@ func (wh *WebHooks) GetOptionsSchema(ctx context.Context) string {
 		i18n.Expand(ctx, i18n.MsgWebhooksOptReply),
 		i18n.Expand(ctx, i18n.MsgWebhooksOptReplyTag),
 		i18n.Expand(ctx, i18n.MsgWebhooksOptReplyTx),
+		i18n.Expand(ctx, i18n.MsgWebhooksOptSecret),
 		i18n.Expand(ctx, i18n.MsgWebhooksOptHeaders),
 		i18n.Expand(ctx, i18n.MsgWebhooksOptQuery),
 		i18n.Expand(ctx, i18n.MsgWebhooksOptInput),
@@ -175,6 +195,7 @@ func (wh *WebHooks) buildRequest(options fftypes.JSONObject, firstData fftypes.J
 		method:    options.GetString("method"),
 		forceJSON: options.GetBool("json"),
 		replyTx:   options.GetString("replytx"),
+		secret:    options.GetString("secret"),
 	}
 	if req.url == "" {
 		return nil, i18n.NewError(wh.ctx, i18n.MsgWebhookURLEmpty)
@@ -284,24 +305,34 @@ func (wh *WebHooks) attemptRequest(sub *fftypes.Subscription, event *fftypes.Eve
 		return nil, nil, err
 	}
 
+	var body []byte
 	if req.method == http.MethodPost || req.method == http.MethodPatch || req.method == http.MethodPut {
 		switch {
 		case !withData:
 			// We are just sending the event itself
-			req.r.SetBody(event)
+			body, err = json.Marshal(event)
 		case req.body != nil:
 			// We might have been told to extract a body from the first data record
-			req.r.SetBody(req.body)
+			body, err = json.Marshal(req.body)
 		case len(allData) > 1:
 			// We've got an array of data to POST
-			req.r.SetBody(allData)
+			body, err = json.Marshal(allData)
 		default:
 			// Otherwise just send the first object directly
-			req.r.SetBody(firstData)
+			body, err = json.Marshal(firstData)
+		}
+		if err != nil {
+			return nil, nil, err
 		}
+		req.r.SetBody(body)
+	}
+	if req.secret != "" {
+		mac := hmac.New(sha256.New, []byte(req.secret))
+		mac.Write(body)
+		req.r.SetHeader(SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
 	}
 
-	resp, err := req.r.Execute(req.method, req.url)
+	resp, err := wh.execute(req)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -342,6 +373,24 @@ func (wh *WebHooks) attemptRequest(sub *fftypes.Subscription, event *fftypes.Eve
 	return req, res, nil
 }
 
+// execute invokes the webhook, retrying connection failures and 5xx responses up to the
+// configured maximum number of retries. The response to the final attempt is returned.
+func (wh *WebHooks) execute(req *whRequest) (resp *resty.Response, err error) {
+	err = wh.retry.Do(wh.ctx, "webhook request", func(attempt int) (retry bool, err error) {
+		resp, err = req.r.Execute(req.method, req.url)
+		canRetry := attempt <= wh.maxRetries
+		if err == nil && resp.StatusCode() >= 500 && canRetry {
+			_ = resp.RawBody().Close()
+			return true, i18n.NewError(wh.ctx, i18n.MsgWebhookFailedStatus, resp.StatusCode())
+		}
+		return canRetry, err
+	})
+	if err == nil {
+		restclient.OnAfterResponse(wh.client, resp) // required using SetDoNotParseResponse
+	}
+	return resp, err
+}
+
 func (wh *WebHooks) doDelivery(connID string, reply bool, sub *fftypes.Subscription, event *fftypes.EventDelivery, data []*fftypes.Data) error {
 	req, res, gwErr := wh.attemptRequest(sub, event, data)
 	if gwErr != nil {
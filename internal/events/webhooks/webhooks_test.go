@@ -18,11 +18,16 @@ package webhooks
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/hyperledger/firefly/internal/config"
@@ -606,3 +611,152 @@ func TestDeliveryRequestReplyToReply(t *testing.T) {
 	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
 	assert.NoError(t, err)
 }
+
+func TestRequestRetry5xxThenSuccess(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+	wh.maxRetries = 2
+	wh.retry.InitialDelay = time.Microsecond
+
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls < 3 {
+			res.WriteHeader(503)
+			return
+		}
+		res.Header().Set("Content-Type", "application/json")
+		res.WriteHeader(200)
+		res.Write([]byte(`{"ok":true}`))
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := &fftypes.Subscription{}
+	to := sub.Options.TransportOptions()
+	to["url"] = fmt.Sprintf("http://%s/myapi", server.Listener.Addr())
+	event := &fftypes.EventDelivery{
+		Event: fftypes.Event{
+			ID: fftypes.NewUUID(),
+		},
+	}
+
+	_, res, err := wh.attemptRequest(sub, event, []*fftypes.Data{})
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.Status)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRequestRetry5xxExhausted(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+	wh.maxRetries = 1
+	wh.retry.InitialDelay = time.Microsecond
+
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		res.WriteHeader(500)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := &fftypes.Subscription{}
+	to := sub.Options.TransportOptions()
+	to["url"] = fmt.Sprintf("http://%s/myapi", server.Listener.Addr())
+	event := &fftypes.EventDelivery{
+		Event: fftypes.Event{
+			ID: fftypes.NewUUID(),
+		},
+	}
+
+	_, res, err := wh.attemptRequest(sub, event, []*fftypes.Data{})
+	assert.NoError(t, err)
+	assert.Equal(t, 500, res.Status)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRequestRetryConnectionFailure(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+	wh.maxRetries = 1
+	wh.retry.InitialDelay = time.Microsecond
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	sub := &fftypes.Subscription{}
+	to := sub.Options.TransportOptions()
+	to["url"] = fmt.Sprintf("http://%s/myapi", server.Listener.Addr())
+	event := &fftypes.EventDelivery{
+		Event: fftypes.Event{
+			ID: fftypes.NewUUID(),
+		},
+	}
+
+	_, _, err := wh.attemptRequest(sub, event, []*fftypes.Data{})
+	assert.Error(t, err)
+}
+
+func TestRequestSignature(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	called := false
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		mac := hmac.New(sha256.New, []byte("secret1"))
+		mac.Write(body)
+		assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), req.Header.Get(SignatureHeader))
+		called = true
+		res.WriteHeader(204)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := &fftypes.Subscription{}
+	to := sub.Options.TransportOptions()
+	to["url"] = fmt.Sprintf("http://%s/myapi", server.Listener.Addr())
+	to["secret"] = "secret1"
+	event := &fftypes.EventDelivery{
+		Event: fftypes.Event{
+			ID: fftypes.NewUUID(),
+		},
+	}
+
+	_, res, err := wh.attemptRequest(sub, event, []*fftypes.Data{})
+	assert.NoError(t, err)
+	assert.Equal(t, 204, res.Status)
+	assert.True(t, called)
+}
+
+func TestRequestBadBodyData(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	yes := true
+	sub := &fftypes.Subscription{
+		Options: fftypes.SubscriptionOptions{
+			SubscriptionCoreOptions: fftypes.SubscriptionCoreOptions{
+				WithData: &yes,
+			},
+		},
+	}
+	to := sub.Options.TransportOptions()
+	to["url"] = "http://localhost:12345/myapi"
+	event := &fftypes.EventDelivery{
+		Event: fftypes.Event{
+			ID: fftypes.NewUUID(),
+		},
+	}
+
+	_, _, err := wh.attemptRequest(sub, event, []*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`!json`)},
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`"value2"`)},
+	})
+	assert.Error(t, err)
+}
@@ -194,4 +194,90 @@ var (
 	MsgTokensRESTErr               = ffm("FF10274", "Error from tokens service: %s")
 	MsgTokenPoolDuplicate          = ffm("FF10275", "Duplicate token pool")
 	MsgTokenPoolRejected           = ffm("FF10276", "Token pool with ID '%s' was rejected. Please check the FireFly logs for more information")
+	MsgInvalidEnumValue            = ffm("FF10277", "Invalid value '%s' for %s", 400)
+	MsgClientCertRequired          = ffm("FF10278", "A client certificate is required", 401)
+	MsgClientCertIdentityUnknown   = ffm("FF10279", "The client certificate subject '%s' does not match a registered organization", 401)
+	MsgGroupTooLarge               = ffm("FF10280", "Group '%s' exceeds maximum size %d", 400)
+	MsgStorageListNotSupported     = ffm("FF10281", "Public storage plugin '%s' does not support listing stored items", 501)
+	MsgBatchPayloadHashMismatch    = ffm("FF10282", "Batch payload hash mismatch: expected %s got %s")
+	MsgPublicMessageSentPrivately  = ffm("FF10283", "Public-sensitivity message cannot be sent privately", 400)
+	MsgBatchPayloadInvalidJSON     = ffm("FF10284", "Invalid batch payload: data entry %d does not contain valid JSON")
+	MsgBatchPayloadNilDataID       = ffm("FF10285", "Invalid batch payload: data entry %d has a nil ID")
+	MsgBatchPayloadNilDataHash     = ffm("FF10286", "Invalid batch payload: data entry %d has a nil hash")
+	MsgBatchPayloadNilTXID         = ffm("FF10287", "Invalid batch payload: nil transaction ID")
+	MsgGroupSchemaMismatch         = ffm("FF10288", "Message data does not match group schema %s/%s", 400)
+	MsgRateLimitExceeded           = ffm("FF10289", "Rate limit exceeded. Retry after %ds", 429)
+	MsgThreadMessageNotFound       = ffm("FF10290", "Message '%s' not found in namespace '%s' to continue thread", 400)
+	MsgThreadNotRoot               = ffm("FF10291", "Thread '%s' must be the ID of the root message of the thread '%s'", 400)
+	MsgEthInvalidBlockNumber       = ffm("FF10292", "Invalid block number '%s' returned from the ethereum node")
+	MsgNilDataItem                 = ffm("FF10293", "Data entry %d is nil", 400)
+	MsgNoMintRecipients            = ffm("FF10294", "At least one recipient must be specified to mint tokens", 400)
+	MsgRewindOutOfRange            = ffm("FF10295", "Cannot rewind subscription to sequence %d - must be between 0 and the latest event sequence %d", 400)
+	MsgSSENotSupported             = ffm("FF10341", "The HTTP connection does not support streaming server-sent events", 500)
+	MsgInvalidLastEventID          = ffm("FF10342", "Invalid Last-Event-ID '%s' - must be an event sequence number", 400)
+	MsgDefinitionBroadcastFailed   = ffm("FF10296", "Failed to broadcast definition after %d attempts")
+	MsgBlobChecksumMismatch        = ffm("FF10297", "Blob '%s' failed checksum verification. Expected=%d Actual=%d")
+	MsgInvalidSigningKey           = ffm("FF10346", "Invalid signing key material: %s", 400)
+	MsgSigningKeyMismatch          = ffm("FF10347", "Signing key is for '%s', which does not match signing identity '%s'", 400)
+	MsgVerifyFailedInvalidDigest   = ffm("FF10298", "Invalid message data digest. Digest=%s Expected=%s", 400)
+	MsgDataItemIndexOutOfRange     = ffm("FF10299", "Data item %d out of range - message has %d data items", 400)
+	MsgAdminNotEnabledForNamespace = ffm("FF10300", "Admin operations are not enabled for namespace '%s'", 403)
+	MsgDuplicateMemberAlias        = ffm("FF10301", "Member %d has alias '%s', which is already in use in the group", 400)
+	MsgPreviousDataNotFound        = ffm("FF10302", "Previous data version '%s' not found", 404)
+	MsgDataVersionFork             = ffm("FF10303", "Data '%s' has multiple next versions: %s", 409)
+	MsgTokenPoolFrozen             = ffm("FF10304", "Token pool '%s' is frozen", 409)
+	MsgBroadcastTimeout            = ffm("FF10305", "Broadcast of message '%s' was not confirmed within %s", 408)
+	MsgSubscriptionNotConnected    = ffm("FF10306", "Subscription '%s' has no active connection to redeliver the event to", 409)
+	MsgGroupNonceNotGreater        = ffm("FF10307", "Group init nonce %d is not greater than nonce %d of previous group '%s'", 409)
+	MsgUnknownSecretsProvider      = ffm("FF10308", "Unknown secrets provider '%s'")
+	MsgSecretRetrievalFailed       = ffm("FF10309", "Failed to retrieve secret '%s'")
+	MsgSecretNotFound              = ffm("FF10310", "Secret '%s' not found")
+	MsgNoSecretsProvider           = ffm("FF10311", "Secret '%s' is referenced, but no secrets provider is configured")
+	MsgUnsupportedBatchSchema      = ffm("FF10312", "Unsupported batch schema version %d (maximum supported %d)")
+	MsgFilterExpressionInvalid     = ffm("FF10313", "Invalid filter expression at position %d: %s", 400)
+	MsgUnknownCryptoPlugin         = ffm("FF10314", "Unknown crypto plugin '%s'")
+	MsgCryptoKeyLoadFailed         = ffm("FF10315", "Failed to load node key from '%s'")
+	MsgCryptoKeyGenerateFailed     = ffm("FF10316", "Failed to generate node key")
+	MsgInvalidNodePublicKey        = ffm("FF10317", "Invalid node public key - must be a PEM encoded X25519 or ECDH P-256 public key", 400)
+	MsgInvalidBase58               = ffm("FF10318", "Invalid base58 supplied", 400)
+	MsgInvalidWrongLenBase58B32    = ffm("FF10319", "Base58 value must decode to 32 bytes, or to a 34 byte sha2-256 multihash (IPFS CIDv0)", 400)
+	MsgForwardedFromRequired       = ffm("FF10320", "A forwarded message must set forwardedFrom, and forwardedFrom can only be set on a forwarded message", 400)
+	MsgForwardedNotFound           = ffm("FF10321", "Message '%s' not found in namespace '%s' to forward", 400)
+	MsgForwardedNotConfirmed       = ffm("FF10322", "Message '%s' cannot be forwarded until it is confirmed", 409)
+	MsgFeatureNotEnabled           = ffm("FF10323", "Feature '%s' is not enabled", 403)
+	MsgOperationNotDXMessageSend   = ffm("FF10324", "Operation '%s' is not a data exchange message send with a tracking ID", 400)
+	MsgInvalidMessageContentType   = ffm("FF10325", "Invalid content type '%s' - must be a MIME type such as 'application/xml'", 400)
+	MsgBatchCompressFailed         = ffm("FF10326", "Failed to compress batch")
+	MsgBatchDecompressFailed       = ffm("FF10327", "Failed to decompress batch")
+	MsgWebhooksOptSecret           = ffm("FF10328", "A secret used to sign the request body with HMAC-SHA256. The hex encoded signature is set in the X-FireFly-Signature header. The secret is masked when the subscription is read back")
+	MsgWebhookFailedStatus         = ffm("FF10329", "Webhook request failed with status %d")
+	MsgInvalidCountryCode          = ffm("FF10330", "Invalid country '%s' - must be an ISO 3166-1 alpha-2 code such as 'GB'", 400)
+	MsgNamespaceReadOnly           = ffm("FF10331", "Namespace '%s' is read-only", 405)
+	MsgGroupACLDenied              = ffm("FF10332", "Identity '%s' is not permitted to update group '%s'", 403)
+	MsgDeleteNotConfirmed          = ffm("FF10333", "Bulk delete must be confirmed with the query parameter confirm=true", 400)
+	MsgInvalidMessageStatus        = ffm("FF10334", "Invalid message status '%s' - must be one of: confirmed, rejected", 400)
+	MsgInvalidMaxDeliveryAttempts  = ffm("FF10335", "Invalid maxDeliveryAttempts %d - must be between 1 and %d", 400)
+	MsgUnsupportedMessageVersion   = ffm("FF10336", "Unsupported message version %d; this node supports up to %d")
+	MsgInvalidJSONFilter           = ffm("FF10337", "Invalid JSON filter '%s' - must be in the form field.path <operator> value, where operator is one of =, !=, >, <, CONTAINS", 400)
+	MsgBatchNamespaceMismatch      = ffm("FF10338", "Batch namespace '%s' does not match configured namespace '%s'")
+	MsgInvalidKeyType              = ffm("FF10339", "Unknown key type '%s' - must be one of: %s", 400)
+	MsgUnsupportedKeyType          = ffm("FF10340", "Key type '%s' is not supported by the %s blockchain plugin", 400)
+	MsgInvalidBigInt               = ffm("FF10344", "Invalid integer '%s' - must be a base 10 integer of any size", 400)
+	MsgBroadcastMustBePinned       = ffm("FF10345", "Broadcast messages must be pinned, as other members discover them through the blockchain", 400)
+	MsgEthInvalidGas               = ffm("FF10348", "Invalid gas value '%s' returned from ethconnect")
+	MsgInvalidReplayRange          = ffm("FF10349", "Invalid replay range - 'to' (%s) is before 'from' (%s)", 400)
+	MsgAdditionalGroupsUnpinned    = ffm("FF10350", "Additional groups can only be used on pinned messages", 400)
+	MsgAdditionalGroupNotFound     = ffm("FF10351", "Additional group '%s' not found in namespace '%s'", 404)
+	MsgPreviousGroupNotFound       = ffm("FF10352", "Previous group '%s' not found", 404)
+	MsgGroupAlreadyReinitialized   = ffm("FF10353", "Previous group '%s' has already been re-initialized by group '%s'", 409)
+	MsgAdditionalGroupNotMember    = ffm("FF10354", "Author '%s' is not a member of additional group '%s'", 403)
+	MsgBigIntOutOfRange            = ffm("FF10355", "Integer '%s' is out of range for storage - must fit in 256 bits", 400)
+	MsgCryptoKeyFileRequired       = ffm("FF10356", "No crypto key file is configured, and the default location in the home directory could not be determined")
+	MsgMTLSRequiresClientAuth      = ffm("FF10357", "The %s server has mtls.enabled set, which requires tls.enabled and tls.clientAuth to also be set")
+	MsgBroadcastTimeoutQueryParam  = ffm("FF10358", "When set the HTTP request blocks until the message is confirmed, and the message is failed if it is not confirmed within this duration")
+	MsgMessageNamespaceMismatch    = ffm("FF10359", "Message '%s' in namespace '%s' cannot be dispatched in a batch for namespace '%s'")
+	MsgDataVersionCycle            = ffm("FF10360", "Data '%s' is a later version of itself", 409)
+	MsgDataVersionHistoryTooLong   = ffm("FF10361", "Data '%s' has more than %d versions", 400)
+	MsgEthRPCNotConfigured         = ffm("FF10362", "No JSON-RPC endpoint is configured for the ethereum node in blockchain.ethereum.rpc.url")
+	MsgEthRPCErr                   = ffm("FF10363", "Error from ethereum JSON-RPC endpoint: %s")
 )
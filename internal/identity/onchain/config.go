@@ -18,7 +18,14 @@ package onchain
 
 import (
 	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+const (
+	// OnChainConfKeyType is the signing algorithm of the on-chain keys, set on every resolved identity
+	OnChainConfKeyType = "keyType"
 )
 
 func (oc *OnChain) InitPrefix(prefix config.Prefix) {
+	prefix.AddKnownKey(OnChainConfKeyType, fftypes.KeyTypeSecp256k1)
 }
@@ -27,6 +27,7 @@ import (
 type OnChain struct {
 	capabilities *identity.Capabilities
 	callbacks    identity.Callbacks
+	keyType      string
 }
 
 func (oc *OnChain) Name() string {
@@ -36,7 +37,8 @@ func (oc *OnChain) Name() string {
 func (oc *OnChain) Init(ctx context.Context, prefix config.Prefix, callbacks identity.Callbacks) (err error) {
 	oc.callbacks = callbacks
 	oc.capabilities = &identity.Capabilities{}
-	return nil
+	oc.keyType = prefix.GetString(OnChainConfKeyType)
+	return fftypes.ValidateKeyType(ctx, oc.keyType)
 }
 
 func (oc *OnChain) Start() error {
@@ -48,8 +50,14 @@ func (oc *OnChain) Capabilities() *identity.Capabilities {
 }
 
 func (oc *OnChain) Resolve(ctx context.Context, identifier string) (*fftypes.Identity, error) {
+	metadata, err := oc.callbacks.GetIdentityMetadata(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
 	return &fftypes.Identity{
 		Identifier: identifier,
 		OnChain:    identifier,
+		KeyType:    oc.keyType,
+		Metadata:   metadata,
 	}, nil
 }
@@ -18,17 +18,21 @@ package onchain
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/mocks/identitymocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/identity"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var utConfPrefix = config.NewPluginConfig("onchain_unit_tests")
 
 func TestInit(t *testing.T) {
+	config.Reset()
 	var oc identity.Plugin = &OnChain{}
 	oc.InitPrefix(utConfPrefix)
 	err := oc.Init(context.Background(), utConfPrefix, &identitymocks.Callbacks{})
@@ -40,13 +44,41 @@ func TestInit(t *testing.T) {
 	assert.NotNil(t, capabilities)
 }
 
-func TestResolve(t *testing.T) {
+func TestInitBadKeyType(t *testing.T) {
+	config.Reset()
 	var oc identity.Plugin = &OnChain{}
+	oc.InitPrefix(utConfPrefix)
+	utConfPrefix.Set(OnChainConfKeyType, "rsa")
 	err := oc.Init(context.Background(), utConfPrefix, &identitymocks.Callbacks{})
+	assert.Regexp(t, "FF10339.*rsa", err)
+}
+
+func TestResolve(t *testing.T) {
+	config.Reset()
+	var oc identity.Plugin = &OnChain{}
+	oc.InitPrefix(utConfPrefix)
+	mcb := &identitymocks.Callbacks{}
+	mcb.On("GetIdentityMetadata", mock.Anything, "0x12345").Return(fftypes.JSONObject{"role": "auditor"}, nil)
+	err := oc.Init(context.Background(), utConfPrefix, mcb)
 	assert.NoError(t, err)
 
 	id, err := oc.Resolve(context.Background(), "0x12345")
 	assert.NoError(t, err)
 	assert.Equal(t, "0x12345", id.Identifier)
 	assert.Equal(t, "0x12345", id.OnChain)
+	assert.Equal(t, fftypes.KeyTypeSecp256k1, id.KeyType)
+	assert.Equal(t, "auditor", id.Metadata.GetString("role"))
+}
+
+func TestResolveMetadataFail(t *testing.T) {
+	config.Reset()
+	var oc identity.Plugin = &OnChain{}
+	oc.InitPrefix(utConfPrefix)
+	mcb := &identitymocks.Callbacks{}
+	mcb.On("GetIdentityMetadata", mock.Anything, "0x12345").Return(nil, fmt.Errorf("pop"))
+	err := oc.Init(context.Background(), utConfPrefix, mcb)
+	assert.NoError(t, err)
+
+	_, err = oc.Resolve(context.Background(), "0x12345")
+	assert.EqualError(t, err, "pop")
 }
@@ -0,0 +1,48 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// ValidateNetworkConsistency cross-checks the nodes, organizations and group members we have
+// stored locally, to find references that could not be resolved (such as after a network partition)
+func (nm *networkMap) ValidateNetworkConsistency(ctx context.Context) (*fftypes.ConsistencyReport, error) {
+	ghostNodes, err := nm.database.GetGhostNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	missingOrgs, err := nm.database.GetMissingOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicateIdentities, err := nm.database.GetDuplicateIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fftypes.ConsistencyReport{
+		GhostNodes:          ghostNodes,
+		MissingOrgs:         missingOrgs,
+		DuplicateIdentities: duplicateIdentities,
+	}, nil
+}
@@ -0,0 +1,71 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNetworkConsistencyOk(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	ghostNode := fftypes.NewUUID()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetGhostNodes", nm.ctx).Return([]*fftypes.UUID{ghostNode}, nil)
+	mdi.On("GetMissingOrganizations", nm.ctx).Return([]string{"0x12345"}, nil)
+	mdi.On("GetDuplicateIdentities", nm.ctx).Return([]string{"0x23456"}, nil)
+	report, err := nm.ValidateNetworkConsistency(nm.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*fftypes.UUID{ghostNode}, report.GhostNodes)
+	assert.Equal(t, []string{"0x12345"}, report.MissingOrgs)
+	assert.Equal(t, []string{"0x23456"}, report.DuplicateIdentities)
+}
+
+func TestValidateNetworkConsistencyGhostNodesFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetGhostNodes", nm.ctx).Return(nil, fmt.Errorf("pop"))
+	_, err := nm.ValidateNetworkConsistency(nm.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestValidateNetworkConsistencyMissingOrgsFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetGhostNodes", nm.ctx).Return([]*fftypes.UUID{}, nil)
+	mdi.On("GetMissingOrganizations", nm.ctx).Return(nil, fmt.Errorf("pop"))
+	_, err := nm.ValidateNetworkConsistency(nm.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestValidateNetworkConsistencyDuplicatesFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetGhostNodes", nm.ctx).Return([]*fftypes.UUID{}, nil)
+	mdi.On("GetMissingOrganizations", nm.ctx).Return([]string{}, nil)
+	mdi.On("GetDuplicateIdentities", nm.ctx).Return(nil, fmt.Errorf("pop"))
+	_, err := nm.ValidateNetworkConsistency(nm.ctx)
+	assert.Regexp(t, "pop", err)
+}
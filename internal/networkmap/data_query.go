@@ -31,6 +31,14 @@ func (nm *networkMap) GetOrganizationByID(ctx context.Context, id string) (*ffty
 	return nm.database.GetOrganizationByID(ctx, u)
 }
 
+func (nm *networkMap) GetOrganizationByIdentity(ctx context.Context, identity string) (*fftypes.Organization, error) {
+	return nm.database.GetOrganizationByIdentity(ctx, identity)
+}
+
+func (nm *networkMap) GetOrganizationByName(ctx context.Context, name string) (*fftypes.Organization, error) {
+	return nm.database.GetOrganizationByName(ctx, name)
+}
+
 func (nm *networkMap) GetOrganizations(ctx context.Context, filter database.AndFilter) ([]*fftypes.Organization, *database.FilterResult, error) {
 	return nm.database.GetOrganizations(ctx, filter)
 }
@@ -36,6 +36,26 @@ func TestGetOrganizationByIDOk(t *testing.T) {
 	assert.Equal(t, *id, *res.ID)
 }
 
+func TestGetOrganizationByIdentityOk(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	id := fftypes.NewUUID()
+	nm.database.(*databasemocks.Plugin).On("GetOrganizationByIdentity", nm.ctx, "0x12345").Return(&fftypes.Organization{ID: id}, nil)
+	res, err := nm.GetOrganizationByIdentity(nm.ctx, "0x12345")
+	assert.NoError(t, err)
+	assert.Equal(t, *id, *res.ID)
+}
+
+func TestGetOrganizationByNameOk(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	id := fftypes.NewUUID()
+	nm.database.(*databasemocks.Plugin).On("GetOrganizationByName", nm.ctx, "org1").Return(&fftypes.Organization{ID: id}, nil)
+	res, err := nm.GetOrganizationByName(nm.ctx, "org1")
+	assert.NoError(t, err)
+	assert.Equal(t, *id, *res.ID)
+}
+
 func TestGetOrganizationByIDBadUUID(t *testing.T) {
 	nm, cancel := newTestNetworkmap(t)
 	defer cancel()
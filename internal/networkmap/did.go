@@ -0,0 +1,79 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// GetOrganizationDID returns a W3C DID document for an organization, looked up by its ID or by its identity.
+// The on-chain identity of the organization is the verification method, and the data exchange endpoints
+// of the nodes owned by the organization are listed as services.
+func (nm *networkMap) GetOrganizationDID(ctx context.Context, identity string) (*fftypes.DIDDocument, error) {
+	var org *fftypes.Organization
+	var err error
+	if id, parseErr := fftypes.ParseUUID(ctx, identity); parseErr == nil {
+		org, err = nm.database.GetOrganizationByID(ctx, id)
+	} else {
+		org, err = nm.database.GetOrganizationByIdentity(ctx, identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+
+	fb := database.NodeQueryFactory.NewFilter(ctx)
+	nodes, _, err := nm.database.GetNodes(ctx, fb.Eq("owner", org.Identity))
+	if err != nil {
+		return nil, err
+	}
+
+	did := fftypes.OrgDID(org.Identity)
+	keyID := did + "#key-1"
+	doc := &fftypes.DIDDocument{
+		Context: []string{fftypes.DIDContextV1},
+		ID:      did,
+		VerificationMethod: []*fftypes.DIDVerificationMethod{
+			{
+				ID:                  keyID,
+				Type:                fftypes.DIDVerificationMethodBlockchainAccount,
+				Controller:          did,
+				BlockchainAccountID: org.Identity,
+			},
+		},
+		Authentication: []string{keyID},
+		Service:        []*fftypes.DIDService{},
+	}
+	if org.Parent != "" {
+		doc.Controller = fftypes.OrgDID(org.Parent)
+	}
+	for _, node := range nodes {
+		doc.Service = append(doc.Service, &fftypes.DIDService{
+			ID:              fmt.Sprintf("%s#dx-%s", did, node.Name),
+			Type:            fftypes.DIDServiceDataExchange,
+			ServiceEndpoint: node.DX.Endpoint,
+		})
+	}
+	return doc, nil
+}
@@ -0,0 +1,115 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetOrganizationDIDByIdentity(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByIdentity", nm.ctx, "0x12345").Return(&fftypes.Organization{
+		ID:       fftypes.NewUUID(),
+		Identity: "0x12345",
+		Parent:   "0x23456",
+	}, nil)
+	mdi.On("GetNodes", nm.ctx, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == "owner == '0x12345'"
+	})).Return([]*fftypes.Node{
+		{Name: "node1", DX: fftypes.DXInfo{Peer: "peer1", Endpoint: fftypes.JSONObject{"url": "https://dx1"}}},
+	}, nil, nil)
+
+	doc, err := nm.GetOrganizationDID(nm.ctx, "0x12345")
+	assert.NoError(t, err)
+
+	b, _ := json.Marshal(doc)
+	assert.JSONEq(t, `{
+		"@context": ["https://www.w3.org/ns/did/v1"],
+		"id": "did:firefly:0x12345",
+		"controller": "did:firefly:0x23456",
+		"verificationMethod": [{
+			"id": "did:firefly:0x12345#key-1",
+			"type": "BlockchainVerificationMethod2021",
+			"controller": "did:firefly:0x12345",
+			"blockchainAccountId": "0x12345"
+		}],
+		"authentication": ["did:firefly:0x12345#key-1"],
+		"service": [{
+			"id": "did:firefly:0x12345#dx-node1",
+			"type": "FireFlyDataExchange",
+			"serviceEndpoint": {"url": "https://dx1"}
+		}]
+	}`, string(b))
+}
+
+func TestGetOrganizationDIDByID(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	mdi := nm.database.(*databasemocks.Plugin)
+	id := fftypes.NewUUID()
+	mdi.On("GetOrganizationByID", nm.ctx, id).Return(&fftypes.Organization{ID: id, Identity: "0x12345"}, nil)
+	mdi.On("GetNodes", nm.ctx, mock.Anything).Return([]*fftypes.Node{}, nil, nil)
+
+	doc, err := nm.GetOrganizationDID(nm.ctx, id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "did:firefly:0x12345", doc.ID)
+	assert.Empty(t, doc.Controller)
+	assert.Empty(t, doc.Service)
+	assert.Len(t, doc.VerificationMethod, 1)
+}
+
+func TestGetOrganizationDIDNotFound(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByIdentity", nm.ctx, "0x12345").Return(nil, nil)
+
+	_, err := nm.GetOrganizationDID(nm.ctx, "0x12345")
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetOrganizationDIDLookupFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByIdentity", nm.ctx, "0x12345").Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.GetOrganizationDID(nm.ctx, "0x12345")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetOrganizationDIDGetNodesFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByIdentity", nm.ctx, "0x12345").Return(&fftypes.Organization{Identity: "0x12345"}, nil)
+	mdi.On("GetNodes", nm.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := nm.GetOrganizationDID(nm.ctx, "0x12345")
+	assert.EqualError(t, err, "pop")
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/firefly/internal/broadcast"
 	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/pkg/crypto"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
 	"github.com/hyperledger/firefly/pkg/fftypes"
@@ -29,13 +30,20 @@ import (
 
 type Manager interface {
 	RegisterOrganization(ctx context.Context, org *fftypes.Organization, waitConfirm bool) (msg *fftypes.Message, err error)
+	RegisterOrganizationWithKey(ctx context.Context, org *fftypes.Organization, keyMaterial []byte, waitConfirm bool) (msg *fftypes.Message, err error)
 	RegisterNode(ctx context.Context, waitConfirm bool) (node *fftypes.Node, msg *fftypes.Message, err error)
 	RegisterNodeOrganization(ctx context.Context, waitConfirm bool) (org *fftypes.Organization, msg *fftypes.Message, err error)
+	RotateNodeKey(ctx context.Context, waitConfirm bool) (node *fftypes.Node, msg *fftypes.Message, err error)
 
 	GetOrganizationByID(ctx context.Context, id string) (*fftypes.Organization, error)
+	GetOrganizationByIdentity(ctx context.Context, identity string) (*fftypes.Organization, error)
+	GetOrganizationByName(ctx context.Context, name string) (*fftypes.Organization, error)
 	GetOrganizations(ctx context.Context, filter database.AndFilter) ([]*fftypes.Organization, *database.FilterResult, error)
+	GetOrganizationDID(ctx context.Context, identity string) (*fftypes.DIDDocument, error)
 	GetNodeByID(ctx context.Context, id string) (*fftypes.Node, error)
 	GetNodes(ctx context.Context, filter database.AndFilter) ([]*fftypes.Node, *database.FilterResult, error)
+
+	ValidateNetworkConsistency(ctx context.Context) (*fftypes.ConsistencyReport, error)
 }
 
 type networkMap struct {
@@ -44,10 +52,11 @@ type networkMap struct {
 	broadcast broadcast.Manager
 	exchange  dataexchange.Plugin
 	identity  identity.Plugin
+	crypto    crypto.Plugin
 }
 
-func NewNetworkMap(ctx context.Context, di database.Plugin, bm broadcast.Manager, dx dataexchange.Plugin, ii identity.Plugin) (Manager, error) {
-	if di == nil || bm == nil || dx == nil || ii == nil {
+func NewNetworkMap(ctx context.Context, di database.Plugin, bm broadcast.Manager, dx dataexchange.Plugin, ii identity.Plugin, cp crypto.Plugin) (Manager, error) {
+	if di == nil || bm == nil || dx == nil || ii == nil || cp == nil {
 		return nil, i18n.NewError(ctx, i18n.MsgInitializationNilDepError)
 	}
 
@@ -57,6 +66,7 @@ func NewNetworkMap(ctx context.Context, di database.Plugin, bm broadcast.Manager
 		broadcast: bm,
 		exchange:  dx,
 		identity:  ii,
+		crypto:    cp,
 	}
 	return nm, nil
 }
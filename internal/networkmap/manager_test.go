@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/cryptomocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/identitymocks"
@@ -35,13 +36,14 @@ func newTestNetworkmap(t *testing.T) (*networkMap, func()) {
 	mbm := &broadcastmocks.Manager{}
 	mdx := &dataexchangemocks.Plugin{}
 	mii := &identitymocks.Plugin{}
-	nm, err := NewNetworkMap(ctx, mdi, mbm, mdx, mii)
+	mcp := &cryptomocks.Plugin{}
+	nm, err := NewNetworkMap(ctx, mdi, mbm, mdx, mii, mcp)
 	assert.NoError(t, err)
 	return nm.(*networkMap), cancel
 
 }
 
 func TestNewNetworkMapMissingDep(t *testing.T) {
-	_, err := NewNetworkMap(context.Background(), nil, nil, nil, nil)
+	_, err := NewNetworkMap(context.Background(), nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
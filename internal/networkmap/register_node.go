@@ -36,6 +36,9 @@ func (nm *networkMap) RegisterNode(ctx context.Context, waitConfirm bool) (node
 	if node.Name == "" {
 		node.Name = config.GetString(config.OrgIdentity)
 	}
+	if heartbeatInterval := fftypes.FFDuration(config.GetDuration(config.NodeHeartbeatInterval)); heartbeatInterval > 0 {
+		node.HeartbeatInterval = &heartbeatInterval
+	}
 	if node.Owner == "" || node.Name == "" {
 		return nil, nil, i18n.NewError(ctx, i18n.MsgNodeAndOrgIDMustBeSet)
 	}
@@ -45,6 +48,11 @@ func (nm *networkMap) RegisterNode(ctx context.Context, waitConfirm bool) (node
 		return nil, nil, err
 	}
 
+	node.PublicKey, _, err = nm.crypto.GenerateOrLoadKey(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	err = node.Validate(ctx, false)
 	if err != nil {
 		return nil, nil, err
@@ -59,7 +67,19 @@ func (nm *networkMap) RegisterNode(ctx context.Context, waitConfirm bool) (node
 		return nil, nil, i18n.WrapError(ctx, err, i18n.MsgInvalidSigningIdentity)
 	}
 
-	msg, err = nm.broadcast.BroadcastDefinition(ctx, node, signingIdentity, fftypes.SystemTagDefineNode, waitConfirm)
+	// Registering again with unchanged details, such as on every restart, does not broadcast a new definition
+	var created bool
+	msg, created, err = nm.broadcast.BroadcastDefinitionIfNotExists(ctx, node, signingIdentity, fftypes.SystemTagDefineNode, waitConfirm,
+		config.GetInt(config.BroadcastDefinitionRetryCount), config.GetDuration(config.BroadcastDefinitionRetryInitDelay))
+	if err == nil && !created {
+		existing, err := nm.database.GetNode(ctx, node.Owner, node.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if existing != nil {
+			return existing, msg, nil
+		}
+	}
 	if msg != nil {
 		node.Message = msg.Header.ID
 	}
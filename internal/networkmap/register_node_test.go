@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/cryptomocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/identitymocks"
@@ -36,6 +37,7 @@ func TestRegisterNodeOk(t *testing.T) {
 	defer cancel()
 
 	config.Set(config.NodeDescription, "Node 1")
+	config.Set(config.NodeHeartbeatInterval, "30s")
 	config.Set(config.OrgIdentity, "0x23456")
 
 	mdi := nm.database.(*databasemocks.Plugin)
@@ -53,14 +55,96 @@ func TestRegisterNodeOk(t *testing.T) {
 	mdx := nm.exchange.(*dataexchangemocks.Plugin)
 	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"endpoint": "details"}, nil)
 
+	publicKey := "-----BEGIN PUBLIC KEY-----\nMCowBQYDK2VuAyEAVko0QhJfAkAWmy3gqtHfWFOidyb2ZNU/gDTpt56b3wE=\n-----END PUBLIC KEY-----\n"
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return(publicKey, "private", nil)
+
 	mockMsg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}
 	mbm := nm.broadcast.(*broadcastmocks.Manager)
-	mbm.On("BroadcastDefinition", nm.ctx, mock.Anything, parentID, fftypes.SystemTagDefineNode, true).Return(mockMsg, nil)
+	mbm.On("BroadcastDefinitionIfNotExists", nm.ctx, mock.MatchedBy(func(node *fftypes.Node) bool {
+		return node.PublicKey == publicKey
+	}), parentID, fftypes.SystemTagDefineNode, true, mock.Anything, mock.Anything).Return(mockMsg, true, nil)
 
 	node, msg, err := nm.RegisterNode(nm.ctx, true)
 	assert.NoError(t, err)
 	assert.Equal(t, mockMsg, msg)
 	assert.Equal(t, *mockMsg.Header.ID, *node.Message)
+	assert.Equal(t, publicKey, node.PublicKey)
+	assert.Equal(t, "30s", node.HeartbeatInterval.String())
+
+}
+
+func setupRegisterNodeUnchanged(t *testing.T) (*networkMap, func(), *fftypes.Message) {
+
+	nm, cancel := newTestNetworkmap(t)
+
+	config.Set(config.NodeDescription, "Node 1")
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByIdentity", nm.ctx, "0x23456").Return(&fftypes.Organization{
+		Identity:    "0x23456",
+		Description: "owning organization",
+	}, nil)
+
+	mii := nm.identity.(*identitymocks.Plugin)
+	mii.On("Resolve", nm.ctx, "0x23456").Return(&fftypes.Identity{OnChain: "0x23456"}, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"endpoint": "details"}, nil)
+
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", nil)
+
+	existingMsg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}
+	mbm := nm.broadcast.(*broadcastmocks.Manager)
+	mbm.On("BroadcastDefinitionIfNotExists", nm.ctx, mock.Anything, mock.Anything, fftypes.SystemTagDefineNode, false, mock.Anything, mock.Anything).Return(existingMsg, false, nil)
+
+	return nm, cancel, existingMsg
+}
+
+func TestRegisterNodeUnchanged(t *testing.T) {
+
+	nm, cancel, existingMsg := setupRegisterNodeUnchanged(t)
+	defer cancel()
+
+	existing := &fftypes.Node{ID: fftypes.NewUUID(), Owner: "0x23456", Name: "node1", Message: existingMsg.Header.ID}
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(existing, nil)
+
+	node, msg, err := nm.RegisterNode(nm.ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, existingMsg, msg)
+	assert.Equal(t, existing, node)
+
+}
+
+func TestRegisterNodeUnchangedNotYetStored(t *testing.T) {
+
+	nm, cancel, existingMsg := setupRegisterNodeUnchanged(t)
+	defer cancel()
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(nil, nil)
+
+	node, msg, err := nm.RegisterNode(nm.ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, existingMsg, msg)
+	assert.Equal(t, *existingMsg.Header.ID, *node.Message)
+
+}
+
+func TestRegisterNodeUnchangedGetNodeFail(t *testing.T) {
+
+	nm, cancel, _ := setupRegisterNodeUnchanged(t)
+	defer cancel()
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := nm.RegisterNode(nm.ctx, false)
+	assert.EqualError(t, err, "pop")
 
 }
 
@@ -99,6 +183,9 @@ func TestRegisterNodeBadParentID(t *testing.T) {
 	mdx := nm.exchange.(*dataexchangemocks.Plugin)
 	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"endpoint": "details"}, nil)
 
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", nil)
+
 	_, _, err := nm.RegisterNode(nm.ctx, false)
 	assert.Regexp(t, "FF10215", err)
 
@@ -125,6 +212,9 @@ func TestRegisterNodeBadNodeID(t *testing.T) {
 	mdx := nm.exchange.(*dataexchangemocks.Plugin)
 	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"endpoint": "details"}, nil)
 
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", nil)
+
 	_, _, err := nm.RegisterNode(nm.ctx, false)
 	assert.Regexp(t, "pop", err)
 
@@ -149,6 +239,9 @@ func TestRegisterNodeParentNotFound(t *testing.T) {
 	mdx := nm.exchange.(*dataexchangemocks.Plugin)
 	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"endpoint": "details"}, nil)
 
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", nil)
+
 	_, _, err := nm.RegisterNode(nm.ctx, false)
 	assert.Regexp(t, "FF10214", err)
 
@@ -166,6 +259,9 @@ func TestRegisterNodeParentBadNode(t *testing.T) {
 	mdx := nm.exchange.(*dataexchangemocks.Plugin)
 	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"endpoint": "details"}, nil)
 
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", nil)
+
 	_, _, err := nm.RegisterNode(nm.ctx, false)
 	assert.Regexp(t, "FF10188", err)
 
@@ -187,3 +283,22 @@ func TestRegisterNodeParentDXEndpointFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 
 }
+
+func TestRegisterNodeGenerateKeyFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"endpoint": "details"}, nil)
+
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", fmt.Errorf("pop"))
+
+	_, _, err := nm.RegisterNode(nm.ctx, false)
+	assert.Regexp(t, "pop", err)
+
+}
@@ -46,6 +46,7 @@ func (nm *networkMap) RegisterNodeOrganization(ctx context.Context, waitConfirm
 		Name:        config.GetString(config.OrgName),
 		Identity:    config.GetString(config.OrgIdentity),
 		Description: config.GetString(config.OrgDescription),
+		Country:     config.GetString(config.OrgCountry),
 	}
 	if org.Identity == "" || org.Name == "" {
 		return nil, nil, i18n.NewError(ctx, i18n.MsgNodeAndOrgIDMustBeSet)
@@ -59,6 +60,29 @@ func (nm *networkMap) RegisterNodeOrganization(ctx context.Context, waitConfirm
 
 func (nm *networkMap) RegisterOrganization(ctx context.Context, org *fftypes.Organization, waitConfirm bool) (*fftypes.Message, error) {
 
+	signingIdentity, err := nm.resolveOrgSigningIdentity(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return nm.broadcast.BroadcastDefinitionWithRetry(ctx, org, signingIdentity, fftypes.SystemTagDefineOrganization, waitConfirm,
+		config.GetInt(config.BroadcastDefinitionRetryCount), config.GetDuration(config.BroadcastDefinitionRetryInitDelay))
+}
+
+// RegisterOrganizationWithKey registers an organization, where the signing key is managed outside of the
+// node (such as in an HSM or KMS). The PEM encoded private key signs the definition broadcast, and is not persisted.
+func (nm *networkMap) RegisterOrganizationWithKey(ctx context.Context, org *fftypes.Organization, keyMaterial []byte, waitConfirm bool) (*fftypes.Message, error) {
+
+	signingIdentity, err := nm.resolveOrgSigningIdentity(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return nm.broadcast.BroadcastSignedDefinition(ctx, org, signingIdentity, keyMaterial, fftypes.SystemTagDefineOrganization, waitConfirm)
+}
+
+func (nm *networkMap) resolveOrgSigningIdentity(ctx context.Context, org *fftypes.Organization) (*fftypes.Identity, error) {
+
 	err := org.Validate(ctx, false)
 	if err != nil {
 		return nil, err
@@ -86,6 +110,5 @@ func (nm *networkMap) RegisterOrganization(ctx context.Context, org *fftypes.Org
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, i18n.MsgInvalidSigningIdentity)
 	}
-
-	return nm.broadcast.BroadcastDefinition(ctx, org, signingIdentity, fftypes.SystemTagDefineOrganization, waitConfirm)
+	return signingIdentity, nil
 }
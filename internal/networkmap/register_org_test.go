@@ -48,7 +48,7 @@ func TestRegisterOrganizationChildOk(t *testing.T) {
 
 	mockMsg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}
 	mbm := nm.broadcast.(*broadcastmocks.Manager)
-	mbm.On("BroadcastDefinition", nm.ctx, mock.Anything, parentID, fftypes.SystemTagDefineOrganization, false).Return(mockMsg, nil)
+	mbm.On("BroadcastDefinitionWithRetry", nm.ctx, mock.Anything, parentID, fftypes.SystemTagDefineOrganization, false, mock.Anything, mock.Anything).Return(mockMsg, nil)
 
 	msg, err := nm.RegisterOrganization(nm.ctx, &fftypes.Organization{
 		Name:        "org1",
@@ -69,6 +69,7 @@ func TestRegisterNodeOrganizationRootOk(t *testing.T) {
 	config.Set(config.OrgName, "org1")
 	config.Set(config.OrgIdentity, "0x12345")
 	config.Set(config.OrgDescription, "my organization")
+	config.Set(config.OrgCountry, "GB")
 
 	mii := nm.identity.(*identitymocks.Plugin)
 	rootID := &fftypes.Identity{OnChain: "0x12345"}
@@ -76,7 +77,9 @@ func TestRegisterNodeOrganizationRootOk(t *testing.T) {
 
 	mockMsg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}
 	mbm := nm.broadcast.(*broadcastmocks.Manager)
-	mbm.On("BroadcastDefinition", nm.ctx, mock.Anything, rootID, fftypes.SystemTagDefineOrganization, true).Return(mockMsg, nil)
+	mbm.On("BroadcastDefinitionWithRetry", nm.ctx, mock.MatchedBy(func(org *fftypes.Organization) bool {
+		return org.Country == "GB"
+	}), rootID, fftypes.SystemTagDefineOrganization, true, mock.Anything, mock.Anything).Return(mockMsg, nil)
 
 	org, msg, err := nm.RegisterNodeOrganization(nm.ctx, true)
 	assert.NoError(t, err)
@@ -182,3 +185,37 @@ func TestRegisterOrganizationParentLookupFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 
 }
+
+func TestRegisterOrganizationWithKeyOk(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	mii := nm.identity.(*identitymocks.Plugin)
+	rootID := &fftypes.Identity{OnChain: "0x12345"}
+	mii.On("Resolve", nm.ctx, "0x12345").Return(rootID, nil)
+
+	mockMsg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}
+	mbm := nm.broadcast.(*broadcastmocks.Manager)
+	mbm.On("BroadcastSignedDefinition", nm.ctx, mock.Anything, rootID, []byte("key"), fftypes.SystemTagDefineOrganization, false).Return(mockMsg, nil)
+
+	msg, err := nm.RegisterOrganizationWithKey(nm.ctx, &fftypes.Organization{
+		Name:     "org1",
+		Identity: "0x12345",
+	}, []byte("key"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, mockMsg, msg)
+
+	mbm.AssertExpectations(t)
+}
+
+func TestRegisterOrganizationWithKeyBadObject(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	_, err := nm.RegisterOrganizationWithKey(nm.ctx, &fftypes.Organization{
+		Name: "!name",
+	}, []byte("key"), false)
+	assert.Regexp(t, "FF10131", err)
+}
@@ -0,0 +1,79 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// RotateNodeKey re-broadcasts the definition of the local node, with the latest endpoint
+// information from the data exchange. The existing node ID is retained, so any group
+// memberships that reference this node are unaffected.
+func (nm *networkMap) RotateNodeKey(ctx context.Context, waitConfirm bool) (node *fftypes.Node, msg *fftypes.Message, err error) {
+
+	owner := config.GetString(config.OrgIdentity)
+	name := config.GetString(config.NodeName)
+	if name == "" {
+		name = owner
+	}
+	if owner == "" || name == "" {
+		return nil, nil, i18n.NewError(ctx, i18n.MsgNodeAndOrgIDMustBeSet)
+	}
+
+	node, err = nm.database.GetNode(ctx, owner, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if node == nil {
+		return nil, nil, i18n.NewError(ctx, i18n.MsgNodeNotFound, name)
+	}
+
+	node.DX.Peer, node.DX.Endpoint, err = nm.exchange.GetEndpointInfo(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	node.PublicKey, _, err = nm.crypto.GenerateOrLoadKey(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = node.Validate(ctx, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signingIdentity, err := nm.identity.Resolve(ctx, node.Owner)
+	if err != nil {
+		return nil, nil, i18n.WrapError(ctx, err, i18n.MsgInvalidSigningIdentity)
+	}
+
+	msg, err = nm.broadcast.BroadcastDefinition(ctx, node, signingIdentity, fftypes.SystemTagDefineNode, waitConfirm)
+	if err != nil {
+		return nil, nil, err
+	}
+	node.Message = msg.Header.ID
+
+	if err = nm.database.UpsertNode(ctx, node, true); err != nil {
+		return nil, nil, err
+	}
+	return node, msg, nil
+}
@@ -0,0 +1,301 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/cryptomocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/identitymocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRotateNodeKeyOk(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	nodeID := fftypes.NewUUID()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(&fftypes.Node{
+		ID:    nodeID,
+		Owner: "0x23456",
+		Name:  "node1",
+		DX: fftypes.DXInfo{
+			Peer:     "peer1",
+			Endpoint: fftypes.JSONObject{"cert": "old"},
+		},
+	}, nil)
+	mdi.On("UpsertNode", nm.ctx, mock.MatchedBy(func(node *fftypes.Node) bool {
+		return node.ID.Equals(nodeID) && node.DX.Endpoint.GetString("cert") == "new"
+	}), true).Return(nil)
+
+	parentID := &fftypes.Identity{OnChain: "0x23456"}
+	mii := nm.identity.(*identitymocks.Plugin)
+	mii.On("Resolve", nm.ctx, "0x23456").Return(parentID, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"cert": "new"}, nil)
+
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", nil)
+
+	mockMsg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}
+	mbm := nm.broadcast.(*broadcastmocks.Manager)
+	mbm.On("BroadcastDefinition", nm.ctx, mock.Anything, parentID, fftypes.SystemTagDefineNode, true).Return(mockMsg, nil)
+
+	node, msg, err := nm.RotateNodeKey(nm.ctx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, mockMsg, msg)
+	assert.Equal(t, *nodeID, *node.ID)
+	assert.Equal(t, *mockMsg.Header.ID, *node.Message)
+	assert.Equal(t, "new", node.DX.Endpoint.GetString("cert"))
+
+	mdi.AssertExpectations(t)
+	mbm.AssertExpectations(t)
+
+}
+
+func TestRotateNodeKeyMissingConfig(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, nil)
+	config.Set(config.OrgIdentity, nil)
+
+	_, _, err := nm.RotateNodeKey(nm.ctx, false)
+	assert.Regexp(t, "FF10216", err)
+
+}
+
+func TestRotateNodeKeyGetNodeFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := nm.RotateNodeKey(nm.ctx, false)
+	assert.Regexp(t, "pop", err)
+
+}
+
+func TestRotateNodeKeyNotRegistered(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(nil, nil)
+
+	_, _, err := nm.RotateNodeKey(nm.ctx, false)
+	assert.Regexp(t, "FF10224", err)
+
+}
+
+func TestRotateNodeKeyDXEndpointFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(&fftypes.Node{
+		ID:    fftypes.NewUUID(),
+		Owner: "0x23456",
+		Name:  "node1",
+	}, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx).Return("", nil, fmt.Errorf("pop"))
+
+	_, _, err := nm.RotateNodeKey(nm.ctx, false)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertNotCalled(t, "UpsertNode", mock.Anything, mock.Anything, mock.Anything)
+
+}
+
+func TestRotateNodeKeyGenerateKeyFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(&fftypes.Node{
+		ID:    fftypes.NewUUID(),
+		Owner: "0x23456",
+		Name:  "node1",
+	}, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"cert": "new"}, nil)
+
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", fmt.Errorf("pop"))
+
+	_, _, err := nm.RotateNodeKey(nm.ctx, false)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertNotCalled(t, "UpsertNode", mock.Anything, mock.Anything, mock.Anything)
+
+}
+
+func TestRotateNodeKeyBadNode(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(&fftypes.Node{
+		Owner: "0x23456",
+		Name:  "node1",
+	}, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"cert": "new"}, nil)
+
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", nil)
+
+	_, _, err := nm.RotateNodeKey(nm.ctx, false)
+	assert.Regexp(t, "FF10203", err)
+
+}
+
+func TestRotateNodeKeyBadSigningIdentity(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(&fftypes.Node{
+		ID:    fftypes.NewUUID(),
+		Owner: "0x23456",
+		Name:  "node1",
+	}, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"cert": "new"}, nil)
+
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", nil)
+
+	mii := nm.identity.(*identitymocks.Plugin)
+	mii.On("Resolve", nm.ctx, "0x23456").Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := nm.RotateNodeKey(nm.ctx, false)
+	assert.Regexp(t, "FF10215", err)
+
+}
+
+func TestRotateNodeKeyBroadcastFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(&fftypes.Node{
+		ID:    fftypes.NewUUID(),
+		Owner: "0x23456",
+		Name:  "node1",
+	}, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"cert": "new"}, nil)
+
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", nil)
+
+	parentID := &fftypes.Identity{OnChain: "0x23456"}
+	mii := nm.identity.(*identitymocks.Plugin)
+	mii.On("Resolve", nm.ctx, "0x23456").Return(parentID, nil)
+
+	mbm := nm.broadcast.(*broadcastmocks.Manager)
+	mbm.On("BroadcastDefinition", nm.ctx, mock.Anything, parentID, fftypes.SystemTagDefineNode, false).Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := nm.RotateNodeKey(nm.ctx, false)
+	assert.Regexp(t, "pop", err)
+
+}
+
+func TestRotateNodeKeyUpsertFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	config.Set(config.NodeName, "node1")
+	config.Set(config.OrgIdentity, "0x23456")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNode", nm.ctx, "0x23456", "node1").Return(&fftypes.Node{
+		ID:    fftypes.NewUUID(),
+		Owner: "0x23456",
+		Name:  "node1",
+	}, nil)
+	mdi.On("UpsertNode", nm.ctx, mock.Anything, true).Return(fmt.Errorf("pop"))
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx).Return("peer1", fftypes.JSONObject{"cert": "new"}, nil)
+
+	mcp := nm.crypto.(*cryptomocks.Plugin)
+	mcp.On("GenerateOrLoadKey", nm.ctx).Return("", "", nil)
+
+	parentID := &fftypes.Identity{OnChain: "0x23456"}
+	mii := nm.identity.(*identitymocks.Plugin)
+	mii.On("Resolve", nm.ctx, "0x23456").Return(parentID, nil)
+
+	mockMsg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}
+	mbm := nm.broadcast.(*broadcastmocks.Manager)
+	mbm.On("BroadcastDefinition", nm.ctx, mock.Anything, parentID, fftypes.SystemTagDefineNode, false).Return(mockMsg, nil)
+
+	_, _, err := nm.RotateNodeKey(nm.ctx, false)
+	assert.Regexp(t, "pop", err)
+
+}
@@ -0,0 +1,59 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsplugins
+
+import (
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/dataexchange"
+	"github.com/hyperledger/firefly/pkg/publicstorage"
+)
+
+// Plugins are the plugin overrides configured for an individual namespace, via the "plugins" section
+// of its entry in namespaces.predefined. Any plugin that is not overridden is nil.
+type Plugins struct {
+	Blockchain    blockchain.Plugin
+	DataExchange  dataexchange.Plugin
+	PublicStorage publicstorage.Plugin
+}
+
+// Overrides are the plugin overrides of each namespace, keyed by namespace name.
+// A nil Overrides is valid, and resolves every namespace to the global plugins.
+type Overrides map[string]*Plugins
+
+// Blockchain returns the blockchain plugin for a namespace, falling back to the global plugin
+func (o Overrides) Blockchain(ns string, global blockchain.Plugin) blockchain.Plugin {
+	if np, ok := o[ns]; ok && np.Blockchain != nil {
+		return np.Blockchain
+	}
+	return global
+}
+
+// DataExchange returns the data exchange plugin for a namespace, falling back to the global plugin
+func (o Overrides) DataExchange(ns string, global dataexchange.Plugin) dataexchange.Plugin {
+	if np, ok := o[ns]; ok && np.DataExchange != nil {
+		return np.DataExchange
+	}
+	return global
+}
+
+// PublicStorage returns the public storage plugin for a namespace, falling back to the global plugin
+func (o Overrides) PublicStorage(ns string, global publicstorage.Plugin) publicstorage.Plugin {
+	if np, ok := o[ns]; ok && np.PublicStorage != nil {
+		return np.PublicStorage
+	}
+	return global
+}
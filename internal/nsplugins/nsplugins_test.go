@@ -0,0 +1,49 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsplugins
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/publicstoragemocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverrides(t *testing.T) {
+	mbi, mbi2 := &blockchainmocks.Plugin{}, &blockchainmocks.Plugin{}
+	mdx, mdx2 := &dataexchangemocks.Plugin{}, &dataexchangemocks.Plugin{}
+	mps, mps2 := &publicstoragemocks.Plugin{}, &publicstoragemocks.Plugin{}
+	o := Overrides{
+		"ns1": {Blockchain: mbi2, DataExchange: mdx2, PublicStorage: mps2},
+		"ns2": {},
+	}
+
+	assert.Equal(t, mbi2, o.Blockchain("ns1", mbi))
+	assert.Equal(t, mdx2, o.DataExchange("ns1", mdx))
+	assert.Equal(t, mps2, o.PublicStorage("ns1", mps))
+
+	assert.Equal(t, mbi, o.Blockchain("ns2", mbi))
+	assert.Equal(t, mdx, o.DataExchange("ns2", mdx))
+	assert.Equal(t, mps, o.PublicStorage("ns2", mps))
+
+	var none Overrides
+	assert.Equal(t, mbi, none.Blockchain("ns1", mbi))
+	assert.Equal(t, mdx, none.DataExchange("ns1", mdx))
+	assert.Equal(t, mps, none.PublicStorage("ns1", mps))
+}
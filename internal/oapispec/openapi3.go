@@ -243,6 +243,8 @@ func addRoute(ctx context.Context, doc *openapi3.T, route *Route) {
 		pi.Put = op
 	case http.MethodPost:
 		pi.Post = op
+	case http.MethodPatch:
+		pi.Patch = op
 	case http.MethodDelete:
 		pi.Delete = op
 	}
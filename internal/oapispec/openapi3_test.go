@@ -102,6 +102,20 @@ var testRoutes = []*Route{
 		JSONOutputValue: func() interface{} { return nil },
 		JSONOutputCodes: []int{http.StatusNoContent},
 	},
+	{
+		Name:   "op5",
+		Path:   "example2/{id}",
+		Method: http.MethodPatch,
+		PathParams: []*PathParam{
+			{Name: "id", Description: i18n.MsgTBD},
+		},
+		QueryParams:     nil,
+		FilterFactory:   nil,
+		Description:     i18n.MsgTBD,
+		JSONInputValue:  func() interface{} { return &fftypes.Data{} },
+		JSONOutputValue: func() interface{} { return &fftypes.Data{} },
+		JSONOutputCodes: []int{http.StatusOK},
+	},
 }
 
 func TestOpenAPI3SwaggerGen(t *testing.T) {
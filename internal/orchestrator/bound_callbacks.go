@@ -60,3 +60,11 @@ func (bc *boundCallbacks) MessageReceived(peerID string, data []byte) error {
 func (bc *boundCallbacks) TokenPoolCreated(plugin tokens.Plugin, tokenType fftypes.TokenType, tx *fftypes.UUID, protocolID, signingIdentity, protocolTxID string, additionalInfo fftypes.JSONObject) error {
 	return bc.am.TokenPoolCreated(plugin, tokenType, tx, protocolID, signingIdentity, protocolTxID, additionalInfo)
 }
+
+func (bc *boundCallbacks) TokensTransferred(plugin tokens.Plugin, poolProtocolID, signingKey, from, to string, amount *fftypes.BigInt, protocolTxID string, additionalInfo fftypes.JSONObject) error {
+	return bc.am.TokensTransferred(plugin, poolProtocolID, signingKey, from, to, amount, protocolTxID, additionalInfo)
+}
+
+func (bc *boundCallbacks) TokenPoolStatusChanged(plugin tokens.Plugin, poolProtocolID string, status fftypes.TokenPoolStatus, protocolTxID string, additionalInfo fftypes.JSONObject) error {
+	return bc.am.TokenPoolStatusChanged(plugin, poolProtocolID, status, protocolTxID, additionalInfo)
+}
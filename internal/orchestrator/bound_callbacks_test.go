@@ -71,4 +71,12 @@ func TestBoundCallbacks(t *testing.T) {
 	mam.On("TokenPoolCreated", mti, fftypes.TokenTypeFungible, txID, "123", "0x12345", "tx12345", info).Return(fmt.Errorf("pop"))
 	err = bc.TokenPoolCreated(mti, fftypes.TokenTypeFungible, txID, "123", "0x12345", "tx12345", info)
 	assert.EqualError(t, err, "pop")
+
+	mam.On("TokensTransferred", mti, "123", "0x0", "0x1", "0x2", fftypes.NewBigInt(1), "tx12345", info).Return(fmt.Errorf("pop"))
+	err = bc.TokensTransferred(mti, "123", "0x0", "0x1", "0x2", fftypes.NewBigInt(1), "tx12345", info)
+	assert.EqualError(t, err, "pop")
+
+	mam.On("TokenPoolStatusChanged", mti, "123", fftypes.TokenPoolStatusFrozen, "tx12345", info).Return(fmt.Errorf("pop"))
+	err = bc.TokenPoolStatusChanged(mti, "123", fftypes.TokenPoolStatusFrozen, "tx12345", info)
+	assert.EqualError(t, err, "pop")
 }
@@ -0,0 +1,153 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// PurgeExpiredData deletes the data items in a namespace that have passed their expiry time. Any blob attached
+// to an expired data item is removed from the Public Storage and Data Exchange, once no other data item refers
+// to it. Messages that referred to the purged data are left in place. Data referred to by a message that is still
+// pending is retained until that message is confirmed or rejected, so it is available to be sent and processed.
+func (or *orchestrator) PurgeExpiredData(ctx context.Context, ns string, dryRun bool) (*fftypes.PurgeResult, error) {
+	if err := or.verifyNamespaceSyntax(ctx, ns); err != nil {
+		return nil, err
+	}
+	fb := database.DataQueryFactory.NewFilter(ctx)
+	expired, _, err := or.database.GetData(ctx, fb.And(
+		fb.Eq("namespace", ns),
+		fb.Lte("expiresat", fftypes.Now()),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &fftypes.PurgeResult{
+		DryRun:  dryRun,
+		Expired: []*fftypes.UUID{},
+		Deleted: []*fftypes.UUID{},
+	}
+	for _, data := range expired {
+		pending, err := or.dataHasPendingMessages(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		if pending {
+			continue
+		}
+		result.Expired = append(result.Expired, data.ID)
+		if dryRun {
+			continue
+		}
+		log.L(ctx).Infof("Purging data %s which expired at %s", data.ID, data.ExpiresAt)
+		// The blob is removed first, so that a failure leaves the data to be retried on the next purge
+		if data.Blob != nil && data.Blob.Hash != nil {
+			if err := or.purgeBlob(ctx, ns, data); err != nil {
+				return nil, err
+			}
+		}
+		if err := or.database.DeleteData(ctx, data.ID); err != nil {
+			return nil, err
+		}
+		result.Deleted = append(result.Deleted, data.ID)
+	}
+	return result, nil
+}
+
+// dataHasPendingMessages checks whether any message that refers to the data is yet to be confirmed or rejected
+func (or *orchestrator) dataHasPendingMessages(ctx context.Context, data *fftypes.Data) (bool, error) {
+	fb := database.MessageQueryFactory.NewFilterLimit(ctx, 1)
+	msgs, _, err := or.database.GetMessagesForData(ctx, data.ID, fb.Eq("pending", true))
+	if err != nil {
+		return false, err
+	}
+	if len(msgs) > 0 {
+		log.L(ctx).Debugf("Retaining expired data %s, as it is referred to by pending message %s", data.ID, msgs[0].Header.ID)
+		return true, nil
+	}
+	return false, nil
+}
+
+// purgeBlob removes the blob attached to an expired data item, unless another data item refers to the same blob
+func (or *orchestrator) purgeBlob(ctx context.Context, ns string, data *fftypes.Data) error {
+	fb := database.DataQueryFactory.NewFilterLimit(ctx, 1)
+	others, _, err := or.database.GetData(ctx, fb.And(
+		fb.Eq("blob.hash", data.Blob.Hash),
+		fb.Neq("id", data.ID),
+	))
+	if err != nil {
+		return err
+	}
+	if len(others) > 0 {
+		log.L(ctx).Debugf("Retaining blob %s of data %s, as it is referred to by data %s", data.Blob.Hash, data.ID, others[0].ID)
+		return nil
+	}
+
+	if data.Blob.Public != "" {
+		if err := or.publicstorageFor(ns).DeleteData(ctx, data.Blob.Public); err != nil {
+			return err
+		}
+	}
+	bfb := database.BlobQueryFactory.NewFilter(ctx)
+	blobs, _, err := or.database.GetBlobs(ctx, bfb.Eq("hash", data.Blob.Hash))
+	if err != nil {
+		return err
+	}
+	dx := or.dataexchangeFor(ns)
+	for _, blob := range blobs {
+		if err := dx.DeleteBLOB(ctx, blob.PayloadRef); err != nil {
+			return err
+		}
+		if err := or.database.DeleteBlob(ctx, blob.Sequence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (or *orchestrator) dataPurgeLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			or.purgeAllExpiredData(or.ctx)
+		case <-or.ctx.Done():
+			log.L(or.ctx).Debugf("Data purge loop exiting")
+			return
+		}
+	}
+}
+
+func (or *orchestrator) purgeAllExpiredData(ctx context.Context) {
+	namespaces, _, err := or.database.GetNamespaces(ctx, database.NamespaceQueryFactory.NewFilter(ctx).And())
+	if err != nil {
+		log.L(ctx).Errorf("Failed to list namespaces for data purge: %s", err)
+		return
+	}
+	for _, ns := range namespaces {
+		if _, err := or.PurgeExpiredData(ctx, ns.Name, false); err != nil {
+			log.L(ctx).Errorf("Failed to purge expired data in namespace '%s': %s", ns.Name, err)
+		}
+	}
+}
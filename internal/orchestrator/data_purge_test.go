@@ -0,0 +1,255 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/config"
+
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func matchExpiredDataFilter(ns string) interface{} {
+	return mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.HasPrefix(fi.String(), fmt.Sprintf("( namespace == '%s' ) && ( expiresat <= ", ns))
+	})
+}
+
+func matchOtherBlobRefsFilter(data *fftypes.Data) interface{} {
+	return mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), fmt.Sprintf("id != '%s'", data.ID))
+	})
+}
+
+func TestPurgeExpiredDataDeletesDataAndBlob(t *testing.T) {
+	or := newTestOrchestrator()
+	blob := &fftypes.Blob{Hash: fftypes.NewRandB32(), PayloadRef: "ns1/blob1", Sequence: 12345}
+	plain := &fftypes.Data{ID: fftypes.NewUUID(), ExpiresAt: fftypes.Now()}
+	withBlob := &fftypes.Data{ID: fftypes.NewUUID(), ExpiresAt: fftypes.Now(), Blob: &fftypes.BlobRef{
+		Hash:   blob.Hash,
+		Public: "QmBlob1",
+	}}
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return([]*fftypes.Data{plain, withBlob}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetData", mock.Anything, matchOtherBlobRefsFilter(withBlob)).Return([]*fftypes.Data{}, nil, nil)
+	or.mdi.On("GetBlobs", mock.Anything, mock.Anything).Return([]*fftypes.Blob{blob}, nil, nil)
+	or.mps.On("DeleteData", mock.Anything, "QmBlob1").Return(nil)
+	or.mdx.On("DeleteBLOB", mock.Anything, "ns1/blob1").Return(nil)
+	or.mdi.On("DeleteBlob", mock.Anything, int64(12345)).Return(nil)
+	or.mdi.On("DeleteData", mock.Anything, plain.ID).Return(nil)
+	or.mdi.On("DeleteData", mock.Anything, withBlob.ID).Return(nil)
+
+	result, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.NoError(t, err)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, []*fftypes.UUID{plain.ID, withBlob.ID}, result.Expired)
+	assert.Equal(t, []*fftypes.UUID{plain.ID, withBlob.ID}, result.Deleted)
+	or.mdi.AssertExpectations(t)
+	or.mps.AssertExpectations(t)
+	or.mdx.AssertExpectations(t)
+}
+
+func TestPurgeExpiredDataRetainsSharedBlob(t *testing.T) {
+	or := newTestOrchestrator()
+	data := &fftypes.Data{ID: fftypes.NewUUID(), ExpiresAt: fftypes.Now(), Blob: &fftypes.BlobRef{
+		Hash:   fftypes.NewRandB32(),
+		Public: "QmShared",
+	}}
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return([]*fftypes.Data{data}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetData", mock.Anything, matchOtherBlobRefsFilter(data)).Return([]*fftypes.Data{{ID: fftypes.NewUUID()}}, nil, nil)
+	or.mdi.On("DeleteData", mock.Anything, data.ID).Return(nil)
+
+	result, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []*fftypes.UUID{data.ID}, result.Deleted)
+	or.mps.AssertNotCalled(t, "DeleteData", mock.Anything, mock.Anything)
+	or.mdx.AssertNotCalled(t, "DeleteBLOB", mock.Anything, mock.Anything)
+	or.mdi.AssertExpectations(t)
+}
+
+func TestPurgeExpiredDataDryRun(t *testing.T) {
+	or := newTestOrchestrator()
+	data := &fftypes.Data{ID: fftypes.NewUUID(), ExpiresAt: fftypes.Now(), Blob: &fftypes.BlobRef{
+		Hash: fftypes.NewRandB32(),
+	}}
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return([]*fftypes.Data{data}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+
+	result, err := or.PurgeExpiredData(or.ctx, "ns1", true)
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []*fftypes.UUID{data.ID}, result.Expired)
+	assert.Empty(t, result.Deleted)
+	or.mdi.AssertNotCalled(t, "DeleteData", mock.Anything, mock.Anything)
+	or.mdx.AssertNotCalled(t, "DeleteBLOB", mock.Anything, mock.Anything)
+	or.mdi.AssertExpectations(t)
+}
+
+func TestPurgeExpiredDataRetainsPendingMessageData(t *testing.T) {
+	or := newTestOrchestrator()
+	data := &fftypes.Data{ID: fftypes.NewUUID(), ExpiresAt: fftypes.Now()}
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return([]*fftypes.Data{data}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, data.ID, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == "pending == 1 limit=1"
+	})).Return([]*fftypes.Message{{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}}, nil, nil)
+
+	result, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Expired)
+	assert.Empty(t, result.Deleted)
+	or.mdi.AssertNotCalled(t, "DeleteData", mock.Anything, mock.Anything)
+	or.mdi.AssertExpectations(t)
+}
+
+func TestPurgeExpiredDataPendingMessagesFail(t *testing.T) {
+	or := newTestOrchestrator()
+	data := &fftypes.Data{ID: fftypes.NewUUID(), ExpiresAt: fftypes.Now()}
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return([]*fftypes.Data{data}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, data.ID, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPurgeExpiredDataBadNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	_, err := or.PurgeExpiredData(or.ctx, "!wrong", false)
+	assert.Regexp(t, "FF10131", err)
+}
+
+func TestPurgeExpiredDataQueryFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetData", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPurgeExpiredDataDeleteFail(t *testing.T) {
+	or := newTestOrchestrator()
+	data := &fftypes.Data{ID: fftypes.NewUUID(), ExpiresAt: fftypes.Now()}
+	or.mdi.On("GetData", mock.Anything, mock.Anything).Return([]*fftypes.Data{data}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("DeleteData", mock.Anything, data.ID).Return(fmt.Errorf("pop"))
+	_, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPurgeExpiredDataBlobRefsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	data := &fftypes.Data{ID: fftypes.NewUUID(), Blob: &fftypes.BlobRef{Hash: fftypes.NewRandB32()}}
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return([]*fftypes.Data{data}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetData", mock.Anything, matchOtherBlobRefsFilter(data)).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+	or.mdi.AssertNotCalled(t, "DeleteData", mock.Anything, mock.Anything)
+}
+
+func TestPurgeExpiredDataPublicDeleteFail(t *testing.T) {
+	or := newTestOrchestrator()
+	data := &fftypes.Data{ID: fftypes.NewUUID(), Blob: &fftypes.BlobRef{Hash: fftypes.NewRandB32(), Public: "QmBlob1"}}
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return([]*fftypes.Data{data}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetData", mock.Anything, matchOtherBlobRefsFilter(data)).Return([]*fftypes.Data{}, nil, nil)
+	or.mps.On("DeleteData", mock.Anything, "QmBlob1").Return(fmt.Errorf("pop"))
+	_, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPurgeExpiredDataGetBlobsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	data := &fftypes.Data{ID: fftypes.NewUUID(), Blob: &fftypes.BlobRef{Hash: fftypes.NewRandB32()}}
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return([]*fftypes.Data{data}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetData", mock.Anything, matchOtherBlobRefsFilter(data)).Return([]*fftypes.Data{}, nil, nil)
+	or.mdi.On("GetBlobs", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPurgeExpiredDataDXDeleteFail(t *testing.T) {
+	or := newTestOrchestrator()
+	data := &fftypes.Data{ID: fftypes.NewUUID(), Blob: &fftypes.BlobRef{Hash: fftypes.NewRandB32()}}
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return([]*fftypes.Data{data}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetData", mock.Anything, matchOtherBlobRefsFilter(data)).Return([]*fftypes.Data{}, nil, nil)
+	or.mdi.On("GetBlobs", mock.Anything, mock.Anything).Return([]*fftypes.Blob{{PayloadRef: "ns1/blob1"}}, nil, nil)
+	or.mdx.On("DeleteBLOB", mock.Anything, "ns1/blob1").Return(fmt.Errorf("pop"))
+	_, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPurgeExpiredDataBlobRecordDeleteFail(t *testing.T) {
+	or := newTestOrchestrator()
+	data := &fftypes.Data{ID: fftypes.NewUUID(), Blob: &fftypes.BlobRef{Hash: fftypes.NewRandB32()}}
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return([]*fftypes.Data{data}, nil, nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetData", mock.Anything, matchOtherBlobRefsFilter(data)).Return([]*fftypes.Data{}, nil, nil)
+	or.mdi.On("GetBlobs", mock.Anything, mock.Anything).Return([]*fftypes.Blob{{PayloadRef: "ns1/blob1", Sequence: 1}}, nil, nil)
+	or.mdx.On("DeleteBLOB", mock.Anything, "ns1/blob1").Return(nil)
+	or.mdi.On("DeleteBlob", mock.Anything, int64(1)).Return(fmt.Errorf("pop"))
+	_, err := or.PurgeExpiredData(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDataPurgeLoop(t *testing.T) {
+	or := newTestOrchestrator()
+	purged := make(chan struct{})
+	or.mdi.On("GetNamespaces", mock.Anything, mock.Anything).Return([]*fftypes.Namespace{{Name: "ns1"}, {Name: "ns2"}}, nil, nil)
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns1")).Return(nil, nil, fmt.Errorf("pop"))
+	or.mdi.On("GetData", mock.Anything, matchExpiredDataFilter("ns2")).Return([]*fftypes.Data{}, nil, nil).Run(func(args mock.Arguments) {
+		or.cancelCtx()
+		close(purged)
+	}).Once()
+
+	go or.dataPurgeLoop(1 * time.Millisecond)
+	<-purged
+	<-or.ctx.Done()
+	or.mdi.AssertExpectations(t)
+}
+
+func TestPurgeAllExpiredDataNamespacesFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetNamespaces", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	or.purgeAllExpiredData(or.ctx)
+	or.mdi.AssertExpectations(t)
+}
+
+func TestStartDataPurgeLoop(t *testing.T) {
+	or := newTestOrchestrator()
+	config.Set(config.DataPurgeInterval, "1h")
+	or.mbi.On("Start").Return(nil)
+	or.mba.On("Start").Return(nil)
+	or.mem.On("Start").Return(nil)
+	or.mbm.On("Start").Return(nil)
+	or.mpm.On("Start").Return(nil)
+	or.mti.On("Start").Return(nil)
+	err := or.Start()
+	assert.NoError(t, err)
+	or.cancelCtx()
+}
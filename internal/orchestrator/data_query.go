@@ -25,6 +25,9 @@ import (
 	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
+// maxMessageFetchIDs is the maximum number of messages that can be fetched by ID in a single call
+const maxMessageFetchIDs = 100
+
 func (or *orchestrator) verifyNamespaceSyntax(ctx context.Context, ns string) error {
 	return fftypes.ValidateFFNameField(ctx, ns, "namespace")
 }
@@ -118,6 +121,21 @@ func (or *orchestrator) GetDataByID(ctx context.Context, ns, id string) (*fftype
 	return or.database.GetDataByID(ctx, u, true)
 }
 
+func (or *orchestrator) GetDataVersionHistory(ctx context.Context, ns, id string) ([]*fftypes.Data, error) {
+	u, err := or.verifyIDAndNamespace(ctx, ns, id)
+	if err != nil {
+		return nil, err
+	}
+	history, err := or.database.GetDataVersionHistory(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 || history[0].Namespace != ns {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	return history, nil
+}
+
 func (or *orchestrator) GetDatatypeByID(ctx context.Context, ns, id string) (*fftypes.Datatype, error) {
 	u, err := or.verifyIDAndNamespace(ctx, ns, id)
 	if err != nil {
@@ -141,7 +159,19 @@ func (or *orchestrator) GetOperationByID(ctx context.Context, ns, id string) (*f
 	if err != nil {
 		return nil, err
 	}
-	return or.database.GetOperationByID(ctx, u)
+	op, err := or.database.GetOperationByID(ctx, u)
+	if err == nil && op != nil {
+		op.History, err = or.database.GetOperationHistory(ctx, u)
+	}
+	return op, err
+}
+
+func (or *orchestrator) GetOperationHistory(ctx context.Context, ns, id string) ([]*fftypes.OperationTransition, error) {
+	u, err := or.verifyIDAndNamespace(ctx, ns, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.database.GetOperationHistory(ctx, u)
 }
 
 func (or *orchestrator) GetEventByID(ctx context.Context, ns, id string) (*fftypes.Event, error) {
@@ -170,6 +200,31 @@ func (or *orchestrator) GetMessages(ctx context.Context, ns string, filter datab
 	return or.database.GetMessages(ctx, filter)
 }
 
+func (or *orchestrator) GetMessagesByIDs(ctx context.Context, ns string, ids []*fftypes.UUID) ([]*fftypes.Message, error) {
+	if err := or.verifyNamespaceSyntax(ctx, ns); err != nil {
+		return nil, err
+	}
+	if len(ids) > maxMessageFetchIDs {
+		return nil, i18n.NewError(ctx, i18n.MsgTooManyItems, "ids", maxMessageFetchIDs, len(ids))
+	}
+	idValues := make([]driver.Value, 0, len(ids))
+	for _, id := range ids {
+		if id != nil {
+			idValues = append(idValues, id)
+		}
+	}
+	if len(idValues) == 0 {
+		return []*fftypes.Message{}, nil
+	}
+	// Any IDs that are not found are simply omitted from the results
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	msgs, _, err := or.database.GetMessages(ctx, fb.And(
+		fb.Eq("namespace", ns),
+		fb.In("id", idValues),
+	).Limit(uint64(len(idValues))))
+	return msgs, err
+}
+
 func (or *orchestrator) GetMessageData(ctx context.Context, ns, id string) ([]*fftypes.Data, error) {
 	msg, err := or.getMessageByID(ctx, ns, id)
 	if err != nil || msg == nil {
@@ -264,6 +319,11 @@ func (or *orchestrator) GetDatatypes(ctx context.Context, ns string, filter data
 	return or.database.GetDatatypes(ctx, filter)
 }
 
+func (or *orchestrator) GetGroups(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Group, *database.FilterResult, error) {
+	filter = or.scopeNS(ns, filter)
+	return or.database.GetGroups(ctx, filter)
+}
+
 func (or *orchestrator) GetOperations(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Operation, *database.FilterResult, error) {
 	filter = or.scopeNS(ns, filter)
 	return or.database.GetOperations(ctx, filter)
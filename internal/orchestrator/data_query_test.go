@@ -167,6 +167,46 @@ func TestGetMessages(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetMessagesByIDs(t *testing.T) {
+	or := newTestOrchestrator()
+	u1 := fftypes.NewUUID()
+	u2 := fftypes.NewUUID()
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{
+		{Header: fftypes.MessageHeader{ID: u2}},
+	}, nil, nil)
+	msgs, err := or.GetMessagesByIDs(context.Background(), "ns1", []*fftypes.UUID{u1, nil, u2})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	filter := or.mdi.Calls[0].Arguments[1].(database.Filter)
+	fi, err := filter.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( namespace == 'ns1' ) && ( id IN ['"+u1.String()+"','"+u2.String()+"'] ) limit=2", fi.String())
+}
+
+func TestGetMessagesByIDsNone(t *testing.T) {
+	or := newTestOrchestrator()
+	msgs, err := or.GetMessagesByIDs(context.Background(), "ns1", []*fftypes.UUID{})
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+	or.mdi.AssertNotCalled(t, "GetMessages", mock.Anything, mock.Anything)
+}
+
+func TestGetMessagesByIDsTooMany(t *testing.T) {
+	or := newTestOrchestrator()
+	ids := make([]*fftypes.UUID, maxMessageFetchIDs+1)
+	for i := range ids {
+		ids[i] = fftypes.NewUUID()
+	}
+	_, err := or.GetMessagesByIDs(context.Background(), "ns1", ids)
+	assert.Regexp(t, "FF10227", err)
+}
+
+func TestGetMessagesByIDsBadNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	_, err := or.GetMessagesByIDs(context.Background(), "!wrong", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF10131", err)
+}
+
 func TestGetMessagesForData(t *testing.T) {
 	or := newTestOrchestrator()
 	u := fftypes.NewUUID()
@@ -418,6 +458,50 @@ func TestGetDataByIDBadID(t *testing.T) {
 	assert.Regexp(t, "FF10142", err)
 }
 
+func TestGetDataVersionHistory(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetDataVersionHistory", mock.Anything, u).Return([]*fftypes.Data{
+		{ID: u, Namespace: "ns1", Version: 1},
+		{ID: fftypes.NewUUID(), Namespace: "ns1", Version: 2, Previous: u},
+	}, nil)
+	history, err := or.GetDataVersionHistory(context.Background(), "ns1", u.String())
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+}
+
+func TestGetDataVersionHistoryNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetDataVersionHistory", mock.Anything, u).Return(nil, nil)
+	_, err := or.GetDataVersionHistory(context.Background(), "ns1", u.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetDataVersionHistoryWrongNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetDataVersionHistory", mock.Anything, u).Return([]*fftypes.Data{
+		{ID: u, Namespace: "ns2", Version: 1},
+	}, nil)
+	_, err := or.GetDataVersionHistory(context.Background(), "ns1", u.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetDataVersionHistoryFail(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetDataVersionHistory", mock.Anything, u).Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetDataVersionHistory(context.Background(), "ns1", u.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetDataVersionHistoryBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	_, err := or.GetDataVersionHistory(context.Background(), "", "")
+	assert.Regexp(t, "FF10142", err)
+}
+
 func TestGetData(t *testing.T) {
 	or := newTestOrchestrator()
 	u := fftypes.NewUUID()
@@ -469,12 +553,37 @@ func TestGetOperationByID(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetOperationByIDWithHistory(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	history := []*fftypes.OperationTransition{{Status: fftypes.OpStatusPending}}
+	or.mdi.On("GetOperationByID", mock.Anything, u).Return(&fftypes.Operation{ID: u}, nil)
+	or.mdi.On("GetOperationHistory", mock.Anything, u).Return(history, nil)
+	op, err := or.GetOperationByID(context.Background(), "ns1", u.String())
+	assert.NoError(t, err)
+	assert.Equal(t, history, op.History)
+}
+
 func TestGetOperationIDBadID(t *testing.T) {
 	or := newTestOrchestrator()
 	_, err := or.GetOperationByID(context.Background(), "", "")
 	assert.Regexp(t, "FF10142", err)
 }
 
+func TestGetOperationHistory(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetOperationHistory", mock.Anything, u).Return([]*fftypes.OperationTransition{}, nil)
+	_, err := or.GetOperationHistory(context.Background(), "ns1", u.String())
+	assert.NoError(t, err)
+}
+
+func TestGetOperationHistoryBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	_, err := or.GetOperationHistory(context.Background(), "", "")
+	assert.Regexp(t, "FF10142", err)
+}
+
 func TestGetEventByID(t *testing.T) {
 	or := newTestOrchestrator()
 	u := fftypes.NewUUID()
@@ -499,6 +608,18 @@ func TestGetDatatypes(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetGroups(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetGroups", mock.Anything, mock.MatchedBy(func(f database.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == "( description %= 'supply' ) && ( namespace == 'ns1' )"
+	})).Return([]*fftypes.Group{}, nil, nil)
+	fb := database.GroupQueryFactory.NewFilter(context.Background())
+	f := fb.And(fb.Contains("description", "supply"))
+	_, _, err := or.GetGroups(context.Background(), "ns1", f)
+	assert.NoError(t, err)
+}
+
 func TestGetOperations(t *testing.T) {
 	or := newTestOrchestrator()
 	u := fftypes.NewUUID()
@@ -0,0 +1,33 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// GetIdentityMetadata is the identity plugin callback to look up the metadata for an identity,
+// which is registered and persisted along with the organization that owns the identity
+func (or *orchestrator) GetIdentityMetadata(ctx context.Context, identifier string) (fftypes.JSONObject, error) {
+	org, err := or.database.GetOrganizationByIdentity(ctx, identifier)
+	if err != nil || org == nil {
+		return nil, err
+	}
+	return org.Metadata, nil
+}
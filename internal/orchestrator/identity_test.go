@@ -0,0 +1,51 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIdentityMetadata(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOrganizationByIdentity", or.ctx, "0x12345").Return(&fftypes.Organization{
+		Identity: "0x12345",
+		Metadata: fftypes.JSONObject{"role": "auditor"},
+	}, nil)
+	metadata, err := or.GetIdentityMetadata(or.ctx, "0x12345")
+	assert.NoError(t, err)
+	assert.Equal(t, "auditor", metadata.GetString("role"))
+}
+
+func TestGetIdentityMetadataNoOrg(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOrganizationByIdentity", or.ctx, "0x12345").Return(nil, nil)
+	metadata, err := or.GetIdentityMetadata(or.ctx, "0x12345")
+	assert.NoError(t, err)
+	assert.Nil(t, metadata)
+}
+
+func TestGetIdentityMetadataFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOrganizationByIdentity", or.ctx, "0x12345").Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetIdentityMetadata(or.ctx, "0x12345")
+	assert.EqualError(t, err, "pop")
+}
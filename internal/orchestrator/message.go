@@ -19,13 +19,54 @@ package orchestrator
 import (
 	"context"
 
+	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
+// readReceiptTag is the tag set on the unpinned private message sent back to the group as a read receipt
+const readReceiptTag = "read_receipt"
+
 func (or *orchestrator) RequestReply(ctx context.Context, ns string, msg *fftypes.MessageInOut) (reply *fftypes.MessageInOut, err error) {
 	if msg.Header.Group == nil && (msg.Group == nil || len(msg.Group.Members) == 0) {
 		return nil, i18n.NewError(ctx, i18n.MsgRequestMustBePrivate)
 	}
 	return or.PrivateMessaging().RequestReply(ctx, ns, msg)
 }
+
+func (or *orchestrator) MarkRead(ctx context.Context, ns, msgID string) error {
+	msg, err := or.getMessageByID(ctx, ns, msgID)
+	if err != nil {
+		return err
+	}
+	if msg.Header.Namespace != ns {
+		return i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	if msg.ReadAt != nil {
+		// Already read - we keep the time of the first read
+		return nil
+	}
+	msg.ReadAt = fftypes.Now()
+	update := database.MessageQueryFactory.NewUpdate(ctx).Set("readat", msg.ReadAt)
+	if err = or.database.UpdateMessage(ctx, msg.Header.ID, update); err != nil {
+		return err
+	}
+
+	if msg.Header.Type == fftypes.MessageTypePrivate && config.GetBool(config.PrivateMessagingReadReceipts) {
+		// Send a small unpinned acknowledgement back to the group, correlated to the original message
+		receipt := &fftypes.MessageInOut{
+			Message: fftypes.Message{
+				Header: fftypes.MessageHeader{
+					CID:    msg.Header.ID,
+					TxType: fftypes.TransactionTypeNone,
+					Group:  msg.Header.Group,
+					Topics: msg.Header.Topics,
+					Tag:    readReceiptTag,
+				},
+			},
+		}
+		_, err = or.PrivateMessaging().SendMessage(ctx, ns, receipt, false)
+	}
+	return err
+}
@@ -0,0 +1,162 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// DeleteMessages deletes the messages in a namespace that match the filter, along with their data references and pins.
+// Only messages that have been confirmed or rejected are deleted, as a pending message is still being sent or processed.
+// The data and blobs are left in place, as they can be shared between messages. Once every message in a batch
+// has been deleted, the operations of the batch transaction are soft deleted (to be removed by an operation purge).
+// If the filter has a limit, at most that many messages are deleted. Otherwise every match is deleted,
+// a page of message.delete.readPageSize at a time.
+func (or *orchestrator) DeleteMessages(ctx context.Context, ns string, filter database.AndFilter) (int64, error) {
+	if err := or.verifyNamespaceSyntax(ctx, ns); err != nil {
+		return 0, err
+	}
+	filter = or.scopeNS(ns, filter.Condition(filter.Builder().Eq("pending", false)))
+	fi, err := filter.Finalize()
+	if err != nil {
+		return 0, err
+	}
+	limited := fi.Limit > 0
+	pageSize := fi.Limit
+	if !limited {
+		pageSize = uint64(config.GetUint(config.MessageDeleteReadPageSize))
+		filter.Limit(pageSize)
+	}
+
+	var deleted int64
+	for {
+		// Deleted messages no longer match, so each page is read from the start
+		msgs, _, err := or.database.GetMessages(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		if err := or.deleteMessagesPage(ctx, msgs); err != nil {
+			return 0, err
+		}
+		deleted += int64(len(msgs))
+		if limited || uint64(len(msgs)) < pageSize {
+			return deleted, nil
+		}
+	}
+}
+
+// deleteMessagesPage deletes a page of messages, with their pins, and the operations of any batch left empty
+func (or *orchestrator) deleteMessagesPage(ctx context.Context, msgs []*fftypes.Message) error {
+	return or.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		batches := make([]*fftypes.Batch, 0)
+		seenBatches := make(map[fftypes.UUID]*fftypes.Batch)
+		for _, msg := range msgs {
+			log.L(ctx).Infof("Deleting message %s", msg.Header.ID)
+			if err := or.database.DeleteMessage(ctx, msg.Header.ID); err != nil {
+				return err
+			}
+			if msg.BatchID == nil {
+				continue
+			}
+			batch, seen := seenBatches[*msg.BatchID]
+			if !seen {
+				var err error
+				if batch, err = or.database.GetBatchByID(ctx, msg.BatchID); err != nil {
+					return err
+				}
+				seenBatches[*msg.BatchID] = batch
+				if batch != nil {
+					batches = append(batches, batch)
+				}
+			}
+			if batch != nil {
+				if err := or.deleteMessagePins(ctx, batch, msg); err != nil {
+					return err
+				}
+			}
+		}
+		for _, batch := range batches {
+			if err := or.deleteBatchOperations(ctx, batch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteMessagePins deletes the pins of a message, which are found from the position of its topics in the batch.
+// A private batch has a pin for every message-topic in each group it is delivered to, in turn.
+func (or *orchestrator) deleteMessagePins(ctx context.Context, batch *fftypes.Batch, msg *fftypes.Message) error {
+	var topicCount, first, count int64
+	for _, m := range batch.Payload.Messages {
+		if m != nil && m.Header.ID.Equals(msg.Header.ID) {
+			first, count = topicCount, int64(len(m.Header.Topics))
+		}
+		if m != nil {
+			topicCount += int64(len(m.Header.Topics))
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	pins, _, err := or.database.GetPins(ctx, database.PinQueryFactory.NewFilter(ctx).Eq("batch", batch.ID))
+	if err != nil {
+		return err
+	}
+	for _, pin := range pins {
+		if index := pin.Index % topicCount; index >= first && index < first+count {
+			if err := or.database.DeletePin(ctx, pin.Sequence); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteBatchOperations soft deletes the operations of the transaction that pinned a batch, once no messages remain in the batch
+func (or *orchestrator) deleteBatchOperations(ctx context.Context, batch *fftypes.Batch) error {
+	remaining, _, err := or.database.GetMessages(ctx, database.MessageQueryFactory.NewFilterLimit(ctx, 1).Eq("batch", batch.ID))
+	if err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		log.L(ctx).Debugf("Retaining operations of batch %s, as it contains message %s", batch.ID, remaining[0].Header.ID)
+		return nil
+	}
+	if batch.Payload.TX.ID == nil {
+		return nil
+	}
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	ops, _, err := or.database.GetOperations(ctx, fb.And(
+		fb.Eq("tx", batch.Payload.TX.ID),
+		fb.Eq("deleted", nil),
+	))
+	if err != nil {
+		return err
+	}
+	for _, op := range ops {
+		if err := or.database.SoftDeleteOperation(ctx, op.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
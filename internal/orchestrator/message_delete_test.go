@@ -0,0 +1,294 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func matchFilterContaining(s string) interface{} {
+	return mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), s)
+	})
+}
+
+func mockRunAsGroupPassthrough(or *testOrchestrator) {
+	rag := or.mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+}
+
+func testDeleteMessagesFilter() database.AndFilter {
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	return fb.And(fb.Eq("type", fftypes.MessageTypeBroadcast))
+}
+
+func TestDeleteMessagesCascade(t *testing.T) {
+	or := newTestOrchestrator()
+	batch1 := &fftypes.Batch{ID: fftypes.NewUUID()}
+	batch1.Payload.TX.ID = fftypes.NewUUID()
+	batch2 := &fftypes.Batch{ID: fftypes.NewUUID()}
+	msg1 := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFNameArray{"t1", "t2"}}, BatchID: batch1.ID}
+	msg2 := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFNameArray{"t1"}}, BatchID: batch1.ID}
+	msg3 := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFNameArray{"t1"}}, BatchID: batch2.ID}
+	msg4 := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}
+	batch1.Payload.Messages = []*fftypes.Message{msg1, msg2}
+	batch2.Payload.Messages = []*fftypes.Message{{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFNameArray{"t1"}}}, msg3}
+	op1 := &fftypes.Operation{ID: fftypes.NewUUID()}
+	op2 := &fftypes.Operation{ID: fftypes.NewUUID()}
+
+	mockRunAsGroupPassthrough(or)
+	or.mdi.On("GetMessages", mock.Anything, matchFilterContaining("( type == 'broadcast' ) && ( pending == 0 ) && ( namespace == 'ns1' )")).
+		Return([]*fftypes.Message{msg1, msg2, msg3, msg4}, nil, nil)
+	for _, msg := range []*fftypes.Message{msg1, msg2, msg3, msg4} {
+		or.mdi.On("DeleteMessage", mock.Anything, msg.Header.ID).Return(nil).Once()
+	}
+	or.mdi.On("GetBatchByID", mock.Anything, batch1.ID).Return(batch1, nil).Once()
+	or.mdi.On("GetBatchByID", mock.Anything, batch2.ID).Return(batch2, nil).Once()
+	// batch1 was delivered to an additional group, so has a second set of pins for its three message-topics
+	or.mdi.On("GetPins", mock.Anything, matchFilterContaining(fmt.Sprintf("batch == '%s'", batch1.ID))).Return([]*fftypes.Pin{
+		{Sequence: 10, Index: 0}, {Sequence: 11, Index: 1}, {Sequence: 12, Index: 2},
+		{Sequence: 13, Index: 3}, {Sequence: 14, Index: 4}, {Sequence: 15, Index: 5},
+	}, nil, nil).Twice()
+	or.mdi.On("GetPins", mock.Anything, matchFilterContaining(fmt.Sprintf("batch == '%s'", batch2.ID))).Return([]*fftypes.Pin{
+		{Sequence: 20, Index: 0}, {Sequence: 21, Index: 1},
+	}, nil, nil).Once()
+	for _, seq := range []int64{10, 11, 12, 13, 14, 15, 21} {
+		or.mdi.On("DeletePin", mock.Anything, seq).Return(nil).Once()
+	}
+	or.mdi.On("GetMessages", mock.Anything, matchFilterContaining(fmt.Sprintf("batch == '%s'", batch1.ID))).Return([]*fftypes.Message{}, nil, nil).Once()
+	or.mdi.On("GetMessages", mock.Anything, matchFilterContaining(fmt.Sprintf("batch == '%s'", batch2.ID))).Return([]*fftypes.Message{
+		{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}},
+	}, nil, nil).Once()
+	or.mdi.On("GetOperations", mock.Anything, matchFilterContaining(fmt.Sprintf("( tx == '%s' ) && ( deleted == null )", batch1.Payload.TX.ID))).
+		Return([]*fftypes.Operation{op1, op2}, nil, nil)
+	or.mdi.On("SoftDeleteOperation", mock.Anything, op1.ID).Return(nil)
+	or.mdi.On("SoftDeleteOperation", mock.Anything, op2.ID).Return(nil)
+
+	deleted, err := or.DeleteMessages(or.ctx, "ns1", testDeleteMessagesFilter())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+	or.mdi.AssertExpectations(t)
+	or.mdi.AssertNotCalled(t, "DeletePin", mock.Anything, int64(20))
+}
+
+func TestDeleteMessagesPaged(t *testing.T) {
+	or := newTestOrchestrator()
+	config.Set(config.MessageDeleteReadPageSize, 2)
+	defer config.Reset()
+	msgs := make([]*fftypes.Message, 5)
+	for i := range msgs {
+		msgs[i] = &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}
+		or.mdi.On("DeleteMessage", mock.Anything, msgs[i].Header.ID).Return(nil).Once()
+	}
+
+	// Each page is read from the start with the page size as the limit, until a short page is returned
+	mockRunAsGroupPassthrough(or)
+	pageFilter := mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.Skip == 0 && fi.Limit == 2
+	})
+	or.mdi.On("GetMessages", mock.Anything, pageFilter).Return(msgs[0:2], nil, nil).Once()
+	or.mdi.On("GetMessages", mock.Anything, pageFilter).Return(msgs[2:4], nil, nil).Once()
+	or.mdi.On("GetMessages", mock.Anything, pageFilter).Return(msgs[4:], nil, nil).Once()
+
+	deleted, err := or.DeleteMessages(or.ctx, "ns1", testDeleteMessagesFilter())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), deleted)
+	or.mdi.AssertExpectations(t)
+}
+
+func TestDeleteMessagesExplicitLimit(t *testing.T) {
+	or := newTestOrchestrator()
+	msgs := []*fftypes.Message{
+		{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}},
+		{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}},
+	}
+	mockRunAsGroupPassthrough(or)
+	or.mdi.On("GetMessages", mock.Anything, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.Limit == 2
+	})).Return(msgs, nil, nil).Once()
+	for _, msg := range msgs {
+		or.mdi.On("DeleteMessage", mock.Anything, msg.Header.ID).Return(nil).Once()
+	}
+
+	filter := testDeleteMessagesFilter()
+	filter.Limit(2)
+	deleted, err := or.DeleteMessages(or.ctx, "ns1", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	or.mdi.AssertExpectations(t)
+}
+
+func TestDeleteMessagesBadFilter(t *testing.T) {
+	or := newTestOrchestrator()
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	_, err := or.DeleteMessages(or.ctx, "ns1", fb.And(fb.Eq("created", map[bool]bool{true: false})))
+	assert.Regexp(t, "FF10149", err)
+}
+
+func TestDeleteMessagesBadNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	_, err := or.DeleteMessages(or.ctx, "!wrong", testDeleteMessagesFilter())
+	assert.Regexp(t, "FF10131", err)
+}
+
+func TestDeleteMessagesQueryFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := or.DeleteMessages(or.ctx, "ns1", testDeleteMessagesFilter())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteMessagesDeleteFail(t *testing.T) {
+	or := newTestOrchestrator()
+	msg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}
+	mockRunAsGroupPassthrough(or)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{msg}, nil, nil)
+	or.mdi.On("DeleteMessage", mock.Anything, msg.Header.ID).Return(fmt.Errorf("pop"))
+	deleted, err := or.DeleteMessages(or.ctx, "ns1", testDeleteMessagesFilter())
+	assert.EqualError(t, err, "pop")
+	assert.Zero(t, deleted)
+}
+
+func TestDeleteMessagesGetBatchFail(t *testing.T) {
+	or := newTestOrchestrator()
+	msg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}, BatchID: fftypes.NewUUID()}
+	mockRunAsGroupPassthrough(or)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{msg}, nil, nil)
+	or.mdi.On("DeleteMessage", mock.Anything, msg.Header.ID).Return(nil)
+	or.mdi.On("GetBatchByID", mock.Anything, msg.BatchID).Return(nil, fmt.Errorf("pop"))
+	_, err := or.DeleteMessages(or.ctx, "ns1", testDeleteMessagesFilter())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteMessagesBatchNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	batchID := fftypes.NewUUID()
+	msg1 := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}, BatchID: batchID}
+	msg2 := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}, BatchID: batchID}
+	mockRunAsGroupPassthrough(or)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{msg1, msg2}, nil, nil)
+	or.mdi.On("DeleteMessage", mock.Anything, mock.Anything).Return(nil)
+	or.mdi.On("GetBatchByID", mock.Anything, batchID).Return(nil, nil).Once()
+	deleted, err := or.DeleteMessages(or.ctx, "ns1", testDeleteMessagesFilter())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	or.mdi.AssertExpectations(t)
+	or.mdi.AssertNotCalled(t, "GetPins", mock.Anything, mock.Anything)
+}
+
+func TestDeleteMessagesRemainingQueryFail(t *testing.T) {
+	or := newTestOrchestrator()
+	batch := &fftypes.Batch{ID: fftypes.NewUUID()}
+	msg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}, BatchID: batch.ID}
+	mockRunAsGroupPassthrough(or)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{msg}, nil, nil).Once()
+	or.mdi.On("DeleteMessage", mock.Anything, msg.Header.ID).Return(nil)
+	or.mdi.On("GetBatchByID", mock.Anything, batch.ID).Return(batch, nil)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Once()
+	_, err := or.DeleteMessages(or.ctx, "ns1", testDeleteMessagesFilter())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteMessagesPinsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	batch := &fftypes.Batch{ID: fftypes.NewUUID()}
+	msg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFNameArray{"t1"}}, BatchID: batch.ID}
+	batch.Payload.Messages = []*fftypes.Message{msg}
+	mockRunAsGroupPassthrough(or)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{msg}, nil, nil).Once()
+	or.mdi.On("DeleteMessage", mock.Anything, msg.Header.ID).Return(nil)
+	or.mdi.On("GetBatchByID", mock.Anything, batch.ID).Return(batch, nil)
+	or.mdi.On("GetPins", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := or.DeleteMessages(or.ctx, "ns1", testDeleteMessagesFilter())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteMessagePinsGetPinsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	msg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFNameArray{"t1"}}}
+	batch := &fftypes.Batch{ID: fftypes.NewUUID()}
+	batch.Payload.Messages = []*fftypes.Message{nil, msg}
+	or.mdi.On("GetPins", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	err := or.deleteMessagePins(or.ctx, batch, msg)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteMessagePinsDeleteFail(t *testing.T) {
+	or := newTestOrchestrator()
+	msg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFNameArray{"t1"}}}
+	batch := &fftypes.Batch{ID: fftypes.NewUUID()}
+	batch.Payload.Messages = []*fftypes.Message{msg}
+	or.mdi.On("GetPins", mock.Anything, mock.Anything).Return([]*fftypes.Pin{{Sequence: 1}}, nil, nil)
+	or.mdi.On("DeletePin", mock.Anything, int64(1)).Return(fmt.Errorf("pop"))
+	err := or.deleteMessagePins(or.ctx, batch, msg)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteMessagePinsNotInBatch(t *testing.T) {
+	or := newTestOrchestrator()
+	msg := &fftypes.Message{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFNameArray{"t1"}}}
+	err := or.deleteMessagePins(or.ctx, &fftypes.Batch{ID: fftypes.NewUUID()}, msg)
+	assert.NoError(t, err)
+	or.mdi.AssertNotCalled(t, "GetPins", mock.Anything, mock.Anything)
+}
+
+func TestDeleteBatchOperationsNoTX(t *testing.T) {
+	or := newTestOrchestrator()
+	batch := &fftypes.Batch{ID: fftypes.NewUUID()}
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	err := or.deleteBatchOperations(or.ctx, batch)
+	assert.NoError(t, err)
+	or.mdi.AssertNotCalled(t, "GetOperations", mock.Anything, mock.Anything)
+}
+
+func TestDeleteBatchOperationsGetOperationsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	batch := &fftypes.Batch{ID: fftypes.NewUUID()}
+	batch.Payload.TX.ID = fftypes.NewUUID()
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	err := or.deleteBatchOperations(or.ctx, batch)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteBatchOperationsSoftDeleteFail(t *testing.T) {
+	or := newTestOrchestrator()
+	batch := &fftypes.Batch{ID: fftypes.NewUUID()}
+	batch.Payload.TX.ID = fftypes.NewUUID()
+	op := &fftypes.Operation{ID: fftypes.NewUUID()}
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{op}, nil, nil)
+	or.mdi.On("SoftDeleteOperation", mock.Anything, op.ID).Return(fmt.Errorf("pop"))
+	err := or.deleteBatchOperations(or.ctx, batch)
+	assert.EqualError(t, err, "pop")
+}
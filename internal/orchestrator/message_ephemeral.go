@@ -0,0 +1,224 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+const defaultEphemeralPurgeInterval = time.Minute
+
+// purgeEphemeralMessages removes every database record of the confirmed ephemeral messages in a namespace,
+// once their events have been delivered to all durable subscriptions in the namespace. The events, pins and
+// message are removed, along with any data (and blob) no other message refers to. Once no messages remain
+// in the batch, the batch itself is removed along with its transaction and operations.
+func (or *orchestrator) purgeEphemeralMessages(ctx context.Context, ns string) error {
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	msgs, _, err := or.database.GetMessages(ctx, fb.And(
+		fb.Eq("namespace", ns),
+		fb.Eq("ephemeral", true),
+		fb.Eq("pending", false),
+	))
+	if err != nil || len(msgs) == 0 {
+		return err
+	}
+
+	delivered, err := or.deliveredEventSequence(ctx, ns)
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		efb := database.EventQueryFactory.NewFilter(ctx)
+		events, _, err := or.database.GetEvents(ctx, efb.Eq("reference", msg.Header.ID))
+		if err != nil {
+			return err
+		}
+		if !eventsDelivered(events, delivered) {
+			log.L(ctx).Debugf("Retaining ephemeral message %s, as its events are yet to be delivered", msg.Header.ID)
+			continue
+		}
+		err = or.database.RunAsGroup(ctx, func(ctx context.Context) error {
+			return or.purgeEphemeralMessage(ctx, ns, msg, events)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliveredEventSequence returns the sequence of the last event delivered to every durable subscription in a namespace.
+// A subscription that has not yet stored an offset has not had any events delivered.
+func (or *orchestrator) deliveredEventSequence(ctx context.Context, ns string) (int64, error) {
+	fb := database.SubscriptionQueryFactory.NewFilter(ctx)
+	subs, _, err := or.database.GetSubscriptions(ctx, fb.Eq("namespace", ns))
+	if err != nil {
+		return -1, err
+	}
+	delivered := int64(math.MaxInt64)
+	for _, sub := range subs {
+		offset, err := or.database.GetOffset(ctx, fftypes.OffsetTypeSubscription, sub.ID.String())
+		if err != nil {
+			return -1, err
+		}
+		if offset == nil {
+			return -1, nil
+		}
+		if offset.Current < delivered {
+			delivered = offset.Current
+		}
+	}
+	return delivered, nil
+}
+
+func eventsDelivered(events []*fftypes.Event, delivered int64) bool {
+	for _, event := range events {
+		if event.Sequence > delivered {
+			return false
+		}
+	}
+	return true
+}
+
+func (or *orchestrator) purgeEphemeralMessage(ctx context.Context, ns string, msg *fftypes.Message, events []*fftypes.Event) error {
+	log.L(ctx).Infof("Purging ephemeral message %s", msg.Header.ID)
+	for _, event := range events {
+		if err := or.database.DeleteEvent(ctx, event.ID); err != nil {
+			return err
+		}
+	}
+
+	var batch *fftypes.Batch
+	if msg.BatchID != nil {
+		var err error
+		if batch, err = or.database.GetBatchByID(ctx, msg.BatchID); err != nil {
+			return err
+		}
+		if batch != nil {
+			if err := or.deleteMessagePins(ctx, batch, msg); err != nil {
+				return err
+			}
+		}
+	}
+	if err := or.database.DeleteMessage(ctx, msg.Header.ID); err != nil {
+		return err
+	}
+
+	for _, dataRef := range msg.Data {
+		if err := or.purgeEphemeralData(ctx, ns, dataRef.ID); err != nil {
+			return err
+		}
+	}
+	if batch != nil {
+		return or.purgeEphemeralBatch(ctx, batch)
+	}
+	return nil
+}
+
+// purgeEphemeralData removes a data item of a purged ephemeral message, unless another message refers to it
+func (or *orchestrator) purgeEphemeralData(ctx context.Context, ns string, dataID *fftypes.UUID) error {
+	fb := database.MessageQueryFactory.NewFilterLimit(ctx, 1)
+	others, _, err := or.database.GetMessagesForData(ctx, dataID, fb.And())
+	if err != nil {
+		return err
+	}
+	if len(others) > 0 {
+		log.L(ctx).Debugf("Retaining data %s, as it is referred to by message %s", dataID, others[0].Header.ID)
+		return nil
+	}
+	data, err := or.database.GetDataByID(ctx, dataID, false)
+	if err != nil || data == nil {
+		return err
+	}
+	if data.Blob != nil && data.Blob.Hash != nil {
+		if err := or.purgeBlob(ctx, ns, data); err != nil {
+			return err
+		}
+	}
+	return or.database.DeleteData(ctx, data.ID)
+}
+
+// purgeEphemeralBatch removes a batch along with its transaction and operations, once no messages remain in the batch
+func (or *orchestrator) purgeEphemeralBatch(ctx context.Context, batch *fftypes.Batch) error {
+	remaining, _, err := or.database.GetMessages(ctx, database.MessageQueryFactory.NewFilterLimit(ctx, 1).Eq("batch", batch.ID))
+	if err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		return nil
+	}
+	if txID := batch.Payload.TX.ID; txID != nil {
+		ops, _, err := or.database.GetOperations(ctx, database.OperationQueryFactory.NewFilter(ctx).Eq("tx", txID))
+		if err != nil {
+			return err
+		}
+		for _, op := range ops {
+			if err := or.database.DeleteOperation(ctx, op.ID); err != nil {
+				return err
+			}
+		}
+		if err := or.database.DeleteTransaction(ctx, txID); err != nil && err != database.DeleteRecordNotFound {
+			return err
+		}
+	}
+	return or.database.DeleteBatch(ctx, batch.ID)
+}
+
+// ephemeralPurgeInterval returns the configured purge interval. Ephemeral messages must not be retained
+// indefinitely, so the purge always runs, and an invalid interval falls back to the default
+func ephemeralPurgeInterval(ctx context.Context) time.Duration {
+	interval := config.GetDuration(config.MessageEphemeralPurgeInterval)
+	if interval <= 0 {
+		log.L(ctx).Warnf("Invalid %s '%s' - using %s", config.MessageEphemeralPurgeInterval, config.GetString(config.MessageEphemeralPurgeInterval), defaultEphemeralPurgeInterval)
+		return defaultEphemeralPurgeInterval
+	}
+	return interval
+}
+
+func (or *orchestrator) ephemeralPurgeLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			or.purgeAllEphemeralMessages(or.ctx)
+		case <-or.ctx.Done():
+			log.L(or.ctx).Debugf("Ephemeral message purge loop exiting")
+			return
+		}
+	}
+}
+
+func (or *orchestrator) purgeAllEphemeralMessages(ctx context.Context) {
+	namespaces, _, err := or.database.GetNamespaces(ctx, database.NamespaceQueryFactory.NewFilter(ctx).And())
+	if err != nil {
+		log.L(ctx).Errorf("Failed to list namespaces for ephemeral message purge: %s", err)
+		return
+	}
+	for _, ns := range namespaces {
+		if err := or.purgeEphemeralMessages(ctx, ns.Name); err != nil {
+			log.L(ctx).Errorf("Failed to purge ephemeral messages in namespace '%s': %s", ns.Name, err)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type ephemeralPurgeTest struct {
+	sub   *fftypes.Subscription
+	batch *fftypes.Batch
+	msg   *fftypes.Message
+	data  *fftypes.Data
+	blob  *fftypes.Blob
+	event *fftypes.Event
+	op    *fftypes.Operation
+}
+
+// mockEphemeralPurge sets up the mocks for purging a single ephemeral message, which is the only message in its
+// batch and whose event has been delivered. Mocks set up before calling this take precedence, to inject failures.
+func mockEphemeralPurge(or *testOrchestrator) *ephemeralPurgeTest {
+	ept := &ephemeralPurgeTest{
+		sub:   &fftypes.Subscription{SubscriptionRef: fftypes.SubscriptionRef{ID: fftypes.NewUUID()}},
+		batch: &fftypes.Batch{ID: fftypes.NewUUID()},
+		blob:  &fftypes.Blob{Hash: fftypes.NewRandB32(), PayloadRef: "ns1/blob1", Sequence: 12345},
+		op:    &fftypes.Operation{ID: fftypes.NewUUID()},
+	}
+	ept.batch.Payload.TX.ID = fftypes.NewUUID()
+	ept.data = &fftypes.Data{ID: fftypes.NewUUID(), Blob: &fftypes.BlobRef{Hash: ept.blob.Hash}}
+	ept.msg = &fftypes.Message{
+		Header:    fftypes.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFNameArray{"t1"}},
+		BatchID:   ept.batch.ID,
+		Ephemeral: true,
+		Data:      fftypes.DataRefs{{ID: ept.data.ID}},
+	}
+	ept.batch.Payload.Messages = []*fftypes.Message{ept.msg}
+	ept.event = &fftypes.Event{ID: fftypes.NewUUID(), Reference: ept.msg.Header.ID, Sequence: 50}
+
+	mockRunAsGroupPassthrough(or)
+	or.mdi.On("GetMessages", mock.Anything, matchFilterContaining("ephemeral")).Return([]*fftypes.Message{ept.msg}, nil, nil)
+	or.mdi.On("GetSubscriptions", mock.Anything, mock.Anything).Return([]*fftypes.Subscription{ept.sub}, nil, nil)
+	or.mdi.On("GetOffset", mock.Anything, fftypes.OffsetTypeSubscription, ept.sub.ID.String()).Return(&fftypes.Offset{Current: 100}, nil)
+	or.mdi.On("GetEvents", mock.Anything, mock.Anything).Return([]*fftypes.Event{ept.event}, nil, nil)
+	or.mdi.On("DeleteEvent", mock.Anything, ept.event.ID).Return(nil)
+	or.mdi.On("GetBatchByID", mock.Anything, ept.batch.ID).Return(ept.batch, nil)
+	or.mdi.On("GetPins", mock.Anything, mock.Anything).Return([]*fftypes.Pin{{Sequence: 1, Index: 0}}, nil, nil)
+	or.mdi.On("DeletePin", mock.Anything, int64(1)).Return(nil)
+	or.mdi.On("DeleteMessage", mock.Anything, ept.msg.Header.ID).Return(nil)
+	or.mdi.On("GetMessagesForData", mock.Anything, ept.data.ID, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetDataByID", mock.Anything, ept.data.ID, false).Return(ept.data, nil)
+	or.mdi.On("GetData", mock.Anything, matchOtherBlobRefsFilter(ept.data)).Return([]*fftypes.Data{}, nil, nil)
+	or.mdi.On("GetBlobs", mock.Anything, mock.Anything).Return([]*fftypes.Blob{ept.blob}, nil, nil)
+	or.mdx.On("DeleteBLOB", mock.Anything, "ns1/blob1").Return(nil)
+	or.mdi.On("DeleteBlob", mock.Anything, int64(12345)).Return(nil)
+	or.mdi.On("DeleteData", mock.Anything, ept.data.ID).Return(nil)
+	or.mdi.On("GetMessages", mock.Anything, matchFilterContaining("batch ==")).Return([]*fftypes.Message{}, nil, nil)
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{ept.op}, nil, nil)
+	or.mdi.On("DeleteOperation", mock.Anything, ept.op.ID).Return(nil)
+	or.mdi.On("DeleteTransaction", mock.Anything, ept.batch.Payload.TX.ID).Return(nil)
+	or.mdi.On("DeleteBatch", mock.Anything, ept.batch.ID).Return(nil)
+	return ept
+}
+
+func TestPurgeEphemeralMessagesLeavesNoTrace(t *testing.T) {
+	or := newTestOrchestrator()
+	mockEphemeralPurge(or)
+
+	err := or.purgeEphemeralMessages(or.ctx, "ns1")
+	assert.NoError(t, err)
+	or.mdi.AssertExpectations(t)
+	or.mdx.AssertExpectations(t)
+}
+
+func TestPurgeEphemeralMessagesTransactionNotStored(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("DeleteTransaction", mock.Anything, mock.Anything).Return(database.DeleteRecordNotFound)
+	mockEphemeralPurge(or)
+
+	err := or.purgeEphemeralMessages(or.ctx, "ns1")
+	assert.NoError(t, err)
+	or.mdi.AssertCalled(t, "DeleteBatch", mock.Anything, mock.Anything)
+}
+
+func TestPurgeEphemeralMessagesNone(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+
+	err := or.purgeEphemeralMessages(or.ctx, "ns1")
+	assert.NoError(t, err)
+	or.mdi.AssertNotCalled(t, "GetSubscriptions", mock.Anything, mock.Anything)
+}
+
+func TestPurgeEphemeralMessagesRetainsUndelivered(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOffset", mock.Anything, fftypes.OffsetTypeSubscription, mock.Anything).Return(&fftypes.Offset{Current: 10}, nil)
+	mockEphemeralPurge(or)
+
+	err := or.purgeEphemeralMessages(or.ctx, "ns1")
+	assert.NoError(t, err)
+	or.mdi.AssertNotCalled(t, "RunAsGroup", mock.Anything, mock.Anything)
+	or.mdi.AssertNotCalled(t, "DeleteMessage", mock.Anything, mock.Anything)
+}
+
+func TestPurgeEphemeralMessagesRetainsNoOffset(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOffset", mock.Anything, fftypes.OffsetTypeSubscription, mock.Anything).Return(nil, nil)
+	mockEphemeralPurge(or)
+
+	err := or.purgeEphemeralMessages(or.ctx, "ns1")
+	assert.NoError(t, err)
+	or.mdi.AssertNotCalled(t, "DeleteMessage", mock.Anything, mock.Anything)
+}
+
+func TestPurgeEphemeralMessagesRetainsSharedDataAndBatch(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetMessagesForData", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.Message{{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, matchFilterContaining("batch ==")).Return([]*fftypes.Message{{Header: fftypes.MessageHeader{ID: fftypes.NewUUID()}}}, nil, nil)
+	mockEphemeralPurge(or)
+
+	err := or.purgeEphemeralMessages(or.ctx, "ns1")
+	assert.NoError(t, err)
+	or.mdi.AssertCalled(t, "DeleteMessage", mock.Anything, mock.Anything)
+	or.mdi.AssertNotCalled(t, "DeleteData", mock.Anything, mock.Anything)
+	or.mdi.AssertNotCalled(t, "DeleteBatch", mock.Anything, mock.Anything)
+}
+
+func TestPurgeEphemeralMessagesUnbatched(t *testing.T) {
+	or := newTestOrchestrator()
+	ept := mockEphemeralPurge(or)
+	ept.msg.BatchID = nil
+	ept.msg.Data = nil
+
+	err := or.purgeEphemeralMessages(or.ctx, "ns1")
+	assert.NoError(t, err)
+	or.mdi.AssertCalled(t, "DeleteMessage", mock.Anything, mock.Anything)
+	or.mdi.AssertNotCalled(t, "DeleteBatch", mock.Anything, mock.Anything)
+}
+
+func TestPurgeEphemeralMessagesBatchNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetBatchByID", mock.Anything, mock.Anything).Return(nil, nil)
+	mockEphemeralPurge(or)
+
+	err := or.purgeEphemeralMessages(or.ctx, "ns1")
+	assert.NoError(t, err)
+	or.mdi.AssertNotCalled(t, "DeletePin", mock.Anything, mock.Anything)
+	or.mdi.AssertNotCalled(t, "DeleteBatch", mock.Anything, mock.Anything)
+}
+
+func TestPurgeEphemeralMessagesBatchWithoutTX(t *testing.T) {
+	or := newTestOrchestrator()
+	ept := mockEphemeralPurge(or)
+	ept.batch.Payload.TX.ID = nil
+
+	err := or.purgeEphemeralMessages(or.ctx, "ns1")
+	assert.NoError(t, err)
+	or.mdi.AssertNotCalled(t, "GetOperations", mock.Anything, mock.Anything)
+	or.mdi.AssertCalled(t, "DeleteBatch", mock.Anything, mock.Anything)
+}
+
+func TestPurgeEphemeralMessagesFailures(t *testing.T) {
+	failures := []struct {
+		method string
+		args   []interface{}
+		ret    []interface{}
+	}{
+		{"GetMessages", []interface{}{mock.Anything, matchFilterContaining("ephemeral")}, []interface{}{nil, nil, fmt.Errorf("pop")}},
+		{"GetSubscriptions", []interface{}{mock.Anything, mock.Anything}, []interface{}{nil, nil, fmt.Errorf("pop")}},
+		{"GetOffset", []interface{}{mock.Anything, mock.Anything, mock.Anything}, []interface{}{nil, fmt.Errorf("pop")}},
+		{"GetEvents", []interface{}{mock.Anything, mock.Anything}, []interface{}{nil, nil, fmt.Errorf("pop")}},
+		{"DeleteEvent", []interface{}{mock.Anything, mock.Anything}, []interface{}{fmt.Errorf("pop")}},
+		{"GetBatchByID", []interface{}{mock.Anything, mock.Anything}, []interface{}{nil, fmt.Errorf("pop")}},
+		{"GetPins", []interface{}{mock.Anything, mock.Anything}, []interface{}{nil, nil, fmt.Errorf("pop")}},
+		{"DeleteMessage", []interface{}{mock.Anything, mock.Anything}, []interface{}{fmt.Errorf("pop")}},
+		{"GetMessagesForData", []interface{}{mock.Anything, mock.Anything, mock.Anything}, []interface{}{nil, nil, fmt.Errorf("pop")}},
+		{"GetDataByID", []interface{}{mock.Anything, mock.Anything, false}, []interface{}{nil, fmt.Errorf("pop")}},
+		{"GetBlobs", []interface{}{mock.Anything, mock.Anything}, []interface{}{nil, nil, fmt.Errorf("pop")}},
+		{"DeleteData", []interface{}{mock.Anything, mock.Anything}, []interface{}{fmt.Errorf("pop")}},
+		{"GetMessages", []interface{}{mock.Anything, matchFilterContaining("batch ==")}, []interface{}{nil, nil, fmt.Errorf("pop")}},
+		{"GetOperations", []interface{}{mock.Anything, mock.Anything}, []interface{}{nil, nil, fmt.Errorf("pop")}},
+		{"DeleteOperation", []interface{}{mock.Anything, mock.Anything}, []interface{}{fmt.Errorf("pop")}},
+		{"DeleteTransaction", []interface{}{mock.Anything, mock.Anything}, []interface{}{fmt.Errorf("pop")}},
+		{"DeleteBatch", []interface{}{mock.Anything, mock.Anything}, []interface{}{fmt.Errorf("pop")}},
+	}
+	for _, f := range failures {
+		or := newTestOrchestrator()
+		or.mdi.On(f.method, f.args...).Return(f.ret...)
+		mockEphemeralPurge(or)
+
+		err := or.purgeEphemeralMessages(or.ctx, "ns1")
+		assert.EqualError(t, err, "pop", f.method)
+	}
+}
+
+func TestEphemeralPurgeLoop(t *testing.T) {
+	or := newTestOrchestrator()
+	purged := make(chan struct{})
+	or.mdi.On("GetNamespaces", mock.Anything, mock.Anything).Return([]*fftypes.Namespace{{Name: "ns1"}, {Name: "ns2"}}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, matchFilterContaining("namespace == 'ns1'")).Return(nil, nil, fmt.Errorf("pop"))
+	or.mdi.On("GetMessages", mock.Anything, matchFilterContaining("namespace == 'ns2'")).Return([]*fftypes.Message{}, nil, nil).Run(func(args mock.Arguments) {
+		or.cancelCtx()
+		close(purged)
+	}).Once()
+
+	go or.ephemeralPurgeLoop(1 * time.Millisecond)
+	<-purged
+	<-or.ctx.Done()
+	or.mdi.AssertExpectations(t)
+}
+
+func TestPurgeAllEphemeralMessagesNamespacesFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetNamespaces", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	or.purgeAllEphemeralMessages(or.ctx)
+	or.mdi.AssertExpectations(t)
+}
+
+func TestStartEphemeralPurgeLoop(t *testing.T) {
+	or := newTestOrchestrator()
+	config.Set(config.MessageEphemeralPurgeInterval, "1h")
+	or.mbi.On("Start").Return(nil)
+	or.mba.On("Start").Return(nil)
+	or.mem.On("Start").Return(nil)
+	or.mbm.On("Start").Return(nil)
+	or.mpm.On("Start").Return(nil)
+	or.mti.On("Start").Return(nil)
+	err := or.Start()
+	assert.NoError(t, err)
+	or.cancelCtx()
+}
+
+func TestEphemeralPurgeIntervalNotDisabled(t *testing.T) {
+	config.Reset()
+	assert.Equal(t, time.Minute, ephemeralPurgeInterval(context.Background()))
+	config.Set(config.MessageEphemeralPurgeInterval, "0")
+	assert.Equal(t, defaultEphemeralPurgeInterval, ephemeralPurgeInterval(context.Background()))
+	config.Set(config.MessageEphemeralPurgeInterval, "1h")
+	assert.Equal(t, time.Hour, ephemeralPurgeInterval(context.Background()))
+}
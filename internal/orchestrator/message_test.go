@@ -18,10 +18,13 @@ package orchestrator
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestRequestReplyMissingGroup(t *testing.T) {
@@ -44,3 +47,86 @@ func TestRequestReply(t *testing.T) {
 	_, err := or.RequestReply(context.Background(), "ns1", input)
 	assert.NoError(t, err)
 }
+
+func TestMarkReadBroadcast(t *testing.T) {
+	or := newTestOrchestrator()
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, msgID).Return(&fftypes.Message{
+		Header: fftypes.MessageHeader{ID: msgID, Namespace: "ns1", Type: fftypes.MessageTypeBroadcast},
+	}, nil)
+	or.mdi.On("UpdateMessage", mock.Anything, msgID, mock.Anything).Return(nil)
+	err := or.MarkRead(context.Background(), "ns1", msgID.String())
+	assert.NoError(t, err)
+	or.mpm.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMarkReadPrivateReceipt(t *testing.T) {
+	or := newTestOrchestrator()
+	config.Set(config.PrivateMessagingReadReceipts, true)
+	defer config.Reset()
+	msgID := fftypes.NewUUID()
+	groupID := fftypes.NewRandB32()
+	or.mdi.On("GetMessageByID", mock.Anything, msgID).Return(&fftypes.Message{
+		Header: fftypes.MessageHeader{ID: msgID, Namespace: "ns1", Type: fftypes.MessageTypePrivate, Group: groupID},
+	}, nil)
+	or.mdi.On("UpdateMessage", mock.Anything, msgID, mock.Anything).Return(nil)
+	or.mpm.On("SendMessage", mock.Anything, "ns1", mock.MatchedBy(func(in *fftypes.MessageInOut) bool {
+		return in.Header.CID.Equals(msgID) && in.Header.Group.Equals(groupID) && in.Header.Tag == readReceiptTag
+	}), false).Return(&fftypes.Message{}, nil)
+	err := or.MarkRead(context.Background(), "ns1", msgID.String())
+	assert.NoError(t, err)
+	or.mpm.AssertExpectations(t)
+}
+
+func TestMarkReadPrivateNoReceipt(t *testing.T) {
+	or := newTestOrchestrator()
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, msgID).Return(&fftypes.Message{
+		Header: fftypes.MessageHeader{ID: msgID, Namespace: "ns1", Type: fftypes.MessageTypePrivate},
+	}, nil)
+	or.mdi.On("UpdateMessage", mock.Anything, msgID, mock.Anything).Return(nil)
+	err := or.MarkRead(context.Background(), "ns1", msgID.String())
+	assert.NoError(t, err)
+	or.mpm.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMarkReadAlreadyRead(t *testing.T) {
+	or := newTestOrchestrator()
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, msgID).Return(&fftypes.Message{
+		Header: fftypes.MessageHeader{ID: msgID, Namespace: "ns1"},
+		ReadAt: fftypes.Now(),
+	}, nil)
+	err := or.MarkRead(context.Background(), "ns1", msgID.String())
+	assert.NoError(t, err)
+	or.mdi.AssertNotCalled(t, "UpdateMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMarkReadWrongNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, msgID).Return(&fftypes.Message{
+		Header: fftypes.MessageHeader{ID: msgID, Namespace: "ns2"},
+	}, nil)
+	err := or.MarkRead(context.Background(), "ns1", msgID.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestMarkReadNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, msgID).Return(nil, nil)
+	err := or.MarkRead(context.Background(), "ns1", msgID.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestMarkReadUpdateFail(t *testing.T) {
+	or := newTestOrchestrator()
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, msgID).Return(&fftypes.Message{
+		Header: fftypes.MessageHeader{ID: msgID, Namespace: "ns1"},
+	}, nil)
+	or.mdi.On("UpdateMessage", mock.Anything, msgID, mock.Anything).Return(fmt.Errorf("pop"))
+	err := or.MarkRead(context.Background(), "ns1", msgID.String())
+	assert.EqualError(t, err, "pop")
+}
@@ -0,0 +1,148 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/blockchain/bifactory"
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/dataexchange/dxfactory"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/internal/nsplugins"
+	"github.com/hyperledger/firefly/internal/publicstorage/psfactory"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/dataexchange"
+	"github.com/hyperledger/firefly/pkg/publicstorage"
+)
+
+func (or *orchestrator) initNamespacePlugins(ctx context.Context) (err error) {
+
+	// All plugins are tracked by type, so namespaces sharing a type share the same instance
+	if or.blockchains == nil {
+		or.blockchains = make(map[string]blockchain.Plugin)
+	}
+	or.blockchains[or.blockchain.Name()] = or.blockchain
+	if or.dataexchanges == nil {
+		or.dataexchanges = make(map[string]dataexchange.Plugin)
+	}
+	or.dataexchanges[or.dataexchange.Name()] = or.dataexchange
+	if or.publicstorages == nil {
+		or.publicstorages = make(map[string]publicstorage.Plugin)
+	}
+	or.publicstorages[or.publicstorage.Name()] = or.publicstorage
+
+	or.nsPlugins = make(nsplugins.Overrides)
+	for _, nsObject := range config.GetObjectArray(config.NamespacesPredefined) {
+		plugins, ok := nsObject.GetObjectOk("plugins")
+		if !ok {
+			continue
+		}
+		name := nsObject.GetString("name")
+		np := &nsplugins.Plugins{}
+		if biType := plugins.GetString("blockchain"); biType != "" {
+			if np.Blockchain, err = or.getBlockchainPlugin(ctx, biType); err != nil {
+				return err
+			}
+		}
+		if dxType := plugins.GetString("dataexchange"); dxType != "" {
+			if np.DataExchange, err = or.getDataExchangePlugin(ctx, dxType); err != nil {
+				return err
+			}
+		}
+		if psType := plugins.GetString("publicstorage"); psType != "" {
+			if np.PublicStorage, err = or.getPublicStoragePlugin(ctx, psType); err != nil {
+				return err
+			}
+		}
+		log.L(ctx).Infof("Namespace '%s' plugin overrides: blockchain=%s dataexchange=%s publicstorage=%s", name,
+			plugins.GetString("blockchain"), plugins.GetString("dataexchange"), plugins.GetString("publicstorage"))
+		or.nsPlugins[name] = np
+	}
+	return nil
+}
+
+func (or *orchestrator) getBlockchainPlugin(ctx context.Context, biType string) (bi blockchain.Plugin, err error) {
+	if bi, ok := or.blockchains[biType]; ok {
+		return bi, nil
+	}
+	if bi, err = bifactory.GetPlugin(ctx, biType); err != nil {
+		return nil, err
+	}
+	if err = bi.Init(ctx, blockchainConfig.SubPrefix(bi.Name()), &or.bc); err != nil {
+		return nil, err
+	}
+	or.blockchains[biType] = bi
+	return bi, nil
+}
+
+func (or *orchestrator) getDataExchangePlugin(ctx context.Context, dxType string) (dx dataexchange.Plugin, err error) {
+	if dx, ok := or.dataexchanges[dxType]; ok {
+		return dx, nil
+	}
+	if dx, err = dxfactory.GetPlugin(ctx, dxType); err != nil {
+		return nil, err
+	}
+	if err = dx.Init(ctx, dataexchangeConfig.SubPrefix(dx.Name()), &or.bc); err != nil {
+		return nil, err
+	}
+	or.dataexchanges[dxType] = dx
+	return dx, nil
+}
+
+func (or *orchestrator) getPublicStoragePlugin(ctx context.Context, psType string) (ps publicstorage.Plugin, err error) {
+	if ps, ok := or.publicstorages[psType]; ok {
+		return ps, nil
+	}
+	if ps, err = psfactory.GetPlugin(ctx, psType); err != nil {
+		return nil, err
+	}
+	if err = ps.Init(ctx, publicstorageConfig.SubPrefix(ps.Name()), or); err != nil {
+		return nil, err
+	}
+	or.publicstorages[psType] = ps
+	return ps, nil
+}
+
+func (or *orchestrator) startNamespacePlugins() (err error) {
+	// The global plugins are started by their owning components
+	for _, bi := range or.blockchains {
+		if bi != or.blockchain {
+			if err = bi.Start(); err != nil {
+				return err
+			}
+		}
+	}
+	for _, dx := range or.dataexchanges {
+		if dx != or.dataexchange {
+			if err = dx.Start(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dataexchangeFor returns the data exchange plugin for a namespace, falling back to the global plugin
+func (or *orchestrator) dataexchangeFor(ns string) dataexchange.Plugin {
+	return or.nsPlugins.DataExchange(ns, or.dataexchange)
+}
+
+// publicstorageFor returns the public storage plugin for a namespace, falling back to the global plugin
+func (or *orchestrator) publicstorageFor(ns string) publicstorage.Plugin {
+	return or.nsPlugins.PublicStorage(ns, or.publicstorage)
+}
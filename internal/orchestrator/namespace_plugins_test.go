@@ -0,0 +1,131 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/blockchain/bifactory"
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/publicstoragemocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/dataexchange"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/publicstorage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespacePluginResolution(t *testing.T) {
+	or := newTestOrchestrator()
+	mbi2 := &blockchainmocks.Plugin{}
+	mdx2 := &dataexchangemocks.Plugin{}
+	mps2 := &publicstoragemocks.Plugin{}
+	or.blockchains = map[string]blockchain.Plugin{"mock-bi2": mbi2}
+	or.dataexchanges = map[string]dataexchange.Plugin{"mock-dx2": mdx2}
+	or.publicstorages = map[string]publicstorage.Plugin{"mock-ps2": mps2}
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "default"},
+		{"name": "ns1", "plugins": fftypes.JSONObject{
+			"blockchain":    "mock-bi2",
+			"dataexchange":  "mock-dx2",
+			"publicstorage": "mock-ps2",
+		}},
+		{"name": "ns2", "plugins": fftypes.JSONObject{
+			"blockchain": "mock-bi",
+		}},
+	})
+
+	err := or.initNamespacePlugins(or.ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, mbi2, or.nsPlugins.Blockchain("ns1", or.blockchain))
+	assert.Equal(t, mdx2, or.dataexchangeFor("ns1"))
+	assert.Equal(t, mps2, or.publicstorageFor("ns1"))
+
+	assert.Equal(t, or.mbi, or.nsPlugins.Blockchain("ns2", or.blockchain))
+	assert.Equal(t, or.mdx, or.dataexchangeFor("ns2"))
+	assert.Equal(t, or.mps, or.publicstorageFor("ns2"))
+
+	assert.Equal(t, or.mbi, or.nsPlugins.Blockchain("default", or.blockchain))
+	assert.Equal(t, or.mdx, or.dataexchangeFor("default"))
+	assert.Equal(t, or.mps, or.publicstorageFor("default"))
+
+	mbi2.On("Start").Return(nil)
+	mdx2.On("Start").Return(nil)
+	err = or.startNamespacePlugins()
+	assert.NoError(t, err)
+	mbi2.AssertExpectations(t)
+	mdx2.AssertExpectations(t)
+}
+
+func TestNamespacePluginsBadBlockchain(t *testing.T) {
+	or := newTestOrchestrator()
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "ns1", "plugins": fftypes.JSONObject{"blockchain": "wrong"}},
+	})
+	err := or.initNamespacePlugins(or.ctx)
+	assert.Regexp(t, "FF10110.*wrong", err)
+}
+
+func TestNamespacePluginsBadDataExchange(t *testing.T) {
+	or := newTestOrchestrator()
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "ns1", "plugins": fftypes.JSONObject{"dataexchange": "wrong"}},
+	})
+	err := or.initNamespacePlugins(or.ctx)
+	assert.Regexp(t, "FF10213.*wrong", err)
+}
+
+func TestNamespacePluginsBadPublicStorage(t *testing.T) {
+	or := newTestOrchestrator()
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "ns1", "plugins": fftypes.JSONObject{"publicstorage": "wrong"}},
+	})
+	err := or.initNamespacePlugins(or.ctx)
+	assert.Regexp(t, "FF10134.*wrong", err)
+}
+
+func TestNamespacePluginsInitFail(t *testing.T) {
+	or := newTestOrchestrator()
+	bifactory.InitPrefix(blockchainConfig)
+	config.Set(config.NamespacesPredefined, fftypes.JSONObjectArray{
+		{"name": "ns1", "plugins": fftypes.JSONObject{"blockchain": "ethereum"}},
+	})
+	err := or.initNamespacePlugins(or.ctx)
+	assert.Regexp(t, "FF10138", err)
+}
+
+func TestStartNamespacePluginsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	mbi2 := &blockchainmocks.Plugin{}
+	mdx2 := &dataexchangemocks.Plugin{}
+	mbi2.On("Start").Return(nil)
+	mdx2.On("Start").Return(fmt.Errorf("pop"))
+	or.blockchains = map[string]blockchain.Plugin{"mock-bi2": mbi2}
+	or.dataexchanges = map[string]dataexchange.Plugin{"mock-dx2": mdx2}
+	err := or.startNamespacePlugins()
+	assert.EqualError(t, err, "pop")
+
+	mbi3 := &blockchainmocks.Plugin{}
+	mbi3.On("Start").Return(fmt.Errorf("pop"))
+	or.blockchains = map[string]blockchain.Plugin{"mock-bi3": mbi3}
+	err = or.startNamespacePlugins()
+	assert.EqualError(t, err, "pop")
+}
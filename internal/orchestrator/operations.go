@@ -0,0 +1,77 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+func (or *orchestrator) DeleteOperation(ctx context.Context, ns, id string) error {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return err
+	}
+	op, err := or.database.GetOperationByID(ctx, u)
+	if err != nil {
+		return err
+	}
+	if op == nil || op.Namespace != ns || op.DeletedAt != nil {
+		return i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	return or.database.SoftDeleteOperation(ctx, u)
+}
+
+// GetOperationDeliveryStatus asks data exchange whether the recipient has acknowledged the message sent by a batch send operation
+func (or *orchestrator) GetOperationDeliveryStatus(ctx context.Context, ns, id string) (*fftypes.MessageDeliveryStatus, error) {
+	u, err := or.verifyIDAndNamespace(ctx, ns, id)
+	if err != nil {
+		return nil, err
+	}
+	op, err := or.database.GetOperationByID(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil || op.Namespace != ns {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	if op.Type != fftypes.OpTypeDataExchangeBatchSend || op.BackendID == "" {
+		return nil, i18n.NewError(ctx, i18n.MsgOperationNotDXMessageSend, op.ID)
+	}
+	status, err := or.dataexchange.GetMessageStatus(ctx, op.BackendID)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	return status, nil
+}
+
+func (or *orchestrator) PurgeOperations(ctx context.Context, olderThan string) error {
+	age, err := fftypes.ParseDurationString(olderThan, time.Millisecond)
+	if err != nil {
+		return err
+	}
+	cutoff := fftypes.Now().Add(-time.Duration(age))
+	log.L(ctx).Infof("Purging operations soft deleted before %s", cutoff)
+	return or.database.PurgeOperations(ctx, cutoff)
+}
@@ -0,0 +1,158 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteOperationOk(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetOperationByID", mock.Anything, u).Return(&fftypes.Operation{ID: u, Namespace: "ns1"}, nil)
+	or.mdi.On("SoftDeleteOperation", mock.Anything, u).Return(nil)
+	err := or.DeleteOperation(or.ctx, "ns1", u.String())
+	assert.NoError(t, err)
+	or.mdi.AssertExpectations(t)
+}
+
+func TestDeleteOperationBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	err := or.DeleteOperation(or.ctx, "ns1", "!bad")
+	assert.Regexp(t, "FF10142", err)
+}
+
+func TestDeleteOperationGetFail(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetOperationByID", mock.Anything, u).Return(nil, fmt.Errorf("pop"))
+	err := or.DeleteOperation(or.ctx, "ns1", u.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteOperationWrongNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetOperationByID", mock.Anything, u).Return(&fftypes.Operation{ID: u, Namespace: "ns2"}, nil)
+	err := or.DeleteOperation(or.ctx, "ns1", u.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDeleteOperationAlreadyDeleted(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetOperationByID", mock.Anything, u).Return(&fftypes.Operation{ID: u, Namespace: "ns1", DeletedAt: fftypes.Now()}, nil)
+	err := or.DeleteOperation(or.ctx, "ns1", u.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestPurgeOperationsOk(t *testing.T) {
+	or := newTestOrchestrator()
+	before := fftypes.Now()
+	or.mdi.On("PurgeOperations", mock.Anything, mock.MatchedBy(func(cutoff *fftypes.FFTime) bool {
+		return before.Sub(cutoff) > 29*24*time.Hour && before.Sub(cutoff) <= 30*24*time.Hour
+	})).Return(nil)
+	err := or.PurgeOperations(or.ctx, "30d")
+	assert.NoError(t, err)
+	or.mdi.AssertExpectations(t)
+}
+
+func TestPurgeOperationsBadDuration(t *testing.T) {
+	or := newTestOrchestrator()
+	err := or.PurgeOperations(or.ctx, "!bad")
+	assert.Regexp(t, "FF10167", err)
+}
+
+func TestGetOperationDeliveryStatusOk(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetOperationByID", mock.Anything, u).Return(&fftypes.Operation{
+		ID:        u,
+		Namespace: "ns1",
+		Type:      fftypes.OpTypeDataExchangeBatchSend,
+		BackendID: "tracking1",
+	}, nil)
+	or.mdx.On("GetMessageStatus", mock.Anything, "tracking1").Return(&fftypes.MessageDeliveryStatus{
+		Status: fftypes.OpStatusSucceeded,
+	}, nil)
+	status, err := or.GetOperationDeliveryStatus(or.ctx, "ns1", u.String())
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.OpStatusSucceeded, status.Status)
+	or.mdi.AssertExpectations(t)
+	or.mdx.AssertExpectations(t)
+}
+
+func TestGetOperationDeliveryStatusBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	_, err := or.GetOperationDeliveryStatus(or.ctx, "ns1", "!uuid")
+	assert.Regexp(t, "FF10142", err)
+}
+
+func TestGetOperationDeliveryStatusLookupFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperationByID", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetOperationDeliveryStatus(or.ctx, "ns1", fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetOperationDeliveryStatusWrongNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperationByID", mock.Anything, mock.Anything).Return(&fftypes.Operation{Namespace: "ns2"}, nil)
+	_, err := or.GetOperationDeliveryStatus(or.ctx, "ns1", fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetOperationDeliveryStatusNotMessageSend(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperationByID", mock.Anything, mock.Anything).Return(&fftypes.Operation{
+		Namespace: "ns1",
+		Type:      fftypes.OpTypeDataExchangeBlobSend,
+		BackendID: "tracking1",
+	}, nil)
+	_, err := or.GetOperationDeliveryStatus(or.ctx, "ns1", fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10324", err)
+}
+
+func TestGetOperationDeliveryStatusDXFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperationByID", mock.Anything, mock.Anything).Return(&fftypes.Operation{
+		Namespace: "ns1",
+		Type:      fftypes.OpTypeDataExchangeBatchSend,
+		BackendID: "tracking1",
+	}, nil)
+	or.mdx.On("GetMessageStatus", mock.Anything, "tracking1").Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetOperationDeliveryStatus(or.ctx, "ns1", fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetOperationDeliveryStatusUnknownToDX(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperationByID", mock.Anything, mock.Anything).Return(&fftypes.Operation{
+		Namespace: "ns1",
+		Type:      fftypes.OpTypeDataExchangeBatchSend,
+		BackendID: "tracking1",
+	}, nil)
+	or.mdx.On("GetMessageStatus", mock.Anything, "tracking1").Return(nil, nil)
+	_, err := or.GetOperationDeliveryStatus(or.ctx, "ns1", fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/firefly/internal/blockchain/bifactory"
 	"github.com/hyperledger/firefly/internal/broadcast"
 	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/crypto/cryptofactory"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/database/difactory"
 	"github.com/hyperledger/firefly/internal/dataexchange/dxfactory"
@@ -34,12 +35,14 @@ import (
 	"github.com/hyperledger/firefly/internal/identity/iifactory"
 	"github.com/hyperledger/firefly/internal/log"
 	"github.com/hyperledger/firefly/internal/networkmap"
+	"github.com/hyperledger/firefly/internal/nsplugins"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/internal/publicstorage/psfactory"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/internal/syshandlers"
 	"github.com/hyperledger/firefly/internal/tokens/tifactory"
 	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/crypto"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
 	"github.com/hyperledger/firefly/pkg/fftypes"
@@ -50,6 +53,7 @@ import (
 
 var (
 	blockchainConfig    = config.NewPluginConfig("blockchain")
+	cryptoConfig        = config.NewPluginConfig("crypto")
 	databaseConfig      = config.NewPluginConfig("database")
 	identityConfig      = config.NewPluginConfig("identity")
 	publicstorageConfig = config.NewPluginConfig("publicstorage")
@@ -68,17 +72,25 @@ type Orchestrator interface {
 	NetworkMap() networkmap.Manager
 	Data() data.Manager
 	Assets() assets.Manager
+	Identity() identity.Plugin
 	IsPreInit() bool
 
 	// Status
 	GetStatus(ctx context.Context) (*fftypes.NodeStatus, error)
+	GetBlockchainStatus(ctx context.Context) (*fftypes.BlockchainStatus, error)
 
 	// Subscription management
 	GetSubscriptions(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Subscription, *database.FilterResult, error)
 	GetSubscriptionByID(ctx context.Context, ns, id string) (*fftypes.Subscription, error)
 	CreateSubscription(ctx context.Context, ns string, subDef *fftypes.Subscription) (*fftypes.Subscription, error)
 	CreateUpdateSubscription(ctx context.Context, ns string, subDef *fftypes.Subscription) (*fftypes.Subscription, error)
+	UpdateSubscription(ctx context.Context, ns, id string, update *fftypes.SubscriptionUpdate) (*fftypes.Subscription, error)
 	DeleteSubscription(ctx context.Context, ns, id string) error
+	PauseSubscription(ctx context.Context, ns, id string) error
+	ResumeSubscription(ctx context.Context, ns, id string) error
+	RewindSubscription(ctx context.Context, ns, id string, fromSequence int64) error
+	GetSubscriptionDeadLetterEvents(ctx context.Context, ns, id string, filter database.AndFilter) ([]*fftypes.DeadLetterEvent, *database.FilterResult, error)
+	RedeliverDeadLetterEvent(ctx context.Context, ns, id, eventID string) error
 
 	// Data Query
 	GetNamespace(ctx context.Context, ns string) (*fftypes.Namespace, error)
@@ -88,6 +100,7 @@ type Orchestrator interface {
 	GetTransactions(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Transaction, *database.FilterResult, error)
 	GetMessageByID(ctx context.Context, ns, id string, withValues bool) (*fftypes.MessageInOut, error)
 	GetMessages(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Message, *database.FilterResult, error)
+	GetMessagesByIDs(ctx context.Context, ns string, ids []*fftypes.UUID) ([]*fftypes.Message, error)
 	GetMessageTransaction(ctx context.Context, ns, id string) (*fftypes.Transaction, error)
 	GetMessageOperations(ctx context.Context, ns, id string) ([]*fftypes.Operation, *database.FilterResult, error)
 	GetMessageEvents(ctx context.Context, ns, id string, filter database.AndFilter) ([]*fftypes.Event, *database.FilterResult, error)
@@ -96,15 +109,31 @@ type Orchestrator interface {
 	GetBatchByID(ctx context.Context, ns, id string) (*fftypes.Batch, error)
 	GetBatches(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Batch, *database.FilterResult, error)
 	GetDataByID(ctx context.Context, ns, id string) (*fftypes.Data, error)
+	GetDataVersionHistory(ctx context.Context, ns, id string) ([]*fftypes.Data, error)
 	GetData(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Data, *database.FilterResult, error)
 	GetDatatypeByID(ctx context.Context, ns, id string) (*fftypes.Datatype, error)
 	GetDatatypeByName(ctx context.Context, ns, name, version string) (*fftypes.Datatype, error)
 	GetDatatypes(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Datatype, *database.FilterResult, error)
+	GetGroups(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Group, *database.FilterResult, error)
 	GetOperationByID(ctx context.Context, ns, id string) (*fftypes.Operation, error)
+	GetOperationHistory(ctx context.Context, ns, id string) ([]*fftypes.OperationTransition, error)
 	GetOperations(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Operation, *database.FilterResult, error)
 	GetEventByID(ctx context.Context, ns, id string) (*fftypes.Event, error)
 	GetEvents(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Event, *database.FilterResult, error)
 
+	// Public storage
+	GetPublicStorageItems(ctx context.Context, ns string, since *fftypes.FFTime) ([]publicstorage.StoredItem, error)
+	GCPublicStorage(ctx context.Context, ns string, dryRun bool) (*fftypes.GCResult, error)
+
+	// Data retention
+	PurgeExpiredData(ctx context.Context, ns string, dryRun bool) (*fftypes.PurgeResult, error)
+	DeleteMessages(ctx context.Context, ns string, filter database.AndFilter) (int64, error)
+
+	// Operation management
+	DeleteOperation(ctx context.Context, ns, id string) error
+	GetOperationDeliveryStatus(ctx context.Context, ns, id string) (*fftypes.MessageDeliveryStatus, error)
+	PurgeOperations(ctx context.Context, olderThan string) error
+
 	// Config Management
 	GetConfig(ctx context.Context) fftypes.JSONObject
 	GetConfigRecord(ctx context.Context, key string) (*fftypes.ConfigRecord, error)
@@ -115,30 +144,36 @@ type Orchestrator interface {
 
 	// Message Routing
 	RequestReply(ctx context.Context, ns string, msg *fftypes.MessageInOut) (reply *fftypes.MessageInOut, err error)
+	MarkRead(ctx context.Context, ns, msgID string) error
 }
 
 type orchestrator struct {
-	ctx           context.Context
-	cancelCtx     context.CancelFunc
-	started       bool
-	database      database.Plugin
-	blockchain    blockchain.Plugin
-	identity      identity.Plugin
-	publicstorage publicstorage.Plugin
-	dataexchange  dataexchange.Plugin
-	events        events.EventManager
-	networkmap    networkmap.Manager
-	batch         batch.Manager
-	broadcast     broadcast.Manager
-	messaging     privatemessaging.Manager
-	syshandlers   syshandlers.SystemHandlers
-	data          data.Manager
-	syncasync     syncasync.Bridge
-	batchpin      batchpin.Submitter
-	assets        assets.Manager
-	tokens        map[string]tokens.Plugin
-	bc            boundCallbacks
-	preInitMode   bool
+	ctx            context.Context
+	cancelCtx      context.CancelFunc
+	started        bool
+	database       database.Plugin
+	blockchain     blockchain.Plugin
+	identity       identity.Plugin
+	crypto         crypto.Plugin
+	publicstorage  publicstorage.Plugin
+	dataexchange   dataexchange.Plugin
+	events         events.EventManager
+	networkmap     networkmap.Manager
+	batch          batch.Manager
+	broadcast      broadcast.Manager
+	messaging      privatemessaging.Manager
+	syshandlers    syshandlers.SystemHandlers
+	data           data.Manager
+	syncasync      syncasync.Bridge
+	batchpin       batchpin.Submitter
+	assets         assets.Manager
+	tokens         map[string]tokens.Plugin
+	blockchains    map[string]blockchain.Plugin
+	dataexchanges  map[string]dataexchange.Plugin
+	publicstorages map[string]publicstorage.Plugin
+	nsPlugins      nsplugins.Overrides
+	bc             boundCallbacks
+	preInitMode    bool
 }
 
 func NewOrchestrator() Orchestrator {
@@ -146,6 +181,7 @@ func NewOrchestrator() Orchestrator {
 
 	// Initialize the config on all the factories
 	bifactory.InitPrefix(blockchainConfig)
+	cryptofactory.InitPrefix(cryptoConfig)
 	difactory.InitPrefix(databaseConfig)
 	psfactory.InitPrefix(publicstorageConfig)
 	dxfactory.InitPrefix(dataexchangeConfig)
@@ -193,6 +229,9 @@ func (or *orchestrator) Start() error {
 	if err == nil {
 		err = or.messaging.Start()
 	}
+	if err == nil {
+		err = or.startNamespacePlugins()
+	}
 	if err == nil {
 		for _, el := range or.tokens {
 			if err = el.Start(); err != nil {
@@ -200,6 +239,12 @@ func (or *orchestrator) Start() error {
 			}
 		}
 	}
+	if err == nil {
+		if interval := config.GetDuration(config.DataPurgeInterval); interval > 0 {
+			go or.dataPurgeLoop(interval)
+		}
+		go or.ephemeralPurgeLoop(ephemeralPurgeInterval(or.ctx))
+	}
 	or.started = true
 	return err
 }
@@ -247,6 +292,10 @@ func (or *orchestrator) Assets() assets.Manager {
 	return or.assets
 }
 
+func (or *orchestrator) Identity() identity.Plugin {
+	return or.identity
+}
+
 func (or *orchestrator) initDatabaseCheckPreinit(ctx context.Context) (err error) {
 
 	if or.database == nil {
@@ -290,6 +339,16 @@ func (or *orchestrator) initPlugins(ctx context.Context) (err error) {
 		return err
 	}
 
+	if or.crypto == nil {
+		cpType := config.GetString(config.CryptoType)
+		if or.crypto, err = cryptofactory.GetPlugin(ctx, cpType); err != nil {
+			return err
+		}
+	}
+	if err = or.crypto.Init(ctx, cryptoConfig.SubPrefix(or.crypto.Name())); err != nil {
+		return err
+	}
+
 	if or.blockchain == nil {
 		biType := config.GetString(config.BlockchainType)
 		if or.blockchain, err = bifactory.GetPlugin(ctx, biType); err != nil {
@@ -320,6 +379,10 @@ func (or *orchestrator) initPlugins(ctx context.Context) (err error) {
 		return err
 	}
 
+	if err = or.initNamespacePlugins(ctx); err != nil {
+		return err
+	}
+
 	if or.tokens == nil {
 		or.tokens = make(map[string]tokens.Plugin)
 		for i := 0; i < tokensConfig.ArraySize(); i++ {
@@ -374,19 +437,23 @@ func (or *orchestrator) initComponents(ctx context.Context) (err error) {
 		if err != nil {
 			return err
 		}
+		if config.GetInt(config.DebugPort) >= 0 {
+			// Batch assembly is logged when the debug listener is enabled
+			or.batch.RegisterInspector(batch.NewLoggingInspector(ctx))
+		}
 	}
 
 	or.syncasync = syncasync.NewSyncAsyncBridge(ctx, or.database, or.data)
-	or.batchpin = batchpin.NewBatchPinSubmitter(or.database, or.identity, or.blockchain)
+	or.batchpin = batchpin.NewBatchPinSubmitter(or.database, or.identity, or.blockchain, or.nsPlugins)
 
 	if or.messaging == nil {
-		if or.messaging, err = privatemessaging.NewPrivateMessaging(ctx, or.database, or.identity, or.dataexchange, or.blockchain, or.batch, or.data, or.syncasync, or.batchpin); err != nil {
+		if or.messaging, err = privatemessaging.NewPrivateMessaging(ctx, or.database, or.identity, or.dataexchange, or.blockchain, or.nsPlugins, or.batch, or.data, or.syncasync, or.batchpin); err != nil {
 			return err
 		}
 	}
 
 	if or.broadcast == nil {
-		if or.broadcast, err = broadcast.NewBroadcastManager(ctx, or.database, or.identity, or.data, or.blockchain, or.dataexchange, or.publicstorage, or.batch, or.syncasync, or.batchpin); err != nil {
+		if or.broadcast, err = broadcast.NewBroadcastManager(ctx, or.database, or.identity, or.data, or.blockchain, or.dataexchange, or.publicstorage, or.nsPlugins, or.batch, or.syncasync, or.batchpin); err != nil {
 			return err
 		}
 	}
@@ -401,14 +468,14 @@ func (or *orchestrator) initComponents(ctx context.Context) (err error) {
 	or.syshandlers = syshandlers.NewSystemHandlers(or.database, or.identity, or.dataexchange, or.data, or.broadcast, or.messaging, or.assets)
 
 	if or.events == nil {
-		or.events, err = events.NewEventManager(ctx, or.publicstorage, or.database, or.identity, or.syshandlers, or.data)
+		or.events, err = events.NewEventManager(ctx, or.publicstorage, or.nsPlugins, or.database, or.identity, or.syshandlers, or.data, or.messaging)
 		if err != nil {
 			return err
 		}
 	}
 
 	if or.networkmap == nil {
-		or.networkmap, err = networkmap.NewNetworkMap(ctx, or.database, or.broadcast, or.dataexchange, or.identity)
+		or.networkmap, err = networkmap.NewNetworkMap(ctx, or.database, or.broadcast, or.dataexchange, or.identity, or.crypto)
 		if err != nil {
 			return err
 		}
@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/batchmocks"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
 	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/cryptomocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
@@ -58,6 +59,7 @@ type testOrchestrator struct {
 	mpm *privatemessagingmocks.Manager
 	mbi *blockchainmocks.Plugin
 	mii *identitymocks.Plugin
+	mcp *cryptomocks.Plugin
 	mdx *dataexchangemocks.Plugin
 	mam *assetmocks.Manager
 	mti *tokenmocks.Plugin
@@ -81,6 +83,7 @@ func newTestOrchestrator() *testOrchestrator {
 		mpm: &privatemessagingmocks.Manager{},
 		mbi: &blockchainmocks.Plugin{},
 		mii: &identitymocks.Plugin{},
+		mcp: &cryptomocks.Plugin{},
 		mdx: &dataexchangemocks.Plugin{},
 		mam: &assetmocks.Manager{},
 		mti: &tokenmocks.Plugin{},
@@ -95,6 +98,7 @@ func newTestOrchestrator() *testOrchestrator {
 	tor.orchestrator.messaging = tor.mpm
 	tor.orchestrator.blockchain = tor.mbi
 	tor.orchestrator.identity = tor.mii
+	tor.orchestrator.crypto = tor.mcp
 	tor.orchestrator.dataexchange = tor.mdx
 	tor.orchestrator.assets = tor.mam
 	tor.orchestrator.tokens = map[string]tokens.Plugin{"token": tor.mti}
@@ -103,12 +107,18 @@ func newTestOrchestrator() *testOrchestrator {
 	tor.mps.On("Name").Return("mock-ps").Maybe()
 	tor.mbi.On("Name").Return("mock-bi").Maybe()
 	tor.mii.On("Name").Return("mock-ii").Maybe()
+	tor.mcp.On("Name").Return("mock-cp").Maybe()
 	tor.mdx.On("Name").Return("mock-dx").Maybe()
 	tor.mam.On("Name").Return("mock-am").Maybe()
 	tor.mti.On("Name").Return("mock-tk").Maybe()
 	return tor
 }
 
+func TestIdentity(t *testing.T) {
+	or := newTestOrchestrator()
+	assert.Equal(t, or.mii, or.Identity())
+}
+
 func TestNewOrchestrator(t *testing.T) {
 	or := NewOrchestrator()
 	assert.NotNil(t, or)
@@ -165,6 +175,29 @@ func TestBadIdentityInitFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestBadCryptoPlugin(t *testing.T) {
+	or := newTestOrchestrator()
+	config.Set(config.CryptoType, "wrong")
+	or.crypto = nil
+	or.mdi.On("GetConfigRecords", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.ConfigRecord{}, nil, nil)
+	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	err := or.Init(ctx, cancelCtx)
+	assert.Regexp(t, "FF10314.*wrong", err)
+}
+
+func TestCryptoInitFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetConfigRecords", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.ConfigRecord{}, nil, nil)
+	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	err := or.Init(ctx, cancelCtx)
+	assert.EqualError(t, err, "pop")
+}
+
 func TestBadBlockchainPlugin(t *testing.T) {
 	or := newTestOrchestrator()
 	config.Set(config.BlockchainType, "wrong")
@@ -172,6 +205,7 @@ func TestBadBlockchainPlugin(t *testing.T) {
 	or.mdi.On("GetConfigRecords", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.ConfigRecord{}, nil, nil)
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	err := or.Init(ctx, cancelCtx)
 	assert.Regexp(t, "FF10110.*wrong", err)
@@ -181,6 +215,7 @@ func TestBlockchaiInitFail(t *testing.T) {
 	or := newTestOrchestrator()
 	or.mdi.On("GetConfigRecords", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.ConfigRecord{}, nil, nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 	ctx, cancelCtx := context.WithCancel(context.Background())
@@ -192,6 +227,7 @@ func TestBlockchaiInitGetConfigRecordsFail(t *testing.T) {
 	or := newTestOrchestrator()
 	or.mdi.On("GetConfigRecords", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	ctx, cancelCtx := context.WithCancel(context.Background())
@@ -208,6 +244,7 @@ func TestBlockchaiInitMergeConfigRecordsFail(t *testing.T) {
 		},
 	}, nil, nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
@@ -224,6 +261,7 @@ func TestBadPublicStoragePlugin(t *testing.T) {
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	err := or.Init(ctx, cancelCtx)
@@ -236,6 +274,7 @@ func TestBadPublicStorageInitFail(t *testing.T) {
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	or.mps.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 	ctx, cancelCtx := context.WithCancel(context.Background())
@@ -251,6 +290,7 @@ func TestBadDataExchangePlugin(t *testing.T) {
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	or.mps.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	ctx, cancelCtx := context.WithCancel(context.Background())
@@ -264,6 +304,7 @@ func TestBadDataExchangeInitFail(t *testing.T) {
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	or.mps.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mdx.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
@@ -284,6 +325,7 @@ func TestBadTokensPlugin(t *testing.T) {
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	or.mps.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mdx.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -305,6 +347,7 @@ func TestBadTokensPluginNoConnector(t *testing.T) {
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	or.mps.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mdx.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -327,6 +370,7 @@ func TestBadTokensPluginNoName(t *testing.T) {
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	or.mps.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mdx.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -349,6 +393,7 @@ func TestBadTokensPluginNoType(t *testing.T) {
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	or.mps.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mdx.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -372,6 +417,7 @@ func TestGoodTokensPlugin(t *testing.T) {
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	or.mps.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mdx.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -415,6 +461,16 @@ func TestInitBatchComponentFail(t *testing.T) {
 	assert.Regexp(t, "FF10128", err)
 }
 
+func TestInitBatchComponentDebugInspector(t *testing.T) {
+	or := newTestOrchestrator()
+	config.Set(config.DebugPort, 6060)
+	defer config.Reset()
+	or.batch = nil
+	err := or.initComponents(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, or.batch)
+}
+
 func TestInitBroadcastComponentFail(t *testing.T) {
 	or := newTestOrchestrator()
 	or.database = nil
@@ -546,6 +602,7 @@ func TestInitOK(t *testing.T) {
 	or.mdi.On("GetConfigRecords", mock.Anything, mock.Anything, mock.Anything).Return([]*fftypes.ConfigRecord{}, nil, nil)
 	or.mdi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mii.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	or.mcp.On("Init", mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	or.mbi.On("VerifyIdentitySyntax", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	or.mps.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -0,0 +1,145 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/publicstorage"
+)
+
+func (or *orchestrator) GetPublicStorageItems(ctx context.Context, ns string, since *fftypes.FFTime) ([]publicstorage.StoredItem, error) {
+	ps := or.publicstorageFor(ns)
+	if !ps.Capabilities().SupportsList {
+		return nil, i18n.NewError(ctx, i18n.MsgStorageListNotSupported, ps.Name())
+	}
+	return ps.ListStoredItems(ctx, since)
+}
+
+// GCPublicStorage finds broadcast batches uploaded to the Public Storage that are known to have failed, and deletes
+// the uploaded payloads. A batch has failed when the blockchain transaction to pin it failed, and none of its messages
+// were confirmed. Only pins that failed longer ago than publicstorage.gc.minAge are considered, so that a batch which
+// is still being retried is not affected.
+func (or *orchestrator) GCPublicStorage(ctx context.Context, ns string, dryRun bool) (*fftypes.GCResult, error) {
+	if err := or.verifyNamespaceSyntax(ctx, ns); err != nil {
+		return nil, err
+	}
+	cutoff := fftypes.Now().Add(-config.GetDuration(config.PublicStorageGCMinAge))
+	pageSize := uint64(config.GetUint(config.PublicStorageGCReadPageSize))
+
+	result := &fftypes.GCResult{
+		DryRun:   dryRun,
+		Orphaned: []string{},
+		Deleted:  []string{},
+	}
+	ps := or.publicstorageFor(ns)
+	seen := make(map[fftypes.UUID]bool)
+	for skip := uint64(0); ; skip += pageSize {
+		fb := database.OperationQueryFactory.NewFilter(ctx)
+		ops, _, err := or.database.GetOperations(ctx, fb.And(
+			fb.Eq("namespace", ns),
+			fb.Eq("type", fftypes.OpTypeBlockchainBatchPin),
+			fb.Eq("status", fftypes.OpStatusFailed),
+			fb.Lt("updated", cutoff),
+		).Sort("created").Skip(skip).Limit(pageSize))
+		if err != nil {
+			return nil, err
+		}
+		batches, err := or.failedBatches(ctx, ns, ops)
+		if err != nil {
+			return nil, err
+		}
+		for _, batch := range batches {
+			// A batch pin can fail more than once, if it was resubmitted
+			if seen[*batch.ID] {
+				continue
+			}
+			seen[*batch.ID] = true
+			result.Orphaned = append(result.Orphaned, batch.PayloadRef)
+			if dryRun {
+				continue
+			}
+			log.L(ctx).Infof("Deleting orphaned upload %s of failed batch %s", batch.PayloadRef, batch.ID)
+			if err := ps.DeleteData(ctx, batch.PayloadRef); err != nil {
+				return nil, err
+			}
+			// Clear the reference, so the batch is not considered again
+			if err := or.database.UpdateBatch(ctx, batch.ID, database.BatchQueryFactory.NewUpdate(ctx).Set("payloadref", "")); err != nil {
+				return nil, err
+			}
+			result.Deleted = append(result.Deleted, batch.PayloadRef)
+		}
+		if uint64(len(ops)) < pageSize {
+			return result, nil
+		}
+	}
+}
+
+// failedBatches finds the uploaded broadcast batches pinned by a page of failed operations, excluding any
+// where a message was confirmed (such as after the pin was resubmitted)
+func (or *orchestrator) failedBatches(ctx context.Context, ns string, ops []*fftypes.Operation) ([]*fftypes.Batch, error) {
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	txIDs := make([]driver.Value, len(ops))
+	for i, op := range ops {
+		txIDs[i] = op.Transaction
+	}
+	fb := database.BatchQueryFactory.NewFilter(ctx)
+	batches, _, err := or.database.GetBatches(ctx, fb.And(
+		fb.Eq("namespace", ns),
+		fb.Eq("type", fftypes.MessageTypeBroadcast),
+		fb.Neq("payloadref", ""),
+		fb.In("tx.id", txIDs),
+	))
+	if err != nil || len(batches) == 0 {
+		return nil, err
+	}
+
+	batchIDs := make([]driver.Value, len(batches))
+	for i, batch := range batches {
+		batchIDs[i] = batch.ID
+	}
+	mfb := database.MessageQueryFactory.NewFilter(ctx)
+	confirmed, _, err := or.database.GetMessages(ctx, mfb.And(
+		mfb.In("batch", batchIDs),
+		mfb.Neq("confirmed", nil),
+	))
+	if err != nil {
+		return nil, err
+	}
+	confirmedBatches := make(map[fftypes.UUID]bool)
+	for _, msg := range confirmed {
+		confirmedBatches[*msg.BatchID] = true
+	}
+
+	failed := make([]*fftypes.Batch, 0, len(batches))
+	for _, batch := range batches {
+		if confirmedBatches[*batch.ID] {
+			log.L(ctx).Debugf("Retaining upload %s of batch %s, as it has confirmed messages", batch.PayloadRef, batch.ID)
+			continue
+		}
+		failed = append(failed, batch)
+	}
+	return failed, nil
+}
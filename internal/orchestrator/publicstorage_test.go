@@ -0,0 +1,178 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/publicstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetPublicStorageItems(t *testing.T) {
+	or := newTestOrchestrator()
+	since := fftypes.Now()
+	or.mps.On("Capabilities").Return(&publicstorage.Capabilities{SupportsList: true})
+	or.mps.On("ListStoredItems", mock.Anything, since).Return([]publicstorage.StoredItem{
+		{PayloadRef: "Qm12345", Size: 100},
+	}, nil)
+	items, err := or.GetPublicStorageItems(or.ctx, "ns1", since)
+	assert.NoError(t, err)
+	assert.Equal(t, "Qm12345", items[0].PayloadRef)
+}
+
+func TestGetPublicStorageItemsNotSupported(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mps.On("Capabilities").Return(&publicstorage.Capabilities{})
+	_, err := or.GetPublicStorageItems(or.ctx, "ns1", nil)
+	assert.Regexp(t, "FF10281.*mock-ps", err)
+}
+
+func TestGCPublicStorageDeletesFailedBatches(t *testing.T) {
+	or := newTestOrchestrator()
+	config.Set(config.PublicStorageGCReadPageSize, 2)
+	tx1, tx2, tx3 := fftypes.NewUUID(), fftypes.NewUUID(), fftypes.NewUUID()
+	failed := &fftypes.Batch{ID: fftypes.NewUUID(), PayloadRef: "QmFailed"}
+	resubmitted := &fftypes.Batch{ID: fftypes.NewUUID(), PayloadRef: "QmResubmitted"}
+	failedLater := &fftypes.Batch{ID: fftypes.NewUUID(), PayloadRef: "QmFailedLater"}
+	or.mdi.On("GetOperations", mock.Anything, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.HasPrefix(fi.String(), "( namespace == 'ns1' ) && ( type == 'blockchain_batch_pin' ) && ( status == 'Failed' ) && ( updated < ") &&
+			strings.HasSuffix(fi.String(), "sort=created limit=2")
+	})).Return([]*fftypes.Operation{{Transaction: tx1}, {Transaction: tx2}}, nil, nil)
+	or.mdi.On("GetOperations", mock.Anything, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.HasSuffix(fi.String(), "sort=created skip=2 limit=2")
+	})).Return([]*fftypes.Operation{{Transaction: tx3}}, nil, nil)
+	or.mdi.On("GetBatches", mock.Anything, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), fmt.Sprintf("tx.id IN ['%s','%s']", tx1, tx2))
+	})).Return([]*fftypes.Batch{failed, resubmitted}, nil, nil)
+	or.mdi.On("GetBatches", mock.Anything, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), fmt.Sprintf("tx.id IN ['%s']", tx3))
+	})).Return([]*fftypes.Batch{failedLater}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), fmt.Sprintf("batch IN ['%s','%s']", failed.ID, resubmitted.ID))
+	})).Return([]*fftypes.Message{{BatchID: resubmitted.ID}}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mps.On("DeleteData", mock.Anything, "QmFailed").Return(nil)
+	or.mps.On("DeleteData", mock.Anything, "QmFailedLater").Return(nil)
+	or.mdi.On("UpdateBatch", mock.Anything, failed.ID, mock.Anything).Return(nil)
+	or.mdi.On("UpdateBatch", mock.Anything, failedLater.ID, mock.Anything).Return(nil)
+
+	result, err := or.GCPublicStorage(or.ctx, "ns1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"QmFailed", "QmFailedLater"}, result.Orphaned)
+	assert.Equal(t, []string{"QmFailed", "QmFailedLater"}, result.Deleted)
+	or.mdi.AssertExpectations(t)
+	or.mps.AssertExpectations(t)
+	or.mps.AssertNotCalled(t, "DeleteData", mock.Anything, "QmResubmitted")
+}
+
+func TestGCPublicStorageDryRun(t *testing.T) {
+	or := newTestOrchestrator()
+	batch := &fftypes.Batch{ID: fftypes.NewUUID(), PayloadRef: "QmFailed"}
+	config.Set(config.PublicStorageGCReadPageSize, 1)
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{{Transaction: fftypes.NewUUID()}}, nil, nil).Twice()
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{}, nil, nil)
+	// The same batch is found for both failed pins of a resubmitted transaction
+	or.mdi.On("GetBatches", mock.Anything, mock.Anything).Return([]*fftypes.Batch{batch}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+
+	result, err := or.GCPublicStorage(or.ctx, "ns1", true)
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []string{"QmFailed"}, result.Orphaned)
+	assert.Empty(t, result.Deleted)
+	or.mps.AssertNotCalled(t, "DeleteData", mock.Anything, mock.Anything)
+}
+
+func TestGCPublicStorageNoBatches(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{{Transaction: fftypes.NewUUID()}}, nil, nil)
+	or.mdi.On("GetBatches", mock.Anything, mock.Anything).Return([]*fftypes.Batch{}, nil, nil)
+
+	result, err := or.GCPublicStorage(or.ctx, "ns1", false)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Orphaned)
+	or.mdi.AssertNotCalled(t, "GetMessages", mock.Anything, mock.Anything)
+}
+
+func TestGCPublicStorageBadNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	_, err := or.GCPublicStorage(or.ctx, "!wrong", false)
+	assert.Regexp(t, "FF10131", err)
+}
+
+func TestGCPublicStorageGetOperationsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := or.GCPublicStorage(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGCPublicStorageGetBatchesFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{{Transaction: fftypes.NewUUID()}}, nil, nil)
+	or.mdi.On("GetBatches", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := or.GCPublicStorage(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGCPublicStorageGetMessagesFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{{Transaction: fftypes.NewUUID()}}, nil, nil)
+	or.mdi.On("GetBatches", mock.Anything, mock.Anything).Return([]*fftypes.Batch{
+		{ID: fftypes.NewUUID(), PayloadRef: "QmFailed"},
+	}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := or.GCPublicStorage(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGCPublicStorageDeleteFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{{Transaction: fftypes.NewUUID()}}, nil, nil)
+	or.mdi.On("GetBatches", mock.Anything, mock.Anything).Return([]*fftypes.Batch{
+		{ID: fftypes.NewUUID(), PayloadRef: "QmFailed"},
+	}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mps.On("DeleteData", mock.Anything, "QmFailed").Return(fmt.Errorf("pop"))
+	_, err := or.GCPublicStorage(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGCPublicStorageUpdateBatchFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetOperations", mock.Anything, mock.Anything).Return([]*fftypes.Operation{{Transaction: fftypes.NewUUID()}}, nil, nil)
+	or.mdi.On("GetBatches", mock.Anything, mock.Anything).Return([]*fftypes.Batch{
+		{ID: fftypes.NewUUID(), PayloadRef: "QmFailed"},
+	}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, mock.Anything).Return([]*fftypes.Message{}, nil, nil)
+	or.mps.On("DeleteData", mock.Anything, "QmFailed").Return(nil)
+	or.mdi.On("UpdateBatch", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	_, err := or.GCPublicStorage(or.ctx, "ns1", false)
+	assert.EqualError(t, err, "pop")
+}
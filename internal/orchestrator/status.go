@@ -18,8 +18,11 @@ package orchestrator
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
@@ -36,6 +39,11 @@ func (or *orchestrator) GetStatus(ctx context.Context) (status *fftypes.NodeStat
 		Defaults: fftypes.NodeStatusDefaults{
 			Namespace: config.GetString(config.NamespacesDefault),
 		},
+		MessageVersions: fftypes.NodeStatusMessageVersions{
+			Current:      fftypes.MessageVersion,
+			MinSupported: fftypes.MinSupportedMessageVersion,
+			MaxSupported: fftypes.MaxSupportedMessageVersion,
+		},
 	}
 
 	org, err := or.database.GetOrganizationByName(ctx, status.Org.Name)
@@ -60,3 +68,40 @@ func (or *orchestrator) GetStatus(ctx context.Context) (status *fftypes.NodeStat
 
 	return status, nil
 }
+
+func (or *orchestrator) GetBlockchainStatus(ctx context.Context) (status *fftypes.BlockchainStatus, err error) {
+	status = &fftypes.BlockchainStatus{}
+	status.CurrentBlock, err = or.blockchain.GetBlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The block number of a pin is in the protocol specific info the blockchain plugin
+	// attached to the confirmed transaction
+	fb := database.TransactionQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("type", fftypes.TransactionTypeBatchPin),
+		fb.Eq("status", fftypes.OpStatusSucceeded),
+	).Sort("sequence").Descending().Limit(1)
+	txns, _, err := or.database.GetTransactions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(txns) == 0 {
+		return status, nil
+	}
+	sBlockNumber := txns[0].Info.GetString("blockNumber")
+	latestPinned, err := strconv.ParseUint(sBlockNumber, 10, 64)
+	if err != nil {
+		log.L(ctx).Warnf("Unable to parse block number '%s' of transaction %s", sBlockNumber, txns[0].ID)
+		return status, nil
+	}
+
+	var lag uint64
+	if status.CurrentBlock > latestPinned {
+		lag = status.CurrentBlock - latestPinned
+	}
+	status.LatestPinned = &latestPinned
+	status.Lag = &lag
+	return status, nil
+}
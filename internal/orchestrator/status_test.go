@@ -22,8 +22,10 @@ import (
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetStatusRegistered(t *testing.T) {
@@ -53,6 +55,9 @@ func TestGetStatusRegistered(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.Equal(t, "default", status.Defaults.Namespace)
+	assert.Equal(t, fftypes.MessageVersion, status.MessageVersions.Current)
+	assert.Equal(t, fftypes.MinSupportedMessageVersion, status.MessageVersions.MinSupported)
+	assert.Equal(t, fftypes.MaxSupportedMessageVersion, status.MessageVersions.MaxSupported)
 
 	assert.Equal(t, "org1", status.Org.Name)
 	assert.True(t, status.Org.Registered)
@@ -155,3 +160,80 @@ func TestGetStatusNodeError(t *testing.T) {
 	_, err := or.GetStatus(or.ctx)
 	assert.EqualError(t, err, "pop")
 }
+
+func TestGetBlockchainStatusLag(t *testing.T) {
+	or := newTestOrchestrator()
+
+	or.mbi.On("GetBlockNumber", or.ctx).Return(uint64(1050), nil)
+	or.mdi.On("GetTransactions", or.ctx, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == "( type == 'batch_pin' ) && ( status == 'Succeeded' ) sort=-sequence limit=1"
+	})).Return([]*fftypes.Transaction{
+		{ID: fftypes.NewUUID(), Info: fftypes.JSONObject{"blockNumber": "1000"}},
+	}, nil, nil)
+
+	status, err := or.GetBlockchainStatus(or.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1050), status.CurrentBlock)
+	assert.Equal(t, uint64(1000), *status.LatestPinned)
+	assert.Equal(t, uint64(50), *status.Lag)
+}
+
+func TestGetBlockchainStatusPinnedAhead(t *testing.T) {
+	or := newTestOrchestrator()
+
+	or.mbi.On("GetBlockNumber", or.ctx).Return(uint64(999), nil)
+	or.mdi.On("GetTransactions", or.ctx, mock.Anything).Return([]*fftypes.Transaction{
+		{ID: fftypes.NewUUID(), Info: fftypes.JSONObject{"blockNumber": "1000"}},
+	}, nil, nil)
+
+	status, err := or.GetBlockchainStatus(or.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), *status.Lag)
+}
+
+func TestGetBlockchainStatusNoPins(t *testing.T) {
+	or := newTestOrchestrator()
+
+	or.mbi.On("GetBlockNumber", or.ctx).Return(uint64(20), nil)
+	or.mdi.On("GetTransactions", or.ctx, mock.Anything).Return([]*fftypes.Transaction{}, nil, nil)
+
+	status, err := or.GetBlockchainStatus(or.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), status.CurrentBlock)
+	assert.Nil(t, status.LatestPinned)
+	assert.Nil(t, status.Lag)
+}
+
+func TestGetBlockchainStatusBadBlockNumber(t *testing.T) {
+	or := newTestOrchestrator()
+
+	or.mbi.On("GetBlockNumber", or.ctx).Return(uint64(20), nil)
+	or.mdi.On("GetTransactions", or.ctx, mock.Anything).Return([]*fftypes.Transaction{
+		{ID: fftypes.NewUUID(), Info: fftypes.JSONObject{}},
+	}, nil, nil)
+
+	status, err := or.GetBlockchainStatus(or.ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, status.LatestPinned)
+	assert.Nil(t, status.Lag)
+}
+
+func TestGetBlockchainStatusBlockNumberFail(t *testing.T) {
+	or := newTestOrchestrator()
+
+	or.mbi.On("GetBlockNumber", or.ctx).Return(uint64(0), fmt.Errorf("pop"))
+
+	_, err := or.GetBlockchainStatus(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetBlockchainStatusTransactionsFail(t *testing.T) {
+	or := newTestOrchestrator()
+
+	or.mbi.On("GetBlockNumber", or.ctx).Return(uint64(20), nil)
+	or.mdi.On("GetTransactions", or.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.GetBlockchainStatus(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
@@ -25,6 +25,22 @@ import (
 	"github.com/hyperledger/firefly/pkg/fftypes"
 )
 
+// maskedSecret replaces the webhook HMAC secret of every subscription returned on the API,
+// so a secret cannot be read back once it has been configured
+const maskedSecret = "****"
+
+func maskSubscriptionSecrets(subs ...*fftypes.Subscription) {
+	for _, sub := range subs {
+		if sub == nil {
+			continue
+		}
+		options := sub.Options.TransportOptions()
+		if _, ok := options["secret"]; ok {
+			options["secret"] = maskedSecret
+		}
+	}
+}
+
 func (or *orchestrator) CreateSubscription(ctx context.Context, ns string, subDef *fftypes.Subscription) (*fftypes.Subscription, error) {
 	return or.createUpdateSubscription(ctx, ns, subDef, true)
 }
@@ -48,7 +64,49 @@ func (or *orchestrator) createUpdateSubscription(ctx context.Context, ns string,
 		return nil, i18n.NewError(ctx, i18n.MsgSystemTransportInternal)
 	}
 
-	return subDef, or.events.CreateUpdateDurableSubscription(ctx, subDef, mustNew)
+	// A definition read back from the API has its secret masked, so keep the existing secret
+	// if that definition is submitted again unchanged
+	options := subDef.Options.TransportOptions()
+	if !mustNew && options["secret"] == maskedSecret {
+		existing, err := or.database.GetSubscriptionByName(ctx, ns, subDef.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			options["secret"] = existing.Options.TransportOptions()["secret"]
+		}
+	}
+
+	if err := or.events.CreateUpdateDurableSubscription(ctx, subDef, mustNew); err != nil {
+		return nil, err
+	}
+	maskSubscriptionSecrets(subDef)
+	return subDef, nil
+}
+
+func (or *orchestrator) UpdateSubscription(ctx context.Context, ns, id string, update *fftypes.SubscriptionUpdate) (*fftypes.Subscription, error) {
+	u, err := or.verifyIDAndNamespace(ctx, ns, id)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := or.database.GetSubscriptionByID(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil || sub.Namespace != ns {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	if update.MaxDeliveryAttempts != nil {
+		sub.MaxDeliveryAttempts = *update.MaxDeliveryAttempts
+		if err := sub.ValidateMaxDeliveryAttempts(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := or.events.CreateUpdateDurableSubscription(ctx, sub, false); err != nil {
+		return nil, err
+	}
+	maskSubscriptionSecrets(sub)
+	return sub, nil
 }
 
 func (or *orchestrator) DeleteSubscription(ctx context.Context, ns, id string) error {
@@ -66,9 +124,51 @@ func (or *orchestrator) DeleteSubscription(ctx context.Context, ns, id string) e
 	return or.events.DeleteDurableSubscription(ctx, sub)
 }
 
+func (or *orchestrator) PauseSubscription(ctx context.Context, ns, id string) error {
+	if err := or.verifyNamespaceSyntax(ctx, ns); err != nil {
+		return err
+	}
+	return or.events.PauseSubscription(ctx, ns, id)
+}
+
+func (or *orchestrator) ResumeSubscription(ctx context.Context, ns, id string) error {
+	if err := or.verifyNamespaceSyntax(ctx, ns); err != nil {
+		return err
+	}
+	return or.events.ResumeSubscription(ctx, ns, id)
+}
+
+func (or *orchestrator) RewindSubscription(ctx context.Context, ns, id string, fromSequence int64) error {
+	if err := or.verifyNamespaceSyntax(ctx, ns); err != nil {
+		return err
+	}
+	return or.events.RewindSubscription(ctx, ns, id, fromSequence)
+}
+
+func (or *orchestrator) GetSubscriptionDeadLetterEvents(ctx context.Context, ns, id string, filter database.AndFilter) ([]*fftypes.DeadLetterEvent, *database.FilterResult, error) {
+	filter = or.scopeNS(ns, filter)
+	u, err := or.verifyIDAndNamespace(ctx, ns, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return or.database.GetDeadLetterEvents(ctx, filter.Condition(filter.Builder().Eq("subscription", u)))
+}
+
+func (or *orchestrator) RedeliverDeadLetterEvent(ctx context.Context, ns, id, eventID string) error {
+	if err := or.verifyNamespaceSyntax(ctx, ns); err != nil {
+		return err
+	}
+	return or.events.RedeliverDeadLetterEvent(ctx, ns, id, eventID)
+}
+
 func (or *orchestrator) GetSubscriptions(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Subscription, *database.FilterResult, error) {
 	filter = or.scopeNS(ns, filter)
-	return or.database.GetSubscriptions(ctx, filter)
+	subs, res, err := or.database.GetSubscriptions(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	maskSubscriptionSecrets(subs...)
+	return subs, res, nil
 }
 
 func (or *orchestrator) GetSubscriptionByID(ctx context.Context, ns, id string) (*fftypes.Subscription, error) {
@@ -76,5 +176,10 @@ func (or *orchestrator) GetSubscriptionByID(ctx context.Context, ns, id string)
 	if err != nil {
 		return nil, err
 	}
-	return or.database.GetSubscriptionByID(ctx, u)
+	sub, err := or.database.GetSubscriptionByID(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	maskSubscriptionSecrets(sub)
+	return sub, nil
 }
@@ -134,6 +134,135 @@ func TestDeleteSubscription(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestUpdateSubscription(t *testing.T) {
+	or := newTestOrchestrator()
+	sub := &fftypes.Subscription{
+		SubscriptionRef: fftypes.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Name:      "sub1",
+			Namespace: "ns1",
+		},
+		MaxDeliveryAttempts: 3,
+	}
+	maxAttempts := 10
+	or.mdi.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+	or.mem.On("CreateUpdateDurableSubscription", mock.Anything, mock.MatchedBy(func(s *fftypes.Subscription) bool {
+		return s.MaxDeliveryAttempts == 10
+	}), false).Return(nil)
+	updated, err := or.UpdateSubscription(or.ctx, "ns1", sub.ID.String(), &fftypes.SubscriptionUpdate{
+		MaxDeliveryAttempts: &maxAttempts,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, updated.MaxDeliveryAttempts)
+}
+
+func TestUpdateSubscriptionBadMaxDeliveryAttempts(t *testing.T) {
+	or := newTestOrchestrator()
+	sub := &fftypes.Subscription{
+		SubscriptionRef: fftypes.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Name:      "sub1",
+			Namespace: "ns1",
+		},
+	}
+	maxAttempts := 0
+	or.mdi.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+	_, err := or.UpdateSubscription(or.ctx, "ns1", sub.ID.String(), &fftypes.SubscriptionUpdate{
+		MaxDeliveryAttempts: &maxAttempts,
+	})
+	assert.Regexp(t, "FF10335", err)
+}
+
+func TestUpdateSubscriptionNSMismatch(t *testing.T) {
+	or := newTestOrchestrator()
+	sub := &fftypes.Subscription{
+		SubscriptionRef: fftypes.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Name:      "sub1",
+			Namespace: "ns1",
+		},
+	}
+	or.mdi.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+	_, err := or.UpdateSubscription(or.ctx, "ns2", sub.ID.String(), &fftypes.SubscriptionUpdate{})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestUpdateSubscriptionLookupError(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetSubscriptionByID", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	_, err := or.UpdateSubscription(or.ctx, "ns1", fftypes.NewUUID().String(), &fftypes.SubscriptionUpdate{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestUpdateSubscriptionBadUUID(t *testing.T) {
+	or := newTestOrchestrator()
+	_, err := or.UpdateSubscription(or.ctx, "ns1", "! a UUID", &fftypes.SubscriptionUpdate{})
+	assert.Regexp(t, "FF10142", err)
+}
+
+func TestPauseResumeSubscription(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mem.On("PauseSubscription", mock.Anything, "ns1", "sub1").Return(nil)
+	or.mem.On("ResumeSubscription", mock.Anything, "ns1", "sub1").Return(nil)
+	err := or.PauseSubscription(or.ctx, "ns1", "sub1")
+	assert.NoError(t, err)
+	err = or.ResumeSubscription(or.ctx, "ns1", "sub1")
+	assert.NoError(t, err)
+}
+
+func TestPauseResumeSubscriptionBadNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	err := or.PauseSubscription(or.ctx, "!wrong", "sub1")
+	assert.Regexp(t, "FF10131", err)
+	err = or.ResumeSubscription(or.ctx, "!wrong", "sub1")
+	assert.Regexp(t, "FF10131", err)
+}
+
+func TestRewindSubscription(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mem.On("RewindSubscription", mock.Anything, "ns1", "sub1", int64(10)).Return(nil)
+	err := or.RewindSubscription(or.ctx, "ns1", "sub1", 10)
+	assert.NoError(t, err)
+}
+
+func TestRewindSubscriptionBadNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	err := or.RewindSubscription(or.ctx, "!wrong", "sub1", 10)
+	assert.Regexp(t, "FF10131", err)
+}
+
+func TestRedeliverDeadLetterEvent(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mem.On("RedeliverDeadLetterEvent", mock.Anything, "ns1", "sub1", "event1").Return(nil)
+	err := or.RedeliverDeadLetterEvent(or.ctx, "ns1", "sub1", "event1")
+	assert.NoError(t, err)
+}
+
+func TestRedeliverDeadLetterEventBadNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	err := or.RedeliverDeadLetterEvent(or.ctx, "!wrong", "sub1", "event1")
+	assert.Regexp(t, "FF10131", err)
+}
+
+func TestGetSubscriptionDeadLetterEvents(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetDeadLetterEvents", mock.Anything, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == "( namespace == 'ns1' ) && ( subscription == '"+u.String()+"' )"
+	})).Return([]*fftypes.DeadLetterEvent{}, nil, nil)
+	fb := database.DeadLetterEventQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetSubscriptionDeadLetterEvents(context.Background(), "ns1", u.String(), fb.And())
+	assert.NoError(t, err)
+}
+
+func TestGetSubscriptionDeadLetterEventsBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	fb := database.DeadLetterEventQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetSubscriptionDeadLetterEvents(context.Background(), "ns1", "!bad", fb.And())
+	assert.Regexp(t, "FF10142", err)
+}
+
 func TestGetSubscriptions(t *testing.T) {
 	or := newTestOrchestrator()
 	u := fftypes.NewUUID()
@@ -157,3 +286,106 @@ func TestGetSubscriptionDefsByIDBadID(t *testing.T) {
 	_, err := or.GetSubscriptionByID(context.Background(), "", "")
 	assert.Regexp(t, "FF10142", err)
 }
+
+func newTestSecretSubscription(secret string) *fftypes.Subscription {
+	sub := &fftypes.Subscription{
+		SubscriptionRef: fftypes.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Name:      "sub1",
+			Namespace: "ns1",
+		},
+	}
+	sub.Options.TransportOptions()["secret"] = secret
+	return sub
+}
+
+func TestCreateSubscriptionMasksSecret(t *testing.T) {
+	or := newTestOrchestrator()
+	sub := newTestSecretSubscription("s3cret")
+	or.mdm.On("VerifyNamespaceExists", mock.Anything, "ns1").Return(nil)
+	or.mem.On("CreateUpdateDurableSubscription", mock.Anything, mock.MatchedBy(func(s *fftypes.Subscription) bool {
+		return s.Options.TransportOptions()["secret"] == "s3cret"
+	}), true).Return(nil)
+	s1, err := or.CreateSubscription(or.ctx, "ns1", sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "****", s1.Options.TransportOptions()["secret"])
+}
+
+func TestCreateSubscriptionFail(t *testing.T) {
+	or := newTestOrchestrator()
+	sub := newTestSecretSubscription("s3cret")
+	or.mdm.On("VerifyNamespaceExists", mock.Anything, "ns1").Return(nil)
+	or.mem.On("CreateUpdateDurableSubscription", mock.Anything, mock.Anything, true).Return(fmt.Errorf("pop"))
+	_, err := or.CreateSubscription(or.ctx, "ns1", sub)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateUpdateSubscriptionKeepsMaskedSecret(t *testing.T) {
+	or := newTestOrchestrator()
+	sub := newTestSecretSubscription("****")
+	or.mdm.On("VerifyNamespaceExists", mock.Anything, "ns1").Return(nil)
+	or.mdi.On("GetSubscriptionByName", mock.Anything, "ns1", "sub1").Return(newTestSecretSubscription("s3cret"), nil)
+	or.mem.On("CreateUpdateDurableSubscription", mock.Anything, mock.MatchedBy(func(s *fftypes.Subscription) bool {
+		return s.Options.TransportOptions()["secret"] == "s3cret"
+	}), false).Return(nil)
+	s1, err := or.CreateUpdateSubscription(or.ctx, "ns1", sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "****", s1.Options.TransportOptions()["secret"])
+	or.mem.AssertExpectations(t)
+}
+
+func TestCreateUpdateSubscriptionMaskedSecretLookupFail(t *testing.T) {
+	or := newTestOrchestrator()
+	sub := newTestSecretSubscription("****")
+	or.mdm.On("VerifyNamespaceExists", mock.Anything, "ns1").Return(nil)
+	or.mdi.On("GetSubscriptionByName", mock.Anything, "ns1", "sub1").Return(nil, fmt.Errorf("pop"))
+	_, err := or.CreateUpdateSubscription(or.ctx, "ns1", sub)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestUpdateSubscriptionFail(t *testing.T) {
+	or := newTestOrchestrator()
+	sub := newTestSecretSubscription("s3cret")
+	or.mdi.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+	or.mem.On("CreateUpdateDurableSubscription", mock.Anything, mock.Anything, false).Return(fmt.Errorf("pop"))
+	_, err := or.UpdateSubscription(or.ctx, "ns1", sub.ID.String(), &fftypes.SubscriptionUpdate{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetSubscriptionsMasksSecret(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetSubscriptions", mock.Anything, mock.Anything).Return([]*fftypes.Subscription{
+		newTestSecretSubscription("s3cret"),
+		{SubscriptionRef: fftypes.SubscriptionRef{Name: "sub2"}},
+	}, nil, nil)
+	fb := database.SubscriptionQueryFactory.NewFilter(context.Background())
+	subs, _, err := or.GetSubscriptions(context.Background(), "ns1", fb.And())
+	assert.NoError(t, err)
+	assert.Equal(t, "****", subs[0].Options.TransportOptions()["secret"])
+	assert.NotContains(t, subs[1].Options.TransportOptions(), "secret")
+}
+
+func TestGetSubscriptionsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mdi.On("GetSubscriptions", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	fb := database.SubscriptionQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetSubscriptions(context.Background(), "ns1", fb.And())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetSubscriptionByIDMasksSecret(t *testing.T) {
+	or := newTestOrchestrator()
+	sub := newTestSecretSubscription("s3cret")
+	or.mdi.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+	s1, err := or.GetSubscriptionByID(context.Background(), "ns1", sub.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "****", s1.Options.TransportOptions()["secret"])
+}
+
+func TestGetSubscriptionByIDFail(t *testing.T) {
+	or := newTestOrchestrator()
+	u := fftypes.NewUUID()
+	or.mdi.On("GetSubscriptionByID", mock.Anything, u).Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetSubscriptionByID(context.Background(), "ns1", u.String())
+	assert.EqualError(t, err, "pop")
+}
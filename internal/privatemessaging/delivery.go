@@ -0,0 +1,83 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+func (pm *privateMessaging) GetDeliveryStatus(ctx context.Context, ns, msgID string) ([]*fftypes.DeliveryStatus, error) {
+	id, err := fftypes.ParseUUID(ctx, msgID)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := pm.database.GetMessageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil || msg.Header.Namespace != ns {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+
+	// Until the message is dispatched in a batch, there is nothing to report
+	deliveries := []*fftypes.DeliveryStatus{}
+	if msg.BatchID == nil {
+		return deliveries, nil
+	}
+	batch, err := pm.database.GetBatchByID(ctx, msg.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, i18n.NewError(ctx, i18n.MsgBatchNotFound, msg.BatchID)
+	}
+	if batch.Payload.TX.ID == nil {
+		return nil, i18n.NewError(ctx, i18n.MsgBatchTXNotSet, msg.BatchID)
+	}
+
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	ops, _, err := pm.database.GetOperations(ctx, fb.And(
+		fb.Eq("tx", batch.Payload.TX.ID),
+		fb.Eq("type", fftypes.OpTypeDataExchangeBatchSend),
+	))
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		// The member of each send operation is the ID of the target node
+		nodeID, err := fftypes.ParseUUID(ctx, op.Member)
+		if err != nil {
+			log.L(ctx).Warnf("Operation %s has invalid node ID '%s'", op.ID, op.Member)
+		}
+		updated := op.Updated
+		if updated == nil {
+			updated = op.Created
+		}
+		deliveries = append(deliveries, &fftypes.DeliveryStatus{
+			NodeID:    nodeID,
+			Status:    op.Status,
+			UpdatedAt: updated,
+			Error:     op.Error,
+		})
+	}
+	return deliveries, nil
+}
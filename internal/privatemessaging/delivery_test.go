@@ -0,0 +1,191 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetDeliveryStatusOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msgID := fftypes.NewUUID()
+	batchID := fftypes.NewUUID()
+	txID := fftypes.NewUUID()
+	node1 := fftypes.NewUUID()
+	created := fftypes.Now()
+	updated := fftypes.Now()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, msgID).Return(&fftypes.Message{
+		Header:  fftypes.MessageHeader{ID: msgID, Namespace: "ns1"},
+		BatchID: batchID,
+	}, nil)
+	mdi.On("GetBatchByID", pm.ctx, batchID).Return(&fftypes.Batch{
+		ID: batchID,
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{ID: txID},
+		},
+	}, nil)
+	mdi.On("GetOperations", pm.ctx, mock.Anything).Return([]*fftypes.Operation{
+		{Member: node1.String(), Status: fftypes.OpStatusSucceeded, Created: created, Updated: updated},
+		{Member: "!bad", Status: fftypes.OpStatusFailed, Created: created, Error: "pop"},
+	}, nil, nil)
+
+	deliveries, err := pm.GetDeliveryStatus(pm.ctx, "ns1", msgID.String())
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, *node1, *deliveries[0].NodeID)
+	assert.Equal(t, fftypes.OpStatusSucceeded, deliveries[0].Status)
+	assert.Equal(t, updated, deliveries[0].UpdatedAt)
+	assert.Nil(t, deliveries[1].NodeID)
+	assert.Equal(t, fftypes.OpStatusFailed, deliveries[1].Status)
+	assert.Equal(t, created, deliveries[1].UpdatedAt)
+	assert.Equal(t, "pop", deliveries[1].Error)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetDeliveryStatusNotBatched(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msgID := fftypes.NewUUID()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, msgID).Return(&fftypes.Message{
+		Header: fftypes.MessageHeader{ID: msgID, Namespace: "ns1"},
+	}, nil)
+
+	deliveries, err := pm.GetDeliveryStatus(pm.ctx, "ns1", msgID.String())
+	assert.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
+
+func TestGetDeliveryStatusBadID(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.GetDeliveryStatus(pm.ctx, "ns1", "!bad")
+	assert.Regexp(t, "FF10142", err)
+}
+
+func TestGetDeliveryStatusGetMessageFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msgID := fftypes.NewUUID()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, msgID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.GetDeliveryStatus(pm.ctx, "ns1", msgID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetDeliveryStatusWrongNamespace(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msgID := fftypes.NewUUID()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, msgID).Return(&fftypes.Message{
+		Header: fftypes.MessageHeader{ID: msgID, Namespace: "ns2"},
+	}, nil)
+
+	_, err := pm.GetDeliveryStatus(pm.ctx, "ns1", msgID.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetDeliveryStatusGetBatchFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msgID := fftypes.NewUUID()
+	batchID := fftypes.NewUUID()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, msgID).Return(&fftypes.Message{
+		Header:  fftypes.MessageHeader{ID: msgID, Namespace: "ns1"},
+		BatchID: batchID,
+	}, nil)
+	mdi.On("GetBatchByID", pm.ctx, batchID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.GetDeliveryStatus(pm.ctx, "ns1", msgID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetDeliveryStatusBatchNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msgID := fftypes.NewUUID()
+	batchID := fftypes.NewUUID()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, msgID).Return(&fftypes.Message{
+		Header:  fftypes.MessageHeader{ID: msgID, Namespace: "ns1"},
+		BatchID: batchID,
+	}, nil)
+	mdi.On("GetBatchByID", pm.ctx, batchID).Return(nil, nil)
+
+	_, err := pm.GetDeliveryStatus(pm.ctx, "ns1", msgID.String())
+	assert.Regexp(t, "FF10209", err)
+}
+
+func TestGetDeliveryStatusBatchNoTX(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msgID := fftypes.NewUUID()
+	batchID := fftypes.NewUUID()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, msgID).Return(&fftypes.Message{
+		Header:  fftypes.MessageHeader{ID: msgID, Namespace: "ns1"},
+		BatchID: batchID,
+	}, nil)
+	mdi.On("GetBatchByID", pm.ctx, batchID).Return(&fftypes.Batch{ID: batchID}, nil)
+
+	_, err := pm.GetDeliveryStatus(pm.ctx, "ns1", msgID.String())
+	assert.Regexp(t, "FF10210", err)
+}
+
+func TestGetDeliveryStatusGetOperationsFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msgID := fftypes.NewUUID()
+	batchID := fftypes.NewUUID()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, msgID).Return(&fftypes.Message{
+		Header:  fftypes.MessageHeader{ID: msgID, Namespace: "ns1"},
+		BatchID: batchID,
+	}, nil)
+	mdi.On("GetBatchByID", pm.ctx, batchID).Return(&fftypes.Batch{
+		ID: batchID,
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{ID: fftypes.NewUUID()},
+		},
+	}, nil)
+	mdi.On("GetOperations", pm.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := pm.GetDeliveryStatus(pm.ctx, "ns1", msgID.String())
+	assert.EqualError(t, err, "pop")
+}
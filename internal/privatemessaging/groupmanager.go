@@ -19,6 +19,7 @@ package privatemessaging
 import (
 	"context"
 	"encoding/json"
+	"sync/atomic"
 	"time"
 
 	"github.com/hyperledger/firefly/internal/data"
@@ -32,15 +33,18 @@ import (
 type GroupManager interface {
 	GetGroupByID(ctx context.Context, id string) (*fftypes.Group, error)
 	GetGroups(ctx context.Context, filter database.AndFilter) ([]*fftypes.Group, *database.FilterResult, error)
-	ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (*fftypes.Group, error)
+	ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (group *fftypes.Group, valid bool, err error)
 	EnsureLocalGroup(ctx context.Context, group *fftypes.Group) (ok bool, err error)
 }
 
 type groupManager struct {
-	database      database.Plugin
-	data          data.Manager
-	groupCacheTTL time.Duration
-	groupCache    *ccache.Cache
+	groupCacheHits   int64 // accessed atomically, so kept first for 64bit alignment
+	groupCacheMisses int64
+	database         database.Plugin
+	data             data.Manager
+	groupCacheTTL    time.Duration
+	groupCache       *ccache.Cache
+	maxGroupSize     int
 }
 
 type groupHashEntry struct {
@@ -73,6 +77,9 @@ func (gm *groupManager) EnsureLocalGroup(ctx context.Context, group *fftypes.Gro
 	return true, nil
 }
 
+// groupInit stores a new group and sends its definition to the members, ahead of the first message sent to it.
+// The group is validated first - including its size against privatemessaging.group.maxSize - and any error is
+// returned to the API caller sending that message.
 func (gm *groupManager) groupInit(ctx context.Context, signer *fftypes.Identity, group *fftypes.Group) (err error) {
 
 	// Serialize it into a data object, as a piece of data we can write to a message
@@ -92,6 +99,18 @@ func (gm *groupManager) groupInit(ctx context.Context, signer *fftypes.Identity,
 	if err != nil {
 		return i18n.WrapError(ctx, err, i18n.MsgSerializationFailed)
 	}
+	if err = group.ValidateSize(ctx, gm.maxGroupSize); err != nil {
+		return err
+	}
+	if _, err = gm.validatePrevious(ctx, signer.Identifier, group); err != nil {
+		return err
+	}
+	if group.Schema != nil {
+		// Check the schema exists, before we commit the group to it
+		if err = gm.data.ValidateAgainstDatatype(ctx, group.Namespace, group.Schema, nil); err != nil {
+			return err
+		}
+	}
 
 	// In the case of groups, we actually write the unconfirmed group directly to our database.
 	// So it can be used straight away.
@@ -117,6 +136,7 @@ func (gm *groupManager) groupInit(ctx context.Context, signer *fftypes.Identity,
 			Topics:    fftypes.FFNameArray{group.Topic()},
 			TxType:    fftypes.TransactionTypeBatchPin,
 		},
+		Pinned: true,
 		Data: fftypes.DataRefs{
 			{ID: data.ID, Hash: data.Hash},
 		},
@@ -126,6 +146,7 @@ func (gm *groupManager) groupInit(ctx context.Context, signer *fftypes.Identity,
 	err = msg.Seal(ctx)
 	if err == nil {
 		// Store the message - this asynchronously triggers the next step in process
+		msg.CreatedBy = fftypes.GetCreatedBy(ctx)
 		err = gm.database.InsertMessageLocal(ctx, msg)
 	}
 	if err == nil {
@@ -135,8 +156,65 @@ func (gm *groupManager) groupInit(ctx context.Context, signer *fftypes.Identity,
 
 }
 
+// previousGroup returns the group that the supplied group re-initializes, and any other group that has already
+// re-initialized it. Groups that start a new lineage, without a previous group, return nil for both.
+func (gm *groupManager) previousGroup(ctx context.Context, group *fftypes.Group) (previous, successor *fftypes.Group, err error) {
+	if group.Previous == nil {
+		return nil, nil, nil
+	}
+	if previous, err = gm.database.GetGroupByHash(ctx, group.Previous); err != nil || previous == nil {
+		return nil, nil, err
+	}
+	fb := database.GroupQueryFactory.NewFilterLimit(ctx, 1)
+	filter := fb.And(
+		fb.Eq("previous", group.Previous),
+		fb.Neq("hash", group.Hash),
+	)
+	successors, _, err := gm.database.GetGroups(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(successors) > 0 {
+		successor = successors[0]
+	}
+	return previous, successor, nil
+}
+
+// checkPrevious protects against replay of an old group init, by requiring that a group re-initializing a
+// previous group has a strictly greater nonce. Each group can only be re-initialized once, so where two
+// re-inits of the same group are sent concurrently only the first to be pinned is accepted.
+// Only the identities permitted by the previous group can re-initialize it - so the permissions follow the
+// lineage of the group, rather than any group that happens to have the same name.
+func checkPrevious(ctx context.Context, author string, group, previous, successor *fftypes.Group) error {
+	if group.Previous == nil {
+		return nil
+	}
+	if previous == nil {
+		return i18n.NewError(ctx, i18n.MsgPreviousGroupNotFound, group.Previous)
+	}
+	if !previous.AllowsReinit(author) {
+		return i18n.NewError(ctx, i18n.MsgGroupACLDenied, author, previous.Hash)
+	}
+	if group.Nonce <= previous.Nonce {
+		return i18n.NewError(ctx, i18n.MsgGroupNonceNotGreater, group.Nonce, previous.Nonce, previous.Hash)
+	}
+	if successor != nil {
+		return i18n.NewError(ctx, i18n.MsgGroupAlreadyReinitialized, previous.Hash, successor.Hash)
+	}
+	return nil
+}
+
+// validatePrevious checks the lineage of a group, returning the group it re-initializes (if any)
+func (gm *groupManager) validatePrevious(ctx context.Context, author string, group *fftypes.Group) (*fftypes.Group, error) {
+	previous, successor, err := gm.previousGroup(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	return previous, checkPrevious(ctx, author, group, previous, successor)
+}
+
 func (gm *groupManager) GetGroupByID(ctx context.Context, hash string) (*fftypes.Group, error) {
-	h, err := fftypes.ParseBytes32(ctx, hash)
+	h, err := fftypes.ParseBytes32Flexible(ctx, hash)
 	if err != nil {
 		return nil, err
 	}
@@ -150,10 +228,12 @@ func (gm *groupManager) GetGroups(ctx context.Context, filter database.AndFilter
 func (gm *groupManager) getGroupNodes(ctx context.Context, groupHash *fftypes.Bytes32) (*fftypes.Group, []*fftypes.Node, error) {
 
 	if cached := gm.groupCache.Get(groupHash.String()); cached != nil {
+		atomic.AddInt64(&gm.groupCacheHits, 1)
 		cached.Extend(gm.groupCacheTTL)
 		ghe := cached.Value().(*groupHashEntry)
 		return ghe.group, ghe.nodes, nil
 	}
+	atomic.AddInt64(&gm.groupCacheMisses, 1)
 
 	group, err := gm.database.GetGroupByHash(ctx, groupHash)
 	if err != nil {
@@ -188,50 +268,76 @@ func (gm *groupManager) getGroupNodes(ctx context.Context, groupHash *fftypes.By
 	return group, nodes, nil
 }
 
+// FlushGroupCache discards all cached group membership, so it is re-read from the database on next use
+func (gm *groupManager) FlushGroupCache() {
+	gm.groupCache.Clear()
+}
+
+func (gm *groupManager) GroupCacheStats() *fftypes.CacheStats {
+	return &fftypes.CacheStats{
+		Size:   gm.groupCache.ItemCount(),
+		Hits:   atomic.LoadInt64(&gm.groupCacheHits),
+		Misses: atomic.LoadInt64(&gm.groupCacheMisses),
+	}
+}
+
 // ResolveInitGroup is called when a message comes in as the first private message on a particular context.
 // If the message is a group creation request, then it is validated and the group is created.
 // Otherwise, the existing group must exist.
 //
-// Errors are only returned for database issues. For validation issues, a nil group is returned without an error.
-func (gm *groupManager) ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (*fftypes.Group, error) {
+// Errors are only returned for database issues. A group init that fails validation is returned as not valid,
+// so that the message can be rejected. A nil group that is valid means the message must wait - for its data,
+// or for the group to be initialized.
+func (gm *groupManager) ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (*fftypes.Group, bool, error) {
 	if msg.Header.Tag == string(fftypes.SystemTagDefineGroup) {
 		// Store the new group
 		data, foundAll, err := gm.data.GetMessageData(ctx, msg, true)
 		if err != nil || !foundAll || len(data) == 0 {
 			log.L(ctx).Warnf("Group %s definition in message %s invalid: missing data", msg.Header.Group, msg.Header.ID)
-			return nil, err
+			return nil, true, err
 		}
 		var newGroup fftypes.Group
 		err = json.Unmarshal(data[0].Value, &newGroup)
 		if err != nil {
 			log.L(ctx).Warnf("Group %s definition in message %s invalid: %s", msg.Header.Group, msg.Header.ID, err)
-			return nil, nil
+			return nil, false, nil
 		}
 		err = newGroup.Validate(ctx, true)
+		if err == nil {
+			err = newGroup.ValidateSize(ctx, gm.maxGroupSize)
+		}
 		if err != nil {
 			log.L(ctx).Warnf("Group %s definition in message %s invalid: %s", msg.Header.Group, msg.Header.ID, err)
-			return nil, nil
+			return nil, false, nil
 		}
 		if !newGroup.Hash.Equals(msg.Header.Group) {
 			log.L(ctx).Warnf("Group %s definition in message %s invalid: mismatched hash with message '%s'", msg.Header.Group, msg.Header.ID, newGroup.Hash)
-			return nil, nil
+			return nil, false, nil
+		}
+		previous, successor, err := gm.previousGroup(ctx, &newGroup)
+		if err != nil {
+			return nil, false, err
+		}
+		if err = checkPrevious(ctx, msg.Header.Author, &newGroup, previous, successor); err != nil {
+			log.L(ctx).Warnf("Group %s definition in message %s invalid: %s", msg.Header.Group, msg.Header.ID, err)
+			return nil, false, nil
 		}
 		newGroup.Message = msg.Header.ID
 		err = gm.database.UpsertGroup(ctx, &newGroup, true)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
-		return &newGroup, nil
+		return &newGroup, true, nil
 	}
 
 	// Get the existing group
 	group, err := gm.database.GetGroupByHash(ctx, msg.Header.Group)
 	if err != nil {
-		return group, err
+		return group, false, err
 	}
 	if group == nil {
 		log.L(ctx).Warnf("Group %s not found for first message in context. type=%s namespace=%s", msg.Header.Group, msg.Header.Type, msg.Header.Namespace)
-		return nil, nil
+		return nil, true, nil
 	}
-	return group, nil
+	return group, true, nil
 }
@@ -59,6 +59,232 @@ func TestGroupInitWriteGroupFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 }
 
+func TestGroupInitNonceNotGreater(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{Name: "group1", ACL: fftypes.GroupACL{"org1"}, Nonce: 3}, Hash: previous,
+	}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{}, nil, nil)
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "id1", Node: fftypes.NewUUID()},
+			},
+			Previous: previous,
+			Nonce:    3,
+		},
+	}
+	group.Seal()
+	err := pm.groupInit(pm.ctx, &fftypes.Identity{Identifier: "org1"}, group)
+	assert.Regexp(t, "FF10307.*3.*3", err)
+}
+
+func TestGroupInitACLDenied(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{Name: "group1", ACL: fftypes.GroupACL{"org1"}}, Hash: previous,
+	}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{}, nil, nil)
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "id1", Node: fftypes.NewUUID()},
+			},
+			Previous: previous,
+			Nonce:    1,
+		},
+	}
+	group.Seal()
+	err := pm.groupInit(pm.ctx, &fftypes.Identity{Identifier: "org2"}, group)
+	assert.Regexp(t, "FF10332.*org2", err)
+}
+
+func TestGroupInitPreviousNotFound(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(nil, nil)
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "id1", Node: fftypes.NewUUID()},
+			},
+			Previous: previous,
+			Nonce:    1,
+		},
+	}
+	group.Seal()
+	err := pm.groupInit(pm.ctx, &fftypes.Identity{}, group)
+	assert.Regexp(t, "FF10352", err)
+}
+
+func TestGroupInitAlreadyReinitialized(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	successor := fftypes.NewRandB32()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{Name: "group1", ACL: fftypes.GroupACL{"org1"}}, Hash: previous,
+	}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{
+		{GroupIdentity: fftypes.GroupIdentity{Name: "group1", Nonce: 1, Previous: previous}, Hash: successor},
+	}, nil, nil)
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "id1", Node: fftypes.NewUUID()},
+			},
+			Previous: previous,
+			Nonce:    1,
+		},
+	}
+	group.Seal()
+	err := pm.groupInit(pm.ctx, &fftypes.Identity{Identifier: "org1"}, group)
+	assert.Regexp(t, "FF10353.*"+successor.String(), err)
+}
+
+func TestGroupInitPreviousQueryFail(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "id1", Node: fftypes.NewUUID()},
+			},
+			Previous: fftypes.NewRandB32(),
+		},
+	}
+	group.Seal()
+	err := pm.groupInit(pm.ctx, &fftypes.Identity{}, group)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGroupInitSuccessorQueryFail(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(&fftypes.Group{}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "id1", Node: fftypes.NewUUID()},
+			},
+			Previous: fftypes.NewRandB32(),
+		},
+	}
+	group.Seal()
+	err := pm.groupInit(pm.ctx, &fftypes.Identity{}, group)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGroupInitFirstNonceZero(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertGroup", mock.Anything, mock.Anything, true).Return(fmt.Errorf("pop"))
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "id1", Node: fftypes.NewUUID()},
+			},
+		},
+	}
+	group.Seal()
+	err := pm.groupInit(pm.ctx, &fftypes.Identity{}, group)
+	assert.Regexp(t, "pop", err) // a group without a previous group is not checked, so fails on the write
+	mdi.AssertExpectations(t)
+}
+
+func TestGroupInitTooLarge(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.maxGroupSize = 1
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "id1", Node: fftypes.NewUUID()},
+				{Identity: "id2", Node: fftypes.NewUUID()},
+			},
+		},
+	}
+	group.Seal()
+	err := pm.groupInit(pm.ctx, &fftypes.Identity{}, group)
+	assert.Regexp(t, "FF10280", err)
+}
+
+func TestGroupInitSchemaNotFound(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	schema := &fftypes.DatatypeRef{Name: "customer", Version: "0.0.1"}
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ValidateAgainstDatatype", pm.ctx, "ns1", schema, []*fftypes.Data(nil)).Return(fmt.Errorf("pop"))
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "id1", Node: fftypes.NewUUID()},
+			},
+			Schema: schema,
+		},
+	}
+	group.Seal()
+	err := pm.groupInit(pm.ctx, &fftypes.Identity{}, group)
+	assert.Regexp(t, "pop", err)
+	mdm.AssertExpectations(t)
+}
+
 func TestGroupInitWriteDataFail(t *testing.T) {
 
 	pm, cancel := newTestPrivateMessaging(t)
@@ -88,7 +314,7 @@ func TestResolveInitGroupMissingData(t *testing.T) {
 	mdm := pm.data.(*datamocks.Manager)
 	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return([]*fftypes.Data{}, false, nil)
 
-	_, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+	_, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:        fftypes.NewUUID(),
 			Namespace: fftypes.SystemNamespace,
@@ -98,6 +324,7 @@ func TestResolveInitGroupMissingData(t *testing.T) {
 		},
 	})
 	assert.NoError(t, err)
+	assert.True(t, valid)
 
 }
 
@@ -110,7 +337,7 @@ func TestResolveInitGroupBadData(t *testing.T) {
 		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`!json`)},
 	}, true, nil)
 
-	_, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+	_, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:        fftypes.NewUUID(),
 			Namespace: fftypes.SystemNamespace,
@@ -120,6 +347,7 @@ func TestResolveInitGroupBadData(t *testing.T) {
 		},
 	})
 	assert.NoError(t, err)
+	assert.False(t, valid)
 
 }
 
@@ -132,7 +360,7 @@ func TestResolveInitGroupBadValidation(t *testing.T) {
 		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`{}`)},
 	}, true, nil)
 
-	_, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+	_, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:        fftypes.NewUUID(),
 			Namespace: fftypes.SystemNamespace,
@@ -142,6 +370,7 @@ func TestResolveInitGroupBadValidation(t *testing.T) {
 		},
 	})
 	assert.NoError(t, err)
+	assert.False(t, valid)
 
 }
 
@@ -167,7 +396,7 @@ func TestResolveInitGroupBadGroupID(t *testing.T) {
 		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
 	}, true, nil)
 
-	_, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+	_, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:        fftypes.NewUUID(),
 			Namespace: fftypes.SystemNamespace,
@@ -177,6 +406,45 @@ func TestResolveInitGroupBadGroupID(t *testing.T) {
 		},
 	})
 	assert.NoError(t, err)
+	assert.False(t, valid)
+
+}
+
+func TestResolveInitGroupTooLarge(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.maxGroupSize = 1
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "abce12345", Node: fftypes.NewUUID()},
+				{Identity: "abce67890", Node: fftypes.NewUUID()},
+			},
+		},
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return([]*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
+	}, true, nil)
+
+	resolved, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: fftypes.SystemNamespace,
+			Tag:       string(fftypes.SystemTagDefineGroup),
+			Group:     group.Hash,
+			Author:    "author1",
+		},
+	})
+	assert.NoError(t, err)
+	assert.False(t, valid)
+	assert.Nil(t, resolved)
 
 }
 
@@ -202,9 +470,10 @@ func TestResolveInitGroupUpsertFail(t *testing.T) {
 		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
 	}, true, nil)
 	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{}, nil, nil)
 	mdi.On("UpsertGroup", pm.ctx, mock.Anything, true).Return(fmt.Errorf("pop"))
 
-	_, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+	_, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:        fftypes.NewUUID(),
 			Namespace: fftypes.SystemNamespace,
@@ -214,6 +483,7 @@ func TestResolveInitGroupUpsertFail(t *testing.T) {
 		},
 	})
 	assert.EqualError(t, err, "pop")
+	assert.False(t, valid)
 
 }
 
@@ -239,10 +509,139 @@ func TestResolveInitGroupNewOk(t *testing.T) {
 		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
 	}, true, nil)
 	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{}, nil, nil)
 	mdi.On("UpsertGroup", pm.ctx, mock.Anything, true).Return(nil)
 	mdi.On("InsertEvent", pm.ctx, mock.Anything).Return(nil)
 
-	group, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+	group, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: fftypes.SystemNamespace,
+			Tag:       string(fftypes.SystemTagDefineGroup),
+			Group:     group.Hash,
+			Author:    "author1",
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+}
+
+func TestResolveInitGroupNonceNotGreater(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "abce12345", Node: fftypes.NewUUID()},
+			},
+			Previous: previous,
+			Nonce:    1,
+		},
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return([]*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{Name: "group1", Nonce: 1, Members: fftypes.Members{{Identity: "author1"}}}, Hash: previous,
+	}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{}, nil, nil)
+
+	resolved, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: fftypes.SystemNamespace,
+			Tag:       string(fftypes.SystemTagDefineGroup),
+			Group:     group.Hash,
+			Author:    "author1",
+		},
+	})
+	assert.NoError(t, err)
+	assert.False(t, valid)
+	assert.Nil(t, resolved)
+	mdi.AssertNotCalled(t, "UpsertGroup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResolveInitGroupReinitOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "abce12345", Node: fftypes.NewUUID()},
+			},
+			Previous: previous,
+			Nonce:    1,
+		},
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return([]*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{Name: "group1", Members: fftypes.Members{{Identity: "author1"}}}, Hash: previous,
+	}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{}, nil, nil)
+	mdi.On("UpsertGroup", pm.ctx, mock.Anything, true).Return(nil)
+
+	resolved, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: fftypes.SystemNamespace,
+			Tag:       string(fftypes.SystemTagDefineGroup),
+			Group:     group.Hash,
+			Author:    "author1",
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, previous, resolved.Previous)
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveInitGroupPreviousQueryFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "abce12345", Node: fftypes.NewUUID()},
+			},
+			Previous: fftypes.NewRandB32(),
+			Nonce:    1,
+		},
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return([]*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, group.Previous).Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:        fftypes.NewUUID(),
 			Namespace: fftypes.SystemNamespace,
@@ -251,8 +650,206 @@ func TestResolveInitGroupNewOk(t *testing.T) {
 			Author:    "author1",
 		},
 	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestResolveInitGroupACLDenied(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "abce12345", Node: fftypes.NewUUID()},
+			},
+			Previous: previous,
+			Nonce:    2,
+		},
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return([]*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:    "group1",
+			Nonce:   1,
+			Members: fftypes.Members{{Identity: "author1"}},
+			ACL:     fftypes.GroupACL{"owner1"},
+		},
+		Hash: previous,
+	}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{}, nil, nil)
+
+	resolved, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: fftypes.SystemNamespace,
+			Tag:       string(fftypes.SystemTagDefineGroup),
+			Group:     group.Hash,
+			Author:    "author1",
+		},
+	})
+	assert.NoError(t, err)
+	assert.False(t, valid)
+	assert.Nil(t, resolved)
+	mdi.AssertNotCalled(t, "UpsertGroup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResolveInitGroupNonMemberDenied(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "abce12345", Node: fftypes.NewUUID()},
+			},
+			Previous: previous,
+			Nonce:    2,
+		},
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return([]*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:    "group1",
+			Nonce:   1,
+			Members: fftypes.Members{{Identity: "author1"}},
+		},
+		Hash: previous,
+	}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{}, nil, nil)
+
+	resolved, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: fftypes.SystemNamespace,
+			Tag:       string(fftypes.SystemTagDefineGroup),
+			Group:     group.Hash,
+			Author:    "stranger1",
+		},
+	})
 	assert.NoError(t, err)
+	assert.False(t, valid)
+	assert.Nil(t, resolved)
+	mdi.AssertNotCalled(t, "UpsertGroup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResolveInitGroupACLAllowed(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "abce12345", Node: fftypes.NewUUID()},
+			},
+			ACL:      fftypes.GroupACL{"owner1"},
+			Previous: previous,
+			Nonce:    2,
+		},
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return([]*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:    "group1",
+			Nonce:   1,
+			Members: fftypes.Members{{Identity: "author1"}},
+			ACL:     fftypes.GroupACL{"owner1"},
+		},
+		Hash: previous,
+	}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{}, nil, nil)
+	mdi.On("UpsertGroup", pm.ctx, mock.Anything, true).Return(nil)
+
+	resolved, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: fftypes.SystemNamespace,
+			Tag:       string(fftypes.SystemTagDefineGroup),
+			Group:     group.Hash,
+			Author:    "owner1",
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, fftypes.GroupACL{"owner1"}, resolved.ACL)
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveInitGroupSuccessorQueryFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: fftypes.Members{
+				{Identity: "abce12345", Node: fftypes.NewUUID()},
+			},
+			Previous: previous,
+			Nonce:    2,
+		},
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
 
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return([]*fftypes.Data{
+		{ID: fftypes.NewUUID(), Value: fftypes.Byteable(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:    "group1",
+			Nonce:   1,
+			Members: fftypes.Members{{Identity: "author1"}},
+		},
+		Hash: previous,
+	}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	resolved, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: fftypes.SystemNamespace,
+			Tag:       string(fftypes.SystemTagDefineGroup),
+			Group:     group.Hash,
+			Author:    "author1",
+		},
+	})
+	assert.EqualError(t, err, "pop")
+	assert.False(t, valid)
+	assert.Nil(t, resolved)
 }
 
 func TestResolveInitGroupExistingOK(t *testing.T) {
@@ -263,7 +860,7 @@ func TestResolveInitGroupExistingOK(t *testing.T) {
 	mdi.On("UpsertGroup", pm.ctx, mock.Anything, true).Return(nil)
 	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(&fftypes.Group{}, nil)
 
-	_, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+	_, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:        fftypes.NewUUID(),
 			Namespace: "ns1",
@@ -273,6 +870,7 @@ func TestResolveInitGroupExistingOK(t *testing.T) {
 		},
 	})
 	assert.NoError(t, err)
+	assert.True(t, valid)
 }
 
 func TestResolveInitGroupExistingFail(t *testing.T) {
@@ -282,7 +880,7 @@ func TestResolveInitGroupExistingFail(t *testing.T) {
 	mdi := pm.database.(*databasemocks.Plugin)
 	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
 
-	_, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+	_, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:        fftypes.NewUUID(),
 			Namespace: "ns1",
@@ -292,6 +890,7 @@ func TestResolveInitGroupExistingFail(t *testing.T) {
 		},
 	})
 	assert.EqualError(t, err, "pop")
+	assert.False(t, valid)
 }
 
 func TestResolveInitGroupExistingNotFound(t *testing.T) {
@@ -301,7 +900,7 @@ func TestResolveInitGroupExistingNotFound(t *testing.T) {
 	mdi := pm.database.(*databasemocks.Plugin)
 	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(nil, nil)
 
-	group, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
+	group, valid, err := pm.ResolveInitGroup(pm.ctx, &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			ID:        fftypes.NewUUID(),
 			Namespace: "ns1",
@@ -311,6 +910,7 @@ func TestResolveInitGroupExistingNotFound(t *testing.T) {
 		},
 	})
 	assert.NoError(t, err)
+	assert.True(t, valid)
 	assert.Nil(t, group)
 }
 
@@ -379,6 +979,42 @@ func TestGetGroupNodesCache(t *testing.T) {
 	assert.Equal(t, *group.Hash, *g.Hash)
 }
 
+func TestGroupCacheStatsAndFlush(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	node1 := fftypes.NewUUID()
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Members: fftypes.Members{
+				&fftypes.Member{Node: node1},
+			},
+		},
+	}
+	group.Seal()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(group, nil).Twice()
+	mdi.On("GetNodeByID", pm.ctx, mock.Anything).Return(&fftypes.Node{
+		ID: node1,
+	}, nil).Twice()
+
+	_, _, err := pm.getGroupNodes(pm.ctx, group.Hash)
+	assert.NoError(t, err)
+	_, _, err = pm.getGroupNodes(pm.ctx, group.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, &fftypes.CacheStats{Size: 1, Hits: 1, Misses: 1}, pm.GroupCacheStats())
+
+	// After a flush we go back to the database
+	pm.FlushGroupCache()
+	assert.Equal(t, 0, pm.GroupCacheStats().Size)
+	_, _, err = pm.getGroupNodes(pm.ctx, group.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, &fftypes.CacheStats{Size: 1, Hits: 1, Misses: 2}, pm.GroupCacheStats())
+
+	mdi.AssertExpectations(t)
+}
+
 func TestGetGroupNodesGetGroupFail(t *testing.T) {
 	pm, cancel := newTestPrivateMessaging(t)
 	defer cancel()
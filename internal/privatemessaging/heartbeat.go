@@ -0,0 +1,219 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// nodeHeartbeat is the in-memory heartbeat state for a node we send heartbeats to
+type nodeHeartbeat struct {
+	status        fftypes.HeartbeatStatus
+	lastSent      *fftypes.FFTime
+	lastHeartbeat *fftypes.FFTime
+	awaitingSince *fftypes.FFTime // when we sent the first heartbeat that has not had a reply
+}
+
+func (pm *privateMessaging) heartbeatLoop() {
+	ticker := time.NewTicker(pm.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := pm.sendHeartbeats(pm.ctx); err != nil {
+				log.L(pm.ctx).Errorf("Failed to send heartbeats: %s", err)
+			}
+		case <-pm.ctx.Done():
+			log.L(pm.ctx).Debugf("Heartbeat loop exiting")
+			return
+		}
+	}
+}
+
+// heartbeatNodes returns the other nodes that are members of any group we know of
+func (pm *privateMessaging) heartbeatNodes(ctx context.Context) ([]*fftypes.Node, error) {
+	localNodeID, err := pm.resolveLocalNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, _, err := pm.database.GetGroups(ctx, database.GroupQueryFactory.NewFilter(ctx).And())
+	if err != nil {
+		return nil, err
+	}
+	nodeIDs := make(map[fftypes.UUID]bool)
+	var nodes []*fftypes.Node
+	for _, group := range groups {
+		for _, member := range group.Members {
+			if member.Node == nil || member.Node.Equals(localNodeID) || nodeIDs[*member.Node] {
+				continue
+			}
+			nodeIDs[*member.Node] = true
+			node, err := pm.database.GetNodeByID(ctx, member.Node)
+			if err != nil {
+				return nil, err
+			}
+			if node != nil {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	return nodes, nil
+}
+
+func (pm *privateMessaging) sendHeartbeats(ctx context.Context) error {
+	nodes, err := pm.heartbeatNodes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err := pm.checkHeartbeatMissed(ctx, node); err != nil {
+			return err
+		}
+		if err := pm.sendHeartbeat(ctx, node, nil); err != nil {
+			// Not sending is the same as not getting a reply, so we carry on to the other nodes
+			log.L(ctx).Warnf("Failed to send heartbeat to node %s: %s", node.ID, err)
+			continue
+		}
+		pm.heartbeatMux.Lock()
+		hb := pm.getNodeHeartbeat(node.ID)
+		hb.lastSent = fftypes.Now()
+		if hb.awaitingSince == nil {
+			hb.awaitingSince = hb.lastSent
+		}
+		pm.heartbeatMux.Unlock()
+	}
+	return nil
+}
+
+// checkHeartbeatMissed emits a heartbeat missed event, the first time a node fails to reply within
+// twice the heartbeat interval
+func (pm *privateMessaging) checkHeartbeatMissed(ctx context.Context, node *fftypes.Node) error {
+	pm.heartbeatMux.Lock()
+	hb := pm.getNodeHeartbeat(node.ID)
+	missed := hb.status != fftypes.HeartbeatStatusMissed &&
+		hb.awaitingSince != nil &&
+		time.Since(hb.awaitingSince.Time()) > 2*pm.heartbeatInterval
+	if missed {
+		hb.status = fftypes.HeartbeatStatusMissed
+	}
+	pm.heartbeatMux.Unlock()
+
+	if missed {
+		log.L(ctx).Warnf("Heartbeat missed for node %s (%s)", node.ID, node.Name)
+		event := fftypes.NewEvent(fftypes.EventTypeHeartbeatMissed, fftypes.SystemNamespace, node.ID)
+		return pm.database.InsertEvent(ctx, event)
+	}
+	return nil
+}
+
+// sendHeartbeat sends a heartbeat to a node, or a reply to a heartbeat if replyTo is set
+func (pm *privateMessaging) sendHeartbeat(ctx context.Context, node *fftypes.Node, replyTo *fftypes.UUID) error {
+	payload, _ := json.Marshal(&fftypes.TransportWrapper{
+		Type: fftypes.TransportPayloadTypeHeartbeat,
+		Message: &fftypes.Message{
+			Header: fftypes.MessageHeader{
+				ID:      fftypes.NewUUID(),
+				CID:     replyTo,
+				Type:    fftypes.MessageTypePrivate,
+				Author:  pm.localOrgIdentity,
+				Created: fftypes.Now(),
+			},
+		},
+	})
+	_, err := pm.exchange.SendMessage(ctx, node.DX.Peer, payload)
+	return err
+}
+
+// HeartbeatReceived handles a heartbeat from another node. Heartbeats get a reply, and replies
+// update the status of the node.
+func (pm *privateMessaging) HeartbeatReceived(ctx context.Context, node *fftypes.Node, heartbeat *fftypes.Message) error {
+	if heartbeat.Header.CID == nil {
+		return pm.sendHeartbeat(ctx, node, heartbeat.Header.ID)
+	}
+
+	pm.heartbeatMux.Lock()
+	defer pm.heartbeatMux.Unlock()
+	hb := pm.getNodeHeartbeat(node.ID)
+	hb.status = fftypes.HeartbeatStatusOK
+	hb.lastHeartbeat = fftypes.Now()
+	hb.awaitingSince = nil
+	return nil
+}
+
+// GetNodeHeartbeat returns the heartbeat status of a node, which must be a member of a group in the namespace
+func (pm *privateMessaging) GetNodeHeartbeat(ctx context.Context, ns, id string) (*fftypes.NodeHeartbeat, error) {
+	nodeID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	node, err := pm.database.GetNodeByID(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+	member, err := pm.isGroupMemberInNamespace(ctx, ns, node.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, i18n.NewError(ctx, i18n.Msg404NotFound)
+	}
+
+	pm.heartbeatMux.Lock()
+	defer pm.heartbeatMux.Unlock()
+	hb := pm.getNodeHeartbeat(node.ID)
+	return &fftypes.NodeHeartbeat{
+		Node:          node.ID,
+		Status:        hb.status,
+		LastSent:      hb.lastSent,
+		LastHeartbeat: hb.lastHeartbeat,
+	}, nil
+}
+
+func (pm *privateMessaging) isGroupMemberInNamespace(ctx context.Context, ns string, nodeID *fftypes.UUID) (bool, error) {
+	groups, _, err := pm.database.GetGroups(ctx, database.GroupQueryFactory.NewFilter(ctx).Eq("namespace", ns))
+	if err != nil {
+		return false, err
+	}
+	for _, group := range groups {
+		for _, member := range group.Members {
+			if member.Node.Equals(nodeID) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// getNodeHeartbeat must be called with the heartbeatMux held
+func (pm *privateMessaging) getNodeHeartbeat(nodeID *fftypes.UUID) *nodeHeartbeat {
+	hb, ok := pm.heartbeats[*nodeID]
+	if !ok {
+		hb = &nodeHeartbeat{status: fftypes.HeartbeatStatusUnknown}
+		pm.heartbeats[*nodeID] = hb
+	}
+	return hb
+}
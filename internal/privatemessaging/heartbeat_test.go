@@ -0,0 +1,344 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestHeartbeatGroup(pm *privateMessaging) (*fftypes.Node, *fftypes.Group) {
+	pm.localNodeID = fftypes.NewUUID()
+	node := &fftypes.Node{ID: fftypes.NewUUID(), Name: "node2", DX: fftypes.DXInfo{Peer: "peer2"}}
+	group := &fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{
+			Members: fftypes.Members{
+				{Identity: "localorg", Node: pm.localNodeID},
+				{Identity: "org2", Node: node.ID},
+				{Identity: "org3", Node: node.ID},
+			},
+		},
+	}
+	return node, group
+}
+
+func TestStartHeartbeatLoop(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.heartbeatInterval = 1 * time.Millisecond
+	node, group := newTestHeartbeatGroup(pm)
+
+	sent := make(chan struct{})
+	var once sync.Once
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{group}, nil, nil)
+	mdi.On("GetNodeByID", pm.ctx, node.ID).Return(node, nil)
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("Start").Return(nil)
+	mdx.On("SendMessage", pm.ctx, "peer2", mock.Anything).Return("tracking1", nil).Run(func(args mock.Arguments) {
+		var wrapper fftypes.TransportWrapper
+		err := json.Unmarshal(args[2].([]byte), &wrapper)
+		assert.NoError(t, err)
+		assert.Equal(t, fftypes.TransportPayloadTypeHeartbeat, wrapper.Type)
+		assert.Equal(t, fftypes.MessageTypePrivate, wrapper.Message.Header.Type)
+		assert.Equal(t, "localorg", wrapper.Message.Header.Author)
+		assert.Nil(t, wrapper.Message.Header.CID)
+		once.Do(func() { close(sent) })
+	})
+
+	err := pm.Start()
+	assert.NoError(t, err)
+	<-sent
+	cancel()
+}
+
+func TestStartHeartbeatLoopFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.heartbeatInterval = 1 * time.Millisecond
+	pm.localNodeID = fftypes.NewUUID()
+
+	called := make(chan struct{})
+	var once sync.Once
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		once.Do(func() { close(called) })
+	})
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("Start").Return(nil)
+
+	err := pm.Start()
+	assert.NoError(t, err)
+	<-called
+	cancel()
+}
+
+func TestSendHeartbeatsMissed(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.heartbeatInterval = 1 * time.Second
+	node, group := newTestHeartbeatGroup(pm)
+
+	awaitingSince := fftypes.FFTime(time.Now().Add(-3 * time.Second))
+	pm.heartbeats[*node.ID] = &nodeHeartbeat{
+		status:        fftypes.HeartbeatStatusOK,
+		awaitingSince: &awaitingSince,
+	}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{group}, nil, nil)
+	mdi.On("GetNodeByID", pm.ctx, node.ID).Return(node, nil)
+	mdi.On("InsertEvent", pm.ctx, mock.MatchedBy(func(event *fftypes.Event) bool {
+		return event.Type == fftypes.EventTypeHeartbeatMissed &&
+			event.Namespace == fftypes.SystemNamespace &&
+			event.Reference.Equals(node.ID)
+	})).Return(nil).Once()
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("SendMessage", pm.ctx, "peer2", mock.Anything).Return("", fmt.Errorf("pop"))
+
+	err := pm.sendHeartbeats(pm.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.HeartbeatStatusMissed, pm.heartbeats[*node.ID].status)
+
+	// The event is only emitted once
+	err = pm.sendHeartbeats(pm.ctx)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestSendHeartbeatsAwaitingReply(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.heartbeatInterval = 1 * time.Minute
+	node, group := newTestHeartbeatGroup(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{group}, nil, nil)
+	mdi.On("GetNodeByID", pm.ctx, node.ID).Return(node, nil)
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("SendMessage", pm.ctx, "peer2", mock.Anything).Return("tracking1", nil)
+
+	err := pm.sendHeartbeats(pm.ctx)
+	assert.NoError(t, err)
+	hb := pm.heartbeats[*node.ID]
+	assert.Equal(t, fftypes.HeartbeatStatusUnknown, hb.status)
+	assert.NotNil(t, hb.lastSent)
+	firstSent := hb.awaitingSince
+
+	err = pm.sendHeartbeats(pm.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, firstSent, hb.awaitingSince)
+	mdx.AssertNumberOfCalls(t, "SendMessage", 2)
+}
+
+func TestSendHeartbeatsInsertEventFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.heartbeatInterval = 1 * time.Second
+	node, group := newTestHeartbeatGroup(pm)
+
+	awaitingSince := fftypes.FFTime(time.Now().Add(-3 * time.Second))
+	pm.heartbeats[*node.ID] = &nodeHeartbeat{awaitingSince: &awaitingSince}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{group}, nil, nil)
+	mdi.On("GetNodeByID", pm.ctx, node.ID).Return(node, nil)
+	mdi.On("InsertEvent", pm.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := pm.sendHeartbeats(pm.ctx)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestSendHeartbeatsLocalNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetNodes", pm.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := pm.sendHeartbeats(pm.ctx)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestSendHeartbeatsGetNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	node, group := newTestHeartbeatGroup(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{group}, nil, nil)
+	mdi.On("GetNodeByID", pm.ctx, node.ID).Return(nil, fmt.Errorf("pop"))
+
+	err := pm.sendHeartbeats(pm.ctx)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestSendHeartbeatsNodeNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	node, group := newTestHeartbeatGroup(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{group}, nil, nil)
+	mdi.On("GetNodeByID", pm.ctx, node.ID).Return(nil, nil)
+
+	err := pm.sendHeartbeats(pm.ctx)
+	assert.NoError(t, err)
+}
+
+func TestHeartbeatReceivedSendsReply(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	node := &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer2"}}
+	heartbeatID := fftypes.NewUUID()
+
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("SendMessage", pm.ctx, "peer2", mock.MatchedBy(func(payload []byte) bool {
+		var wrapper fftypes.TransportWrapper
+		err := json.Unmarshal(payload, &wrapper)
+		return err == nil && wrapper.Type == fftypes.TransportPayloadTypeHeartbeat && wrapper.Message.Header.CID.Equals(heartbeatID)
+	})).Return("tracking1", nil)
+
+	err := pm.HeartbeatReceived(pm.ctx, node, &fftypes.Message{
+		Header: fftypes.MessageHeader{ID: heartbeatID},
+	})
+	assert.NoError(t, err)
+	mdx.AssertExpectations(t)
+}
+
+func TestHeartbeatReplyReceived(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	node := &fftypes.Node{ID: fftypes.NewUUID()}
+
+	awaitingSince := fftypes.Now()
+	pm.heartbeats[*node.ID] = &nodeHeartbeat{
+		status:        fftypes.HeartbeatStatusMissed,
+		lastSent:      awaitingSince,
+		awaitingSince: awaitingSince,
+	}
+
+	err := pm.HeartbeatReceived(pm.ctx, node, &fftypes.Message{
+		Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), CID: fftypes.NewUUID()},
+	})
+	assert.NoError(t, err)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetNodeByID", pm.ctx, node.ID).Return(node, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return(testHeartbeatGroups(node.ID), nil, nil)
+	hb, err := pm.GetNodeHeartbeat(pm.ctx, "ns1", node.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.HeartbeatStatusOK, hb.Status)
+	assert.Equal(t, awaitingSince, hb.LastSent)
+	assert.NotNil(t, hb.LastHeartbeat)
+	assert.Nil(t, pm.heartbeats[*node.ID].awaitingSince)
+}
+
+func TestGetNodeHeartbeatUnknown(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	node := &fftypes.Node{ID: fftypes.NewUUID()}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetNodeByID", pm.ctx, node.ID).Return(node, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return(testHeartbeatGroups(node.ID), nil, nil)
+
+	hb, err := pm.GetNodeHeartbeat(pm.ctx, "ns1", node.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, *node.ID, *hb.Node)
+	assert.Equal(t, fftypes.HeartbeatStatusUnknown, hb.Status)
+	assert.Nil(t, hb.LastHeartbeat)
+}
+
+func TestGetNodeHeartbeatBadID(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.GetNodeHeartbeat(pm.ctx, "ns1", "!uuid")
+	assert.Regexp(t, "FF10142", err)
+}
+
+func TestGetNodeHeartbeatGetNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetNodeByID", pm.ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.GetNodeHeartbeat(pm.ctx, "ns1", fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetNodeHeartbeatNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetNodeByID", pm.ctx, mock.Anything).Return(nil, nil)
+
+	_, err := pm.GetNodeHeartbeat(pm.ctx, "ns1", fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func testHeartbeatGroups(nodeID *fftypes.UUID) []*fftypes.Group {
+	return []*fftypes.Group{
+		{GroupIdentity: fftypes.GroupIdentity{Namespace: "ns1", Members: fftypes.Members{
+			{Identity: "org1", Node: fftypes.NewUUID()},
+			{Identity: "org2", Node: nodeID},
+		}}},
+	}
+}
+
+func TestGetNodeHeartbeatNotInNamespace(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	node := &fftypes.Node{ID: fftypes.NewUUID()}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetNodeByID", pm.ctx, node.ID).Return(node, nil)
+	mdi.On("GetGroups", pm.ctx, mock.MatchedBy(func(f database.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == "namespace == 'ns2'"
+	})).Return(testHeartbeatGroups(fftypes.NewUUID()), nil, nil)
+
+	_, err := pm.GetNodeHeartbeat(pm.ctx, "ns2", node.ID.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetNodeHeartbeatGetGroupsFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	node := &fftypes.Node{ID: fftypes.NewUUID()}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetNodeByID", pm.ctx, node.ID).Return(node, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := pm.GetNodeHeartbeat(pm.ctx, "ns1", node.ID.String())
+	assert.EqualError(t, err, "pop")
+}
@@ -32,6 +32,20 @@ func (pm *privateMessaging) SendMessage(ctx context.Context, ns string, in *ffty
 func (pm *privateMessaging) sendMessageWithID(ctx context.Context, ns string, id *fftypes.UUID, unresolved *fftypes.MessageInOut, resolved *fftypes.Message, waitConfirm bool) (*fftypes.Message, error) {
 	if unresolved != nil {
 		resolved = &unresolved.Message
+		if unresolved.Pinned != nil && !*unresolved.Pinned {
+			// Unpinned messages are sent directly over data exchange, without a transaction
+			resolved.Header.TxType = fftypes.TransactionTypeNone
+		}
+	}
+
+	if resolved.Header.Sensitivity == fftypes.DataSensitivityPublic {
+		return nil, i18n.NewError(ctx, i18n.MsgPublicMessageSentPrivately)
+	}
+
+	if unresolved != nil {
+		if err := pm.resolveForwarded(ctx, ns, resolved); err != nil {
+			return nil, err
+		}
 	}
 
 	resolved.Header.ID = id
@@ -43,6 +57,11 @@ func (pm *privateMessaging) sendMessageWithID(ctx context.Context, ns string, id
 	if resolved.Header.TxType == "" {
 		resolved.Header.TxType = fftypes.TransactionTypeBatchPin
 	}
+	resolved.Pinned = resolved.Header.TxType != fftypes.TransactionTypeNone
+
+	if err := pm.resolveAdditionalGroups(ctx, ns, resolved); err != nil {
+		return nil, err
+	}
 
 	sender, err := pm.identity.Resolve(ctx, resolved.Header.Author)
 	if err != nil {
@@ -70,6 +89,57 @@ func (pm *privateMessaging) sendMessageWithID(ctx context.Context, ns string, id
 	return resolved, err
 }
 
+// resolveForwarded checks a message being relayed refers to a confirmed message in the same namespace.
+// The relayed message is still authored and signed by the local org. The forwardedFrom reference is
+// informational metadata only, and receivers do not treat it as proof of the original author.
+func (pm *privateMessaging) resolveForwarded(ctx context.Context, ns string, msg *fftypes.Message) error {
+	if msg.Header.Forwarded != (msg.Header.ForwardedFrom != nil) {
+		return i18n.NewError(ctx, i18n.MsgForwardedFromRequired)
+	}
+	if !msg.Header.Forwarded {
+		return nil
+	}
+	original, err := pm.database.GetMessageByID(ctx, msg.Header.ForwardedFrom)
+	if err != nil {
+		return err
+	}
+	if original == nil || original.Header.Namespace != ns {
+		return i18n.NewError(ctx, i18n.MsgForwardedNotFound, msg.Header.ForwardedFrom, ns)
+	}
+	if original.Confirmed == nil {
+		return i18n.NewError(ctx, i18n.MsgForwardedNotConfirmed, msg.Header.ForwardedFrom)
+	}
+	return nil
+}
+
+// resolveAdditionalGroups checks the extra groups a message is delivered to all exist in the namespace, and
+// that the author is a member of each of them. These groups are pinned alongside the message group, so are
+// not supported on unpinned messages.
+func (pm *privateMessaging) resolveAdditionalGroups(ctx context.Context, ns string, msg *fftypes.Message) error {
+	if len(msg.Groups) == 0 {
+		return nil
+	}
+	if msg.Header.TxType == fftypes.TransactionTypeNone {
+		return i18n.NewError(ctx, i18n.MsgAdditionalGroupsUnpinned)
+	}
+	for _, g := range msg.Groups {
+		if g.Hash == nil {
+			return i18n.NewError(ctx, i18n.MsgAdditionalGroupNotFound, g.Hash, ns)
+		}
+		group, _, err := pm.groupManager.getGroupNodes(ctx, g.Hash)
+		if err != nil {
+			return err
+		}
+		if group.Namespace != ns {
+			return i18n.NewError(ctx, i18n.MsgAdditionalGroupNotFound, g.Hash, ns)
+		}
+		if !group.Members.Contains(msg.Header.Author) {
+			return i18n.NewError(ctx, i18n.MsgAdditionalGroupNotMember, msg.Header.Author, g.Hash)
+		}
+	}
+	return nil
+}
+
 func (pm *privateMessaging) resolveMessage(ctx context.Context, sender *fftypes.Identity, in *fftypes.MessageInOut) (err error) {
 	// Resolve the member list into a group
 	if err = pm.resolveReceipientList(ctx, sender, in); err != nil {
@@ -78,7 +148,39 @@ func (pm *privateMessaging) resolveMessage(ctx context.Context, sender *fftypes.
 
 	// The data manager is responsible for the heavy lifting of storing/validating all our in-line data elements
 	in.Message.Data, err = pm.data.ResolveInlineDataPrivate(ctx, in.Header.Namespace, in.InlineData)
-	return err
+	if err != nil {
+		return err
+	}
+
+	return pm.validateTargetGroups(ctx, &in.Message)
+}
+
+// validateTargetGroups checks each group the message is sent to is within the maximum group size, and that
+// the message data conforms to the group schema. This happens when the message is sent, so the sender gets
+// the error - rather than the message being stuck when its batch could never be dispatched.
+func (pm *privateMessaging) validateTargetGroups(ctx context.Context, msg *fftypes.Message) error {
+	var data []*fftypes.Data
+	for _, groupHash := range msg.TargetGroups() {
+		group, _, err := pm.groupManager.getGroupNodes(ctx, groupHash)
+		if err != nil {
+			return err
+		}
+		if err = group.ValidateSize(ctx, pm.maxGroupSize); err != nil {
+			return err
+		}
+		if group.Schema == nil {
+			continue
+		}
+		if data == nil {
+			if data, _, err = pm.data.GetMessageData(ctx, msg, true); err != nil {
+				return err
+			}
+		}
+		if err = pm.data.ValidateAgainstDatatype(ctx, msg.Header.Namespace, group.Schema, data); err != nil {
+			return i18n.WrapError(ctx, err, i18n.MsgGroupSchemaMismatch, group.Schema.Name, group.Schema.Version)
+		}
+	}
+	return nil
 }
 
 func (pm *privateMessaging) sendOrWaitMessage(ctx context.Context, msg *fftypes.Message, waitConfirm bool) (*fftypes.Message, error) {
@@ -98,6 +200,7 @@ func (pm *privateMessaging) sendOrWaitMessage(ctx context.Context, msg *fftypes.
 		}
 
 		// Store the message - this asynchronously triggers the next step in process
+		msg.CreatedBy = fftypes.GetCreatedBy(ctx)
 		if err := pm.database.InsertMessageLocal(ctx, msg); err != nil {
 			return nil, err
 		}
@@ -136,7 +239,7 @@ func (pm *privateMessaging) sendUnpinnedMessage(ctx context.Context, message *ff
 
 	id, err := pm.identity.Resolve(ctx, message.Header.Author)
 	if err == nil {
-		err = pm.blockchain.VerifyIdentitySyntax(ctx, id)
+		err = pm.blockchainFor(message.Header.Namespace).VerifyIdentitySyntax(ctx, id)
 	}
 	if err != nil {
 		log.L(ctx).Errorf("Invalid signing identity '%s': %s", message.Header.Author, err)
@@ -158,5 +261,5 @@ func (pm *privateMessaging) sendUnpinnedMessage(ctx context.Context, message *ff
 		return i18n.WrapError(ctx, err, i18n.MsgSerializationFailed)
 	}
 
-	return pm.sendData(ctx, "message", message.Header.ID, message.Header.Group, message.Header.Namespace, nodes, payload, nil, data)
+	return pm.sendData(ctx, "message", message.Header.ID, message.Header.Group, message.Header.Namespace, nodes, payload, nil, data, message.Header.Priority)
 }
@@ -50,6 +50,7 @@ func TestSendConfirmMessageE2EOk(t *testing.T) {
 	}, nil)
 
 	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(&fftypes.Group{}, nil)
 	rag := mdi.On("RunAsGroup", pm.ctx, mock.Anything).Return(nil)
 	rag.RunFn = func(a mock.Arguments) {
 		err := a[1].(func(context.Context) error)(a[0].(context.Context))
@@ -85,7 +86,7 @@ func TestSendConfirmMessageE2EOk(t *testing.T) {
 		}).
 		Return(retMsg, nil).Once()
 	mdi.On("InsertMessageLocal", pm.ctx, mock.MatchedBy(func(msg *fftypes.Message) bool {
-		return msg.Header.ID == requestID
+		return msg.Header.ID == requestID && msg.Pinned // pinned by default
 	})).Return(nil).Once()
 
 	msg, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
@@ -151,7 +152,7 @@ func TestSendUnpinnedMessageE2EOk(t *testing.T) {
 	mdi.On("InsertEvent", pm.ctx, mock.Anything).Return(nil).Once()
 
 	mdx := pm.exchange.(*dataexchangemocks.Plugin)
-	mdx.On("SendMessage", pm.ctx, "peer2-remote", mock.Anything).Return("tracking1", nil).Once()
+	mdx.On("SendMessage", mock.Anything, "peer2-remote", mock.Anything).Return("tracking1", nil).Once()
 
 	msg, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
 		Message: fftypes.Message{
@@ -178,6 +179,88 @@ func TestSendUnpinnedMessageE2EOk(t *testing.T) {
 
 }
 
+func TestSendMessageNotPinnedConfirmedImmediately(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mii := pm.identity.(*identitymocks.Plugin)
+	mii.On("Resolve", pm.ctx, "localorg").Return(&fftypes.Identity{
+		Identifier: "localorg",
+		OnChain:    "0x12345",
+	}, nil)
+
+	dataID := fftypes.NewUUID()
+	groupID := fftypes.NewRandB32()
+	nodeID1 := fftypes.NewUUID()
+	nodeID2 := fftypes.NewUUID()
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineDataPrivate", pm.ctx, "ns1", mock.Anything).Return(fftypes.DataRefs{
+		{ID: dataID, Hash: fftypes.NewRandB32()},
+	}, nil)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return([]*fftypes.Data{
+		{ID: dataID, Value: fftypes.Byteable(`{"some": "data"}`)},
+	}, true, nil).Once()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	rag := mdi.On("RunAsGroup", pm.ctx, mock.Anything).Return(nil)
+	rag.RunFn = func(a mock.Arguments) {
+		err := a[1].(func(context.Context) error)(a[0].(context.Context))
+		rag.ReturnArguments = mock.Arguments{err}
+	}
+	mdi.On("GetGroupByHash", pm.ctx, groupID).Return(&fftypes.Group{
+		Hash: groupID,
+		GroupIdentity: fftypes.GroupIdentity{
+			Members: fftypes.Members{
+				{Node: nodeID1, Identity: "localorg"},
+				{Node: nodeID2, Identity: "remoteorg"},
+			},
+		},
+	}, nil).Once()
+	mdi.On("GetNodeByID", pm.ctx, nodeID1).Return(&fftypes.Node{
+		ID: nodeID1, Name: "node1", Owner: "localorg", DX: fftypes.DXInfo{Peer: "peer1-local"},
+	}, nil).Once()
+	mdi.On("GetNodeByID", pm.ctx, nodeID2).Return(&fftypes.Node{
+		ID: nodeID2, Name: "node2", Owner: "org1", DX: fftypes.DXInfo{Peer: "peer2-remote"},
+	}, nil).Once()
+	mdi.On("InsertMessageLocal", pm.ctx, mock.Anything).Return(nil).Once()
+	mdi.On("InsertEvent", pm.ctx, mock.MatchedBy(func(event *fftypes.Event) bool {
+		return event.Type == fftypes.EventTypeMessageConfirmed
+	})).Return(nil).Once()
+
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("SendMessage", mock.Anything, "peer2-remote", mock.Anything).Return("tracking1", nil).Once()
+
+	pinned := false
+	msg, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Group: groupID,
+			},
+		},
+		Pinned: &pinned,
+		InlineData: fftypes.InlineData{
+			{Value: fftypes.Byteable(`{"some": "data"}`)},
+		},
+		Group: &fftypes.InputGroup{
+			Members: []fftypes.MemberInput{
+				{Identity: "org1"},
+			},
+		},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, *dataID, *msg.Data[0].ID)
+	assert.NotNil(t, msg.Header.Group)
+	assert.Equal(t, fftypes.TransactionTypeNone, msg.Header.TxType)
+	assert.False(t, msg.Pinned)
+	assert.NotNil(t, msg.Confirmed)
+	mdx.AssertExpectations(t)
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+
+}
+
 func TestSendMessageBadIdentity(t *testing.T) {
 
 	pm, cancel := newTestPrivateMessaging(t)
@@ -200,6 +283,163 @@ func TestSendMessageBadIdentity(t *testing.T) {
 
 }
 
+func TestSendMessagePublicSensitivity(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Sensitivity: fftypes.DataSensitivityPublic,
+			},
+		},
+		Group: &fftypes.InputGroup{
+			Members: []fftypes.MemberInput{
+				{Identity: "org1"},
+			},
+		},
+	}, false)
+	assert.Regexp(t, "FF10283", err)
+
+}
+
+func TestSendForwardedMessageAuthoredByLocalOrg(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	originalID := fftypes.NewUUID()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, originalID).Return(&fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:        originalID,
+			Namespace: "ns1",
+			Author:    "org2",
+		},
+		Confirmed: fftypes.Now(),
+	}, nil)
+
+	// The local org signs the relayed message, not the original author
+	mii := pm.identity.(*identitymocks.Plugin)
+	mii.On("Resolve", pm.ctx, "localorg").Return(nil, fmt.Errorf("pop"))
+
+	in := &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Forwarded:     true,
+				ForwardedFrom: originalID,
+			},
+		},
+		Group: &fftypes.InputGroup{
+			Members: []fftypes.MemberInput{
+				{Identity: "org1"},
+			},
+		},
+	}
+	_, err := pm.SendMessage(pm.ctx, "ns1", in, false)
+	assert.Regexp(t, "FF10206.*pop", err)
+	assert.Equal(t, "localorg", in.Header.Author)
+	assert.Equal(t, originalID, in.Header.ForwardedFrom)
+
+	mdi.AssertExpectations(t)
+	mii.AssertExpectations(t)
+}
+
+func TestSendForwardedMessageMismatchedFields(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Forwarded: true,
+			},
+		},
+	}, false)
+	assert.Regexp(t, "FF10320", err)
+
+	_, err = pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				ForwardedFrom: fftypes.NewUUID(),
+			},
+		},
+	}, false)
+	assert.Regexp(t, "FF10320", err)
+
+}
+
+func TestSendForwardedMessageLookupFail(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Forwarded:     true,
+				ForwardedFrom: fftypes.NewUUID(),
+			},
+		},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+}
+
+func TestSendForwardedMessageNotFound(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	wrongNS := fftypes.NewUUID()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, wrongNS).Return(&fftypes.Message{
+		Header:    fftypes.MessageHeader{Namespace: "ns2"},
+		Confirmed: fftypes.Now(),
+	}, nil)
+	mdi.On("GetMessageByID", pm.ctx, mock.Anything).Return(nil, nil)
+
+	for _, id := range []*fftypes.UUID{wrongNS, fftypes.NewUUID()} {
+		_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+			Message: fftypes.Message{
+				Header: fftypes.MessageHeader{
+					Forwarded:     true,
+					ForwardedFrom: id,
+				},
+			},
+		}, false)
+		assert.Regexp(t, "FF10321", err)
+	}
+
+}
+
+func TestSendForwardedMessageNotConfirmed(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, mock.Anything).Return(&fftypes.Message{
+		Header: fftypes.MessageHeader{Namespace: "ns1"},
+	}, nil)
+
+	_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Forwarded:     true,
+				ForwardedFrom: fftypes.NewUUID(),
+			},
+		},
+	}, false)
+	assert.Regexp(t, "FF10322", err)
+
+}
+
 func TestSendMessageFail(t *testing.T) {
 
 	pm, cancel := newTestPrivateMessaging(t)
@@ -463,6 +703,7 @@ func TestSendUnpinnedMessageInsertFail(t *testing.T) {
 	}, nil)
 
 	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(&fftypes.Group{}, nil)
 	rag := mdi.On("RunAsGroup", pm.ctx, mock.Anything).Return(nil)
 	rag.RunFn = func(a mock.Arguments) {
 		err := a[1].(func(context.Context) error)(a[0].(context.Context))
@@ -498,50 +739,19 @@ func TestSendUnpinnedMessageResolveGroupFail(t *testing.T) {
 	pm, cancel := newTestPrivateMessaging(t)
 	defer cancel()
 
-	mii := pm.identity.(*identitymocks.Plugin)
-	mii.On("Resolve", pm.ctx, "localorg").Return(&fftypes.Identity{
-		Identifier: "localorg",
-		OnChain:    "0x12345",
-	}, nil)
-
-	dataID := fftypes.NewUUID()
 	groupID := fftypes.NewRandB32()
-	mdm := pm.data.(*datamocks.Manager)
-	mdm.On("ResolveInlineDataPrivate", pm.ctx, "ns1", mock.Anything).Return(fftypes.DataRefs{
-		{ID: dataID, Hash: fftypes.NewRandB32()},
-	}, nil)
-
 	mdi := pm.database.(*databasemocks.Plugin)
-	rag := mdi.On("RunAsGroup", pm.ctx, mock.Anything).Return(nil)
-	rag.RunFn = func(a mock.Arguments) {
-		err := a[1].(func(context.Context) error)(a[0].(context.Context))
-		rag.ReturnArguments = mock.Arguments{err}
-	}
 	mdi.On("GetGroupByHash", pm.ctx, groupID).Return(nil, fmt.Errorf("pop")).Once()
 	mdi.On("InsertMessageLocal", pm.ctx, mock.Anything).Return(nil).Once()
 
-	mdx := pm.exchange.(*dataexchangemocks.Plugin)
-	mdx.On("SendMessage", pm.ctx, "peer2-remote", mock.Anything).Return("tracking1", nil).Once()
-
-	_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
-		Message: fftypes.Message{
-			Header: fftypes.MessageHeader{
-				TxType: fftypes.TransactionTypeNone,
-				Group:  groupID,
-			},
-		},
-		InlineData: fftypes.InlineData{
-			{Value: fftypes.Byteable(`{"some": "data"}`)},
-		},
-		Group: &fftypes.InputGroup{
-			Members: []fftypes.MemberInput{
-				{Identity: "org1"},
-			},
+	_, err := pm.sendOrWaitMessage(pm.ctx, &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			TxType: fftypes.TransactionTypeNone,
+			Group:  groupID,
 		},
 	}, false)
 	assert.EqualError(t, err, "pop")
 
-	mdm.AssertExpectations(t)
 	mdi.AssertExpectations(t)
 
 }
@@ -594,7 +804,7 @@ func TestSendUnpinnedMessageEventFail(t *testing.T) {
 	mdi.On("InsertEvent", pm.ctx, mock.Anything).Return(fmt.Errorf("pop")).Once()
 
 	mdx := pm.exchange.(*dataexchangemocks.Plugin)
-	mdx.On("SendMessage", pm.ctx, "peer2-remote", mock.Anything).Return("tracking1", nil).Once()
+	mdx.On("SendMessage", mock.Anything, "peer2-remote", mock.Anything).Return("tracking1", nil).Once()
 
 	_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
 		Message: fftypes.Message{
@@ -618,3 +828,217 @@ func TestSendUnpinnedMessageEventFail(t *testing.T) {
 	mdi.AssertExpectations(t)
 
 }
+
+func TestSendMessageAdditionalGroups(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group2 := &fftypes.Group{
+		Hash:          fftypes.NewRandB32(),
+		GroupIdentity: fftypes.GroupIdentity{Namespace: "ns1", Members: fftypes.Members{{Identity: "localorg"}}},
+	}
+	group3 := &fftypes.Group{
+		Hash:          fftypes.NewRandB32(),
+		GroupIdentity: fftypes.GroupIdentity{Namespace: "ns1", Members: fftypes.Members{{Identity: "localorg"}}},
+	}
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetNodeByID", pm.ctx, mock.Anything).Return(&fftypes.Node{ID: fftypes.NewUUID()}, nil)
+	mdi.On("GetGroupByHash", pm.ctx, group2.Hash).Return(group2, nil)
+	mdi.On("GetGroupByHash", pm.ctx, group3.Hash).Return(group3, nil)
+
+	mii := pm.identity.(*identitymocks.Plugin)
+	mii.On("Resolve", pm.ctx, "localorg").Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Groups: fftypes.GroupRefs{{Hash: group2.Hash}, {Hash: group3.Hash}},
+		},
+		Group: &fftypes.InputGroup{
+			Members: []fftypes.MemberInput{
+				{Identity: "org1"},
+			},
+		},
+	}, false)
+	assert.Regexp(t, "FF10206.*pop", err)
+
+	mdi.AssertExpectations(t)
+	mii.AssertExpectations(t)
+}
+
+func TestSendMessageAdditionalGroupsNotMember(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group2 := &fftypes.Group{
+		Hash:          fftypes.NewRandB32(),
+		GroupIdentity: fftypes.GroupIdentity{Namespace: "ns1", Members: fftypes.Members{{Identity: "org1"}}},
+	}
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetNodeByID", pm.ctx, mock.Anything).Return(&fftypes.Node{ID: fftypes.NewUUID()}, nil)
+	mdi.On("GetGroupByHash", pm.ctx, group2.Hash).Return(group2, nil)
+
+	_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Groups: fftypes.GroupRefs{{Hash: group2.Hash}},
+		},
+	}, false)
+	assert.Regexp(t, "FF10354.*localorg", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestSendMessageAdditionalGroupsUnpinned(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	pinned := false
+	_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Groups: fftypes.GroupRefs{{Hash: fftypes.NewRandB32()}},
+		},
+		Pinned: &pinned,
+	}, false)
+	assert.Regexp(t, "FF10350", err)
+
+}
+
+func TestSendMessageAdditionalGroupsNotFound(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	wrongNS := &fftypes.Group{
+		Hash:          fftypes.NewRandB32(),
+		GroupIdentity: fftypes.GroupIdentity{Namespace: "ns2"},
+	}
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, wrongNS.Hash).Return(wrongNS, nil)
+	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(nil, nil)
+
+	for _, g := range []fftypes.GroupRef{{Hash: wrongNS.Hash}, {Hash: nil}} {
+		_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+			Message: fftypes.Message{
+				Groups: fftypes.GroupRefs{g},
+			},
+		}, false)
+		assert.Regexp(t, "FF10351", err)
+	}
+
+	_, err := pm.SendMessage(pm.ctx, "ns1", &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Groups: fftypes.GroupRefs{{Hash: fftypes.NewRandB32()}},
+		},
+	}, false)
+	assert.Regexp(t, "FF10226", err)
+
+}
+
+func TestResolveMessageGroupSchema(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	groupID := fftypes.NewRandB32()
+	schema := &fftypes.DatatypeRef{Name: "customer", Version: "0.0.1"}
+	dataRefs := fftypes.DataRefs{{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()}}
+	data := []*fftypes.Data{{ID: dataRefs[0].ID, Value: fftypes.Byteable(`{"field1":"value1"}`)}}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdm := pm.data.(*datamocks.Manager)
+	mdi.On("GetGroupByHash", pm.ctx, groupID).Return(&fftypes.Group{
+		Hash:          groupID,
+		GroupIdentity: fftypes.GroupIdentity{Namespace: "ns1", Schema: schema},
+	}, nil)
+	mdm.On("ResolveInlineDataPrivate", pm.ctx, "ns1", mock.Anything).Return(dataRefs, nil)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return(data, true, nil)
+	mdm.On("ValidateAgainstDatatype", pm.ctx, "ns1", schema, data).Return(nil).Once()
+	mdm.On("ValidateAgainstDatatype", pm.ctx, "ns1", schema, data).Return(fmt.Errorf("pop")).Once()
+
+	in := &fftypes.MessageInOut{
+		Message: fftypes.Message{Header: fftypes.MessageHeader{Namespace: "ns1", Group: groupID}},
+	}
+
+	// Conformant
+	err := pm.resolveMessage(pm.ctx, &fftypes.Identity{}, in)
+	assert.NoError(t, err)
+
+	// Non-conformant
+	err = pm.resolveMessage(pm.ctx, &fftypes.Identity{}, in)
+	assert.Regexp(t, "FF10288.*customer/0.0.1.*pop", err)
+
+	mdm.AssertExpectations(t)
+}
+
+func TestResolveMessageGroupSchemaGroupFail(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdm := pm.data.(*datamocks.Manager)
+	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	mdm.On("ResolveInlineDataPrivate", pm.ctx, "ns1", mock.Anything).Return(fftypes.DataRefs{}, nil)
+
+	err := pm.resolveMessage(pm.ctx, &fftypes.Identity{}, &fftypes.MessageInOut{
+		Message: fftypes.Message{Header: fftypes.MessageHeader{Namespace: "ns1", Group: fftypes.NewRandB32()}},
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestResolveMessageGroupSchemaGetDataFail(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	groupID := fftypes.NewRandB32()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdm := pm.data.(*datamocks.Manager)
+	mdi.On("GetGroupByHash", pm.ctx, groupID).Return(&fftypes.Group{
+		Hash:          groupID,
+		GroupIdentity: fftypes.GroupIdentity{Namespace: "ns1", Schema: &fftypes.DatatypeRef{Name: "customer", Version: "0.0.1"}},
+	}, nil)
+	mdm.On("ResolveInlineDataPrivate", pm.ctx, "ns1", mock.Anything).Return(fftypes.DataRefs{}, nil)
+	mdm.On("GetMessageData", pm.ctx, mock.Anything, true).Return(nil, false, fmt.Errorf("pop"))
+
+	err := pm.resolveMessage(pm.ctx, &fftypes.Identity{}, &fftypes.MessageInOut{
+		Message: fftypes.Message{Header: fftypes.MessageHeader{Namespace: "ns1", Group: groupID}},
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestResolveMessageGroupTooLarge(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.maxGroupSize = 1
+
+	groupID := fftypes.NewRandB32()
+	node1 := fftypes.NewUUID()
+	node2 := fftypes.NewUUID()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdm := pm.data.(*datamocks.Manager)
+	mdi.On("GetGroupByHash", pm.ctx, groupID).Return(&fftypes.Group{
+		Hash: groupID,
+		GroupIdentity: fftypes.GroupIdentity{
+			Name: "group1",
+			Members: fftypes.Members{
+				{Identity: "org1", Node: node1},
+				{Identity: "org2", Node: node2},
+			},
+		},
+	}, nil)
+	mdi.On("GetNodeByID", pm.ctx, node1).Return(&fftypes.Node{ID: node1}, nil)
+	mdi.On("GetNodeByID", pm.ctx, node2).Return(&fftypes.Node{ID: node2}, nil)
+	mdm.On("ResolveInlineDataPrivate", pm.ctx, "ns1", mock.Anything).Return(fftypes.DataRefs{}, nil)
+
+	err := pm.resolveMessage(pm.ctx, &fftypes.Identity{}, &fftypes.MessageInOut{
+		Message: fftypes.Message{Header: fftypes.MessageHeader{Namespace: "ns1", Group: groupID}},
+	})
+	assert.Regexp(t, "FF10280", err)
+
+	mdi.AssertExpectations(t)
+}
@@ -19,6 +19,9 @@ package privatemessaging
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/hyperledger/firefly/internal/batch"
 	"github.com/hyperledger/firefly/internal/batchpin"
@@ -26,6 +29,7 @@ import (
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/i18n"
 	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/internal/nsplugins"
 	"github.com/hyperledger/firefly/internal/retry"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/pkg/blockchain"
@@ -42,6 +46,11 @@ type Manager interface {
 	Start() error
 	SendMessage(ctx context.Context, ns string, in *fftypes.MessageInOut, waitConfirm bool) (out *fftypes.Message, err error)
 	RequestReply(ctx context.Context, ns string, request *fftypes.MessageInOut) (reply *fftypes.MessageInOut, err error)
+	GetDeliveryStatus(ctx context.Context, ns, msgID string) ([]*fftypes.DeliveryStatus, error)
+	FlushGroupCache()
+	GroupCacheStats() *fftypes.CacheStats
+	HeartbeatReceived(ctx context.Context, node *fftypes.Node, heartbeat *fftypes.Message) error
+	GetNodeHeartbeat(ctx context.Context, ns, id string) (*fftypes.NodeHeartbeat, error)
 }
 
 type privateMessaging struct {
@@ -52,6 +61,7 @@ type privateMessaging struct {
 	identity             identity.Plugin
 	exchange             dataexchange.Plugin
 	blockchain           blockchain.Plugin
+	nsPlugins            nsplugins.Overrides
 	batch                batch.Manager
 	data                 data.Manager
 	syncasync            syncasync.Bridge
@@ -61,9 +71,13 @@ type privateMessaging struct {
 	localNodeID          *fftypes.UUID // lookup and cached on first use, as might not be registered at startup
 	localOrgIdentity     string
 	opCorrelationRetries int
+	opTimeout            time.Duration
+	heartbeatInterval    time.Duration
+	heartbeatMux         sync.Mutex
+	heartbeats           map[fftypes.UUID]*nodeHeartbeat
 }
 
-func NewPrivateMessaging(ctx context.Context, di database.Plugin, ii identity.Plugin, dx dataexchange.Plugin, bi blockchain.Plugin, ba batch.Manager, dm data.Manager, sa syncasync.Bridge, bp batchpin.Submitter) (Manager, error) {
+func NewPrivateMessaging(ctx context.Context, di database.Plugin, ii identity.Plugin, dx dataexchange.Plugin, bi blockchain.Plugin, np nsplugins.Overrides, ba batch.Manager, dm data.Manager, sa syncasync.Bridge, bp batchpin.Submitter) (Manager, error) {
 	if di == nil || ii == nil || dx == nil || bi == nil || ba == nil || dm == nil {
 		return nil, i18n.NewError(ctx, i18n.MsgInitializationNilDepError)
 	}
@@ -74,6 +88,7 @@ func NewPrivateMessaging(ctx context.Context, di database.Plugin, ii identity.Pl
 		identity:         ii,
 		exchange:         dx,
 		blockchain:       bi,
+		nsPlugins:        np,
 		batch:            ba,
 		data:             dm,
 		syncasync:        sa,
@@ -84,6 +99,7 @@ func NewPrivateMessaging(ctx context.Context, di database.Plugin, ii identity.Pl
 			database:      di,
 			data:          dm,
 			groupCacheTTL: config.GetDuration(config.GroupCacheTTL),
+			maxGroupSize:  config.GetInt(config.PrivateMessagingGroupMaxSize),
 		},
 		retry: retry.Retry{
 			InitialDelay: config.GetDuration(config.PrivateMessagingRetryInitDelay),
@@ -91,6 +107,9 @@ func NewPrivateMessaging(ctx context.Context, di database.Plugin, ii identity.Pl
 			Factor:       config.GetFloat64(config.PrivateMessagingRetryFactor),
 		},
 		opCorrelationRetries: config.GetInt(config.PrivateMessagingOpCorrelationRetries),
+		opTimeout:            config.GetDuration(config.OperationsDefaultTimeout),
+		heartbeatInterval:    config.GetDuration(config.NodeHeartbeatInterval),
+		heartbeats:           make(map[fftypes.UUID]*nodeHeartbeat),
 	}
 	pm.groupManager.groupCache = ccache.New(
 		// We use a LRU cache with a size-aware max
@@ -113,11 +132,19 @@ func NewPrivateMessaging(ctx context.Context, di database.Plugin, ii identity.Pl
 }
 
 func (pm *privateMessaging) Start() error {
+	if pm.heartbeatInterval > 0 {
+		go pm.heartbeatLoop()
+	}
 	return pm.exchange.Start()
 }
 
 func (pm *privateMessaging) dispatchBatch(ctx context.Context, batch *fftypes.Batch, contexts []*fftypes.Bytes32) error {
 
+	// The operations for the batch are attributed to the identities that submitted its messages
+	if createdBy := batch.Payload.CreatedBy(); createdBy != "" {
+		ctx = fftypes.WithCreatedBy(ctx, createdBy)
+	}
+
 	// Serialize the full payload, which has already been sealed for us by the BatchManager
 	payload, err := json.Marshal(&fftypes.TransportWrapper{
 		Type:  fftypes.TransportPayloadTypeBatch,
@@ -127,10 +154,21 @@ func (pm *privateMessaging) dispatchBatch(ctx context.Context, batch *fftypes.Ba
 		return i18n.WrapError(ctx, err, i18n.MsgSerializationFailed)
 	}
 
-	// Retrieve the group
-	_, nodes, err := pm.groupManager.getGroupNodes(ctx, batch.Group)
-	if err != nil {
-		return err
+	// Retrieve the batch group, and any additional groups the batch is delivered to. Nodes that are
+	// members of more than one of the groups only receive the batch once.
+	nodes := make([]*fftypes.Node, 0)
+	knownIDs := make(map[fftypes.UUID]bool)
+	for _, groupHash := range batch.TargetGroups() {
+		_, groupNodes, err := pm.groupManager.getGroupNodes(ctx, groupHash)
+		if err != nil {
+			return err
+		}
+		for _, node := range groupNodes {
+			if !knownIDs[*node.ID] {
+				knownIDs[*node.ID] = true
+				nodes = append(nodes, node)
+			}
+		}
 	}
 
 	return pm.database.RunAsGroup(ctx, func(ctx context.Context) error {
@@ -138,7 +176,66 @@ func (pm *privateMessaging) dispatchBatch(ctx context.Context, batch *fftypes.Ba
 	})
 }
 
-func (pm *privateMessaging) transferBlobs(ctx context.Context, data []*fftypes.Data, txid *fftypes.UUID, node *fftypes.Node) error {
+// blockchainFor returns the blockchain plugin for a namespace, which might be overridden in its configuration
+func (pm *privateMessaging) blockchainFor(ns string) blockchain.Plugin {
+	return pm.nsPlugins.Blockchain(ns, pm.blockchain)
+}
+
+// exchangeFor returns the data exchange plugin for a namespace, which might be overridden in its configuration
+func (pm *privateMessaging) exchangeFor(ns string) dataexchange.Plugin {
+	return pm.nsPlugins.DataExchange(ns, pm.exchange)
+}
+
+// newOperation builds an operation with an ID derived from the transaction, type, node and key, so that the
+// same operation is recorded when a batch is retried after a failure
+func (pm *privateMessaging) newOperation(ctx context.Context, ns string, txid *fftypes.UUID, opType fftypes.OpType, node *fftypes.Node, key string, priority uint8) *fftypes.Operation {
+	op := fftypes.NewTXOperation(
+		pm.exchangeFor(ns),
+		ns,
+		txid,
+		"",
+		opType,
+		fftypes.OpStatusPending,
+		node.ID.String(),
+		fftypes.GetCreatedBy(ctx))
+	if txid != nil {
+		op.ID = fftypes.NewNamedUUID(txid, fmt.Sprintf("%s/%s/%s", opType, node.ID, key))
+	}
+	timeout := fftypes.FFDuration(pm.opTimeout)
+	op.Timeout = &timeout
+	op.Priority = priority
+	return op
+}
+
+// submitOperation calls the plugin with a context limited by the timeout of the operation, and records the
+// operation if it is part of a transaction. An operation that times out is recorded as failed, and is not
+// returned as an error - so the failure is not rolled back with the rest of the batch, and the batch is not retried.
+func (pm *privateMessaging) submitOperation(ctx context.Context, op *fftypes.Operation, submit func(ctx context.Context) (string, error)) (err error) {
+	submitCtx := ctx
+	if op.Timeout != nil && *op.Timeout > 0 {
+		var cancel context.CancelFunc
+		submitCtx, cancel = context.WithTimeout(ctx, time.Duration(*op.Timeout))
+		defer cancel()
+	}
+
+	op.BackendID, err = submit(submitCtx)
+	if err != nil {
+		if submitCtx.Err() == context.DeadlineExceeded && op.Transaction != nil {
+			log.L(ctx).Errorf("Operation %s of type %s timed out after %s", op.ID, op.Type, op.Timeout)
+			op.Status = fftypes.OpStatusFailed
+			op.Error = "timeout"
+			return pm.database.UpsertOperation(ctx, op, true)
+		}
+		return err
+	}
+
+	if op.Transaction != nil {
+		return pm.database.UpsertOperation(ctx, op, true)
+	}
+	return nil
+}
+
+func (pm *privateMessaging) transferBlobs(ctx context.Context, data []*fftypes.Data, txid *fftypes.UUID, node *fftypes.Node, priority uint8) error {
 	// Send all the blobs associated with this batch
 	for _, d := range data {
 		// We only need to send a blob if there is one, and it's not been uploaded to the public storage
@@ -151,30 +248,18 @@ func (pm *privateMessaging) transferBlobs(ctx context.Context, data []*fftypes.D
 				return i18n.NewError(ctx, i18n.MsgBlobNotFound, d.Blob)
 			}
 
-			trackingID, err := pm.exchange.TransferBLOB(ctx, node.DX.Peer, blob.PayloadRef)
-			if err != nil {
+			op := pm.newOperation(ctx, d.Namespace, txid, fftypes.OpTypeDataExchangeBlobSend, node, d.Blob.Hash.String(), priority)
+			if err = pm.submitOperation(ctx, op, func(ctx context.Context) (string, error) {
+				return pm.exchangeFor(d.Namespace).TransferBLOB(ctx, node.DX.Peer, blob.PayloadRef)
+			}); err != nil {
 				return err
 			}
-
-			if txid != nil {
-				op := fftypes.NewTXOperation(
-					pm.exchange,
-					d.Namespace,
-					txid,
-					trackingID,
-					fftypes.OpTypeDataExchangeBlobSend,
-					fftypes.OpStatusPending,
-					node.ID.String())
-				if err = pm.database.UpsertOperation(ctx, op, false); err != nil {
-					return err
-				}
-			}
 		}
 	}
 	return nil
 }
 
-func (pm *privateMessaging) sendData(ctx context.Context, mType string, mID *fftypes.UUID, group *fftypes.Bytes32, ns string, nodes []*fftypes.Node, payload fftypes.Byteable, txid *fftypes.UUID, data []*fftypes.Data) (err error) {
+func (pm *privateMessaging) sendData(ctx context.Context, mType string, mID *fftypes.UUID, group *fftypes.Bytes32, ns string, nodes []*fftypes.Node, payload fftypes.Byteable, txid *fftypes.UUID, data []*fftypes.Data, priority uint8) (err error) {
 	l := log.L(ctx)
 
 	// Write it to the dataexchange for each member
@@ -188,37 +273,25 @@ func (pm *privateMessaging) sendData(ctx context.Context, mType string, mID *fft
 		l.Debugf("Sending %s %s:%s to group=%s node=%s (%d/%d)", mType, ns, mID, group, node.ID, i+1, len(nodes))
 
 		// Initiate transfer of any blobs first
-		if err = pm.transferBlobs(ctx, data, txid, node); err != nil {
+		if err = pm.transferBlobs(ctx, data, txid, node, priority); err != nil {
 			return err
 		}
 
 		// Send the payload itself
-		trackingID, err := pm.exchange.SendMessage(ctx, node.DX.Peer, payload)
-		if err != nil {
+		op := pm.newOperation(ctx, ns, txid, fftypes.OpTypeDataExchangeBatchSend, node, "", priority)
+		if err = pm.submitOperation(ctx, op, func(ctx context.Context) (string, error) {
+			return pm.exchangeFor(ns).SendMessage(ctx, node.DX.Peer, payload)
+		}); err != nil {
 			return err
 		}
 
-		if txid != nil {
-			op := fftypes.NewTXOperation(
-				pm.exchange,
-				ns,
-				txid,
-				trackingID,
-				fftypes.OpTypeDataExchangeBatchSend,
-				fftypes.OpStatusPending,
-				node.ID.String())
-			if err = pm.database.UpsertOperation(ctx, op, false); err != nil {
-				return err
-			}
-		}
-
 	}
 
 	return nil
 }
 
 func (pm *privateMessaging) sendAndSubmitBatch(ctx context.Context, batch *fftypes.Batch, nodes []*fftypes.Node, payload fftypes.Byteable, contexts []*fftypes.Bytes32) (err error) {
-	if err = pm.sendData(ctx, "batch", batch.ID, batch.Group, batch.Namespace, nodes, payload, batch.Payload.TX.ID, batch.Payload.Data); err != nil {
+	if err = pm.sendData(ctx, "batch", batch.ID, batch.Group, batch.Namespace, nodes, payload, batch.Payload.TX.ID, batch.Payload.Data, batch.Payload.Priority()); err != nil {
 		return err
 	}
 	return pm.writeTransaction(ctx, batch, contexts)
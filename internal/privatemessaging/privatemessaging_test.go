@@ -20,8 +20,10 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/internal/nsplugins"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/mocks/batchmocks"
 	"github.com/hyperledger/firefly/mocks/batchpinmocks"
@@ -55,7 +57,7 @@ func newTestPrivateMessaging(t *testing.T) (*privateMessaging, func()) {
 	mba.On("RegisterDispatcher", []fftypes.MessageType{fftypes.MessageTypeGroupInit, fftypes.MessageTypePrivate}, mock.Anything, mock.Anything).Return()
 
 	ctx, cancel := context.WithCancel(context.Background())
-	pm, err := NewPrivateMessaging(ctx, mdi, mii, mdx, mbi, mba, mdm, msa, mbp)
+	pm, err := NewPrivateMessaging(ctx, mdi, mii, mdx, mbi, nil, mba, mdm, msa, mbp)
 	assert.NoError(t, err)
 
 	// Default mocks to save boilerplate in the tests
@@ -73,6 +75,8 @@ func TestDispatchBatchWithBlobs(t *testing.T) {
 
 	pm, cancel := newTestPrivateMessaging(t)
 	defer cancel()
+	// The two member group is exactly at the limit, so must not be rejected
+	pm.maxGroupSize = 2
 
 	batchID := fftypes.NewUUID()
 	groupID := fftypes.NewRandB32()
@@ -124,23 +128,23 @@ func TestDispatchBatchWithBlobs(t *testing.T) {
 		Hash:       blob1,
 		PayloadRef: "/blob/1",
 	}, nil)
-	mdx.On("TransferBLOB", pm.ctx, "node1", "/blob/1").Return("tracking1", nil)
+	mdx.On("TransferBLOB", mock.Anything, "node1", "/blob/1").Return("tracking1", nil)
 	mdi.On("UpsertOperation", pm.ctx, mock.MatchedBy(func(op *fftypes.Operation) bool {
 		return op.BackendID == "tracking1" && op.Type == fftypes.OpTypeDataExchangeBlobSend
-	}), false).Return(nil, nil)
-	mdx.On("TransferBLOB", pm.ctx, "node2", "/blob/1").Return("tracking2", nil)
+	}), true).Return(nil, nil)
+	mdx.On("TransferBLOB", mock.Anything, "node2", "/blob/1").Return("tracking2", nil)
 	mdi.On("UpsertOperation", pm.ctx, mock.MatchedBy(func(op *fftypes.Operation) bool {
 		return op.BackendID == "tracking2" && op.Type == fftypes.OpTypeDataExchangeBlobSend
-	}), false).Return(nil, nil)
+	}), true).Return(nil, nil)
 
-	mdx.On("SendMessage", pm.ctx, mock.Anything, mock.Anything).Return("tracking3", nil).Once()
+	mdx.On("SendMessage", mock.Anything, mock.Anything, mock.Anything).Return("tracking3", nil).Once()
 	mdi.On("UpsertOperation", pm.ctx, mock.MatchedBy(func(op *fftypes.Operation) bool {
 		return op.BackendID == "tracking3" && op.Type == fftypes.OpTypeDataExchangeBatchSend
-	}), false).Return(nil, nil)
-	mdx.On("SendMessage", pm.ctx, mock.Anything, mock.Anything).Return("tracking4", nil).Once()
+	}), true).Return(nil, nil)
+	mdx.On("SendMessage", mock.Anything, mock.Anything, mock.Anything).Return("tracking4", nil).Once()
 	mdi.On("UpsertOperation", pm.ctx, mock.MatchedBy(func(op *fftypes.Operation) bool {
 		return op.BackendID == "tracking4" && op.Type == fftypes.OpTypeDataExchangeBatchSend
-	}), false).Return(nil, nil)
+	}), true).Return(nil, nil)
 
 	mbp.On("SubmitPinnedBatch", pm.ctx, mock.Anything, mock.Anything).Return(nil)
 
@@ -153,8 +157,11 @@ func TestDispatchBatchWithBlobs(t *testing.T) {
 			TX: fftypes.TransactionRef{
 				ID: txID,
 			},
+			Messages: []*fftypes.Message{
+				{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1"}},
+			},
 			Data: []*fftypes.Data{
-				{ID: dataID1, Blob: &fftypes.BlobRef{Hash: blob1}},
+				{ID: dataID1, Hash: fftypes.NewRandB32(), Blob: &fftypes.BlobRef{Hash: blob1}},
 			},
 		},
 		Hash: batchHash,
@@ -165,8 +172,157 @@ func TestDispatchBatchWithBlobs(t *testing.T) {
 	mdx.AssertExpectations(t)
 }
 
+func TestDispatchBatchMultipleGroups(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mbp := pm.batchpin.(*batchpinmocks.Submitter)
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+
+	rag := mdi.On("RunAsGroup", pm.ctx, mock.Anything).Maybe()
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+
+	// Three groups, each with a distinct node, and one node shared by all of them
+	groupIDs := []*fftypes.Bytes32{fftypes.NewRandB32(), fftypes.NewRandB32(), fftypes.NewRandB32()}
+	sharedNode := &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "shared"}}
+	mdi.On("GetNodeByID", pm.ctx, sharedNode.ID).Return(sharedNode, nil)
+	pins := []*fftypes.Bytes32{}
+	for i, groupID := range groupIDs {
+		node := &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: fmt.Sprintf("peer%d", i)}}
+		mdi.On("GetGroupByHash", pm.ctx, groupID).Return(&fftypes.Group{
+			Hash: groupID,
+			GroupIdentity: fftypes.GroupIdentity{
+				Name: fmt.Sprintf("group%d", i),
+				Members: fftypes.Members{
+					{Identity: fmt.Sprintf("org%d", i), Node: node.ID},
+					{Identity: "sharedorg", Node: sharedNode.ID},
+				},
+			},
+		}, nil)
+		mdi.On("GetNodeByID", pm.ctx, node.ID).Return(node, nil)
+		mdx.On("SendMessage", mock.Anything, node.DX.Peer, mock.Anything).Return("", nil).Once()
+		pins = append(pins, fftypes.NewRandB32())
+	}
+	mdx.On("SendMessage", mock.Anything, "shared", mock.Anything).Return("", nil).Once()
+	mdi.On("UpsertOperation", pm.ctx, mock.Anything, true).Return(nil)
+
+	batch := &fftypes.Batch{
+		ID:        fftypes.NewUUID(),
+		Author:    "org0",
+		Group:     groupIDs[0],
+		Namespace: "ns1",
+		Payload: fftypes.BatchPayload{
+			TX:     fftypes.TransactionRef{ID: fftypes.NewUUID(), Type: fftypes.TransactionTypeBatchPin},
+			Groups: []fftypes.GroupRef{{Hash: groupIDs[1]}, {Hash: groupIDs[2]}},
+		},
+	}
+	mbp.On("SubmitPinnedBatch", pm.ctx, batch, pins).Return(nil).Once()
+
+	err := pm.dispatchBatch(pm.ctx, batch, pins)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	mbp.AssertExpectations(t)
+}
+
+func TestDispatchBatchAttributesOperationsToCreator(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mbp := pm.batchpin.(*batchpinmocks.Submitter)
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+
+	groupID := fftypes.NewRandB32()
+	node := &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer1"}}
+	mdi.On("GetGroupByHash", mock.Anything, groupID).Return(&fftypes.Group{
+		Hash: groupID,
+		GroupIdentity: fftypes.GroupIdentity{
+			Name:    "group1",
+			Members: fftypes.Members{{Identity: "org1", Node: node.ID}},
+		},
+	}, nil)
+	mdi.On("GetNodeByID", mock.Anything, node.ID).Return(node, nil)
+	mdx.On("SendMessage", mock.Anything, "peer1", mock.Anything).Return("tracking1", nil)
+	mdi.On("UpsertOperation", mock.Anything, mock.MatchedBy(func(op *fftypes.Operation) bool {
+		return op.CreatedBy == "org1"
+	}), true).Return(nil)
+	mbp.On("SubmitPinnedBatch", mock.MatchedBy(func(ctx context.Context) bool {
+		return fftypes.GetCreatedBy(ctx) == "org1"
+	}), mock.Anything, mock.Anything).Return(nil)
+
+	err := pm.dispatchBatch(pm.ctx, &fftypes.Batch{
+		ID:        fftypes.NewUUID(),
+		Author:    "org1",
+		Group:     groupID,
+		Namespace: "ns1",
+		Payload: fftypes.BatchPayload{
+			TX: fftypes.TransactionRef{ID: fftypes.NewUUID(), Type: fftypes.TransactionTypeBatchPin},
+			Messages: []*fftypes.Message{
+				{Header: fftypes.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1"}, CreatedBy: "org1"},
+			},
+		},
+	}, []*fftypes.Bytes32{fftypes.NewRandB32()})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	mbp.AssertExpectations(t)
+}
+
+func TestDispatchBatchMultipleGroupsFail(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	groupID1 := fftypes.NewRandB32()
+	groupID2 := fftypes.NewRandB32()
+	node1 := fftypes.NewUUID()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, groupID1).Return(&fftypes.Group{
+		Hash: groupID1,
+		GroupIdentity: fftypes.GroupIdentity{
+			Name: "group1",
+			Members: fftypes.Members{
+				{Identity: "org1", Node: node1},
+			},
+		},
+	}, nil)
+	mdi.On("GetNodeByID", pm.ctx, node1).Return(&fftypes.Node{ID: node1}, nil)
+	mdi.On("GetGroupByHash", pm.ctx, groupID2).Return(nil, fmt.Errorf("pop"))
+
+	err := pm.dispatchBatch(pm.ctx, &fftypes.Batch{
+		ID:        fftypes.NewUUID(),
+		Group:     groupID1,
+		Namespace: "ns1",
+		Payload: fftypes.BatchPayload{
+			TX:     fftypes.TransactionRef{ID: fftypes.NewUUID()},
+			Groups: []fftypes.GroupRef{{Hash: groupID2}},
+		},
+	}, []*fftypes.Bytes32{})
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestNewPrivateMessagingMissingDeps(t *testing.T) {
-	_, err := NewPrivateMessaging(context.Background(), nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := NewPrivateMessaging(context.Background(), nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -177,7 +333,7 @@ func TestDispatchBatchBadData(t *testing.T) {
 	err := pm.dispatchBatch(pm.ctx, &fftypes.Batch{
 		Payload: fftypes.BatchPayload{
 			Data: []*fftypes.Data{
-				{Value: fftypes.Byteable(`{!json}`)},
+				{ID: fftypes.NewUUID(), Value: fftypes.Byteable(`{!json}`)},
 			},
 		},
 	}, []*fftypes.Bytes32{})
@@ -191,7 +347,10 @@ func TestDispatchErrorFindingGroup(t *testing.T) {
 	mdi := pm.database.(*databasemocks.Plugin)
 	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
 
-	err := pm.dispatchBatch(pm.ctx, &fftypes.Batch{}, []*fftypes.Bytes32{})
+	err := pm.dispatchBatch(pm.ctx, &fftypes.Batch{
+		Group:   fftypes.NewRandB32(),
+		Payload: fftypes.BatchPayload{TX: fftypes.TransactionRef{ID: fftypes.NewUUID()}},
+	}, []*fftypes.Bytes32{})
 	assert.Regexp(t, "pop", err)
 }
 
@@ -219,7 +378,7 @@ func TestSendImmediateFail(t *testing.T) {
 	defer cancel()
 
 	mdx := pm.exchange.(*dataexchangemocks.Plugin)
-	mdx.On("SendMessage", pm.ctx, mock.Anything, mock.Anything).Return("", fmt.Errorf("pop"))
+	mdx.On("SendMessage", mock.Anything, mock.Anything, mock.Anything).Return("", fmt.Errorf("pop"))
 
 	err := pm.sendAndSubmitBatch(pm.ctx, &fftypes.Batch{
 		Author: "org1",
@@ -239,10 +398,10 @@ func TestSendSubmitUpsertOperationFail(t *testing.T) {
 	defer cancel()
 
 	mdx := pm.exchange.(*dataexchangemocks.Plugin)
-	mdx.On("SendMessage", pm.ctx, mock.Anything, mock.Anything).Return("tracking1", nil)
+	mdx.On("SendMessage", mock.Anything, mock.Anything, mock.Anything).Return("tracking1", nil)
 
 	mdi := pm.database.(*databasemocks.Plugin)
-	mdi.On("UpsertOperation", pm.ctx, mock.Anything, false).Return(fmt.Errorf("pop"))
+	mdi.On("UpsertOperation", pm.ctx, mock.Anything, true).Return(fmt.Errorf("pop"))
 
 	err := pm.sendAndSubmitBatch(pm.ctx, &fftypes.Batch{
 		Author: "org1",
@@ -293,7 +452,7 @@ func TestWriteTransactionSubmitBatchPinFail(t *testing.T) {
 
 	mdi := pm.database.(*databasemocks.Plugin)
 	mdi.On("UpsertTransaction", pm.ctx, mock.Anything, true, false).Return(nil)
-	mdi.On("UpsertOperation", pm.ctx, mock.Anything, false).Return(nil)
+	mdi.On("UpsertOperation", pm.ctx, mock.Anything, true).Return(nil)
 
 	mbp := pm.batchpin.(*batchpinmocks.Submitter)
 	mbp.On("SubmitPinnedBatch", pm.ctx, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
@@ -311,7 +470,7 @@ func TestTransferBlobsNotFound(t *testing.T) {
 
 	err := pm.transferBlobs(pm.ctx, []*fftypes.Data{
 		{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32(), Blob: &fftypes.BlobRef{Hash: fftypes.NewRandB32()}},
-	}, fftypes.NewUUID(), &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer1"}})
+	}, fftypes.NewUUID(), &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer1"}}, 0)
 	assert.Regexp(t, "FF10239", err)
 }
 
@@ -322,11 +481,11 @@ func TestTransferBlobsFail(t *testing.T) {
 	mdi := pm.database.(*databasemocks.Plugin)
 	mdi.On("GetBlobMatchingHash", pm.ctx, mock.Anything).Return(&fftypes.Blob{PayloadRef: "blob/1"}, nil)
 	mdx := pm.exchange.(*dataexchangemocks.Plugin)
-	mdx.On("TransferBLOB", pm.ctx, "peer1", "blob/1").Return("", fmt.Errorf("pop"))
+	mdx.On("TransferBLOB", mock.Anything, "peer1", "blob/1").Return("", fmt.Errorf("pop"))
 
 	err := pm.transferBlobs(pm.ctx, []*fftypes.Data{
 		{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32(), Blob: &fftypes.BlobRef{Hash: fftypes.NewRandB32()}},
-	}, fftypes.NewUUID(), &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer1"}})
+	}, fftypes.NewUUID(), &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer1"}}, 0)
 	assert.Regexp(t, "pop", err)
 }
 
@@ -338,13 +497,103 @@ func TestTransferBlobsOpInsertFail(t *testing.T) {
 	mdx := pm.exchange.(*dataexchangemocks.Plugin)
 
 	mdi.On("GetBlobMatchingHash", pm.ctx, mock.Anything).Return(&fftypes.Blob{PayloadRef: "blob/1"}, nil)
-	mdx.On("TransferBLOB", pm.ctx, "peer1", "blob/1").Return("tracking1", nil)
-	mdi.On("UpsertOperation", pm.ctx, mock.Anything, false).Return(fmt.Errorf("pop"))
+	mdx.On("TransferBLOB", mock.Anything, "peer1", "blob/1").Return("tracking1", nil)
+	mdi.On("UpsertOperation", pm.ctx, mock.Anything, true).Return(fmt.Errorf("pop"))
+
+	err := pm.transferBlobs(pm.ctx, []*fftypes.Data{
+		{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32(), Blob: &fftypes.BlobRef{Hash: fftypes.NewRandB32()}},
+	}, fftypes.NewUUID(), &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer1"}}, 0)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestTransferBlobsOpTimeout(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.opTimeout = 1 * time.Millisecond
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+
+	mdi.On("GetBlobMatchingHash", pm.ctx, mock.Anything).Return(&fftypes.Blob{PayloadRef: "blob/1"}, nil)
+	mdx.On("TransferBLOB", mock.Anything, "peer1", "blob/1").Run(func(args mock.Arguments) {
+		// Simulate a slow transfer, that only returns when the context expires
+		<-args[0].(context.Context).Done()
+	}).Return("", fmt.Errorf("context deadline exceeded"))
+	mdi.On("UpsertOperation", pm.ctx, mock.MatchedBy(func(op *fftypes.Operation) bool {
+		return op.Type == fftypes.OpTypeDataExchangeBlobSend &&
+			op.Status == fftypes.OpStatusFailed &&
+			op.Error == "timeout" &&
+			*op.Timeout == fftypes.FFDuration(1*time.Millisecond)
+	}), true).Return(nil)
 
 	err := pm.transferBlobs(pm.ctx, []*fftypes.Data{
 		{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32(), Blob: &fftypes.BlobRef{Hash: fftypes.NewRandB32()}},
-	}, fftypes.NewUUID(), &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer1"}})
+	}, fftypes.NewUUID(), &fftypes.Node{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer1"}}, 0)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestSendDataOpTimeoutUpsertFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.opTimeout = 1 * time.Millisecond
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+
+	mdx.On("SendMessage", mock.Anything, "peer1", mock.Anything).Run(func(args mock.Arguments) {
+		<-args[0].(context.Context).Done()
+	}).Return("", fmt.Errorf("context deadline exceeded"))
+	mdi.On("UpsertOperation", pm.ctx, mock.MatchedBy(func(op *fftypes.Operation) bool {
+		return op.Type == fftypes.OpTypeDataExchangeBatchSend && op.Status == fftypes.OpStatusFailed
+	}), true).Return(fmt.Errorf("pop"))
+
+	err := pm.sendData(pm.ctx, "batch", fftypes.NewUUID(), fftypes.NewRandB32(), "ns1", []*fftypes.Node{
+		{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer1"}},
+	}, fftypes.Byteable(`{}`), fftypes.NewUUID(), []*fftypes.Data{}, 0)
 	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestNewOperationSameIDOnRetry(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	txid := fftypes.NewUUID()
+	node := &fftypes.Node{ID: fftypes.NewUUID()}
+	op1 := pm.newOperation(pm.ctx, "ns1", txid, fftypes.OpTypeDataExchangeBlobSend, node, "blob1", 0)
+	op2 := pm.newOperation(pm.ctx, "ns1", txid, fftypes.OpTypeDataExchangeBlobSend, node, "blob1", 0)
+	assert.Equal(t, *op1.ID, *op2.ID)
+	op3 := pm.newOperation(pm.ctx, "ns1", txid, fftypes.OpTypeDataExchangeBlobSend, node, "blob2", 0)
+	assert.NotEqual(t, *op1.ID, *op3.ID)
+	op4 := pm.newOperation(pm.ctx, "ns1", txid, fftypes.OpTypeDataExchangeBatchSend, node, "", 0)
+	assert.NotEqual(t, *op1.ID, *op4.ID)
+}
+
+func TestSendDataNamespaceOverride(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	mdx2 := &dataexchangemocks.Plugin{}
+	mdx2.On("Name").Return("utdx2")
+	pm.nsPlugins = nsplugins.Overrides{"ns1": {DataExchange: mdx2}}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdx2.On("SendMessage", mock.Anything, "peer1", mock.Anything).Return("tracking1", nil)
+	mdi.On("UpsertOperation", pm.ctx, mock.MatchedBy(func(op *fftypes.Operation) bool {
+		return op.Plugin == "utdx2"
+	}), true).Return(nil)
+
+	err := pm.sendData(pm.ctx, "batch", fftypes.NewUUID(), fftypes.NewRandB32(), "ns1", []*fftypes.Node{
+		{ID: fftypes.NewUUID(), DX: fftypes.DXInfo{Peer: "peer1"}},
+	}, fftypes.Byteable(`{}`), fftypes.NewUUID(), []*fftypes.Data{}, 0)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdx2.AssertExpectations(t)
 }
 
 func TestRequestReplyMissingTag(t *testing.T) {
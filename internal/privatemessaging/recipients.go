@@ -107,10 +107,13 @@ func (pm *privateMessaging) resolveNode(ctx context.Context, org *fftypes.Organi
 func (pm *privateMessaging) getReceipients(ctx context.Context, in *fftypes.MessageInOut) (gi *fftypes.GroupIdentity, err error) {
 	foundLocal := false
 	gi = &fftypes.GroupIdentity{
-		Namespace: in.Message.Header.Namespace,
-		Name:      in.Group.Name,
-		Ledger:    in.Group.Ledger,
-		Members:   make(fftypes.Members, len(in.Group.Members)),
+		Namespace:   in.Message.Header.Namespace,
+		Name:        in.Group.Name,
+		Description: in.Group.Description,
+		Ledger:      in.Group.Ledger,
+		Members:     make(fftypes.Members, len(in.Group.Members)),
+		Schema:      in.Group.Schema,
+		Previous:    in.Group.Previous,
 	}
 	for i, rInput := range in.Group.Members {
 		// Resolve the org
@@ -127,6 +130,7 @@ func (pm *privateMessaging) getReceipients(ctx context.Context, in *fftypes.Mess
 		gi.Members[i] = &fftypes.Member{
 			Identity: org.Identity,
 			Node:     node.ID,
+			Alias:    rInput.Alias,
 		}
 	}
 	if !foundLocal {
@@ -140,9 +144,36 @@ func (pm *privateMessaging) getReceipients(ctx context.Context, in *fftypes.Mess
 			Node:     localNodeID,
 		})
 	}
+	if gi.ACL, err = pm.getGroupACL(ctx, in); err != nil {
+		return nil, err
+	}
 	return gi, nil
 }
 
+// getGroupACL resolves the org identities allowed to re-initialize a named group.
+// The creating org is always included, so that it can update the group it defined.
+// Unnamed groups are identified only by their members, so cannot be updated and have no ACL.
+func (pm *privateMessaging) getGroupACL(ctx context.Context, in *fftypes.MessageInOut) (fftypes.GroupACL, error) {
+	if in.Group.Name == "" && len(in.Group.ACL) == 0 {
+		return nil, nil
+	}
+	creator := in.Message.Header.Author
+	if creator == "" {
+		creator = pm.localOrgIdentity
+	}
+	acl := fftypes.GroupACL{creator}
+	for _, aclInput := range in.Group.ACL {
+		org, err := pm.resolveOrg(ctx, aclInput)
+		if err != nil {
+			return nil, err
+		}
+		if !acl.Contains(org.Identity) {
+			acl = append(acl, org.Identity)
+		}
+	}
+	return acl, nil
+}
+
 func (pm *privateMessaging) resolveLocalNode(ctx context.Context) (*fftypes.UUID, error) {
 	if pm.localNodeID != nil {
 		return pm.localNodeID, nil
@@ -177,6 +208,18 @@ func (pm *privateMessaging) findOrGenerateGroup(ctx context.Context, in *fftypes
 		GroupIdentity: *gi,
 		Created:       fftypes.Now(),
 	}
+
+	// A re-initialization of a previous group with new parameters takes the next nonce in its lineage
+	if newCandidate.Previous != nil {
+		previous, err := pm.database.GetGroupByHash(ctx, newCandidate.Previous)
+		if err != nil {
+			return nil, false, err
+		}
+		if previous == nil {
+			return nil, false, i18n.NewError(ctx, i18n.MsgPreviousGroupNotFound, newCandidate.Previous)
+		}
+		newCandidate.Nonce = previous.Nonce + 1
+	}
 	newCandidate.Seal()
 
 	filter := database.GroupQueryFactory.NewFilterLimit(ctx, 1).Eq("hash", newCandidate.Hash)
@@ -187,5 +230,6 @@ func (pm *privateMessaging) findOrGenerateGroup(ctx context.Context, in *fftypes
 	if len(groups) > 0 {
 		return groups[0], false, nil
 	}
+
 	return newCandidate, true, nil
 }
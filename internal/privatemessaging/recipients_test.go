@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -33,10 +34,13 @@ func TestResolveMemberListNewGroupE2E(t *testing.T) {
 	defer cancel()
 
 	mdi := pm.database.(*databasemocks.Plugin)
+	mdm := pm.data.(*datamocks.Manager)
 	nodeIDRemote := fftypes.NewUUID()
 	nodeIDLocal := fftypes.NewUUID()
 	orgID := fftypes.NewUUID()
+	schema := &fftypes.DatatypeRef{Name: "customer", Version: "0.0.1"}
 	var dataID *fftypes.UUID
+	mdm.On("ValidateAgainstDatatype", pm.ctx, "ns1", schema, []*fftypes.Data(nil)).Return(nil)
 	mdi.On("GetOrganizationByName", pm.ctx, mock.Anything).Return(&fftypes.Organization{ID: orgID, Identity: "remoteorg"}, nil)
 	mdi.On("GetNodes", pm.ctx, mock.Anything).Return([]*fftypes.Node{{ID: nodeIDRemote, Name: "node2", Owner: "remoteorg"}}, nil, nil).Once()
 	mdi.On("GetNodes", pm.ctx, mock.Anything).Return([]*fftypes.Node{{ID: nodeIDLocal, Name: "node1", Owner: "localorg"}}, nil, nil).Once()
@@ -56,7 +60,10 @@ func TestResolveMemberListNewGroupE2E(t *testing.T) {
 		assert.Equal(t, *nodeIDLocal, *group.Members[0].Node)
 		assert.Equal(t, "remoteorg", group.Members[1].Identity)
 		assert.Equal(t, *nodeIDRemote, *group.Members[1].Node)
+		assert.Equal(t, "supplier", group.Members[1].Alias)
 		assert.Nil(t, group.Ledger)
+		assert.Equal(t, "Supply chain partners", group.Description)
+		assert.Equal(t, *schema, *group.Schema)
 		dataID = data.ID
 	}
 	um := mdi.On("InsertMessageLocal", pm.ctx, mock.Anything).Return(nil).Once()
@@ -75,13 +82,16 @@ func TestResolveMemberListNewGroupE2E(t *testing.T) {
 			},
 		},
 		Group: &fftypes.InputGroup{
+			Description: "Supply chain partners",
 			Members: []fftypes.MemberInput{
-				{Identity: "remoteorg"},
+				{Identity: "remoteorg", Alias: "supplier"},
 			},
+			Schema: schema,
 		},
 	})
 	assert.NoError(t, err)
 	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
 
 }
 
@@ -131,6 +141,179 @@ func TestResolveMemberListGetGroupsFail(t *testing.T) {
 
 }
 
+func TestFindOrGenerateGroupReinitNamedGroup(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	previous := fftypes.NewRandB32()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByName", pm.ctx, "org1").Return(&fftypes.Organization{ID: fftypes.NewUUID(), Identity: "org1"}, nil)
+	mdi.On("GetNodes", pm.ctx, mock.Anything).Return([]*fftypes.Node{{ID: fftypes.NewUUID(), Name: "node1", Owner: "localorg"}}, nil, nil)
+	mdi.On("GetGroupByHash", pm.ctx, previous).Return(&fftypes.Group{
+		GroupIdentity: fftypes.GroupIdentity{Name: "group1", Nonce: 2}, Hash: previous,
+	}, nil)
+	mdi.On("GetGroups", pm.ctx, mock.Anything).Return([]*fftypes.Group{}, nil, nil)
+
+	group, isNew, err := pm.findOrGenerateGroup(pm.ctx, &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Namespace: "ns1",
+			},
+		},
+		Group: &fftypes.InputGroup{
+			Name: "group1",
+			Members: []fftypes.MemberInput{
+				{Identity: "org1"},
+			},
+			Previous: previous,
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, isNew)
+	assert.Equal(t, uint64(3), group.Nonce)
+	assert.Equal(t, previous, group.Previous)
+	assert.Equal(t, fftypes.GroupACL{"localorg"}, group.ACL)
+	assert.Equal(t, group.GroupIdentity.Hash(), group.Hash)
+	mdi.AssertExpectations(t)
+
+}
+
+func TestFindOrGenerateGroupPreviousNotFound(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByName", pm.ctx, "org1").Return(&fftypes.Organization{ID: fftypes.NewUUID(), Identity: "org1"}, nil)
+	mdi.On("GetNodes", pm.ctx, mock.Anything).Return([]*fftypes.Node{{ID: fftypes.NewUUID(), Name: "node1", Owner: "localorg"}}, nil, nil)
+	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(nil, nil)
+
+	_, _, err := pm.findOrGenerateGroup(pm.ctx, &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Namespace: "ns1",
+			},
+		},
+		Group: &fftypes.InputGroup{
+			Name: "group1",
+			Members: []fftypes.MemberInput{
+				{Identity: "org1"},
+			},
+			Previous: fftypes.NewRandB32(),
+		},
+	})
+	assert.Regexp(t, "FF10352", err)
+	mdi.AssertExpectations(t)
+
+}
+
+func TestFindOrGenerateGroupPreviousQueryFail(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByName", pm.ctx, "org1").Return(&fftypes.Organization{ID: fftypes.NewUUID(), Identity: "org1"}, nil)
+	mdi.On("GetNodes", pm.ctx, mock.Anything).Return([]*fftypes.Node{{ID: fftypes.NewUUID(), Name: "node1", Owner: "localorg"}}, nil, nil)
+	mdi.On("GetGroupByHash", pm.ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := pm.findOrGenerateGroup(pm.ctx, &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Namespace: "ns1",
+			},
+		},
+		Group: &fftypes.InputGroup{
+			Name: "group1",
+			Members: []fftypes.MemberInput{
+				{Identity: "org1"},
+			},
+			Previous: fftypes.NewRandB32(),
+		},
+	})
+	assert.EqualError(t, err, "pop")
+	mdi.AssertExpectations(t)
+
+}
+
+func TestGetReceipientsGroupACL(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByName", pm.ctx, "org1").Return(&fftypes.Organization{ID: fftypes.NewUUID(), Identity: "org1"}, nil)
+	mdi.On("GetOrganizationByName", pm.ctx, "org2").Return(&fftypes.Organization{ID: fftypes.NewUUID(), Identity: "org2"}, nil)
+	mdi.On("GetNodes", pm.ctx, mock.Anything).Return([]*fftypes.Node{{ID: fftypes.NewUUID(), Name: "node1", Owner: "localorg"}}, nil, nil)
+
+	gi, err := pm.getReceipients(pm.ctx, &fftypes.MessageInOut{
+		Message: fftypes.Message{
+			Header: fftypes.MessageHeader{
+				Namespace: "ns1",
+				Author:    "org1",
+			},
+		},
+		Group: &fftypes.InputGroup{
+			Members: []fftypes.MemberInput{
+				{Identity: "org1"},
+			},
+			ACL: []string{"org2", "org1"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.GroupACL{"org1", "org2"}, gi.ACL)
+	mdi.AssertExpectations(t)
+
+}
+
+func TestGetReceipientsGroupACLUnnamed(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByName", pm.ctx, "org1").Return(&fftypes.Organization{ID: fftypes.NewUUID(), Identity: "org1"}, nil)
+	mdi.On("GetNodes", pm.ctx, mock.Anything).Return([]*fftypes.Node{{ID: fftypes.NewUUID(), Name: "node1", Owner: "localorg"}}, nil, nil)
+
+	gi, err := pm.getReceipients(pm.ctx, &fftypes.MessageInOut{
+		Group: &fftypes.InputGroup{
+			Members: []fftypes.MemberInput{
+				{Identity: "org1"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, gi.ACL)
+	mdi.AssertExpectations(t)
+
+}
+
+func TestGetReceipientsGroupACLOrgNotFound(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrganizationByName", pm.ctx, "org1").Return(&fftypes.Organization{ID: fftypes.NewUUID(), Identity: "org1"}, nil)
+	mdi.On("GetNodes", pm.ctx, mock.Anything).Return([]*fftypes.Node{{ID: fftypes.NewUUID(), Name: "node1", Owner: "localorg"}}, nil, nil)
+	mdi.On("GetOrganizationByName", pm.ctx, "unknown").Return(nil, nil)
+	mdi.On("GetOrganizationByIdentity", pm.ctx, "unknown").Return(nil, nil)
+
+	_, err := pm.getReceipients(pm.ctx, &fftypes.MessageInOut{
+		Group: &fftypes.InputGroup{
+			Name: "group1",
+			Members: []fftypes.MemberInput{
+				{Identity: "org1"},
+			},
+			ACL: []string{"unknown"},
+		},
+	})
+	assert.Regexp(t, "FF10223", err)
+	mdi.AssertExpectations(t)
+
+}
+
 func TestResolveMemberListMissingLocalMemberLookupFailed(t *testing.T) {
 
 	pm, cancel := newTestPrivateMessaging(t)
@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/firefly/internal/i18n"
 	"github.com/hyperledger/firefly/internal/log"
 	"github.com/hyperledger/firefly/internal/restclient"
+	"github.com/hyperledger/firefly/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/publicstorage"
 )
 
@@ -86,7 +87,18 @@ func (i *IPFS) PublishData(ctx context.Context, data io.Reader) (string, error)
 	return ipfsResponse.Hash, err
 }
 
+// ipfsCID returns the IPFS CID for a payload reference. References are normally the CID returned
+// by IPFS on upload, but a reference recorded as the hex bytes32 sha2-256 digest of the content
+// is converted to the equivalent CIDv0, so it is a valid IPFS path.
+func ipfsCID(ctx context.Context, payloadRef string) string {
+	if digest, err := fftypes.ParseBytes32Flexible(ctx, payloadRef); err == nil {
+		return digest.ToIPFSCIDv0()
+	}
+	return payloadRef
+}
+
 func (i *IPFS) RetrieveData(ctx context.Context, payloadRef string) (data io.ReadCloser, err error) {
+	payloadRef = ipfsCID(ctx, payloadRef)
 	res, err := i.gwClient.R().
 		SetContext(ctx).
 		SetDoNotParseResponse(true).
@@ -101,3 +113,22 @@ func (i *IPFS) RetrieveData(ctx context.Context, payloadRef string) (data io.Rea
 	log.L(ctx).Infof("IPFS retrieved %s", payloadRef)
 	return res.RawBody(), nil
 }
+
+func (i *IPFS) DeleteData(ctx context.Context, payloadRef string) error {
+	// Content in IPFS is reclaimed by garbage collection in IPFS, once it is no longer pinned
+	payloadRef = ipfsCID(ctx, payloadRef)
+	res, err := i.apiClient.R().
+		SetContext(ctx).
+		SetQueryParam("arg", payloadRef).
+		Post("/api/v0/pin/rm")
+	if err != nil || !res.IsSuccess() {
+		return restclient.WrapRestErr(i.ctx, res, err, i18n.MsgIPFSRESTErr)
+	}
+	log.L(ctx).Infof("IPFS unpinned %s", payloadRef)
+	return nil
+}
+
+func (i *IPFS) ListStoredItems(ctx context.Context, since *fftypes.FFTime) ([]publicstorage.StoredItem, error) {
+	// IPFS does not track when content was added, so cannot provide an inventory
+	return nil, i18n.NewError(ctx, i18n.MsgStorageListNotSupported, i.Name())
+}
@@ -68,6 +68,13 @@ func TestInit(t *testing.T) {
 	assert.Equal(t, "ipfs", i.Name())
 	assert.NoError(t, err)
 	assert.NotNil(t, i.Capabilities())
+	assert.False(t, i.Capabilities().SupportsList)
+}
+
+func TestIPFSListStoredItemsNotSupported(t *testing.T) {
+	i := &IPFS{}
+	_, err := i.ListStoredItems(context.Background(), nil)
+	assert.Regexp(t, "FF10281", err)
 }
 
 func TestIPFSUploadSuccess(t *testing.T) {
@@ -121,6 +128,57 @@ func TestIPFSUploadFail(t *testing.T) {
 
 }
 
+func TestIPFSDeleteSuccess(t *testing.T) {
+	i := &IPFS{}
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	resetConf()
+	utConfPrefix.SubPrefix(IPFSConfAPISubconf).Set(restclient.HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.SubPrefix(IPFSConfGatewaySubconf).Set(restclient.HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.SubPrefix(IPFSConfAPISubconf).Set(restclient.HTTPCustomClient, mockedClient)
+
+	err := i.Init(context.Background(), utConfPrefix, &publicstoragemocks.Callbacks{})
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/api/v0/pin/rm",
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "QmRAQfHNnknnz8S936M2yJGhhVNA6wXJ4jTRP3VXtptmmL", req.URL.Query().Get("arg"))
+			return httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+				"Pins": []string{"QmRAQfHNnknnz8S936M2yJGhhVNA6wXJ4jTRP3VXtptmmL"},
+			})(req)
+		})
+
+	err = i.DeleteData(context.Background(), "QmRAQfHNnknnz8S936M2yJGhhVNA6wXJ4jTRP3VXtptmmL")
+	assert.NoError(t, err)
+
+}
+
+func TestIPFSDeleteFail(t *testing.T) {
+	i := &IPFS{}
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	resetConf()
+	utConfPrefix.SubPrefix(IPFSConfAPISubconf).Set(restclient.HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.SubPrefix(IPFSConfGatewaySubconf).Set(restclient.HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.SubPrefix(IPFSConfAPISubconf).Set(restclient.HTTPCustomClient, mockedClient)
+
+	err := i.Init(context.Background(), utConfPrefix, &publicstoragemocks.Callbacks{})
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/api/v0/pin/rm",
+		httpmock.NewJsonResponderOrPanic(500, map[string]interface{}{"error": "not pinned"}))
+
+	err = i.DeleteData(context.Background(), "QmRAQfHNnknnz8S936M2yJGhhVNA6wXJ4jTRP3VXtptmmL")
+	assert.Regexp(t, "FF10136", err)
+
+}
+
 func TestIPFSDownloadSuccess(t *testing.T) {
 	i := &IPFS{}
 
@@ -150,6 +208,30 @@ func TestIPFSDownloadSuccess(t *testing.T) {
 
 }
 
+func TestIPFSDownloadHexDigest(t *testing.T) {
+	i := &IPFS{}
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	resetConf()
+	utConfPrefix.SubPrefix(IPFSConfAPISubconf).Set(restclient.HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.SubPrefix(IPFSConfGatewaySubconf).Set(restclient.HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.SubPrefix(IPFSConfGatewaySubconf).Set(restclient.HTTPCustomClient, mockedClient)
+
+	err := i.Init(context.Background(), utConfPrefix, &publicstoragemocks.Callbacks{})
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/ipfs/QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH",
+		httpmock.NewBytesResponder(200, []byte{}))
+
+	r, err := i.RetrieveData(context.Background(), "0xbfccda787baba32b59c78450ac3d20b633360b43992c77289f9ed46d843561e6")
+	assert.NoError(t, err)
+	r.Close()
+
+}
+
 func TestIPFSDownloadFail(t *testing.T) {
 	i := &IPFS{}
 
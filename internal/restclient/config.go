@@ -37,6 +37,10 @@ const (
 	HTTPConfigAuthUsername = "auth.username"
 	// HTTPConfigAuthPassword HTTPS Basic Auth configuration - secret / password
 	HTTPConfigAuthPassword = "auth.password"
+	// HTTPConfigAuthPasswordSecret HTTPS Basic Auth configuration - path of the password in the configured secret manager, instead of auth.password
+	HTTPConfigAuthPasswordSecret = "auth.passwordSecret"
+	// HTTPConfigAuthTokenSecret path of a bearer token in the configured secret manager, sent in the Authorization header
+	HTTPConfigAuthTokenSecret = "auth.tokenSecret"
 	// HTTPConfigRetryEnabled whether retry is enabled on the actions performed over this HTTP request (does not disable retry at higher layers)
 	HTTPConfigRetryEnabled = "retry.enabled"
 	// HTTPConfigRetryCount the maximum number of retries
@@ -47,6 +51,14 @@ const (
 	HTTPConfigRetryMaxDelay = "retry.maxWaitTime"
 	// HTTPConfigRequestTimeout the request timeout
 	HTTPConfigRequestTimeout = "requestTimeout"
+	// HTTPConfigTimeoutGet overrides the request timeout for GET requests
+	HTTPConfigTimeoutGet = "timeout.get"
+	// HTTPConfigTimeoutPost overrides the request timeout for POST requests
+	HTTPConfigTimeoutPost = "timeout.post"
+	// HTTPConfigTimeoutPut overrides the request timeout for PUT requests
+	HTTPConfigTimeoutPut = "timeout.put"
+	// HTTPConfigTimeoutDelete overrides the request timeout for DELETE requests
+	HTTPConfigTimeoutDelete = "timeout.delete"
 
 	// HTTPCustomClient - unit test only - allows injection of a custom HTTP client to resty
 	HTTPCustomClient = "customClient"
@@ -58,11 +70,17 @@ func InitPrefix(prefix config.KeySet) {
 	prefix.AddKnownKey(HTTPConfigHeaders)
 	prefix.AddKnownKey(HTTPConfigAuthUsername)
 	prefix.AddKnownKey(HTTPConfigAuthPassword)
+	prefix.AddKnownKey(HTTPConfigAuthPasswordSecret)
+	prefix.AddKnownKey(HTTPConfigAuthTokenSecret)
 	prefix.AddKnownKey(HTTPConfigRetryEnabled, defaultRetryEnabled)
 	prefix.AddKnownKey(HTTPConfigRetryCount, defaultRetryCount)
 	prefix.AddKnownKey(HTTPConfigRetryInitDelay, defaultRetryWaitTime)
 	prefix.AddKnownKey(HTTPConfigRetryMaxDelay, defaultRetryMaxWaitTime)
 	prefix.AddKnownKey(HTTPConfigRequestTimeout, defaultRequestTimeout)
+	prefix.AddKnownKey(HTTPConfigTimeoutGet)
+	prefix.AddKnownKey(HTTPConfigTimeoutPost)
+	prefix.AddKnownKey(HTTPConfigTimeoutPut)
+	prefix.AddKnownKey(HTTPConfigTimeoutDelete)
 
 	prefix.AddKnownKey(HTTPCustomClient)
 }
@@ -19,8 +19,11 @@ package restclient
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -34,24 +37,80 @@ import (
 
 type retryCtxKey struct{}
 
+type requestIDCtxKey struct{}
+
+// HeaderRequestID is the HTTP header used to correlate a request across FireFly and its plugins
+const HeaderRequestID = "X-Request-ID"
+
 type retryCtx struct {
 	id       string
 	start    time.Time
 	attempts uint
+	cancel   context.CancelFunc
+}
+
+// TimeoutError is returned by WrapRestErr when a request failed because it timed out,
+// so callers can distinguish a slow or unresponsive server from other failures
+type TimeoutError struct {
+	error
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.error
+}
+
+// IsTimeout returns true if the error is, or wraps, a TimeoutError
+func IsTimeout(err error) bool {
+	var te *TimeoutError
+	return errors.As(err, &te)
+}
+
+// WithRequestID stores the request ID in the context, so that it is propagated on outbound REST calls
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, requestID)
+}
+
+// GetRequestID returns the request ID stored in the context, or an empty string if there is none
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return requestID
+}
+
+// cancelOnClose releases the deadline of a streamed request, once the caller has finished with the body
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (cc *cancelOnClose) Close() error {
+	defer cc.cancel()
+	return cc.ReadCloser.Close()
 }
 
 // OnAfterResponse when using SetDoNotParseResponse(true) for streming binary replies,
 // the caller should invoke ffrest.OnAfterResponse on the response manually.
 // The middleware is disabled on this path :-(
 // See: https://github.com/go-resty/resty/blob/d01e8d1bac5ba1fed0d9e03c4c47ca21e94a7e8e/client.go#L912-L948
+// The deadline of the request is released when the caller closes the raw body.
 func OnAfterResponse(c *resty.Client, resp *resty.Response) {
+	rc := logResponse(c, resp)
+	if rc != nil && rc.cancel != nil && resp.RawResponse != nil && resp.RawResponse.Body != nil {
+		resp.RawResponse.Body = &cancelOnClose{ReadCloser: resp.RawResponse.Body, cancel: rc.cancel}
+	}
+}
+
+func logResponse(c *resty.Client, resp *resty.Response) *retryCtx {
 	if c == nil || resp == nil {
-		return
+		return nil
 	}
 	rctx := resp.Request.Context()
-	rc := rctx.Value(retryCtxKey{}).(*retryCtx)
+	rc, ok := rctx.Value(retryCtxKey{}).(*retryCtx)
+	if !ok {
+		return nil
+	}
 	elapsed := float64(time.Since(rc.start)) / float64(time.Millisecond)
 	log.L(rctx).Infof("<== %s %s [%d] (%.2fms)", resp.Request.Method, resp.Request.URL, resp.StatusCode(), elapsed)
+	return rc
 }
 
 // New creates a new Resty client, using static configuration (from the config file)
@@ -84,7 +143,25 @@ func New(ctx context.Context, staticConfig config.Prefix) *resty.Client {
 		client.SetProxy(proxy)
 	}
 
-	client.SetTimeout(staticConfig.GetDuration(HTTPConfigRequestTimeout))
+	// The client timeout is the longest of the configured timeouts, and requests using a verb
+	// with a shorter timeout have a deadline set on their context
+	requestTimeout := staticConfig.GetDuration(HTTPConfigRequestTimeout)
+	verbTimeouts := map[string]time.Duration{}
+	clientTimeout := requestTimeout
+	for method, key := range map[string]string{
+		http.MethodGet:    HTTPConfigTimeoutGet,
+		http.MethodPost:   HTTPConfigTimeoutPost,
+		http.MethodPut:    HTTPConfigTimeoutPut,
+		http.MethodDelete: HTTPConfigTimeoutDelete,
+	} {
+		if timeout := staticConfig.GetDuration(key); timeout > 0 {
+			verbTimeouts[method] = timeout
+			if timeout > clientTimeout {
+				clientTimeout = timeout
+			}
+		}
+	}
+	client.SetTimeout(clientTimeout)
 
 	client.OnBeforeRequest(func(c *resty.Client, req *resty.Request) error {
 		rctx := req.Context()
@@ -95,19 +172,49 @@ func New(ctx context.Context, staticConfig config.Prefix) *resty.Client {
 				id:    fftypes.ShortID(),
 				start: time.Now(),
 			}
+			timeout, ok := verbTimeouts[req.Method]
+			if !ok {
+				timeout = requestTimeout
+			}
+			if timeout > 0 && timeout < clientTimeout {
+				// The deadline applies across all retries of the request
+				rctx, r.cancel = context.WithTimeout(rctx, timeout)
+			}
 			rctx = context.WithValue(rctx, retryCtxKey{}, r)
 			// Create a request logger from the root logger passed into the client
 			l := log.L(ctx).WithField("breq", r.id)
 			rctx = log.WithLogger(rctx, l)
 			req.SetContext(rctx)
 		}
+		if requestID := GetRequestID(rctx); requestID != "" {
+			req.SetHeader(HeaderRequestID, requestID)
+		}
 		log.L(rctx).Infof("==> %s %s%s", req.Method, url, req.URL)
 		return nil
 	})
 
-	// Note that callers using SetNotParseResponse will need to invoke this themselves
+	retryCount := 0
+	if staticConfig.GetBool(HTTPConfigRetryEnabled) {
+		retryCount = staticConfig.GetInt(HTTPConfigRetryCount)
+	}
 
-	client.OnAfterResponse(func(c *resty.Client, r *resty.Response) error { OnAfterResponse(c, r); return nil })
+	// Note that callers using SetNotParseResponse will need to invoke this themselves.
+	// The deadline of the request is released once the body has been read, unless the
+	// response is going to be retried - as the deadline applies across all the attempts.
+	client.OnAfterResponse(func(c *resty.Client, r *resty.Response) error {
+		rc := logResponse(c, r)
+		if rc != nil && rc.cancel != nil && (r.IsSuccess() || r.Request.Attempt > retryCount) {
+			rc.cancel()
+		}
+		return nil
+	})
+
+	// Requests that fail without a response do not reach the response middleware
+	client.OnError(func(req *resty.Request, err error) {
+		if rc, ok := req.Context().Value(retryCtxKey{}).(*retryCtx); ok && rc.cancel != nil {
+			rc.cancel()
+		}
+	})
 
 	headers := staticConfig.GetObject(HTTPConfigHeaders)
 	for k, v := range headers {
@@ -118,11 +225,20 @@ func New(ctx context.Context, staticConfig config.Prefix) *resty.Client {
 	authUsername := staticConfig.GetString((HTTPConfigAuthUsername))
 	authPassword := staticConfig.GetString((HTTPConfigAuthPassword))
 	if authUsername != "" && authPassword != "" {
-		client.SetHeader("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", authUsername, authPassword)))))
+		client.SetHeader("Authorization", basicAuth(authUsername, authPassword))
+	}
+
+	// Credentials held in the secret manager are resolved as each request is made (the secret
+	// manager caches them) so that they are picked up when rotated, without a restart.
+	authPasswordSecret := staticConfig.GetString(HTTPConfigAuthPasswordSecret)
+	authTokenSecret := staticConfig.GetString(HTTPConfigAuthTokenSecret)
+	if (authUsername != "" && authPasswordSecret != "") || authTokenSecret != "" {
+		client.OnBeforeRequest(func(c *resty.Client, req *resty.Request) error {
+			return setSecretAuthHeader(req, authUsername, authPasswordSecret, authTokenSecret)
+		})
 	}
 
 	if staticConfig.GetBool(HTTPConfigRetryEnabled) {
-		retryCount := staticConfig.GetInt(HTTPConfigRetryCount)
 		minTimeout := staticConfig.GetDuration(HTTPConfigRetryInitDelay)
 		maxTimeout := staticConfig.GetDuration(HTTPConfigRetryMaxDelay)
 		client.
@@ -144,6 +260,35 @@ func New(ctx context.Context, staticConfig config.Prefix) *resty.Client {
 	return client
 }
 
+func basicAuth(username, password string) string {
+	return fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", username, password))))
+}
+
+func setSecretAuthHeader(req *resty.Request, authUsername, authPasswordSecret, authTokenSecret string) error {
+	ctx := req.Context()
+	secretPath := authPasswordSecret
+	if authTokenSecret != "" {
+		secretPath = authTokenSecret
+	}
+	sm, err := config.GetSecretManager(ctx)
+	if err != nil {
+		return err
+	}
+	if sm == nil {
+		return i18n.NewError(ctx, i18n.MsgNoSecretsProvider, secretPath)
+	}
+	secret, err := sm.GetSecret(ctx, secretPath)
+	if err != nil {
+		return err
+	}
+	if authTokenSecret != "" {
+		req.SetHeader("Authorization", fmt.Sprintf("Bearer %s", secret))
+	} else {
+		req.SetHeader("Authorization", basicAuth(authUsername, secret))
+	}
+	return nil
+}
+
 func WrapRestErr(ctx context.Context, res *resty.Response, err error, key i18n.MessageKey) error {
 	var respData string
 	if res != nil {
@@ -161,6 +306,10 @@ func WrapRestErr(ctx context.Context, res *resty.Response, err error, key i18n.M
 		}
 	}
 	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return &TimeoutError{i18n.WrapError(ctx, err, key, respData)}
+		}
 		return i18n.WrapError(ctx, err, key, respData)
 	}
 	return i18n.NewError(ctx, key, respData)
@@ -20,13 +20,18 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/mocks/configmocks"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var utConfPrefix = config.NewPluginConfig("http_unit_tests")
@@ -95,6 +100,29 @@ func TestRequestRetry(t *testing.T) {
 
 }
 
+func TestRequestIDPropagated(t *testing.T) {
+
+	resetConf()
+	utConfPrefix.Set(HTTPConfigURL, "http://localhost:12345")
+
+	c := New(context.Background(), utConfPrefix)
+	httpmock.ActivateNonDefault(c.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/test",
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "req12345", req.Header.Get(HeaderRequestID))
+			return httpmock.NewStringResponder(200, `{}`)(req)
+		})
+
+	ctx := WithRequestID(context.Background(), "req12345")
+	assert.Equal(t, "req12345", GetRequestID(ctx))
+	resp, err := c.R().SetContext(ctx).Get("/test")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode())
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
 func TestConfWithProxy(t *testing.T) {
 
 	ctx := context.Background()
@@ -159,3 +187,201 @@ func TestErrResponse(t *testing.T) {
 func TestOnAfterResponseNil(t *testing.T) {
 	OnAfterResponse(nil, nil)
 }
+
+func TestPerVerbTimeouts(t *testing.T) {
+
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	resetConf()
+	utConfPrefix.Set(HTTPConfigURL, server.URL)
+	utConfPrefix.Set(HTTPConfigTimeoutGet, "10ms")
+	utConfPrefix.Set(HTTPConfigTimeoutPost, "5s")
+
+	c := New(ctx, utConfPrefix)
+	assert.Equal(t, 30*time.Second, c.GetClient().Timeout)
+
+	// GET times out
+	resp, err := c.R().SetContext(ctx).Get("/test")
+	assert.Error(t, err)
+	err = WrapRestErr(ctx, resp, err, i18n.MsgTokensRESTErr)
+	assert.Regexp(t, "FF10274", err)
+	assert.True(t, IsTimeout(err))
+
+	// POST does not
+	resp, err = c.R().SetContext(ctx).Post("/test")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode())
+}
+
+func TestVerbTimeoutReleasedAfterResponse(t *testing.T) {
+
+	ctx := context.Background()
+
+	resetConf()
+	utConfPrefix.Set(HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.Set(HTTPConfigTimeoutGet, "10s")
+	utConfPrefix.Set(HTTPConfigRetryEnabled, true)
+	utConfPrefix.Set(HTTPConfigRetryCount, 1)
+	utConfPrefix.Set(HTTPConfigRetryInitDelay, 1)
+
+	c := New(ctx, utConfPrefix)
+	httpmock.ActivateNonDefault(c.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	// The deadline is kept across a retried attempt, then released once the response is read
+	attempts := 0
+	httpmock.RegisterResponder("GET", "http://localhost:12345/test",
+		func(req *http.Request) (*http.Response, error) {
+			assert.NoError(t, req.Context().Err())
+			attempts++
+			if attempts == 1 {
+				return httpmock.NewStringResponder(500, `{}`)(req)
+			}
+			return httpmock.NewStringResponder(200, `{}`)(req)
+		})
+	resp, err := c.R().SetContext(ctx).Get("/test")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode())
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, context.Canceled, resp.Request.Context().Err())
+
+	// The deadline of a streamed response is released when the body is closed
+	resp, err = c.R().SetContext(ctx).SetDoNotParseResponse(true).Get("/test")
+	assert.NoError(t, err)
+	OnAfterResponse(c, resp)
+	assert.NoError(t, resp.Request.Context().Err())
+	err = resp.RawBody().Close()
+	assert.NoError(t, err)
+	assert.Equal(t, context.Canceled, resp.Request.Context().Err())
+}
+
+func TestVerbTimeoutExtendsClientTimeout(t *testing.T) {
+
+	resetConf()
+	utConfPrefix.Set(HTTPConfigRequestTimeout, "1s")
+	utConfPrefix.Set(HTTPConfigTimeoutPost, "1m")
+
+	c := New(context.Background(), utConfPrefix)
+	assert.Equal(t, 1*time.Minute, c.GetClient().Timeout)
+}
+
+func TestOnAfterResponseNoRetryContext(t *testing.T) {
+	resp := &resty.Response{Request: resty.New().R()}
+	OnAfterResponse(resty.New(), resp)
+	assert.Nil(t, resp.RawResponse)
+}
+
+func TestNonTimeoutError(t *testing.T) {
+	err := WrapRestErr(context.Background(), nil, fmt.Errorf("pop"), i18n.MsgTokensRESTErr)
+	assert.Regexp(t, "FF10274.*pop", err)
+	assert.False(t, IsTimeout(err))
+}
+
+func TestRequestAuthTokenFromSecretManager(t *testing.T) {
+
+	resetConf()
+	utConfPrefix.Set(HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.Set(HTTPConfigAuthTokenSecret, "secret/data/firefly#token")
+	msm := &configmocks.SecretManager{}
+	msm.On("GetSecret", mock.Anything, "secret/data/firefly#token").Return("token12345", nil)
+	config.SetSecretManager(msm)
+	defer config.Reset()
+
+	c := New(context.Background(), utConfPrefix)
+	httpmock.ActivateNonDefault(c.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/test",
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "Bearer token12345", req.Header.Get("Authorization"))
+			return httpmock.NewStringResponder(200, `{}`)(req)
+		})
+
+	resp, err := c.R().Get("/test")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode())
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	msm.AssertExpectations(t)
+}
+
+func TestRequestAuthPasswordFromSecretManager(t *testing.T) {
+
+	resetConf()
+	utConfPrefix.Set(HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.Set(HTTPConfigAuthUsername, "user")
+	utConfPrefix.Set(HTTPConfigAuthPasswordSecret, "secret/data/firefly#password")
+	msm := &configmocks.SecretManager{}
+	msm.On("GetSecret", mock.Anything, "secret/data/firefly#password").Return("pass", nil)
+	config.SetSecretManager(msm)
+	defer config.Reset()
+
+	c := New(context.Background(), utConfPrefix)
+	httpmock.ActivateNonDefault(c.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/test",
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "Basic dXNlcjpwYXNz", req.Header.Get("Authorization"))
+			return httpmock.NewStringResponder(200, `{}`)(req)
+		})
+
+	resp, err := c.R().Get("/test")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode())
+	msm.AssertExpectations(t)
+}
+
+func TestRequestAuthSecretFail(t *testing.T) {
+
+	resetConf()
+	utConfPrefix.Set(HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.Set(HTTPConfigAuthTokenSecret, "secret/data/firefly#token")
+	msm := &configmocks.SecretManager{}
+	msm.On("GetSecret", mock.Anything, "secret/data/firefly#token").Return("", fmt.Errorf("pop"))
+	config.SetSecretManager(msm)
+	defer config.Reset()
+
+	c := New(context.Background(), utConfPrefix)
+	httpmock.ActivateNonDefault(c.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	_, err := c.R().Get("/test")
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 0, httpmock.GetTotalCallCount())
+}
+
+func TestRequestAuthSecretNoProvider(t *testing.T) {
+
+	resetConf()
+	utConfPrefix.Set(HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.Set(HTTPConfigAuthTokenSecret, "secret/data/firefly#token")
+
+	c := New(context.Background(), utConfPrefix)
+	httpmock.ActivateNonDefault(c.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	_, err := c.R().Get("/test")
+	assert.Regexp(t, "FF10311", err)
+}
+
+func TestRequestAuthSecretBadProvider(t *testing.T) {
+
+	resetConf()
+	utConfPrefix.Set(HTTPConfigURL, "http://localhost:12345")
+	utConfPrefix.Set(HTTPConfigAuthTokenSecret, "secret/data/firefly#token")
+	config.Set(config.SecretsProvider, "wrong")
+	defer config.Reset()
+
+	c := New(context.Background(), utConfPrefix)
+	httpmock.ActivateNonDefault(c.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	_, err := c.R().Get("/test")
+	assert.Regexp(t, "FF10308", err)
+}
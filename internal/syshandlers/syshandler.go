@@ -32,9 +32,15 @@ import (
 	"github.com/hyperledger/firefly/pkg/identity"
 )
 
+// TokenPoolDefinitionHandler handles token pool definitions, which are broadcast with their own message type
+type TokenPoolDefinitionHandler interface {
+	HandleTokenPoolDefinition(ctx context.Context, msg *fftypes.Message, data []*fftypes.Data) (valid bool, err error)
+}
+
 // SystemHandlers interface allows components to call broadcast/private messaging functions internally (without import cycles)
 type SystemHandlers interface {
 	privatemessaging.GroupManager
+	TokenPoolDefinitionHandler
 
 	HandleSystemBroadcast(ctx context.Context, msg *fftypes.Message, data []*fftypes.Data) (valid bool, err error)
 	SendReply(ctx context.Context, event *fftypes.Event, reply *fftypes.MessageInOut)
@@ -72,7 +78,7 @@ func (sh *systemHandlers) GetGroups(ctx context.Context, filter database.AndFilt
 	return sh.messaging.GetGroups(ctx, filter)
 }
 
-func (sh *systemHandlers) ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (*fftypes.Group, error) {
+func (sh *systemHandlers) ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (*fftypes.Group, bool, error) {
 	return sh.messaging.ResolveInitGroup(ctx, msg)
 }
 
@@ -93,6 +99,7 @@ func (sh *systemHandlers) HandleSystemBroadcast(ctx context.Context, msg *fftype
 	case fftypes.SystemTagDefineNode:
 		return sh.handleNodeBroadcast(ctx, msg, data)
 	case fftypes.SystemTagDefinePool:
+		// Token pools are now broadcast with their own message type, but older nodes send them as a system definition
 		return sh.handleTokenPoolBroadcast(ctx, msg, data)
 	default:
 		l.Warnf("Unknown topic '%s' for system broadcast ID '%s'", msg.Header.Tag, msg.Header.ID)
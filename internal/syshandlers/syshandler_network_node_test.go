@@ -30,6 +30,12 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+const testNodePublicKey = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEgzjSP4ZqtxQZ96PA5uiMfapobJLY
+zG3T/e9Hv4DoWspc916MCCm3/2iViAThG7sdEMIvHh7tRRhzqlgFWvr4Lw==
+-----END PUBLIC KEY-----
+`
+
 func TestHandleSystemBroadcastNodeOk(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
@@ -42,6 +48,7 @@ func TestHandleSystemBroadcastNodeOk(t *testing.T) {
 			Peer:     "peer1",
 			Endpoint: fftypes.JSONObject{"some": "info"},
 		},
+		PublicKey: testNodePublicKey,
 	}
 	b, err := json.Marshal(&node)
 	assert.NoError(t, err)
@@ -55,7 +62,9 @@ func TestHandleSystemBroadcastNodeOk(t *testing.T) {
 	mdi.On("GetOrganizationByIdentity", mock.Anything, "0x23456").Return(&fftypes.Organization{ID: fftypes.NewUUID(), Identity: "0x23456"}, nil)
 	mdi.On("GetNode", mock.Anything, "0x23456", "node1").Return(nil, nil)
 	mdi.On("GetNodeByID", mock.Anything, node.ID).Return(nil, nil)
-	mdi.On("UpsertNode", mock.Anything, mock.Anything, true).Return(nil)
+	mdi.On("UpsertNode", mock.Anything, mock.MatchedBy(func(n *fftypes.Node) bool {
+		return n.PublicKey == testNodePublicKey
+	}), true).Return(nil)
 	mdx := sh.exchange.(*dataexchangemocks.Plugin)
 	mdx.On("AddPeer", mock.Anything, "peer1", node.DX.Endpoint).Return(nil)
 	valid, err := sh.HandleSystemBroadcast(context.Background(), &fftypes.Message{
@@ -443,6 +452,39 @@ func TestHandleSystemBroadcastNodeValidateFail(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestHandleSystemBroadcastNodeBadPublicKey(t *testing.T) {
+	sh := newTestSystemHandlers(t)
+
+	node := &fftypes.Node{
+		ID:    fftypes.NewUUID(),
+		Name:  "node1",
+		Owner: "0x23456",
+		DX: fftypes.DXInfo{
+			Peer:     "peer1",
+			Endpoint: fftypes.JSONObject{"some": "info"},
+		},
+		PublicKey: "not a key",
+	}
+	b, err := json.Marshal(&node)
+	assert.NoError(t, err)
+	data := &fftypes.Data{
+		Value: fftypes.Byteable(b),
+	}
+
+	valid, err := sh.HandleSystemBroadcast(context.Background(), &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			Namespace: "ns1",
+			Author:    "0x23456",
+			Tag:       string(fftypes.SystemTagDefineNode),
+		},
+	}, []*fftypes.Data{data})
+	assert.False(t, valid)
+	assert.NoError(t, err)
+
+	mdi := sh.database.(*databasemocks.Plugin)
+	mdi.AssertNotCalled(t, "UpsertNode", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestHandleSystemBroadcastNodeUnmarshalFail(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
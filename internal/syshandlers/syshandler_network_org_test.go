@@ -39,6 +39,7 @@ func TestHandleSystemBroadcastOrgOk(t *testing.T) {
 		Parent:      "0x23456",
 		Description: "my org",
 		Profile:     fftypes.JSONObject{"some": "info"},
+		Metadata:    fftypes.JSONObject{"role": "auditor"},
 	}
 	b, err := json.Marshal(&org)
 	assert.NoError(t, err)
@@ -53,7 +54,9 @@ func TestHandleSystemBroadcastOrgOk(t *testing.T) {
 	mdi.On("GetOrganizationByIdentity", mock.Anything, "0x12345").Return(nil, nil)
 	mdi.On("GetOrganizationByName", mock.Anything, "org1").Return(nil, nil)
 	mdi.On("GetOrganizationByID", mock.Anything, org.ID).Return(nil, nil)
-	mdi.On("UpsertOrganization", mock.Anything, mock.Anything, true).Return(nil)
+	mdi.On("UpsertOrganization", mock.Anything, mock.MatchedBy(func(o *fftypes.Organization) bool {
+		return o.Metadata.GetString("role") == "auditor"
+	}), true).Return(nil)
 	valid, err := sh.HandleSystemBroadcast(context.Background(), &fftypes.Message{
 		Header: fftypes.MessageHeader{
 			Namespace: "ns1",
@@ -81,12 +81,12 @@ func TestPrivateMessagingPassthroughs(t *testing.T) {
 	mpm := sh.messaging.(*privatemessagingmocks.Manager)
 	mpm.On("GetGroupByID", ctx, mock.Anything).Return(nil, nil)
 	mpm.On("GetGroups", ctx, mock.Anything).Return(nil, nil, nil)
-	mpm.On("ResolveInitGroup", ctx, mock.Anything).Return(nil, nil)
+	mpm.On("ResolveInitGroup", ctx, mock.Anything).Return(nil, true, nil)
 	mpm.On("EnsureLocalGroup", ctx, mock.Anything).Return(false, nil)
 
 	_, _ = sh.GetGroupByID(ctx, fftypes.NewUUID().String())
 	_, _, _ = sh.GetGroups(ctx, nil)
-	_, _ = sh.ResolveInitGroup(ctx, nil)
+	_, _, _ = sh.ResolveInitGroup(ctx, nil)
 	_, _ = sh.EnsureLocalGroup(ctx, nil)
 
 	mpm.AssertExpectations(t)
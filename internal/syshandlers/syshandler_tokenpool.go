@@ -86,6 +86,18 @@ func (sh *systemHandlers) persistTokenPool(ctx context.Context, pool *fftypes.To
 		}
 	}
 
+	// Symbols are unique within a namespace, so the first pool to claim a symbol keeps it
+	if pool.Symbol != "" {
+		existing, err := sh.database.GetTokenPoolBySymbol(ctx, pool.Namespace, pool.Symbol)
+		if err != nil {
+			return false, err // retryable
+		}
+		if existing != nil && !existing.ID.Equals(pool.ID) {
+			log.L(ctx).Errorf("Invalid token pool '%s'. Symbol '%s' is already used by pool '%s'", pool.ID, pool.Symbol, existing.ID)
+			return false, nil // not retryable
+		}
+	}
+
 	err = sh.database.UpsertTokenPool(ctx, &pool.TokenPool)
 	if err != nil {
 		if err == database.IDMismatch {
@@ -98,6 +110,19 @@ func (sh *systemHandlers) persistTokenPool(ctx context.Context, pool *fftypes.To
 	return true, nil
 }
 
+func (sh *systemHandlers) HandleTokenPoolDefinition(ctx context.Context, msg *fftypes.Message, data []*fftypes.Data) (valid bool, err error) {
+	l := log.L(ctx)
+	l.Infof("Confirming token pool definition '%s' [%s]", msg.Header.Tag, msg.Header.ID)
+
+	if msg.Header.Type != fftypes.MessageTypeTokenPoolDefinition || fftypes.SystemTag(msg.Header.Tag) != fftypes.SystemTagDefinePool {
+		l.Warnf("Unable to process token pool definition %s - unexpected type '%s' tag '%s'", msg.Header.ID, msg.Header.Type, msg.Header.Tag)
+		return false, nil
+	}
+	return sh.handleTokenPoolBroadcast(ctx, msg, data)
+}
+
+// handleTokenPoolBroadcast processes a token pool definition - either sent with its own message type, or as a
+// system definition by a node that predates that type
 func (sh *systemHandlers) handleTokenPoolBroadcast(ctx context.Context, msg *fftypes.Message, data []*fftypes.Data) (valid bool, err error) {
 	l := log.L(ctx)
 
@@ -30,7 +30,7 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-func TestHandleSystemBroadcastTokenPoolSelfOk(t *testing.T) {
+func TestHandleTokenPoolDefinitionSelfOk(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -50,8 +50,9 @@ func TestHandleSystemBroadcastTokenPoolSelfOk(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -68,6 +69,62 @@ func TestHandleSystemBroadcastTokenPoolSelfOk(t *testing.T) {
 	mdi.On("UpdateOperation", context.Background(), opID, mock.Anything).Return(nil)
 	mdi.On("GetTransactionByID", context.Background(), pool.TX.ID).Return(tx, nil)
 	mdi.On("UpsertTransaction", context.Background(), tx, false).Return(nil)
+	mdi.On("GetTokenPoolBySymbol", context.Background(), "ns1", "COIN").Return(nil, nil)
+	mdi.On("UpsertTokenPool", context.Background(), mock.MatchedBy(func(p *fftypes.TokenPool) bool {
+		return *p.ID == *pool.ID && p.Message == msg.Header.ID
+	})).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *fftypes.Event) bool {
+		return *event.Reference == *pool.ID && event.Namespace == pool.Namespace && event.Type == fftypes.EventTypePoolConfirmed
+	})).Return(nil)
+
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
+	assert.True(t, valid)
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.OpStatusSucceeded, tx.Status)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestHandleSystemBroadcastLegacyTokenPool(t *testing.T) {
+	sh := newTestSystemHandlers(t)
+
+	pool := &fftypes.TokenPoolAnnouncement{
+		TokenPool: fftypes.TokenPool{
+			ID:         fftypes.NewUUID(),
+			Namespace:  "ns1",
+			Name:       "name1",
+			Type:       fftypes.TokenTypeFungible,
+			ProtocolID: "12345",
+			Symbol:     "COIN",
+			TX: fftypes.TransactionRef{
+				Type: fftypes.TransactionTypeTokenPool,
+				ID:   fftypes.NewUUID(),
+			},
+		},
+		ProtocolTxID: "tx123",
+	}
+	msg := &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
+		},
+	}
+	b, err := json.Marshal(&pool)
+	assert.NoError(t, err)
+	data := []*fftypes.Data{{
+		Value: fftypes.Byteable(b),
+	}}
+	opID := fftypes.NewUUID()
+	operations := []*fftypes.Operation{{ID: opID}}
+	tx := &fftypes.Transaction{ProtocolID: "tx123"}
+
+	mdi := sh.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), mock.Anything).Return(operations, nil, nil)
+	mdi.On("UpdateOperation", context.Background(), opID, mock.Anything).Return(nil)
+	mdi.On("GetTransactionByID", context.Background(), pool.TX.ID).Return(tx, nil)
+	mdi.On("UpsertTransaction", context.Background(), tx, false).Return(nil)
+	mdi.On("GetTokenPoolBySymbol", context.Background(), "ns1", "COIN").Return(nil, nil)
 	mdi.On("UpsertTokenPool", context.Background(), mock.MatchedBy(func(p *fftypes.TokenPool) bool {
 		return *p.ID == *pool.ID && p.Message == msg.Header.ID
 	})).Return(nil)
@@ -83,7 +140,7 @@ func TestHandleSystemBroadcastTokenPoolSelfOk(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolSelfUpdateOpFail(t *testing.T) {
+func TestHandleTokenPoolDefinitionSelfUpdateOpFail(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -103,8 +160,9 @@ func TestHandleSystemBroadcastTokenPoolSelfUpdateOpFail(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -119,14 +177,14 @@ func TestHandleSystemBroadcastTokenPoolSelfUpdateOpFail(t *testing.T) {
 	mdi.On("GetOperations", context.Background(), mock.Anything).Return(operations, nil, nil)
 	mdi.On("UpdateOperation", context.Background(), opID, mock.Anything).Return(fmt.Errorf("pop"))
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 
 	mdi.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolSelfGetTXFail(t *testing.T) {
+func TestHandleTokenPoolDefinitionSelfGetTXFail(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -146,8 +204,9 @@ func TestHandleSystemBroadcastTokenPoolSelfGetTXFail(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -163,14 +222,14 @@ func TestHandleSystemBroadcastTokenPoolSelfGetTXFail(t *testing.T) {
 	mdi.On("UpdateOperation", context.Background(), opID, mock.Anything).Return(nil)
 	mdi.On("GetTransactionByID", context.Background(), pool.TX.ID).Return(nil, fmt.Errorf("pop"))
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 
 	mdi.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolSelfTXMismatch(t *testing.T) {
+func TestHandleTokenPoolDefinitionSelfTXMismatch(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -190,8 +249,9 @@ func TestHandleSystemBroadcastTokenPoolSelfTXMismatch(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -211,14 +271,14 @@ func TestHandleSystemBroadcastTokenPoolSelfTXMismatch(t *testing.T) {
 		return *event.Reference == *pool.ID && event.Namespace == pool.Namespace && event.Type == fftypes.EventTypePoolRejected
 	})).Return(nil)
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.False(t, valid)
 	assert.NoError(t, err)
 
 	mdi.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolSelfUpdateTXFail(t *testing.T) {
+func TestHandleTokenPoolDefinitionSelfUpdateTXFail(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -238,8 +298,9 @@ func TestHandleSystemBroadcastTokenPoolSelfUpdateTXFail(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -257,14 +318,14 @@ func TestHandleSystemBroadcastTokenPoolSelfUpdateTXFail(t *testing.T) {
 	mdi.On("GetTransactionByID", context.Background(), pool.TX.ID).Return(tx, nil)
 	mdi.On("UpsertTransaction", context.Background(), tx, false).Return(fmt.Errorf("pop"))
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 
 	mdi.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolOk(t *testing.T) {
+func TestHandleTokenPoolDefinitionOk(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -284,8 +345,9 @@ func TestHandleSystemBroadcastTokenPoolOk(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -301,6 +363,7 @@ func TestHandleSystemBroadcastTokenPoolOk(t *testing.T) {
 	mdi.On("UpsertTransaction", context.Background(), mock.MatchedBy(func(t *fftypes.Transaction) bool {
 		return t.Subject.Type == fftypes.TransactionTypeTokenPool && *t.Subject.Reference == *pool.ID
 	}), false).Return(nil)
+	mdi.On("GetTokenPoolBySymbol", context.Background(), "ns1", "COIN").Return(nil, nil)
 	mdi.On("UpsertTokenPool", context.Background(), mock.MatchedBy(func(p *fftypes.TokenPool) bool {
 		return *p.ID == *pool.ID && p.Message == msg.Header.ID
 	})).Return(nil)
@@ -313,7 +376,7 @@ func TestHandleSystemBroadcastTokenPoolOk(t *testing.T) {
 		return *p.ID == *pool.ID
 	}), "tx123").Return(nil)
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.True(t, valid)
 	assert.NoError(t, err)
 
@@ -321,7 +384,7 @@ func TestHandleSystemBroadcastTokenPoolOk(t *testing.T) {
 	mam.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolValidateTxFail(t *testing.T) {
+func TestHandleTokenPoolDefinitionValidateTxFail(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -341,8 +404,9 @@ func TestHandleSystemBroadcastTokenPoolValidateTxFail(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -360,7 +424,7 @@ func TestHandleSystemBroadcastTokenPoolValidateTxFail(t *testing.T) {
 		return *p.ID == *pool.ID
 	}), "tx123").Return(fmt.Errorf("pop"))
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 
@@ -368,7 +432,7 @@ func TestHandleSystemBroadcastTokenPoolValidateTxFail(t *testing.T) {
 	mam.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolBadTX(t *testing.T) {
+func TestHandleTokenPoolDefinitionBadTX(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -388,8 +452,9 @@ func TestHandleSystemBroadcastTokenPoolBadTX(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -410,7 +475,7 @@ func TestHandleSystemBroadcastTokenPoolBadTX(t *testing.T) {
 		return *p.ID == *pool.ID
 	}), "tx123").Return(nil)
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.False(t, valid)
 	assert.NoError(t, err)
 
@@ -418,7 +483,7 @@ func TestHandleSystemBroadcastTokenPoolBadTX(t *testing.T) {
 	mam.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolIDMismatch(t *testing.T) {
+func TestHandleTokenPoolDefinitionIDMismatch(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -438,8 +503,9 @@ func TestHandleSystemBroadcastTokenPoolIDMismatch(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -455,6 +521,7 @@ func TestHandleSystemBroadcastTokenPoolIDMismatch(t *testing.T) {
 	mdi.On("UpsertTransaction", context.Background(), mock.MatchedBy(func(t *fftypes.Transaction) bool {
 		return t.Subject.Type == fftypes.TransactionTypeTokenPool && *t.Subject.Reference == *pool.ID
 	}), false).Return(nil)
+	mdi.On("GetTokenPoolBySymbol", context.Background(), "ns1", "COIN").Return(nil, nil)
 	mdi.On("UpsertTokenPool", context.Background(), mock.MatchedBy(func(p *fftypes.TokenPool) bool {
 		return *p.ID == *pool.ID && p.Message == msg.Header.ID
 	})).Return(database.IDMismatch)
@@ -467,7 +534,65 @@ func TestHandleSystemBroadcastTokenPoolIDMismatch(t *testing.T) {
 		return *p.ID == *pool.ID
 	}), "tx123").Return(nil)
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
+	assert.False(t, valid)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mam.AssertExpectations(t)
+}
+
+func TestHandleTokenPoolDefinitionSymbolConflict(t *testing.T) {
+	sh := newTestSystemHandlers(t)
+
+	pool := &fftypes.TokenPoolAnnouncement{
+		TokenPool: fftypes.TokenPool{
+			ID:         fftypes.NewUUID(),
+			Namespace:  "ns1",
+			Name:       "name1",
+			Type:       fftypes.TokenTypeFungible,
+			ProtocolID: "12345",
+			Symbol:     "COIN",
+			TX: fftypes.TransactionRef{
+				Type: fftypes.TransactionTypeTokenPool,
+				ID:   fftypes.NewUUID(),
+			},
+		},
+		ProtocolTxID: "tx123",
+	}
+	msg := &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
+		},
+	}
+	b, err := json.Marshal(&pool)
+	assert.NoError(t, err)
+	data := []*fftypes.Data{{
+		Value: fftypes.Byteable(b),
+	}}
+	operations := []*fftypes.Operation{}
+
+	mdi := sh.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), mock.Anything).Return(operations, nil, nil)
+	mdi.On("GetTransactionByID", context.Background(), pool.TX.ID).Return(nil, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.MatchedBy(func(t *fftypes.Transaction) bool {
+		return t.Subject.Type == fftypes.TransactionTypeTokenPool && *t.Subject.Reference == *pool.ID
+	}), false).Return(nil)
+	mdi.On("GetTokenPoolBySymbol", context.Background(), "ns1", "COIN").Return(&fftypes.TokenPool{
+		ID: fftypes.NewUUID(),
+	}, nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *fftypes.Event) bool {
+		return *event.Reference == *pool.ID && event.Namespace == pool.Namespace && event.Type == fftypes.EventTypePoolRejected
+	})).Return(nil)
+
+	mam := sh.assets.(*assetmocks.Manager)
+	mam.On("ValidateTokenPoolTx", context.Background(), mock.MatchedBy(func(p *fftypes.TokenPool) bool {
+		return *p.ID == *pool.ID
+	}), "tx123").Return(nil)
+
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.False(t, valid)
 	assert.NoError(t, err)
 
@@ -475,7 +600,60 @@ func TestHandleSystemBroadcastTokenPoolIDMismatch(t *testing.T) {
 	mam.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolFailUpsert(t *testing.T) {
+func TestHandleTokenPoolDefinitionSymbolLookupFail(t *testing.T) {
+	sh := newTestSystemHandlers(t)
+
+	pool := &fftypes.TokenPoolAnnouncement{
+		TokenPool: fftypes.TokenPool{
+			ID:         fftypes.NewUUID(),
+			Namespace:  "ns1",
+			Name:       "name1",
+			Type:       fftypes.TokenTypeFungible,
+			ProtocolID: "12345",
+			Symbol:     "COIN",
+			TX: fftypes.TransactionRef{
+				Type: fftypes.TransactionTypeTokenPool,
+				ID:   fftypes.NewUUID(),
+			},
+		},
+		ProtocolTxID: "tx123",
+	}
+	msg := &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
+		},
+	}
+	b, err := json.Marshal(&pool)
+	assert.NoError(t, err)
+	data := []*fftypes.Data{{
+		Value: fftypes.Byteable(b),
+	}}
+	operations := []*fftypes.Operation{}
+
+	mdi := sh.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), mock.Anything).Return(operations, nil, nil)
+	mdi.On("GetTransactionByID", context.Background(), pool.TX.ID).Return(nil, nil)
+	mdi.On("UpsertTransaction", context.Background(), mock.MatchedBy(func(t *fftypes.Transaction) bool {
+		return t.Subject.Type == fftypes.TransactionTypeTokenPool && *t.Subject.Reference == *pool.ID
+	}), false).Return(nil)
+	mdi.On("GetTokenPoolBySymbol", context.Background(), "ns1", "COIN").Return(nil, fmt.Errorf("pop"))
+
+	mam := sh.assets.(*assetmocks.Manager)
+	mam.On("ValidateTokenPoolTx", context.Background(), mock.MatchedBy(func(p *fftypes.TokenPool) bool {
+		return *p.ID == *pool.ID
+	}), "tx123").Return(nil)
+
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
+	assert.False(t, valid)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mam.AssertExpectations(t)
+}
+
+func TestHandleTokenPoolDefinitionFailUpsert(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -495,8 +673,9 @@ func TestHandleSystemBroadcastTokenPoolFailUpsert(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -512,6 +691,7 @@ func TestHandleSystemBroadcastTokenPoolFailUpsert(t *testing.T) {
 	mdi.On("UpsertTransaction", context.Background(), mock.MatchedBy(func(t *fftypes.Transaction) bool {
 		return t.Subject.Type == fftypes.TransactionTypeTokenPool && *t.Subject.Reference == *pool.ID
 	}), false).Return(nil)
+	mdi.On("GetTokenPoolBySymbol", context.Background(), "ns1", "COIN").Return(nil, nil)
 	mdi.On("UpsertTokenPool", context.Background(), mock.MatchedBy(func(p *fftypes.TokenPool) bool {
 		return *p.ID == *pool.ID && p.Message == msg.Header.ID
 	})).Return(fmt.Errorf("pop"))
@@ -521,7 +701,7 @@ func TestHandleSystemBroadcastTokenPoolFailUpsert(t *testing.T) {
 		return *p.ID == *pool.ID
 	}), "tx123").Return(nil)
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 
@@ -529,7 +709,7 @@ func TestHandleSystemBroadcastTokenPoolFailUpsert(t *testing.T) {
 	mam.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolOpsFail(t *testing.T) {
+func TestHandleTokenPoolDefinitionOpsFail(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -549,8 +729,9 @@ func TestHandleSystemBroadcastTokenPoolOpsFail(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -562,14 +743,14 @@ func TestHandleSystemBroadcastTokenPoolOpsFail(t *testing.T) {
 	mdi := sh.database.(*databasemocks.Plugin)
 	mdi.On("GetOperations", context.Background(), mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 
 	mdi.AssertExpectations(t)
 }
 
-func TestHandleSystemBroadcastTokenPoolValidateFail(t *testing.T) {
+func TestHandleTokenPoolDefinitionValidateFail(t *testing.T) {
 	sh := newTestSystemHandlers(t)
 
 	pool := &fftypes.TokenPoolAnnouncement{
@@ -578,8 +759,9 @@ func TestHandleSystemBroadcastTokenPoolValidateFail(t *testing.T) {
 	}
 	msg := &fftypes.Message{
 		Header: fftypes.MessageHeader{
-			ID:  fftypes.NewUUID(),
-			Tag: string(fftypes.SystemTagDefinePool),
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeTokenPoolDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
 		},
 	}
 	b, err := json.Marshal(&pool)
@@ -593,9 +775,25 @@ func TestHandleSystemBroadcastTokenPoolValidateFail(t *testing.T) {
 		return event.Type == fftypes.EventTypePoolRejected
 	})).Return(nil)
 
-	valid, err := sh.HandleSystemBroadcast(context.Background(), msg, data)
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, data)
 	assert.False(t, valid)
 	assert.NoError(t, err)
 
 	mdi.AssertExpectations(t)
 }
+
+func TestHandleTokenPoolDefinitionWrongType(t *testing.T) {
+	sh := newTestSystemHandlers(t)
+
+	msg := &fftypes.Message{
+		Header: fftypes.MessageHeader{
+			ID:   fftypes.NewUUID(),
+			Type: fftypes.MessageTypeDefinition,
+			Tag:  string(fftypes.SystemTagDefinePool),
+		},
+	}
+
+	valid, err := sh.HandleTokenPoolDefinition(context.Background(), msg, []*fftypes.Data{})
+	assert.False(t, valid)
+	assert.NoError(t, err)
+}
@@ -19,6 +19,10 @@ package fftokens
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/hyperledger/firefly/internal/config"
@@ -35,8 +39,10 @@ type FFTokens struct {
 	capabilities   *tokens.Capabilities
 	callbacks      tokens.Callbacks
 	configuredName string
+	url            string
 	client         *resty.Client
 	wsconn         wsclient.WSClient
+	router         tokens.Router
 }
 
 type wsEvent struct {
@@ -48,8 +54,11 @@ type wsEvent struct {
 type msgType string
 
 const (
-	messageReceipt   msgType = "receipt"
-	messageTokenPool msgType = "token-pool"
+	messageReceipt           msgType = "receipt"
+	messageTokenPool         msgType = "token-pool"
+	messageTokenTransfer     msgType = "token-transfer"
+	messageTokenPoolFreeze   msgType = "token-pool-freeze"
+	messageTokenPoolUnfreeze msgType = "token-pool-unfreeze"
 )
 
 type createPool struct {
@@ -59,6 +68,26 @@ type createPool struct {
 	Config     fftypes.JSONObject `json:"config"`
 }
 
+type mintTokens struct {
+	RequestID string `json:"requestId"`
+	To        string `json:"to"`
+	Amount    string `json:"amount"`
+}
+
+type poolRequest struct {
+	RequestID string `json:"requestId"`
+}
+
+type mintRecipient struct {
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+}
+
+type mintBatch struct {
+	RequestID  string          `json:"requestId"`
+	Recipients []mintRecipient `json:"recipients"`
+}
+
 func (h *FFTokens) Name() string {
 	return "fftokens"
 }
@@ -68,12 +97,15 @@ func (h *FFTokens) Init(ctx context.Context, name string, prefix config.Prefix,
 	h.callbacks = callbacks
 	h.configuredName = name
 
-	if prefix.GetString(restclient.HTTPConfigURL) == "" {
+	h.url = prefix.GetString(restclient.HTTPConfigURL)
+	if h.url == "" {
 		return i18n.NewError(ctx, i18n.MsgMissingPluginConfig, "url", "tokens.fftokens")
 	}
 
 	h.client = restclient.New(h.ctx, prefix)
-	h.capabilities = &tokens.Capabilities{}
+	h.capabilities = &tokens.Capabilities{
+		SupportsBatchMint: true,
+	}
 
 	if prefix.GetString(wsclient.WSConfigKeyPath) == "" {
 		prefix.Set(wsclient.WSConfigKeyPath, "/api/ws")
@@ -88,6 +120,10 @@ func (h *FFTokens) Init(ctx context.Context, name string, prefix config.Prefix,
 	return nil
 }
 
+func (h *FFTokens) SetRouter(router tokens.Router) {
+	h.router = router
+}
+
 func (h *FFTokens) Start() error {
 	return h.wsconn.Connect()
 }
@@ -146,6 +182,50 @@ func (h *FFTokens) handleTokenPoolCreate(ctx context.Context, data fftypes.JSONO
 	return h.callbacks.TokenPoolCreated(h, fftypes.FFEnum(tokenType), txID, protocolID, operatorAddress, txHash, tx)
 }
 
+func (h *FFTokens) handleTokenTransfer(ctx context.Context, data fftypes.JSONObject) (err error) {
+	protocolID := data.GetString("poolId")
+	operatorAddress := data.GetString("operator")
+	from := data.GetString("from")
+	to := data.GetString("to")
+	amountString, _ := data.GetStringOk("amount")
+	tx := data.GetObject("transaction")
+	txHash := tx.GetString("transactionHash")
+
+	if protocolID == "" ||
+		operatorAddress == "" ||
+		(from == "" && to == "") ||
+		data["amount"] == nil ||
+		txHash == "" {
+		log.L(ctx).Errorf("TokenTransfer event is not valid - missing data: %+v", data)
+		return nil // move on
+	}
+
+	// Amounts are arbitrary size integers, so are usually sent as strings
+	amount, amountOK := new(big.Int).SetString(amountString, 10)
+	if !amountOK || amount.Sign() < 0 {
+		log.L(ctx).Errorf("TokenTransfer event is not valid - invalid amount '%v': %+v", data["amount"], data)
+		return nil // move on
+	}
+
+	// If there's an error dispatching the event, we must return the error and shutdown
+	return h.callbacks.TokensTransferred(h, protocolID, operatorAddress, from, to, (*fftypes.BigInt)(amount), txHash, tx)
+}
+
+func (h *FFTokens) handleTokenPoolStatus(ctx context.Context, data fftypes.JSONObject, status fftypes.TokenPoolStatus) (err error) {
+	protocolID := data.GetString("poolId")
+	tx := data.GetObject("transaction")
+	txHash := tx.GetString("transactionHash")
+
+	if protocolID == "" ||
+		txHash == "" {
+		log.L(ctx).Errorf("TokenPool status event is not valid - missing data: %+v", data)
+		return nil // move on
+	}
+
+	// If there's an error dispatching the event, we must return the error and shutdown
+	return h.callbacks.TokenPoolStatusChanged(h, protocolID, status, txHash, tx)
+}
+
 func (h *FFTokens) eventLoop() {
 	defer h.wsconn.Close()
 	l := log.L(h.ctx).WithField("role", "event-loop")
@@ -168,11 +248,20 @@ func (h *FFTokens) eventLoop() {
 				continue // Swallow this and move on
 			}
 			l.Debugf("Received %s event %s", msg.Event, msg.ID)
-			switch msg.Event {
-			case messageReceipt:
-				err = h.handleReceipt(ctx, msg.Data)
-			case messageTokenPool:
-				err = h.handleTokenPoolCreate(ctx, msg.Data)
+			target := h.route(ctx, msg.Data)
+			switch {
+			case target == nil:
+				// Unknown connector - already logged by the router, so skip
+			case msg.Event == messageReceipt:
+				err = target.handleReceipt(ctx, msg.Data)
+			case msg.Event == messageTokenPool:
+				err = target.handleTokenPoolCreate(ctx, msg.Data)
+			case msg.Event == messageTokenTransfer:
+				err = target.handleTokenTransfer(ctx, msg.Data)
+			case msg.Event == messageTokenPoolFreeze:
+				err = target.handleTokenPoolStatus(ctx, msg.Data, fftypes.TokenPoolStatusFrozen)
+			case msg.Event == messageTokenPoolUnfreeze:
+				err = target.handleTokenPoolStatus(ctx, msg.Data, fftypes.TokenPoolStatusActive)
 			default:
 				l.Errorf("Message unexpected: %s", msg.Event)
 			}
@@ -210,3 +299,85 @@ func (h *FFTokens) CreateTokenPool(ctx context.Context, operationID *fftypes.UUI
 	}
 	return nil
 }
+
+func (h *FFTokens) MintTokens(ctx context.Context, operationID *fftypes.UUID, identity *fftypes.Identity, pool *fftypes.TokenPool, recipient tokens.TokenRecipient) error {
+	res, err := h.client.R().SetContext(ctx).
+		SetBody(&mintTokens{
+			RequestID: operationID.String(),
+			To:        recipient.Address,
+			Amount:    strconv.FormatUint(recipient.Amount, 10),
+		}).
+		Post(fmt.Sprintf("/api/v1/pool/%s/mint", url.PathEscape(pool.ProtocolID)))
+	if err != nil || !res.IsSuccess() {
+		return restclient.WrapRestErr(ctx, res, err, i18n.MsgTokensRESTErr)
+	}
+	return nil
+}
+
+func (h *FFTokens) BatchMint(ctx context.Context, operationID *fftypes.UUID, identity *fftypes.Identity, pool *fftypes.TokenPool, recipients []tokens.TokenRecipient) error {
+	body := &mintBatch{
+		RequestID:  operationID.String(),
+		Recipients: make([]mintRecipient, len(recipients)),
+	}
+	for i, r := range recipients {
+		body.Recipients[i] = mintRecipient{
+			To:     r.Address,
+			Amount: strconv.FormatUint(r.Amount, 10),
+		}
+	}
+	res, err := h.client.R().SetContext(ctx).
+		SetBody(body).
+		Post(fmt.Sprintf("/api/v1/pool/%s/mintBatch", url.PathEscape(pool.ProtocolID)))
+	if err != nil || !res.IsSuccess() {
+		return restclient.WrapRestErr(ctx, res, err, i18n.MsgTokensRESTErr)
+	}
+	return nil
+}
+
+func (h *FFTokens) FreezeTokenPool(ctx context.Context, operationID *fftypes.UUID, pool *fftypes.TokenPool) error {
+	return h.poolRequest(ctx, operationID, pool, "freeze")
+}
+
+func (h *FFTokens) UnfreezeTokenPool(ctx context.Context, operationID *fftypes.UUID, pool *fftypes.TokenPool) error {
+	return h.poolRequest(ctx, operationID, pool, "unfreeze")
+}
+
+func (h *FFTokens) poolRequest(ctx context.Context, operationID *fftypes.UUID, pool *fftypes.TokenPool, action string) error {
+	res, err := h.client.R().SetContext(ctx).
+		SetBody(&poolRequest{
+			RequestID: operationID.String(),
+		}).
+		Post(fmt.Sprintf("/api/v1/pool/%s/%s", url.PathEscape(pool.ProtocolID), action))
+	if err != nil || !res.IsSuccess() {
+		return restclient.WrapRestErr(ctx, res, err, i18n.MsgTokensRESTErr)
+	}
+	return nil
+}
+
+func (h *FFTokens) ReplayTokenPoolEvents(ctx context.Context, from, to *fftypes.FFTime) error {
+	req := h.client.R().SetContext(ctx).
+		SetQueryParam("type", string(messageTokenPool))
+	if from != nil {
+		req.SetQueryParam("since", from.String())
+	}
+	if to != nil {
+		req.SetQueryParam("until", to.String())
+	}
+	var events []*wsEvent
+	res, err := req.SetResult(&events).Get("/api/v1/events")
+	if err != nil || !res.IsSuccess() {
+		return restclient.WrapRestErr(ctx, res, err, i18n.MsgTokensRESTErr)
+	}
+
+	log.L(ctx).Infof("Replaying %d token pool events", len(events))
+	for _, event := range events {
+		if event.Event != messageTokenPool {
+			continue
+		}
+		// Duplicates are safe, as the callback ignores a pool that has already been announced
+		if err := h.handleTokenPoolCreate(ctx, event.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
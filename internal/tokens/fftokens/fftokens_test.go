@@ -161,6 +161,248 @@ func TestCreateTokenPoolError(t *testing.T) {
 	assert.Regexp(t, "FF10274", err)
 }
 
+func TestMintTokens(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	opID := fftypes.NewUUID()
+	pool := &fftypes.TokenPool{
+		ProtocolID: "F1",
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/pool/F1/mint", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"requestId": opID.String(),
+				"to":        "0x123",
+				"amount":    "10",
+			}, body)
+			return httpmock.NewJsonResponderOrPanic(202, fftypes.JSONObject{"id": "1"})(req)
+		})
+
+	err := h.MintTokens(context.Background(), opID, &fftypes.Identity{}, pool, tokens.TokenRecipient{Address: "0x123", Amount: 10})
+	assert.NoError(t, err)
+}
+
+func TestMintTokensError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	pool := &fftypes.TokenPool{
+		ProtocolID: "F1",
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/pool/F1/mint", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	err := h.MintTokens(context.Background(), fftypes.NewUUID(), &fftypes.Identity{}, pool, tokens.TokenRecipient{Address: "0x123", Amount: 10})
+	assert.Regexp(t, "FF10274", err)
+}
+
+func TestFreezeTokenPool(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	opID := fftypes.NewUUID()
+	pool := &fftypes.TokenPool{
+		ProtocolID: "F1",
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/pool/F1/freeze", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"requestId": opID.String(),
+			}, body)
+			return httpmock.NewJsonResponderOrPanic(202, fftypes.JSONObject{"id": "1"})(req)
+		})
+
+	err := h.FreezeTokenPool(context.Background(), opID, pool)
+	assert.NoError(t, err)
+}
+
+func TestUnfreezeTokenPool(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	opID := fftypes.NewUUID()
+	pool := &fftypes.TokenPool{
+		ProtocolID: "F1",
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/pool/F1/unfreeze", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"requestId": opID.String(),
+			}, body)
+			return httpmock.NewJsonResponderOrPanic(202, fftypes.JSONObject{"id": "1"})(req)
+		})
+
+	err := h.UnfreezeTokenPool(context.Background(), opID, pool)
+	assert.NoError(t, err)
+}
+
+func TestFreezeTokenPoolError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	pool := &fftypes.TokenPool{
+		ProtocolID: "F1",
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/pool/F1/freeze", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	err := h.FreezeTokenPool(context.Background(), fftypes.NewUUID(), pool)
+	assert.Regexp(t, "FF10274", err)
+}
+
+func TestBatchMint(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	assert.True(t, h.Capabilities().SupportsBatchMint)
+
+	opID := fftypes.NewUUID()
+	pool := &fftypes.TokenPool{
+		ProtocolID: "F1",
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/pool/F1/mintBatch", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"requestId": opID.String(),
+				"recipients": []interface{}{
+					map[string]interface{}{"to": "0x123", "amount": "10"},
+					map[string]interface{}{"to": "0x456", "amount": "20"},
+				},
+			}, body)
+			return httpmock.NewJsonResponderOrPanic(202, fftypes.JSONObject{"id": "1"})(req)
+		})
+
+	err := h.BatchMint(context.Background(), opID, &fftypes.Identity{}, pool, []tokens.TokenRecipient{
+		{Address: "0x123", Amount: 10},
+		{Address: "0x456", Amount: 20},
+	})
+	assert.NoError(t, err)
+}
+
+func TestBatchMintError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	pool := &fftypes.TokenPool{
+		ProtocolID: "F1",
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/pool/F1/mintBatch", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	err := h.BatchMint(context.Background(), fftypes.NewUUID(), &fftypes.Identity{}, pool, []tokens.TokenRecipient{
+		{Address: "0x123", Amount: 10},
+	})
+	assert.Regexp(t, "FF10274", err)
+}
+
+func TestReplayTokenPoolEvents(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	txID := fftypes.NewUUID()
+	from := fftypes.UnixTime(1000)
+	to := fftypes.UnixTime(2000)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/events", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "token-pool", req.URL.Query().Get("type"))
+			assert.Equal(t, from.String(), req.URL.Query().Get("since"))
+			assert.Equal(t, to.String(), req.URL.Query().Get("until"))
+			return httpmock.NewJsonResponderOrPanic(200, []fftypes.JSONObject{
+				{"id": "1", "event": "token-pool", "data": fftypes.JSONObject{
+					"trackingId": txID.String(), "type": "fungible", "poolId": "F1", "operator": "0x0",
+					"transaction": fftypes.JSONObject{"transactionHash": "abc"},
+				}},
+				{"id": "2", "event": "token-transfer", "data": fftypes.JSONObject{}},
+			})(req)
+		})
+
+	mcb := h.callbacks.(*tokenmocks.Callbacks)
+	mcb.On("TokenPoolCreated", h, fftypes.TokenTypeFungible, txID, "F1", "0x0", "abc", fftypes.JSONObject{"transactionHash": "abc"}).Return(nil).Once()
+
+	err := h.ReplayTokenPoolEvents(context.Background(), from, to)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestReplayTokenPoolEventsDuplicate(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	txID := fftypes.NewUUID()
+	poolEvent := fftypes.JSONObject{"id": "1", "event": "token-pool", "data": fftypes.JSONObject{
+		"trackingId": txID.String(), "type": "fungible", "poolId": "F1", "operator": "0x0",
+		"transaction": fftypes.JSONObject{"transactionHash": "abc"},
+	}}
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/events", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Empty(t, req.URL.Query().Get("since"))
+			assert.Empty(t, req.URL.Query().Get("until"))
+			return httpmock.NewJsonResponderOrPanic(200, []fftypes.JSONObject{poolEvent, poolEvent})(req)
+		})
+
+	// Both deliveries resolve to the same transaction, which the callback ignores once announced
+	mcb := h.callbacks.(*tokenmocks.Callbacks)
+	mcb.On("TokenPoolCreated", h, fftypes.TokenTypeFungible, txID, "F1", "0x0", "abc", mock.Anything).Return(nil).Twice()
+
+	err := h.ReplayTokenPoolEvents(context.Background(), nil, nil)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestReplayTokenPoolEventsCallbackFail(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	txID := fftypes.NewUUID()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/events", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, []fftypes.JSONObject{
+			{"id": "1", "event": "token-pool", "data": fftypes.JSONObject{
+				"trackingId": txID.String(), "type": "fungible", "poolId": "F1", "operator": "0x0",
+				"transaction": fftypes.JSONObject{"transactionHash": "abc"},
+			}},
+		}))
+
+	mcb := h.callbacks.(*tokenmocks.Callbacks)
+	mcb.On("TokenPoolCreated", h, fftypes.TokenTypeFungible, txID, "F1", "0x0", "abc", mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := h.ReplayTokenPoolEvents(context.Background(), nil, nil)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReplayTokenPoolEventsError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/events", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	err := h.ReplayTokenPoolEvents(context.Background(), nil, nil)
+	assert.Regexp(t, "FF10274", err)
+}
+
 func TestEvents(t *testing.T) {
 	h, toServer, fromServer, _, done := newTestFFTokens(t)
 	defer done()
@@ -206,6 +448,63 @@ func TestEvents(t *testing.T) {
 	msg = <-toServer
 	assert.Equal(t, `{"data":{"id":"8"},"event":"ack"}`, string(msg))
 
+	// token-transfer: missing data
+	fromServer <- `{"id":"9","event":"token-transfer"}`
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"9"},"event":"ack"}`, string(msg))
+
+	// token-transfer: missing operator
+	fromServer <- `{"id":"9a","event":"token-transfer","data":{"poolId":"F1","from":"0x0","to":"0x1","amount":"5","transaction":{"transactionHash":"abc"}}}`
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"9a"},"event":"ack"}`, string(msg))
+
+	// token-transfer: invalid amount
+	fromServer <- `{"id":"10","event":"token-transfer","data":{"poolId":"F1","operator":"0x9","from":"0x0","to":"0x1","amount":"bad","transaction":{"transactionHash":"abc"}}}`
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"10"},"event":"ack"}`, string(msg))
+
+	// token-transfer: success
+	mcb.On("TokensTransferred", h, "F1", "0x9", "0x0", "0x1", fftypes.NewBigInt(5), "abc", fftypes.JSONObject{"transactionHash": "abc"}).Return(nil)
+	fromServer <- `{"id":"11","event":"token-transfer","data":{"poolId":"F1","operator":"0x9","from":"0x0","to":"0x1","amount":"5","transaction":{"transactionHash":"abc"}}}`
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"11"},"event":"ack"}`, string(msg))
+
+	// token-transfer: success with a numeric amount
+	mcb.On("TokensTransferred", h, "F1", "0x9", "0x0", "0x1", fftypes.NewBigInt(7), "abc", fftypes.JSONObject{"transactionHash": "abc"}).Return(nil)
+	fromServer <- `{"id":"12","event":"token-transfer","data":{"poolId":"F1","operator":"0x9","from":"0x0","to":"0x1","amount":7,"transaction":{"transactionHash":"abc"}}}`
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"12"},"event":"ack"}`, string(msg))
+
+	// token-transfer: success with an amount larger than an int64
+	mcb.On("TokensTransferred", h, "F1", "0x9", "0x0", "0x1", mock.MatchedBy(func(amount *fftypes.BigInt) bool {
+		return amount.String() == "100000000000000000000"
+	}), "abc", fftypes.JSONObject{"transactionHash": "abc"}).Return(nil)
+	fromServer <- `{"id":"13","event":"token-transfer","data":{"poolId":"F1","operator":"0x9","from":"0x0","to":"0x1","amount":"100000000000000000000","transaction":{"transactionHash":"abc"}}}`
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"13"},"event":"ack"}`, string(msg))
+
+	// token-transfer: negative amount
+	fromServer <- `{"id":"14","event":"token-transfer","data":{"poolId":"F1","operator":"0x9","from":"0x0","to":"0x1","amount":"-1","transaction":{"transactionHash":"abc"}}}`
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"14"},"event":"ack"}`, string(msg))
+
+	// token-pool-freeze: missing data
+	fromServer <- `{"id":"15","event":"token-pool-freeze","data":{"poolId":"F1"}}`
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"15"},"event":"ack"}`, string(msg))
+
+	// token-pool-freeze: success
+	mcb.On("TokenPoolStatusChanged", h, "F1", fftypes.TokenPoolStatusFrozen, "abc", fftypes.JSONObject{"transactionHash": "abc"}).Return(nil)
+	fromServer <- `{"id":"16","event":"token-pool-freeze","data":{"poolId":"F1","transaction":{"transactionHash":"abc"}}}`
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"16"},"event":"ack"}`, string(msg))
+
+	// token-pool-unfreeze: success
+	mcb.On("TokenPoolStatusChanged", h, "F1", fftypes.TokenPoolStatusActive, "def", fftypes.JSONObject{"transactionHash": "def"}).Return(nil)
+	fromServer <- `{"id":"17","event":"token-pool-unfreeze","data":{"poolId":"F1","transaction":{"transactionHash":"def"}}}`
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"17"},"event":"ack"}`, string(msg))
+
 	mcb.AssertExpectations(t)
 }
 
@@ -0,0 +1,52 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftokens
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// route returns the plugin instance that should process an event delivered to this instance, using the
+// connectorName field on the event. This allows a single connection to deliver events on behalf of
+// multiple configured connectors. Events without a connectorName are processed by this instance.
+//
+// Nil is returned if the event should be skipped, because it names a connector that is not configured,
+// or a connector that is configured against a different token connector URL. A connection may
+// only deliver events for connectors that share its endpoint.
+func (h *FFTokens) route(ctx context.Context, data fftypes.JSONObject) *FFTokens {
+	name := data.GetString("connectorName")
+	if name == "" || name == h.configuredName {
+		return h
+	}
+	var target *FFTokens
+	if h.router != nil {
+		target, _ = h.router.Lookup(name).(*FFTokens)
+	}
+	if target == nil {
+		log.L(ctx).Errorf("Event for unknown token connector '%s' skipped: %+v", name, data)
+		return nil
+	}
+	if target.url != h.url {
+		log.L(ctx).Errorf("Event for token connector '%s' skipped, as it was delivered by connector '%s' at a different URL: %+v", name, h.configuredName, data)
+		return nil
+	}
+	log.L(ctx).Debugf("Routing event from connector '%s' to connector '%s'", h.configuredName, name)
+	return target
+}
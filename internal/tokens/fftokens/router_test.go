@@ -0,0 +1,123 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftokens
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/mocks/wsmocks"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRouteToRegisteredConnector(t *testing.T) {
+	r := &tokenmocks.Router{}
+	h1 := &FFTokens{configuredName: "tok1", url: "http://tokens", router: r}
+	h2 := &FFTokens{configuredName: "tok2", url: "http://tokens", router: r}
+	r.On("Lookup", "tok2").Return(h2)
+
+	ctx := context.Background()
+	assert.Equal(t, h2, h1.route(ctx, fftypes.JSONObject{"connectorName": "tok2"}))
+	assert.Equal(t, h1, h1.route(ctx, fftypes.JSONObject{"connectorName": "tok1"}))
+	assert.Equal(t, h1, h1.route(ctx, fftypes.JSONObject{}))
+}
+
+func TestRouteUnknownConnector(t *testing.T) {
+	r := &tokenmocks.Router{}
+	h1 := &FFTokens{configuredName: "tok1", url: "http://tokens", router: r}
+	r.On("Lookup", "unknown").Return(nil)
+
+	assert.Nil(t, h1.route(context.Background(), fftypes.JSONObject{"connectorName": "unknown"}))
+}
+
+func TestRouteNoRouter(t *testing.T) {
+	h1 := &FFTokens{configuredName: "tok1", url: "http://tokens"}
+
+	assert.Nil(t, h1.route(context.Background(), fftypes.JSONObject{"connectorName": "tok2"}))
+}
+
+func TestRouteOtherPluginType(t *testing.T) {
+	r := &tokenmocks.Router{}
+	h1 := &FFTokens{configuredName: "tok1", url: "http://tokens", router: r}
+	r.On("Lookup", "tok2").Return(&tokenmocks.Plugin{})
+
+	assert.Nil(t, h1.route(context.Background(), fftypes.JSONObject{"connectorName": "tok2"}))
+}
+
+func TestRouteConnectorDifferentURL(t *testing.T) {
+	r := &tokenmocks.Router{}
+	h1 := &FFTokens{configuredName: "tok1", url: "http://tokens1", router: r}
+	h2 := &FFTokens{configuredName: "tok2", url: "http://tokens2", router: r}
+	r.On("Lookup", "tok2").Return(h2)
+
+	assert.Nil(t, h1.route(context.Background(), fftypes.JSONObject{"connectorName": "tok2"}))
+}
+
+func TestEventLoopRoutesByConnectorName(t *testing.T) {
+	r := &tokenmocks.Router{}
+	cb1 := &tokenmocks.Callbacks{}
+	cb2 := &tokenmocks.Callbacks{}
+	wsm := &wsmocks.WSClient{}
+	h1 := &FFTokens{
+		ctx:            context.Background(),
+		callbacks:      cb1,
+		configuredName: "tok1",
+		url:            "http://tokens",
+		wsconn:         wsm,
+		router:         r,
+	}
+	h2 := &FFTokens{
+		ctx:            context.Background(),
+		callbacks:      cb2,
+		configuredName: "tok2",
+		url:            "http://tokens",
+		router:         r,
+	}
+	r.On("Lookup", "tok2").Return(h2)
+	r.On("Lookup", "unknown").Return(nil)
+
+	opID := fftypes.NewUUID()
+	txID := fftypes.NewUUID()
+	events := make(chan []byte, 3)
+	events <- []byte(`{"id":"1","event":"receipt","data":{"connectorName":"tok2","id":"` + opID.String() + `","success":true}}`)
+	events <- []byte(`{"id":"2","event":"token-pool","data":{"connectorName":"tok2","trackingId":"` + txID.String() + `","type":"fungible","poolId":"F1","operator":"0x0","transaction":{"transactionHash":"abc"}}}`)
+	events <- []byte(`{"id":"3","event":"token-pool","data":{"connectorName":"unknown","trackingId":"` + txID.String() + `","type":"fungible","poolId":"F2","operator":"0x0","transaction":{"transactionHash":"abc"}}}`)
+	close(events)
+
+	cb2.On("TokensOpUpdate", h2, opID, fftypes.OpStatusSucceeded, "", mock.Anything).Return(nil)
+	cb2.On("TokenPoolCreated", h2, fftypes.TokenTypeFungible, txID, "F1", "0x0", "abc", mock.Anything).Return(nil)
+	wsm.On("Close").Return()
+	wsm.On("Receive").Return((<-chan []byte)(events))
+	wsm.On("Send", mock.Anything, []byte(`{"data":{"id":"2"},"event":"ack"}`)).Return(nil)
+	wsm.On("Send", mock.Anything, []byte(`{"data":{"id":"3"},"event":"ack"}`)).Return(nil)
+
+	h1.eventLoop()
+
+	cb1.AssertExpectations(t)
+	cb2.AssertExpectations(t)
+	wsm.AssertExpectations(t)
+}
+
+func TestSetRouter(t *testing.T) {
+	r := &tokenmocks.Router{}
+	h := &FFTokens{}
+	h.SetRouter(r)
+	assert.Equal(t, r, h.router)
+}
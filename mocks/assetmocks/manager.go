@@ -18,6 +18,29 @@ type Manager struct {
 	mock.Mock
 }
 
+// BatchMint provides a mock function with given fields: ctx, ns, typeName, poolName, recipients
+func (_m *Manager) BatchMint(ctx context.Context, ns string, typeName string, poolName string, recipients []tokens.TokenRecipient) ([]*fftypes.Operation, error) {
+	ret := _m.Called(ctx, ns, typeName, poolName, recipients)
+
+	var r0 []*fftypes.Operation
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []tokens.TokenRecipient) []*fftypes.Operation); ok {
+		r0 = rf(ctx, ns, typeName, poolName, recipients)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.Operation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, []tokens.TokenRecipient) error); ok {
+		r1 = rf(ctx, ns, typeName, poolName, recipients)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTokenPool provides a mock function with given fields: ctx, ns, typeName, pool, waitConfirm
 func (_m *Manager) CreateTokenPool(ctx context.Context, ns string, typeName string, pool *fftypes.TokenPool, waitConfirm bool) (*fftypes.TokenPool, error) {
 	ret := _m.Called(ctx, ns, typeName, pool, waitConfirm)
@@ -64,6 +87,29 @@ func (_m *Manager) CreateTokenPoolWithID(ctx context.Context, ns string, id *fft
 	return r0, r1
 }
 
+// FreezeTokenPool provides a mock function with given fields: ctx, ns, typeName, name
+func (_m *Manager) FreezeTokenPool(ctx context.Context, ns string, typeName string, name string) (*fftypes.TokenPool, error) {
+	ret := _m.Called(ctx, ns, typeName, name)
+
+	var r0 *fftypes.TokenPool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *fftypes.TokenPool); ok {
+		r0 = rf(ctx, ns, typeName, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.TokenPool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ns, typeName, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTokenAccounts provides a mock function with given fields: ctx, ns, typeName, name, filter
 func (_m *Manager) GetTokenAccounts(ctx context.Context, ns string, typeName string, name string, filter database.AndFilter) ([]*fftypes.TokenAccount, *database.FilterResult, error) {
 	ret := _m.Called(ctx, ns, typeName, name, filter)
@@ -96,6 +142,38 @@ func (_m *Manager) GetTokenAccounts(ctx context.Context, ns string, typeName str
 	return r0, r1, r2
 }
 
+// GetTokenBalances provides a mock function with given fields: ctx, ns, poolID, filter
+func (_m *Manager) GetTokenBalances(ctx context.Context, ns string, poolID string, filter database.AndFilter) ([]*fftypes.TokenBalance, *database.FilterResult, error) {
+	ret := _m.Called(ctx, ns, poolID, filter)
+
+	var r0 []*fftypes.TokenBalance
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, database.AndFilter) []*fftypes.TokenBalance); ok {
+		r0 = rf(ctx, ns, poolID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.TokenBalance)
+		}
+	}
+
+	var r1 *database.FilterResult
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, database.AndFilter) *database.FilterResult); ok {
+		r1 = rf(ctx, ns, poolID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*database.FilterResult)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, database.AndFilter) error); ok {
+		r2 = rf(ctx, ns, poolID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetTokenPool provides a mock function with given fields: ctx, ns, typeName, name
 func (_m *Manager) GetTokenPool(ctx context.Context, ns string, typeName string, name string) (*fftypes.TokenPool, error) {
 	ret := _m.Called(ctx, ns, typeName, name)
@@ -119,6 +197,29 @@ func (_m *Manager) GetTokenPool(ctx context.Context, ns string, typeName string,
 	return r0, r1
 }
 
+// GetTokenPoolBySymbol provides a mock function with given fields: ctx, ns, symbol
+func (_m *Manager) GetTokenPoolBySymbol(ctx context.Context, ns string, symbol string) (*fftypes.TokenPool, error) {
+	ret := _m.Called(ctx, ns, symbol)
+
+	var r0 *fftypes.TokenPool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *fftypes.TokenPool); ok {
+		r0 = rf(ctx, ns, symbol)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.TokenPool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, ns, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTokenPools provides a mock function with given fields: ctx, ns, typeName, filter
 func (_m *Manager) GetTokenPools(ctx context.Context, ns string, typeName string, filter database.AndFilter) ([]*fftypes.TokenPool, *database.FilterResult, error) {
 	ret := _m.Called(ctx, ns, typeName, filter)
@@ -151,6 +252,20 @@ func (_m *Manager) GetTokenPools(ctx context.Context, ns string, typeName string
 	return r0, r1, r2
 }
 
+// ReplayTokenPoolEvents provides a mock function with given fields: ctx, typeName, from, to
+func (_m *Manager) ReplayTokenPoolEvents(ctx context.Context, typeName string, from *fftypes.FFTime, to *fftypes.FFTime) error {
+	ret := _m.Called(ctx, typeName, from, to)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.FFTime, *fftypes.FFTime) error); ok {
+		r0 = rf(ctx, typeName, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields:
 func (_m *Manager) Start() error {
 	ret := _m.Called()
@@ -179,6 +294,57 @@ func (_m *Manager) TokenPoolCreated(tk tokens.Plugin, tokenType fftypes.FFEnum,
 	return r0
 }
 
+// TokenPoolStatusChanged provides a mock function with given fields: tk, poolProtocolID, status, protocolTxID, additionalInfo
+func (_m *Manager) TokenPoolStatusChanged(tk tokens.Plugin, poolProtocolID string, status fftypes.FFEnum, protocolTxID string, additionalInfo fftypes.JSONObject) error {
+	ret := _m.Called(tk, poolProtocolID, status, protocolTxID, additionalInfo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(tokens.Plugin, string, fftypes.FFEnum, string, fftypes.JSONObject) error); ok {
+		r0 = rf(tk, poolProtocolID, status, protocolTxID, additionalInfo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TokensTransferred provides a mock function with given fields: tk, poolProtocolID, signingKey, from, to, amount, protocolTxID, additionalInfo
+func (_m *Manager) TokensTransferred(tk tokens.Plugin, poolProtocolID string, signingKey string, from string, to string, amount *fftypes.BigInt, protocolTxID string, additionalInfo fftypes.JSONObject) error {
+	ret := _m.Called(tk, poolProtocolID, signingKey, from, to, amount, protocolTxID, additionalInfo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(tokens.Plugin, string, string, string, string, *fftypes.BigInt, string, fftypes.JSONObject) error); ok {
+		r0 = rf(tk, poolProtocolID, signingKey, from, to, amount, protocolTxID, additionalInfo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnfreezeTokenPool provides a mock function with given fields: ctx, ns, typeName, name
+func (_m *Manager) UnfreezeTokenPool(ctx context.Context, ns string, typeName string, name string) (*fftypes.TokenPool, error) {
+	ret := _m.Called(ctx, ns, typeName, name)
+
+	var r0 *fftypes.TokenPool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *fftypes.TokenPool); ok {
+		r0 = rf(ctx, ns, typeName, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.TokenPool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ns, typeName, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateTokenPoolTx provides a mock function with given fields: ctx, pool, protocolTxID
 func (_m *Manager) ValidateTokenPoolTx(ctx context.Context, pool *fftypes.TokenPool, protocolTxID string) error {
 	ret := _m.Called(ctx, pool, protocolTxID)
@@ -3,6 +3,8 @@
 package batchmocks
 
 import (
+	context "context"
+
 	batch "github.com/hyperledger/firefly/internal/batch"
 	fftypes "github.com/hyperledger/firefly/pkg/fftypes"
 
@@ -40,6 +42,25 @@ func (_m *Manager) RegisterDispatcher(msgTypes []fftypes.FFEnum, handler batch.D
 	_m.Called(msgTypes, handler, batchOptions)
 }
 
+// RegisterInspector provides a mock function with given fields: i
+func (_m *Manager) RegisterInspector(i batch.Inspector) {
+	_m.Called(i)
+}
+
+// SetDispatcher provides a mock function with given fields: msgTypes, handler, batchOptions
+func (_m *Manager) SetDispatcher(msgTypes []fftypes.FFEnum, handler batch.DispatchHandler, batchOptions batch.Options) error {
+	ret := _m.Called(msgTypes, handler, batchOptions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]fftypes.FFEnum, batch.DispatchHandler, batch.Options) error); ok {
+		r0 = rf(msgTypes, handler, batchOptions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields:
 func (_m *Manager) Start() error {
 	ret := _m.Called()
@@ -54,6 +75,29 @@ func (_m *Manager) Start() error {
 	return r0
 }
 
+// WaitForMessage provides a mock function with given fields: ctx, msgID
+func (_m *Manager) WaitForMessage(ctx context.Context, msgID *fftypes.UUID) (*fftypes.Batch, error) {
+	ret := _m.Called(ctx, msgID)
+
+	var r0 *fftypes.Batch
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) *fftypes.Batch); ok {
+		r0 = rf(ctx, msgID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Batch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, msgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WaitStop provides a mock function with given fields:
 func (_m *Manager) WaitStop() {
 	_m.Called()
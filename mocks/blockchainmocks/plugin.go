@@ -10,6 +10,8 @@ import (
 
 	fftypes "github.com/hyperledger/firefly/pkg/fftypes"
 
+	big "math/big"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -34,6 +36,59 @@ func (_m *Plugin) Capabilities() *blockchain.Capabilities {
 	return r0
 }
 
+// EstimateGas provides a mock function with given fields: ctx, identity, batch
+func (_m *Plugin) EstimateGas(ctx context.Context, identity *fftypes.Identity, batch *blockchain.BatchPin) (*big.Int, *big.Int, error) {
+	ret := _m.Called(ctx, identity, batch)
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.Identity, *blockchain.BatchPin) *big.Int); ok {
+		r0 = rf(ctx, identity, batch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	var r1 *big.Int
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.Identity, *blockchain.BatchPin) *big.Int); ok {
+		r1 = rf(ctx, identity, batch)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*big.Int)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *fftypes.Identity, *blockchain.BatchPin) error); ok {
+		r2 = rf(ctx, identity, batch)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetBlockNumber provides a mock function with given fields: ctx
+func (_m *Plugin) GetBlockNumber(ctx context.Context) (uint64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context) uint64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Init provides a mock function with given fields: ctx, prefix, callbacks
 func (_m *Plugin) Init(ctx context.Context, prefix config.Prefix, callbacks blockchain.Callbacks) error {
 	ret := _m.Called(ctx, prefix, callbacks)
@@ -67,6 +122,27 @@ func (_m *Plugin) Name() string {
 	return r0
 }
 
+// SignPayload provides a mock function with given fields: ctx, identity, keyMaterial, payload
+func (_m *Plugin) SignPayload(ctx context.Context, identity *fftypes.Identity, keyMaterial []byte, payload []byte) (string, error) {
+	ret := _m.Called(ctx, identity, keyMaterial, payload)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.Identity, []byte, []byte) string); ok {
+		r0 = rf(ctx, identity, keyMaterial, payload)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.Identity, []byte, []byte) error); ok {
+		r1 = rf(ctx, identity, keyMaterial, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *Plugin) Start() error {
 	ret := _m.Called()
@@ -4,9 +4,13 @@ package broadcastmocks
 
 import (
 	context "context"
+	jsontext "encoding/json/jsontext"
 
 	fftypes "github.com/hyperledger/firefly/pkg/fftypes"
+
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Manager is an autogenerated mock type for the Manager type
@@ -60,6 +64,59 @@ func (_m *Manager) BroadcastDefinition(ctx context.Context, def fftypes.Definiti
 	return r0, r1
 }
 
+// BroadcastDefinitionIfNotExists provides a mock function with given fields: ctx, def, signingIdentity, tag, waitConfirm, maxRetries, retryDelay
+func (_m *Manager) BroadcastDefinitionIfNotExists(ctx context.Context, def fftypes.Definition, signingIdentity *fftypes.Identity, tag fftypes.SystemTag, waitConfirm bool, maxRetries int, retryDelay time.Duration) (*fftypes.Message, bool, error) {
+	ret := _m.Called(ctx, def, signingIdentity, tag, waitConfirm, maxRetries, retryDelay)
+
+	var r0 *fftypes.Message
+	if rf, ok := ret.Get(0).(func(context.Context, fftypes.Definition, *fftypes.Identity, fftypes.SystemTag, bool, int, time.Duration) *fftypes.Message); ok {
+		r0 = rf(ctx, def, signingIdentity, tag, waitConfirm, maxRetries, retryDelay)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Message)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, fftypes.Definition, *fftypes.Identity, fftypes.SystemTag, bool, int, time.Duration) bool); ok {
+		r1 = rf(ctx, def, signingIdentity, tag, waitConfirm, maxRetries, retryDelay)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, fftypes.Definition, *fftypes.Identity, fftypes.SystemTag, bool, int, time.Duration) error); ok {
+		r2 = rf(ctx, def, signingIdentity, tag, waitConfirm, maxRetries, retryDelay)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// BroadcastDefinitionWithRetry provides a mock function with given fields: ctx, def, signingIdentity, tag, waitConfirm, maxRetries, retryDelay
+func (_m *Manager) BroadcastDefinitionWithRetry(ctx context.Context, def fftypes.Definition, signingIdentity *fftypes.Identity, tag fftypes.SystemTag, waitConfirm bool, maxRetries int, retryDelay time.Duration) (*fftypes.Message, error) {
+	ret := _m.Called(ctx, def, signingIdentity, tag, waitConfirm, maxRetries, retryDelay)
+
+	var r0 *fftypes.Message
+	if rf, ok := ret.Get(0).(func(context.Context, fftypes.Definition, *fftypes.Identity, fftypes.SystemTag, bool, int, time.Duration) *fftypes.Message); ok {
+		r0 = rf(ctx, def, signingIdentity, tag, waitConfirm, maxRetries, retryDelay)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Message)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, fftypes.Definition, *fftypes.Identity, fftypes.SystemTag, bool, int, time.Duration) error); ok {
+		r1 = rf(ctx, def, signingIdentity, tag, waitConfirm, maxRetries, retryDelay)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BroadcastMessage provides a mock function with given fields: ctx, ns, in, waitConfirm
 func (_m *Manager) BroadcastMessage(ctx context.Context, ns string, in *fftypes.MessageInOut, waitConfirm bool) (*fftypes.Message, error) {
 	ret := _m.Called(ctx, ns, in, waitConfirm)
@@ -106,6 +163,66 @@ func (_m *Manager) BroadcastNamespace(ctx context.Context, ns *fftypes.Namespace
 	return r0, r1
 }
 
+// BroadcastRawJSON provides a mock function with given fields: ctx, ns, data, tag
+func (_m *Manager) BroadcastRawJSON(ctx context.Context, ns string, data jsontext.Value, tag string) (*fftypes.Message, error) {
+	ret := _m.Called(ctx, ns, data, tag)
+
+	var r0 *fftypes.Message
+	if rf, ok := ret.Get(0).(func(context.Context, string, jsontext.Value, string) *fftypes.Message); ok {
+		r0 = rf(ctx, ns, data, tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Message)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, jsontext.Value, string) error); ok {
+		r1 = rf(ctx, ns, data, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BroadcastSignedDefinition provides a mock function with given fields: ctx, def, signingIdentity, keyMaterial, tag, waitConfirm
+func (_m *Manager) BroadcastSignedDefinition(ctx context.Context, def fftypes.SignedDefinition, signingIdentity *fftypes.Identity, keyMaterial []byte, tag fftypes.SystemTag, waitConfirm bool) (*fftypes.Message, error) {
+	ret := _m.Called(ctx, def, signingIdentity, keyMaterial, tag, waitConfirm)
+
+	var r0 *fftypes.Message
+	if rf, ok := ret.Get(0).(func(context.Context, fftypes.SignedDefinition, *fftypes.Identity, []byte, fftypes.SystemTag, bool) *fftypes.Message); ok {
+		r0 = rf(ctx, def, signingIdentity, keyMaterial, tag, waitConfirm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Message)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, fftypes.SignedDefinition, *fftypes.Identity, []byte, fftypes.SystemTag, bool) error); ok {
+		r1 = rf(ctx, def, signingIdentity, keyMaterial, tag, waitConfirm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BroadcastStream provides a mock function with given fields: ctx, ns, in, errors
+func (_m *Manager) BroadcastStream(ctx context.Context, ns string, in <-chan *fftypes.MessageInOut, errors chan<- error) error {
+	ret := _m.Called(ctx, ns, in, errors)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, <-chan *fftypes.MessageInOut, chan<- error) error); ok {
+		r0 = rf(ctx, ns, in, errors)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // BroadcastTokenPool provides a mock function with given fields: ctx, ns, pool, waitConfirm
 func (_m *Manager) BroadcastTokenPool(ctx context.Context, ns string, pool *fftypes.TokenPoolAnnouncement, waitConfirm bool) (*fftypes.Message, error) {
 	ret := _m.Called(ctx, ns, pool, waitConfirm)
@@ -129,6 +246,29 @@ func (_m *Manager) BroadcastTokenPool(ctx context.Context, ns string, pool *ffty
 	return r0, r1
 }
 
+// BroadcastWithTimeout provides a mock function with given fields: ctx, ns, in, timeout
+func (_m *Manager) BroadcastWithTimeout(ctx context.Context, ns string, in *fftypes.MessageInOut, timeout time.Duration) (*fftypes.Message, error) {
+	ret := _m.Called(ctx, ns, in, timeout)
+
+	var r0 *fftypes.Message
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.MessageInOut, time.Duration) *fftypes.Message); ok {
+		r0 = rf(ctx, ns, in, timeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Message)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.MessageInOut, time.Duration) error); ok {
+		r1 = rf(ctx, ns, in, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNodeSigningIdentity provides a mock function with given fields: ctx
 func (_m *Manager) GetNodeSigningIdentity(ctx context.Context) (*fftypes.Identity, error) {
 	ret := _m.Called(ctx)
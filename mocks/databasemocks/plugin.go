@@ -35,6 +35,20 @@ func (_m *Plugin) Capabilities() *database.Capabilities {
 	return r0
 }
 
+// DeleteBatch provides a mock function with given fields: ctx, id
+func (_m *Plugin) DeleteBatch(ctx context.Context, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteBlob provides a mock function with given fields: ctx, sequence
 func (_m *Plugin) DeleteBlob(ctx context.Context, sequence int64) error {
 	ret := _m.Called(ctx, sequence)
@@ -63,6 +77,76 @@ func (_m *Plugin) DeleteConfigRecord(ctx context.Context, key string) error {
 	return r0
 }
 
+// DeleteData provides a mock function with given fields: ctx, id
+func (_m *Plugin) DeleteData(ctx context.Context, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteDeadLetterEvent provides a mock function with given fields: ctx, id
+func (_m *Plugin) DeleteDeadLetterEvent(ctx context.Context, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteDeliveryFailure provides a mock function with given fields: ctx, subscription, event
+func (_m *Plugin) DeleteDeliveryFailure(ctx context.Context, subscription *fftypes.UUID, event *fftypes.UUID) error {
+	ret := _m.Called(ctx, subscription, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, subscription, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteEvent provides a mock function with given fields: ctx, id
+func (_m *Plugin) DeleteEvent(ctx context.Context, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteMessage provides a mock function with given fields: ctx, id
+func (_m *Plugin) DeleteMessage(ctx context.Context, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteNamespace provides a mock function with given fields: ctx, id
 func (_m *Plugin) DeleteNamespace(ctx context.Context, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, id)
@@ -119,6 +203,20 @@ func (_m *Plugin) DeleteOffset(ctx context.Context, t fftypes.FFEnum, name strin
 	return r0
 }
 
+// DeleteOperation provides a mock function with given fields: ctx, id
+func (_m *Plugin) DeleteOperation(ctx context.Context, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeletePin provides a mock function with given fields: ctx, sequence
 func (_m *Plugin) DeletePin(ctx context.Context, sequence int64) error {
 	ret := _m.Called(ctx, sequence)
@@ -147,6 +245,20 @@ func (_m *Plugin) DeleteSubscriptionByID(ctx context.Context, id *fftypes.UUID)
 	return r0
 }
 
+// DeleteTransaction provides a mock function with given fields: ctx, id
+func (_m *Plugin) DeleteTransaction(ctx context.Context, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetBatchByID provides a mock function with given fields: ctx, id
 func (_m *Plugin) GetBatchByID(ctx context.Context, id *fftypes.UUID) (*fftypes.Batch, error) {
 	ret := _m.Called(ctx, id)
@@ -399,6 +511,29 @@ func (_m *Plugin) GetDataRefs(ctx context.Context, filter database.Filter) (ffty
 	return r0, r1, r2
 }
 
+// GetDataVersionHistory provides a mock function with given fields: ctx, rootDataID
+func (_m *Plugin) GetDataVersionHistory(ctx context.Context, rootDataID *fftypes.UUID) ([]*fftypes.Data, error) {
+	ret := _m.Called(ctx, rootDataID)
+
+	var r0 []*fftypes.Data
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) []*fftypes.Data); ok {
+		r0 = rf(ctx, rootDataID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.Data)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, rootDataID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDatatypeByID provides a mock function with given fields: ctx, id
 func (_m *Plugin) GetDatatypeByID(ctx context.Context, id *fftypes.UUID) (*fftypes.Datatype, error) {
 	ret := _m.Called(ctx, id)
@@ -477,6 +612,93 @@ func (_m *Plugin) GetDatatypes(ctx context.Context, filter database.Filter) ([]*
 	return r0, r1, r2
 }
 
+// GetDeadLetterEvents provides a mock function with given fields: ctx, filter
+func (_m *Plugin) GetDeadLetterEvents(ctx context.Context, filter database.Filter) ([]*fftypes.DeadLetterEvent, *database.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*fftypes.DeadLetterEvent
+	if rf, ok := ret.Get(0).(func(context.Context, database.Filter) []*fftypes.DeadLetterEvent); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.DeadLetterEvent)
+		}
+	}
+
+	var r1 *database.FilterResult
+	if rf, ok := ret.Get(1).(func(context.Context, database.Filter) *database.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*database.FilterResult)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, database.Filter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetDeliveryFailures provides a mock function with given fields: ctx, filter
+func (_m *Plugin) GetDeliveryFailures(ctx context.Context, filter database.Filter) ([]*fftypes.DeliveryFailure, *database.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*fftypes.DeliveryFailure
+	if rf, ok := ret.Get(0).(func(context.Context, database.Filter) []*fftypes.DeliveryFailure); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.DeliveryFailure)
+		}
+	}
+
+	var r1 *database.FilterResult
+	if rf, ok := ret.Get(1).(func(context.Context, database.Filter) *database.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*database.FilterResult)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, database.Filter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetDuplicateIdentities provides a mock function with given fields: ctx
+func (_m *Plugin) GetDuplicateIdentities(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEventByID provides a mock function with given fields: ctx, id
 func (_m *Plugin) GetEventByID(ctx context.Context, id *fftypes.UUID) (*fftypes.Event, error) {
 	ret := _m.Called(ctx, id)
@@ -532,6 +754,29 @@ func (_m *Plugin) GetEvents(ctx context.Context, filter database.Filter) ([]*fft
 	return r0, r1, r2
 }
 
+// GetGhostNodes provides a mock function with given fields: ctx
+func (_m *Plugin) GetGhostNodes(ctx context.Context) ([]*fftypes.UUID, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*fftypes.UUID
+	if rf, ok := ret.Get(0).(func(context.Context) []*fftypes.UUID); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.UUID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetGroupByHash provides a mock function with given fields: ctx, hash
 func (_m *Plugin) GetGroupByHash(ctx context.Context, hash *fftypes.Bytes32) (*fftypes.Group, error) {
 	ret := _m.Called(ctx, hash)
@@ -706,6 +951,29 @@ func (_m *Plugin) GetMessagesForData(ctx context.Context, dataID *fftypes.UUID,
 	return r0, r1, r2
 }
 
+// GetMissingOrganizations provides a mock function with given fields: ctx
+func (_m *Plugin) GetMissingOrganizations(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNamespace provides a mock function with given fields: ctx, name
 func (_m *Plugin) GetNamespace(ctx context.Context, name string) (*fftypes.Namespace, error) {
 	ret := _m.Called(ctx, name)
@@ -1050,6 +1318,29 @@ func (_m *Plugin) GetOperationByID(ctx context.Context, id *fftypes.UUID) (*ffty
 	return r0, r1
 }
 
+// GetOperationHistory provides a mock function with given fields: ctx, id
+func (_m *Plugin) GetOperationHistory(ctx context.Context, id *fftypes.UUID) ([]*fftypes.OperationTransition, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*fftypes.OperationTransition
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) []*fftypes.OperationTransition); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.OperationTransition)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOperations provides a mock function with given fields: ctx, filter
 func (_m *Plugin) GetOperations(ctx context.Context, filter database.Filter) ([]*fftypes.Operation, *database.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -1348,6 +1639,61 @@ func (_m *Plugin) GetTokenAccounts(ctx context.Context, filter database.Filter)
 	return r0, r1, r2
 }
 
+// GetTokenBalance provides a mock function with given fields: ctx, poolID, address
+func (_m *Plugin) GetTokenBalance(ctx context.Context, poolID *fftypes.UUID, address string) (*fftypes.TokenBalance, error) {
+	ret := _m.Called(ctx, poolID, address)
+
+	var r0 *fftypes.TokenBalance
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, string) *fftypes.TokenBalance); ok {
+		r0 = rf(ctx, poolID, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.TokenBalance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID, string) error); ok {
+		r1 = rf(ctx, poolID, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenBalances provides a mock function with given fields: ctx, filter
+func (_m *Plugin) GetTokenBalances(ctx context.Context, filter database.Filter) ([]*fftypes.TokenBalance, *database.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*fftypes.TokenBalance
+	if rf, ok := ret.Get(0).(func(context.Context, database.Filter) []*fftypes.TokenBalance); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.TokenBalance)
+		}
+	}
+
+	var r1 *database.FilterResult
+	if rf, ok := ret.Get(1).(func(context.Context, database.Filter) *database.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*database.FilterResult)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, database.Filter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetTokenPool provides a mock function with given fields: ctx, ns, name
 func (_m *Plugin) GetTokenPool(ctx context.Context, ns string, name string) (*fftypes.TokenPool, error) {
 	ret := _m.Called(ctx, ns, name)
@@ -1417,6 +1763,29 @@ func (_m *Plugin) GetTokenPoolByProtocolID(ctx context.Context, id string) (*fft
 	return r0, r1
 }
 
+// GetTokenPoolBySymbol provides a mock function with given fields: ctx, ns, symbol
+func (_m *Plugin) GetTokenPoolBySymbol(ctx context.Context, ns string, symbol string) (*fftypes.TokenPool, error) {
+	ret := _m.Called(ctx, ns, symbol)
+
+	var r0 *fftypes.TokenPool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *fftypes.TokenPool); ok {
+		r0 = rf(ctx, ns, symbol)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.TokenPool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, ns, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTokenPools provides a mock function with given fields: ctx, filter
 func (_m *Plugin) GetTokenPools(ctx context.Context, filter database.Filter) ([]*fftypes.TokenPool, *database.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -1537,6 +1906,20 @@ func (_m *Plugin) InsertBlob(ctx context.Context, blob *fftypes.Blob) error {
 	return r0
 }
 
+// InsertDeadLetterEvent provides a mock function with given fields: ctx, dle
+func (_m *Plugin) InsertDeadLetterEvent(ctx context.Context, dle *fftypes.DeadLetterEvent) error {
+	ret := _m.Called(ctx, dle)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.DeadLetterEvent) error); ok {
+		r0 = rf(ctx, dle)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertEvent provides a mock function with given fields: ctx, data
 func (_m *Plugin) InsertEvent(ctx context.Context, data *fftypes.Event) error {
 	ret := _m.Called(ctx, data)
@@ -1593,6 +1976,20 @@ func (_m *Plugin) Name() string {
 	return r0
 }
 
+// PurgeOperations provides a mock function with given fields: ctx, deletedBefore
+func (_m *Plugin) PurgeOperations(ctx context.Context, deletedBefore *fftypes.FFTime) error {
+	ret := _m.Called(ctx, deletedBefore)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFTime) error); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RunAsGroup provides a mock function with given fields: ctx, fn
 func (_m *Plugin) RunAsGroup(ctx context.Context, fn func(context.Context) error) error {
 	ret := _m.Called(ctx, fn)
@@ -1621,6 +2018,20 @@ func (_m *Plugin) SetPinDispatched(ctx context.Context, sequence int64) error {
 	return r0
 }
 
+// SoftDeleteOperation provides a mock function with given fields: ctx, id
+func (_m *Plugin) SoftDeleteOperation(ctx context.Context, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateBatch provides a mock function with given fields: ctx, id, update
 func (_m *Plugin) UpdateBatch(ctx context.Context, id *fftypes.UUID, update database.Update) error {
 	ret := _m.Called(ctx, id, update)
@@ -1873,6 +2284,20 @@ func (_m *Plugin) UpsertDatatype(ctx context.Context, datadef *fftypes.Datatype,
 	return r0
 }
 
+// UpsertDeliveryFailure provides a mock function with given fields: ctx, df
+func (_m *Plugin) UpsertDeliveryFailure(ctx context.Context, df *fftypes.DeliveryFailure) error {
+	ret := _m.Called(ctx, df)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.DeliveryFailure) error); ok {
+		r0 = rf(ctx, df)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpsertGroup provides a mock function with given fields: ctx, data, allowExisting
 func (_m *Plugin) UpsertGroup(ctx context.Context, data *fftypes.Group, allowExisting bool) error {
 	ret := _m.Called(ctx, data, allowExisting)
@@ -2027,6 +2452,20 @@ func (_m *Plugin) UpsertTokenAccount(ctx context.Context, account *fftypes.Token
 	return r0
 }
 
+// UpsertTokenBalance provides a mock function with given fields: ctx, balance
+func (_m *Plugin) UpsertTokenBalance(ctx context.Context, balance *fftypes.TokenBalance) error {
+	ret := _m.Called(ctx, balance)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.TokenBalance) error); ok {
+		r0 = rf(ctx, balance)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpsertTokenPool provides a mock function with given fields: ctx, pool
 func (_m *Plugin) UpsertTokenPool(ctx context.Context, pool *fftypes.TokenPool) error {
 	ret := _m.Called(ctx, pool)
@@ -74,6 +74,20 @@ func (_m *Plugin) CheckBLOBReceived(ctx context.Context, peerID string, ns strin
 	return r0, r1
 }
 
+// DeleteBLOB provides a mock function with given fields: ctx, payloadRef
+func (_m *Plugin) DeleteBLOB(ctx context.Context, payloadRef string) error {
+	ret := _m.Called(ctx, payloadRef)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, payloadRef)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DownloadBLOB provides a mock function with given fields: ctx, payloadRef
 func (_m *Plugin) DownloadBLOB(ctx context.Context, payloadRef string) (io.ReadCloser, error) {
 	ret := _m.Called(ctx, payloadRef)
@@ -127,6 +141,29 @@ func (_m *Plugin) GetEndpointInfo(ctx context.Context) (string, fftypes.JSONObje
 	return r0, r1, r2
 }
 
+// GetMessageStatus provides a mock function with given fields: ctx, trackingID
+func (_m *Plugin) GetMessageStatus(ctx context.Context, trackingID string) (*fftypes.MessageDeliveryStatus, error) {
+	ret := _m.Called(ctx, trackingID)
+
+	var r0 *fftypes.MessageDeliveryStatus
+	if rf, ok := ret.Get(0).(func(context.Context, string) *fftypes.MessageDeliveryStatus); ok {
+		r0 = rf(ctx, trackingID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.MessageDeliveryStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, trackingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Init provides a mock function with given fields: ctx, prefix, callbacks
 func (_m *Plugin) Init(ctx context.Context, prefix config.Prefix, callbacks dataexchange.Callbacks) error {
 	ret := _m.Called(ctx, prefix, callbacks)
@@ -107,13 +107,13 @@ func (_m *Manager) GetMessageData(ctx context.Context, msg *fftypes.Message, wit
 	return r0, r1, r2
 }
 
-// ResolveInlineDataBroadcast provides a mock function with given fields: ctx, ns, inData
-func (_m *Manager) ResolveInlineDataBroadcast(ctx context.Context, ns string, inData fftypes.InlineData) (fftypes.DataRefs, []*fftypes.DataAndBlob, error) {
-	ret := _m.Called(ctx, ns, inData)
+// ResolveInlineDataBroadcast provides a mock function with given fields: ctx, ns, msgID, inData
+func (_m *Manager) ResolveInlineDataBroadcast(ctx context.Context, ns string, msgID *fftypes.UUID, inData fftypes.InlineData) (fftypes.DataRefs, []*fftypes.DataAndBlob, error) {
+	ret := _m.Called(ctx, ns, msgID, inData)
 
 	var r0 fftypes.DataRefs
-	if rf, ok := ret.Get(0).(func(context.Context, string, fftypes.InlineData) fftypes.DataRefs); ok {
-		r0 = rf(ctx, ns, inData)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, fftypes.InlineData) fftypes.DataRefs); ok {
+		r0 = rf(ctx, ns, msgID, inData)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(fftypes.DataRefs)
@@ -121,8 +121,8 @@ func (_m *Manager) ResolveInlineDataBroadcast(ctx context.Context, ns string, in
 	}
 
 	var r1 []*fftypes.DataAndBlob
-	if rf, ok := ret.Get(1).(func(context.Context, string, fftypes.InlineData) []*fftypes.DataAndBlob); ok {
-		r1 = rf(ctx, ns, inData)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, fftypes.InlineData) []*fftypes.DataAndBlob); ok {
+		r1 = rf(ctx, ns, msgID, inData)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([]*fftypes.DataAndBlob)
@@ -130,8 +130,8 @@ func (_m *Manager) ResolveInlineDataBroadcast(ctx context.Context, ns string, in
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, fftypes.InlineData) error); ok {
-		r2 = rf(ctx, ns, inData)
+	if rf, ok := ret.Get(2).(func(context.Context, string, *fftypes.UUID, fftypes.InlineData) error); ok {
+		r2 = rf(ctx, ns, msgID, inData)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -208,6 +208,20 @@ func (_m *Manager) UploadJSON(ctx context.Context, ns string, inData *fftypes.Da
 	return r0, r1
 }
 
+// ValidateAgainstDatatype provides a mock function with given fields: ctx, ns, datatypeRef, _a3
+func (_m *Manager) ValidateAgainstDatatype(ctx context.Context, ns string, datatypeRef *fftypes.DatatypeRef, _a3 []*fftypes.Data) error {
+	ret := _m.Called(ctx, ns, datatypeRef, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.DatatypeRef, []*fftypes.Data) error); ok {
+		r0 = rf(ctx, ns, datatypeRef, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ValidateAll provides a mock function with given fields: ctx, _a1
 func (_m *Manager) ValidateAll(ctx context.Context, _a1 []*fftypes.Data) (bool, error) {
 	ret := _m.Called(ctx, _a1)
@@ -199,6 +199,62 @@ func (_m *EventManager) OperationUpdate(plugin fftypes.Named, operationID *fftyp
 	return r0
 }
 
+// PauseSubscription provides a mock function with given fields: ctx, ns, subID
+func (_m *EventManager) PauseSubscription(ctx context.Context, ns string, subID string) error {
+	ret := _m.Called(ctx, ns, subID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, ns, subID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RedeliverDeadLetterEvent provides a mock function with given fields: ctx, ns, subID, eventID
+func (_m *EventManager) RedeliverDeadLetterEvent(ctx context.Context, ns string, subID string, eventID string) error {
+	ret := _m.Called(ctx, ns, subID, eventID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ns, subID, eventID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResumeSubscription provides a mock function with given fields: ctx, ns, subID
+func (_m *EventManager) ResumeSubscription(ctx context.Context, ns string, subID string) error {
+	ret := _m.Called(ctx, ns, subID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, ns, subID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RewindSubscription provides a mock function with given fields: ctx, ns, subID, fromSequence
+func (_m *EventManager) RewindSubscription(ctx context.Context, ns string, subID string, fromSequence int64) error {
+	ret := _m.Called(ctx, ns, subID, fromSequence)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) error); ok {
+		r0 = rf(ctx, ns, subID, fromSequence)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields:
 func (_m *EventManager) Start() error {
 	ret := _m.Called()
@@ -2,9 +2,37 @@
 
 package identitymocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	fftypes "github.com/hyperledger/firefly/pkg/fftypes"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Callbacks is an autogenerated mock type for the Callbacks type
 type Callbacks struct {
 	mock.Mock
 }
+
+// GetIdentityMetadata provides a mock function with given fields: ctx, identifier
+func (_m *Callbacks) GetIdentityMetadata(ctx context.Context, identifier string) (fftypes.JSONObject, error) {
+	ret := _m.Called(ctx, identifier)
+
+	var r0 fftypes.JSONObject
+	if rf, ok := ret.Get(0).(func(context.Context, string) fftypes.JSONObject); ok {
+		r0 = rf(ctx, identifier)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(fftypes.JSONObject)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, identifier)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -94,6 +94,75 @@ func (_m *Manager) GetOrganizationByID(ctx context.Context, id string) (*fftypes
 	return r0, r1
 }
 
+// GetOrganizationByIdentity provides a mock function with given fields: ctx, identity
+func (_m *Manager) GetOrganizationByIdentity(ctx context.Context, identity string) (*fftypes.Organization, error) {
+	ret := _m.Called(ctx, identity)
+
+	var r0 *fftypes.Organization
+	if rf, ok := ret.Get(0).(func(context.Context, string) *fftypes.Organization); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Organization)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrganizationByName provides a mock function with given fields: ctx, name
+func (_m *Manager) GetOrganizationByName(ctx context.Context, name string) (*fftypes.Organization, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *fftypes.Organization
+	if rf, ok := ret.Get(0).(func(context.Context, string) *fftypes.Organization); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Organization)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrganizationDID provides a mock function with given fields: ctx, identity
+func (_m *Manager) GetOrganizationDID(ctx context.Context, identity string) (*fftypes.DIDDocument, error) {
+	ret := _m.Called(ctx, identity)
+
+	var r0 *fftypes.DIDDocument
+	if rf, ok := ret.Get(0).(func(context.Context, string) *fftypes.DIDDocument); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.DIDDocument)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOrganizations provides a mock function with given fields: ctx, filter
 func (_m *Manager) GetOrganizations(ctx context.Context, filter database.AndFilter) ([]*fftypes.Organization, *database.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -212,3 +281,81 @@ func (_m *Manager) RegisterOrganization(ctx context.Context, org *fftypes.Organi
 
 	return r0, r1
 }
+
+// RegisterOrganizationWithKey provides a mock function with given fields: ctx, org, keyMaterial, waitConfirm
+func (_m *Manager) RegisterOrganizationWithKey(ctx context.Context, org *fftypes.Organization, keyMaterial []byte, waitConfirm bool) (*fftypes.Message, error) {
+	ret := _m.Called(ctx, org, keyMaterial, waitConfirm)
+
+	var r0 *fftypes.Message
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.Organization, []byte, bool) *fftypes.Message); ok {
+		r0 = rf(ctx, org, keyMaterial, waitConfirm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Message)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.Organization, []byte, bool) error); ok {
+		r1 = rf(ctx, org, keyMaterial, waitConfirm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateNodeKey provides a mock function with given fields: ctx, waitConfirm
+func (_m *Manager) RotateNodeKey(ctx context.Context, waitConfirm bool) (*fftypes.Node, *fftypes.Message, error) {
+	ret := _m.Called(ctx, waitConfirm)
+
+	var r0 *fftypes.Node
+	if rf, ok := ret.Get(0).(func(context.Context, bool) *fftypes.Node); ok {
+		r0 = rf(ctx, waitConfirm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Node)
+		}
+	}
+
+	var r1 *fftypes.Message
+	if rf, ok := ret.Get(1).(func(context.Context, bool) *fftypes.Message); ok {
+		r1 = rf(ctx, waitConfirm)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*fftypes.Message)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, bool) error); ok {
+		r2 = rf(ctx, waitConfirm)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ValidateNetworkConsistency provides a mock function with given fields: ctx
+func (_m *Manager) ValidateNetworkConsistency(ctx context.Context) (*fftypes.ConsistencyReport, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *fftypes.ConsistencyReport
+	if rf, ok := ret.Get(0).(func(context.Context) *fftypes.ConsistencyReport); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.ConsistencyReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
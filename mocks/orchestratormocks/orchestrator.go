@@ -16,11 +16,15 @@ import (
 
 	fftypes "github.com/hyperledger/firefly/pkg/fftypes"
 
+	identity "github.com/hyperledger/firefly/pkg/identity"
+
 	mock "github.com/stretchr/testify/mock"
 
 	networkmap "github.com/hyperledger/firefly/internal/networkmap"
 
 	privatemessaging "github.com/hyperledger/firefly/internal/privatemessaging"
+
+	publicstorage "github.com/hyperledger/firefly/pkg/publicstorage"
 )
 
 // Orchestrator is an autogenerated mock type for the Orchestrator type
@@ -136,6 +140,41 @@ func (_m *Orchestrator) DeleteConfigRecord(ctx context.Context, key string) erro
 	return r0
 }
 
+// DeleteMessages provides a mock function with given fields: ctx, ns, filter
+func (_m *Orchestrator) DeleteMessages(ctx context.Context, ns string, filter database.AndFilter) (int64, error) {
+	ret := _m.Called(ctx, ns, filter)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string, database.AndFilter) int64); ok {
+		r0 = rf(ctx, ns, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, database.AndFilter) error); ok {
+		r1 = rf(ctx, ns, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteOperation provides a mock function with given fields: ctx, ns, id
+func (_m *Orchestrator) DeleteOperation(ctx context.Context, ns string, id string) error {
+	ret := _m.Called(ctx, ns, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, ns, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteSubscription provides a mock function with given fields: ctx, ns, id
 func (_m *Orchestrator) DeleteSubscription(ctx context.Context, ns string, id string) error {
 	ret := _m.Called(ctx, ns, id)
@@ -166,6 +205,29 @@ func (_m *Orchestrator) Events() events.EventManager {
 	return r0
 }
 
+// GCPublicStorage provides a mock function with given fields: ctx, ns, dryRun
+func (_m *Orchestrator) GCPublicStorage(ctx context.Context, ns string, dryRun bool) (*fftypes.GCResult, error) {
+	ret := _m.Called(ctx, ns, dryRun)
+
+	var r0 *fftypes.GCResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *fftypes.GCResult); ok {
+		r0 = rf(ctx, ns, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.GCResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, ns, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBatchByID provides a mock function with given fields: ctx, ns, id
 func (_m *Orchestrator) GetBatchByID(ctx context.Context, ns string, id string) (*fftypes.Batch, error) {
 	ret := _m.Called(ctx, ns, id)
@@ -221,6 +283,29 @@ func (_m *Orchestrator) GetBatches(ctx context.Context, ns string, filter databa
 	return r0, r1, r2
 }
 
+// GetBlockchainStatus provides a mock function with given fields: ctx
+func (_m *Orchestrator) GetBlockchainStatus(ctx context.Context) (*fftypes.BlockchainStatus, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *fftypes.BlockchainStatus
+	if rf, ok := ret.Get(0).(func(context.Context) *fftypes.BlockchainStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.BlockchainStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetConfig provides a mock function with given fields: ctx
 func (_m *Orchestrator) GetConfig(ctx context.Context) fftypes.JSONObject {
 	ret := _m.Called(ctx)
@@ -347,6 +432,29 @@ func (_m *Orchestrator) GetDataByID(ctx context.Context, ns string, id string) (
 	return r0, r1
 }
 
+// GetDataVersionHistory provides a mock function with given fields: ctx, ns, id
+func (_m *Orchestrator) GetDataVersionHistory(ctx context.Context, ns string, id string) ([]*fftypes.Data, error) {
+	ret := _m.Called(ctx, ns, id)
+
+	var r0 []*fftypes.Data
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*fftypes.Data); ok {
+		r0 = rf(ctx, ns, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.Data)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, ns, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDatatypeByID provides a mock function with given fields: ctx, ns, id
 func (_m *Orchestrator) GetDatatypeByID(ctx context.Context, ns string, id string) (*fftypes.Datatype, error) {
 	ret := _m.Called(ctx, ns, id)
@@ -480,6 +588,38 @@ func (_m *Orchestrator) GetEvents(ctx context.Context, ns string, filter databas
 	return r0, r1, r2
 }
 
+// GetGroups provides a mock function with given fields: ctx, ns, filter
+func (_m *Orchestrator) GetGroups(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Group, *database.FilterResult, error) {
+	ret := _m.Called(ctx, ns, filter)
+
+	var r0 []*fftypes.Group
+	if rf, ok := ret.Get(0).(func(context.Context, string, database.AndFilter) []*fftypes.Group); ok {
+		r0 = rf(ctx, ns, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.Group)
+		}
+	}
+
+	var r1 *database.FilterResult
+	if rf, ok := ret.Get(1).(func(context.Context, string, database.AndFilter) *database.FilterResult); ok {
+		r1 = rf(ctx, ns, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*database.FilterResult)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, database.AndFilter) error); ok {
+		r2 = rf(ctx, ns, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetMessageByID provides a mock function with given fields: ctx, ns, id, withValues
 func (_m *Orchestrator) GetMessageByID(ctx context.Context, ns string, id string, withValues bool) (*fftypes.MessageInOut, error) {
 	ret := _m.Called(ctx, ns, id, withValues)
@@ -645,6 +785,29 @@ func (_m *Orchestrator) GetMessages(ctx context.Context, ns string, filter datab
 	return r0, r1, r2
 }
 
+// GetMessagesByIDs provides a mock function with given fields: ctx, ns, ids
+func (_m *Orchestrator) GetMessagesByIDs(ctx context.Context, ns string, ids []*fftypes.UUID) ([]*fftypes.Message, error) {
+	ret := _m.Called(ctx, ns, ids)
+
+	var r0 []*fftypes.Message
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) []*fftypes.Message); ok {
+		r0 = rf(ctx, ns, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.Message)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r1 = rf(ctx, ns, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMessagesForData provides a mock function with given fields: ctx, ns, dataID, filter
 func (_m *Orchestrator) GetMessagesForData(ctx context.Context, ns string, dataID string, filter database.AndFilter) ([]*fftypes.Message, *database.FilterResult, error) {
 	ret := _m.Called(ctx, ns, dataID, filter)
@@ -755,6 +918,52 @@ func (_m *Orchestrator) GetOperationByID(ctx context.Context, ns string, id stri
 	return r0, r1
 }
 
+// GetOperationDeliveryStatus provides a mock function with given fields: ctx, ns, id
+func (_m *Orchestrator) GetOperationDeliveryStatus(ctx context.Context, ns string, id string) (*fftypes.MessageDeliveryStatus, error) {
+	ret := _m.Called(ctx, ns, id)
+
+	var r0 *fftypes.MessageDeliveryStatus
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *fftypes.MessageDeliveryStatus); ok {
+		r0 = rf(ctx, ns, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.MessageDeliveryStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, ns, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOperationHistory provides a mock function with given fields: ctx, ns, id
+func (_m *Orchestrator) GetOperationHistory(ctx context.Context, ns string, id string) ([]*fftypes.OperationTransition, error) {
+	ret := _m.Called(ctx, ns, id)
+
+	var r0 []*fftypes.OperationTransition
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*fftypes.OperationTransition); ok {
+		r0 = rf(ctx, ns, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.OperationTransition)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, ns, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOperations provides a mock function with given fields: ctx, ns, filter
 func (_m *Orchestrator) GetOperations(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Operation, *database.FilterResult, error) {
 	ret := _m.Called(ctx, ns, filter)
@@ -787,6 +996,29 @@ func (_m *Orchestrator) GetOperations(ctx context.Context, ns string, filter dat
 	return r0, r1, r2
 }
 
+// GetPublicStorageItems provides a mock function with given fields: ctx, ns, since
+func (_m *Orchestrator) GetPublicStorageItems(ctx context.Context, ns string, since *fftypes.FFTime) ([]publicstorage.StoredItem, error) {
+	ret := _m.Called(ctx, ns, since)
+
+	var r0 []publicstorage.StoredItem
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.FFTime) []publicstorage.StoredItem); ok {
+		r0 = rf(ctx, ns, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]publicstorage.StoredItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.FFTime) error); ok {
+		r1 = rf(ctx, ns, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStatus provides a mock function with given fields: ctx
 func (_m *Orchestrator) GetStatus(ctx context.Context) (*fftypes.NodeStatus, error) {
 	ret := _m.Called(ctx)
@@ -833,6 +1065,38 @@ func (_m *Orchestrator) GetSubscriptionByID(ctx context.Context, ns string, id s
 	return r0, r1
 }
 
+// GetSubscriptionDeadLetterEvents provides a mock function with given fields: ctx, ns, id, filter
+func (_m *Orchestrator) GetSubscriptionDeadLetterEvents(ctx context.Context, ns string, id string, filter database.AndFilter) ([]*fftypes.DeadLetterEvent, *database.FilterResult, error) {
+	ret := _m.Called(ctx, ns, id, filter)
+
+	var r0 []*fftypes.DeadLetterEvent
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, database.AndFilter) []*fftypes.DeadLetterEvent); ok {
+		r0 = rf(ctx, ns, id, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.DeadLetterEvent)
+		}
+	}
+
+	var r1 *database.FilterResult
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, database.AndFilter) *database.FilterResult); ok {
+		r1 = rf(ctx, ns, id, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*database.FilterResult)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, database.AndFilter) error); ok {
+		r2 = rf(ctx, ns, id, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetSubscriptions provides a mock function with given fields: ctx, ns, filter
 func (_m *Orchestrator) GetSubscriptions(ctx context.Context, ns string, filter database.AndFilter) ([]*fftypes.Subscription, *database.FilterResult, error) {
 	ret := _m.Called(ctx, ns, filter)
@@ -952,6 +1216,22 @@ func (_m *Orchestrator) GetTransactions(ctx context.Context, ns string, filter d
 	return r0, r1, r2
 }
 
+// Identity provides a mock function with given fields:
+func (_m *Orchestrator) Identity() identity.Plugin {
+	ret := _m.Called()
+
+	var r0 identity.Plugin
+	if rf, ok := ret.Get(0).(func() identity.Plugin); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(identity.Plugin)
+		}
+	}
+
+	return r0
+}
+
 // Init provides a mock function with given fields: ctx, cancelCtx
 func (_m *Orchestrator) Init(ctx context.Context, cancelCtx context.CancelFunc) error {
 	ret := _m.Called(ctx, cancelCtx)
@@ -980,6 +1260,20 @@ func (_m *Orchestrator) IsPreInit() bool {
 	return r0
 }
 
+// MarkRead provides a mock function with given fields: ctx, ns, msgID
+func (_m *Orchestrator) MarkRead(ctx context.Context, ns string, msgID string) error {
+	ret := _m.Called(ctx, ns, msgID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, ns, msgID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NetworkMap provides a mock function with given fields:
 func (_m *Orchestrator) NetworkMap() networkmap.Manager {
 	ret := _m.Called()
@@ -996,6 +1290,20 @@ func (_m *Orchestrator) NetworkMap() networkmap.Manager {
 	return r0
 }
 
+// PauseSubscription provides a mock function with given fields: ctx, ns, id
+func (_m *Orchestrator) PauseSubscription(ctx context.Context, ns string, id string) error {
+	ret := _m.Called(ctx, ns, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, ns, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PrivateMessaging provides a mock function with given fields:
 func (_m *Orchestrator) PrivateMessaging() privatemessaging.Manager {
 	ret := _m.Called()
@@ -1012,6 +1320,43 @@ func (_m *Orchestrator) PrivateMessaging() privatemessaging.Manager {
 	return r0
 }
 
+// PurgeExpiredData provides a mock function with given fields: ctx, ns, dryRun
+func (_m *Orchestrator) PurgeExpiredData(ctx context.Context, ns string, dryRun bool) (*fftypes.PurgeResult, error) {
+	ret := _m.Called(ctx, ns, dryRun)
+
+	var r0 *fftypes.PurgeResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *fftypes.PurgeResult); ok {
+		r0 = rf(ctx, ns, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.PurgeResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, ns, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeOperations provides a mock function with given fields: ctx, olderThan
+func (_m *Orchestrator) PurgeOperations(ctx context.Context, olderThan string) error {
+	ret := _m.Called(ctx, olderThan)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PutConfigRecord provides a mock function with given fields: ctx, key, configRecord
 func (_m *Orchestrator) PutConfigRecord(ctx context.Context, key string, configRecord fftypes.Byteable) (fftypes.Byteable, error) {
 	ret := _m.Called(ctx, key, configRecord)
@@ -1035,6 +1380,20 @@ func (_m *Orchestrator) PutConfigRecord(ctx context.Context, key string, configR
 	return r0, r1
 }
 
+// RedeliverDeadLetterEvent provides a mock function with given fields: ctx, ns, id, eventID
+func (_m *Orchestrator) RedeliverDeadLetterEvent(ctx context.Context, ns string, id string, eventID string) error {
+	ret := _m.Called(ctx, ns, id, eventID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ns, id, eventID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RequestReply provides a mock function with given fields: ctx, ns, msg
 func (_m *Orchestrator) RequestReply(ctx context.Context, ns string, msg *fftypes.MessageInOut) (*fftypes.MessageInOut, error) {
 	ret := _m.Called(ctx, ns, msg)
@@ -1063,6 +1422,34 @@ func (_m *Orchestrator) ResetConfig(ctx context.Context) {
 	_m.Called(ctx)
 }
 
+// ResumeSubscription provides a mock function with given fields: ctx, ns, id
+func (_m *Orchestrator) ResumeSubscription(ctx context.Context, ns string, id string) error {
+	ret := _m.Called(ctx, ns, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, ns, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RewindSubscription provides a mock function with given fields: ctx, ns, id, fromSequence
+func (_m *Orchestrator) RewindSubscription(ctx context.Context, ns string, id string, fromSequence int64) error {
+	ret := _m.Called(ctx, ns, id, fromSequence)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) error); ok {
+		r0 = rf(ctx, ns, id, fromSequence)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields:
 func (_m *Orchestrator) Start() error {
 	ret := _m.Called()
@@ -1077,6 +1464,29 @@ func (_m *Orchestrator) Start() error {
 	return r0
 }
 
+// UpdateSubscription provides a mock function with given fields: ctx, ns, id, update
+func (_m *Orchestrator) UpdateSubscription(ctx context.Context, ns string, id string, update *fftypes.SubscriptionUpdate) (*fftypes.Subscription, error) {
+	ret := _m.Called(ctx, ns, id, update)
+
+	var r0 *fftypes.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *fftypes.SubscriptionUpdate) *fftypes.Subscription); ok {
+		r0 = rf(ctx, ns, id, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *fftypes.SubscriptionUpdate) error); ok {
+		r1 = rf(ctx, ns, id, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WaitStop provides a mock function with given fields:
 func (_m *Orchestrator) WaitStop() {
 	_m.Called()
@@ -37,6 +37,34 @@ func (_m *Manager) EnsureLocalGroup(ctx context.Context, group *fftypes.Group) (
 	return r0, r1
 }
 
+// FlushGroupCache provides a mock function with given fields:
+func (_m *Manager) FlushGroupCache() {
+	_m.Called()
+}
+
+// GetDeliveryStatus provides a mock function with given fields: ctx, ns, msgID
+func (_m *Manager) GetDeliveryStatus(ctx context.Context, ns string, msgID string) ([]*fftypes.DeliveryStatus, error) {
+	ret := _m.Called(ctx, ns, msgID)
+
+	var r0 []*fftypes.DeliveryStatus
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*fftypes.DeliveryStatus); ok {
+		r0 = rf(ctx, ns, msgID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.DeliveryStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, ns, msgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetGroupByID provides a mock function with given fields: ctx, id
 func (_m *Manager) GetGroupByID(ctx context.Context, id string) (*fftypes.Group, error) {
 	ret := _m.Called(ctx, id)
@@ -92,6 +120,59 @@ func (_m *Manager) GetGroups(ctx context.Context, filter database.AndFilter) ([]
 	return r0, r1, r2
 }
 
+// GetNodeHeartbeat provides a mock function with given fields: ctx, ns, id
+func (_m *Manager) GetNodeHeartbeat(ctx context.Context, ns string, id string) (*fftypes.NodeHeartbeat, error) {
+	ret := _m.Called(ctx, ns, id)
+
+	var r0 *fftypes.NodeHeartbeat
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *fftypes.NodeHeartbeat); ok {
+		r0 = rf(ctx, ns, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.NodeHeartbeat)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, ns, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GroupCacheStats provides a mock function with given fields:
+func (_m *Manager) GroupCacheStats() *fftypes.CacheStats {
+	ret := _m.Called()
+
+	var r0 *fftypes.CacheStats
+	if rf, ok := ret.Get(0).(func() *fftypes.CacheStats); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.CacheStats)
+		}
+	}
+
+	return r0
+}
+
+// HeartbeatReceived provides a mock function with given fields: ctx, node, heartbeat
+func (_m *Manager) HeartbeatReceived(ctx context.Context, node *fftypes.Node, heartbeat *fftypes.Message) error {
+	ret := _m.Called(ctx, node, heartbeat)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.Node, *fftypes.Message) error); ok {
+		r0 = rf(ctx, node, heartbeat)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RequestReply provides a mock function with given fields: ctx, ns, request
 func (_m *Manager) RequestReply(ctx context.Context, ns string, request *fftypes.MessageInOut) (*fftypes.MessageInOut, error) {
 	ret := _m.Called(ctx, ns, request)
@@ -116,7 +197,7 @@ func (_m *Manager) RequestReply(ctx context.Context, ns string, request *fftypes
 }
 
 // ResolveInitGroup provides a mock function with given fields: ctx, msg
-func (_m *Manager) ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (*fftypes.Group, error) {
+func (_m *Manager) ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (*fftypes.Group, bool, error) {
 	ret := _m.Called(ctx, msg)
 
 	var r0 *fftypes.Group
@@ -128,14 +209,21 @@ func (_m *Manager) ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.Message) error); ok {
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.Message) bool); ok {
 		r1 = rf(ctx, msg)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(bool)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *fftypes.Message) error); ok {
+		r2 = rf(ctx, msg)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // SendMessage provides a mock function with given fields: ctx, ns, in, waitConfirm
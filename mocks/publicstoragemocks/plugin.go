@@ -7,6 +7,8 @@ import (
 
 	config "github.com/hyperledger/firefly/internal/config"
 
+	fftypes "github.com/hyperledger/firefly/pkg/fftypes"
+
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
@@ -35,6 +37,20 @@ func (_m *Plugin) Capabilities() *publicstorage.Capabilities {
 	return r0
 }
 
+// DeleteData provides a mock function with given fields: ctx, payloadRef
+func (_m *Plugin) DeleteData(ctx context.Context, payloadRef string) error {
+	ret := _m.Called(ctx, payloadRef)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, payloadRef)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Init provides a mock function with given fields: ctx, prefix, callbacks
 func (_m *Plugin) Init(ctx context.Context, prefix config.Prefix, callbacks publicstorage.Callbacks) error {
 	ret := _m.Called(ctx, prefix, callbacks)
@@ -54,6 +70,29 @@ func (_m *Plugin) InitPrefix(prefix config.Prefix) {
 	_m.Called(prefix)
 }
 
+// ListStoredItems provides a mock function with given fields: ctx, since
+func (_m *Plugin) ListStoredItems(ctx context.Context, since *fftypes.FFTime) ([]publicstorage.StoredItem, error) {
+	ret := _m.Called(ctx, since)
+
+	var r0 []publicstorage.StoredItem
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFTime) []publicstorage.StoredItem); ok {
+		r0 = rf(ctx, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]publicstorage.StoredItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.FFTime) error); ok {
+		r1 = rf(ctx, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Name provides a mock function with given fields:
 func (_m *Plugin) Name() string {
 	ret := _m.Called()
@@ -113,8 +113,29 @@ func (_m *SystemHandlers) HandleSystemBroadcast(ctx context.Context, msg *fftype
 	return r0, r1
 }
 
+// HandleTokenPoolDefinition provides a mock function with given fields: ctx, msg, data
+func (_m *SystemHandlers) HandleTokenPoolDefinition(ctx context.Context, msg *fftypes.Message, data []*fftypes.Data) (bool, error) {
+	ret := _m.Called(ctx, msg, data)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.Message, []*fftypes.Data) bool); ok {
+		r0 = rf(ctx, msg, data)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.Message, []*fftypes.Data) error); ok {
+		r1 = rf(ctx, msg, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResolveInitGroup provides a mock function with given fields: ctx, msg
-func (_m *SystemHandlers) ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (*fftypes.Group, error) {
+func (_m *SystemHandlers) ResolveInitGroup(ctx context.Context, msg *fftypes.Message) (*fftypes.Group, bool, error) {
 	ret := _m.Called(ctx, msg)
 
 	var r0 *fftypes.Group
@@ -126,14 +147,21 @@ func (_m *SystemHandlers) ResolveInitGroup(ctx context.Context, msg *fftypes.Mes
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.Message) error); ok {
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.Message) bool); ok {
 		r1 = rf(ctx, msg)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(bool)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *fftypes.Message) error); ok {
+		r2 = rf(ctx, msg)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // SendReply provides a mock function with given fields: ctx, event, reply
@@ -41,3 +41,31 @@ func (_m *Callbacks) TokensOpUpdate(plugin tokens.Plugin, operationID *fftypes.U
 
 	return r0
 }
+
+// TokenPoolStatusChanged provides a mock function with given fields: plugin, poolProtocolID, status, protocolTxID, additionalInfo
+func (_m *Callbacks) TokenPoolStatusChanged(plugin tokens.Plugin, poolProtocolID string, status fftypes.FFEnum, protocolTxID string, additionalInfo fftypes.JSONObject) error {
+	ret := _m.Called(plugin, poolProtocolID, status, protocolTxID, additionalInfo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(tokens.Plugin, string, fftypes.FFEnum, string, fftypes.JSONObject) error); ok {
+		r0 = rf(plugin, poolProtocolID, status, protocolTxID, additionalInfo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TokensTransferred provides a mock function with given fields: plugin, poolProtocolID, signingKey, from, to, amount, protocolTxID, additionalInfo
+func (_m *Callbacks) TokensTransferred(plugin tokens.Plugin, poolProtocolID string, signingKey string, from string, to string, amount *fftypes.BigInt, protocolTxID string, additionalInfo fftypes.JSONObject) error {
+	ret := _m.Called(plugin, poolProtocolID, signingKey, from, to, amount, protocolTxID, additionalInfo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(tokens.Plugin, string, string, string, string, *fftypes.BigInt, string, fftypes.JSONObject) error); ok {
+		r0 = rf(plugin, poolProtocolID, signingKey, from, to, amount, protocolTxID, additionalInfo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
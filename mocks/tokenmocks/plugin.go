@@ -19,6 +19,20 @@ type Plugin struct {
 	mock.Mock
 }
 
+// BatchMint provides a mock function with given fields: ctx, operationID, identity, pool, recipients
+func (_m *Plugin) BatchMint(ctx context.Context, operationID *fftypes.UUID, identity *fftypes.Identity, pool *fftypes.TokenPool, recipients []tokens.TokenRecipient) error {
+	ret := _m.Called(ctx, operationID, identity, pool, recipients)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *fftypes.Identity, *fftypes.TokenPool, []tokens.TokenRecipient) error); ok {
+		r0 = rf(ctx, operationID, identity, pool, recipients)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Capabilities provides a mock function with given fields:
 func (_m *Plugin) Capabilities() *tokens.Capabilities {
 	ret := _m.Called()
@@ -49,6 +63,20 @@ func (_m *Plugin) CreateTokenPool(ctx context.Context, operationID *fftypes.UUID
 	return r0
 }
 
+// FreezeTokenPool provides a mock function with given fields: ctx, operationID, pool
+func (_m *Plugin) FreezeTokenPool(ctx context.Context, operationID *fftypes.UUID, pool *fftypes.TokenPool) error {
+	ret := _m.Called(ctx, operationID, pool)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *fftypes.TokenPool) error); ok {
+		r0 = rf(ctx, operationID, pool)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Init provides a mock function with given fields: ctx, name, prefix, callbacks
 func (_m *Plugin) Init(ctx context.Context, name string, prefix config.Prefix, callbacks tokens.Callbacks) error {
 	ret := _m.Called(ctx, name, prefix, callbacks)
@@ -68,6 +96,20 @@ func (_m *Plugin) InitPrefix(prefix config.PrefixArray) {
 	_m.Called(prefix)
 }
 
+// MintTokens provides a mock function with given fields: ctx, operationID, identity, pool, recipient
+func (_m *Plugin) MintTokens(ctx context.Context, operationID *fftypes.UUID, identity *fftypes.Identity, pool *fftypes.TokenPool, recipient tokens.TokenRecipient) error {
+	ret := _m.Called(ctx, operationID, identity, pool, recipient)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *fftypes.Identity, *fftypes.TokenPool, tokens.TokenRecipient) error); ok {
+		r0 = rf(ctx, operationID, identity, pool, recipient)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Name provides a mock function with given fields:
 func (_m *Plugin) Name() string {
 	ret := _m.Called()
@@ -82,6 +124,25 @@ func (_m *Plugin) Name() string {
 	return r0
 }
 
+// ReplayTokenPoolEvents provides a mock function with given fields: ctx, from, to
+func (_m *Plugin) ReplayTokenPoolEvents(ctx context.Context, from *fftypes.FFTime, to *fftypes.FFTime) error {
+	ret := _m.Called(ctx, from, to)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFTime, *fftypes.FFTime) error); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRouter provides a mock function with given fields: router
+func (_m *Plugin) SetRouter(router tokens.Router) {
+	_m.Called(router)
+}
+
 // Start provides a mock function with given fields:
 func (_m *Plugin) Start() error {
 	ret := _m.Called()
@@ -95,3 +156,17 @@ func (_m *Plugin) Start() error {
 
 	return r0
 }
+
+// UnfreezeTokenPool provides a mock function with given fields: ctx, operationID, pool
+func (_m *Plugin) UnfreezeTokenPool(ctx context.Context, operationID *fftypes.UUID, pool *fftypes.TokenPool) error {
+	ret := _m.Called(ctx, operationID, pool)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *fftypes.TokenPool) error); ok {
+		r0 = rf(ctx, operationID, pool)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
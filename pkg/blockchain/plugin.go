@@ -18,6 +18,7 @@ package blockchain
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/hyperledger/firefly/internal/config"
 	"github.com/hyperledger/firefly/pkg/fftypes"
@@ -41,11 +42,23 @@ type Plugin interface {
 	Capabilities() *Capabilities
 
 	// VerifyIdentitySyntax verifies that the supplied identity string is valid syntax according to the protocol.
-	// Can apply transformations to the supplied signing identity (only), such as lower case
+	// Can apply transformations to the supplied signing identity (only), such as lower case.
+	// The identity's KeyType selects the signing algorithm, and the plugin must reject key types it cannot support.
 	VerifyIdentitySyntax(ctx context.Context, identity *fftypes.Identity) error
 
+	// SignPayload signs the payload with the supplied PEM encoded private key, using the signing algorithm of the
+	// protocol. The key must be the key of the identity, and must not be retained by the plugin.
+	SignPayload(ctx context.Context, identity *fftypes.Identity, keyMaterial []byte, payload []byte) (string, error)
+
 	// SubmitBatchPin sequences a batch of message globally to all viewers of a given ledger
 	SubmitBatchPin(ctx context.Context, operationID *fftypes.UUID, ledgerID *fftypes.UUID, identity *fftypes.Identity, batch *BatchPin) error
+
+	// EstimateGas estimates the gas limit and gas price of a batch pin, before it is submitted.
+	// The gas price is nil if the plugin is unable to query it
+	EstimateGas(ctx context.Context, identity *fftypes.Identity, batch *BatchPin) (gasLimit, gasPrice *big.Int, err error)
+
+	// GetBlockNumber returns the current height of the chain, as seen by the blockchain connector
+	GetBlockNumber(ctx context.Context) (uint64, error)
 }
 
 // Callbacks is the interface provided to the blockchain plugin, to allow it to pass events back to firefly.
@@ -121,4 +134,8 @@ type BatchPin struct {
 	//   - The hashes contain a sender specific nonce that is a monotomically increasing number
 	//     for batches sent by that sender, within the context (maintined by the sender FireFly node)
 	Contexts []*fftypes.Bytes32
+
+	// GasLimit and GasPrice are estimated before the batch pin is submitted, and are used for the submission
+	GasLimit *big.Int
+	GasPrice *big.Int
 }
@@ -0,0 +1,39 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/config"
+	"github.com/hyperledger/firefly/pkg/fftypes"
+)
+
+// Plugin is the interface implemented by each crypto plugin
+type Plugin interface {
+	fftypes.Named
+
+	// InitPrefix initializes the set of configuration options that are valid, with defaults. Called on all plugins.
+	InitPrefix(prefix config.Prefix)
+
+	// Init initializes the plugin, with configuration
+	Init(ctx context.Context, prefix config.Prefix) error
+
+	// GenerateOrLoadKey returns the PEM encoded key pair this node uses for application-layer
+	// message encryption, generating a new key pair if one does not already exist
+	GenerateOrLoadKey(ctx context.Context) (publicKey, privateKey string, err error)
+}
@@ -97,6 +97,10 @@ const (
 	FilterOpICont FilterOp = "^="
 	// FilterOpNotICont does not contain the specified text, case insensitive
 	FilterOpNotICont FilterOp = "^!"
+	// FilterOpJSONContains the JSON object contains every key of the specified object, with the same value
+	FilterOpJSONContains FilterOp = "@>"
+	// FilterOpJSONHasKey the JSON object has the specified key
+	FilterOpJSONHasKey FilterOp = "?"
 )
 
 // FilterBuilder is the syntax used to build the filter, where And() and Or() can be nested
@@ -131,6 +135,10 @@ type FilterBuilder interface {
 	IContains(name string, value driver.Value) Filter
 	// INotContains disallows the string anywhere - case sensitive
 	NotIContains(name string, value driver.Value) Filter
+	// JSONContains requires a JSON object field to contain every key of the object, with the same value
+	JSONContains(name string, value driver.Value) Filter
+	// JSONHasKey requires a JSON object field to have the key
+	JSONHasKey(name string, key string) Filter
 }
 
 // SortField is field+direction for sorting
@@ -147,6 +155,7 @@ type FilterInfo struct {
 	Limit    uint64
 	Count    bool
 	Field    string
+	JSONPath []string // when set, the filter applies to the value at this path within the JSON content of the field
 	Op       FilterOp
 	Values   []FieldSerialization
 	Value    FieldSerialization
@@ -170,6 +179,8 @@ func valueString(f FieldSerialization) string {
 		return fmt.Sprintf("'%s'", tv)
 	case int64:
 		return strconv.FormatInt(tv, 10)
+	case float64:
+		return strconv.FormatFloat(tv, 'f', -1, 64)
 	case bool:
 		return fmt.Sprintf("%t", tv)
 	default:
@@ -192,7 +203,11 @@ func (f *FilterInfo) filterString() string {
 		}
 		return fmt.Sprintf("%s %s [%s]", f.Field, f.Op, strings.Join(strValues, ","))
 	default:
-		return fmt.Sprintf("%s %s %s", f.Field, f.Op, valueString(f.Value))
+		field := f.Field
+		if len(f.JSONPath) > 0 {
+			field = fmt.Sprintf("%s.%s", field, strings.Join(f.JSONPath, "."))
+		}
+		return fmt.Sprintf("%s %s %s", field, f.Op, valueString(f.Value))
 	}
 }
 
@@ -258,10 +273,28 @@ func (f *baseFilter) Builder() FilterBuilder {
 	return f.fb
 }
 
+// jsonPathField resolves a filter name such as "value.customer.id", where the first part of the name is
+// a JSON field, and the remainder is the path to a value within the JSON. The path is case sensitive.
+func (fb *filterBuilder) jsonPathField(name string) (field string, path []string, ok bool) {
+	parts := strings.Split(name, ".")
+	field = strings.ToLower(parts[0])
+	if _, isJSON := fb.queryFields[field].(*JSONField); !isJSON || len(parts) < 2 {
+		return "", nil, false
+	}
+	for _, p := range parts[1:] {
+		if p == "" {
+			return "", nil, false
+		}
+	}
+	return field, parts[1:], true
+}
+
 func (f *baseFilter) Finalize() (fi *FilterInfo, err error) {
 	var children []*FilterInfo
 	var value FieldSerialization
 	var values []FieldSerialization
+	var fieldName = f.field
+	var jsonPath []string
 
 	switch f.op {
 	case FilterOpAnd, FilterOpOr:
@@ -285,11 +318,27 @@ func (f *baseFilter) Finalize() (fi *FilterInfo, err error) {
 				return nil, i18n.WrapError(f.fb.ctx, err, i18n.MsgInvalidValueForFilterField, name)
 			}
 		}
+	case FilterOpJSONContains, FilterOpJSONHasKey:
+		name := strings.ToLower(f.field)
+		if _, ok := f.fb.queryFields[name].(*JSONField); !ok {
+			return nil, i18n.NewError(f.fb.ctx, i18n.MsgInvalidFilterField, name)
+		}
+		if f.op == FilterOpJSONContains {
+			value = &jsonField{}
+		} else {
+			value = &stringField{}
+		}
+		if err = value.Scan(f.value); err != nil {
+			return nil, i18n.WrapError(f.fb.ctx, err, i18n.MsgInvalidValueForFilterField, name)
+		}
 	default:
 		name := strings.ToLower(f.field)
 		field, ok := f.fb.queryFields[name]
 		if !ok {
-			return nil, i18n.NewError(f.fb.ctx, i18n.MsgInvalidFilterField, name)
+			if fieldName, jsonPath, ok = f.fb.jsonPathField(f.field); !ok {
+				return nil, i18n.NewError(f.fb.ctx, i18n.MsgInvalidFilterField, name)
+			}
+			field = &jsonPathValueField{}
 		}
 		value = field.getSerialization()
 		if err = value.Scan(f.value); err != nil {
@@ -310,7 +359,8 @@ func (f *baseFilter) Finalize() (fi *FilterInfo, err error) {
 	return &FilterInfo{
 		Children: children,
 		Op:       f.op,
-		Field:    f.field,
+		Field:    fieldName,
+		JSONPath: jsonPath,
 		Values:   values,
 		Value:    value,
 		Sort:     f.fb.sort,
@@ -448,6 +498,14 @@ func (fb *filterBuilder) NotIContains(name string, value driver.Value) Filter {
 	return fb.fieldFilter(FilterOpNotICont, name, value)
 }
 
+func (fb *filterBuilder) JSONContains(name string, value driver.Value) Filter {
+	return fb.fieldFilter(FilterOpJSONContains, name, value)
+}
+
+func (fb *filterBuilder) JSONHasKey(name string, key string) Filter {
+	return fb.fieldFilter(FilterOpJSONHasKey, name, key)
+}
+
 func (fb *filterBuilder) fieldFilter(op FilterOp, name string, value interface{}) Filter {
 	return &fieldFilter{
 		baseFilter: baseFilter{
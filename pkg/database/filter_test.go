@@ -19,6 +19,7 @@ package database
 import (
 	"context"
 	"database/sql/driver"
+	"strings"
 	"testing"
 
 	"github.com/hyperledger/firefly/pkg/fftypes"
@@ -117,7 +118,7 @@ func TestBuildMessageUUIDConvert(t *testing.T) {
 
 func TestBuildMessageBytes32Convert(t *testing.T) {
 	fb := MessageQueryFactory.NewFilter(context.Background())
-	b32, _ := fftypes.ParseBytes32(context.Background(), "7f4806535f8b3d9bf178af053d2bbdb46047365466ed16bbb0732a71492bdaf0")
+	b32, _ := fftypes.ParseBytes32Flexible(context.Background(), "7f4806535f8b3d9bf178af053d2bbdb46047365466ed16bbb0732a71492bdaf0")
 	var nilB32 *fftypes.Bytes32
 	f, err := fb.And(
 		fb.Eq("hash", b32),
@@ -274,6 +275,45 @@ func TestBuildMessageFailInt64Convert(t *testing.T) {
 	assert.Regexp(t, "FF10149.*sequence", err)
 }
 
+func TestBuildTokenBalanceBigIntConvert(t *testing.T) {
+	fb := TokenBalanceQueryFactory.NewFilter(context.Background())
+	f, err := fb.And(
+		fb.Gt("amount", "100000000000000000000"),
+		fb.Gt("amount", int64(1)),
+		fb.Gt("amount", 1),
+		fb.Gt("amount", int32(1)),
+		fb.Gt("amount", uint(1)),
+		fb.Gt("amount", uint32(1)),
+		fb.Gt("amount", uint64(1)),
+		fb.Gt("amount", fftypes.NewBigInt(1)),
+		fb.Gt("amount", *fftypes.NewBigInt(1)),
+	).Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( amount > '10000000000000000000000000000000000000000000000056bc75e2d63100000' ) && "+
+		strings.TrimSuffix(strings.Repeat("( amount > '10000000000000000000000000000000000000000000000000000000000000001' ) && ", 8), " && "), f.String())
+}
+
+func TestBuildTransactionGasPriceHex(t *testing.T) {
+	fb := TransactionQueryFactory.NewFilter(context.Background())
+	f, err := fb.And(
+		fb.Gte("gasprice", "0x4a817c800"),
+		fb.Lte("gaslimit", "21000"),
+	).Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( gasprice >= '1"+strings.Repeat("0", 55)+"4a817c800' ) && ( gaslimit <= '1"+strings.Repeat("0", 60)+"5208' )", f.String())
+
+	_, err = fb.Gte("gasprice", "0xzz").Finalize()
+	assert.Regexp(t, "FF10149.*gasprice", err)
+}
+
+func TestBuildTokenBalanceFailBigIntConvert(t *testing.T) {
+	fb := TokenBalanceQueryFactory.NewFilter(context.Background())
+	_, err := fb.Lt("amount", "not a number").Finalize()
+	assert.Regexp(t, "FF10149.*amount", err)
+	_, err = fb.Lt("amount", map[bool]bool{true: false}).Finalize()
+	assert.Regexp(t, "FF10149.*amount", err)
+}
+
 func TestBuildMessageFailTimeConvert(t *testing.T) {
 	fb := MessageQueryFactory.NewFilter(context.Background())
 	_, err := fb.Lt("created", map[bool]bool{true: false}).Finalize()
@@ -322,6 +362,49 @@ func TestBuildMessageFailJSONConvert(t *testing.T) {
 	assert.Regexp(t, "FF10149.*info", err)
 }
 
+func TestBuildJSONPathFilter(t *testing.T) {
+	fb := DataQueryFactory.NewFilter(context.Background())
+	f, err := fb.And(
+		fb.Eq("value.customer.id", 123),
+		fb.Gt("Value.Customer.Amount", 1.5),
+		fb.Contains("value.customer.name", "acme"),
+	).Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( value.customer.id == 123 ) && ( value.Customer.Amount > 1.5 ) && ( value.customer.name %= 'acme' )", f.String())
+	assert.Equal(t, "value", f.Children[1].Field)
+	assert.Equal(t, []string{"Customer", "Amount"}, f.Children[1].JSONPath)
+}
+
+func TestBuildJSONPathFilterBadField(t *testing.T) {
+	fb := DataQueryFactory.NewFilter(context.Background())
+	_, err := fb.Eq("id.customer", "abc").Finalize()
+	assert.Regexp(t, "FF10148.*id.customer", err)
+	_, err = fb.Eq("value.", "abc").Finalize()
+	assert.Regexp(t, "FF10148.*value.", err)
+	_, err = fb.Eq("value.customer", true).Finalize()
+	assert.Regexp(t, "FF10149.*value.customer", err)
+}
+
+func TestBuildJSONContainsFilter(t *testing.T) {
+	fb := MessageQueryFactory.NewFilter(context.Background())
+	f, err := fb.And(
+		fb.JSONContains("labels", fftypes.JSONObject{"region": "eu"}),
+		fb.JSONHasKey("labels", "department"),
+	).Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, `( labels @> '{"region":"eu"}' ) && ( labels ? 'department' )`, f.String())
+}
+
+func TestBuildJSONContainsFilterBadField(t *testing.T) {
+	fb := MessageQueryFactory.NewFilter(context.Background())
+	_, err := fb.JSONContains("author", fftypes.JSONObject{"region": "eu"}).Finalize()
+	assert.Regexp(t, "FF10148.*author", err)
+	_, err = fb.JSONHasKey("wrong", "region").Finalize()
+	assert.Regexp(t, "FF10148.*wrong", err)
+	_, err = fb.JSONContains("labels", true).Finalize()
+	assert.Regexp(t, "FF10149.*labels", err)
+}
+
 func TestStringsForTypes(t *testing.T) {
 
 	assert.Equal(t, "test", (&stringField{s: "test"}).String())
@@ -329,10 +412,12 @@ func TestStringsForTypes(t *testing.T) {
 	b32 := fftypes.NewRandB32()
 	assert.Equal(t, b32.String(), (&bytes32Field{b32: b32}).String())
 	assert.Equal(t, "12345", (&int64Field{i: 12345}).String())
+	assert.Equal(t, "12345", (&bigIntField{i: fftypes.NewBigInt(12345)}).String())
 	now := fftypes.Now()
 	assert.Equal(t, now.String(), (&timeField{t: now}).String())
 	assert.Equal(t, `{"some":"value"}`, (&jsonField{b: []byte(`{"some":"value"}`)}).String())
 	assert.Equal(t, "t1,t2", (&ffNameArrayField{na: fftypes.FFNameArray{"t1", "t2"}}).String())
 	assert.Equal(t, "true", (&boolField{b: true}).String())
 	assert.Equal(t, "true", (&sortableBoolField{b: true}).String())
+	assert.Equal(t, "1.5", (&jsonPathValue{v: 1.5}).String())
 }
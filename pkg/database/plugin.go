@@ -78,6 +78,9 @@ type iMessageCollection interface {
 	// UpdateMessages - Update messages
 	UpdateMessages(ctx context.Context, filter Filter, update Update) (err error)
 
+	// DeleteMessage - Delete a message, along with its data references (but not the data itself)
+	DeleteMessage(ctx context.Context, id *fftypes.UUID) (err error)
+
 	// GetMessageByID - Get a message by ID
 	GetMessageByID(ctx context.Context, id *fftypes.UUID) (message *fftypes.Message, err error)
 
@@ -105,8 +108,14 @@ type iDataCollection interface {
 	// GetData - Get data
 	GetData(ctx context.Context, filter Filter) (message []*fftypes.Data, res *FilterResult, err error)
 
+	// GetDataVersionHistory - Get the chain of versions of a data item, starting from the specified root
+	GetDataVersionHistory(ctx context.Context, rootDataID *fftypes.UUID) (history []*fftypes.Data, err error)
+
 	// GetDataRefs - Get data references only (no data)
 	GetDataRefs(ctx context.Context, filter Filter) (message fftypes.DataRefs, res *FilterResult, err error)
+
+	// DeleteData - Delete a data record
+	DeleteData(ctx context.Context, id *fftypes.UUID) (err error)
 }
 
 type iBatchCollection interface {
@@ -120,6 +129,9 @@ type iBatchCollection interface {
 	// GetBatchByID - Get a batch by ID
 	GetBatchByID(ctx context.Context, id *fftypes.UUID) (message *fftypes.Batch, err error)
 
+	// DeleteBatch - Delete a batch
+	DeleteBatch(ctx context.Context, id *fftypes.UUID) (err error)
+
 	// GetBatches - Get batches
 	GetBatches(ctx context.Context, filter Filter) (message []*fftypes.Batch, res *FilterResult, err error)
 }
@@ -135,6 +147,9 @@ type iTransactionCollection interface {
 	// GetTransactionByID - Get a transaction by ID
 	GetTransactionByID(ctx context.Context, id *fftypes.UUID) (message *fftypes.Transaction, err error)
 
+	// DeleteTransaction - Delete a transaction
+	DeleteTransaction(ctx context.Context, id *fftypes.UUID) (err error)
+
 	// GetTransactions - Get transactions
 	GetTransactions(ctx context.Context, filter Filter) (message []*fftypes.Transaction, res *FilterResult, err error)
 }
@@ -199,6 +214,18 @@ type iOperationCollection interface {
 
 	// GetOperations - Get operation
 	GetOperations(ctx context.Context, filter Filter) (operation []*fftypes.Operation, res *FilterResult, err error)
+
+	// SoftDeleteOperation - Mark an operation as deleted, without removing it from the database
+	SoftDeleteOperation(ctx context.Context, id *fftypes.UUID) (err error)
+
+	// PurgeOperations - Permanently remove operations that were soft deleted before the supplied time
+	PurgeOperations(ctx context.Context, deletedBefore *fftypes.FFTime) (err error)
+
+	// DeleteOperation - Permanently remove an operation, along with its history
+	DeleteOperation(ctx context.Context, id *fftypes.UUID) (err error)
+
+	// GetOperationHistory - Get the status transitions of an operation, oldest first
+	GetOperationHistory(ctx context.Context, id *fftypes.UUID) (history []*fftypes.OperationTransition, err error)
 }
 
 type iSubscriptionCollection interface {
@@ -234,6 +261,9 @@ type iEventCollection interface {
 
 	// GetEvents - Get events
 	GetEvents(ctx context.Context, filter Filter) (message []*fftypes.Event, res *FilterResult, err error)
+
+	// DeleteEvent - Delete an event
+	DeleteEvent(ctx context.Context, id *fftypes.UUID) (err error)
 }
 
 type iOrganizationsCollection interface {
@@ -254,6 +284,12 @@ type iOrganizationsCollection interface {
 
 	// GetOrganizations - Get organizations
 	GetOrganizations(ctx context.Context, filter Filter) (org []*fftypes.Organization, res *FilterResult, err error)
+
+	// GetMissingOrganizations - Get identities referenced by nodes or group members, that have no registered organization
+	GetMissingOrganizations(ctx context.Context) (identities []string, err error)
+
+	// GetDuplicateIdentities - Get identities that group members bind to more than one node
+	GetDuplicateIdentities(ctx context.Context) (identities []string, err error)
 }
 
 type iNodeCollection interface {
@@ -271,6 +307,9 @@ type iNodeCollection interface {
 
 	// GetNodes - Get nodes
 	GetNodes(ctx context.Context, filter Filter) (node []*fftypes.Node, res *FilterResult, err error)
+
+	// GetGhostNodes - Get the IDs of nodes referenced by group members, that are not registered
+	GetGhostNodes(ctx context.Context) (ids []*fftypes.UUID, err error)
 }
 
 type iGroupCollection interface {
@@ -335,6 +374,28 @@ type iBlobCollection interface {
 	DeleteBlob(ctx context.Context, sequence int64) (err error)
 }
 
+type iDeadLetterEventCollection interface {
+	// InsertDeadLetterEvent - insert an event that could not be delivered to a subscription
+	InsertDeadLetterEvent(ctx context.Context, dle *fftypes.DeadLetterEvent) (err error)
+
+	// GetDeadLetterEvents - get dead lettered events
+	GetDeadLetterEvents(ctx context.Context, filter Filter) (dles []*fftypes.DeadLetterEvent, res *FilterResult, err error)
+
+	// DeleteDeadLetterEvent - delete a dead lettered event, once it has been redelivered
+	DeleteDeadLetterEvent(ctx context.Context, id *fftypes.UUID) (err error)
+}
+
+type iDeliveryFailureCollection interface {
+	// UpsertDeliveryFailure - insert or update the count of failed deliveries of an event to a subscription
+	UpsertDeliveryFailure(ctx context.Context, df *fftypes.DeliveryFailure) (err error)
+
+	// GetDeliveryFailures - get the counts of failed deliveries
+	GetDeliveryFailures(ctx context.Context, filter Filter) (dfs []*fftypes.DeliveryFailure, res *FilterResult, err error)
+
+	// DeleteDeliveryFailure - delete the count of failed deliveries, once the event is delivered or dead lettered
+	DeleteDeliveryFailure(ctx context.Context, subscription, event *fftypes.UUID) (err error)
+}
+
 type iConfigRecordCollection interface {
 	// UpsertConfigRecord - Upsert a config record
 	// Throws IDMismatch error if updating and ids don't match
@@ -357,6 +418,9 @@ type iTokenPoolCollection interface {
 	// GetTokenPool - Get a token pool by name
 	GetTokenPool(ctx context.Context, ns, name string) (*fftypes.TokenPool, error)
 
+	// GetTokenPoolBySymbol - Get a token pool by ticker symbol
+	GetTokenPoolBySymbol(ctx context.Context, ns, symbol string) (*fftypes.TokenPool, error)
+
 	// GetTokenPoolByID - Get a token pool by pool ID
 	GetTokenPoolByID(ctx context.Context, id *fftypes.UUID) (*fftypes.TokenPool, error)
 
@@ -378,6 +442,17 @@ type iTokenAccountCollection interface {
 	GetTokenAccounts(ctx context.Context, filter Filter) ([]*fftypes.TokenAccount, *FilterResult, error)
 }
 
+type iTokenBalanceCollection interface {
+	// UpsertTokenBalance - Upsert the balance of an address in a token pool
+	UpsertTokenBalance(ctx context.Context, balance *fftypes.TokenBalance) error
+
+	// GetTokenBalance - Get the balance of an address in a token pool
+	GetTokenBalance(ctx context.Context, poolID *fftypes.UUID, address string) (*fftypes.TokenBalance, error)
+
+	// GetTokenBalances - Get token balances
+	GetTokenBalances(ctx context.Context, filter Filter) ([]*fftypes.TokenBalance, *FilterResult, error)
+}
+
 // PeristenceInterface are the operations that must be implemented by a database interfavce plugin.
 // The database mechanism of Firefly is designed to provide the balance between being able
 // to query the data a member of the network has transferred/received via Firefly efficiently,
@@ -427,9 +502,12 @@ type PeristenceInterface interface {
 	iNonceCollection
 	iNextPinCollection
 	iBlobCollection
+	iDeadLetterEventCollection
+	iDeliveryFailureCollection
 	iConfigRecordCollection
 	iTokenPoolCollection
 	iTokenAccountCollection
+	iTokenBalanceCollection
 }
 
 // CollectionName represents all collections
@@ -499,6 +577,7 @@ const (
 	CollectionNonces        OtherCollection = "nonces"
 	CollectionOffsets       OtherCollection = "offsets"
 	CollectionTokenAccounts OtherCollection = "tokenaccounts"
+	CollectionTokenBalances OtherCollection = "tokenbalances"
 )
 
 // Callbacks are the methods for passing data from plugin to core
@@ -545,39 +624,56 @@ var NamespaceQueryFactory = &queryFields{
 
 // MessageQueryFactory filter fields for messages
 var MessageQueryFactory = &queryFields{
-	"id":        &UUIDField{},
-	"cid":       &UUIDField{},
-	"namespace": &StringField{},
-	"type":      &StringField{},
-	"author":    &StringField{},
-	"topics":    &FFNameArrayField{},
-	"tag":       &StringField{},
-	"group":     &Bytes32Field{},
-	"created":   &TimeField{},
-	"hash":      &Bytes32Field{},
-	"pins":      &FFNameArrayField{},
-	"rejected":  &BoolField{},
-	"pending":   &SortableBoolField{},
-	"confirmed": &TimeField{},
-	"sequence":  &Int64Field{},
-	"txtype":    &StringField{},
-	"batch":     &UUIDField{},
-	"local":     &BoolField{},
+	"id":            &UUIDField{},
+	"cid":           &UUIDField{},
+	"namespace":     &StringField{},
+	"type":          &StringField{},
+	"author":        &StringField{},
+	"topics":        &FFNameArrayField{},
+	"tag":           &StringField{},
+	"group":         &Bytes32Field{},
+	"created":       &TimeField{},
+	"hash":          &Bytes32Field{},
+	"pins":          &FFNameArrayField{},
+	"rejected":      &BoolField{},
+	"pending":       &SortableBoolField{},
+	"confirmed":     &TimeField{},
+	"sequence":      &Int64Field{},
+	"txtype":        &StringField{},
+	"batch":         &UUIDField{},
+	"local":         &BoolField{},
+	"readat":        &TimeField{},
+	"sensitivity":   &StringField{},
+	"thread":        &UUIDField{},
+	"pinned":        &BoolField{},
+	"deadline":      &TimeField{},
+	"error":         &StringField{},
+	"labels":        &JSONField{},
+	"forwarded":     &BoolField{},
+	"forwardedfrom": &UUIDField{},
+	"contenttype":   &StringField{},
+	"ephemeral":     &BoolField{},
+	"createdby":     &StringField{},
 }
 
 // BatchQueryFactory filter fields for batches
 var BatchQueryFactory = &queryFields{
-	"id":         &UUIDField{},
-	"namespace":  &StringField{},
-	"type":       &StringField{},
-	"author":     &StringField{},
-	"group":      &Bytes32Field{},
-	"hash":       &Bytes32Field{},
-	"payloadref": &StringField{},
-	"created":    &TimeField{},
-	"confirmed":  &TimeField{},
-	"tx.type":    &StringField{},
-	"tx.id":      &UUIDField{},
+	"id":          &UUIDField{},
+	"namespace":   &StringField{},
+	"type":        &StringField{},
+	"author":      &StringField{},
+	"creatornode": &UUIDField{},
+	"group":       &Bytes32Field{},
+	"hash":        &Bytes32Field{},
+	"payloadref":  &StringField{},
+	"created":     &TimeField{},
+	"confirmed":   &TimeField{},
+	"confirmedat": &TimeField{},
+	"pincount":    &Int64Field{},
+	"publishedat": &TimeField{},
+	"tx.type":     &StringField{},
+	"tx.id":       &UUIDField{},
+	"tags":        &StringField{},
 }
 
 // TransactionQueryFactory filter fields for transactions
@@ -592,6 +688,8 @@ var TransactionQueryFactory = &queryFields{
 	"sequence":   &Int64Field{},
 	"info":       &JSONField{},
 	"namespace":  &StringField{},
+	"gaslimit":   &BigIntField{},
+	"gasprice":   &BigIntField{},
 }
 
 // DataQueryFactory filter fields for data
@@ -604,7 +702,13 @@ var DataQueryFactory = &queryFields{
 	"hash":             &Bytes32Field{},
 	"blob.hash":        &Bytes32Field{},
 	"blob.public":      &StringField{},
+	"blob.uploadedby":  &StringField{},
 	"created":          &TimeField{},
+	"version":          &Int64Field{},
+	"previous":         &UUIDField{},
+	"createdfrom":      &UUIDField{},
+	"expiresat":        &TimeField{},
+	"value":            &JSONField{},
 }
 
 // DatatypeQueryFactory filter fields for data definitions
@@ -640,20 +744,25 @@ var OperationQueryFactory = &queryFields{
 	"backendid": &StringField{},
 	"created":   &TimeField{},
 	"updated":   &TimeField{},
+	"deleted":   &TimeField{},
+	"priority":  &Int64Field{},
+	"createdby": &StringField{},
 }
 
 // SubscriptionQueryFactory filter fields for data subscriptions
 var SubscriptionQueryFactory = &queryFields{
-	"id":            &UUIDField{},
-	"namespace":     &StringField{},
-	"name":          &StringField{},
-	"transport":     &StringField{},
-	"events":        &StringField{},
-	"filter.topics": &StringField{},
-	"filter.tag":    &StringField{},
-	"filter.group":  &StringField{},
-	"options":       &StringField{},
-	"created":       &TimeField{},
+	"id":                  &UUIDField{},
+	"namespace":           &StringField{},
+	"name":                &StringField{},
+	"transport":           &StringField{},
+	"events":              &StringField{},
+	"filter.topics":       &StringField{},
+	"filter.tag":          &StringField{},
+	"filter.group":        &StringField{},
+	"options":             &StringField{},
+	"created":             &TimeField{},
+	"maxdeliveryattempts": &Int64Field{},
+	"paused":              &BoolField{},
 }
 
 // EventQueryFactory filter fields for data events
@@ -686,6 +795,8 @@ var OrganizationQueryFactory = &queryFields{
 	"identity":    &StringField{},
 	"description": &StringField{},
 	"profile":     &JSONField{},
+	"metadata":    &JSONField{},
+	"country":     &StringField{},
 	"created":     &TimeField{},
 }
 
@@ -703,12 +814,18 @@ var NodeQueryFactory = &queryFields{
 
 // GroupQueryFactory filter fields for nodes
 var GroupQueryFactory = &queryFields{
-	"hash":        &Bytes32Field{},
-	"message":     &UUIDField{},
-	"namespace":   &StringField{},
-	"description": &StringField{},
-	"ledger":      &UUIDField{},
-	"created":     &TimeField{},
+	"hash":           &Bytes32Field{},
+	"message":        &UUIDField{},
+	"namespace":      &StringField{},
+	"name":           &StringField{},
+	"description":    &StringField{},
+	"ledger":         &UUIDField{},
+	"nonce":          &Int64Field{},
+	"created":        &TimeField{},
+	"schema.name":    &StringField{},
+	"schema.version": &StringField{},
+	"acl":            &JSONField{},
+	"previous":       &Bytes32Field{},
 }
 
 // NonceQueryFactory filter fields for nodes
@@ -738,6 +855,26 @@ var BlobQueryFactory = &queryFields{
 	"hash":       &Bytes32Field{},
 	"payloadref": &StringField{},
 	"created":    &TimeField{},
+	"uploadedby": &StringField{},
+}
+
+// DeadLetterEventQueryFactory filter fields for dead lettered events
+var DeadLetterEventQueryFactory = &queryFields{
+	"id":           &UUIDField{},
+	"namespace":    &StringField{},
+	"subscription": &UUIDField{},
+	"event":        &UUIDField{},
+	"attempts":     &Int64Field{},
+	"created":      &TimeField{},
+}
+
+// DeliveryFailureQueryFactory filter fields for counts of failed deliveries
+var DeliveryFailureQueryFactory = &queryFields{
+	"namespace":    &StringField{},
+	"subscription": &UUIDField{},
+	"event":        &UUIDField{},
+	"attempts":     &Int64Field{},
+	"updated":      &TimeField{},
 }
 
 // TokenPoolQueryFactory filter fields for token pools
@@ -750,6 +887,7 @@ var TokenPoolQueryFactory = &queryFields{
 	"symbol":     &StringField{},
 	"message":    &UUIDField{},
 	"created":    &TimeField{},
+	"status":     &StringField{},
 }
 
 // TokenAccountQueryFactory filter fields for token accounts
@@ -759,3 +897,11 @@ var TokenAccountQueryFactory = &queryFields{
 	"identity":   &StringField{},
 	"balance":    &Int64Field{},
 }
+
+// TokenBalanceQueryFactory filter fields for token balances
+var TokenBalanceQueryFactory = &queryFields{
+	"pool":        &UUIDField{},
+	"address":     &StringField{},
+	"amount":      &BigIntField{},
+	"lastupdated": &TimeField{},
+}
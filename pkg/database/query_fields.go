@@ -22,6 +22,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
@@ -165,7 +166,7 @@ func (f *bytes32Field) Scan(src interface{}) (err error) {
 			f.b32 = nil
 			return nil
 		}
-		f.b32, err = fftypes.ParseBytes32(context.Background(), tv)
+		f.b32, err = fftypes.ParseBytes32Flexible(context.Background(), tv)
 		return err
 	case *fftypes.Bytes32:
 		f.b32 = tv
@@ -213,6 +214,46 @@ func (f *int64Field) Value() (driver.Value, error)         { return f.i, nil }
 func (f *int64Field) String() string                       { return fmt.Sprintf("%d", f.i) }
 func (f *Int64Field) getSerialization() FieldSerialization { return &int64Field{} }
 
+type BigIntField struct{}
+type bigIntField struct{ i *fftypes.BigInt }
+
+func (f *bigIntField) Scan(src interface{}) (err error) {
+	switch tv := src.(type) {
+	case int:
+		f.i = fftypes.NewBigInt(int64(tv))
+	case int32:
+		f.i = fftypes.NewBigInt(int64(tv))
+	case int64:
+		f.i = fftypes.NewBigInt(tv)
+	case uint:
+		f.i = fftypes.NewBigInt(int64(tv))
+	case uint32:
+		f.i = fftypes.NewBigInt(int64(tv))
+	case uint64:
+		f.i = (*fftypes.BigInt)(new(big.Int).SetUint64(tv))
+	case string:
+		// Filter values are supplied in decimal, or as 0x prefixed hex
+		i, ok := new(big.Int).SetString(tv, 10)
+		if strings.HasPrefix(tv, "0x") {
+			i, ok = new(big.Int).SetString(tv[2:], 16)
+		}
+		if !ok {
+			return i18n.NewError(context.Background(), i18n.MsgScanFailed, src, f.i)
+		}
+		f.i = (*fftypes.BigInt)(i)
+	case *fftypes.BigInt:
+		f.i = tv
+	case fftypes.BigInt:
+		f.i = &tv
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgScanFailed, src, f.i)
+	}
+	return nil
+}
+func (f *bigIntField) Value() (driver.Value, error)         { return f.i.Value() }
+func (f *bigIntField) String() string                       { return f.i.String() }
+func (f *BigIntField) getSerialization() FieldSerialization { return &bigIntField{} }
+
 type TimeField struct{}
 type timeField struct{ t *fftypes.FFTime }
 
@@ -270,6 +311,26 @@ func (f *jsonField) Value() (driver.Value, error)         { return f.b, nil }
 func (f *jsonField) String() string                       { return string(f.b) }
 func (f *JSONField) getSerialization() FieldSerialization { return &jsonField{} }
 
+// jsonPathValueField is the value compared against a path within a JSON field. Numbers keep their type,
+// so the database can compare them as numbers rather than strings.
+type jsonPathValueField struct{}
+type jsonPathValue struct{ v driver.Value }
+
+func (f *jsonPathValue) Scan(src interface{}) (err error) {
+	switch tv := src.(type) {
+	case string, int64, float64:
+		f.v = tv
+	case int:
+		f.v = int64(tv)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgScanFailed, src, f.v)
+	}
+	return nil
+}
+func (f *jsonPathValue) Value() (driver.Value, error)              { return f.v, nil }
+func (f *jsonPathValue) String() string                            { return fmt.Sprintf("%v", f.v) }
+func (f *jsonPathValueField) getSerialization() FieldSerialization { return &jsonPathValue{} }
+
 type FFNameArrayField struct{}
 type ffNameArrayField struct{ na fftypes.FFNameArray }
 
@@ -81,6 +81,9 @@ type Plugin interface {
 	// DownloadBLOB streams a received blob out of storage
 	DownloadBLOB(ctx context.Context, payloadRef string) (content io.ReadCloser, err error)
 
+	// DeleteBLOB removes a blob from storage, once it is no longer required
+	DeleteBLOB(ctx context.Context, payloadRef string) (err error)
+
 	// CheckBLOBReceived confirms that a blob with the specified hash has been received from the specified peer
 	CheckBLOBReceived(ctx context.Context, peerID, ns string, id fftypes.UUID) (hash *fftypes.Bytes32, err error)
 
@@ -88,6 +91,9 @@ type Plugin interface {
 	// Should return as quickly as possible for parallelsim, then report completion asynchronously via the operation ID
 	SendMessage(ctx context.Context, peerID string, data []byte) (trackingID string, err error)
 
+	// GetMessageStatus returns the acknowledgement status of a message sent with SendMessage, or nil if the tracking ID is not known
+	GetMessageStatus(ctx context.Context, trackingID string) (status *fftypes.MessageDeliveryStatus, err error)
+
 	// TransferBLOB initiates a transfer of a previoiusly stored blob to another node
 	TransferBLOB(ctx context.Context, peerID string, payloadRef string) (trackingID string, err error)
 }
@@ -0,0 +1,124 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+)
+
+// base58Alphabet is the Bitcoin alphabet, which is also the one used by IPFS for CIDv0
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// multihashSHA256Prefix is the multihash code (sha2-256) and length (32) that prefix the digest in an IPFS CIDv0
+var multihashSHA256Prefix = []byte{0x12, 0x20}
+
+var base58Index = func() (idx [256]int) {
+	for i := range idx {
+		idx[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		idx[base58Alphabet[i]] = i
+	}
+	return idx
+}()
+
+func base58Encode(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+	// log(256)/log(58) is ~1.37, so this is always big enough for the digits
+	digits := make([]byte, 0, len(b)*138/100+1)
+	for _, v := range b[zeros:] {
+		carry := int(v)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+	out := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out[i] = base58Alphabet[0]
+	}
+	for i, d := range digits {
+		out[len(out)-1-i] = base58Alphabet[d]
+	}
+	return string(out)
+}
+
+func base58Decode(ctx context.Context, s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	bytes := make([]byte, 0, len(s))
+	for i := zeros; i < len(s); i++ {
+		carry := base58Index[s[i]]
+		if carry < 0 {
+			return nil, i18n.NewError(ctx, i18n.MsgInvalidBase58)
+		}
+		for j := range bytes {
+			carry += int(bytes[j]) * 58
+			bytes[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			bytes = append(bytes, byte(carry))
+			carry >>= 8
+		}
+	}
+	out := make([]byte, zeros+len(bytes))
+	for i, b := range bytes {
+		out[len(out)-1-i] = b
+	}
+	return out, nil
+}
+
+// ToBase58 returns the base58 encoding of the 32 bytes
+func (b32 *Bytes32) ToBase58() string {
+	return base58Encode(b32[:])
+}
+
+// ToIPFSCIDv0 treats the bytes as a sha2-256 digest, and returns the IPFS CIDv0 (Qm...) that refers to it
+func (b32 *Bytes32) ToIPFSCIDv0() string {
+	return base58Encode(append(append([]byte{}, multihashSHA256Prefix...), b32[:]...))
+}
+
+// ParseBytes32FromBase58 parses a base58 string that is either the encoding of 32 bytes,
+// or an IPFS CIDv0 - in which case the sha2-256 digest is returned
+func ParseBytes32FromBase58(ctx context.Context, s string) (*Bytes32, error) {
+	b, err := base58Decode(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 34 && b[0] == multihashSHA256Prefix[0] && b[1] == multihashSHA256Prefix[1] {
+		b = b[2:]
+	}
+	if len(b) != 32 {
+		return nil, i18n.NewError(ctx, i18n.MsgInvalidWrongLenBase58B32)
+	}
+	var b32 Bytes32
+	copy(b32[:], b)
+	return &b32, nil
+}
@@ -0,0 +1,84 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBase58KnownVectors(t *testing.T) {
+	assert.Equal(t, "2NEpo7TZRRrLZSi2U", base58Encode([]byte("Hello World!")))
+	assert.Equal(t, "1111", base58Encode([]byte{0, 0, 0, 0}))
+	assert.Equal(t, "", base58Encode([]byte{}))
+
+	b, err := base58Decode(context.Background(), "2NEpo7TZRRrLZSi2U")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello World!", string(b))
+
+	b, err = base58Decode(context.Background(), "11")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0}, b)
+}
+
+func TestBase58DecodeBadChar(t *testing.T) {
+	_, err := base58Decode(context.Background(), "0OIl")
+	assert.Regexp(t, "FF10318", err)
+}
+
+func TestBytes32Base58RoundTrip(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		b32 := NewRandB32()
+		if i == 0 {
+			b32 = &Bytes32{} // all zeros
+		}
+		b2, err := ParseBytes32FromBase58(context.Background(), b32.ToBase58())
+		assert.NoError(t, err)
+		assert.Equal(t, b32, b2)
+
+		b2, err = ParseBytes32FromBase58(context.Background(), b32.ToIPFSCIDv0())
+		assert.NoError(t, err)
+		assert.Equal(t, b32, b2)
+	}
+}
+
+func TestBytes32IPFSCIDv0KnownVectors(t *testing.T) {
+	// The CIDv0 digest is the sha2-256 of the dag-pb encoded IPFS block
+	emptyFileBlock, _ := hex.DecodeString("0a0408021800")
+	emptyDirBlock, _ := hex.DecodeString("0a020801")
+	for cid, block := range map[string][]byte{
+		"QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH": emptyFileBlock,
+		"QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn": emptyDirBlock,
+	} {
+		digest := Bytes32(sha256.Sum256(block))
+		assert.Equal(t, cid, digest.ToIPFSCIDv0())
+		b32, err := ParseBytes32FromBase58(context.Background(), cid)
+		assert.NoError(t, err)
+		assert.Equal(t, digest, *b32)
+	}
+}
+
+func TestParseBytes32FromBase58Fail(t *testing.T) {
+	_, err := ParseBytes32FromBase58(context.Background(), "!bad")
+	assert.Regexp(t, "FF10318", err)
+	_, err = ParseBytes32FromBase58(context.Background(), "2NEpo7TZRRrLZSi2U")
+	assert.Regexp(t, "FF10319", err)
+}
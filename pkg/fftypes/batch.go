@@ -21,28 +21,43 @@ import (
 	"crypto/sha256"
 	"database/sql/driver"
 	"encoding/json"
+	"strings"
 
 	"github.com/hyperledger/firefly/internal/i18n"
+	"github.com/hyperledger/firefly/internal/log"
+	"github.com/klauspost/compress/zstd"
 )
 
+// BatchSchemaVersion is the version of the batch format written by this node. It must be
+// incremented whenever a change is made that older nodes would not be able to process.
+// Batches written before the version was introduced have a version of zero.
+const BatchSchemaVersion = 1
+
 type Batch struct {
-	ID         *UUID        `json:"id"`
-	Namespace  string       `json:"namespace"`
-	Type       MessageType  `json:"type"`
-	Author     string       `json:"author"`
-	Group      *Bytes32     `jdon:"group,omitempty"`
-	Hash       *Bytes32     `json:"hash"`
-	Created    *FFTime      `json:"created"`
-	Confirmed  *FFTime      `json:"confirmed"`
-	Payload    BatchPayload `json:"payload"`
-	PayloadRef string       `json:"payloadRef,omitempty"`
-	Blobs      []*Bytes32   `json:"blobs,omitempty"` // only used in-flight
+	ID            *UUID        `json:"id"`
+	Namespace     string       `json:"namespace"`
+	Type          MessageType  `json:"type"`
+	Author        string       `json:"author"`
+	SchemaVersion int          `json:"schemaVersion,omitempty"`
+	CreatorNodeID *UUID        `json:"creatorNode,omitempty"` // the node within the author's org that dispatched the batch
+	Group         *Bytes32     `jdon:"group,omitempty"`
+	Hash          *Bytes32     `json:"hash"`
+	Created       *FFTime      `json:"created"`
+	Confirmed     *FFTime      `json:"confirmed"`
+	ConfirmedAt   *FFTime      `json:"confirmedAt,omitempty"` // when the blockchain pin of the batch was received
+	PinCount      int          `json:"pinCount,omitempty"`    // the number of blockchain pins (contexts) associated with the batch
+	PublishedAt   *FFTime      `json:"publishedAt,omitempty"` // when the batch was uploaded to the public storage, for broadcast batches
+	Payload       BatchPayload `json:"payload"`
+	PayloadRef    string       `json:"payloadRef,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+	Blobs         []*Bytes32   `json:"blobs,omitempty"` // only used in-flight
 }
 
 type BatchPayload struct {
 	TX       TransactionRef `json:"tx"`
 	Messages []*Message     `json:"messages"`
 	Data     []*Data        `json:"data"`
+	Groups   GroupRefs      `json:"groups,omitempty"` // additional groups to deliver a private batch to, beyond the batch group
 }
 
 // Value implements sql.Valuer
@@ -56,6 +71,176 @@ func (ma *BatchPayload) Hash() *Bytes32 {
 	return &b32
 }
 
+// VerifyHash recomputes the hash of the payload, and checks it matches the hash set on the batch
+func (b *Batch) VerifyHash(ctx context.Context) error {
+	hash := b.Payload.Hash()
+	if b.Hash == nil || *b.Hash != *hash {
+		return i18n.NewError(ctx, i18n.MsgBatchPayloadHashMismatch, b.Hash, hash)
+	}
+	return nil
+}
+
+// TargetGroups returns the hashes of all the groups a private batch is delivered to - the batch group
+// first, followed by any additional groups listed in the payload, without duplicates
+func (b *Batch) TargetGroups() []*Bytes32 {
+	return targetGroups(b.Group, b.Payload.Groups)
+}
+
+func targetGroups(group *Bytes32, additional GroupRefs) []*Bytes32 {
+	groups := make([]*Bytes32, 0, 1+len(additional))
+	candidates := []*Bytes32{group}
+	for _, g := range additional {
+		candidates = append(candidates, g.Hash)
+	}
+	for _, c := range candidates {
+		if c == nil {
+			continue
+		}
+		dup := false
+		for _, g := range groups {
+			if *g == *c {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			groups = append(groups, c)
+		}
+	}
+	return groups
+}
+
+// AdditionalGroups returns the target groups of a private batch other than the batch group, in the
+// order their pins follow the batch group's pins in the batch contexts and on each message
+func (b *Batch) AdditionalGroups() []*Bytes32 {
+	groups := b.TargetGroups()
+	if len(groups) > 0 && b.Group != nil && *groups[0] == *b.Group {
+		return groups[1:]
+	}
+	return groups
+}
+
+// ValidateNamespace checks the batch belongs to the namespace it is being processed for
+func (b *Batch) ValidateNamespace(ctx context.Context, configured string) error {
+	if b.Namespace != configured {
+		return i18n.NewError(ctx, i18n.MsgBatchNamespaceMismatch, b.Namespace, configured)
+	}
+	return nil
+}
+
+// VerifySchemaVersion checks the batch was written in a format this node can process
+func (b *Batch) VerifySchemaVersion(ctx context.Context) error {
+	if b.SchemaVersion > BatchSchemaVersion {
+		return i18n.NewError(ctx, i18n.MsgUnsupportedBatchSchema, b.SchemaVersion, BatchSchemaVersion)
+	}
+	if b.SchemaVersion < BatchSchemaVersion {
+		log.L(ctx).Warnf("Batch '%s' has older schema version %d (current=%d)", b.ID, b.SchemaVersion, BatchSchemaVersion)
+	}
+	return nil
+}
+
+// Compress serializes the batch, and compresses it with zstd at the supplied level
+func (b *Batch) Compress(level zstd.EncoderLevel) ([]byte, error) {
+	ctx := context.Background()
+	w, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgBatchCompressFailed)
+	}
+	defer w.Close()
+	j, err := json.Marshal(b)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgBatchCompressFailed)
+	}
+	return w.EncodeAll(j, nil), nil
+}
+
+// DecompressBatch restores a batch from the bytes returned by Compress
+func DecompressBatch(data []byte) (*Batch, error) {
+	ctx := context.Background()
+	r, _ := zstd.NewReader(nil) // cannot fail without options
+	defer r.Close()
+	b, err := r.DecodeAll(data, nil)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgBatchDecompressFailed)
+	}
+	var batch Batch
+	if err = json.Unmarshal(b, &batch); err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgBatchDecompressFailed)
+	}
+	return &batch, nil
+}
+
+// Validate performs the checks on the content of a batch payload that are required before it is dispatched
+func (ma *BatchPayload) Validate(ctx context.Context) error {
+	for i, d := range ma.Data {
+		if d == nil || d.ID == nil {
+			return i18n.NewError(ctx, i18n.MsgBatchPayloadNilDataID, i)
+		}
+		if len(d.Value) > 0 && !json.Valid(d.Value) {
+			return i18n.NewError(ctx, i18n.MsgBatchPayloadInvalidJSON, i)
+		}
+		if d.Hash == nil {
+			return i18n.NewError(ctx, i18n.MsgBatchPayloadNilDataHash, i)
+		}
+	}
+	if ma.TX.ID == nil {
+		return i18n.NewError(ctx, i18n.MsgBatchPayloadNilTXID)
+	}
+	return nil
+}
+
+// MessageTags returns the union of the tags of all messages in the payload, in the order they are first seen
+func (ma *BatchPayload) MessageTags() []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, msg := range ma.Messages {
+		if msg == nil || msg.Header.Tag == "" || seen[msg.Header.Tag] {
+			continue
+		}
+		seen[msg.Header.Tag] = true
+		tags = append(tags, msg.Header.Tag)
+	}
+	return tags
+}
+
+// Priority returns the highest priority of any message in the payload
+func (ma *BatchPayload) Priority() uint8 {
+	var priority uint8
+	for _, msg := range ma.Messages {
+		if msg != nil && msg.Header.Priority > priority {
+			priority = msg.Header.Priority
+		}
+	}
+	return priority
+}
+
+// Ephemeral returns true if the payload contains messages, and every one of them is ephemeral
+func (ma *BatchPayload) Ephemeral() bool {
+	if len(ma.Messages) == 0 {
+		return false
+	}
+	for _, msg := range ma.Messages {
+		if msg == nil || !msg.Ephemeral {
+			return false
+		}
+	}
+	return true
+}
+
+// CreatedBy returns the identities that submitted the messages in the payload, as a comma separated
+// list with duplicates removed, or an empty string if all the messages were created by the node itself
+func (ma *BatchPayload) CreatedBy() string {
+	createdBy := make([]string, 0)
+	known := make(map[string]bool)
+	for _, msg := range ma.Messages {
+		if msg != nil && msg.CreatedBy != "" && !known[msg.CreatedBy] {
+			known[msg.CreatedBy] = true
+			createdBy = append(createdBy, msg.CreatedBy)
+		}
+	}
+	return strings.Join(createdBy, ",")
+}
+
 // Scan implements sql.Scanner
 func (ma *BatchPayload) Scan(src interface{}) error {
 	switch src := src.(type) {
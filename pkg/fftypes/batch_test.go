@@ -17,9 +17,13 @@
 package fftypes
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -62,3 +66,285 @@ func TestSQLSerializedMessageArray(t *testing.T) {
 	assert.NotNil(t, hash)
 
 }
+
+func TestBatchPayloadMessageTags(t *testing.T) {
+	batchPayload := BatchPayload{
+		Messages: []*Message{
+			{Header: MessageHeader{Tag: "tag1"}},
+			{Header: MessageHeader{Tag: ""}},
+			nil,
+			{Header: MessageHeader{Tag: "tag2"}},
+			{Header: MessageHeader{Tag: "tag1"}},
+		},
+	}
+	assert.Equal(t, []string{"tag1", "tag2"}, batchPayload.MessageTags())
+
+	emptyPayload := BatchPayload{}
+	assert.Equal(t, []string{}, emptyPayload.MessageTags())
+}
+
+func TestBatchPayloadMessageTagsFullBatch(t *testing.T) {
+	batchPayload := BatchPayload{}
+	for i := 0; i < 200; i++ {
+		batchPayload.Messages = append(batchPayload.Messages, &Message{
+			Header: MessageHeader{Tag: fmt.Sprintf("%s%.3d", strings.Repeat("t", 61), i)},
+		})
+	}
+	tags := batchPayload.MessageTags()
+	assert.Len(t, tags, 200)
+	assert.Equal(t, strings.Repeat("t", 61)+"199", tags[199])
+}
+
+func TestBatchPayloadPriority(t *testing.T) {
+	batchPayload := BatchPayload{
+		Messages: []*Message{
+			{Header: MessageHeader{Priority: 1}},
+			nil,
+			{Header: MessageHeader{Priority: 5}},
+			{Header: MessageHeader{}},
+		},
+	}
+	assert.Equal(t, uint8(5), batchPayload.Priority())
+
+	emptyPayload := BatchPayload{}
+	assert.Equal(t, uint8(0), emptyPayload.Priority())
+}
+
+func TestBatchPayloadEphemeral(t *testing.T) {
+	batchPayload := BatchPayload{
+		Messages: []*Message{
+			{Ephemeral: true},
+			{Ephemeral: true},
+		},
+	}
+	assert.True(t, batchPayload.Ephemeral())
+
+	batchPayload.Messages = append(batchPayload.Messages, &Message{})
+	assert.False(t, batchPayload.Ephemeral())
+
+	batchPayload.Messages = []*Message{nil}
+	assert.False(t, batchPayload.Ephemeral())
+
+	emptyPayload := BatchPayload{}
+	assert.False(t, emptyPayload.Ephemeral())
+}
+
+func TestBatchPayloadCreatedBy(t *testing.T) {
+	batchPayload := BatchPayload{
+		Messages: []*Message{
+			{CreatedBy: "org1"},
+			nil,
+			{},
+			{CreatedBy: "org2"},
+			{CreatedBy: "org1"},
+		},
+	}
+	assert.Equal(t, "org1,org2", batchPayload.CreatedBy())
+
+	emptyPayload := BatchPayload{}
+	assert.Equal(t, "", emptyPayload.CreatedBy())
+}
+
+func TestBatchVerifyHash(t *testing.T) {
+	batch := &Batch{
+		Payload: BatchPayload{
+			Messages: []*Message{{Header: MessageHeader{ID: NewUUID()}}},
+		},
+	}
+	err := batch.VerifyHash(context.Background())
+	assert.Regexp(t, "FF10282", err)
+
+	batch.Hash = batch.Payload.Hash()
+	err = batch.VerifyHash(context.Background())
+	assert.NoError(t, err)
+
+	batch.Payload.Messages[0].Header.Tag = "tampered"
+	err = batch.VerifyHash(context.Background())
+	assert.Regexp(t, "FF10282.*"+batch.Hash.String(), err)
+}
+
+func TestBatchTargetGroups(t *testing.T) {
+	g1 := NewRandB32()
+	g2 := NewRandB32()
+	b := &Batch{
+		Group: g1,
+		Payload: BatchPayload{
+			Groups: []GroupRef{{Hash: g2}, {Hash: g1}, {Hash: nil}},
+		},
+	}
+	assert.Equal(t, []*Bytes32{g1, g2}, b.TargetGroups())
+
+	assert.Equal(t, []*Bytes32{g2}, b.AdditionalGroups())
+
+	b = &Batch{}
+	assert.Empty(t, b.TargetGroups())
+	assert.Empty(t, b.AdditionalGroups())
+}
+
+func TestBatchPayloadGroupsOmitted(t *testing.T) {
+	b, err := json.Marshal(&BatchPayload{})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "groups")
+}
+
+func TestBatchValidateNamespace(t *testing.T) {
+	batch := &Batch{Namespace: "ns1"}
+	assert.NoError(t, batch.ValidateNamespace(context.Background(), "ns1"))
+	err := batch.ValidateNamespace(context.Background(), "ns2")
+	assert.Regexp(t, "FF10338.*ns1.*ns2", err)
+}
+
+func TestBatchVerifySchemaVersion(t *testing.T) {
+	batch := &Batch{SchemaVersion: BatchSchemaVersion}
+	assert.NoError(t, batch.VerifySchemaVersion(context.Background()))
+
+	batch.SchemaVersion = 0
+	assert.NoError(t, batch.VerifySchemaVersion(context.Background()))
+
+	batch.SchemaVersion = BatchSchemaVersion + 1
+	err := batch.VerifySchemaVersion(context.Background())
+	assert.Regexp(t, "FF10312.*2", err)
+}
+
+func TestBatchPayloadValidate(t *testing.T) {
+	validData := func() *Data {
+		return &Data{ID: NewUUID(), Hash: NewRandB32(), Value: Byteable(`{"some":"data"}`)}
+	}
+	tests := []struct {
+		name    string
+		payload BatchPayload
+		err     string
+	}{
+		{
+			name:    "valid",
+			payload: BatchPayload{TX: TransactionRef{ID: NewUUID()}, Data: []*Data{validData(), {ID: NewUUID(), Hash: NewRandB32()}}},
+		},
+		{
+			name:    "invalid JSON",
+			payload: BatchPayload{TX: TransactionRef{ID: NewUUID()}, Data: []*Data{validData(), {ID: NewUUID(), Hash: NewRandB32(), Value: Byteable(`!json`)}}},
+			err:     "FF10284.*1",
+		},
+		{
+			name:    "nil data",
+			payload: BatchPayload{TX: TransactionRef{ID: NewUUID()}, Data: []*Data{nil}},
+			err:     "FF10285.*0",
+		},
+		{
+			name:    "nil data ID",
+			payload: BatchPayload{TX: TransactionRef{ID: NewUUID()}, Data: []*Data{{Hash: NewRandB32()}}},
+			err:     "FF10285.*0",
+		},
+		{
+			name:    "nil data hash",
+			payload: BatchPayload{TX: TransactionRef{ID: NewUUID()}, Data: []*Data{{ID: NewUUID()}}},
+			err:     "FF10286.*0",
+		},
+		{
+			name:    "nil TX ID",
+			payload: BatchPayload{Data: []*Data{validData()}},
+			err:     "FF10287",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.payload.Validate(context.Background())
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestBatchCompressDecompress(t *testing.T) {
+	batch := &Batch{
+		ID:        NewUUID(),
+		Namespace: "ns1",
+		Payload: BatchPayload{
+			Messages: []*Message{{Header: MessageHeader{ID: NewUUID(), Topics: FFNameArray{"topic1"}}}},
+			Data:     []*Data{{ID: NewUUID(), Value: Byteable(`{"some":"data"}`)}},
+		},
+	}
+	batch.Hash = batch.Payload.Hash()
+
+	b, err := batch.Compress(zstd.SpeedBestCompression)
+	assert.NoError(t, err)
+
+	batch2, err := DecompressBatch(b)
+	assert.NoError(t, err)
+	assert.Equal(t, batch.ID, batch2.ID)
+	assert.Equal(t, *batch.Hash, *batch2.Payload.Hash())
+}
+
+func TestBatchCompressBadLevel(t *testing.T) {
+	_, err := (&Batch{}).Compress(zstd.EncoderLevel(99))
+	assert.Regexp(t, "FF10326", err)
+}
+
+func TestBatchCompressBadData(t *testing.T) {
+	batch := &Batch{
+		Payload: BatchPayload{
+			Data: []*Data{{Value: Byteable(`!json`)}},
+		},
+	}
+	_, err := batch.Compress(zstd.SpeedDefault)
+	assert.Regexp(t, "FF10326", err)
+}
+
+func TestDecompressBatchNotZstd(t *testing.T) {
+	_, err := DecompressBatch([]byte(`{}`))
+	assert.Regexp(t, "FF10327", err)
+}
+
+func TestDecompressBatchTruncated(t *testing.T) {
+	b, err := (&Batch{}).Compress(zstd.SpeedDefault)
+	assert.NoError(t, err)
+	_, err = DecompressBatch(b[0 : len(b)-4])
+	assert.Regexp(t, "FF10327", err)
+}
+
+func TestDecompressBatchBadJSON(t *testing.T) {
+	w, _ := zstd.NewWriter(nil)
+	_, err := DecompressBatch(w.EncodeAll([]byte(`!json`), nil))
+	assert.Regexp(t, "FF10327", err)
+}
+
+func BenchmarkBatchCompress(b *testing.B) {
+	batch := &Batch{
+		ID:        NewUUID(),
+		Namespace: "ns1",
+		Type:      MessageTypeBroadcast,
+		Author:    "0x12345",
+	}
+	for i := 0; i < 50; i++ {
+		msg := &Message{
+			Header: MessageHeader{
+				ID:        NewUUID(),
+				Namespace: "ns1",
+				Type:      MessageTypeBroadcast,
+				Author:    "0x12345",
+				Created:   Now(),
+				Topics:    FFNameArray{"topic1"},
+			},
+		}
+		data := &Data{
+			ID:        NewUUID(),
+			Namespace: "ns1",
+			Value:     Byteable(fmt.Sprintf(`{"index":%d,"description":"sample payload for message %d"}`, i, i)),
+		}
+		data.Hash = data.Value.Hash()
+		msg.Data = DataRefs{{ID: data.ID, Hash: data.Hash}}
+		batch.Payload.Messages = append(batch.Payload.Messages, msg)
+		batch.Payload.Data = append(batch.Payload.Data, data)
+	}
+	uncompressed, _ := json.Marshal(batch)
+
+	b.ResetTimer()
+	var compressed []byte
+	for i := 0; i < b.N; i++ {
+		compressed, _ = batch.Compress(zstd.SpeedDefault)
+	}
+	b.ReportMetric(float64(len(uncompressed)), "raw-bytes")
+	b.ReportMetric(float64(len(compressed)), "compressed-bytes")
+}
@@ -0,0 +1,151 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+)
+
+// HexBigInt is an arbitrary size integer, such as a gas amount, that is serialized in JSON as a 0x prefixed hex string
+type HexBigInt big.Int
+
+// NewHexBigInt wraps a big.Int for serialization
+func NewHexBigInt(i *big.Int) *HexBigInt {
+	return (*HexBigInt)(i)
+}
+
+// Int returns the underlying big.Int
+func (h *HexBigInt) Int() *big.Int {
+	return (*big.Int)(h)
+}
+
+func (h HexBigInt) String() string {
+	return "0x" + (*big.Int)(&h).Text(16)
+}
+
+func (h HexBigInt) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *HexBigInt) UnmarshalText(b []byte) error {
+	// The 0x prefix is optional on input
+	s := strings.TrimPrefix(string(b), "0x")
+	if _, ok := (*big.Int)(h).SetString(s, 16); !ok {
+		return i18n.NewError(context.Background(), i18n.MsgInvalidHex)
+	}
+	return nil
+}
+
+// Value implements sql.Valuer, storing the value in the same zero padded form as a BigInt
+func (h HexBigInt) Value() (driver.Value, error) {
+	return BigInt(h).Value()
+}
+
+// Scan implements sql.Scanner
+func (h *HexBigInt) Scan(src interface{}) error {
+	return (*BigInt)(h).Scan(src)
+}
+
+// bigIntStorageDigits is the number of hex digits a BigInt is padded to in the database - enough for a uint256
+const bigIntStorageDigits = 64
+
+// bigIntStorageOffset is added to negative values, to store them in two's complement form
+var bigIntStorageOffset = new(big.Int).Lsh(big.NewInt(1), bigIntStorageDigits*4)
+
+// BigInt is an arbitrary size integer, such as a token amount, that is serialized in JSON as a decimal string.
+// A JSON number is also accepted on input.
+//
+// In the database it is stored as a zero padded hex string with a sign prefix, so that values compare and
+// sort in numeric order regardless of size or sign. Non-negative values are prefixed with "1", and negative
+// values are prefixed with "0" and stored in two's complement form.
+type BigInt big.Int
+
+// NewBigInt returns a BigInt with an int64 value
+func NewBigInt(i int64) *BigInt {
+	return (*BigInt)(big.NewInt(i))
+}
+
+// Int returns the underlying big.Int
+func (i *BigInt) Int() *big.Int {
+	return (*big.Int)(i)
+}
+
+func (i BigInt) String() string {
+	return (*big.Int)(&i).String()
+}
+
+func (i BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+func (i *BigInt) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if _, ok := i.Int().SetString(s, 10); !ok {
+		return i18n.NewError(context.Background(), i18n.MsgInvalidBigInt, s)
+	}
+	return nil
+}
+
+// Value implements sql.Valuer
+func (i BigInt) Value() (driver.Value, error) {
+	v := (*big.Int)(&i)
+	if v.Sign() < 0 {
+		twos := new(big.Int).Add(bigIntStorageOffset, v)
+		if twos.Sign() < 0 {
+			return nil, i18n.NewError(context.Background(), i18n.MsgBigIntOutOfRange, v)
+		}
+		return fmt.Sprintf("0%0*x", bigIntStorageDigits, twos), nil
+	}
+	if v.Cmp(bigIntStorageOffset) >= 0 {
+		return nil, i18n.NewError(context.Background(), i18n.MsgBigIntOutOfRange, v)
+	}
+	return fmt.Sprintf("1%0*x", bigIntStorageDigits, v), nil
+}
+
+// Scan implements sql.Scanner
+func (i *BigInt) Scan(src interface{}) error {
+	switch tv := src.(type) {
+	case nil:
+		i.Int().SetInt64(0)
+	case int64:
+		i.Int().SetInt64(tv)
+	case string:
+		digits, negative := tv, false
+		if len(tv) == bigIntStorageDigits+1 && (tv[0] == '0' || tv[0] == '1') {
+			// Strip the sign prefix
+			digits, negative = tv[1:], tv[0] == '0'
+		}
+		if _, ok := i.Int().SetString(digits, 16); !ok {
+			return i18n.NewError(context.Background(), i18n.MsgScanFailed, src, i)
+		}
+		if negative {
+			i.Int().Sub(i.Int(), bigIntStorageOffset)
+		}
+	case []byte:
+		return i.Scan(string(tv))
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgScanFailed, src, i)
+	}
+	return nil
+}
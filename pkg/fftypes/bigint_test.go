@@ -0,0 +1,162 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexBigIntJSON(t *testing.T) {
+	gas, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	ref := TransactionRef{
+		Type:     TransactionTypeBatchPin,
+		GasLimit: NewHexBigInt(big.NewInt(21000)),
+		GasPrice: NewHexBigInt(gas),
+	}
+	b, err := json.Marshal(&ref)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"batch_pin","gasLimit":"0x5208","gasPrice":"0x18ee90ff6c373e0ee4e3f0ad2"}`, string(b))
+
+	var ref2 TransactionRef
+	err = json.Unmarshal(b, &ref2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000), ref2.GasLimit.Int().Int64())
+	assert.Equal(t, 0, gas.Cmp(ref2.GasPrice.Int()))
+}
+
+func TestHexBigIntOmitted(t *testing.T) {
+	b, err := json.Marshal(&TransactionRef{Type: TransactionTypeBatchPin})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"batch_pin"}`, string(b))
+}
+
+func TestHexBigIntUnmarshalNoPrefix(t *testing.T) {
+	var h HexBigInt
+	err := json.Unmarshal([]byte(`"ff"`), &h)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(255), h.Int().Int64())
+	assert.Equal(t, "0xff", h.String())
+}
+
+func TestHexBigIntUnmarshalBad(t *testing.T) {
+	var h HexBigInt
+	err := json.Unmarshal([]byte(`"0xzz"`), &h)
+	assert.Regexp(t, "FF10231", err)
+	err = json.Unmarshal([]byte(`12345`), &h)
+	assert.Error(t, err)
+}
+
+func TestHexBigIntDatabaseSerialization(t *testing.T) {
+	h := NewHexBigInt(big.NewInt(21000))
+	v, err := h.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "10000000000000000000000000000000000000000000000000000000000005208", v)
+
+	var h2 HexBigInt
+	err = h2.Scan(v)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x5208", h2.String())
+
+	err = h2.Scan(false)
+	assert.Regexp(t, "FF10125", err)
+}
+
+func TestBigIntJSON(t *testing.T) {
+	amount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	balance := TokenBalance{Amount: BigInt(*amount)}
+	b, err := json.Marshal(&balance)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"amount":"123456789012345678901234567890"}`, string(b))
+
+	var balance2 TokenBalance
+	err = json.Unmarshal(b, &balance2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, amount.Cmp(balance2.Amount.Int()))
+
+	var i BigInt
+	err = json.Unmarshal([]byte(`12345`), &i)
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", i.String())
+}
+
+func TestBigIntUnmarshalBad(t *testing.T) {
+	var i BigInt
+	err := json.Unmarshal([]byte(`"0x12"`), &i)
+	assert.Regexp(t, "FF10344", err)
+}
+
+func TestBigIntDatabaseSerialization(t *testing.T) {
+	v, err := NewBigInt(255).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "100000000000000000000000000000000000000000000000000000000000000ff", v)
+
+	v, err = NewBigInt(-255).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "0ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff01", v)
+
+	var i BigInt
+	assert.NoError(t, i.Scan(v))
+	assert.Equal(t, int64(-255), i.Int().Int64())
+	assert.NoError(t, i.Scan([]byte("ff")))
+	assert.Equal(t, int64(255), i.Int().Int64())
+	assert.NoError(t, i.Scan(int64(12)))
+	assert.Equal(t, int64(12), i.Int().Int64())
+	assert.NoError(t, i.Scan(nil))
+	assert.Equal(t, int64(0), i.Int().Int64())
+	assert.Regexp(t, "FF10125", i.Scan("zz"))
+	assert.Regexp(t, "FF10125", i.Scan(true))
+}
+
+func TestBigIntDatabaseSortOrder(t *testing.T) {
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	values := []*BigInt{
+		(*BigInt)(new(big.Int).Neg(new(big.Int).Add(max, big.NewInt(1)))),
+		NewBigInt(-1000000),
+		NewBigInt(-256),
+		NewBigInt(-255),
+		NewBigInt(-1),
+		NewBigInt(0),
+		NewBigInt(1),
+		NewBigInt(255),
+		NewBigInt(1000000),
+		(*BigInt)(max),
+	}
+	var previous string
+	for _, bi := range values {
+		v, err := bi.Value()
+		assert.NoError(t, err)
+		assert.Len(t, v, 65)
+		assert.Greater(t, v.(string), previous, bi.String())
+		previous = v.(string)
+
+		var i BigInt
+		assert.NoError(t, i.Scan(v))
+		assert.Equal(t, bi.String(), i.String())
+	}
+}
+
+func TestBigIntDatabaseOutOfRange(t *testing.T) {
+	tooBig := new(big.Int).Lsh(big.NewInt(1), 256)
+	_, err := (*BigInt)(tooBig).Value()
+	assert.Regexp(t, "FF10355", err)
+	_, err = (*BigInt)(tooBig.Neg(tooBig).Sub(tooBig, big.NewInt(1))).Value()
+	assert.Regexp(t, "FF10355", err)
+}
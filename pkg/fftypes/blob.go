@@ -16,10 +16,30 @@
 
 package fftypes
 
+import (
+	"crypto/sha256"
+	"hash/crc32"
+	"io"
+)
+
 type Blob struct {
-	Hash       *Bytes32 `json:"hash"`
-	PayloadRef string   `json:"payloadRef,omitempty"`
-	Peer       string   `json:"peer,omitempty"`
-	Created    *FFTime  `json:"created,omitempty"`
-	Sequence   int64    `json:"-"`
+	Hash         *Bytes32 `json:"hash"`
+	Checksum     uint32   `json:"checksum,omitempty"`
+	Unverifiable bool     `json:"unverifiable,omitempty"` // no checksum is available, such as for blobs received from peers
+	PayloadRef   string   `json:"payloadRef,omitempty"`
+	Peer         string   `json:"peer,omitempty"`
+	Created      *FFTime  `json:"created,omitempty"`
+	UploadedBy   string   `json:"uploadedBy,omitempty"` // org identity of the node the blob was uploaded to
+	Sequence     int64    `json:"-"`
+}
+
+// ComputeBlobChecksum reads the whole stream, calculating both the CRC32 (IEEE) checksum
+// and the SHA-256 hash of the content in a single pass
+func ComputeBlobChecksum(r io.Reader) (uint32, *Bytes32, error) {
+	checksumCalc := crc32.NewIEEE()
+	hashCalc := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(checksumCalc, hashCalc), r); err != nil {
+		return 0, nil, err
+	}
+	return checksumCalc.Sum32(), HashResult(hashCalc), nil
 }
@@ -0,0 +1,45 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"hash/crc32"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeBlobChecksum(t *testing.T) {
+	content := []byte("some blob content")
+	checksum, hash, err := ComputeBlobChecksum(bytes.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, crc32.ChecksumIEEE(content), checksum)
+	assert.Equal(t, [32]byte(sha256.Sum256(content)), [32]byte(*hash))
+
+	corrupted, _, err := ComputeBlobChecksum(bytes.NewReader([]byte("some blob c0ntent")))
+	assert.NoError(t, err)
+	assert.NotEqual(t, checksum, corrupted)
+}
+
+func TestComputeBlobChecksumReadFail(t *testing.T) {
+	_, _, err := ComputeBlobChecksum(iotest.ErrReader(fmt.Errorf("pop")))
+	assert.EqualError(t, err, "pop")
+}
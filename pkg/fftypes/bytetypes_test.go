@@ -22,6 +22,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -75,10 +76,10 @@ func TestScanBytes32(t *testing.T) {
 	b32 := &Bytes32{}
 
 	b32.Scan(nil)
-	assert.Equal(t, *b32, Bytes32{})
+	assert.True(t, b32.IsZero())
 
 	b32.Scan("")
-	assert.Equal(t, *b32, Bytes32{})
+	assert.True(t, b32.IsZero())
 
 	rand := NewRandB32()
 	b32.Scan(rand.String())
@@ -87,7 +88,7 @@ func TestScanBytes32(t *testing.T) {
 	b32 = &Bytes32{}
 
 	b32.Scan([]byte{})
-	assert.Equal(t, *b32, Bytes32{})
+	assert.True(t, b32.IsZero())
 
 	b32.Scan([]byte(rand.String()))
 	assert.Equal(t, b32, rand)
@@ -158,3 +159,62 @@ func TestParseBytes32(t *testing.T) {
 	_, err = ParseBytes32(context.Background(), "!!!!d907ee03ecbcfb416ce89d957682e8ef41ac548b0b571f65cb196f2b0ab4")
 	assert.Regexp(t, "FF10231", err)
 }
+
+func TestParseBytes32Flexible(t *testing.T) {
+	expected := "d907ee03ecbcfb416ce89d957682e8ef41ac548b0b571f65cb196f2b0ab4da05"
+
+	b32, err := ParseBytes32Flexible(context.Background(), "0x"+expected)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, b32.String())
+
+	b32, err = ParseBytes32Flexible(context.Background(), expected)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, b32.String())
+
+	b32, err = ParseBytes32Flexible(context.Background(), "0X"+strings.ToUpper(expected))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, b32.String())
+
+	b32, err = ParseBytes32Flexible(context.Background(), "D907EE03ecbcfb416CE89D957682e8ef41ac548b0b571f65cb196f2b0ab4DA05")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, b32.String())
+
+	_, err = ParseBytes32Flexible(context.Background(), "0x"+expected[0:62])
+	assert.Regexp(t, "FF10232", err)
+
+	_, err = ParseBytes32Flexible(context.Background(), expected+"00")
+	assert.Regexp(t, "FF10232", err)
+
+	_, err = ParseBytes32Flexible(context.Background(), "0x")
+	assert.Regexp(t, "FF10232", err)
+
+	_, err = ParseBytes32Flexible(context.Background(), "zz"+expected[2:])
+	assert.Regexp(t, "FF10231", err)
+}
+
+func TestBytes32IsZero(t *testing.T) {
+	testCases := []struct {
+		name   string
+		b32    *Bytes32
+		isZero bool
+	}{
+		{name: "nil", b32: nil, isZero: true},
+		{name: "zero", b32: &Bytes32{}, isZero: true},
+		{name: "random", b32: NewRandB32(), isZero: false},
+		{name: "last byte set", b32: &Bytes32{31: 1}, isZero: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.isZero, tc.b32.IsZero())
+		})
+	}
+}
+
+func TestMustParseBytes32(t *testing.T) {
+	rand := NewRandB32()
+	assert.Equal(t, rand, MustParseBytes32(rand.String()))
+	assert.Equal(t, rand, MustParseBytes32("0x"+rand.String()))
+	assert.Panics(t, func() {
+		MustParseBytes32("!hex")
+	})
+}
@@ -69,6 +69,36 @@ func ParseBytes32(ctx context.Context, hexStr string) (*Bytes32, error) {
 	return &b32, nil
 }
 
+// ParseBytes32Flexible parses a 64 character hex string, with or without a leading 0x (or 0X) prefix.
+// Upper, lower and mixed case hex digits are all accepted.
+func ParseBytes32Flexible(ctx context.Context, hexStr string) (*Bytes32, error) {
+	if len(hexStr) >= 2 && hexStr[0] == '0' && (hexStr[1] == 'x' || hexStr[1] == 'X') {
+		hexStr = hexStr[2:]
+	}
+	if len(hexStr) != 64 {
+		return nil, i18n.NewError(ctx, i18n.MsgInvalidWrongLenB32)
+	}
+	var b32 Bytes32
+	if _, err := hex.Decode(b32[0:32], []byte(hexStr)); err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgInvalidHex)
+	}
+	return &b32, nil
+}
+
+// MustParseBytes32 parses a hex string, panicking on failure - intended for test fixtures and constants
+func MustParseBytes32(hexStr string) *Bytes32 {
+	b32, err := ParseBytes32Flexible(context.Background(), hexStr)
+	if err != nil {
+		panic(err)
+	}
+	return b32
+}
+
+// IsZero returns true for a nil receiver, or if all bytes are zero
+func (b32 *Bytes32) IsZero() bool {
+	return b32 == nil || *b32 == Bytes32{}
+}
+
 // Scan implements sql.Scanner
 func (b32 *Bytes32) Scan(src interface{}) error {
 	switch src := src.(type) {
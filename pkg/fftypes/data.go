@@ -21,29 +21,38 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hyperledger/firefly/internal/i18n"
 )
 
 type DataRef struct {
-	ID   *UUID    `json:"id,omitempty"`
-	Hash *Bytes32 `json:"hash,omitempty"`
+	ID       *UUID    `json:"id,omitempty"`
+	Hash     *Bytes32 `json:"hash,omitempty"`
+	Optional bool     `json:"optional,omitempty"` // the message can be confirmed without this data being present
 }
 
 type BlobRef struct {
-	Hash   *Bytes32 `json:"hash"`
-	Public string   `json:"public,omitempty"`
+	Hash       *Bytes32 `json:"hash"`
+	Public     string   `json:"public,omitempty"`
+	UploadedBy string   `json:"uploadedBy,omitempty"`
 }
 
 type Data struct {
-	ID        *UUID         `json:"id,omitempty"`
-	Validator ValidatorType `json:"validator"`
-	Namespace string        `json:"namespace,omitempty"`
-	Hash      *Bytes32      `json:"hash,omitempty"`
-	Created   *FFTime       `json:"created,omitempty"`
-	Datatype  *DatatypeRef  `json:"datatype,omitempty"`
-	Value     Byteable      `json:"value"`
-	Blob      *BlobRef      `json:"blob,omitempty"`
+	ID          *UUID         `json:"id,omitempty"`
+	Validator   ValidatorType `json:"validator"`
+	Namespace   string        `json:"namespace,omitempty"`
+	Hash        *Bytes32      `json:"hash,omitempty"`
+	Created     *FFTime       `json:"created,omitempty"`
+	Datatype    *DatatypeRef  `json:"datatype,omitempty"`
+	Value       Byteable      `json:"value"`
+	Blob        *BlobRef      `json:"blob,omitempty"`
+	Version     int           `json:"version,omitempty"`
+	Previous    *UUID         `json:"previous,omitempty"`
+	CreatedFrom *UUID         `json:"createdFrom,omitempty"`
+	ExpiresAt   *FFTime       `json:"expiresAt,omitempty"`
 }
 
 type DataAndBlob struct {
@@ -71,6 +80,19 @@ func (d DataRefs) Hash() *Bytes32 {
 	return &b32
 }
 
+func (d DataRefs) hashes() []*Bytes32 {
+	hashes := make([]*Bytes32, len(d))
+	for i, ref := range d {
+		hashes[i] = ref.Hash
+	}
+	return hashes
+}
+
+// MerkleRoot calculates the Merkle root of the hashes of the data references, which must all be set
+func (d DataRefs) MerkleRoot() *Bytes32 {
+	return MerkleRoot(d.hashes())
+}
+
 func CheckValidatorType(ctx context.Context, validator ValidatorType) error {
 	switch validator {
 	case ValidatorTypeJSON, ValidatorTypeNone, ValidatorTypeSystemDefinition:
@@ -114,9 +136,48 @@ func (d *Data) Seal(ctx context.Context) (err error) {
 	if d.Created == nil {
 		d.Created = Now()
 	}
+	if d.Version == 0 {
+		d.Version = 1
+	}
 	d.Hash, err = d.CalcHash(ctx)
 	if err == nil {
 		err = CheckValidatorType(ctx, d.Validator)
 	}
 	return err
 }
+
+// SealAll seals each of the supplied data items, spreading the hashing work across a
+// pool of goroutines sized by GOMAXPROCS. The first error encountered is returned.
+func SealAll(ctx context.Context, items []*Data) error {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(items) {
+		workers = len(items)
+	}
+	var firstErr error
+	var errOnce sync.Once
+	var wg sync.WaitGroup
+	next := int64(-1)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(items) {
+					return
+				}
+				var err error
+				if items[i] == nil {
+					err = i18n.NewError(ctx, i18n.MsgNilDataItem, i)
+				} else {
+					err = items[i].Seal(ctx)
+				}
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
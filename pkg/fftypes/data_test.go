@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -112,3 +113,75 @@ func TestHashDataNull(t *testing.T) {
 	assert.Equal(t, expectedHash.String(), hash.String())
 
 }
+
+func TestSealAll(t *testing.T) {
+	items := []*Data{
+		{Value: Byteable(`{"a":1}`)},
+		{Value: Byteable(`{"b":2}`)},
+		{Value: Byteable(`{"c":3}`)},
+	}
+	err := SealAll(context.Background(), items)
+	assert.NoError(t, err)
+	for _, d := range items {
+		assert.NotNil(t, d.ID)
+		assert.Equal(t, d.Value.Hash(), d.Hash)
+		assert.Equal(t, ValidatorTypeJSON, d.Validator)
+	}
+}
+
+func TestSealAllEmpty(t *testing.T) {
+	err := SealAll(context.Background(), []*Data{})
+	assert.NoError(t, err)
+}
+
+func TestSealAllError(t *testing.T) {
+	items := []*Data{
+		{Value: Byteable(`{"a":1}`)},
+		{},
+		{Value: Byteable(`{"c":3}`)},
+	}
+	err := SealAll(context.Background(), items)
+	assert.Regexp(t, "FF10199", err)
+}
+
+func TestSealAllNilItem(t *testing.T) {
+	err := SealAll(context.Background(), []*Data{nil})
+	assert.Regexp(t, "FF10293", err)
+}
+
+func newBenchmarkSealData() []*Data {
+	value := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		value[fmt.Sprintf("field%d", i)] = fmt.Sprintf("value of field %d", i)
+	}
+	b, _ := json.Marshal(value)
+	items := make([]*Data, 100)
+	for i := range items {
+		items[i] = &Data{Value: Byteable(b)}
+	}
+	return items
+}
+
+func BenchmarkSealSequential(b *testing.B) {
+	ctx := context.Background()
+	items := newBenchmarkSealData()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, d := range items {
+			if err := d.Seal(ctx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkSealAll(b *testing.B) {
+	ctx := context.Background()
+	items := newBenchmarkSealData()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := SealAll(ctx, items); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
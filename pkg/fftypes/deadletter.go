@@ -0,0 +1,38 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+// DeadLetterEvent records an event that could not be delivered to a subscription,
+// after all delivery retries were exhausted
+type DeadLetterEvent struct {
+	ID           *UUID   `json:"id"`
+	Namespace    string  `json:"namespace"`
+	Subscription *UUID   `json:"subscription"`
+	Event        *UUID   `json:"event"`
+	Attempts     int     `json:"attempts"`
+	Created      *FFTime `json:"created"`
+}
+
+// DeliveryFailure counts the failed deliveries of an event to a durable subscription, that is
+// yet to be either delivered or dead lettered
+type DeliveryFailure struct {
+	Namespace    string  `json:"namespace"`
+	Subscription *UUID   `json:"subscription"`
+	Event        *UUID   `json:"event"`
+	Attempts     int     `json:"attempts"`
+	Updated      *FFTime `json:"updated"`
+}
@@ -23,3 +23,11 @@ type Definition interface {
 	// SetBroadcastMessage sets the message that broadcast the definition
 	SetBroadcastMessage(msgID *UUID)
 }
+
+// SignedDefinition is a definition that carries a signature over its own content, made with the key of
+// the signing identity when that key is managed outside of the node
+type SignedDefinition interface {
+	Definition
+	// SetSignature sets the signature, which is empty while the content is being signed
+	SetSignature(signature string)
+}
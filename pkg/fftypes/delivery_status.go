@@ -0,0 +1,34 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+// DeliveryStatus is the state of the delivery of a private message batch to a single node,
+// derived from the data exchange send operation for that node
+type DeliveryStatus struct {
+	NodeID    *UUID    `json:"node,omitempty"`
+	Status    OpStatus `json:"status"`
+	UpdatedAt *FFTime  `json:"updated,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// MessageDeliveryStatus is the acknowledgement status of a single message sent via data exchange,
+// as reported by the data exchange connector for the tracking ID of the send
+type MessageDeliveryStatus struct {
+	Status     OpStatus `json:"status"`
+	ReceivedAt *FFTime  `json:"receivedAt,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
@@ -0,0 +1,58 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+const (
+	// DIDMethodPrefix is the prefix of the DIDs assigned to organization identities
+	DIDMethodPrefix = "did:firefly:"
+	// DIDContextV1 is the JSON-LD context of a W3C DID Core document
+	DIDContextV1 = "https://www.w3.org/ns/did/v1"
+	// DIDVerificationMethodBlockchainAccount is the verification method type for an on-chain account
+	DIDVerificationMethodBlockchainAccount = "BlockchainVerificationMethod2021"
+	// DIDServiceDataExchange is the service type for the data exchange endpoint of a node
+	DIDServiceDataExchange = "FireFlyDataExchange"
+)
+
+// DIDDocument is a W3C DID Core document describing an organization identity
+type DIDDocument struct {
+	Context            []string                 `json:"@context"`
+	ID                 string                   `json:"id"`
+	Controller         string                   `json:"controller,omitempty"`
+	VerificationMethod []*DIDVerificationMethod `json:"verificationMethod"`
+	Authentication     []string                 `json:"authentication"`
+	Service            []*DIDService            `json:"service"`
+}
+
+// DIDVerificationMethod binds the DID to the on-chain account of the organization
+type DIDVerificationMethod struct {
+	ID                  string `json:"id"`
+	Type                string `json:"type"`
+	Controller          string `json:"controller"`
+	BlockchainAccountID string `json:"blockchainAccountId"`
+}
+
+// DIDService is an endpoint through which the organization can be reached
+type DIDService struct {
+	ID              string     `json:"id"`
+	Type            string     `json:"type"`
+	ServiceEndpoint JSONObject `json:"serviceEndpoint"`
+}
+
+// OrgDID returns the DID for an organization identity
+func OrgDID(identity string) string {
+	return DIDMethodPrefix + identity
+}
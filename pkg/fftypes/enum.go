@@ -17,8 +17,11 @@
 package fftypes
 
 import (
+	"context"
 	"database/sql/driver"
 	"strings"
+
+	"github.com/hyperledger/firefly/internal/i18n"
 )
 
 type FFEnum string
@@ -34,6 +37,16 @@ func FFEnumValues(t string) []interface{} {
 	return enumValues[t]
 }
 
+// FFEnumValid checks the value is one of the registered values for the enum type
+func FFEnumValid(ctx context.Context, t string, val FFEnum) error {
+	for _, v := range enumValues[t] {
+		if v.(string) == val.String() {
+			return nil
+		}
+	}
+	return i18n.NewError(ctx, i18n.MsgInvalidEnumValue, val, t)
+}
+
 func (ts FFEnum) String() string {
 	return strings.ToLower(string(ts))
 }
@@ -17,6 +17,7 @@
 package fftypes
 
 import (
+	"context"
 	"database/sql/driver"
 	"encoding/json"
 	"testing"
@@ -49,3 +50,9 @@ func TestFFEnumValues(t *testing.T) {
 	assert.Equal(t, FFEnum("test2"), ffEnum("ut", "test2"))
 	assert.Equal(t, []interface{}{"test1", "test2"}, FFEnumValues("ut"))
 }
+
+func TestFFEnumValid(t *testing.T) {
+	assert.NoError(t, FFEnumValid(context.Background(), "txtype", TransactionTypeTokenMint))
+	assert.NoError(t, FFEnumValid(context.Background(), "txtype", "Token_Burn"))
+	assert.Regexp(t, "FF10277.*wrong.*txtype", FFEnumValid(context.Background(), "txtype", "wrong"))
+}
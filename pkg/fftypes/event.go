@@ -35,6 +35,8 @@ var (
 	EventTypePoolConfirmed EventType = ffEnum("eventtype", "token_pool_confirmed")
 	// EventTypePoolRejected occurs when a new token pool is rejected (due to validation errors, duplicates, etc)
 	EventTypePoolRejected EventType = ffEnum("eventtype", "token_pool_rejected")
+	// EventTypeHeartbeatMissed occurs when a node in one of our groups did not reply to a heartbeat within twice the heartbeat interval (the reference is the node)
+	EventTypeHeartbeatMissed EventType = ffEnum("eventtype", "heartbeat_missed")
 )
 
 // Event is an activity in the system, delivered reliably to applications, that indicates something has happened in the network
@@ -19,18 +19,25 @@ package fftypes
 import (
 	"context"
 	"crypto/sha256"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/hyperledger/firefly/internal/i18n"
 )
 
 type GroupIdentity struct {
-	Ledger    *UUID   `json:"ledger,omitempty"`
-	Namespace string  `json:"namespace,omitempty"`
-	Name      string  `json:"name"`
-	Members   Members `json:"members"`
+	Ledger      *UUID        `json:"ledger,omitempty"`
+	Namespace   string       `json:"namespace,omitempty"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Members     Members      `json:"members"`
+	ACL         GroupACL     `json:"acl,omitempty"`      // org identities allowed to re-initialize the group
+	Previous    *Bytes32     `json:"previous,omitempty"` // hash of the group this group re-initializes, if any
+	Nonce       uint64       `json:"nonce,omitempty"`    // must be greater than the nonce of the previous group
+	Schema      *DatatypeRef `json:"schema,omitempty"`   // datatype all private data sent to the group must conform to
 }
 
 type Group struct {
@@ -40,8 +47,60 @@ type Group struct {
 	Created *FFTime  `json:"created,omitempty"`
 }
 
+// GroupRef is a reference to a private group, by its hash
+type GroupRef struct {
+	Hash *Bytes32 `json:"hash"`
+}
+
+// GroupRefs is a list of references to private groups, stored as JSON
+type GroupRefs []GroupRef
+
+// Scan implements sql.Scanner
+func (gr *GroupRefs) Scan(src interface{}) error {
+	switch st := src.(type) {
+	case string:
+		return gr.Scan([]byte(st))
+	case []byte:
+		if len(st) == 0 {
+			return nil
+		}
+		return json.Unmarshal(st, gr)
+	case nil:
+		return nil
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgScanFailed, src, gr)
+	}
+}
+
+// Value implements sql.Valuer
+func (gr GroupRefs) Value() (driver.Value, error) {
+	if len(gr) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(gr)
+}
+
+// String returns the hashes of the groups, for use as a key
+func (gr GroupRefs) String() string {
+	hashes := make([]string, len(gr))
+	for i, g := range gr {
+		hashes[i] = g.Hash.String()
+	}
+	return strings.Join(hashes, ",")
+}
+
 type Members []*Member
 
+// Contains returns true if the identity is one of the members
+func (m Members) Contains(identity string) bool {
+	for _, member := range m {
+		if member.Identity == identity {
+			return true
+		}
+	}
+	return false
+}
+
 func (m Members) Len() int           { return len(m) }
 func (m Members) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
 func (m Members) Less(i, j int) bool { return m[i].Identity < m[j].Identity } // Note there's a dupcheck in validate
@@ -49,15 +108,26 @@ func (m Members) Less(i, j int) bool { return m[i].Identity < m[j].Identity } //
 type Member struct {
 	Identity string `json:"identity,omitempty"`
 	Node     *UUID  `json:"node,omitempty"`
+	Alias    string `json:"alias,omitempty"`
 }
 
 type MemberInput struct {
 	Identity string `json:"identity,omitempty"`
 	Node     string `json:"node,omitempty"`
+	Alias    string `json:"alias,omitempty"`
 }
 
+// Hash excludes the member aliases, which are only display names, so assigning aliases
+// does not change the identity of the group
 func (man *GroupIdentity) Hash() *Bytes32 {
-	b, _ := json.Marshal(&man)
+	hashed := *man
+	hashed.Members = make(Members, len(man.Members))
+	for i, m := range man.Members {
+		if m != nil {
+			hashed.Members[i] = &Member{Identity: m.Identity, Node: m.Node}
+		}
+	}
+	b, _ := json.Marshal(&hashed)
 	hash := Bytes32(sha256.Sum256(b))
 	return &hash
 }
@@ -72,10 +142,14 @@ func (group *Group) Validate(ctx context.Context, existing bool) (err error) {
 			return err
 		}
 	}
+	if err = ValidateLength(ctx, group.Description, "description", 4096); err != nil {
+		return err
+	}
 	if len(group.Members) == 0 {
 		return i18n.NewError(ctx, i18n.MsgGroupMustHaveMembers)
 	}
 	dupCheck := make(map[string]bool)
+	aliasCheck := make(map[string]bool)
 	for i, r := range group.Members {
 		if r.Identity == "" {
 			return i18n.NewError(ctx, i18n.MsgEmptyMemberIdentity, i)
@@ -91,6 +165,18 @@ func (group *Group) Validate(ctx context.Context, existing bool) (err error) {
 			return i18n.NewError(ctx, i18n.MsgDuplicateMember, i)
 		}
 		dupCheck[key] = true
+		if r.Alias != "" {
+			if err = ValidateLength(ctx, r.Alias, "alias", 128); err != nil {
+				return err
+			}
+			if aliasCheck[r.Alias] {
+				return i18n.NewError(ctx, i18n.MsgDuplicateMemberAlias, i, r.Alias)
+			}
+			aliasCheck[r.Alias] = true
+		}
+	}
+	if err = group.ACL.Validate(ctx, "acl"); err != nil {
+		return err
 	}
 	if existing {
 		hash := group.GroupIdentity.Hash()
@@ -101,11 +187,29 @@ func (group *Group) Validate(ctx context.Context, existing bool) (err error) {
 	return nil
 }
 
+// ValidateSize checks the group does not have more than the maximum number of members.
+// A max of zero or less means there is no limit.
+func (group *Group) ValidateSize(ctx context.Context, max int) error {
+	if max > 0 && len(group.Members) > max {
+		return i18n.NewError(ctx, i18n.MsgGroupTooLarge, group.Name, max)
+	}
+	return nil
+}
+
 func (group *Group) Seal() {
 	sort.Sort(group.Members)
 	group.Hash = group.GroupIdentity.Hash()
 }
 
+// AllowsReinit returns true if the identity is permitted to re-initialize the group. That is an identity in
+// its ACL or, for a group without an ACL, one of its members.
+func (group *Group) AllowsReinit(identity string) bool {
+	if len(group.ACL) > 0 {
+		return group.ACL.Contains(identity)
+	}
+	return group.Members.Contains(identity)
+}
+
 func (group *Group) Topic() string {
 	return group.Hash.String()
 }
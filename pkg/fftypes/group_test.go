@@ -112,6 +112,25 @@ func TestGroupValidation(t *testing.T) {
 	}
 	assert.Regexp(t, "FF10222", group.Validate(context.Background(), false))
 
+	group.Members = Members{
+		{Node: nodeID, Identity: "0x12345", Alias: "alice"},
+		{Node: nodeID, Identity: "0x23456", Alias: "alice"},
+	}
+	assert.Regexp(t, "FF10301.*alice", group.Validate(context.Background(), false))
+
+	group.Members = Members{
+		{Node: nodeID, Identity: "0x12345", Alias: string(make([]byte, 129))},
+	}
+	assert.Regexp(t, "FF10188.*alias", group.Validate(context.Background(), false))
+
+	group.Members = Members{
+		{Node: nodeID, Identity: "0x12345", Alias: "alice"},
+		{Node: nodeID, Identity: "0x23456", Alias: "bob"},
+		{Node: nodeID, Identity: "0x34567"},
+		{Node: nodeID, Identity: "0x45678"},
+	}
+	assert.NoError(t, group.Validate(context.Background(), false))
+
 	group.Members = Members{
 		{Node: nodeID, Identity: "0x12345"},
 	}
@@ -126,6 +145,21 @@ func TestGroupValidation(t *testing.T) {
 	assert.NotNil(t, group.Message)
 }
 
+func TestGroupValidateSize(t *testing.T) {
+	group := &Group{
+		GroupIdentity: GroupIdentity{
+			Name: "group1",
+			Members: Members{
+				{Identity: "org1", Node: NewUUID()},
+				{Identity: "org2", Node: NewUUID()},
+			},
+		},
+	}
+	assert.NoError(t, group.ValidateSize(context.Background(), 2))
+	assert.NoError(t, group.ValidateSize(context.Background(), 0))
+	assert.Regexp(t, "FF10280.*group1.*1", group.ValidateSize(context.Background(), 1))
+}
+
 func TestGroupSealSorting(t *testing.T) {
 
 	m1 := &Member{Node: NewUUID(), Identity: "0x11111"}
@@ -154,3 +188,129 @@ func TestGroupSealSorting(t *testing.T) {
 	assert.Equal(t, *group1.Hash, *group2.Hash)
 
 }
+
+func TestGroupValidateDescription(t *testing.T) {
+	group := &Group{
+		GroupIdentity: GroupIdentity{
+			Namespace:   "ns1",
+			Name:        "group1",
+			Description: string(make([]byte, 4096)),
+			Members: Members{
+				{Identity: "org1", Node: NewUUID()},
+			},
+		},
+	}
+	assert.NoError(t, group.Validate(context.Background(), false))
+
+	group.Description = string(make([]byte, 4097))
+	assert.Regexp(t, "FF10188.*description", group.Validate(context.Background(), false))
+}
+
+func TestGroupDescriptionInHash(t *testing.T) {
+	group := &Group{
+		GroupIdentity: GroupIdentity{
+			Namespace: "ns1",
+			Name:      "group1",
+			Members: Members{
+				{Identity: "org1", Node: MustParseUUID("8b5c0d39-925f-4579-9c60-54f3e846ab99")},
+			},
+		},
+	}
+	group.Seal()
+	noDescHash := group.Hash
+
+	group.Description = "Supply chain partners"
+	group.Seal()
+	assert.NotEqual(t, noDescHash, group.Hash)
+}
+
+func TestGroupACLInHash(t *testing.T) {
+	group := &Group{
+		GroupIdentity: GroupIdentity{
+			Namespace: "ns1",
+			Name:      "group1",
+			Members: Members{
+				{Identity: "org1", Node: MustParseUUID("8b5c0d39-925f-4579-9c60-54f3e846ab99")},
+			},
+		},
+	}
+	group.Seal()
+	noACLHash := group.Hash
+
+	group.ACL = GroupACL{"org1"}
+	group.Seal()
+	assert.NotEqual(t, noACLHash, group.Hash)
+	assert.NoError(t, group.Validate(context.Background(), true))
+
+	group.ACL = GroupACL{"org1", "org1"}
+	assert.Regexp(t, "FF10228.*acl", group.Validate(context.Background(), false))
+}
+
+func TestGroupSchemaInHash(t *testing.T) {
+	group := &Group{
+		GroupIdentity: GroupIdentity{
+			Namespace: "ns1",
+			Name:      "group1",
+			Members: Members{
+				{Identity: "org1", Node: MustParseUUID("8b5c0d39-925f-4579-9c60-54f3e846ab99")},
+			},
+		},
+	}
+	group.Seal()
+	noSchemaHash := group.Hash
+
+	group.Schema = &DatatypeRef{Name: "customer", Version: "0.0.1"}
+	group.Seal()
+	assert.NotEqual(t, noSchemaHash, group.Hash)
+	assert.NoError(t, group.Validate(context.Background(), true))
+
+	// The schema cannot be changed without changing the identity of the group
+	group.Schema.Version = "0.0.2"
+	assert.Regexp(t, "FF10230", group.Validate(context.Background(), true))
+}
+
+func TestGroupAliasNotInHash(t *testing.T) {
+	group := &Group{
+		GroupIdentity: GroupIdentity{
+			Namespace: "ns1",
+			Name:      "group1",
+			Members: Members{
+				{Identity: "org1", Node: MustParseUUID("8b5c0d39-925f-4579-9c60-54f3e846ab99")},
+			},
+		},
+	}
+	group.Seal()
+	noAliasHash := group.Hash
+
+	group.Members[0].Alias = "Supplier"
+	group.Seal()
+	assert.Equal(t, noAliasHash, group.Hash)
+	assert.Equal(t, "Supplier", group.Members[0].Alias)
+	assert.NoError(t, group.Validate(context.Background(), true))
+}
+
+func TestGroupRefsDatabaseSerialization(t *testing.T) {
+	hash := MustParseBytes32("0000000000000000000000000000000000000000000000000000000000000001")
+	gr := GroupRefs{{Hash: hash}}
+	v, err := gr.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"hash":"0000000000000000000000000000000000000000000000000000000000000001"}]`, string(v.([]byte)))
+	assert.Equal(t, hash.String(), gr.String())
+
+	v, err = GroupRefs(nil).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	var gr2 GroupRefs
+	assert.NoError(t, gr2.Scan(`[{"hash":"0000000000000000000000000000000000000000000000000000000000000001"}]`))
+	assert.Equal(t, gr, gr2)
+
+	var gr3 GroupRefs
+	assert.NoError(t, gr3.Scan([]byte{}))
+	assert.Nil(t, gr3)
+	assert.NoError(t, gr3.Scan(nil))
+	assert.Nil(t, gr3)
+
+	assert.Regexp(t, "FF10125", gr3.Scan(12345))
+	assert.Error(t, gr3.Scan("!json"))
+}
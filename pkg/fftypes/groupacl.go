@@ -0,0 +1,92 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+)
+
+const maxGroupACLEntries = 100
+
+// GroupACL is the list of org identities that are allowed to re-initialize a group.
+// A group with an empty ACL can be re-initialized by any of its members.
+type GroupACL []string
+
+func (acl GroupACL) Value() (driver.Value, error) {
+	if len(acl) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(acl)
+}
+
+func (acl *GroupACL) Scan(src interface{}) error {
+	switch st := src.(type) {
+	case string:
+		return acl.Scan([]byte(st))
+	case []byte:
+		if len(st) == 0 {
+			return nil
+		}
+		return json.Unmarshal(st, acl)
+	case nil:
+		return nil
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgScanFailed, src, acl)
+	}
+}
+
+func (acl GroupACL) Validate(ctx context.Context, fieldName string) error {
+	if len(acl) > maxGroupACLEntries {
+		return i18n.NewError(ctx, i18n.MsgTooManyItems, fieldName, maxGroupACLEntries, len(acl))
+	}
+	dupCheck := make(map[string]bool)
+	for i, identity := range acl {
+		if err := ValidateLength(ctx, identity, fmt.Sprintf("%s[%d]", fieldName, i), 1024); err != nil {
+			return err
+		}
+		if identity == "" {
+			return i18n.NewError(ctx, i18n.MsgEmptyMemberIdentity, i)
+		}
+		if dupCheck[identity] {
+			return i18n.NewError(ctx, i18n.MsgDuplicateArrayEntry, fieldName, i, identity)
+		}
+		dupCheck[identity] = true
+	}
+	return nil
+}
+
+// Contains returns true if the identity is explicitly listed in the ACL
+func (acl GroupACL) Contains(identity string) bool {
+	for _, entry := range acl {
+		if entry == identity {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupACLMatch returns the fragment of the stored JSON of an ACL that is present
+// when the ACL contains the identity
+func GroupACLMatch(identity string) string {
+	b, _ := json.Marshal(identity)
+	return string(b)
+}
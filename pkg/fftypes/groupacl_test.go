@@ -0,0 +1,83 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupACLValidate(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, GroupACL(nil).Validate(ctx, "acl"))
+	assert.NoError(t, GroupACL{"org1", "org2"}.Validate(ctx, "acl"))
+
+	tooMany := GroupACL{}
+	for i := 0; i < 101; i++ {
+		tooMany = append(tooMany, fmt.Sprintf("org%d", i))
+	}
+	assert.Regexp(t, "FF10227", tooMany.Validate(ctx, "acl"))
+	assert.Regexp(t, "FF10188.*acl\\[0\\]", GroupACL{strings.Repeat("o", 1025)}.Validate(ctx, "acl"))
+	assert.Regexp(t, "FF10220", GroupACL{""}.Validate(ctx, "acl"))
+	assert.Regexp(t, "FF10228", GroupACL{"org1", "org1"}.Validate(ctx, "acl"))
+}
+
+func TestGroupAllowsReinit(t *testing.T) {
+	group := &Group{GroupIdentity: GroupIdentity{
+		Members: Members{{Identity: "org1"}, {Identity: "org2"}},
+	}}
+	assert.True(t, group.AllowsReinit("org1"))
+	assert.False(t, group.AllowsReinit("org3"))
+	group.ACL = GroupACL{"org3"}
+	assert.True(t, group.AllowsReinit("org3"))
+	assert.False(t, group.AllowsReinit("org1"))
+	assert.False(t, GroupACL(nil).Contains("org1"))
+}
+
+func TestGroupACLDatabaseSerialization(t *testing.T) {
+	acl := GroupACL{"org1"}
+	v, err := acl.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `["org1"]`, string(v.([]byte)))
+
+	v, err = GroupACL{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	var acl2 GroupACL
+	assert.NoError(t, acl2.Scan(`["org1"]`))
+	assert.Equal(t, acl, acl2)
+
+	var acl3 GroupACL
+	assert.NoError(t, acl3.Scan([]byte{}))
+	assert.Nil(t, acl3)
+	assert.NoError(t, acl3.Scan(nil))
+	assert.Nil(t, acl3)
+
+	assert.Regexp(t, "FF10125", acl3.Scan(12345))
+	assert.Error(t, acl3.Scan("!json"))
+}
+
+func TestGroupACLMatch(t *testing.T) {
+	assert.Equal(t, `"org1"`, GroupACLMatch("org1"))
+	assert.Equal(t, `"did:firefly:org/\"quoted\""`, GroupACLMatch(`did:firefly:org/"quoted"`))
+}
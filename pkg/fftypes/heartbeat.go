@@ -0,0 +1,37 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+// HeartbeatStatus is the liveness of a node, as seen from the replies to the heartbeats we send it
+type HeartbeatStatus = FFEnum
+
+var (
+	// HeartbeatStatusUnknown is when no reply has been received from the node yet
+	HeartbeatStatusUnknown HeartbeatStatus = ffEnum("heartbeatstatus", "unknown")
+	// HeartbeatStatusOK is when the node replied to the most recent heartbeats in time
+	HeartbeatStatusOK HeartbeatStatus = ffEnum("heartbeatstatus", "ok")
+	// HeartbeatStatusMissed is when the node has not replied within twice the heartbeat interval
+	HeartbeatStatusMissed HeartbeatStatus = ffEnum("heartbeatstatus", "missed")
+)
+
+// NodeHeartbeat is the heartbeat status of another node in the network
+type NodeHeartbeat struct {
+	Node          *UUID           `json:"node"`
+	Status        HeartbeatStatus `json:"status" ffenum:"heartbeatstatus"`
+	LastSent      *FFTime         `json:"lastSent,omitempty"`
+	LastHeartbeat *FFTime         `json:"lastHeartbeat,omitempty"` // The last time a heartbeat reply was received from the node
+}
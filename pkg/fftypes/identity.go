@@ -16,10 +16,41 @@
 
 package fftypes
 
+import (
+	"context"
+	"strings"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+)
+
+const (
+	// KeyTypeSecp256k1 is the default key type, used by Ethereum and other EVM based chains
+	KeyTypeSecp256k1 = "secp256k1"
+	// KeyTypeEd25519 is an Edwards-curve signing key
+	KeyTypeEd25519 = "ed25519"
+	// KeyTypeECDSAP256 is an ECDSA signing key on the NIST P-256 curve
+	KeyTypeECDSAP256 = "ecdsa-p256"
+)
+
+var keyTypes = []string{KeyTypeSecp256k1, KeyTypeEd25519, KeyTypeECDSAP256}
+
 // Identity is a structure used to keep track of and map identity in the system.
 //
 // TODO: Mapping of more sophisticate identities (DIDs etc.) via plugins, and richer interface
 type Identity struct {
-	Identifier string `json:"identifier,omitempty"`
-	OnChain    string `json:"onchain,omitempty"`
+	Identifier string     `json:"identifier,omitempty"`
+	OnChain    string     `json:"onchain,omitempty"`
+	KeyType    string     `json:"keyType,omitempty"`
+	Metadata   JSONObject `json:"metadata,omitempty"`
+}
+
+// ValidateKeyType checks the key type is one of the signing algorithms known to FireFly.
+// Whether a given key type can be used is then down to the blockchain plugin.
+func ValidateKeyType(ctx context.Context, keyType string) error {
+	for _, kt := range keyTypes {
+		if keyType == kt {
+			return nil
+		}
+	}
+	return i18n.NewError(ctx, i18n.MsgInvalidKeyType, keyType, strings.Join(keyTypes, ","))
 }
@@ -0,0 +1,34 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateKeyType(t *testing.T) {
+	for _, kt := range []string{KeyTypeSecp256k1, KeyTypeEd25519, KeyTypeECDSAP256} {
+		assert.NoError(t, ValidateKeyType(context.Background(), kt))
+	}
+	for _, kt := range []string{"", "rsa", "ED25519"} {
+		err := ValidateKeyType(context.Background(), kt)
+		assert.Regexp(t, "FF10339.*secp256k1,ed25519,ecdsa-p256", err)
+	}
+}
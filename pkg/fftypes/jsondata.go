@@ -21,6 +21,7 @@ import (
 	"crypto/sha256"
 	"database/sql/driver"
 	"encoding/json"
+	"math"
 	"strconv"
 	"strings"
 
@@ -66,6 +67,56 @@ func (jd JSONObject) GetBool(key string) bool {
 	}
 }
 
+// GetInt64 returns the integer value of a key, or zero if the key is missing or not numeric
+func (jd JSONObject) GetInt64(key string) int64 {
+	i, _ := jd.GetInt64Ok(key)
+	return i
+}
+
+// GetInt64Ok returns the integer value of a key, accepting JSON numbers (with any fraction
+// truncated) and base 10 strings - as large numbers are often passed as strings in JSON
+func (jd JSONObject) GetInt64Ok(key string) (int64, bool) {
+	vInterface := jd[key]
+	switch vt := vInterface.(type) {
+	case float64:
+		if vt < math.MinInt64 || vt >= math.MaxInt64 {
+			log.L(context.Background()).Errorf("Integer value '%+v' out of range for key '%s'", vInterface, key)
+			return 0, false
+		}
+		return int64(vt), true
+	case int64:
+		return vt, true
+	case int:
+		return int64(vt), true
+	case string:
+		i, err := strconv.ParseInt(vt, 10, 64)
+		if err != nil {
+			log.L(context.Background()).Errorf("Invalid integer value '%+v' for key '%s'", vInterface, key)
+			return 0, false
+		}
+		return i, true
+	case nil:
+		return 0, false // no need to log for nil
+	default:
+		log.L(context.Background()).Errorf("Invalid integer value '%+v' for key '%s'", vInterface, key)
+		return 0, false
+	}
+}
+
+// GetUint64 returns the unsigned integer value of a key, or zero if the key is missing, not numeric, or negative
+func (jd JSONObject) GetUint64(key string) uint64 {
+	if s, ok := jd[key].(string); ok {
+		// Parse strings directly, to support the full unsigned range
+		u, _ := strconv.ParseUint(s, 10, 64)
+		return u
+	}
+	i, ok := jd.GetInt64Ok(key)
+	if !ok || i < 0 {
+		return 0
+	}
+	return uint64(i)
+}
+
 func (jd JSONObject) GetStringOk(key string) (string, bool) {
 	vInterface := jd[key]
 	switch vt := vInterface.(type) {
@@ -105,6 +156,58 @@ func (jd JSONObject) GetObjectOk(key string) (JSONObject, bool) {
 	return JSONObject{}, false // Ensures a non-nil return
 }
 
+// Flatten returns a single level map, with the keys of nested objects joined using the supplied separator.
+// For example {"transport": {"url": "..."}} becomes {"transport.url": "..."} with a "." separator.
+// Arrays and empty objects are treated as leaf values.
+func (jd JSONObject) Flatten(sep string) map[string]interface{} {
+	flat := make(map[string]interface{})
+	jd.flattenInto(flat, "", sep)
+	return flat
+}
+
+func (jd JSONObject) flattenInto(flat map[string]interface{}, prefix, sep string) {
+	for k, v := range jd {
+		key := k
+		if prefix != "" {
+			key = prefix + sep + k
+		}
+		var nested JSONObject
+		switch vt := v.(type) {
+		case map[string]interface{}:
+			nested = vt
+		case JSONObject:
+			nested = vt
+		}
+		if len(nested) > 0 {
+			nested.flattenInto(flat, key, sep)
+		} else {
+			flat[key] = v
+		}
+	}
+}
+
+// Unflatten is the inverse of Flatten, building nested objects by splitting each key on the separator
+func Unflatten(flat map[string]interface{}, sep string) JSONObject {
+	jd := JSONObject{}
+	for k, v := range flat {
+		path := []string{k}
+		if sep != "" {
+			path = strings.Split(k, sep)
+		}
+		parent := jd
+		for _, p := range path[:len(path)-1] {
+			child, ok := parent[p].(JSONObject)
+			if !ok {
+				child = JSONObject{}
+				parent[p] = child
+			}
+			parent = child
+		}
+		parent[path[len(path)-1]] = v
+	}
+	return jd
+}
+
 func ToJSONObjectArray(unknown interface{}) (JSONObjectArray, bool) {
 	vMap, ok := unknown.([]interface{})
 	joa := make(JSONObjectArray, len(vMap))
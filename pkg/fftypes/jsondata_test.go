@@ -149,6 +149,61 @@ func TestJSONObjectBool(t *testing.T) {
 
 }
 
+func TestJSONObjectGetInt64(t *testing.T) {
+
+	var data JSONObject
+	err := json.Unmarshal([]byte(`{
+		"int": 12345,
+		"float": 12.9,
+		"negative": -10,
+		"negativeFloat": -10.9,
+		"string": "67890",
+		"negativeString": "-5",
+		"badString": "abc",
+		"floatString": "1.5",
+		"bool": true,
+		"object": {},
+		"null": null,
+		"huge": 1e100,
+		"maxUint": "18446744073709551615"
+	}`), &data)
+	assert.NoError(t, err)
+	data["goInt"] = 42
+	data["goInt64"] = int64(-42)
+
+	testCases := []struct {
+		key    string
+		i64    int64
+		ok     bool
+		uint64 uint64
+	}{
+		{key: "int", i64: 12345, ok: true, uint64: 12345},
+		{key: "float", i64: 12, ok: true, uint64: 12},
+		{key: "negative", i64: -10, ok: true, uint64: 0},
+		{key: "negativeFloat", i64: -10, ok: true, uint64: 0},
+		{key: "string", i64: 67890, ok: true, uint64: 67890},
+		{key: "negativeString", i64: -5, ok: true, uint64: 0},
+		{key: "badString", i64: 0, ok: false, uint64: 0},
+		{key: "floatString", i64: 0, ok: false, uint64: 0},
+		{key: "bool", i64: 0, ok: false, uint64: 0},
+		{key: "object", i64: 0, ok: false, uint64: 0},
+		{key: "null", i64: 0, ok: false, uint64: 0},
+		{key: "missing", i64: 0, ok: false, uint64: 0},
+		{key: "huge", i64: 0, ok: false, uint64: 0},
+		{key: "maxUint", i64: 0, ok: false, uint64: 18446744073709551615},
+		{key: "goInt", i64: 42, ok: true, uint64: 42},
+		{key: "goInt64", i64: -42, ok: true, uint64: 0},
+	}
+	for _, tc := range testCases {
+		i, ok := data.GetInt64Ok(tc.key)
+		assert.Equal(t, tc.i64, i, tc.key)
+		assert.Equal(t, tc.ok, ok, tc.key)
+		assert.Equal(t, tc.i64, data.GetInt64(tc.key), tc.key)
+		assert.Equal(t, tc.uint64, data.GetUint64(tc.key), tc.key)
+	}
+
+}
+
 func TestJSONNestedSafeGet(t *testing.T) {
 
 	var jd JSONObject
@@ -218,3 +273,58 @@ func TestJSONNestedSafeGet(t *testing.T) {
 	)
 
 }
+
+func TestJSONObjectFlattenUnflatten(t *testing.T) {
+
+	var jd JSONObject
+	err := json.Unmarshal([]byte(`{
+		"transport": {
+			"url": "http://localhost:12345",
+			"tls": {
+				"enabled": true,
+				"ca": {
+					"file": "ca.pem"
+				}
+			},
+			"headers": {}
+		},
+		"names": ["one", "two"],
+		"objects": [{"nested": "not flattened"}],
+		"count": 5,
+		"nothing": null
+	}`), &jd)
+	assert.NoError(t, err)
+
+	flat := jd.Flatten(".")
+	assert.Equal(t, map[string]interface{}{
+		"transport.url":         "http://localhost:12345",
+		"transport.tls.enabled": true,
+		"transport.tls.ca.file": "ca.pem",
+		"transport.headers":     map[string]interface{}{},
+		"names":                 []interface{}{"one", "two"},
+		"objects":               []interface{}{map[string]interface{}{"nested": "not flattened"}},
+		"count":                 float64(5),
+		"nothing":               nil,
+	}, flat)
+
+	unflat := Unflatten(flat, ".")
+	assert.Equal(t, jd.String(), unflat.String())
+
+	flat = JSONObject{"a": JSONObject{"b": "c"}}.Flatten("/")
+	assert.Equal(t, map[string]interface{}{"a/b": "c"}, flat)
+	assert.Equal(t, "c", Unflatten(flat, "/").GetObject("a").GetString("b"))
+}
+
+func TestJSONObjectFlattenNil(t *testing.T) {
+	var jd JSONObject
+	assert.Empty(t, jd.Flatten("."))
+	assert.Empty(t, Unflatten(nil, "."))
+	assert.Equal(t, JSONObject{"a.b": "c"}, Unflatten(map[string]interface{}{"a.b": "c"}, ""))
+}
+
+func TestUnflattenReplacesLeafWithObject(t *testing.T) {
+	jd := Unflatten(map[string]interface{}{"a": "leaf"}, ".")
+	assert.Equal(t, "leaf", jd.GetString("a"))
+	jd = Unflatten(map[string]interface{}{"a.b.c": 1, "a.b.d": 2}, ".")
+	assert.Equal(t, `{"a":{"b":{"c":1,"d":2}}}`, jd.String())
+}
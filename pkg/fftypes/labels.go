@@ -0,0 +1,74 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/firefly/internal/i18n"
+)
+
+const (
+	maxLabels           = 50
+	maxLabelKeyLength   = 64
+	maxLabelValueLength = 256
+)
+
+// Labels are arbitrary key-value pairs attached to a message, allowing it to be
+// tagged in multiple dimensions (unlike the single tag in the header)
+type Labels map[string]string
+
+func (l Labels) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+func (l *Labels) Scan(src interface{}) error {
+	switch st := src.(type) {
+	case string:
+		return l.Scan([]byte(st))
+	case []byte:
+		if len(st) == 0 {
+			return nil
+		}
+		return json.Unmarshal(st, l)
+	case nil:
+		return nil
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgScanFailed, src, l)
+	}
+}
+
+func (l Labels) Validate(ctx context.Context, fieldName string) error {
+	if len(l) > maxLabels {
+		return i18n.NewError(ctx, i18n.MsgTooManyItems, fieldName, maxLabels, len(l))
+	}
+	for k, v := range l {
+		if err := ValidateLength(ctx, k, fmt.Sprintf("%s key '%s'", fieldName, k), maxLabelKeyLength); err != nil {
+			return err
+		}
+		if err := ValidateLength(ctx, v, fmt.Sprintf("%s.%s", fieldName, k), maxLabelValueLength); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelsValidate(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, Labels(nil).Validate(ctx, "labels"))
+	assert.NoError(t, Labels{"department": "finance", "region": "eu"}.Validate(ctx, "labels"))
+
+	tooMany := Labels{}
+	for i := 0; i < 51; i++ {
+		tooMany[fmt.Sprintf("key%d", i)] = "value"
+	}
+	err := tooMany.Validate(ctx, "labels")
+	assert.Regexp(t, "FF10227", err)
+
+	err = Labels{strings.Repeat("k", 65): "value"}.Validate(ctx, "labels")
+	assert.Regexp(t, "FF10188", err)
+
+	err = Labels{"key": strings.Repeat("v", 257)}.Validate(ctx, "labels")
+	assert.Regexp(t, "FF10188.*labels.key", err)
+
+	assert.NoError(t, Labels{strings.Repeat("k", 64): strings.Repeat("v", 256)}.Validate(ctx, "labels"))
+}
+
+func TestLabelsDatabaseSerialization(t *testing.T) {
+	l := Labels{"department": "finance"}
+	v, err := l.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"department":"finance"}`, string(v.([]byte)))
+
+	v, err = Labels(nil).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	var l2 Labels
+	assert.NoError(t, l2.Scan(`{"department":"finance"}`))
+	assert.Equal(t, l, l2)
+
+	var l3 Labels
+	assert.NoError(t, l3.Scan([]byte{}))
+	assert.Nil(t, l3)
+	assert.NoError(t, l3.Scan(nil))
+	assert.Nil(t, l3)
+
+	assert.Regexp(t, "FF10125", l3.Scan(12345))
+	assert.Error(t, l3.Scan("!json"))
+}
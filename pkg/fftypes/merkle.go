@@ -0,0 +1,91 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"crypto/sha256"
+)
+
+// Domain separation prefixes, so a leaf can never be confused with an intermediate node (as per RFC 6962)
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+func merkleLeaf(h *Bytes32) Bytes32 {
+	hash := sha256.New()
+	hash.Write([]byte{merkleLeafPrefix})
+	hash.Write(h[:])
+	return *HashResult(hash)
+}
+
+func merkleNode(left, right *Bytes32) Bytes32 {
+	hash := sha256.New()
+	hash.Write([]byte{merkleNodePrefix})
+	hash.Write(left[:])
+	hash.Write(right[:])
+	return *HashResult(hash)
+}
+
+// merkleLevels builds every level of the tree, from the leaves up to the single root.
+// Where a level has an odd number of nodes, the last node is promoted unchanged to the next level.
+func merkleLevels(hashes []*Bytes32) [][]Bytes32 {
+	level := make([]Bytes32, len(hashes))
+	for i, h := range hashes {
+		level[i] = merkleLeaf(h)
+	}
+	levels := [][]Bytes32{level}
+	for len(level) > 1 {
+		next := make([]Bytes32, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 < len(level) {
+				next = append(next, merkleNode(&level[i], &level[i+1]))
+			} else {
+				next = append(next, level[i])
+			}
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return levels
+}
+
+// MerkleRoot calculates the root of a binary Merkle tree, with the supplied hashes as the leaves.
+// All hashes must be non-nil. The root of an empty list is nil.
+func MerkleRoot(hashes []*Bytes32) *Bytes32 {
+	if len(hashes) == 0 {
+		return nil
+	}
+	levels := merkleLevels(hashes)
+	root := levels[len(levels)-1][0]
+	return &root
+}
+
+// merkleProof returns the sibling hashes required to prove inclusion of the leaf at the given index,
+// ordered from the leaf up to the root. Levels where the node was promoted have no sibling.
+func merkleProof(hashes []*Bytes32, index int) []Bytes32 {
+	levels := merkleLevels(hashes)
+	proof := []Bytes32{}
+	for _, level := range levels[:len(levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		index /= 2
+	}
+	return proof
+}
@@ -0,0 +1,102 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testMerkleHashes(n int) []*Bytes32 {
+	hashes := make([]*Bytes32, n)
+	for i := 0; i < n; i++ {
+		var h Bytes32 = sha256.Sum256([]byte{'a' + byte(i)})
+		hashes[i] = &h
+	}
+	return hashes
+}
+
+// verifyTestMerkleProof walks from the leaf to the root, using the index and leaf count
+// to work out which side each sibling is on, and where a node was promoted without a sibling
+func verifyTestMerkleProof(leaf *Bytes32, index, count int, proof []Bytes32, root *Bytes32) bool {
+	node := merkleLeaf(leaf)
+	for count > 1 {
+		sibling := index ^ 1
+		if sibling < count {
+			if len(proof) == 0 {
+				return false
+			}
+			if index%2 == 0 {
+				node = merkleNode(&node, &proof[0])
+			} else {
+				node = merkleNode(&proof[0], &node)
+			}
+			proof = proof[1:]
+		}
+		index /= 2
+		count = (count + 1) / 2
+	}
+	return len(proof) == 0 && node == *root
+}
+
+func TestMerkleRootKnownVectors(t *testing.T) {
+	hashes := testMerkleHashes(5)
+	assert.Equal(t, "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb", hashes[0].String())
+
+	assert.Nil(t, MerkleRoot([]*Bytes32{}))
+	assert.Equal(t, "a23bd5b06da9048238a65b3f1d9d0b9e15fae3dde262688e6489aa4c763d1820", MerkleRoot(hashes[0:1]).String())
+	assert.Equal(t, "ad5ca6cddc0b27c6a83e332bf28011769236e6c6a1f786ebf7b5267b37a5bd22", MerkleRoot(hashes[0:2]).String())
+	assert.Equal(t, "cac3d448d4e20a2ad5eae1f500e63c2a7f9217cd14572ba7fd22e26dc1ec2648", MerkleRoot(hashes[0:3]).String())
+	assert.Equal(t, "4dc1abc938a0141a3c7cd1fed88948c35c4452e7e8aff9b1503eb5100a2c77b3", MerkleRoot(hashes).String())
+}
+
+func TestMerkleRootOrderSensitive(t *testing.T) {
+	hashes := testMerkleHashes(2)
+	assert.NotEqual(t, *MerkleRoot(hashes), *MerkleRoot([]*Bytes32{hashes[1], hashes[0]}))
+}
+
+func TestMerkleProofKnownVectors(t *testing.T) {
+	hashes := testMerkleHashes(5)
+
+	proof := merkleProof(hashes, 2)
+	assert.Len(t, proof, 3)
+	assert.Equal(t, "de22f76c222682c331f7dda7349654b6a9f4f710077025e9b29130023712780f", proof[0].String())
+	assert.Equal(t, "ad5ca6cddc0b27c6a83e332bf28011769236e6c6a1f786ebf7b5267b37a5bd22", proof[1].String())
+	assert.Equal(t, "ccfa4ba2b7ea0f00e2ab8e295f288befbfd9f316b854edaccb5bfdca87970fc6", proof[2].String())
+
+	// The last leaf is promoted up two levels, so only needs the root of the first four
+	proof = merkleProof(hashes, 4)
+	assert.Len(t, proof, 1)
+	assert.Equal(t, "3baac34fdbf4f2297a37c0613822d0c48efdcd6602ca7a4f48ceb31339ffb3d5", proof[0].String())
+
+	assert.Empty(t, merkleProof(hashes[0:1], 0))
+}
+
+func TestMerkleProofAllItems(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		hashes := testMerkleHashes(n)
+		root := MerkleRoot(hashes)
+		for i := 0; i < n; i++ {
+			proof := merkleProof(hashes, i)
+			assert.True(t, verifyTestMerkleProof(hashes[i], i, n, proof, root), fmt.Sprintf("n=%d i=%d", n, i))
+			assert.False(t, verifyTestMerkleProof(NewRandB32(), i, n, proof, root), fmt.Sprintf("n=%d i=%d", n, i))
+		}
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"strings"
+	"unicode"
 
 	"github.com/hyperledger/firefly/internal/i18n"
 )
@@ -27,6 +29,17 @@ import (
 const (
 	// DefaultTopic will be set as the topic of any messages set without a topic
 	DefaultTopic = "default"
+
+	// MessageVersion is the version of the message format written by this node when it seals a message.
+	// It stays at zero, which is omitted from the header and so from its hash, until the format changes - so
+	// the messages this node writes are still accepted by nodes that predate the version.
+	MessageVersion uint8 = 0
+
+	// MinSupportedMessageVersion is the oldest message format this node can process
+	MinSupportedMessageVersion uint8 = 0
+
+	// MaxSupportedMessageVersion is the newest message format this node can process
+	MaxSupportedMessageVersion uint8 = MessageVersion
 )
 
 // MessageType is the fundamental type of a message
@@ -41,38 +54,70 @@ var (
 	MessageTypePrivate MessageType = ffEnum("messagetype", "private")
 	// MessageTypeGroupInit is a special private message that contains the definition of the group
 	MessageTypeGroupInit MessageType = ffEnum("messagetype", "groupinit")
+	// MessageTypeTokenPoolDefinition is a message broadcasting the definition of a token pool, kept separate from other system definitions
+	MessageTypeTokenPoolDefinition MessageType = ffEnum("messagetype", "token_pool_definition")
+)
+
+// DataSensitivity is the data classification of a message
+type DataSensitivity = FFEnum
+
+var (
+	// DataSensitivityPublic is data that can be freely shared, and hence must not be sent privately
+	DataSensitivityPublic DataSensitivity = ffEnum("datasensitivity", "public")
+	// DataSensitivityInternal is data for use only within the participating organizations
+	DataSensitivityInternal DataSensitivity = ffEnum("datasensitivity", "internal")
+	// DataSensitivityConfidential is data that should only be shared with specific parties
+	DataSensitivityConfidential DataSensitivity = ffEnum("datasensitivity", "confidential")
+	// DataSensitivityRestricted is the most sensitive classification of data
+	DataSensitivityRestricted DataSensitivity = ffEnum("datasensitivity", "restricted")
 )
 
 // MessageHeader contains all fields that contribute to the hash
 // The order of the serialization mut not change, once released
 type MessageHeader struct {
-	ID        *UUID           `json:"id,omitempty"`
-	CID       *UUID           `json:"cid,omitempty"`
-	Type      MessageType     `json:"type" ffenum:"messagetype"`
-	TxType    TransactionType `json:"txtype,omitempty"`
-	Author    string          `json:"author,omitempty"`
-	Created   *FFTime         `json:"created,omitempty"`
-	Namespace string          `json:"namespace,omitempty"`
-	Group     *Bytes32        `json:"group,omitempty"`
-	Topics    FFNameArray     `json:"topics,omitempty"`
-	Tag       string          `json:"tag,omitempty"`
-	DataHash  *Bytes32        `json:"datahash,omitempty"`
+	ID            *UUID           `json:"id,omitempty"`
+	CID           *UUID           `json:"cid,omitempty"`
+	Type          MessageType     `json:"type" ffenum:"messagetype"`
+	TxType        TransactionType `json:"txtype,omitempty"`
+	Author        string          `json:"author,omitempty"`
+	Created       *FFTime         `json:"created,omitempty"`
+	Namespace     string          `json:"namespace,omitempty"`
+	Group         *Bytes32        `json:"group,omitempty"`
+	Topics        FFNameArray     `json:"topics,omitempty"`
+	Tag           string          `json:"tag,omitempty"`
+	DataHash      *Bytes32        `json:"datahash,omitempty"`
+	Sensitivity   DataSensitivity `json:"sensitivity,omitempty" ffenum:"datasensitivity"`
+	Thread        *UUID           `json:"thread,omitempty"`
+	Priority      uint8           `json:"priority,omitempty"`
+	Forwarded     bool            `json:"forwarded,omitempty"`
+	ForwardedFrom *UUID           `json:"forwardedFrom,omitempty"`
+	ContentType   string          `json:"contentType,omitempty"`
+	Version       uint8           `json:"version,omitempty"`
 }
 
 // Message is the envelope by which coordinated data exchange can happen between parties in the network
 // Data is passed by reference in these messages, and a chain of hashes covering the data and the
 // details of the message, provides a verification against tampering.
 type Message struct {
-	Header    MessageHeader `json:"header"`
-	Hash      *Bytes32      `json:"hash,omitempty"`
-	BatchID   *UUID         `json:"batch,omitempty"`
-	Local     bool          `json:"local,omitempty"`
-	Rejected  bool          `json:"rejected,omitempty"`
-	Pending   SortableBool  `json:"pending"`
-	Confirmed *FFTime       `json:"confirmed,omitempty"`
-	Data      DataRefs      `json:"data"`
-	Pins      FFNameArray   `json:"pins,omitempty"`
-	Sequence  int64         `json:"-"` // Local database sequence used internally for batch assembly
+	Header     MessageHeader `json:"header"`
+	Hash       *Bytes32      `json:"hash,omitempty"`
+	BatchID    *UUID         `json:"batch,omitempty"`
+	Local      bool          `json:"local,omitempty"`
+	Rejected   bool          `json:"rejected,omitempty"`
+	Pending    SortableBool  `json:"pending"`
+	Pinned     bool          `json:"pinned"`
+	Ephemeral  bool          `json:"ephemeral,omitempty"` // All records of the message are removed once it is confirmed, and its events delivered
+	Confirmed  *FFTime       `json:"confirmed,omitempty"`
+	CreatedBy  string        `json:"createdBy,omitempty"` // Identity that submitted the message to this node, recorded on the operations for its batch
+	ReadAt     *FFTime       `json:"-"`                   // Node-local time the message was marked as read
+	Deadline   *FFTime       `json:"-"`                   // Node-local latest time by which the message must be dispatched, or it is failed
+	Error      string        `json:"-"`                   // Node-local reason the message failed, such as missing its deadline
+	Labels     Labels        `json:"labels,omitempty"`
+	Data       DataRefs      `json:"data"`
+	DataDigest *Bytes32      `json:"dataDigest,omitempty"` // Merkle root of the data hashes, allowing inclusion proofs for individual data items
+	Pins       FFNameArray   `json:"pins,omitempty"`
+	Groups     GroupRefs     `json:"groups,omitempty"` // Additional private groups the message is delivered to, beyond Header.Group
+	Sequence   int64         `json:"-"`                // Local database sequence used internally for batch assembly
 }
 
 // MessageInOut allows API users to submit values in-line in the payload submitted, which
@@ -81,13 +126,25 @@ type MessageInOut struct {
 	Message
 	InlineData InlineData  `json:"data"`
 	Group      *InputGroup `json:"group,omitempty"`
+	InReplyTo  *UUID       `json:"inReplyTo,omitempty"`
+	Pinned     *bool       `json:"pinned,omitempty"` // overrides the default for whether the message is pinned
+	ExternalID string      `json:"-"`                // caller supplied identifier, used to correlate errors from asynchronous submission
+}
+
+// MessageFetchInput is the list of message IDs to fetch in a single bulk request
+type MessageFetchInput struct {
+	IDs []*UUID `json:"ids"`
 }
 
 // InputGroup declares a group in-line for auotmatic resolution, without having to define a group up-front
 type InputGroup struct {
-	Name    string        `json:"name,omitempty"`
-	Ledger  *UUID         `json:"ledger,omitempty"`
-	Members []MemberInput `json:"members"`
+	Name        string        `json:"name,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Ledger      *UUID         `json:"ledger,omitempty"`
+	Members     []MemberInput `json:"members"`
+	ACL         []string      `json:"acl,omitempty"`
+	Schema      *DatatypeRef  `json:"schema,omitempty"`
+	Previous    *Bytes32      `json:"previous,omitempty"`
 }
 
 // InlineData is an array of data references or values
@@ -102,6 +159,8 @@ type DataRefOrValue struct {
 	Datatype  *DatatypeRef  `json:"datatype,omitempty"`
 	Value     Byteable      `json:"value,omitempty"`
 	Blob      *BlobRef      `json:"blob,omitempty"`
+	Previous  *UUID         `json:"previous,omitempty"`  // the previous version of this data, if it is an update
+	ExpiresAt *FFTime       `json:"expiresAt,omitempty"` // when the data should be purged from this node
 }
 
 // MessageRef is a lightweight data structure that can be used to refer to a message
@@ -142,6 +201,16 @@ func (m *Message) Seal(ctx context.Context) (err error) {
 	if m.Header.Created == nil {
 		m.Header.Created = Now()
 	}
+	if err = m.Header.validateSensitivity(ctx); err != nil {
+		return err
+	}
+	if err = m.Header.validateContentType(ctx); err != nil {
+		return err
+	}
+	if err = m.Labels.Validate(ctx, "labels"); err != nil {
+		return err
+	}
+	m.Header.Version = MessageVersion
 	m.Confirmed = nil
 	m.Pending = true
 	if m.Data == nil {
@@ -150,11 +219,41 @@ func (m *Message) Seal(ctx context.Context) (err error) {
 	err = m.DupDataCheck(ctx)
 	if err == nil {
 		m.Header.DataHash = m.Data.Hash()
+		m.DataDigest = m.Data.MerkleRoot()
 		m.Hash = m.Header.Hash()
 	}
 	return err
 }
 
+func (h *MessageHeader) validateSensitivity(ctx context.Context) error {
+	if h.Sensitivity == "" {
+		return nil
+	}
+	return FFEnumValid(ctx, "datasensitivity", h.Sensitivity)
+}
+
+// maxContentTypeLength matches the size of the content_type column
+const maxContentTypeLength = 256
+
+// validateContentType checks the optional content type is a MIME type, such as "application/xml"
+func (h *MessageHeader) validateContentType(ctx context.Context) error {
+	if h.ContentType == "" {
+		return nil
+	}
+	if err := ValidateLength(ctx, h.ContentType, "contentType", maxContentTypeLength); err != nil {
+		return err
+	}
+	if !strings.Contains(h.ContentType, "/") {
+		return i18n.NewError(ctx, i18n.MsgInvalidMessageContentType, h.ContentType)
+	}
+	for _, r := range h.ContentType {
+		if unicode.IsControl(r) {
+			return i18n.NewError(ctx, i18n.MsgInvalidMessageContentType, h.ContentType)
+		}
+	}
+	return nil
+}
+
 func (m *Message) DupDataCheck(ctx context.Context) (err error) {
 	dupCheck := make(map[string]bool)
 	for i, d := range m.Data {
@@ -171,6 +270,9 @@ func (m *Message) DupDataCheck(ctx context.Context) (err error) {
 }
 
 func (m *Message) Verify(ctx context.Context) error {
+	if m.Header.Version > MaxSupportedMessageVersion {
+		return i18n.NewError(ctx, i18n.MsgUnsupportedMessageVersion, m.Header.Version, MaxSupportedMessageVersion)
+	}
 	if err := m.Header.Topics.Validate(ctx, "header.topics"); err != nil {
 		return err
 	}
@@ -179,6 +281,15 @@ func (m *Message) Verify(ctx context.Context) error {
 			return err
 		}
 	}
+	if err := m.Header.validateSensitivity(ctx); err != nil {
+		return err
+	}
+	if err := m.Header.validateContentType(ctx); err != nil {
+		return err
+	}
+	if err := m.Labels.Validate(ctx, "labels"); err != nil {
+		return err
+	}
 	err := m.DupDataCheck(ctx)
 	if err != nil {
 		return err
@@ -191,9 +302,35 @@ func (m *Message) Verify(ctx context.Context) error {
 	if *m.Hash != *headerHash || *m.Header.DataHash != *dataHash {
 		return i18n.NewError(ctx, i18n.MsgVerifyFailedInvalidHashes, m.Hash.String(), headerHash.String(), m.Header.DataHash.String(), dataHash.String())
 	}
+	// The digest is optional, as messages sealed by older nodes will not have one
+	if m.DataDigest != nil {
+		if dataDigest := m.Data.MerkleRoot(); !m.DataDigest.Equals(dataDigest) {
+			return i18n.NewError(ctx, i18n.MsgVerifyFailedInvalidDigest, m.DataDigest, dataDigest)
+		}
+	}
 	return nil
 }
 
+// ProofForDataItem returns the sibling hashes required to prove the data item at the supplied
+// index is included in the DataDigest of the message, ordered from the leaf up to the root
+func (m *Message) ProofForDataItem(index int) ([]Bytes32, error) {
+	if index < 0 || index >= len(m.Data) {
+		return nil, i18n.NewError(context.Background(), i18n.MsgDataItemIndexOutOfRange, index, len(m.Data))
+	}
+	for i, d := range m.Data {
+		if d == nil || d.Hash == nil {
+			return nil, i18n.NewError(context.Background(), i18n.MsgNilDataReferenceSealFail, i)
+		}
+	}
+	return merkleProof(m.Data.hashes(), index), nil
+}
+
 func (m *Message) LocalSequence() int64 {
 	return m.Sequence
 }
+
+// TargetGroups returns the hashes of all the groups a private message is sent to - the message group
+// first, followed by any additional groups, without duplicates
+func (m *Message) TargetGroups() []*Bytes32 {
+	return targetGroups(m.Header.Group, m.Groups)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -34,6 +35,57 @@ func TestSealBareMessage(t *testing.T) {
 	assert.NotNil(t, msg.Hash)
 }
 
+func TestMessageSensitivityValidation(t *testing.T) {
+	for _, sensitivity := range FFEnumValues("datasensitivity") {
+		msg := Message{
+			Header: MessageHeader{Sensitivity: DataSensitivity(sensitivity.(string))},
+		}
+		assert.NoError(t, msg.Seal(context.Background()))
+		assert.NoError(t, msg.Verify(context.Background()))
+	}
+
+	msg := Message{
+		Header: MessageHeader{Sensitivity: "secret"},
+	}
+	assert.Regexp(t, "FF10277.*secret", msg.Seal(context.Background()))
+	assert.Regexp(t, "FF10277.*secret", msg.Verify(context.Background()))
+}
+
+func TestMessageContentTypeValidation(t *testing.T) {
+	msg := Message{
+		Header: MessageHeader{ContentType: "application/xml"},
+	}
+	assert.NoError(t, msg.Seal(context.Background()))
+	assert.NoError(t, msg.Verify(context.Background()))
+
+	msg.Header.ContentType = "xml"
+	assert.Regexp(t, "FF10325.*xml", msg.Seal(context.Background()))
+	assert.Regexp(t, "FF10325.*xml", msg.Verify(context.Background()))
+
+	msg.Header.ContentType = "text/csv\n"
+	assert.Regexp(t, "FF10325", msg.Seal(context.Background()))
+	assert.Regexp(t, "FF10325", msg.Verify(context.Background()))
+
+	msg.Header.ContentType = "application/" + strings.Repeat("x", 244)
+	assert.NoError(t, msg.Seal(context.Background()))
+
+	msg.Header.ContentType = "application/" + strings.Repeat("x", 245)
+	assert.Regexp(t, "FF10188.*contentType", msg.Seal(context.Background()))
+	assert.Regexp(t, "FF10188.*contentType", msg.Verify(context.Background()))
+}
+
+func TestMessageLabelsValidation(t *testing.T) {
+	msg := Message{
+		Labels: Labels{"department": "finance"},
+	}
+	assert.NoError(t, msg.Seal(context.Background()))
+	assert.NoError(t, msg.Verify(context.Background()))
+
+	msg.Labels["region"] = strings.Repeat("x", 257)
+	assert.Regexp(t, "FF10188.*labels.region", msg.Seal(context.Background()))
+	assert.Regexp(t, "FF10188.*labels.region", msg.Verify(context.Background()))
+}
+
 func TestVerifyEmptyTopicString(t *testing.T) {
 	msg := Message{
 		Header: MessageHeader{
@@ -160,6 +212,9 @@ func TestSealKnownMessage(t *testing.T) {
 	assert.Equal(t, `2468d5c26cc85968acaf8b96d09476453916ea4eab41632a31d09efc7ab297d2`, dataHash.String())
 	assert.Equal(t, dataHash, *msg.Header.DataHash)
 
+	// The data digest is the Merkle root of the data hashes, and does not contribute to the message hash
+	assert.Equal(t, `0bc27e723eea9e648321e8798a15fdb45a2ea106b34b5486faa5a96aa3915a1e`, msg.DataDigest.String())
+
 	// Header contains the data hash, and is hashed into the message hash
 	actualHeader, _ := json.Marshal(&msg.Header)
 	expectedHeader := `{"id":"2cd37805-5f40-4e12-962e-67868cde3049","cid":"39296b6e-91b9-4a61-b279-833c85b04d94","type":"private","txtype":"batch_pin","author":"0x12345","created":"2021-05-04T04:55:03.123456789Z","namespace":"ns1","group":"3fcc7e07069e441f07c9f6b26f16fcb2dc896222d72888675082fd308440d9ae","topics":["topic1","topic2"],"tag":"tag1","datahash":"2468d5c26cc85968acaf8b96d09476453916ea4eab41632a31d09efc7ab297d2"}`
@@ -186,3 +241,98 @@ func TestSetInlineData(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Regexp(t, "some data", string(b))
 }
+
+func TestVerifyMismatchedDataDigest(t *testing.T) {
+	msg := Message{
+		Data: DataRefs{
+			{ID: NewUUID(), Hash: NewRandB32()},
+		},
+	}
+	err := msg.Seal(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, msg.Verify(context.Background()))
+
+	msg.DataDigest = NewRandB32()
+	err = msg.Verify(context.Background())
+	assert.Regexp(t, "FF10298", err)
+
+	// Messages from older nodes do not have a digest
+	msg.DataDigest = nil
+	assert.NoError(t, msg.Verify(context.Background()))
+}
+
+func TestVerifyMessageVersion(t *testing.T) {
+	msg := Message{
+		Data: DataRefs{
+			{ID: NewUUID(), Hash: NewRandB32()},
+		},
+	}
+	err := msg.Seal(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, MessageVersion, msg.Header.Version)
+
+	// A message written at the version this node supports is accepted
+	assert.Equal(t, MaxSupportedMessageVersion, msg.Header.Version)
+	assert.NoError(t, msg.Verify(context.Background()))
+
+	// The version is not written until the format changes, so the hash matches that of an older node
+	b, _ := json.Marshal(&msg.Header)
+	assert.NotContains(t, string(b), "version")
+
+	// A message from a newer node is rejected before the hashes are checked
+	msg.Header.Version = MaxSupportedMessageVersion + 1
+	err = msg.Verify(context.Background())
+	assert.Regexp(t, "FF10336.*1.*0", err)
+}
+
+func TestProofForDataItem(t *testing.T) {
+	hashes := testMerkleHashes(3)
+	msg := Message{
+		Data: DataRefs{
+			{ID: NewUUID(), Hash: hashes[0]},
+			{ID: NewUUID(), Hash: hashes[1]},
+			{ID: NewUUID(), Hash: hashes[2]},
+		},
+	}
+	err := msg.Seal(context.Background())
+	assert.NoError(t, err)
+
+	for i := range msg.Data {
+		proof, err := msg.ProofForDataItem(i)
+		assert.NoError(t, err)
+		assert.True(t, verifyTestMerkleProof(msg.Data[i].Hash, i, len(msg.Data), proof, msg.DataDigest))
+	}
+}
+
+func TestProofForDataItemOutOfRange(t *testing.T) {
+	msg := Message{
+		Data: DataRefs{
+			{ID: NewUUID(), Hash: NewRandB32()},
+		},
+	}
+	_, err := msg.ProofForDataItem(1)
+	assert.Regexp(t, "FF10299", err)
+	_, err = msg.ProofForDataItem(-1)
+	assert.Regexp(t, "FF10299", err)
+}
+
+func TestProofForDataItemNilHash(t *testing.T) {
+	msg := Message{
+		Data: DataRefs{
+			{ID: NewUUID(), Hash: NewRandB32()},
+			{ID: NewUUID()},
+		},
+	}
+	_, err := msg.ProofForDataItem(0)
+	assert.Regexp(t, "FF10144.*1", err)
+}
+
+func TestMessageTargetGroups(t *testing.T) {
+	g1 := NewRandB32()
+	g2 := NewRandB32()
+	m := &Message{
+		Header: MessageHeader{Group: g1},
+		Groups: GroupRefs{{Hash: g1}, {Hash: g2}},
+	}
+	assert.Equal(t, []*Bytes32{g1, g2}, m.TargetGroups())
+}
@@ -18,19 +18,31 @@ package fftypes
 
 import (
 	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
 
 	"github.com/hyperledger/firefly/internal/i18n"
 )
 
+var (
+	oidPublicKeyX25519 = asn1.ObjectIdentifier{1, 3, 101, 110}
+	oidPublicKeyECDSA  = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveP256  = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+)
+
 // Node is a FireFly node within the network
 type Node struct {
-	ID          *UUID   `json:"id"`
-	Message     *UUID   `json:"message,omitempty"`
-	Owner       string  `json:"owner,omitempty"`
-	Name        string  `json:"name,omitempty"`
-	Description string  `json:"description,omitempty"`
-	DX          DXInfo  `json:"dx"`
-	Created     *FFTime `json:"created,omitempty"`
+	ID                *UUID       `json:"id"`
+	Message           *UUID       `json:"message,omitempty"`
+	Owner             string      `json:"owner,omitempty"`
+	Name              string      `json:"name,omitempty"`
+	Description       string      `json:"description,omitempty"`
+	DX                DXInfo      `json:"dx"`
+	PublicKey         string      `json:"publicKey,omitempty"`
+	HeartbeatInterval *FFDuration `json:"heartbeatInterval,omitempty"` // How often the node sends heartbeats to the members of its groups
+	Created           *FFTime     `json:"created,omitempty"`
 }
 
 // DXInfo is the data exchange information
@@ -49,6 +61,11 @@ func (n *Node) Validate(ctx context.Context, existing bool) (err error) {
 	if n.Owner == "" {
 		return i18n.NewError(ctx, i18n.MsgOwnerMissing)
 	}
+	if n.PublicKey != "" {
+		if err = validateNodePublicKey(ctx, n.PublicKey); err != nil {
+			return err
+		}
+	}
 	if existing {
 		if n.ID == nil {
 			return i18n.NewError(ctx, i18n.MsgNilID)
@@ -57,6 +74,37 @@ func (n *Node) Validate(ctx context.Context, existing bool) (err error) {
 	return nil
 }
 
+// validateNodePublicKey checks the key is a PEM encoded PKIX public key for one
+// of the key agreement curves supported for message encryption (X25519 or P-256)
+func validateNodePublicKey(ctx context.Context, publicKey string) error {
+	block, _ := pem.Decode([]byte(publicKey))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return i18n.NewError(ctx, i18n.MsgInvalidNodePublicKey)
+	}
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if rest, err := asn1.Unmarshal(block.Bytes, &spki); err != nil || len(rest) > 0 {
+		return i18n.NewError(ctx, i18n.MsgInvalidNodePublicKey)
+	}
+	switch {
+	case spki.Algorithm.Algorithm.Equal(oidPublicKeyX25519):
+		if len(spki.PublicKey.RightAlign()) == 32 {
+			return nil
+		}
+	case spki.Algorithm.Algorithm.Equal(oidPublicKeyECDSA):
+		var namedCurve asn1.ObjectIdentifier
+		if _, err := asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &namedCurve); err == nil && namedCurve.Equal(oidNamedCurveP256) {
+			// Let the standard library check the point is on the curve
+			if _, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+				return nil
+			}
+		}
+	}
+	return i18n.NewError(ctx, i18n.MsgInvalidNodePublicKey)
+}
+
 func (n *Node) Topic() string {
 	return orgTopic(n.Owner)
 }
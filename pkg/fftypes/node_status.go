@@ -18,9 +18,10 @@ package fftypes
 
 // NodeStatus is a set of information that represents the health, and identity of a node
 type NodeStatus struct {
-	Node     NodeStatusNode     `json:"node"`
-	Org      NodeStatusOrg      `json:"org"`
-	Defaults NodeStatusDefaults `json:"defaults"`
+	Node            NodeStatusNode            `json:"node"`
+	Org             NodeStatusOrg             `json:"org"`
+	Defaults        NodeStatusDefaults        `json:"defaults"`
+	MessageVersions NodeStatusMessageVersions `json:"messageVersions"`
 }
 
 // NodeStatusNode is the information about the local node, returned in the node status
@@ -38,6 +39,21 @@ type NodeStatusOrg struct {
 	ID         *UUID  `json:"id,omitempty"`
 }
 
+// NodeStatusMessageVersions is the range of message format versions the node can process, and the version it writes
+type NodeStatusMessageVersions struct {
+	Current      uint8 `json:"current"`
+	MinSupported uint8 `json:"minSupported"`
+	MaxSupported uint8 `json:"maxSupported"`
+}
+
+// BlockchainStatus compares the current height of the chain, with the block of the
+// latest batch pin this node has processed, to allow detection of a node that is lagging
+type BlockchainStatus struct {
+	CurrentBlock uint64  `json:"currentBlock"`
+	LatestPinned *uint64 `json:"latestPinned,omitempty"` // Omitted until the block number of a confirmed pin is known
+	Lag          *uint64 `json:"lag,omitempty"`
+}
+
 // NodeStatusDefaults is information about core configuration th
 type NodeStatusDefaults struct {
 	Namespace string `json:"namespace"`
@@ -53,3 +53,51 @@ func TestNodeValidation(t *testing.T) {
 	def.SetBroadcastMessage(NewUUID())
 	assert.NotNil(t, n.Message)
 }
+
+func TestNodeValidatePublicKey(t *testing.T) {
+
+	n := &Node{
+		Name:  "ok",
+		Owner: "0x12345",
+	}
+
+	n.PublicKey = `-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VuAyEAVko0QhJfAkAWmy3gqtHfWFOidyb2ZNU/gDTpt56b3wE=
+-----END PUBLIC KEY-----`
+	assert.NoError(t, n.Validate(context.Background(), false))
+
+	n.PublicKey = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEgzjSP4ZqtxQZ96PA5uiMfapobJLY
+zG3T/e9Hv4DoWspc916MCCm3/2iViAThG7sdEMIvHh7tRRhzqlgFWvr4Lw==
+-----END PUBLIC KEY-----`
+	assert.NoError(t, n.Validate(context.Background(), false))
+
+	// P-384 is not supported
+	n.PublicKey = `-----BEGIN PUBLIC KEY-----
+MHYwEAYHKoZIzj0CAQYFK4EEACIDYgAEm39oYfTEur2I+TXVzZk8jLqH1NteBtY0
+D859gzspz90YjXP19Sro/G9231fupXdj59OAnEaWOnG0ylTqswczJBs+SnCtTBhy
+q0FZQ2hs4Mj/lO2lZQVqhCIYFtHmmpk7
+-----END PUBLIC KEY-----`
+	assert.Regexp(t, "FF10317", n.Validate(context.Background(), false))
+
+	// P-256 point not on the curve
+	n.PublicKey = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEgzjSP4ZqtxQZ96PA5uiMfapobJLY
+zG3T/e9Hv4DoWspc916MCCm3/2iViAThG7sdEMIvHh7tRRhzqlgFWvr4LA==
+-----END PUBLIC KEY-----`
+	assert.Regexp(t, "FF10317", n.Validate(context.Background(), false))
+
+	// X25519 key of the wrong length
+	n.PublicKey = `-----BEGIN PUBLIC KEY-----
+MCkwBQYDK2VuAyAAVko0QhJfAkAWmy3gqtHfWFOidyb2ZNU/gDTpt56b3w==
+-----END PUBLIC KEY-----`
+	assert.Regexp(t, "FF10317", n.Validate(context.Background(), false))
+
+	n.PublicKey = `-----BEGIN PUBLIC KEY-----
+bm90IGFzbjE=
+-----END PUBLIC KEY-----`
+	assert.Regexp(t, "FF10317", n.Validate(context.Background(), false))
+
+	n.PublicKey = "not a key"
+	assert.Regexp(t, "FF10317", n.Validate(context.Background(), false))
+}
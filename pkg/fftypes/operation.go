@@ -16,6 +16,8 @@
 
 package fftypes
 
+import "context"
+
 // OpType describes mechanical steps in the process that have to be performed,
 // might be asynchronous, and have results in the back-end systems that might need
 // to be correlated with messages by operators.
@@ -34,6 +36,12 @@ var (
 	OpTypeTokensCreatePool OpType = ffEnum("optype", "tokens_create_pool")
 	// OpTypeTokensAnnounce is a broadcast of token pool info
 	OpTypeTokensAnnouncePool OpType = ffEnum("optype", "tokens_announce_pool")
+	// OpTypeTokensMint is a mint of tokens to a single recipient
+	OpTypeTokensMint OpType = ffEnum("optype", "tokens_mint")
+	// OpTypeTokensFreezePool is a suspension of all activity on a token pool
+	OpTypeTokensFreezePool OpType = ffEnum("optype", "tokens_freeze_pool")
+	// OpTypeTokensUnfreezePool is a resumption of activity on a frozen token pool
+	OpTypeTokensUnfreezePool OpType = ffEnum("optype", "tokens_unfreeze_pool")
 )
 
 // OpStatus is the current status of an operation
@@ -53,7 +61,7 @@ type Named interface {
 }
 
 // NewTXOperation creates a new operation for a transaction
-func NewTXOperation(plugin Named, namespace string, tx *UUID, backendID string, opType OpType, opStatus OpStatus, member string) *Operation {
+func NewTXOperation(plugin Named, namespace string, tx *UUID, backendID string, opType OpType, opStatus OpStatus, member, createdBy string) *Operation {
 	return &Operation{
 		ID:          NewUUID(),
 		Namespace:   namespace,
@@ -63,23 +71,49 @@ func NewTXOperation(plugin Named, namespace string, tx *UUID, backendID string,
 		Type:        opType,
 		Member:      member,
 		Status:      opStatus,
+		CreatedBy:   createdBy,
 		Created:     Now(),
 	}
 }
 
+type createdByCtxKey struct{}
+
+// WithCreatedBy returns a context that attributes any operations created with it to the supplied identity
+func WithCreatedBy(ctx context.Context, createdBy string) context.Context {
+	return context.WithValue(ctx, createdByCtxKey{}, createdBy)
+}
+
+// GetCreatedBy returns the identity that initiated the work on the context, or an empty string for work initiated by the node itself
+func GetCreatedBy(ctx context.Context) string {
+	createdBy, _ := ctx.Value(createdByCtxKey{}).(string)
+	return createdBy
+}
+
 // Operation is a description of an action performed as part of a transaction submitted by this node
 type Operation struct {
-	ID          *UUID      `json:"id"`
-	Namespace   string     `json:"namespace"`
-	Transaction *UUID      `json:"tx"`
-	Type        OpType     `json:"type" ffenum:"optype"`
-	Member      string     `json:"member,omitempty"`
-	Status      OpStatus   `json:"status"`
-	Error       string     `json:"error,omitempty"`
-	Plugin      string     `json:"plugin"`
-	BackendID   string     `json:"backendId"`
-	Input       JSONObject `json:"input,omitempty"`
-	Output      JSONObject `json:"output,omitempty"`
-	Created     *FFTime    `json:"created,omitempty"`
-	Updated     *FFTime    `json:"updated,omitempty"`
+	ID          *UUID                  `json:"id"`
+	Namespace   string                 `json:"namespace"`
+	Transaction *UUID                  `json:"tx"`
+	Type        OpType                 `json:"type" ffenum:"optype"`
+	Member      string                 `json:"member,omitempty"`
+	Status      OpStatus               `json:"status"`
+	Error       string                 `json:"error,omitempty"`
+	Plugin      string                 `json:"plugin"`
+	BackendID   string                 `json:"backendId"`
+	Input       JSONObject             `json:"input,omitempty"`
+	Output      JSONObject             `json:"output,omitempty"`
+	Created     *FFTime                `json:"created,omitempty"`
+	Updated     *FFTime                `json:"updated,omitempty"`
+	DeletedAt   *FFTime                `json:"deleted,omitempty"`
+	Timeout     *FFDuration            `json:"timeout,omitempty"`
+	Priority    uint8                  `json:"priority,omitempty"`
+	CreatedBy   string                 `json:"createdBy,omitempty"`
+	History     []*OperationTransition `json:"history,omitempty"`
+}
+
+// OperationTransition records a change in the status of an operation
+type OperationTransition struct {
+	Status    OpStatus `json:"status"`
+	Timestamp *FFTime  `json:"timestamp"`
+	Error     string   `json:"error,omitempty"`
 }
@@ -17,6 +17,7 @@
 package fftypes
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,7 +30,7 @@ func (f *fakePlugin) Name() string { return "fake" }
 func TestNewPendingMessageOp(t *testing.T) {
 
 	txID := NewUUID()
-	op := NewTXOperation(&fakePlugin{}, "ns1", txID, "testBackend", OpTypePublicStorageBatchBroadcast, OpStatusPending, "member")
+	op := NewTXOperation(&fakePlugin{}, "ns1", txID, "testBackend", OpTypePublicStorageBatchBroadcast, OpStatusPending, "member", "org1")
 	assert.Equal(t, Operation{
 		ID:          op.ID,
 		Namespace:   "ns1",
@@ -39,6 +40,16 @@ func TestNewPendingMessageOp(t *testing.T) {
 		Type:        OpTypePublicStorageBatchBroadcast,
 		Member:      "member",
 		Status:      OpStatusPending,
+		CreatedBy:   "org1",
 		Created:     op.Created,
 	}, *op)
 }
+
+func TestCreatedByContext(t *testing.T) {
+
+	ctx := context.Background()
+	assert.Equal(t, "", GetCreatedBy(ctx))
+
+	ctx = WithCreatedBy(ctx, "org1")
+	assert.Equal(t, "org1", GetCreatedBy(ctx))
+}
@@ -34,7 +34,10 @@ type Organization struct {
 	Name        string     `json:"name,omitempty"`
 	Description string     `json:"description,omitempty"`
 	Profile     JSONObject `json:"profile,omitempty"`
+	Metadata    JSONObject `json:"metadata,omitempty"`
+	Country     string     `json:"country,omitempty"`
 	Created     *FFTime    `json:"created,omitempty"`
+	Signature   string     `json:"signature,omitempty"`
 }
 
 func (org *Organization) Validate(ctx context.Context, existing bool) (err error) {
@@ -44,6 +47,16 @@ func (org *Organization) Validate(ctx context.Context, existing bool) (err error
 	if err = ValidateLength(ctx, org.Description, "description", 4096); err != nil {
 		return err
 	}
+	if org.Metadata != nil {
+		if err = ValidateLength(ctx, org.Metadata.String(), "metadata", 4096); err != nil {
+			return err
+		}
+	}
+	if org.Country != "" {
+		if err = validateCountry(ctx, org.Country); err != nil {
+			return err
+		}
+	}
 	if existing {
 		if org.ID == nil {
 			return i18n.NewError(ctx, i18n.MsgNilID)
@@ -52,6 +65,19 @@ func (org *Organization) Validate(ctx context.Context, existing bool) (err error
 	return nil
 }
 
+// validateCountry checks the country is an ISO 3166-1 alpha-2 code, which is two upper case letters
+func validateCountry(ctx context.Context, country string) error {
+	if len(country) != 2 {
+		return i18n.NewError(ctx, i18n.MsgInvalidCountryCode, country)
+	}
+	for _, c := range country {
+		if c < 'A' || c > 'Z' {
+			return i18n.NewError(ctx, i18n.MsgInvalidCountryCode, country)
+		}
+	}
+	return nil
+}
+
 func orgTopic(orgIdentity string) string {
 	buf := strings.Builder{}
 	for _, r := range orgIdentity {
@@ -74,3 +100,7 @@ func (org *Organization) Topic() string {
 func (org *Organization) SetBroadcastMessage(msgID *UUID) {
 	org.Message = msgID
 }
+
+func (org *Organization) SetSignature(signature string) {
+	org.Signature = signature
+}
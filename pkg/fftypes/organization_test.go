@@ -36,13 +36,35 @@ func TestOrganizationValidation(t *testing.T) {
 	}
 	assert.Regexp(t, "FF10188.*description", org.Validate(context.Background(), false))
 
+	org = &Organization{
+		Name:     "ok",
+		Metadata: JSONObject{"big": string(make([]byte, 4096))},
+	}
+	assert.Regexp(t, "FF10188.*metadata", org.Validate(context.Background(), false))
+
+	org = &Organization{
+		Name:    "ok",
+		Country: "gb",
+	}
+	assert.Regexp(t, "FF10330.*gb", org.Validate(context.Background(), false))
+
+	org = &Organization{
+		Name:    "ok",
+		Country: "GBR",
+	}
+	assert.Regexp(t, "FF10330.*GBR", org.Validate(context.Background(), false))
+
 	org = &Organization{
 		Name:        "ok",
 		Description: "ok",
 		Identity:    "ok",
+		Metadata:    JSONObject{"role": "auditor"},
 	}
 	assert.NoError(t, org.Validate(context.Background(), false))
 
+	org.Country = "GB"
+	assert.NoError(t, org.Validate(context.Background(), false))
+
 	assert.Regexp(t, "FF10203", org.Validate(context.Background(), true))
 
 	var def Definition = org
@@ -50,4 +72,8 @@ func TestOrganizationValidation(t *testing.T) {
 	assert.Equal(t, "ff_org_A_B_C_D_E_F_G_H_I_J_K_L_M_N_O_P_Q_R_S_T_U_V_W_X_Y_Z___________0_1", def.Topic())
 	def.SetBroadcastMessage(NewUUID())
 	assert.NotNil(t, org.Message)
+
+	var signed SignedDefinition = org
+	signed.SetSignature("0x12345")
+	assert.Equal(t, "0x12345", org.Signature)
 }
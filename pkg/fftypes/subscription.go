@@ -24,13 +24,15 @@ import (
 	"github.com/hyperledger/firefly/internal/i18n"
 )
 
-// SubscriptionFilter contains regular expressions to match against events. All must match for an event to be dispatched to a subscription
+// SubscriptionFilter contains regular expressions to match against events. All must match for an event to be dispatched to a subscription.
+// Expression is an optional boolean filter expression over the event fields, such as "type==message_confirmed OR NOT reference==<uuid>"
 type SubscriptionFilter struct {
-	Events string `json:"events,omitempty"`
-	Topics string `json:"topics,omitempty"`
-	Tag    string `json:"tag,omitempty"`
-	Group  string `json:"group,omitempty"`
-	Author string `json:"author,omitempty"`
+	Events     string `json:"events,omitempty"`
+	Topics     string `json:"topics,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	Group      string `json:"group,omitempty"`
+	Author     string `json:"author,omitempty"`
+	Expression string `json:"expression,omitempty"`
 }
 
 // SubOptsFirstEvent picks the first event that should be dispatched on the subscription, and can be a string containing an exact sequence as well as one of the enum values
@@ -43,6 +45,13 @@ const (
 	SubOptsFirstEventNewest SubOptsFirstEvent = "newest"
 )
 
+const (
+	// SubscriptionDefaultMaxDeliveryAttempts is set on durable subscriptions created without a delivery limit
+	SubscriptionDefaultMaxDeliveryAttempts = 3
+	// SubscriptionMaxDeliveryAttemptsLimit is the highest delivery limit that can be set on a subscription
+	SubscriptionMaxDeliveryAttemptsLimit = 100
+)
+
 // SubscriptionCoreOptions are the core options that apply across all transports
 type SubscriptionCoreOptions struct {
 	FirstEvent *SubOptsFirstEvent `json:"firstEvent,omitempty"`
@@ -78,6 +87,30 @@ type Subscription struct {
 	Ephemeral bool                `json:"ephemeral,omitempty"`
 	Created   *FFTime             `json:"created"`
 	Updated   *FFTime             `json:"updated"`
+
+	// MaxDeliveryAttempts is the number of failed deliveries of an event, before it is moved to the dead letter table
+	MaxDeliveryAttempts int `json:"maxDeliveryAttempts,omitempty"`
+
+	// Paused is set while delivery of events on the subscription is paused
+	Paused bool `json:"paused,omitempty"`
+}
+
+// SubscriptionUpdate is the input to change the settings of an existing durable subscription
+type SubscriptionUpdate struct {
+	MaxDeliveryAttempts *int `json:"maxDeliveryAttempts,omitempty"`
+}
+
+// ValidateMaxDeliveryAttempts checks the delivery limit of the subscription is in the allowed range
+func (sub *Subscription) ValidateMaxDeliveryAttempts(ctx context.Context) error {
+	if sub.MaxDeliveryAttempts < 1 || sub.MaxDeliveryAttempts > SubscriptionMaxDeliveryAttemptsLimit {
+		return i18n.NewError(ctx, i18n.MsgInvalidMaxDeliveryAttempts, sub.MaxDeliveryAttempts, SubscriptionMaxDeliveryAttemptsLimit)
+	}
+	return nil
+}
+
+// SubscriptionRewind is the input to restart delivery on a durable subscription from an earlier event sequence
+type SubscriptionRewind struct {
+	FromSequence int64 `json:"fromSequence"`
 }
 
 func (so *SubscriptionOptions) UnmarshalJSON(b []byte) error {
@@ -17,6 +17,7 @@
 package fftypes
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -89,3 +90,14 @@ func TestSubscriptionUnMarshalFail(t *testing.T) {
 	assert.Regexp(t, "readAhead", err)
 
 }
+
+func TestSubscriptionValidateMaxDeliveryAttempts(t *testing.T) {
+	sub := &Subscription{MaxDeliveryAttempts: 1}
+	assert.NoError(t, sub.ValidateMaxDeliveryAttempts(context.Background()))
+	sub.MaxDeliveryAttempts = 100
+	assert.NoError(t, sub.ValidateMaxDeliveryAttempts(context.Background()))
+	sub.MaxDeliveryAttempts = 0
+	assert.Regexp(t, "FF10335", sub.ValidateMaxDeliveryAttempts(context.Background()))
+	sub.MaxDeliveryAttempts = 101
+	assert.Regexp(t, "FF10335", sub.ValidateMaxDeliveryAttempts(context.Background()))
+}
@@ -20,7 +20,9 @@ import (
 	"context"
 	"database/sql/driver"
 	"encoding/json"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hyperledger/firefly/internal/i18n"
@@ -154,6 +156,35 @@ func (ft *FFTime) Equal(ft2 *FFTime) bool {
 	return *ft == *ft2
 }
 
+// Time returns the underlying time.Time, treating a nil receiver as the zero time
+func (ft *FFTime) Time() time.Time {
+	if ft == nil {
+		return time.Time{}
+	}
+	return time.Time(*ft)
+}
+
+// Add returns a new FFTime offset by the supplied duration
+func (ft *FFTime) Add(d time.Duration) *FFTime {
+	t := FFTime(ft.Time().Add(d))
+	return &t
+}
+
+// Sub returns the duration ft-ft2, with nil values treated as the zero time
+func (ft *FFTime) Sub(ft2 *FFTime) time.Duration {
+	return ft.Time().Sub(ft2.Time())
+}
+
+// Before reports whether ft is before ft2, with nil values treated as the zero time
+func (ft *FFTime) Before(ft2 *FFTime) bool {
+	return ft.Time().Before(ft2.Time())
+}
+
+// After reports whether ft is after ft2, with nil values treated as the zero time
+func (ft *FFTime) After(ft2 *FFTime) bool {
+	return ft.Time().After(ft2.Time())
+}
+
 // ParseToDuration is a standard handling of any duration string, in config or API options
 func ParseToDuration(durationString string) time.Duration {
 	if durationString == "" {
@@ -170,16 +201,30 @@ func ParseToDuration(durationString string) time.Duration {
 func ParseDurationString(durationString string, def time.Duration) (FFDuration, error) {
 	duration, err := time.ParseDuration(durationString)
 	if err != nil {
-		intVal, err := strconv.ParseInt(durationString, 10, 64)
+		numString, unit := durationString, def
+		if strings.HasSuffix(numString, "d") {
+			// Whole days, which are not supported by time.ParseDuration
+			numString, unit = strings.TrimSuffix(numString, "d"), 24*time.Hour
+		}
+		intVal, err := strconv.ParseInt(numString, 10, 64)
 		if err != nil {
 			return 0, i18n.NewError(context.Background(), i18n.MsgDurationParseFail, durationString)
 		}
 		// Default without a suffix
-		duration = time.Duration(intVal) * def
+		return durationFromUnits(intVal, unit, durationString)
 	}
 	return FFDuration(duration), nil
 }
 
+// durationFromUnits multiplies a count of units into a duration, failing if the result cannot
+// be represented in an int64 number of nanoseconds
+func durationFromUnits(count int64, unit time.Duration, original string) (FFDuration, error) {
+	if unit > 0 && (count > math.MaxInt64/int64(unit) || count < math.MinInt64/int64(unit)) {
+		return 0, i18n.NewError(context.Background(), i18n.MsgDurationParseFail, original)
+	}
+	return FFDuration(time.Duration(count) * unit), nil
+}
+
 func (fd *FFDuration) MarshalJSON() ([]byte, error) {
 	return json.Marshal(time.Duration(*fd).String())
 }
@@ -193,10 +238,22 @@ func (fd *FFDuration) UnmarshalJSON(b []byte) error {
 		if err != nil {
 			return err
 		}
-		*fd = FFDuration(intVal) * FFDuration(time.Millisecond)
+		duration, err := durationFromUnits(intVal, time.Millisecond, string(b))
+		if err != nil {
+			return err
+		}
+		*fd = duration
 		return nil
 	}
-	duration, err := ParseDurationString(stringVal, time.Millisecond)
+	return fd.UnmarshalText([]byte(stringVal))
+}
+
+func (fd *FFDuration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(*fd).String()), nil
+}
+
+func (fd *FFDuration) UnmarshalText(b []byte) error {
+	duration, err := ParseDurationString(string(b), time.Millisecond)
 	if err != nil {
 		return err
 	}
@@ -220,12 +277,10 @@ func (fd *FFDuration) Scan(src interface{}) error {
 		return nil
 
 	case int:
-		*fd = FFDuration(time.Duration(src) * time.Millisecond)
-		return nil
+		return fd.scanMillis(int64(src))
 
 	case int64:
-		*fd = FFDuration(time.Duration(src) * time.Millisecond)
-		return nil
+		return fd.scanMillis(src)
 
 	default:
 		return i18n.NewError(context.Background(), i18n.MsgScanFailed, src, fd)
@@ -233,8 +288,20 @@ func (fd *FFDuration) Scan(src interface{}) error {
 
 }
 
+func (fd *FFDuration) scanMillis(millis int64) error {
+	duration, err := durationFromUnits(millis, time.Millisecond, strconv.FormatInt(millis, 10))
+	if err != nil {
+		return err
+	}
+	*fd = duration
+	return nil
+}
+
 // Value implements sql.Valuer
 func (fd *FFDuration) Value() (driver.Value, error) {
+	if fd == nil {
+		return nil, nil
+	}
 	return fd.String(), nil
 }
 
@@ -19,6 +19,7 @@ package fftypes
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -122,6 +123,40 @@ func TestSafeEqual(t *testing.T) {
 	assert.True(t, ft1.Equal(ft2))
 }
 
+func TestFFTimeArithmetic(t *testing.T) {
+	t1 := UnixTime(1621103852123456789)
+	t2 := t1.Add(1 * time.Second)
+	zero := ZeroTime()
+	var nilTime *FFTime
+
+	testCases := []struct {
+		name   string
+		a      *FFTime
+		b      *FFTime
+		sub    time.Duration
+		before bool
+		after  bool
+	}{
+		{name: "equal", a: t1, b: t1, sub: 0, before: false, after: false},
+		{name: "later", a: t2, b: t1, sub: 1 * time.Second, before: false, after: true},
+		{name: "earlier", a: t1, b: t2, sub: -1 * time.Second, before: true, after: false},
+		{name: "nil receiver", a: nilTime, b: &zero, sub: 0, before: false, after: false},
+		{name: "nil arg", a: t1, b: nilTime, sub: time.Time(*t1).Sub(time.Time{}), before: false, after: true},
+		{name: "both nil", a: nilTime, b: nilTime, sub: 0, before: false, after: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.sub, tc.a.Sub(tc.b))
+			assert.Equal(t, tc.before, tc.a.Before(tc.b))
+			assert.Equal(t, tc.after, tc.a.After(tc.b))
+		})
+	}
+
+	assert.Equal(t, int64(1621103853123456789), t2.UnixNano())
+	assert.Equal(t, time.Time{}.Add(time.Minute), time.Time(*nilTime.Add(time.Minute)))
+	assert.True(t, nilTime.Time().IsZero())
+}
+
 func TestFFTimeParseValue(t *testing.T) {
 
 	var ft FFTime
@@ -248,7 +283,7 @@ func TestFFDurationParseValue(t *testing.T) {
 	var pfd *FFDuration
 	v, err := pfd.Value()
 	assert.NoError(t, err)
-	assert.Equal(t, "", v)
+	assert.Nil(t, v)
 
 	pfd = &fd
 	*pfd = FFDuration(12345) * FFDuration(time.Millisecond)
@@ -262,4 +297,78 @@ func TestDurationParsing(t *testing.T) {
 	assert.Zero(t, ParseToDuration("!a duration"))
 	assert.Zero(t, ParseToDuration(""))
 	assert.Equal(t, "15ms", ParseToDuration("15ms").String())
+	assert.Equal(t, "720h0m0s", ParseToDuration("30d").String())
+	assert.Zero(t, ParseToDuration("d"))
+}
+
+func TestFFDurationUnmarshalFormats(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		json     string
+		expected FFDuration
+		err      string
+	}{
+		{name: "minutes string", json: `"5m"`, expected: FFDuration(5 * time.Minute)},
+		{name: "seconds string", json: `"300s"`, expected: FFDuration(300 * time.Second)},
+		{name: "millis number", json: `300000`, expected: FFDuration(300 * time.Second)},
+		{name: "millis string", json: `"300000"`, expected: FFDuration(300 * time.Second)},
+		{name: "days string", json: `"2d"`, expected: FFDuration(48 * time.Hour)},
+		{name: "zero number", json: `0`, expected: FFDuration(0)},
+		{name: "zero string", json: `"0s"`, expected: FFDuration(0)},
+		{name: "negative number", json: `-1500`, expected: FFDuration(-1500 * time.Millisecond)},
+		{name: "negative string", json: `"-5m"`, expected: FFDuration(-5 * time.Minute)},
+		{name: "overflow number", json: `9223372036854775807`, err: "FF10167"},
+		{name: "negative overflow number", json: `-9223372036854775807`, err: "FF10167"},
+		{name: "overflow string", json: `"9223372036854775807"`, err: "FF10167"},
+		{name: "overflow days", json: `"106752d"`, err: "FF10167"},
+		{name: "overflow duration string", json: `"2562048h"`, err: "FF10167"},
+		{name: "beyond int64", json: `92233720368547758070`, err: "cannot unmarshal"},
+		{name: "fractional number", json: `1.5`, err: "cannot unmarshal"},
+		{name: "bad string", json: `"five minutes"`, err: "FF10167"},
+		{name: "empty string", json: `""`, err: "FF10167"},
+		{name: "bool", json: `true`, err: "cannot unmarshal"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var fd FFDuration
+			err := json.Unmarshal([]byte(tc.json), &fd)
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, fd)
+			}
+		})
+	}
+
+}
+
+func TestFFDurationText(t *testing.T) {
+
+	fd := FFDuration(90 * time.Second)
+	b, err := fd.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "1m30s", string(b))
+
+	var fd2 FFDuration
+	err = fd2.UnmarshalText(b)
+	assert.NoError(t, err)
+	assert.Equal(t, fd, fd2)
+
+	err = fd2.UnmarshalText([]byte("300000"))
+	assert.NoError(t, err)
+	assert.Equal(t, FFDuration(5*time.Minute), fd2)
+
+	err = fd2.UnmarshalText([]byte("!duration"))
+	assert.Regexp(t, "FF10167", err)
+
+}
+
+func TestFFDurationScanOverflow(t *testing.T) {
+
+	var fd FFDuration
+	err := fd.Scan(int64(math.MaxInt64))
+	assert.Regexp(t, "FF10167", err)
+
 }
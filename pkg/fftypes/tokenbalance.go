@@ -0,0 +1,26 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftypes
+
+// TokenBalance is the running balance of an address within a token pool, accumulated from the
+// transfer events received from the tokens connector
+type TokenBalance struct {
+	Pool        *UUID   `json:"pool,omitempty"`
+	Address     string  `json:"address,omitempty"`
+	Amount      BigInt  `json:"amount"`
+	LastUpdated *FFTime `json:"lastUpdated,omitempty"`
+}
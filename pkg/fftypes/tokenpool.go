@@ -27,19 +27,36 @@ var (
 	TokenTypeNonFungible TokenType = ffEnum("tokentype", "nonfungible")
 )
 
+type TokenPoolStatus = FFEnum
+
+var (
+	// TokenPoolStatusActive is a pool that can be used normally
+	TokenPoolStatusActive TokenPoolStatus = ffEnum("tokenpoolstatus", "active")
+	// TokenPoolStatusFrozen is a pool that has been suspended, and cannot be used until it is unfrozen
+	TokenPoolStatusFrozen TokenPoolStatus = ffEnum("tokenpoolstatus", "frozen")
+)
+
 type TokenPool struct {
-	ID         *UUID          `json:"id,omitempty"`
-	Type       TokenType      `json:"type" ffenum:"tokentype"`
-	Namespace  string         `json:"namespace,omitempty"`
-	Name       string         `json:"name,omitempty"`
-	ProtocolID string         `json:"protocolId,omitempty"`
-	Author     string         `json:"author,omitempty"`
-	Symbol     string         `json:"symbol,omitempty"`
-	Connector  string         `json:"connector,omitempty"`
-	Message    *UUID          `json:"message,omitempty"`
-	Created    *FFTime        `json:"created,omitempty"`
-	Config     JSONObject     `json:"config,omitempty"`
-	TX         TransactionRef `json:"tx,omitempty"`
+	ID         *UUID           `json:"id,omitempty"`
+	Type       TokenType       `json:"type" ffenum:"tokentype"`
+	Namespace  string          `json:"namespace,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	ProtocolID string          `json:"protocolId,omitempty"`
+	Author     string          `json:"author,omitempty"`
+	Symbol     string          `json:"symbol,omitempty"`
+	Connector  string          `json:"connector,omitempty"`
+	Message    *UUID           `json:"message,omitempty"`
+	Created    *FFTime         `json:"created,omitempty"`
+	Config     JSONObject      `json:"config,omitempty"`
+	TX         TransactionRef  `json:"tx,omitempty"`
+	Status     TokenPoolStatus `json:"status,omitempty" ffenum:"tokenpoolstatus"`
+}
+
+// TokenPoolEventReplay is the input to replay the token pool events of a connector within a time range.
+// Either end of the range can be omitted
+type TokenPoolEventReplay struct {
+	From *FFTime `json:"from,omitempty"`
+	To   *FFTime `json:"to,omitempty"`
 }
 
 type TokenPoolAnnouncement struct {
@@ -30,12 +30,24 @@ var (
 	TransactionTypeBatchPin TransactionType = ffEnum("txtype", "batch_pin")
 	// TransactionTypeTokenPool represents a token pool creation
 	TransactionTypeTokenPool TransactionType = ffEnum("txtype", "token_pool")
+	// TransactionTypeTokenMint represents the minting of new tokens into a pool
+	TransactionTypeTokenMint TransactionType = ffEnum("txtype", "token_mint")
+	// TransactionTypeTokenBurn represents the burning of tokens from a pool
+	TransactionTypeTokenBurn TransactionType = ffEnum("txtype", "token_burn")
+	// TransactionTypeTokenTransfer represents a transfer of tokens between accounts
+	TransactionTypeTokenTransfer TransactionType = ffEnum("txtype", "token_transfer")
+	// TransactionTypeTokenApprove represents an approval for another account to transfer tokens
+	TransactionTypeTokenApprove TransactionType = ffEnum("txtype", "token_approve")
+	// TransactionTypeTokenFreeze represents freezing or unfreezing a token pool
+	TransactionTypeTokenFreeze TransactionType = ffEnum("txtype", "token_freeze")
 )
 
 // TransactionRef refers to a transaction, in other types
 type TransactionRef struct {
-	Type TransactionType `json:"type"`
-	ID   *UUID           `json:"id,omitempty"`
+	Type     TransactionType `json:"type"`
+	ID       *UUID           `json:"id,omitempty"`
+	GasLimit *HexBigInt      `json:"gasLimit,omitempty"`
+	GasPrice *HexBigInt      `json:"gasPrice,omitempty"`
 }
 
 // TransactionSubject is the hashable reason for the transaction was performed
@@ -63,4 +75,6 @@ type Transaction struct {
 	Status     OpStatus           `json:"status"`
 	ProtocolID string             `json:"protocolId,omitempty"`
 	Info       JSONObject         `json:"info,omitempty"`
+	GasLimit   *HexBigInt         `json:"gasLimit,omitempty"` // estimated before submission - not part of the hash
+	GasPrice   *HexBigInt         `json:"gasPrice,omitempty"`
 }
@@ -19,8 +19,9 @@ package fftypes
 type TransportPayloadType = FFEnum
 
 var (
-	TransportPayloadTypeMessage TransportPayloadType = ffEnum("transportpayload", "message")
-	TransportPayloadTypeBatch   TransportPayloadType = ffEnum("transportpayload", "batch")
+	TransportPayloadTypeMessage   TransportPayloadType = ffEnum("transportpayload", "message")
+	TransportPayloadTypeBatch     TransportPayloadType = ffEnum("transportpayload", "batch")
+	TransportPayloadTypeHeartbeat TransportPayloadType = ffEnum("transportpayload", "heartbeat")
 )
 
 // TransportWrapper wraps paylaods over data exchange transfers, for easy deserialization at target
@@ -46,6 +46,12 @@ func NewUUID() *UUID {
 	return &u
 }
 
+// NewNamedUUID returns a name based UUID, which is the same every time it is generated from the same namespace and name
+func NewNamedUUID(namespace *UUID, name string) *UUID {
+	u := UUID(uuid.NewSHA1(uuid.UUID(*namespace), []byte(name)))
+	return &u
+}
+
 func (u *UUID) String() string {
 	if u == nil {
 		return ""
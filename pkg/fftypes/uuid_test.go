@@ -73,3 +73,10 @@ func TestSafeEquals(t *testing.T) {
 	assert.False(t, u1.Equals(u2))
 
 }
+
+func TestNewNamedUUID(t *testing.T) {
+	ns := NewUUID()
+	assert.Equal(t, *NewNamedUUID(ns, "name1"), *NewNamedUUID(ns, "name1"))
+	assert.NotEqual(t, *NewNamedUUID(ns, "name1"), *NewNamedUUID(ns, "name2"))
+	assert.NotEqual(t, *NewNamedUUID(ns, "name1"), *NewNamedUUID(NewUUID(), "name1"))
+}
@@ -46,6 +46,8 @@ type Plugin interface {
 
 // Callbacks is the interface provided to the identity plugin, to allow it to request information from firefly, or pass events.
 type Callbacks interface {
+	// GetIdentityMetadata returns the metadata registered for an identity, or nil if there is none
+	GetIdentityMetadata(ctx context.Context, identifier string) (fftypes.JSONObject, error)
 }
 
 // Capabilities the supported featureset of the identity
@@ -43,10 +43,27 @@ type Plugin interface {
 
 	// RetrieveData reads data back from IPFS using the payload reference format returned from PublishData
 	RetrieveData(ctx context.Context, payloadRef string) (data io.ReadCloser, err error)
+
+	// DeleteData releases data previously published with PublishData, so that it can be reclaimed by the Public Storage
+	DeleteData(ctx context.Context, payloadRef string) error
+
+	// ListStoredItems returns the items uploaded to the Public Storage, optionally only those uploaded since the supplied time.
+	// Only called if the SupportsList capability is set
+	ListStoredItems(ctx context.Context, since *fftypes.FFTime) ([]StoredItem, error)
 }
 
 type Callbacks interface {
 }
 
 type Capabilities struct {
+	// SupportsList is true if the plugin can enumerate stored items with ListStoredItems
+	SupportsList bool
+}
+
+// StoredItem describes an item held in the Public Storage
+type StoredItem struct {
+	PayloadRef  string          `json:"payloadRef"`
+	Size        int64           `json:"size"`
+	ContentType string          `json:"contentType,omitempty"`
+	UploadedAt  *fftypes.FFTime `json:"uploaded,omitempty"`
 }
@@ -34,6 +34,10 @@ type Plugin interface {
 	// Returns the supported featureset of the interface
 	Init(ctx context.Context, name string, prefix config.Prefix, callbacks Callbacks) error
 
+	// SetRouter provides access to the other configured tokens plugins, so that events received by this plugin
+	// on behalf of another can be dispatched to it. Called before Start.
+	SetRouter(router Router)
+
 	// Blockchain interface must not deliver any events until start is called
 	Start() error
 
@@ -42,6 +46,30 @@ type Plugin interface {
 
 	// CreateTokenPool creates a new (fungible or non-fungible) pool of tokens
 	CreateTokenPool(ctx context.Context, operationID *fftypes.UUID, identity *fftypes.Identity, pool *fftypes.TokenPool) error
+
+	// MintTokens mints new tokens in a pool to a single recipient
+	MintTokens(ctx context.Context, operationID *fftypes.UUID, identity *fftypes.Identity, pool *fftypes.TokenPool, recipient TokenRecipient) error
+
+	// BatchMint mints new tokens in a pool to multiple recipients, in a single transaction.
+	// Only called if the plugin reports SupportsBatchMint in its capabilities.
+	BatchMint(ctx context.Context, operationID *fftypes.UUID, identity *fftypes.Identity, pool *fftypes.TokenPool, recipients []TokenRecipient) error
+
+	// FreezeTokenPool suspends all activity on a token pool, such as in the event of fraud or error
+	FreezeTokenPool(ctx context.Context, operationID *fftypes.UUID, pool *fftypes.TokenPool) error
+
+	// UnfreezeTokenPool resumes activity on a token pool that was previously frozen
+	UnfreezeTokenPool(ctx context.Context, operationID *fftypes.UUID, pool *fftypes.TokenPool) error
+
+	// ReplayTokenPoolEvents requests any token pool events in the time range from the connector, and
+	// delivers them again through the callbacks. Used to recover events missed while disconnected.
+	// A nil from or to leaves that end of the range open.
+	ReplayTokenPoolEvents(ctx context.Context, from, to *fftypes.FFTime) error
+}
+
+// TokenRecipient is an address, and the amount of tokens to mint to that address
+type TokenRecipient struct {
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"`
 }
 
 // Callbacks is the interface provided to the tokens plugin, to allow it to pass events back to firefly.
@@ -64,9 +92,30 @@ type Callbacks interface {
 	//
 	// Error should will only be returned in shutdown scenarios
 	TokenPoolCreated(plugin Plugin, tokenType fftypes.TokenType, tx *fftypes.UUID, protocolID, signingIdentity, protocolTxID string, additionalInfo fftypes.JSONObject) error
+
+	// TokensTransferred notifies on a transfer of tokens within a pool, between two addresses.
+	// The from address is empty for a mint, and the to address is empty for a burn. The signing
+	// key is the account that submitted the transaction.
+	//
+	// Error should will only be returned in shutdown scenarios
+	TokensTransferred(plugin Plugin, poolProtocolID, signingKey, from, to string, amount *fftypes.BigInt, protocolTxID string, additionalInfo fftypes.JSONObject) error
+
+	// TokenPoolStatusChanged notifies that a token pool has been frozen or unfrozen on the blockchain,
+	// which might have been requested by us, or by any other authorized party in the network.
+	//
+	// Error should will only be returned in shutdown scenarios
+	TokenPoolStatusChanged(plugin Plugin, poolProtocolID string, status fftypes.TokenPoolStatus, protocolTxID string, additionalInfo fftypes.JSONObject) error
+}
+
+// Router is provided to each tokens plugin by the asset manager, which owns the set of configured plugins
+type Router interface {
+	// Lookup returns the plugin configured with the given name, or nil if there is none
+	Lookup(name string) Plugin
 }
 
 // Capabilities the supported featureset of the tokens
 // interface implemented by the plugin, with the specified config
 type Capabilities struct {
+	// SupportsBatchMint indicates the plugin can mint to multiple recipients in one transaction
+	SupportsBatchMint bool
 }
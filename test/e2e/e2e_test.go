@@ -304,6 +304,43 @@ func TestE2EPrivate(t *testing.T) {
 	assert.Equal(t, data.Value, val2)
 }
 
+func TestE2EPrivateUnpinned(t *testing.T) {
+
+	ts := beforeE2ETest(t)
+	defer ts.done()
+
+	received1, _ := wsReader(t, ts.ws1)
+	received2, _ := wsReader(t, ts.ws2)
+
+	value := fftypes.Byteable(`"Hello unpinned"`)
+	data := fftypes.DataRefOrValue{
+		Value: value,
+	}
+
+	resp, err := UnpinnedPrivateMessage(t, ts.client1, &data, []string{
+		ts.org1.Name,
+		ts.org2.Name,
+	}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 202, resp.StatusCode())
+
+	// Both sides confirm the message without a blockchain transaction
+	<-received1
+	val1 := validateReceivedMessages(ts, ts.client1, fftypes.MessageTypePrivate, fftypes.TransactionTypeNone, 1, 0)
+	assert.Equal(t, data.Value, val1)
+
+	<-received2
+	val2 := validateReceivedMessages(ts, ts.client2, fftypes.MessageTypePrivate, fftypes.TransactionTypeNone, 1, 0)
+	assert.Equal(t, data.Value, val2)
+
+	for _, client := range []*resty.Client{ts.client1, ts.client2} {
+		messages := GetMessages(t, client, ts.startTime, fftypes.MessageTypePrivate, 200)
+		require.Len(t, messages, 1)
+		assert.False(t, messages[0].Pinned)
+		assert.NotNil(t, messages[0].Confirmed)
+	}
+}
+
 func TestE2EBroadcastBlob(t *testing.T) {
 
 	ts := beforeE2ETest(t)
@@ -237,6 +237,21 @@ func PrivateBlobMessageDatatypeTagged(t *testing.T, client *resty.Client, orgNam
 }
 
 func PrivateMessage(t *testing.T, client *resty.Client, data *fftypes.DataRefOrValue, orgNames []string, tag string, txType fftypes.TransactionType) (*resty.Response, error) {
+	return client.R().
+		SetBody(privateMessageInput(data, orgNames, tag, txType)).
+		Post(urlPrivateMessage)
+}
+
+func UnpinnedPrivateMessage(t *testing.T, client *resty.Client, data *fftypes.DataRefOrValue, orgNames []string, tag string) (*resty.Response, error) {
+	msg := privateMessageInput(data, orgNames, tag, "")
+	pinned := false
+	msg.Pinned = &pinned
+	return client.R().
+		SetBody(msg).
+		Post(urlPrivateMessage)
+}
+
+func privateMessageInput(data *fftypes.DataRefOrValue, orgNames []string, tag string, txType fftypes.TransactionType) *fftypes.MessageInOut {
 	members := make([]fftypes.MemberInput, len(orgNames))
 	for i, oName := range orgNames {
 		// We let FireFly resolve the friendly name of the org to the identity
@@ -257,9 +272,7 @@ func PrivateMessage(t *testing.T, client *resty.Client, data *fftypes.DataRefOrV
 			Name:    fmt.Sprintf("test_%d", time.Now().Unix()),
 		},
 	}
-	return client.R().
-		SetBody(msg).
-		Post(urlPrivateMessage)
+	return &msg
 }
 
 func RequestReply(t *testing.T, client *resty.Client, data *fftypes.DataRefOrValue, orgNames []string, tag string, txType fftypes.TransactionType) *fftypes.MessageInOut {